@@ -113,6 +113,7 @@ func main() {
 		Env:        env,
 		ErrLog:     fsterr.Log,
 		HTTPClient: httpClient,
+		Stderr:     os.Stderr,
 		Stdin:      in,
 		Stdout:     out,
 		Versioners: app.Versioners{
@@ -128,6 +129,9 @@ func main() {
 				Repo:       "viceroy",
 				Binary:     "viceroy",
 			}),
+			WasmOpt: github.NewBinaryen(github.BinaryenOpts{
+				HTTPClient: httpClient,
+			}),
 		},
 	}
 	err = app.Run(opts)
@@ -0,0 +1,24 @@
+package api
+
+import "github.com/fastly/go-fastly/v7/fastly"
+
+// BatchModify applies the operations of a batch API endpoint (e.g.
+// BatchModifyACLEntries) by calling modify with batches of at most the maximum
+// number of operations accepted by the API, in order.
+//
+// It returns the number of operations applied before any error. Each batch is
+// applied atomically, so a failure leaves the operations of earlier batches
+// applied and those of later batches untouched.
+func BatchModify[T any](ops []T, modify func(batch []T) error) (applied int, err error) {
+	for applied < len(ops) {
+		n := len(ops) - applied
+		if n > fastly.BatchModifyMaximumOperations {
+			n = fastly.BatchModifyMaximumOperations
+		}
+		if err := modify(ops[applied : applied+n]); err != nil {
+			return applied, err
+		}
+		applied += n
+	}
+	return applied, nil
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// curlOmittedHeaders are HTTP headers that curl sets itself, or that aren't
+// relevant to the API, and so aren't printed.
+var curlOmittedHeaders = map[string]bool{
+	"Accept-Encoding": true,
+	"Content-Length":  true,
+	"User-Agent":      true,
+}
+
+// CurlPrinter prints each API request as an equivalent curl command.
+type CurlPrinter struct {
+	mu  sync.Mutex
+	out io.Writer
+	// tokenVar is the environment variable the API token is referenced as, so
+	// the token itself is never printed.
+	tokenVar string
+}
+
+// NewCurlPrinter returns a CurlPrinter writing to out, which references the
+// API token as the environment variable tokenVar.
+func NewCurlPrinter(out io.Writer, tokenVar string) *CurlPrinter {
+	return &CurlPrinter{out: out, tokenVar: tokenVar}
+}
+
+// Wrap returns a http.RoundTripper that prints every request before it's
+// handled by the next transport. If next is nil then http.DefaultTransport is
+// used.
+func (p *CurlPrinter) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return curlTransport{printer: p, next: next}
+}
+
+type curlTransport struct {
+	printer *CurlPrinter
+	next    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t curlTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	t.printer.mu.Lock()
+	fmt.Fprintln(t.printer.out, t.printer.command(req, body))
+	t.printer.mu.Unlock()
+
+	return t.next.RoundTrip(req)
+}
+
+// command formats the request as a curl command, with the values of sensitive
+// headers, query parameters and body fields redacted in the same way as a
+// recorded session.
+func (p *CurlPrinter) command(req *http.Request, body []byte) string {
+	first := "curl "
+	if req.Method != http.MethodGet {
+		first += "-X " + req.Method + " "
+	}
+	args := []string{first + shellQuote(sanitizeURL(req.URL))}
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	form := mediaType == "multipart/form-data"
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		// curl sets the Content-Type of a form itself, with its own boundary.
+		if curlOmittedHeaders[canonical] || (form && canonical == "Content-Type") {
+			continue
+		}
+		for _, v := range req.Header[name] {
+			switch canonical {
+			case "Fastly-Key":
+				args = append(args, fmt.Sprintf(`-H "%s: $%s"`, canonical, p.tokenVar))
+			case "Authorization", "Cookie":
+				args = append(args, fmt.Sprintf("-H %s", shellQuote(canonical+": "+Redacted)))
+			default:
+				args = append(args, "-H "+shellQuote(canonical+": "+v))
+			}
+		}
+	}
+
+	switch {
+	case len(body) == 0:
+	case form:
+		fields, err := curlFormFields(body, params["boundary"])
+		if err != nil {
+			args = append(args, curlElidedBody(body))
+			break
+		}
+		args = append(args, fields...)
+	case !utf8.Valid(body) || bytes.IndexByte(body, 0) >= 0:
+		args = append(args, curlElidedBody(body))
+	default:
+		args = append(args, "--data-raw "+shellQuote(sanitizeBody(body)))
+	}
+	return strings.Join(args, " \\\n  ")
+}
+
+// curlFormFields returns the -F arguments of a multipart form, referencing the
+// files it uploads by name rather than printing their content.
+func curlFormFields(body []byte, boundary string) ([]string, error) {
+	var args []string
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return args, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := part.FormName()
+		if file := part.FileName(); file != "" {
+			args = append(args, "-F "+shellQuote(name+"=@"+file))
+			continue
+		}
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		if sensitiveFields[strings.ToLower(name)] {
+			value = []byte(Redacted)
+		}
+		args = append(args, "-F "+shellQuote(name+"="+string(value)))
+	}
+}
+
+// curlElidedBody returns a placeholder for a binary body, which can't be
+// printed.
+func curlElidedBody(body []byte) string {
+	return "--data-binary " + shellQuote(fmt.Sprintf("<%d bytes of binary data elided>", len(body)))
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
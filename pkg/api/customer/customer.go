@@ -0,0 +1,161 @@
+// Package customer provides abstractions for talking to the Fastly customer
+// and contact API endpoints, which aren't supported by the API client library.
+package customer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/useragent"
+)
+
+// The contact roles assigned to a user of the customer account.
+const (
+	RoleBilling   = "billing"
+	RoleLegal     = "legal"
+	RoleSecurity  = "security"
+	RoleTechnical = "technical"
+)
+
+// Roles are the contact roles assigned to a user of the customer account.
+var Roles = []string{RoleBilling, RoleLegal, RoleSecurity, RoleTechnical}
+
+// Customer models a Fastly customer account.
+type Customer struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	OwnerID            string `json:"owner_id"`
+	BillingContactID   string `json:"billing_contact_id"`
+	LegalContactID     string `json:"legal_contact_id"`
+	SecurityContactID  string `json:"security_contact_id"`
+	TechnicalContactID string `json:"technical_contact_id"`
+	BillingNetworkType string `json:"billing_network_type"`
+	PricingPlan        string `json:"pricing_plan"`
+	PhoneNumber        string `json:"phone_number"`
+	PostalAddress      string `json:"postal_address"`
+	CreatedAt          string `json:"created_at"`
+	UpdatedAt          string `json:"updated_at"`
+}
+
+// ContactID returns the ID of the user assigned the contact role.
+func (c Customer) ContactID(role string) string {
+	switch role {
+	case RoleBilling:
+		return c.BillingContactID
+	case RoleLegal:
+		return c.LegalContactID
+	case RoleSecurity:
+		return c.SecurityContactID
+	case RoleTechnical:
+		return c.TechnicalContactID
+	}
+	return ""
+}
+
+// Contact models a contact of a Fastly customer account.
+type Contact struct {
+	ID          string `json:"id"`
+	ContactType string `json:"contact_type"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	UserID      string `json:"user_id"`
+	CustomerID  string `json:"customer_id"`
+}
+
+// Client calls the customer API endpoints.
+type Client struct {
+	Endpoint   string
+	Token      string
+	HTTPClient api.HTTPClient
+}
+
+// GetCurrent returns the customer of the authenticated user.
+func (c Client) GetCurrent() (*Customer, error) {
+	var r Customer
+	if err := c.do(http.MethodGet, "/current_customer", nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Get returns the customer.
+func (c Client) Get(customerID string) (*Customer, error) {
+	var r Customer
+	if err := c.do(http.MethodGet, "/customer/"+url.PathEscape(customerID), nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// UpdateContacts assigns the contact roles to users, given a map of role to
+// user ID.
+func (c Client) UpdateContacts(customerID string, contacts map[string]string) (*Customer, error) {
+	form := url.Values{}
+	for role, userID := range contacts {
+		form.Set(role+"_contact_id", userID)
+	}
+	var r Customer
+	if err := c.do(http.MethodPut, "/customer/"+url.PathEscape(customerID), form, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListContacts returns the contacts of the customer.
+func (c Client) ListContacts(customerID string) ([]Contact, error) {
+	var r []Contact
+	if err := c.do(http.MethodGet, "/customer/"+url.PathEscape(customerID)+"/contacts", nil, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// do sends a request, with the form as its body if set, and decodes the JSON
+// response into v.
+func (c Client) do(method, path string, form url.Values, v any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.Endpoint, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("error constructing API request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", c.Token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", useragent.Name)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error executing API request: %w", err)
+	}
+	defer resp.Body.Close() // #nosec G307
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// NOTE: The API describes the error in the msg and detail fields.
+		var apiErr struct {
+			Msg    string `json:"msg"`
+			Detail string `json:"detail"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		msg := strings.TrimSpace(strings.Join([]string{apiErr.Msg, apiErr.Detail}, " "))
+		if msg != "" {
+			return fmt.Errorf("error from API: %s: %s", resp.Status, msg)
+		}
+		return fmt.Errorf("error from API: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding API response: %w", err)
+	}
+	return nil
+}
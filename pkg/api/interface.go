@@ -29,6 +29,7 @@ type Interface interface {
 	DeleteService(*fastly.DeleteServiceInput) error
 	SearchService(*fastly.SearchServiceInput) (*fastly.Service, error)
 
+	CreateVersion(*fastly.CreateVersionInput) (*fastly.Version, error)
 	CloneVersion(*fastly.CloneVersionInput) (*fastly.Version, error)
 	ListVersions(*fastly.ListVersionsInput) ([]*fastly.Version, error)
 	GetVersion(*fastly.GetVersionInput) (*fastly.Version, error)
@@ -245,6 +246,22 @@ type Interface interface {
 	UpdateDynamicSnippet(i *fastly.UpdateDynamicSnippetInput) (*fastly.DynamicSnippet, error)
 	DeleteSnippet(i *fastly.DeleteSnippetInput) error
 
+	CreateHeader(i *fastly.CreateHeaderInput) (*fastly.Header, error)
+	ListHeaders(i *fastly.ListHeadersInput) ([]*fastly.Header, error)
+
+	CreateCondition(i *fastly.CreateConditionInput) (*fastly.Condition, error)
+	ListConditions(i *fastly.ListConditionsInput) ([]*fastly.Condition, error)
+
+	ListCacheSettings(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error)
+	ListGzips(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error)
+	ListRequestSettings(i *fastly.ListRequestSettingsInput) ([]*fastly.RequestSetting, error)
+	ListResponseObjects(i *fastly.ListResponseObjectsInput) ([]*fastly.ResponseObject, error)
+
+	GetSettings(i *fastly.GetSettingsInput) (*fastly.Settings, error)
+	UpdateSettings(i *fastly.UpdateSettingsInput) (*fastly.Settings, error)
+
+	GetAPIEvents(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error)
+
 	Purge(i *fastly.PurgeInput) (*fastly.Purge, error)
 	PurgeKey(i *fastly.PurgeKeyInput) (*fastly.Purge, error)
 	PurgeKeys(i *fastly.PurgeKeysInput) (map[string]string, error)
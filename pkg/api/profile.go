@@ -0,0 +1,89 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Profile is a count of the API calls made by a command, and the bytes
+// transferred by them.
+type Profile struct {
+	Calls         int
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// Profiler counts the API calls made through any number of wrapped
+// transports (see the global --profile-cli flag).
+type Profiler struct {
+	mu      sync.Mutex
+	profile Profile
+}
+
+// NewProfiler returns a Profiler that has counted nothing.
+func NewProfiler() *Profiler {
+	return &Profiler{}
+}
+
+// Wrap returns a http.RoundTripper that counts every request handled by the
+// next transport. If next is nil then http.DefaultTransport is used.
+func (p *Profiler) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return profilingTransport{profiler: p, next: next}
+}
+
+// Profile returns the counts so far.
+func (p *Profiler) Profile() Profile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.profile
+}
+
+func (p *Profiler) add(calls int, sent, received int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profile.Calls += calls
+	p.profile.BytesSent += sent
+	p.profile.BytesReceived += received
+}
+
+type profilingTransport struct {
+	profiler *Profiler
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+//
+// NOTE: Only the bodies are counted, as the size of the headers on the wire
+// depends on the protocol. The response body is counted as it's read, so
+// that it needn't be buffered.
+func (t profilingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var sent int64
+	if req.ContentLength > 0 {
+		sent = req.ContentLength
+	}
+	t.profiler.add(1, sent, 0)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, profiler: t.profiler}
+	return resp, nil
+}
+
+// countingBody adds the bytes read from a response body to the profiler.
+type countingBody struct {
+	io.ReadCloser
+	profiler *Profiler
+}
+
+// Read implements io.Reader.
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.profiler.add(0, 0, int64(n))
+	return n, err
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly indicates a mutating API request was blocked by read-only mode.
+var ErrReadOnly = errors.New("read-only mode prevented a mutating API request")
+
+// ReadOnly returns a http.RoundTripper that only sends safe (non-mutating)
+// requests to the next transport, and otherwise returns ErrReadOnly. If next
+// is nil then http.DefaultTransport is used.
+func ReadOnly(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return readOnlyTransport{next: next}
+}
+
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w (%s %s)", ErrReadOnly, req.Method, req.URL.Path)
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces sensitive values within a recorded session.
+const Redacted = "REDACTED"
+
+// sensitiveHeaders are HTTP headers whose values are never recorded.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Fastly-Key", "Set-Cookie"}
+
+// sensitiveFields are request/response body fields whose values are never
+// recorded.
+var sensitiveFields = map[string]bool{
+	"access_token":      true,
+	"client_key":        true,
+	"hec_token":         true,
+	"password":          true,
+	"private_key":       true,
+	"secret":            true,
+	"secret_access_key": true,
+	"secret_key":        true,
+	"ssl_client_key":    true,
+	"tls_client_key":    true,
+	"token":             true,
+}
+
+// Session is a recording of the API interactions of a single command run.
+type Session struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded API request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized HTTP request.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a sanitized HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder captures API interactions from any number of wrapped transports.
+type Recorder struct {
+	mu      sync.Mutex
+	session Session
+}
+
+// NewRecorder returns a Recorder with an empty session.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Wrap returns a http.RoundTripper that records every request handled by the
+// next transport. If next is nil then http.DefaultTransport is used.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return recordingTransport{recorder: r, next: next}
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.recorder.mu.Lock()
+	t.recorder.session.Interactions = append(t.recorder.session.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     sanitizeURL(req.URL),
+			Headers: sanitizeHeaders(req.Header),
+			Body:    sanitizeBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    sanitizeHeaders(resp.Header),
+			Body:       sanitizeBody(respBody),
+		},
+	})
+	t.recorder.mu.Unlock()
+
+	return resp, nil
+}
+
+// Session returns the interactions recorded so far.
+func (r *Recorder) Session() Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Session{Interactions: append([]Interaction(nil), r.session.Interactions...)}
+}
+
+// Save writes the recorded session to the given path as JSON.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Session(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding API session: %w", err)
+	}
+	if err := os.WriteFile(filepath.Clean(path), data, 0o600); err != nil {
+		return fmt.Errorf("error writing API session: %w", err)
+	}
+	return nil
+}
+
+// Replayer is a http.RoundTripper that serves responses from a recorded
+// session. Requests are matched, in order, by method and URL.
+type Replayer struct {
+	mu      sync.Mutex
+	pending []Interaction
+}
+
+// NewReplayer returns a Replayer for the given session.
+func NewReplayer(s Session) *Replayer {
+	return &Replayer{pending: append([]Interaction(nil), s.Interactions...)}
+}
+
+// ReadSession reads a recorded session from disk.
+func ReadSession(path string) (Session, error) {
+	var s Session
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return s, fmt.Errorf("error reading API session: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("error decoding API session: %w", err)
+	}
+	return s, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u := sanitizeURL(req.URL)
+	for i, in := range r.pending {
+		if in.Request.Method != req.Method || in.Request.URL != u {
+			continue
+		}
+		r.pending = append(r.pending[:i], r.pending[i+1:]...)
+		return &http.Response{
+			StatusCode: in.Response.StatusCode,
+			Status:     fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			Header:     in.Response.Headers.Clone(),
+			Body:       io.NopCloser(strings.NewReader(in.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded API interaction for %s %s", req.Method, u)
+}
+
+// Remaining returns the number of recorded interactions not yet replayed.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+func sanitizeURL(u *url.URL) string {
+	c := *u
+	q := c.Query()
+	for k := range q {
+		if sensitiveFields[strings.ToLower(k)] {
+			q.Set(k, Redacted)
+		}
+	}
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+func sanitizeHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	c := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, Redacted)
+		}
+	}
+	return c
+}
+
+// sanitizeBody redacts sensitive fields from either a JSON or form encoded
+// body. Bodies in any other format are recorded as-is.
+func sanitizeBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		data, err := json.Marshal(redactJSON(v))
+		if err == nil {
+			return string(data)
+		}
+	}
+
+	if q, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		for k := range q {
+			if sensitiveFields[strings.ToLower(k)] {
+				q.Set(k, Redacted)
+			}
+		}
+		return q.Encode()
+	}
+
+	return string(body)
+}
+
+func redactJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if sensitiveFields[strings.ToLower(k)] {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = redactJSON(val)
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactJSON(val)
+		}
+	}
+	return v
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/commands/account"
 	"github.com/fastly/cli/pkg/commands/acl"
 	"github.com/fastly/cli/pkg/commands/aclentry"
 	"github.com/fastly/cli/pkg/commands/authtoken"
@@ -10,9 +11,13 @@ import (
 	"github.com/fastly/cli/pkg/commands/config"
 	"github.com/fastly/cli/pkg/commands/configstore"
 	"github.com/fastly/cli/pkg/commands/configstoreentry"
+	"github.com/fastly/cli/pkg/commands/contact"
 	"github.com/fastly/cli/pkg/commands/dictionary"
 	"github.com/fastly/cli/pkg/commands/dictionaryentry"
+	"github.com/fastly/cli/pkg/commands/doctor"
 	"github.com/fastly/cli/pkg/commands/domain"
+	"github.com/fastly/cli/pkg/commands/env"
+	"github.com/fastly/cli/pkg/commands/gc"
 	"github.com/fastly/cli/pkg/commands/healthcheck"
 	"github.com/fastly/cli/pkg/commands/ip"
 	"github.com/fastly/cli/pkg/commands/logging"
@@ -46,14 +51,17 @@ import (
 	"github.com/fastly/cli/pkg/commands/objectstoreentry"
 	"github.com/fastly/cli/pkg/commands/pop"
 	"github.com/fastly/cli/pkg/commands/profile"
+	"github.com/fastly/cli/pkg/commands/propagate"
 	"github.com/fastly/cli/pkg/commands/purge"
 	"github.com/fastly/cli/pkg/commands/resourcelink"
 	"github.com/fastly/cli/pkg/commands/secretstore"
 	"github.com/fastly/cli/pkg/commands/secretstoreentry"
 	"github.com/fastly/cli/pkg/commands/service"
+	serviceNotes "github.com/fastly/cli/pkg/commands/service/notes"
 	"github.com/fastly/cli/pkg/commands/serviceauth"
 	"github.com/fastly/cli/pkg/commands/serviceversion"
 	"github.com/fastly/cli/pkg/commands/shellcomplete"
+	"github.com/fastly/cli/pkg/commands/smoketest"
 	"github.com/fastly/cli/pkg/commands/stats"
 	"github.com/fastly/cli/pkg/global"
 
@@ -83,19 +91,26 @@ func defineCommands(
 	opts RunOpts,
 ) []cmd.Command {
 	shellcompleteCmdRoot := shellcomplete.NewRootCommand(app, g)
+	accountCmdRoot := account.NewRootCommand(app, g)
+	accountDescribe := account.NewDescribeCommand(accountCmdRoot.CmdClause, g)
 	aclCmdRoot := acl.NewRootCommand(app, g)
 	aclCreate := acl.NewCreateCommand(aclCmdRoot.CmdClause, g, m)
 	aclDelete := acl.NewDeleteCommand(aclCmdRoot.CmdClause, g, m)
 	aclDescribe := acl.NewDescribeCommand(aclCmdRoot.CmdClause, g, m)
 	aclList := acl.NewListCommand(aclCmdRoot.CmdClause, g, m)
+	aclRefresh := acl.NewRefreshCommand(aclCmdRoot.CmdClause, g, m)
 	aclUpdate := acl.NewUpdateCommand(aclCmdRoot.CmdClause, g, m)
 	aclEntryCmdRoot := aclentry.NewRootCommand(app, g)
+	aclEntryCount := aclentry.NewCountCommand(aclEntryCmdRoot.CmdClause, g, m)
 	aclEntryCreate := aclentry.NewCreateCommand(aclEntryCmdRoot.CmdClause, g, m)
 	aclEntryDelete := aclentry.NewDeleteCommand(aclEntryCmdRoot.CmdClause, g, m)
 	aclEntryDescribe := aclentry.NewDescribeCommand(aclEntryCmdRoot.CmdClause, g, m)
+	aclEntryImport := aclentry.NewImportCommand(aclEntryCmdRoot.CmdClause, g, m)
 	aclEntryList := aclentry.NewListCommand(aclEntryCmdRoot.CmdClause, g, m)
+	aclEntryLookup := aclentry.NewLookupCommand(aclEntryCmdRoot.CmdClause, g, m)
 	aclEntryUpdate := aclentry.NewUpdateCommand(aclEntryCmdRoot.CmdClause, g, m)
 	authtokenCmdRoot := authtoken.NewRootCommand(app, g)
+	authtokenAdvise := authtoken.NewAdviseCommand(authtokenCmdRoot.CmdClause, g, app, m)
 	authtokenCreate := authtoken.NewCreateCommand(authtokenCmdRoot.CmdClause, g, m)
 	authtokenDelete := authtoken.NewDeleteCommand(authtokenCmdRoot.CmdClause, g, m)
 	authtokenDescribe := authtoken.NewDescribeCommand(authtokenCmdRoot.CmdClause, g, m)
@@ -105,15 +120,26 @@ func defineCommands(
 	backendDelete := backend.NewDeleteCommand(backendCmdRoot.CmdClause, g, m)
 	backendDescribe := backend.NewDescribeCommand(backendCmdRoot.CmdClause, g, m)
 	backendList := backend.NewListCommand(backendCmdRoot.CmdClause, g, m)
+	backendStatus := backend.NewStatusCommand(backendCmdRoot.CmdClause, g, m)
 	backendUpdate := backend.NewUpdateCommand(backendCmdRoot.CmdClause, g, m)
 	computeCmdRoot := compute.NewRootCommand(app, g)
-	computeBuild := compute.NewBuildCommand(computeCmdRoot.CmdClause, g, m)
-	computeDeploy := compute.NewDeployCommand(computeCmdRoot.CmdClause, g, m)
+	computeBuild := compute.NewBuildCommand(computeCmdRoot.CmdClause, g, opts.Versioners.Viceroy, opts.Versioners.WasmOpt, m)
+	computeDeploy := compute.NewDeployCommand(computeCmdRoot.CmdClause, g, computeBuild, m)
+	computeDeployStatus := compute.NewDeployStatusCommand(computeCmdRoot.CmdClause, g)
 	computeHashsum := compute.NewHashsumCommand(computeCmdRoot.CmdClause, g, computeBuild, m)
 	computeInit := compute.NewInitCommand(computeCmdRoot.CmdClause, g, m)
 	computePack := compute.NewPackCommand(computeCmdRoot.CmdClause, g, m)
+	computePackageCmdRoot := compute.NewPackageRootCommand(computeCmdRoot.CmdClause, g)
+	computePackageDescribe := compute.NewPackageDescribeCommand(computePackageCmdRoot.CmdClause, g, m)
+	computePromote := compute.NewPromoteCommand(computeCmdRoot.CmdClause, g)
 	computePublish := compute.NewPublishCommand(computeCmdRoot.CmdClause, g, computeBuild, computeDeploy, m)
+	computePullConfig := compute.NewPullConfigCommand(computeCmdRoot.CmdClause, g, m)
+	computeRollback := compute.NewRollbackCommand(computeCmdRoot.CmdClause, g, m)
+	computeSBOM := compute.NewSBOMCommand(computeCmdRoot.CmdClause, g, m)
 	computeServe := compute.NewServeCommand(computeCmdRoot.CmdClause, g, computeBuild, opts.Versioners.Viceroy, m)
+	computeStartersCmdRoot := compute.NewStartersRootCommand(computeCmdRoot.CmdClause, g)
+	computeStartersList := compute.NewStartersListCommand(computeStartersCmdRoot.CmdClause, g)
+	computeTest := compute.NewTestCommand(computeCmdRoot.CmdClause, g, computeBuild, opts.Versioners.Viceroy, m)
 	computeUpdate := compute.NewUpdateCommand(computeCmdRoot.CmdClause, g, m)
 	computeValidate := compute.NewValidateCommand(computeCmdRoot.CmdClause, g, m)
 	configCmdRoot := config.NewRootCommand(app, g)
@@ -128,8 +154,12 @@ func defineCommands(
 	configstoreentryCreate := configstoreentry.NewCreateCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryDelete := configstoreentry.NewDeleteCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryDescribe := configstoreentry.NewDescribeCommand(configstoreentryCmdRoot.CmdClause, g, m)
+	configstoreentryImport := configstoreentry.NewImportCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryList := configstoreentry.NewListCommand(configstoreentryCmdRoot.CmdClause, g, m)
 	configstoreentryUpdate := configstoreentry.NewUpdateCommand(configstoreentryCmdRoot.CmdClause, g, m)
+	contactCmdRoot := contact.NewRootCommand(app, g)
+	contactList := contact.NewListCommand(contactCmdRoot.CmdClause, g)
+	contactUpdate := contact.NewUpdateCommand(contactCmdRoot.CmdClause, g)
 	dictionaryCmdRoot := dictionary.NewRootCommand(app, g)
 	dictionaryCreate := dictionary.NewCreateCommand(dictionaryCmdRoot.CmdClause, g, m)
 	dictionaryDelete := dictionary.NewDeleteCommand(dictionaryCmdRoot.CmdClause, g, m)
@@ -138,10 +168,14 @@ func defineCommands(
 	dictionaryEntryCreate := dictionaryentry.NewCreateCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryEntryDelete := dictionaryentry.NewDeleteCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryEntryDescribe := dictionaryentry.NewDescribeCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
+	dictionaryEntryExport := dictionaryentry.NewExportCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
+	dictionaryEntryImport := dictionaryentry.NewImportCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryEntryList := dictionaryentry.NewListCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
+	dictionaryEntrySync := dictionaryentry.NewSyncCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryEntryUpdate := dictionaryentry.NewUpdateCommand(dictionaryEntryCmdRoot.CmdClause, g, m)
 	dictionaryList := dictionary.NewListCommand(dictionaryCmdRoot.CmdClause, g, m)
 	dictionaryUpdate := dictionary.NewUpdateCommand(dictionaryCmdRoot.CmdClause, g, m)
+	doctorCmdRoot := doctor.NewRootCommand(app, g, opts.Versioners.Viceroy, m)
 	domainCmdRoot := domain.NewRootCommand(app, g)
 	domainCreate := domain.NewCreateCommand(domainCmdRoot.CmdClause, g, m)
 	domainDelete := domain.NewDeleteCommand(domainCmdRoot.CmdClause, g, m)
@@ -149,6 +183,13 @@ func defineCommands(
 	domainList := domain.NewListCommand(domainCmdRoot.CmdClause, g, m)
 	domainUpdate := domain.NewUpdateCommand(domainCmdRoot.CmdClause, g, m)
 	domainValidate := domain.NewValidateCommand(domainCmdRoot.CmdClause, g, m)
+	domainVerify := domain.NewVerifyCommand(domainCmdRoot.CmdClause, g, m)
+	envCmdRoot := env.NewRootCommand(app, g)
+	envGet := env.NewGetCommand(envCmdRoot.CmdClause, g, m)
+	envList := env.NewListCommand(envCmdRoot.CmdClause, g, m)
+	envSet := env.NewSetCommand(envCmdRoot.CmdClause, g, m)
+	envUnset := env.NewUnsetCommand(envCmdRoot.CmdClause, g, m)
+	gcCmdRoot := gc.NewRootCommand(app, g)
 	healthcheckCmdRoot := healthcheck.NewRootCommand(app, g)
 	healthcheckCreate := healthcheck.NewCreateCommand(healthcheckCmdRoot.CmdClause, g, m)
 	healthcheckDelete := healthcheck.NewDeleteCommand(healthcheckCmdRoot.CmdClause, g, m)
@@ -312,12 +353,15 @@ func defineCommands(
 	objectstoreCreate := objectstore.NewCreateCommand(objectstoreCmdRoot.CmdClause, g, m)
 	objectstoreDelete := objectstore.NewDeleteCommand(objectstoreCmdRoot.CmdClause, g, m)
 	objectstoreDescribe := objectstore.NewDescribeCommand(objectstoreCmdRoot.CmdClause, g, m)
+	objectstoreDiff := objectstore.NewDiffCommand(objectstoreCmdRoot.CmdClause, g)
 	objectstoreList := objectstore.NewListCommand(objectstoreCmdRoot.CmdClause, g, m)
+	objectstoreUpdate := objectstore.NewUpdateCommand(objectstoreCmdRoot.CmdClause, g, m)
 	objectstoreentryCmdRoot := objectstoreentry.NewRootCommand(app, g)
 	objectstoreentryCreate := objectstoreentry.NewCreateCommand(objectstoreentryCmdRoot.CmdClause, g, m)
 	objectstoreentryDelete := objectstoreentry.NewDeleteCommand(objectstoreentryCmdRoot.CmdClause, g, m)
 	objectstoreentryDescribe := objectstoreentry.NewDescribeCommand(objectstoreentryCmdRoot.CmdClause, g, m)
 	objectstoreentryList := objectstoreentry.NewListCommand(objectstoreentryCmdRoot.CmdClause, g, m)
+	objectstoreentryUpload := objectstoreentry.NewUploadCommand(objectstoreentryCmdRoot.CmdClause, g, m)
 	popCmdRoot := pop.NewRootCommand(app, g)
 	profileCmdRoot := profile.NewRootCommand(app, g)
 	profileCreate := profile.NewCreateCommand(profileCmdRoot.CmdClause, profile.APIClientFactory(opts.APIClient), g)
@@ -326,6 +370,7 @@ func defineCommands(
 	profileSwitch := profile.NewSwitchCommand(profileCmdRoot.CmdClause, g)
 	profileToken := profile.NewTokenCommand(profileCmdRoot.CmdClause, g)
 	profileUpdate := profile.NewUpdateCommand(profileCmdRoot.CmdClause, profile.APIClientFactory(opts.APIClient), g)
+	propagateCmdRoot := propagate.NewRootCommand(app, g, m)
 	purgeCmdRoot := purge.NewRootCommand(app, g, m)
 	resourcelinkCmdRoot := resourcelink.NewRootCommand(app, g)
 	resourcelinkCreate := resourcelink.NewCreateCommand(resourcelinkCmdRoot.CmdClause, g, m)
@@ -347,7 +392,11 @@ func defineCommands(
 	serviceCreate := service.NewCreateCommand(serviceCmdRoot.CmdClause, g)
 	serviceDelete := service.NewDeleteCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceDescribe := service.NewDescribeCommand(serviceCmdRoot.CmdClause, g, m)
+	serviceDiff := service.NewDiffCommand(serviceCmdRoot.CmdClause, g)
 	serviceList := service.NewListCommand(serviceCmdRoot.CmdClause, g)
+	serviceNotesCmdRoot := serviceNotes.NewRootCommand(serviceCmdRoot.CmdClause, g)
+	serviceNotesSet := serviceNotes.NewSetCommand(serviceNotesCmdRoot.CmdClause, g, m)
+	serviceNotesShow := serviceNotes.NewShowCommand(serviceNotesCmdRoot.CmdClause, g, m)
 	serviceSearch := service.NewSearchCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceUpdate := service.NewUpdateCommand(serviceCmdRoot.CmdClause, g, m)
 	serviceauthCmdRoot := serviceauth.NewRootCommand(app, g)
@@ -355,16 +404,23 @@ func defineCommands(
 	serviceauthDelete := serviceauth.NewDeleteCommand(serviceauthCmdRoot.CmdClause, g, m)
 	serviceauthDescribe := serviceauth.NewDescribeCommand(serviceauthCmdRoot.CmdClause, g, m)
 	serviceauthList := serviceauth.NewListCommand(serviceauthCmdRoot.CmdClause, g)
+	serviceauthSync := serviceauth.NewSyncCommand(serviceauthCmdRoot.CmdClause, g)
 	serviceauthUpdate := serviceauth.NewUpdateCommand(serviceauthCmdRoot.CmdClause, g, m)
 	serviceVersionCmdRoot := serviceversion.NewRootCommand(app, g)
 	serviceVersionActivate := serviceversion.NewActivateCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionClone := serviceversion.NewCloneCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionDeactivate := serviceversion.NewDeactivateCommand(serviceVersionCmdRoot.CmdClause, g, m)
+	serviceVersionDiff := serviceversion.NewDiffCommand(serviceVersionCmdRoot.CmdClause, g, m)
+	serviceVersionHistory := serviceversion.NewHistoryCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionList := serviceversion.NewListCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionLock := serviceversion.NewLockCommand(serviceVersionCmdRoot.CmdClause, g, m)
+	serviceVersionStage := serviceversion.NewStageCommand(serviceVersionCmdRoot.CmdClause, g, m)
 	serviceVersionUpdate := serviceversion.NewUpdateCommand(serviceVersionCmdRoot.CmdClause, g, m)
+	smoketestCmdRoot := smoketest.NewRootCommand(app, g, m)
 	statsCmdRoot := stats.NewRootCommand(app, g)
+	statsExport := stats.NewExportCommand(statsCmdRoot.CmdClause, g, m)
 	statsHistorical := stats.NewHistoricalCommand(statsCmdRoot.CmdClause, g, m)
+	statsPush := stats.NewPushCommand(statsCmdRoot.CmdClause, g, m)
 	statsRealtime := stats.NewRealtimeCommand(statsCmdRoot.CmdClause, g, m)
 	statsRegions := stats.NewRegionsCommand(statsCmdRoot.CmdClause, g)
 	tlsConfigCmdRoot := tlsConfig.NewRootCommand(app, g)
@@ -387,6 +443,7 @@ func defineCommands(
 	tlsCustomDomainCmdRoot := tlsCustomDomain.NewRootCommand(tlsCustomCmdRoot.CmdClause, g)
 	tlsCustomDomainList := tlsCustomDomain.NewListCommand(tlsCustomDomainCmdRoot.CmdClause, g, m)
 	tlsCustomPrivateKeyCmdRoot := tlsCustomPrivateKey.NewRootCommand(tlsCustomCmdRoot.CmdClause, g)
+	tlsCustomPrivateKeyCheck := tlsCustomPrivateKey.NewCheckCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, g)
 	tlsCustomPrivateKeyCreate := tlsCustomPrivateKey.NewCreateCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, g, m)
 	tlsCustomPrivateKeyDelete := tlsCustomPrivateKey.NewDeleteCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, g, m)
 	tlsCustomPrivateKeyDescribe := tlsCustomPrivateKey.NewDescribeCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, g, m)
@@ -423,24 +480,31 @@ func defineCommands(
 	vclSnippetDescribe := snippet.NewDescribeCommand(vclSnippetCmdRoot.CmdClause, g, m)
 	vclSnippetList := snippet.NewListCommand(vclSnippetCmdRoot.CmdClause, g, m)
 	vclSnippetUpdate := snippet.NewUpdateCommand(vclSnippetCmdRoot.CmdClause, g, m)
-	versionCmdRoot := version.NewRootCommand(app, opts.Versioners.Viceroy)
+	versionCmdRoot := version.NewRootCommand(app, opts.Versioners.CLI, opts.Versioners.Viceroy)
 	whoamiCmdRoot := whoami.NewRootCommand(app, g)
 
 	return []cmd.Command{
 		shellcompleteCmdRoot,
+		accountCmdRoot,
+		accountDescribe,
 		aclCmdRoot,
 		aclCreate,
 		aclDelete,
 		aclDescribe,
 		aclList,
+		aclRefresh,
 		aclUpdate,
 		aclEntryCmdRoot,
+		aclEntryCount,
 		aclEntryCreate,
 		aclEntryDelete,
 		aclEntryDescribe,
+		aclEntryImport,
 		aclEntryList,
+		aclEntryLookup,
 		aclEntryUpdate,
 		authtokenCmdRoot,
+		authtokenAdvise,
 		authtokenCreate,
 		authtokenDelete,
 		authtokenDescribe,
@@ -450,15 +514,26 @@ func defineCommands(
 		backendDelete,
 		backendDescribe,
 		backendList,
+		backendStatus,
 		backendUpdate,
 		computeBuild,
 		computeCmdRoot,
 		computeDeploy,
+		computeDeployStatus,
 		computeHashsum,
 		computeInit,
 		computePack,
+		computePackageCmdRoot,
+		computePackageDescribe,
+		computePromote,
 		computePublish,
+		computePullConfig,
+		computeRollback,
+		computeSBOM,
 		computeServe,
+		computeStartersCmdRoot,
+		computeStartersList,
+		computeTest,
 		computeUpdate,
 		computeValidate,
 		configCmdRoot,
@@ -473,8 +548,12 @@ func defineCommands(
 		configstoreentryCreate,
 		configstoreentryDelete,
 		configstoreentryDescribe,
+		configstoreentryImport,
 		configstoreentryList,
 		configstoreentryUpdate,
+		contactCmdRoot,
+		contactList,
+		contactUpdate,
 		dictionaryCmdRoot,
 		dictionaryCreate,
 		dictionaryDelete,
@@ -483,10 +562,14 @@ func defineCommands(
 		dictionaryEntryCreate,
 		dictionaryEntryDelete,
 		dictionaryEntryDescribe,
+		dictionaryEntryExport,
+		dictionaryEntryImport,
 		dictionaryEntryList,
+		dictionaryEntrySync,
 		dictionaryEntryUpdate,
 		dictionaryList,
 		dictionaryUpdate,
+		doctorCmdRoot,
 		domainCmdRoot,
 		domainCreate,
 		domainDelete,
@@ -494,6 +577,13 @@ func defineCommands(
 		domainList,
 		domainUpdate,
 		domainValidate,
+		domainVerify,
+		envCmdRoot,
+		envGet,
+		envList,
+		envSet,
+		envUnset,
+		gcCmdRoot,
 		healthcheckCmdRoot,
 		healthcheckCreate,
 		healthcheckDelete,
@@ -656,11 +746,14 @@ func defineCommands(
 		objectstoreCreate,
 		objectstoreDelete,
 		objectstoreDescribe,
+		objectstoreDiff,
 		objectstoreList,
+		objectstoreUpdate,
 		objectstoreentryCreate,
 		objectstoreentryDelete,
 		objectstoreentryDescribe,
 		objectstoreentryList,
+		objectstoreentryUpload,
 		popCmdRoot,
 		profileCmdRoot,
 		profileCreate,
@@ -669,6 +762,7 @@ func defineCommands(
 		profileSwitch,
 		profileToken,
 		profileUpdate,
+		propagateCmdRoot,
 		purgeCmdRoot,
 		resourcelinkCmdRoot,
 		resourcelinkCreate,
@@ -688,7 +782,11 @@ func defineCommands(
 		serviceCreate,
 		serviceDelete,
 		serviceDescribe,
+		serviceDiff,
 		serviceList,
+		serviceNotesCmdRoot,
+		serviceNotesSet,
+		serviceNotesShow,
 		serviceSearch,
 		serviceUpdate,
 		serviceauthCmdRoot,
@@ -696,16 +794,23 @@ func defineCommands(
 		serviceauthDelete,
 		serviceauthDescribe,
 		serviceauthList,
+		serviceauthSync,
 		serviceauthUpdate,
 		serviceVersionActivate,
 		serviceVersionClone,
 		serviceVersionCmdRoot,
 		serviceVersionDeactivate,
+		serviceVersionDiff,
+		serviceVersionHistory,
 		serviceVersionList,
 		serviceVersionLock,
+		serviceVersionStage,
 		serviceVersionUpdate,
+		smoketestCmdRoot,
 		statsCmdRoot,
+		statsExport,
 		statsHistorical,
+		statsPush,
 		statsRealtime,
 		statsRegions,
 		tlsConfigCmdRoot,
@@ -728,6 +833,7 @@ func defineCommands(
 		tlsCustomDomainCmdRoot,
 		tlsCustomDomainList,
 		tlsCustomPrivateKeyCmdRoot,
+		tlsCustomPrivateKeyCheck,
 		tlsCustomPrivateKeyCreate,
 		tlsCustomPrivateKeyDelete,
 		tlsCustomPrivateKeyDescribe,
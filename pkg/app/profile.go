@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// cliProfile collects the timings and API usage of a command for the global
+// --profile-cli flag.
+type cliProfile struct {
+	start time.Time
+	api   *api.Profiler
+
+	mu sync.Mutex
+	// phases are the names of the phases in the order they first finished.
+	phases    []string
+	durations map[string]time.Duration
+}
+
+// newCLIProfile returns a profile of a command started at start, which
+// records the duration of each phase emitted by p.
+func newCLIProfile(start time.Time, p *text.Progress) *cliProfile {
+	c := &cliProfile{
+		start:     start,
+		durations: make(map[string]time.Duration),
+	}
+	p.Observe(c.observe)
+	return c
+}
+
+// observe records the end of a phase.
+//
+// NOTE: A phase can run more than once (e.g. building each package of a
+// workspace), in which case its durations are summed.
+func (c *cliProfile) observe(e text.ProgressEvent) {
+	if e.Step != "" || e.Status == text.ProgressStarted {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.durations[e.Phase]; !ok {
+		c.phases = append(c.phases, e.Phase)
+	}
+	c.durations[e.Phase] += time.Duration(e.DurationMS) * time.Millisecond
+}
+
+// Print writes the profile to w.
+func (c *cliProfile) Print(w io.Writer) {
+	var p api.Profile
+	if c.api != nil {
+		p = c.api.Profile()
+	}
+
+	text.Break(w)
+	fmt.Fprintln(w, "Profile:")
+	t := text.NewTable(w)
+	t.AddLine("  Wall time", time.Since(c.start).Round(time.Millisecond))
+	t.AddLine("  API calls", p.Calls)
+	t.AddLine("  Bytes sent", p.BytesSent)
+	t.AddLine("  Bytes received", p.BytesReceived)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, phase := range c.phases {
+		t.AddLine(fmt.Sprintf("  Phase '%s'", phase), c.durations[phase])
+	}
+	t.Print()
+}
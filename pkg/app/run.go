@@ -1,22 +1,29 @@
 package app
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/commands/update"
 	"github.com/fastly/cli/pkg/commands/version"
 	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
 	"github.com/fastly/cli/pkg/github"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/profile"
+	"github.com/fastly/cli/pkg/query"
 	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/go-fastly/v7/fastly"
@@ -41,11 +48,22 @@ func FastlyAPIClient(token, endpoint string) (api.Interface, error) {
 // io.Writer. All error-related information should be encoded into an error type
 // and returned to the caller. This includes usage text.
 func Run(opts RunOpts) error {
+	start := time.Now()
+
 	var md manifest.Data
 	md.File.SetErrLog(opts.ErrLog)
 	md.File.SetOutput(opts.Stdout)
 	_ = md.File.Read(manifest.Filename)
 
+	// NOTE: A fastly.<env>.toml manifest (rather than an [env.<env>] section)
+	// is still supported by `compute serve` and `compute test`.
+	var envErr error
+	if e := cmd.EnvFlag(opts.Args); e != "" && md.File.Exists() {
+		if md.File.HasEnv(e) || !filesystem.FileExists(fmt.Sprintf("fastly.%s.toml", e)) {
+			envErr = md.File.ApplyEnv(e)
+		}
+	}
+
 	// The g will hold generally-applicable configuration parameters
 	// from a variety of sources, and is provided to each concrete command.
 	g := global.Data{
@@ -86,10 +104,18 @@ func Run(opts RunOpts) error {
 	tokenHelp := fmt.Sprintf("Fastly API token (or via %s)", env.Token)
 	app.Flag("accept-defaults", "Accept default options for all interactive prompts apart from Yes/No confirmations").Short('d').BoolVar(&g.Flags.AcceptDefaults)
 	app.Flag("auto-yes", "Answer yes automatically to all Yes/No confirmations. This may suppress security warnings").Short('y').BoolVar(&g.Flags.AutoYes)
+	app.Flag("curl", "Print the equivalent curl command of each API call on stderr before it's made, with the token referenced as $"+env.Token+". Combine with --read-only to print mutating calls without making them").BoolVar(&g.Flags.Curl)
 	app.Flag("endpoint", "Fastly API endpoint").Hidden().StringVar(&g.Flags.Endpoint)
+	app.Flag("env", fmt.Sprintf("Select an environment of the %s manifest, whose [env.<name>] section overrides the service_id, [setup] and [local_server] values", manifest.Filename)).PlaceHolder("NAME").StringVar(&g.Flags.Env)
 	app.Flag("non-interactive", "Do not prompt for user input - suitable for CI processes. Equivalent to --accept-defaults and --auto-yes").Short('i').BoolVar(&g.Flags.NonInteractive)
+	app.Flag("output-ci", "Emit errors as CI annotations and fold long logs into collapsible sections (auto, github, gitlab)").PlaceHolder("CI").EnumVar(&g.Flags.OutputCI, "auto", string(text.CIGitHub), string(text.CIGitLab))
 	app.Flag("profile", "Switch account profile for single command execution (see also: 'fastly profile switch')").Short('o').StringVar(&g.Flags.Profile)
+	app.Flag("profile-cli", "Print the number of API calls made, the bytes transferred and the wall time of each phase on stderr once the command finishes, to find slow command paths").BoolVar(&g.Flags.ProfileCLI)
+	app.Flag("progress-json", "Emit setup, build and deploy progress as NDJSON events on stderr, for wrapping tools to render").BoolVar(&g.Flags.ProgressJSON)
+	app.Flag("query", "Filter the JSON output of a command (e.g. --json) with a subset of JMESPath (fields, indexes, [*], [], *, [?filter] and pipes), such as 'Number' or '[?Active].Number'. Strings are printed without quotes. Implies --non-interactive").PlaceHolder("EXPR").StringVar(&g.Flags.Query)
 	app.Flag("quiet", "Silence all output except direct command output. This won't prevent interactive prompts (see: --accept-defaults, --auto-yes, --non-interactive)").Short('q').BoolVar(&g.Flags.Quiet)
+	app.Flag("read-only", fmt.Sprintf("Fail any command that would make a mutating API call, allowing safe exploration of an account (or via %s)", env.ReadOnly)).BoolVar(&g.Flags.ReadOnly)
+	app.Flag("record-api", "Record all API interactions (sanitized) to the given JSON file, useful for sharing bug reproductions").PlaceHolder("PATH").StringVar(&g.Flags.RecordAPI)
 	app.Flag("token", tokenHelp).Short('t').StringVar(&g.Flags.Token)
 	app.Flag("verbose", "Verbose logging").Short('v').BoolVar(&g.Flags.Verbose)
 
@@ -111,10 +137,52 @@ func Run(opts RunOpts) error {
 		return nil
 	}
 
+	if envErr != nil {
+		return envErr
+	}
+
 	if g.Flags.Quiet {
 		md.File.SetQuiet(true)
 	}
 
+	var q *query.Query
+	if g.Flags.Query != "" {
+		if q, err = query.Compile(g.Flags.Query); err != nil {
+			return fsterr.RemediationError{
+				Inner:       err,
+				Remediation: fsterr.QueryRemediation,
+			}
+		}
+		// NOTE: The output is buffered until the command has finished, so any
+		// prompt wouldn't be seen by the user.
+		g.Flags.NonInteractive = true
+	}
+
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	if g.Flags.ProgressJSON {
+		g.Progress = text.NewProgress(stderr)
+	}
+
+	var profiler *cliProfile
+	if g.Flags.ProfileCLI {
+		// NOTE: The phases are observed from the progress events, which are
+		// only written if --progress-json is also set.
+		if g.Progress == nil {
+			g.Progress = text.NewProgress(nil)
+		}
+		profiler = newCLIProfile(start, g.Progress)
+		defer profiler.Print(stderr)
+	}
+
+	// Nobody can respond to a prompt in a CI environment, so rather than hang
+	// waiting for input we return an error if a prompt is required.
+	if ok, reason := g.Promptable(); !ok {
+		opts.Stdin = text.NoPromptReader{Reader: opts.Stdin, Reason: reason}
+	}
+
 	token, source := g.Token()
 
 	if g.Verbose() {
@@ -128,7 +196,7 @@ func Run(opts RunOpts) error {
 
 	token, err = profile.Init(token, &md, &g, opts.Stdin, opts.Stdout)
 	if err != nil {
-		return err
+		return promptRemediation(err)
 	}
 
 	// If we are using the token from config file, check the file's permissions
@@ -168,6 +236,31 @@ func Run(opts RunOpts) error {
 		return fmt.Errorf("error constructing Fastly API client: %w", err)
 	}
 
+	if g.ReadOnly() {
+		wrapTransport(&g, api.ReadOnly)
+	}
+
+	// NOTE: The requests are printed before read-only mode blocks them, so
+	// that --curl --read-only prints the mutating calls instead of making them.
+	if g.Flags.Curl {
+		wrapTransport(&g, api.NewCurlPrinter(stderr, env.Token).Wrap)
+	}
+
+	if profiler != nil {
+		profiler.api = api.NewProfiler()
+		wrapTransport(&g, profiler.api.Wrap)
+	}
+
+	if g.Flags.RecordAPI != "" {
+		recorder := recordAPI(&g)
+		defer func() {
+			if err := recorder.Save(g.Flags.RecordAPI); err != nil {
+				g.ErrLog.Add(err)
+				text.Warning(opts.Stdout, "Unable to save the recorded API session: %s", err)
+			}
+		}()
+	}
+
 	// NOTE: We return error immediately so there's no issue assigning to global.
 	// nosemgrep
 	g.RTSClient, err = fastly.NewRealtimeStatsClientForEndpoint(token, fastly.DefaultRealtimeStatsEndpoint)
@@ -176,7 +269,7 @@ func Run(opts RunOpts) error {
 		return fmt.Errorf("error constructing Fastly realtime stats client: %w", err)
 	}
 
-	if opts.Versioners.CLI != nil && name != "update" && !version.IsPreRelease(revision.AppVersion) {
+	if opts.Versioners.CLI != nil && name != "update" && !version.IsPreRelease(revision.AppVersion) && !checksForUpdate(command) {
 		f := update.CheckAsync(
 			revision.AppVersion,
 			opts.Versioners.CLI,
@@ -185,7 +278,96 @@ func Run(opts RunOpts) error {
 		defer f(opts.Stdout) // ...and the printing function second, so we hit the timeout
 	}
 
-	return command.Exec(opts.Stdin, opts.Stdout)
+	// When filtering the output, the command's output is buffered so that the
+	// query can be applied once the command has finished.
+	out := opts.Stdout
+	var buf bytes.Buffer
+	if q != nil {
+		out = &buf
+	}
+
+	err = readOnlyRemediation(promptRemediation(command.Exec(opts.Stdin, out)))
+	if q != nil {
+		if err == nil {
+			if err = q.Apply(bytes.NewReader(buf.Bytes()), opts.Stdout); err != nil {
+				err = fsterr.RemediationError{
+					Inner:       fmt.Errorf("error applying --query: %w", err),
+					Remediation: fsterr.QueryRemediation,
+				}
+			}
+		}
+		// The unfiltered output is printed if the command or the query fails, so
+		// that it isn't lost.
+		if err != nil {
+			_, _ = buf.WriteTo(opts.Stdout)
+		}
+	}
+	if err != nil {
+		if ci := g.CI(); ci != "" {
+			var file string
+			if manifestError(err, md) && filesystem.FileExists(manifest.Filename) {
+				file = manifest.Filename
+			}
+			text.CIError(opts.Stdout, ci, file, err.Error())
+		}
+	}
+	return err
+}
+
+// manifestErrors are the errors caused by the content of the fastly.toml.
+var manifestErrors = []error{
+	fsterr.ErrIncompatibleManifestVersion,
+	fsterr.ErrInvalidManifestVersion,
+	fsterr.ErrMissingManifestVersion,
+	fsterr.ErrParsingManifest,
+	fsterr.ErrUnrecognisedManifestVersion,
+}
+
+// manifestError reports whether err was caused by reading or parsing the
+// fastly.toml, so that a CI annotation can point at the file.
+func manifestError(err error, md manifest.Data) bool {
+	if readErr := md.File.ReadError(); readErr != nil && errors.Is(err, readErr) {
+		return true
+	}
+	for _, e := range manifestErrors {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordAPI wraps the HTTP transport of the API clients so that all API
+// interactions are captured by the returned recorder.
+func recordAPI(g *global.Data) *api.Recorder {
+	recorder := api.NewRecorder()
+	wrapTransport(g, recorder.Wrap)
+	return recorder
+}
+
+// wrapTransport wraps the HTTP transport of the API clients.
+func wrapTransport(g *global.Data, wrap func(http.RoundTripper) http.RoundTripper) {
+	if c, ok := g.APIClient.(*fastly.Client); ok && c.HTTPClient != nil {
+		hc := *c.HTTPClient
+		hc.Transport = wrap(hc.Transport)
+		c.HTTPClient = &hc
+	}
+	if c, ok := g.HTTPClient.(*http.Client); ok {
+		hc := *c
+		hc.Transport = wrap(hc.Transport)
+		g.HTTPClient = &hc
+	}
+}
+
+// readOnlyRemediation explains an error caused by read-only mode.
+func readOnlyRemediation(err error) error {
+	if !errors.Is(err, api.ErrReadOnly) {
+		return err
+	}
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: fsterr.ReadOnlyRemediation,
+	}
 }
 
 // RunOpts represent arguments to Run()
@@ -197,6 +379,7 @@ type RunOpts struct {
 	Env        config.Environment
 	ErrLog     fsterr.LogInterface
 	HTTPClient api.HTTPClient
+	Stderr     io.Writer
 	Stdin      io.Reader
 	Stdout     io.Writer
 	Versioners Versioners
@@ -213,6 +396,7 @@ type APIClientFactory func(token, endpoint string) (api.Interface, error)
 type Versioners struct {
 	CLI     github.AssetVersioner
 	Viceroy github.AssetVersioner
+	WasmOpt github.AssetVersioner
 }
 
 // displayTokenSource prints the token source.
@@ -242,3 +426,25 @@ func determineProfile(manifestValue, flagValue string, profiles config.Profiles)
 	name, _ := profile.Default(profiles)
 	return name
 }
+
+// promptRemediation suggests how to avoid prompts when an error was caused by a
+// prompt that couldn't be answered.
+func promptRemediation(err error) error {
+	var re fsterr.RemediationError
+	if !errors.Is(err, text.ErrPromptUnavailable) || errors.As(err, &re) {
+		return err
+	}
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: fsterr.PromptUnavailableRemediation,
+	}
+}
+
+// checksForUpdate indicates if the command performs its own check for a newer
+// version of the CLI.
+func checksForUpdate(command cmd.Command) bool {
+	if vc, ok := command.(*version.RootCommand); ok {
+		return vc.ChecksForUpdate()
+	}
+	return false
+}
@@ -3,14 +3,20 @@ package app_test
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
 )
 
 func TestShellCompletion(t *testing.T) {
@@ -57,6 +63,7 @@ complete -F _fastly_bash_autocomplete fastly
 			Name: "shell evaluate completion options",
 			Args: args("--completion-bash"),
 			WantOutput: `help
+account
 acl
 acl-entry
 auth-token
@@ -65,9 +72,13 @@ compute
 config
 config-store
 config-store-entry
+contacts
 dictionary
 dictionary-entry
+doctor
 domain
+env
+gc
 healthcheck
 ip-list
 log-tail
@@ -76,6 +87,7 @@ object-store
 object-store-entry
 pops
 profile
+propagate
 purge
 resource-link
 secret-store
@@ -83,6 +95,7 @@ secret-store-entry
 service
 service-auth
 service-version
+smoke-test
 stats
 tls-config
 tls-custom
@@ -134,6 +147,436 @@ whoami
 	}
 }
 
+func TestRecordAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"number":1,"active":true,"service_id":"123","updated_at":"2000-01-01T01:00:00Z"}]`))
+	}))
+	defer srv.Close()
+
+	session := filepath.Join(t.TempDir(), "session.json")
+	command := "service-version list --service-id 123 --token abc --endpoint " + srv.URL
+
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args(command+" --record-api "+session), &stdout)
+	opts.APIClient = app.FastlyAPIClient
+	if err := app.Run(opts); err != nil {
+		t.Fatal(err)
+	}
+	recorded := stdout.String()
+
+	data, err := os.ReadFile(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertStringContains(t, string(data), `"Fastly-Key": [
+            "REDACTED"
+          ]`)
+	if strings.Contains(string(data), "abc") {
+		t.Fatalf("recorded session contains the API token: %s", data)
+	}
+
+	// Replay the recorded session without the server.
+	srv.Close()
+	stdout.Reset()
+	opts = testutil.NewRunOpts(testutil.Args(command), &stdout)
+	opts.APIClient = testutil.ReplayAPIClient(t, session)
+	if err := app.Run(opts); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, recorded, stdout.String())
+}
+
+func TestReadOnly(t *testing.T) {
+	var mutations int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			mutations++
+		}
+		if r.URL.Path != "/service/123/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"number":1,"active":true,"service_id":"123","updated_at":"2000-01-01T01:00:00Z"}]`))
+	}))
+	defer srv.Close()
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		env        map[string]string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:       "read allowed",
+			args:       "service-version list --service-id 123 --read-only",
+			wantOutput: "NUMBER  ACTIVE  LAST EDITED (UTC)",
+		},
+		{
+			name:      "mutation prevented by flag",
+			args:      "service-version clone --service-id 123 --version 1 --read-only",
+			wantError: "read-only mode prevented a mutating API request (PUT /service/123/version/1/clone)",
+		},
+		{
+			name:      "mutation prevented by env var",
+			args:      "service-version clone --service-id 123 --version 1",
+			env:       map[string]string{"FASTLY_READ_ONLY": "1"},
+			wantError: "read-only mode prevented a mutating API request",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token abc --endpoint "+srv.URL), &stdout)
+			opts.APIClient = app.FastlyAPIClient
+			opts.Env.Read(testcase.env)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantError != "" {
+				testutil.AssertRemediationErrorContains(t, err, errors.ReadOnlyRemediation)
+			}
+			testutil.AssertEqual(t, 0, mutations)
+		})
+	}
+}
+
+func TestCurl(t *testing.T) {
+	var mutations int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			mutations++
+		}
+		if r.URL.Path != "/service/123/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"number":1,"active":true,"service_id":"123","updated_at":"2000-01-01T01:00:00Z"},{"number":2,"service_id":"123","updated_at":"2000-01-02T01:00:00Z"}]`))
+	}))
+	defer srv.Close()
+
+	pkg := filepath.Join(t.TempDir(), "package.tar.gz")
+	if err := os.WriteFile(pkg, []byte{0x1f, 0x8b, 0x00, 0xff}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name           string
+		args           string
+		wantError      string
+		wantOutput     string
+		wantStderr     string
+		dontWantStderr string
+	}{
+		{
+			name:       "printed and made",
+			args:       "service-version list --service-id 123 --curl",
+			wantOutput: "NUMBER  ACTIVE  LAST EDITED (UTC)",
+			wantStderr: "curl '" + srv.URL + "/service/123/version' \\\n  -H \"Fastly-Key: $FASTLY_API_TOKEN\"\n",
+		},
+		{
+			name:       "printed instead of made with --read-only",
+			args:       "service-version clone --service-id 123 --version 1 --curl --read-only",
+			wantError:  "read-only mode prevented a mutating API request",
+			wantStderr: "curl -X PUT '" + srv.URL + "/service/123/version/1/clone'",
+		},
+		{
+			name:           "sensitive body fields redacted",
+			args:           "logging splunk create --service-id 123 --version 2 --name log --url https://example.com --auth-token s3cr3t --curl --read-only",
+			wantError:      "read-only mode prevented a mutating API request",
+			wantStderr:     "token=REDACTED",
+			dontWantStderr: "s3cr3t",
+		},
+		{
+			name:           "uploaded files referenced by name",
+			args:           "compute update --service-id 123 --version 2 --package " + pkg + " --curl --read-only",
+			wantError:      "read-only mode prevented a mutating API request",
+			wantStderr:     "-F 'package=@package.tar.gz'",
+			dontWantStderr: "multipart/form-data",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token abc --endpoint "+srv.URL), &stdout)
+			opts.Stderr = &stderr
+			opts.APIClient = app.FastlyAPIClient
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertStringContains(t, stderr.String(), testcase.wantStderr)
+			testutil.AssertStringDoesntContain(t, stderr.String(), "abc")
+			if testcase.dontWantStderr != "" {
+				testutil.AssertStringDoesntContain(t, stderr.String(), testcase.dontWantStderr)
+			}
+			testutil.AssertEqual(t, 0, mutations)
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"number":1,"active":false,"service_id":"123","updated_at":"2000-01-01T01:00:00Z"},{"number":2,"active":true,"service_id":"123","updated_at":"2000-01-02T01:00:00Z"}]`))
+	}))
+	defer srv.Close()
+
+	for _, testcase := range []struct {
+		name                 string
+		args                 string
+		wantError            string
+		wantRemediationError string
+		wantOutput           string
+	}{
+		{
+			name:                 "validate invalid expression",
+			args:                 "service-version list --service-id 123 --json --query [?Active",
+			wantError:            "invalid query '[?Active'",
+			wantRemediationError: "JMESPath",
+		},
+		{
+			name:                 "validate output isn't JSON",
+			args:                 "service-version list --service-id 123 --query [0]",
+			wantError:            "error applying --query: the output isn't JSON",
+			wantRemediationError: "--json",
+			wantOutput:           "NUMBER",
+		},
+		{
+			name:       "filter",
+			args:       "service-version list --service-id 123 --json --query [?Active].Number",
+			wantOutput: "[\n  2\n]\n",
+		},
+		{
+			name:       "scalar",
+			args:       "service-version list --service-id 123 --json --query [-1].ServiceID",
+			wantOutput: "123\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token abc --endpoint "+srv.URL), &stdout)
+			opts.APIClient = app.FastlyAPIClient
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			if testcase.wantError == "" {
+				testutil.AssertString(t, testcase.wantOutput, stdout.String())
+			} else {
+				testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			}
+		})
+	}
+}
+
+func TestOutputCI(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		env        map[string]string
+		wantOutput string
+	}{
+		{
+			name:       "github",
+			args:       "service describe --output-ci github",
+			wantOutput: "::error::error reading service: no service ID found\n",
+		},
+		{
+			name:       "auto detects github",
+			args:       "service describe --output-ci auto",
+			env:        map[string]string{"GITHUB_ACTIONS": "true"},
+			wantOutput: "::error::error reading service: no service ID found\n",
+		},
+		{
+			name: "auto without a detected CI",
+			args: "service describe --output-ci auto",
+		},
+		{
+			name: "disabled",
+			args: "service describe",
+			env:  map[string]string{"GITHUB_ACTIONS": "true"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.Env.Read(testcase.env)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, "error reading service: no service ID found")
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+		})
+	}
+}
+
+// TestOutputCIManifest validates only errors caused by the fastly.toml are
+// annotated with the file.
+func TestOutputCIManifest(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, testcase := range []struct {
+		name       string
+		manifest   string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:       "parse error",
+			manifest:   "name = [\n",
+			wantError:  "failed to parse the fastly.toml manifest",
+			wantOutput: "::error file=fastly.toml::failed to parse the fastly.toml manifest",
+		},
+		{
+			name:       "other error",
+			manifest:   "name = \"package\"\nmanifest_version = 2\nlanguage = \"nope\"\n",
+			wantError:  "unsupported language nope",
+			wantOutput: "::error::unsupported language nope\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(testcase.manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("compute build --output-ci github"), &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestPromptInCI(t *testing.T) {
+	api := mock.API{
+		GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{ID: i.ID, Name: "Foo", ActiveVersion: fastly.Version{Number: 1}}, nil
+		},
+		ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{}, nil
+		},
+		PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+			return &fastly.Purge{Status: "ok"}, nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		env        map[string]string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:      "CI detected",
+			env:       map[string]string{"CI": "true"},
+			wantError: "unable to prompt for input (CI environment detected via $CI): Type the service name (Foo) to confirm:",
+		},
+		{
+			name:       "CI disabled",
+			env:        map[string]string{"CI": "false"},
+			wantOutput: "Purge all status: ok",
+		},
+		{
+			name:       "no CI",
+			wantOutput: "Purge all status: ok",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("purge --all --service-id 123 --token 123"), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			opts.Env.Read(testcase.env)
+			opts.Stdin = strings.NewReader("Foo\n")
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantError != "" {
+				testutil.AssertRemediationErrorContains(t, err, errors.PromptUnavailableRemediation)
+			}
+		})
+	}
+}
+
+func TestManifestEnv(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := "manifest_version = 2\nname = \"envs\"\nservice_id = \"default-id\"\n\n[env.staging]\nservice_id = \"staging-id\"\n"
+	if err := os.WriteFile("fastly.toml", []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	api := mock.API{
+		ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+			return []*fastly.Version{{ServiceID: i.ServiceID, Number: 1}}, nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:       "top-level values",
+			args:       "service-version list --verbose",
+			wantOutput: "Service ID (via fastly.toml): default-id",
+		},
+		{
+			name:       "environment",
+			args:       "service-version list --verbose --env staging",
+			wantOutput: "Service ID (via fastly.toml): staging-id",
+		},
+		{
+			name:       "environment with equals sign",
+			args:       "service-version list --verbose --env=staging",
+			wantOutput: "Service ID (via fastly.toml): staging-id",
+		},
+		{
+			name:       "flag overrides environment",
+			args:       "service-version list --verbose --env staging --service-id 123",
+			wantOutput: "Service ID (via --service-id): 123",
+		},
+		{
+			name:      "unknown environment",
+			args:      "service-version list --env production",
+			wantError: "the environment 'production' isn't defined in the fastly.toml manifest",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token 123"), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
 // stripTrailingSpace removes any trailing spaces from the multiline str.
 func stripTrailingSpace(str string) string {
 	buf := bytes.NewBuffer(nil)
@@ -145,3 +588,133 @@ func stripTrailingSpace(str string) string {
 	}
 	return buf.String()
 }
+
+func TestProfileCLI(t *testing.T) {
+	const body = `[{"number":1,"active":true,"service_id":"123","updated_at":"2000-01-01T01:00:00Z"}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/123/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		wantError     string
+		wantStderr    []string
+		wantNoStderr  string
+		wantNoProfile bool
+	}{
+		{
+			name: "api calls",
+			args: "service-version list --service-id 123 --token abc --profile-cli --endpoint " + srv.URL,
+			wantStderr: []string{
+				"API calls 1",
+				"Bytes sent 0",
+				fmt.Sprintf("Bytes received %d", len(body)),
+			},
+		},
+		{
+			name:      "phases",
+			args:      "compute deploy --token 123 --profile-cli",
+			wantError: "error reading package manifest",
+			wantStderr: []string{
+				"API calls 0",
+				"Phase 'deploy'",
+			},
+			// The progress events are only written with --progress-json.
+			wantNoStderr: `"phase"`,
+		},
+		{
+			name:          "disabled",
+			args:          "service-version list --service-id 123 --token abc --endpoint " + srv.URL,
+			wantNoProfile: true,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = app.FastlyAPIClient
+			opts.Stderr = &stderr
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantNoProfile {
+				testutil.AssertString(t, "", stderr.String())
+				return
+			}
+
+			profile := strings.Join(strings.Fields(stderr.String()), " ")
+			testutil.AssertStringContains(t, profile, "Profile: Wall time")
+			for _, s := range testcase.wantStderr {
+				testutil.AssertStringContains(t, profile, s)
+			}
+			if testcase.wantNoStderr != "" && strings.Contains(profile, testcase.wantNoStderr) {
+				t.Fatalf("want no %s in stderr, got: %s", testcase.wantNoStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestProgressJSON(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		wantStderr []string
+	}{
+		{
+			name: "enabled",
+			args: "compute deploy --progress-json --token 123",
+			wantStderr: []string{
+				`"phase":"deploy","status":"started"`,
+				`"phase":"deploy","status":"failed","error":"error reading package manifest"`,
+			},
+		},
+		{
+			name: "disabled",
+			args: "compute deploy --token 123",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.Stderr = &stderr
+			err := app.Run(opts)
+			if err == nil {
+				t.Fatal("want error for missing package")
+			}
+			lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+			if len(testcase.wantStderr) == 0 {
+				testutil.AssertString(t, "", stderr.String())
+				return
+			}
+			if len(lines) != len(testcase.wantStderr) {
+				t.Fatalf("want %d events, got: %s", len(testcase.wantStderr), stderr.String())
+			}
+			for i, want := range testcase.wantStderr {
+				testutil.AssertStringContains(t, lines[i], want)
+			}
+		})
+	}
+}
@@ -147,10 +147,18 @@ var UsageTemplateFuncs = template.FuncMap{
 var globalFlags = map[string]bool{
 	"accept-defaults": true,
 	"auto-yes":        true,
+	"curl":            true,
+	"env":             true,
 	"help":            true,
 	"non-interactive": true,
+	"output-ci":       true,
 	"profile":         true,
+	"profile-cli":     true,
+	"progress-json":   true,
+	"query":           true,
 	"quiet":           true,
+	"read-only":       true,
+	"record-api":      true,
 	"token":           true,
 	"verbose":         true,
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/env"
@@ -231,6 +232,25 @@ func IsVerboseAndQuiet(args []string) bool {
 	return len(matches) > 1
 }
 
+// EnvFlag returns the value of the global --env flag within args (if any).
+//
+// NOTE: The manifest environment is applied before the flags are parsed, as
+// each command is given a copy of the manifest when it's defined.
+func EnvFlag(args []string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == "--env" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if v, ok := strings.CutPrefix(a, "--env="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
 // IsGlobalFlagsOnly indicates if the user called the binary with any
 // permutation order of the globally defined flags.
 //
@@ -256,14 +276,22 @@ func IsGlobalFlagsOnly(args []string) bool {
 		"-d":                0,
 		"--auto-yes":        0,
 		"-y":                0,
+		"--curl":            0,
 		"--endpoint":        1,
+		"--env":             1,
 		"--help":            0,
 		"--non-interactive": 0,
 		"-i":                0,
+		"--output-ci":       1,
 		"--profile":         1,
 		"-o":                1,
+		"--profile-cli":     0,
+		"--progress-json":   0,
+		"--query":           1,
 		"--quiet":           0,
 		"-q":                0,
+		"--read-only":       0,
+		"--record-api":      1,
 		"--token":           1,
 		"-t":                1,
 		"--verbose":         0,
@@ -237,6 +237,96 @@ func (ac *OptionalAutoClone) Parse(v *fastly.Version, sid string, verbose bool,
 	return v, nil
 }
 
+// ValueSource models the --value-from-env and --value-from-file flags, which
+// allow an item value to be provided without it ending up in shell history.
+type ValueSource struct {
+	Env  string
+	File string
+}
+
+// RegisterValueSourceFlags defines the --value-from-env and --value-from-file
+// flags, which are alternatives to a command's --value flag.
+func (b Base) RegisterValueSourceFlags(vs *ValueSource) {
+	b.CmdClause.Flag("value-from-env", "Read the item value from the named environment variable (alternative to --value)").PlaceHolder("VAR").StringVar(&vs.Env)
+	b.CmdClause.Flag("value-from-file", "Read the item value from a file (alternative to --value)").PlaceHolder("PATH").StringVar(&vs.File)
+}
+
+// Resolve assigns the value read from either the environment variable or the
+// file to dst. If neither flag was provided then dst is left unchanged.
+//
+// NOTE: A single trailing newline is removed from a file's content, as most
+// editors append one.
+func (vs ValueSource) Resolve(dst *string) error {
+	var n int
+	for _, s := range []string{*dst, vs.Env, vs.File} {
+		if s != "" {
+			n++
+		}
+	}
+	if n > 1 {
+		return fsterr.RemediationError{
+			Inner:       errors.New("multiple item values provided"),
+			Remediation: "Provide only one of --value, --value-from-env or --value-from-file.",
+		}
+	}
+
+	switch {
+	case vs.Env != "":
+		v, ok := os.LookupEnv(vs.Env)
+		if !ok {
+			return fmt.Errorf("error reading value: environment variable %s is not set", vs.Env)
+		}
+		*dst = v
+	case vs.File != "":
+		v, err := ReadValueFile(vs.File)
+		if err != nil {
+			return fmt.Errorf("error reading value from file: %w", err)
+		}
+		*dst = v
+	}
+	return nil
+}
+
+// ApplyFileFlag populates value from the file referenced by file (if set), a
+// flag (e.g. --hec-token-file) that's an alternative to passing the value on
+// the command line, where it would be kept in the shell history.
+//
+// NOTE: The flags can't both be set, and the file can't be empty.
+func ApplyFileFlag(file OptionalString, value *OptionalString, fileFlag, valueFlag string) error {
+	if !file.WasSet {
+		return nil
+	}
+	if value.WasSet {
+		return fmt.Errorf("--%s and --%s can't be used together", valueFlag, fileFlag)
+	}
+	v, err := ReadValueFile(file.Value)
+	if err != nil {
+		return fmt.Errorf("error reading --%s: %w", fileFlag, err)
+	}
+	if v == "" {
+		return fmt.Errorf("error reading --%s: the file is empty", fileFlag)
+	}
+	value.WasSet = true
+	value.Value = v
+	return nil
+}
+
+// ReadValueFile returns the content of the file at path, with a single
+// trailing newline removed as most editors append one.
+func ReadValueFile(path string) (string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as we require a user to configure their own environment.
+	/* #nosec */
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	v := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(v, "\r"), nil
+}
+
 // GetActiveVersion returns the active service version.
 func GetActiveVersion(vs []*fastly.Version) (*fastly.Version, error) {
 	for _, v := range vs {
@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -219,6 +220,56 @@ func TestGetSpecifiedVersion(t *testing.T) {
 	}
 }
 
+func TestValueSourceResolve(t *testing.T) {
+	t.Setenv("FASTLY_TEST_ITEM_VALUE", "from-env")
+	file := testutil.MakeTempFile(t, "from-file\n")
+	defer os.RemoveAll(file)
+
+	cases := map[string]struct {
+		value     string
+		source    cmd.ValueSource
+		wantValue string
+		wantError string
+	}{
+		"no source": {
+			value:     "from-flag",
+			wantValue: "from-flag",
+		},
+		"from env": {
+			source:    cmd.ValueSource{Env: "FASTLY_TEST_ITEM_VALUE"},
+			wantValue: "from-env",
+		},
+		"from unset env": {
+			source:    cmd.ValueSource{Env: "FASTLY_TEST_ITEM_VALUE_UNSET"},
+			wantError: "environment variable FASTLY_TEST_ITEM_VALUE_UNSET is not set",
+		},
+		"from file": {
+			source:    cmd.ValueSource{File: file},
+			wantValue: "from-file",
+		},
+		"from missing file": {
+			source:    cmd.ValueSource{File: "missing-file"},
+			wantError: "error reading value from file",
+		},
+		"multiple sources": {
+			value:     "from-flag",
+			source:    cmd.ValueSource{Env: "FASTLY_TEST_ITEM_VALUE"},
+			wantError: "multiple item values provided",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			value := c.value
+			err := c.source.Resolve(&value)
+			testutil.AssertErrorContains(t, err, c.wantError)
+			if c.wantError == "" {
+				testutil.AssertString(t, c.wantValue, value)
+			}
+		})
+	}
+}
+
 func TestOptionalAutoCloneParse(t *testing.T) {
 	cases := map[string]struct {
 		version        *fastly.Version
@@ -0,0 +1,81 @@
+package account_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDescribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Fastly-Key") != "123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/current_customer", "/customer/abc":
+			_ = json.NewEncoder(w).Encode(customer.Customer{
+				ID:                "abc",
+				Name:              "Example Inc",
+				OwnerID:           "u1",
+				BillingContactID:  "u2",
+				SecurityContactID: "u3",
+				PricingPlan:       "enterprise",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"msg":"Record not found","detail":"Cannot find customer"}`))
+		}
+	}))
+	defer server.Close()
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate the current customer is described",
+			Args: args("account describe --token 123 --endpoint " + server.URL),
+			WantOutputs: []string{
+				"ID: abc",
+				"Name: Example Inc",
+				"Pricing plan: enterprise",
+				"Billing contact ID: u2",
+				"Security contact ID: u3",
+			},
+		},
+		{
+			Name:       "validate --customer-id",
+			Args:       args("account describe --customer-id abc --token 123 --endpoint " + server.URL),
+			WantOutput: "Owner ID: u1",
+		},
+		{
+			Name:       "validate --json",
+			Args:       args("account describe --json --token 123 --endpoint " + server.URL),
+			WantOutput: `"security_contact_id": "u3"`,
+		},
+		{
+			Name:      "validate API error",
+			Args:      args("account describe --customer-id missing --token 123 --endpoint " + server.URL),
+			WantError: "error from API: 404 Not Found: Record not found Cannot find customer",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package account
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// NewDescribeCommand returns a usable command registered under the parent.
+func NewDescribeCommand(parent cmd.Registerer, g *global.Data) *DescribeCommand {
+	var c DescribeCommand
+	c.CmdClause = parent.Command("describe", "Show the details of the customer account, including its billing, legal, security and technical contacts").Alias("get")
+	c.Globals = g
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagCustomerIDName,
+		Description: "Alphanumeric string identifying the customer (falls back to FASTLY_CUSTOMER_ID, then the customer of the authenticated user)",
+		Dst:         &c.customerID.Value,
+		Action:      c.customerID.Set,
+	})
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// DescribeCommand calls the Fastly API to describe the customer account.
+type DescribeCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	customerID cmd.OptionalCustomerID
+}
+
+// Exec invokes the application logic for the command.
+func (c *DescribeCommand) Exec(_ io.Reader, out io.Writer) error {
+	token, s := c.Globals.Token()
+	if s == lookup.SourceUndefined {
+		return fsterr.ErrNoToken
+	}
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	endpoint, _ := c.Globals.Endpoint()
+	client := customer.Client{
+		Endpoint:   endpoint,
+		Token:      token,
+		HTTPClient: c.Globals.HTTPClient,
+	}
+
+	// NOTE: Without a customer ID the customer of the authenticated user is
+	// described.
+	var (
+		r   *customer.Customer
+		err error
+	)
+	if c.customerID.Parse() == nil {
+		r, err = client.Get(c.customerID.Value)
+	} else {
+		r, err = client.GetCurrent()
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": c.customerID.Value,
+		})
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, r); ok {
+		return err
+	}
+
+	text.PrintLines(out, text.Lines{
+		"ID":                   r.ID,
+		"Name":                 r.Name,
+		"Owner ID":             r.OwnerID,
+		"Pricing plan":         r.PricingPlan,
+		"Billing network type": r.BillingNetworkType,
+		"Phone number":         r.PhoneNumber,
+		"Postal address":       r.PostalAddress,
+		"Billing contact ID":   r.BillingContactID,
+		"Legal contact ID":     r.LegalContactID,
+		"Security contact ID":  r.SecurityContactID,
+		"Technical contact ID": r.TechnicalContactID,
+		"Created at":           r.CreatedAt,
+		"Updated at":           r.UpdatedAt,
+	})
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package account contains commands to inspect the Fastly customer account.
+package account
@@ -2,9 +2,13 @@ package acl_test
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/acl"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/go-fastly/v7/fastly"
@@ -388,6 +392,218 @@ func TestACLUpdate(t *testing.T) {
 	}
 }
 
+func TestACLCreateFromIPList(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "# Office ranges\n192.0.2.0/24\n198.51.100.7\n\n2001:db8::/32 # IPv6\n192.0.2.0/24\n")
+	}))
+	defer list.Close()
+	aws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"prefixes":[{"ip_prefix":"203.0.113.0/24","region":"us-east-1","service":"CLOUDFRONT"},{"ip_prefix":"198.51.100.0/24","region":"us-east-1","service":"EC2"}],"ipv6_prefixes":[{"ipv6_prefix":"2001:db8:1::/48","region":"eu-west-1","service":"CLOUDFRONT"}]}`)
+	}))
+	defer aws.Close()
+	awsURL := acl.AWSIPRangesURL
+	acl.AWSIPRangesURL = aws.URL
+	defer func() { acl.AWSIPRangesURL = awsURL }()
+
+	createACL := func(i *fastly.CreateACLInput) (*fastly.ACL, error) {
+		return &fastly.ACL{
+			ID:             "456",
+			Name:           *i.Name,
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+		}, nil
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		name        string
+		args        []string
+		api         mock.API
+		wantError   string
+		wantOutput  []string
+		wantEntries []string
+	}{
+		{
+			name: "validate unsupported IP list",
+			args: args("acl create --name foo --service-id 123 --version 3 --from-ip-list azure"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CreateACLFn: func(i *fastly.CreateACLInput) (*fastly.ACL, error) {
+					t.Fatal("unexpected ACL creation")
+					return nil, nil
+				},
+			},
+			wantError: "unsupported IP list 'azure'",
+		},
+		{
+			name: "validate entries from a URL",
+			args: args("acl create --name foo --service-id 123 --version 3 --from-ip-list " + list.URL),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CreateACLFn:    createACL,
+			},
+			wantOutput: []string{
+				"Created ACL 'foo' (id: 456, service: 123, version: 3)",
+				"Added 3 entries from the IP list",
+				"fastly acl refresh --acl-id 456 --service-id 123",
+			},
+			wantEntries: []string{"192.0.2.0/24", "198.51.100.7", "2001:db8::/32"},
+		},
+		{
+			name: "validate entries from Fastly's public IPs",
+			args: args("acl create --name foo --service-id 123 --version 3 --from-ip-list fastly"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CreateACLFn:    createACL,
+				AllIPsFn: func() (fastly.IPAddrs, fastly.IPAddrs, error) {
+					return fastly.IPAddrs{"23.235.32.0/20"}, fastly.IPAddrs{"2a04:4e40::/32"}, nil
+				},
+			},
+			wantOutput:  []string{"Added 2 entries from the IP list 'fastly'"},
+			wantEntries: []string{"23.235.32.0/20", "2a04:4e40::/32"},
+		},
+		{
+			name: "validate entries from AWS filtered by service",
+			args: args("acl create --name foo --service-id 123 --version 3 --from-ip-list aws:cloudfront"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CreateACLFn:    createACL,
+			},
+			wantOutput:  []string{"Added 2 entries from the IP list 'aws:cloudfront'"},
+			wantEntries: []string{"2001:db8:1::/48", "203.0.113.0/24"},
+		},
+		{
+			name: "validate AWS filter without matches",
+			args: args("acl create --name foo --service-id 123 --version 3 --from-ip-list aws:EC2:eu-west-1"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+			},
+			wantError: "the IP list 'aws:EC2:eu-west-1' has no entries",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			var entries []string
+			testcase.api.BatchModifyACLEntriesFn = func(i *fastly.BatchModifyACLEntriesInput) error {
+				for _, e := range i.Entries {
+					testutil.AssertEqual(t, fastly.CreateBatchOperation, e.Operation)
+					testutil.AssertStringContains(t, *e.Comment, "from-ip-list: ")
+					entry := *e.IP
+					if e.Subnet != nil {
+						entry = fmt.Sprintf("%s/%d", entry, *e.Subnet)
+					}
+					entries = append(entries, entry)
+				}
+				return nil
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantEntries, entries)
+		})
+	}
+}
+
+func TestACLRefresh(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "192.0.2.0/24\n203.0.113.0/24\n198.51.100.7\n")
+	}))
+	defer list.Close()
+	comment := "from-ip-list: " + list.URL
+
+	subnet := func(n int) *int { return &n }
+	existing := []*fastly.ACLEntry{
+		{ID: "1", IP: "192.0.2.0", Subnet: subnet(24), Comment: comment},
+		{ID: "2", IP: "198.18.0.0", Subnet: subnet(15), Comment: comment},
+		{ID: "3", IP: "198.51.100.7", Comment: "office"},
+		{ID: "4", IP: "10.0.0.1", Comment: "manually added"},
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		name       string
+		args       []string
+		entries    []*fastly.ACLEntry
+		wantError  string
+		wantOutput string
+		wantOps    []string
+	}{
+		{
+			name:      "validate missing --acl-id flag",
+			args:      args("acl refresh --service-id 123"),
+			wantError: "error parsing arguments: required flag --acl-id not provided",
+		},
+		{
+			name:      "validate ACL without IP list entries",
+			args:      args("acl refresh --acl-id 456 --service-id 123"),
+			entries:   existing[2:],
+			wantError: "ACL 456 has no entries populated from an IP list",
+		},
+		{
+			name:       "validate IP list inferred from the entries",
+			args:       args("acl refresh --acl-id 456 --service-id 123"),
+			entries:    existing,
+			wantOutput: "Refreshed ACL 456 from the IP list '" + list.URL + "': 1 entries added, 1 removed",
+			wantOps:    []string{"delete 2", "create 203.0.113.0/24"},
+		},
+		{
+			name:       "validate --dry-run",
+			args:       args("acl refresh --acl-id 456 --service-id 123 --dry-run"),
+			entries:    existing,
+			wantOutput: "Dry run: 1 entries would be added and 1 removed from ACL 456",
+		},
+		{
+			name: "validate ACL up to date",
+			args: args("acl refresh --acl-id 456 --service-id 123 --from-ip-list " + list.URL),
+			entries: []*fastly.ACLEntry{
+				{ID: "1", IP: "192.0.2.0", Subnet: subnet(24), Comment: comment},
+				{ID: "5", IP: "203.0.113.0", Subnet: subnet(24), Comment: comment},
+				{ID: "3", IP: "198.51.100.7", Comment: "office"},
+			},
+			wantOutput: "ACL 456 is up to date with the IP list",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			var ops []string
+			api := mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &aclEntryPaginator{entries: testcase.entries}
+				},
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					for _, e := range i.Entries {
+						switch e.Operation {
+						case fastly.DeleteBatchOperation:
+							ops = append(ops, "delete "+*e.ID)
+						default:
+							ops = append(ops, fmt.Sprintf("%s %s/%d", e.Operation, *e.IP, *e.Subnet))
+						}
+					}
+					return nil
+				},
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantOps, ops)
+		})
+	}
+}
+
 func getACL(i *fastly.GetACLInput) (*fastly.ACL, error) {
 	t := testutil.Date
 
@@ -429,3 +645,25 @@ func listACLs(i *fastly.ListACLsInput) ([]*fastly.ACL, error) {
 	}
 	return vs, nil
 }
+
+// aclEntryPaginator returns the entries as a single page.
+type aclEntryPaginator struct {
+	entries []*fastly.ACLEntry
+	done    bool
+}
+
+func (p *aclEntryPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p *aclEntryPaginator) Remaining() int {
+	if p.done {
+		return 0
+	}
+	return 1
+}
+
+func (p *aclEntryPaginator) GetNext() ([]*fastly.ACLEntry, error) {
+	p.done = true
+	return p.entries, nil
+}
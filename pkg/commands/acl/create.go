@@ -1,6 +1,7 @@
 package acl
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/cmd"
@@ -35,6 +36,7 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Action: c.autoClone.Set,
 		Dst:    &c.autoClone.Value,
 	})
+	c.CmdClause.Flag("from-ip-list", fmt.Sprintf("Populate the ACL with the entries of an IP list: %s", ipListSources)).StringVar(&c.fromIPList)
 	c.CmdClause.Flag("name", "Name for the ACL. Must start with an alphanumeric character and contain only alphanumeric characters, underscores, and whitespace").Action(c.name.Set).StringVar(&c.name.Value)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
@@ -57,6 +59,7 @@ type CreateCommand struct {
 	cmd.Base
 
 	autoClone      cmd.OptionalAutoClone
+	fromIPList     string
 	manifest       manifest.Data
 	name           cmd.OptionalString
 	serviceName    cmd.OptionalServiceNameID
@@ -83,6 +86,17 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	// NOTE: The IP list is fetched first, so that an ACL isn't created if the
+	// list is unavailable.
+	var entries []ipListEntry
+	if c.fromIPList != "" {
+		entries, err = fetchIPList(c.fromIPList, c.Globals)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+
 	input := c.constructInput(serviceID, serviceVersion.Number)
 
 	a, err := c.Globals.APIClient.CreateACL(input)
@@ -95,6 +109,22 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	text.Success(out, "Created ACL '%s' (id: %s, service: %s, version: %d)", a.Name, a.ID, a.ServiceID, a.ServiceVersion)
+
+	if c.fromIPList == "" {
+		return nil
+	}
+	if err := batchModifyEntries(c.Globals, a.ServiceID, a.ID, createEntryOps(entries, c.fromIPList)); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     a.ID,
+			"Service ID": a.ServiceID,
+		})
+		return errors.RemediationError{
+			Inner:       fmt.Errorf("error adding the entries of the IP list '%s': %w", c.fromIPList, err),
+			Remediation: fmt.Sprintf("The ACL was created. Add its entries with `fastly acl refresh --acl-id %s --service-id %s --from-ip-list %s`.", a.ID, a.ServiceID, c.fromIPList),
+		}
+	}
+	text.Success(out, "Added %d entries from the IP list '%s'", len(entries), c.fromIPList)
+	text.Info(out, "Keep the entries up to date with `fastly acl refresh --acl-id %s --service-id %s`.", a.ID, a.ServiceID)
 	return nil
 }
 
@@ -0,0 +1,269 @@
+package acl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The published IP ranges of the cloud providers supported by --from-ip-list.
+//
+// NOTE: These are package level variables as it makes testing the behaviour
+// of the package easier because the test code can replace the values when
+// running the test suite.
+var (
+	AWSIPRangesURL = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+	GCPIPRangesURL = "https://www.gstatic.com/ipranges/cloud.json"
+)
+
+// ipListCommentPrefix marks the ACL entries populated from an IP list, so that
+// `acl refresh` only modifies those entries and can determine their source.
+const ipListCommentPrefix = "from-ip-list: "
+
+// ipListSources describes the supported values of --from-ip-list.
+const ipListSources = "fastly (Fastly's public IPs), aws[:SERVICE[:REGION]] (e.g. aws:CLOUDFRONT), gcp[:SCOPE] (e.g. gcp:us-east1), or an http(s) URL returning an IP or CIDR per line"
+
+// ipListEntry is an IP or subnet of an IP list.
+type ipListEntry struct {
+	IP     string
+	Subnet *int
+}
+
+// key uniquely identifies the entry, e.g. 192.0.2.0/24.
+func (e ipListEntry) key() string {
+	return entryKey(e.IP, e.Subnet)
+}
+
+// entryKey uniquely identifies an ACL entry by its IP and subnet.
+func entryKey(ip string, subnet *int) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+	if subnet == nil {
+		return ip
+	}
+	return ip + "/" + strconv.Itoa(*subnet)
+}
+
+// ipListComment is the comment of the ACL entries populated from source.
+func ipListComment(source string) string {
+	return ipListCommentPrefix + source
+}
+
+// fetchIPList returns the deduplicated entries of the --from-ip-list source.
+func fetchIPList(source string, g *global.Data) ([]ipListEntry, error) {
+	provider, filter, _ := strings.Cut(source, ":")
+
+	var (
+		cidrs []string
+		err   error
+	)
+	switch {
+	case source == "fastly":
+		var v4, v6 fastly.IPAddrs
+		v4, v6, err = g.APIClient.AllIPs()
+		cidrs = append(v4, v6...)
+	case provider == "aws":
+		cidrs, err = fetchAWSRanges(g.HTTPClient, filter)
+	case provider == "gcp":
+		cidrs, err = fetchGCPRanges(g.HTTPClient, filter)
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		cidrs, err = fetchURLList(g.HTTPClient, source)
+	default:
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("unsupported IP list '%s'", source),
+			Remediation: fmt.Sprintf("The --from-ip-list flag accepts: %s.", ipListSources),
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the IP list '%s': %w", source, err)
+	}
+
+	seen := make(map[string]bool, len(cidrs))
+	var entries []ipListEntry
+	for _, cidr := range cidrs {
+		e, err := parseIPListEntry(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the IP list '%s': %w", source, err)
+		}
+		if seen[e.key()] {
+			continue
+		}
+		seen[e.key()] = true
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("the IP list '%s' has no entries", source),
+			Remediation: "Check the service or region of the cloud provider, or the content returned by the URL.",
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key() < entries[j].key()
+	})
+	return entries, nil
+}
+
+// parseIPListEntry parses an IP address or CIDR block.
+func parseIPListEntry(s string) (ipListEntry, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return ipListEntry{}, fmt.Errorf("invalid IP address '%s'", s)
+		}
+		return ipListEntry{IP: ip.String()}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return ipListEntry{}, fmt.Errorf("invalid CIDR block '%s'", s)
+	}
+	ones, _ := ipnet.Mask.Size()
+	return ipListEntry{IP: ipnet.IP.String(), Subnet: &ones}, nil
+}
+
+// fetch returns the body of a GET request to url.
+func fetch(client api.HTTPClient, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close() // #nosec G307
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s: %s", url, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// fetchURLList returns the IPs and CIDR blocks listed by url, one per line.
+// Blank lines and comments starting with # are ignored.
+func fetchURLList(client api.HTTPClient, url string) ([]string, error) {
+	body, err := fetch(client, url)
+	if err != nil {
+		return nil, err
+	}
+	var cidrs []string
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			cidrs = append(cidrs, line)
+		}
+	}
+	return cidrs, scanner.Err()
+}
+
+// fetchAWSRanges returns the published AWS IP ranges, optionally filtered by
+// service and region (e.g. CLOUDFRONT:us-east-1).
+func fetchAWSRanges(client api.HTTPClient, filter string) ([]string, error) {
+	body, err := fetch(client, AWSIPRangesURL)
+	if err != nil {
+		return nil, err
+	}
+	var ranges struct {
+		Prefixes []struct {
+			IPPrefix string `json:"ip_prefix"`
+			Region   string `json:"region"`
+			Service  string `json:"service"`
+		} `json:"prefixes"`
+		IPv6Prefixes []struct {
+			IPv6Prefix string `json:"ipv6_prefix"`
+			Region     string `json:"region"`
+			Service    string `json:"service"`
+		} `json:"ipv6_prefixes"`
+	}
+	if err := json.Unmarshal(body, &ranges); err != nil {
+		return nil, fmt.Errorf("error decoding AWS IP ranges: %w", err)
+	}
+
+	service, region, _ := strings.Cut(filter, ":")
+	match := func(s, r string) bool {
+		return (service == "" || strings.EqualFold(s, service)) && (region == "" || strings.EqualFold(r, region))
+	}
+	var cidrs []string
+	for _, p := range ranges.Prefixes {
+		if match(p.Service, p.Region) {
+			cidrs = append(cidrs, p.IPPrefix)
+		}
+	}
+	for _, p := range ranges.IPv6Prefixes {
+		if match(p.Service, p.Region) {
+			cidrs = append(cidrs, p.IPv6Prefix)
+		}
+	}
+	return cidrs, nil
+}
+
+// fetchGCPRanges returns the published Google Cloud IP ranges, optionally
+// filtered by scope (e.g. us-east1).
+func fetchGCPRanges(client api.HTTPClient, scope string) ([]string, error) {
+	body, err := fetch(client, GCPIPRangesURL)
+	if err != nil {
+		return nil, err
+	}
+	var ranges struct {
+		Prefixes []struct {
+			IPv4Prefix string `json:"ipv4Prefix"`
+			IPv6Prefix string `json:"ipv6Prefix"`
+			Scope      string `json:"scope"`
+		} `json:"prefixes"`
+	}
+	if err := json.Unmarshal(body, &ranges); err != nil {
+		return nil, fmt.Errorf("error decoding Google Cloud IP ranges: %w", err)
+	}
+
+	var cidrs []string
+	for _, p := range ranges.Prefixes {
+		if scope != "" && !strings.EqualFold(p.Scope, scope) {
+			continue
+		}
+		for _, cidr := range []string{p.IPv4Prefix, p.IPv6Prefix} {
+			if cidr != "" {
+				cidrs = append(cidrs, cidr)
+			}
+		}
+	}
+	return cidrs, nil
+}
+
+// batchModifyEntries applies the operations to the ACL in batches of the
+// maximum size accepted by the API.
+func batchModifyEntries(g *global.Data, serviceID, aclID string, ops []*fastly.BatchACLEntry) error {
+	_, err := api.BatchModify(ops, func(batch []*fastly.BatchACLEntry) error {
+		return g.APIClient.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     aclID,
+			Entries:   batch,
+			ServiceID: serviceID,
+		})
+	})
+	return err
+}
+
+// createEntryOps returns the operations that add the entries to an ACL.
+func createEntryOps(entries []ipListEntry, source string) []*fastly.BatchACLEntry {
+	comment := ipListComment(source)
+	ops := make([]*fastly.BatchACLEntry, 0, len(entries))
+	for _, e := range entries {
+		ops = append(ops, &fastly.BatchACLEntry{
+			Comment:   fastly.String(comment),
+			IP:        fastly.String(e.IP),
+			Operation: fastly.CreateBatchOperation,
+			Subnet:    e.Subnet,
+		})
+	}
+	return ops
+}
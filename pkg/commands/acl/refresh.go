@@ -0,0 +1,216 @@
+package acl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewRefreshCommand returns a usable command registered under the parent.
+func NewRefreshCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *RefreshCommand {
+	c := RefreshCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+
+	c.CmdClause = parent.Command("refresh", "Update the entries of an ACL populated via --from-ip-list to match the current IP list")
+
+	// required
+	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
+
+	// optional
+	c.CmdClause.Flag("dry-run", "Report the entries that would be added and removed, without modifying the ACL").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("from-ip-list", fmt.Sprintf("The IP list to refresh the ACL from, defaults to the list the ACL was populated from: %s", ipListSources)).StringVar(&c.fromIPList)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// RefreshCommand calls the Fastly API to synchronise the entries of an ACL with
+// an IP list.
+type RefreshCommand struct {
+	cmd.Base
+
+	aclID       string
+	dryRun      bool
+	fromIPList  string
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+}
+
+// Exec invokes the application logic for the command.
+//
+// NOTE: Only the entries populated from an IP list (identified by their
+// comment) are removed, so entries added manually are preserved.
+func (c *RefreshCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	existing, err := c.listEntries(serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	list, err := c.ipList(existing)
+	if err != nil {
+		return err
+	}
+	entries, err := fetchIPList(list, c.Globals)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	added, removed, ops := refreshOps(existing, entries, list)
+	if len(ops) == 0 {
+		text.Success(out, "ACL %s is up to date with the IP list '%s' (%d entries)", c.aclID, list, len(entries))
+		return nil
+	}
+
+	if c.dryRun {
+		for _, k := range added {
+			text.Output(out, "+ %s", k)
+		}
+		for _, k := range removed {
+			text.Output(out, "- %s", k)
+		}
+		text.Info(out, "Dry run: %d entries would be added and %d removed from ACL %s", len(added), len(removed), c.aclID)
+		return nil
+	}
+
+	if err := batchModifyEntries(c.Globals, serviceID, c.aclID, ops); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+	text.Success(out, "Refreshed ACL %s from the IP list '%s': %d entries added, %d removed", c.aclID, list, len(added), len(removed))
+	return nil
+}
+
+// listEntries returns every entry of the ACL.
+func (c *RefreshCommand) listEntries(serviceID string) ([]*fastly.ACLEntry, error) {
+	paginator := c.Globals.APIClient.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+		ACLID:     c.aclID,
+		ServiceID: serviceID,
+	})
+	var entries []*fastly.ACLEntry
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, data...)
+	}
+	return entries, nil
+}
+
+// ipList returns the --from-ip-list, or else the IP list the ACL's entries
+// were populated from.
+func (c *RefreshCommand) ipList(existing []*fastly.ACLEntry) (string, error) {
+	if c.fromIPList != "" {
+		return c.fromIPList, nil
+	}
+
+	seen := make(map[string]bool)
+	var lists []string
+	for _, e := range existing {
+		list, ok := strings.CutPrefix(e.Comment, ipListCommentPrefix)
+		if !ok || seen[list] {
+			continue
+		}
+		seen[list] = true
+		lists = append(lists, list)
+	}
+	switch len(lists) {
+	case 0:
+		return "", errors.RemediationError{
+			Inner:       fmt.Errorf("ACL %s has no entries populated from an IP list", c.aclID),
+			Remediation: fmt.Sprintf("Provide the IP list to populate the ACL from with --from-ip-list: %s.", ipListSources),
+		}
+	case 1:
+		return lists[0], nil
+	}
+	sort.Strings(lists)
+	return "", errors.RemediationError{
+		Inner:       fmt.Errorf("ACL %s has entries populated from several IP lists: %s", c.aclID, strings.Join(lists, ", ")),
+		Remediation: "Provide the IP list to refresh the ACL from with --from-ip-list.",
+	}
+}
+
+// refreshOps returns the keys of the entries to add and remove, and the
+// operations that make the ACL match the IP list.
+//
+// An entry populated from an IP list that's no longer listed is removed, and
+// the comment of a listed entry populated from a different IP list is updated.
+func refreshOps(existing []*fastly.ACLEntry, entries []ipListEntry, list string) (added, removed []string, ops []*fastly.BatchACLEntry) {
+	comment := ipListComment(list)
+
+	want := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		want[e.key()] = true
+	}
+
+	have := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		key := entryKey(e.IP, e.Subnet)
+		have[key] = true
+		managed := strings.HasPrefix(e.Comment, ipListCommentPrefix)
+		switch {
+		case managed && !want[key]:
+			removed = append(removed, key)
+			ops = append(ops, &fastly.BatchACLEntry{
+				ID:        fastly.String(e.ID),
+				Operation: fastly.DeleteBatchOperation,
+			})
+		case managed && e.Comment != comment:
+			ops = append(ops, &fastly.BatchACLEntry{
+				Comment:   fastly.String(comment),
+				ID:        fastly.String(e.ID),
+				Operation: fastly.UpdateBatchOperation,
+			})
+		}
+	}
+
+	var missing []ipListEntry
+	for _, e := range entries {
+		if !have[e.key()] {
+			added = append(added, e.key())
+			missing = append(missing, e)
+		}
+	}
+	ops = append(ops, createEntryOps(missing, list)...)
+	sort.Strings(removed)
+	return added, removed, ops
+}
@@ -250,6 +250,99 @@ func (p *mockACLPaginator) GetNext() (as []*fastly.ACLEntry, err error) {
 	return as, err
 }
 
+func TestACLEntryImport(t *testing.T) {
+	var batches [][]*fastly.BatchACLEntry
+	paginator := func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+		return &mockACLPaginator{maxPages: 2}
+	}
+	batchModify := func(i *fastly.BatchModifyACLEntriesInput) error {
+		batches = append(batches, i.Entries)
+		return nil
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --file flag",
+			Args:      args("acl-entry import --acl-id 123"),
+			WantError: "error parsing arguments: required flag --file not provided",
+		},
+		{
+			Name:      "validate invalid address",
+			Args:      args("acl-entry import --acl-id 123 --file testdata/invalid.txt --service-id 123"),
+			WantError: "line 2: invalid IP address: not-an-ip",
+		},
+		{
+			Name: "validate ListACLEntries API error",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{returnErr: true}
+				},
+			},
+			Args:      args("acl-entry import --acl-id 123 --file testdata/blocklist.txt --service-id 123"),
+			WantError: testutil.Err.Error(),
+		},
+		{
+			Name: "validate import of a plain list",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+				BatchModifyACLEntriesFn:      batchModify,
+			},
+			Args:       args("acl-entry import --acl-id 123 --file testdata/blocklist.txt --service-id 123"),
+			WantOutput: "Imported 4 ACL entries (service: 123)\nCreated: 2\nUpdated: 1\nDeleted: 0\nUnchanged: 1\n",
+		},
+		{
+			Name: "validate --replace",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+				BatchModifyACLEntriesFn:      batchModify,
+			},
+			Args:       args("acl-entry import --acl-id 123 --file testdata/replace.txt --replace --service-id 123"),
+			WantOutput: "Imported 1 ACL entries (service: 123)\nCreated: 1\nUpdated: 0\nDeleted: 2\nUnchanged: 0\n",
+		},
+		{
+			Name: "validate import of a CSV file",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+				BatchModifyACLEntriesFn:      batchModify,
+			},
+			Args:       args("acl-entry import --acl-id 123 --file testdata/entries.csv --service-id 123"),
+			WantOutput: "Imported 2 ACL entries (service: 123)\nCreated: 2\nUpdated: 0\nDeleted: 0\nUnchanged: 0\n",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			batches = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+
+	// The CSV columns are mapped onto the batch operations.
+	testutil.AssertEqual(t, 1, len(batches))
+	testutil.AssertEqual(t, []*fastly.BatchACLEntry{
+		{
+			Comment:   fastly.String("corp"),
+			IP:        fastly.String("10.0.0.0"),
+			Negated:   fastly.CBool(false),
+			Operation: fastly.CreateBatchOperation,
+			Subnet:    fastly.Int(8),
+		},
+		{
+			Comment:   fastly.String(""),
+			IP:        fastly.String("192.168.0.1"),
+			Negated:   fastly.CBool(true),
+			Operation: fastly.CreateBatchOperation,
+		},
+	}, batches[0])
+}
+
 func TestACLEntryList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -319,6 +412,47 @@ func TestACLEntryList(t *testing.T) {
 			Args:       args("acl-entry list --acl-id 123 --per-page 1 --service-id 123 --verbose"),
 			WantOutput: listACLEntriesOutputVerbose,
 		},
+		{
+			Name:      "validate --all with --page",
+			Args:      args("acl-entry list --acl-id 123 --all --page 2 --service-id 123"),
+			WantError: "--all and --page can't be used together",
+		},
+		{
+			Name:      "validate --json with --ndjson",
+			Args:      args("acl-entry list --acl-id 123 --json --ndjson --service-id 123"),
+			WantError: "--json and --ndjson can't be used together",
+		},
+		{
+			Name: "validate --all",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{numOfPages: i.PerPage, maxPages: 2}
+				},
+			},
+			Args:       args("acl-entry list --acl-id 123 --all --service-id 123"),
+			WantOutput: listACLEntriesOutput,
+		},
+		{
+			Name: "validate --all --ndjson",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{numOfPages: i.PerPage, maxPages: 2}
+				},
+			},
+			Args: args("acl-entry list --acl-id 123 --all --ndjson --service-id 123"),
+			WantOutput: `{"ACLID":"123","Comment":"foo","CreatedAt":"2021-06-15T23:00:00Z","DeletedAt":"2021-06-15T23:00:00Z","ID":"456","IP":"127.0.0.1","Negated":false,"ServiceID":"123","Subnet":null,"UpdatedAt":"2021-06-15T23:00:00Z"}
+{"ACLID":"123","Comment":"bar",`,
+		},
+		{
+			Name: "validate --all --json",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{numOfPages: i.PerPage, maxPages: 2}
+				},
+			},
+			Args:       args("acl-entry list --acl-id 123 --all --json --service-id 123"),
+			WantOutput: `"UpdatedAt":"2021-06-15T23:00:00Z"},{"ACLID":"123","Comment":"bar",`,
+		},
 	}
 
 	for testcaseIdx := range scenarios {
@@ -376,6 +510,181 @@ Deleted at: 2021-06-15 23:00:00 +0000 UTC
 
 `
 
+func TestACLEntryCount(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --acl-id flag",
+			Args:      args("acl-entry count"),
+			WantError: "error parsing arguments: required flag --acl-id not provided",
+		},
+		{
+			Name: "validate ListACLEntries API error (via GetNext() call)",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{returnErr: true}
+				},
+			},
+			Args:      args("acl-entry count --acl-id 123 --service-id 123"),
+			WantError: testutil.Err.Error(),
+		},
+		{
+			Name: "validate summary",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{maxPages: 2}
+				},
+			},
+			Args:       args("acl-entry count --acl-id 123 --service-id 123"),
+			WantOutput: "Total: 2\nIPv4: 2\nIPv6: 0\nSubnets: 0\nNegated: 1\n",
+		},
+		{
+			Name: "validate --json",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+					return &mockACLPaginator{maxPages: 2}
+				},
+			},
+			Args:       args("acl-entry count --acl-id 123 --json --service-id 123"),
+			WantOutput: `{"total":2,"ipv4":2,"ipv6":0,"subnets":0,"negated":1}`,
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestACLEntryLookup(t *testing.T) {
+	subnet := func(n int) *int { return &n }
+	paginator := func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+		entries := map[string][]*fastly.ACLEntry{
+			"blocklist": {
+				{ID: "1", IP: "192.0.2.0", Subnet: subnet(24), Comment: "feed"},
+				{ID: "2", IP: "192.0.2.7", Negated: true, Comment: "partner"},
+				{ID: "3", IP: "198.51.100.1"},
+			},
+			"allowlist": {
+				{ID: "4", IP: "192.0.0.0", Subnet: subnet(16)},
+			},
+		}
+		return &entriesPaginator{entries: entries[i.ACLID]}
+	}
+	listACLs := func(i *fastly.ListACLsInput) ([]*fastly.ACL, error) {
+		return []*fastly.ACL{
+			{ID: "blocklist", Name: "blocked"},
+			{ID: "allowlist", Name: "allowed"},
+			{ID: "empty", Name: "empty"},
+		}, nil
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --ip flag",
+			Args:      args("acl-entry lookup --service-id 123"),
+			WantError: "error parsing arguments: required flag --ip not provided",
+		},
+		{
+			Name:      "validate invalid --ip",
+			Args:      args("acl-entry lookup --ip 192.0.2 --service-id 123"),
+			WantError: "invalid IP address: 192.0.2",
+		},
+		{
+			Name: "validate ListACLs API error",
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListACLsFn: func(i *fastly.ListACLsInput) ([]*fastly.ACL, error) {
+					return nil, testutil.Err
+				},
+			},
+			Args:      args("acl-entry lookup --ip 192.0.2.1 --service-id 123"),
+			WantError: testutil.Err.Error(),
+		},
+		{
+			Name: "validate matches across every ACL",
+			API: mock.API{
+				ListVersionsFn:               testutil.ListVersions,
+				ListACLsFn:                   listACLs,
+				NewListACLEntriesPaginatorFn: paginator,
+			},
+			Args: args("acl-entry lookup --ip 192.0.2.1 --service-id 123"),
+			WantOutputs: []string{
+				"blocklist  blocked   1         192.0.2.0  24      false    feed",
+				"allowlist  allowed   4         192.0.0.0  16      false",
+				"ACL blocklist matches 192.0.2.1 (entry 1: 192.0.2.0/24)",
+				"ACL allowlist matches 192.0.2.1 (entry 4: 192.0.0.0/16)",
+			},
+		},
+		{
+			Name: "validate negated entry",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+			},
+			Args:       args("acl-entry lookup --acl-id blocklist --ip 192.0.2.7 --service-id 123"),
+			WantOutput: "ACL blocklist doesn't match 192.0.2.7 (negated by entry 2: 192.0.2.7)",
+		},
+		{
+			Name: "validate no matches",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+			},
+			Args:       args("acl-entry lookup --acl-id blocklist --ip 203.0.113.1 --service-id 123"),
+			WantOutput: "No ACL entries match 203.0.113.1 (searched 1 ACLs)",
+		},
+		{
+			Name: "validate --json",
+			API: mock.API{
+				NewListACLEntriesPaginatorFn: paginator,
+			},
+			Args:       args("acl-entry lookup --acl-id blocklist --ip 198.51.100.1 --json --service-id 123"),
+			WantOutput: `[{"acl_id":"blocklist","entries":[{"ACLID":"","Comment":"","CreatedAt":null,"DeletedAt":null,"ID":"3","IP":"198.51.100.1","Negated":false,"ServiceID":"","Subnet":null,"UpdatedAt":null}],"matched":true}]`,
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// entriesPaginator returns a single page of entries.
+type entriesPaginator struct {
+	entries []*fastly.ACLEntry
+	done    bool
+}
+
+func (p *entriesPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p *entriesPaginator) Remaining() int {
+	return 0
+}
+
+func (p *entriesPaginator) GetNext() ([]*fastly.ACLEntry, error) {
+	p.done = true
+	return p.entries, nil
+}
+
 func TestACLEntryUpdate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -448,6 +757,42 @@ func TestACLEntryUpdate(t *testing.T) {
 			Args:       args(`acl-entry update --acl-id 123 --file {"entries":[{"op":"create","ip":"127.0.0.1","subnet":8},{"op":"update"},{"op":"upsert"}]} --id 456 --service-id 123`),
 			WantOutput: "Updated 3 ACL entries (service: 123)",
 		},
+		{
+			Name:      "validate --file and --batch-file can't be used together",
+			Args:      args("acl-entry update --acl-id 123 --batch-file testdata/batch.json --file testdata/batch.json --service-id 123"),
+			WantError: "--file and --batch-file can't be used together",
+		},
+		{
+			Name:      "validate error from --batch-file with an invalid operation",
+			Args:      args(`acl-entry update --acl-id 123 --batch-file {"entries":[{"op":"create"},{"op":"replace"}]} --service-id 123`),
+			WantError: "invalid operation 'replace' for entry 2",
+		},
+		{
+			Name: "validate success with --batch-file",
+			API: mock.API{
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					return nil
+				},
+			},
+			Args: args("acl-entry update --acl-id 123 --batch-file testdata/batch.json --service-id 123"),
+			WantOutputs: []string{
+				"create                          192.168.0.1  8       applied",
+				"update  6yxNzlOpW1V7JfSwvLGtOc  192.168.0.2  16      applied",
+				"delete  6yxNzlOpW1V7JfSwvLGtOc                       applied",
+				"Updated 3 ACL entries (service: 123)",
+			},
+		},
+		{
+			Name: "validate --batch-file results as --json when the API errors",
+			API: mock.API{
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					return testutil.Err
+				},
+			},
+			Args:       args(`acl-entry update --acl-id 123 --batch-file {"entries":[{"op":"delete","id":"456"}]} --json --service-id 123`),
+			WantError:  testutil.Err.Error(),
+			WantOutput: `[{"op":"delete","id":"456","status":"failed","error":"test error"}]`,
+		},
 	}
 
 	for testcaseIdx := range scenarios {
@@ -459,6 +804,9 @@ func TestACLEntryUpdate(t *testing.T) {
 			err := app.Run(opts)
 			testutil.AssertErrorContains(t, err, testcase.WantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
 		})
 	}
 }
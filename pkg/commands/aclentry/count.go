@@ -0,0 +1,140 @@
+package aclentry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewCountCommand returns a usable command registered under the parent.
+func NewCountCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CountCommand {
+	c := CountCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("count", "Summarise the entries of an ACL (fetching every page)")
+
+	// required
+	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
+
+	// optional
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: cmd.FlagJSONDesc,
+		Dst:         &c.json,
+		Short:       'j',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// CountCommand calls the Fastly API to summarise the entries of an ACL.
+type CountCommand struct {
+	cmd.Base
+
+	aclID       string
+	json        bool
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+}
+
+// Summary is the summary of an ACL's entries.
+type Summary struct {
+	Total   int `json:"total"`
+	IPv4    int `json:"ipv4"`
+	IPv6    int `json:"ipv6"`
+	Subnets int `json:"subnets"`
+	Negated int `json:"negated"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *CountCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	paginator := c.Globals.APIClient.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+		ACLID:     c.aclID,
+		ServiceID: serviceID,
+	})
+
+	var s Summary
+	err = eachPage(paginator, func(page []*fastly.ACLEntry) error {
+		for _, a := range page {
+			s.add(a)
+		}
+		return nil
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	if c.json {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(out, "Total: %d\n", s.Total)
+	fmt.Fprintf(out, "IPv4: %d\n", s.IPv4)
+	fmt.Fprintf(out, "IPv6: %d\n", s.IPv6)
+	fmt.Fprintf(out, "Subnets: %d\n", s.Subnets)
+	fmt.Fprintf(out, "Negated: %d\n", s.Negated)
+	return nil
+}
+
+// add counts the entry.
+func (s *Summary) add(a *fastly.ACLEntry) {
+	s.Total++
+	if ip := net.ParseIP(a.IP); ip != nil && ip.To4() == nil {
+		s.IPv6++
+	} else {
+		s.IPv4++
+	}
+	if a.Subnet != nil {
+		s.Subnets++
+	}
+	if a.Negated {
+		s.Negated++
+	}
+}
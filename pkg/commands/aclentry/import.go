@@ -0,0 +1,367 @@
+package aclentry
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The formats of an import file.
+const (
+	formatCSV   = "csv"
+	formatPlain = "plain"
+)
+
+// formats are the values of the --format flag.
+var formats = []string{formatCSV, formatPlain}
+
+// csvColumns are the columns of a CSV file of entries, in the order assumed
+// when the file has no header row.
+var csvColumns = []string{"ip", "subnet", "comment", "negated"}
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ImportCommand {
+	c := ImportCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("import", "Import ACL entries from a list of IPs and CIDR blocks, or a CSV file")
+
+	// required
+	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
+	c.CmdClause.Flag("file", "Path to a file of entries: an IP or CIDR block per line (prefix with ! to negate, comments after # or ;), or CSV with ip,subnet,comment,negated columns").Required().StringVar(&c.file)
+
+	// optional
+	c.CmdClause.Flag("format", "The format of the file (defaults to csv for a .csv file, or else plain)").HintOptions(formats...).EnumVar(&c.format, formats...)
+	c.CmdClause.Flag("replace", "Delete the entries of the ACL that aren't in the file").BoolVar(&c.replace)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// ImportCommand calls the Fastly API to load the entries of a file into an ACL.
+type ImportCommand struct {
+	cmd.Base
+
+	aclID       string
+	file        string
+	format      string
+	manifest    manifest.Data
+	replace     bool
+	serviceName cmd.OptionalServiceNameID
+}
+
+// entry is an ACL entry read from an import file.
+type entry struct {
+	ip      string
+	subnet  *int
+	comment string
+	negated bool
+}
+
+// key identifies the entry by its address and subnet mask.
+func (e entry) key() string {
+	return entryKey(e.ip, e.subnet)
+}
+
+// Exec invokes the application logic for the command.
+func (c *ImportCommand) Exec(_ io.Reader, out io.Writer) error {
+	entries, err := readEntries(c.file, c.format)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"File": c.file,
+		})
+		return err
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	paginator := c.Globals.APIClient.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+		ACLID:     c.aclID,
+		ServiceID: serviceID,
+	})
+	var remote []*fastly.ACLEntry
+	err = eachPage(paginator, func(page []*fastly.ACLEntry) error {
+		remote = append(remote, page...)
+		return nil
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	ops := diffEntries(entries, remote, c.replace)
+	if _, err := batchModify(c.Globals.APIClient, serviceID, c.aclID, ops); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	counts := make(map[fastly.BatchOperation]int)
+	for _, op := range ops {
+		counts[op.Operation]++
+	}
+	unchanged := len(entries) - counts[fastly.CreateBatchOperation] - counts[fastly.UpdateBatchOperation]
+	text.Success(out, "Imported %d ACL entries (service: %s)", len(entries), serviceID)
+	fmt.Fprintf(out, "Created: %d\n", counts[fastly.CreateBatchOperation])
+	fmt.Fprintf(out, "Updated: %d\n", counts[fastly.UpdateBatchOperation])
+	fmt.Fprintf(out, "Deleted: %d\n", counts[fastly.DeleteBatchOperation])
+	fmt.Fprintf(out, "Unchanged: %d\n", unchanged)
+	return nil
+}
+
+// diffEntries returns the batch operations that bring the remote entries in
+// line with the local ones. Entries with the same address and subnet mask are
+// updated rather than recreated, so unchanged entries aren't touched.
+func diffEntries(local []entry, remote []*fastly.ACLEntry, replace bool) []*fastly.BatchACLEntry {
+	existing := make(map[string]*fastly.ACLEntry, len(remote))
+	for _, a := range remote {
+		existing[entryKey(a.IP, a.Subnet)] = a
+	}
+
+	var ops []*fastly.BatchACLEntry
+	wanted := make(map[string]bool, len(local))
+	for _, e := range local {
+		wanted[e.key()] = true
+		a, ok := existing[e.key()]
+		switch {
+		case !ok:
+			ops = append(ops, &fastly.BatchACLEntry{
+				Comment:   fastly.String(e.comment),
+				IP:        fastly.String(e.ip),
+				Negated:   fastly.CBool(e.negated),
+				Operation: fastly.CreateBatchOperation,
+				Subnet:    e.subnet,
+			})
+		case a.Comment != e.comment || a.Negated != e.negated:
+			ops = append(ops, &fastly.BatchACLEntry{
+				Comment:   fastly.String(e.comment),
+				ID:        fastly.String(a.ID),
+				Negated:   fastly.CBool(e.negated),
+				Operation: fastly.UpdateBatchOperation,
+			})
+		}
+	}
+
+	if replace {
+		for _, a := range remote {
+			if !wanted[entryKey(a.IP, a.Subnet)] {
+				ops = append(ops, &fastly.BatchACLEntry{
+					ID:        fastly.String(a.ID),
+					Operation: fastly.DeleteBatchOperation,
+				})
+			}
+		}
+	}
+	return ops
+}
+
+// batchModify sends the batch operations to the ACL, returning the number of
+// operations applied before any error (see api.BatchModify).
+func batchModify(client api.Interface, serviceID, aclID string, ops []*fastly.BatchACLEntry) (int, error) {
+	return api.BatchModify(ops, func(batch []*fastly.BatchACLEntry) error {
+		return client.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     aclID,
+			Entries:   batch,
+			ServiceID: serviceID,
+		})
+	})
+}
+
+// entryKey identifies an entry by its (normalised) address and subnet mask.
+func entryKey(ip string, subnet *int) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+	if subnet == nil {
+		return ip
+	}
+	return fmt.Sprintf("%s/%d", ip, *subnet)
+}
+
+// readEntries reads the entries of an import file, which is either a plain
+// list of addresses or a CSV file. Duplicate entries are ignored.
+func readEntries(file, format string) ([]entry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = formatPlain
+		if strings.EqualFold(filepath.Ext(file), "."+formatCSV) {
+			format = formatCSV
+		}
+	}
+
+	var entries []entry
+	if format == formatCSV {
+		entries, err = readCSV(f)
+	} else {
+		entries, err = readPlain(f)
+	}
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing %s: %w", file, err),
+			Remediation: "Provide an IP address or CIDR block per line, or CSV with ip,subnet,comment,negated columns.",
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries found in %s", file)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	unique := entries[:0]
+	for _, e := range entries {
+		if !seen[e.key()] {
+			seen[e.key()] = true
+			unique = append(unique, e)
+		}
+	}
+	return unique, nil
+}
+
+// readPlain reads a list of addresses, one per line. Text after a # or ; is
+// used as the entry's comment, and a leading ! negates the entry.
+func readPlain(r io.Reader) ([]entry, error) {
+	var entries []entry
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		var comment string
+		if i := strings.IndexAny(line, "#;"); i >= 0 {
+			comment = strings.TrimSpace(line[i+1:])
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		negated := strings.HasPrefix(line, "!")
+		e, err := parseAddress(strings.TrimSpace(strings.TrimPrefix(line, "!")))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		e.comment = comment
+		e.negated = negated
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// readCSV reads CSV rows of entries. The columns are named by an optional
+// header row, and otherwise are ip,subnet,comment,negated.
+func readCSV(r io.Reader) ([]entry, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := csvColumns
+	if _, err := parseAddress(rows[0][0]); err != nil {
+		columns = rows[0]
+		rows = rows[1:]
+	}
+	index := make(map[string]int)
+	for i, name := range columns {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["ip"]; !ok {
+		return nil, errors.New("missing ip column")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := index[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	entries := make([]entry, 0, len(rows))
+	for n, row := range rows {
+		e, err := parseAddress(field(row, "ip"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n+1, err)
+		}
+		if s := field(row, "subnet"); s != "" {
+			if e.subnet != nil {
+				return nil, fmt.Errorf("row %d: a subnet is set on both the ip and subnet columns", n+1)
+			}
+			subnet, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid subnet: %s", n+1, s)
+			}
+			e.subnet = &subnet
+		}
+		if s := field(row, "negated"); s != "" {
+			e.negated, err = strconv.ParseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid negated value: %s", n+1, s)
+			}
+		}
+		e.comment = field(row, "comment")
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseAddress parses an IP address or CIDR block.
+func parseAddress(s string) (entry, error) {
+	if ip, subnet, ok := strings.Cut(s, "/"); ok {
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return entry{}, fmt.Errorf("invalid CIDR block: %s", s)
+		}
+		bits, _ := strconv.Atoi(subnet)
+		return entry{ip: ip, subnet: &bits}, nil
+	}
+	if net.ParseIP(s) == nil {
+		return entry{}, fmt.Errorf("invalid IP address: %s", s)
+	}
+	return entry{ip: s}, nil
+}
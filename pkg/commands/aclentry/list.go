@@ -2,6 +2,7 @@ package aclentry
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
@@ -27,6 +28,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
 
 	// optional
+	c.CmdClause.Flag("all", "Fetch every page of entries, writing each page as it's received (use with --ndjson to export very large ACLs)").BoolVar(&c.all)
 	c.RegisterFlagBool(cmd.BoolFlagOpts{
 		Name:        cmd.FlagJSONName,
 		Description: cmd.FlagJSONDesc,
@@ -47,6 +49,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 	})
 
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(cmd.PaginationDirection[0]).HintOptions(cmd.PaginationDirection...).EnumVar(&c.direction, cmd.PaginationDirection...)
+	c.CmdClause.Flag("ndjson", "Render each entry as a line of JSON (newline delimited JSON)").BoolVar(&c.ndjson)
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.perPage)
 	c.CmdClause.Flag("sort", "Field on which to sort").Default("created").StringVar(&c.sort)
@@ -59,9 +62,11 @@ type ListCommand struct {
 	cmd.Base
 
 	aclID       string
+	all         bool
 	direction   string
 	json        bool
 	manifest    manifest.Data
+	ndjson      bool
 	page        int
 	perPage     int
 	serviceName cmd.OptionalServiceNameID
@@ -70,9 +75,21 @@ type ListCommand struct {
 
 // Exec invokes the application logic for the command.
 func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
-	if c.Globals.Verbose() && c.json {
+	if c.Globals.Verbose() && (c.json || c.ndjson) {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
+	if c.json && c.ndjson {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--json and --ndjson can't be used together"),
+			Remediation: "Use --json for a single JSON array, or --ndjson for a line of JSON per entry.",
+		}
+	}
+	if c.all && c.page > 0 {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--all and --page can't be used together"),
+			Remediation: "Use --all to fetch every page, or --page to fetch a single page.",
+		}
+	}
 
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
@@ -85,6 +102,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	input := c.constructInput(serviceID)
 	paginator := c.Globals.APIClient.NewListACLEntriesPaginator(input)
 
+	if c.all {
+		return c.streamAll(paginator, serviceID, out)
+	}
+
 	// TODO: Use generics support in go 1.18 to replace this almost identical
 	// logic inside of 'dictionary-item list' and 'service list'.
 	var as []*fastly.ACLEntry
@@ -112,6 +133,94 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// streamAll writes each page of entries as it's received, so very large ACLs
+// can be exported without holding every entry in memory.
+//
+// NOTE: The table format is only written once every page is received, as the
+// columns are aligned across all entries.
+func (c *ListCommand) streamAll(paginator fastly.PaginatorACLEntries, serviceID string, out io.Writer) error {
+	var (
+		as    []*fastly.ACLEntry
+		total int
+	)
+	if c.json {
+		fmt.Fprint(out, "[")
+	}
+	err := eachPage(paginator, func(page []*fastly.ACLEntry) error {
+		switch {
+		case c.json:
+			for _, a := range page {
+				data, err := json.Marshal(a)
+				if err != nil {
+					return err
+				}
+				if total > 0 {
+					fmt.Fprint(out, ",")
+				}
+				total++
+				if _, err := out.Write(data); err != nil {
+					return fmt.Errorf("error: unable to write data to stdout: %w", err)
+				}
+			}
+		case c.ndjson:
+			return writeNDJSON(out, page)
+		case c.Globals.Verbose():
+			c.printVerbose(out, page)
+		default:
+			as = append(as, page...)
+		}
+		return nil
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+	if c.json {
+		fmt.Fprint(out, "]")
+	}
+	if !c.json && !c.ndjson && !c.Globals.Verbose() {
+		return c.printSummary(out, as)
+	}
+	return nil
+}
+
+// eachPage calls fn with each page of entries.
+//
+// NOTE: The paginator stops at the first empty page, as the API's pagination
+// links can't be relied upon to end the iteration for very large ACLs.
+func eachPage(paginator fastly.PaginatorACLEntries, fn func([]*fastly.ACLEntry) error) error {
+	for paginator.HasNext() {
+		page, err := paginator.GetNext()
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if paginator.Remaining() < 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeNDJSON writes each entry as a line of JSON.
+func writeNDJSON(out io.Writer, as []*fastly.ACLEntry) error {
+	enc := json.NewEncoder(out)
+	for _, a := range as {
+		if err := enc.Encode(a); err != nil {
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+	}
+	return nil
+}
+
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *ListCommand) constructInput(serviceID string) *fastly.ListACLEntriesInput {
 	var input fastly.ListACLEntriesInput
@@ -154,6 +263,9 @@ func (c *ListCommand) printVerbose(out io.Writer, as []*fastly.ACLEntry) {
 // printSummary displays the information returned from the API in a summarised
 // format.
 func (c *ListCommand) printSummary(out io.Writer, as []*fastly.ACLEntry) error {
+	if c.ndjson {
+		return writeNDJSON(out, as)
+	}
 	if c.json {
 		data, err := json.Marshal(as)
 		if err != nil {
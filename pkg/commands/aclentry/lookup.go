@@ -0,0 +1,248 @@
+package aclentry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewLookupCommand returns a usable command registered under the parent.
+func NewLookupCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *LookupCommand {
+	c := LookupCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("lookup", "Find the ACL entries (including subnets) that match an IP address")
+
+	// required
+	c.CmdClause.Flag("ip", "The IP address to look up").Required().StringVar(&c.ip)
+
+	// optional
+	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL (defaults to every ACL of the service version)").Action(c.aclID.Set).StringVar(&c.aclID.Value)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: cmd.FlagJSONDesc,
+		Dst:         &c.json,
+		Short:       'j',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceVersion.Set,
+		Name:        cmd.FlagVersionName,
+		Description: "The service version whose ACLs are searched when --acl-id isn't set ('latest', 'active', or the number of a specific version; defaults to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+
+	return &c
+}
+
+// LookupCommand calls the Fastly API to find the ACL entries matching an IP.
+type LookupCommand struct {
+	cmd.Base
+
+	aclID          cmd.OptionalString
+	ip             string
+	json           bool
+	manifest       manifest.Data
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// LookupResult is the entries of an ACL that match the IP address.
+type LookupResult struct {
+	ACLID   string             `json:"acl_id"`
+	ACLName string             `json:"acl_name,omitempty"`
+	Entries []*fastly.ACLEntry `json:"entries"`
+	// Matched reports whether the ACL matches the address, which is decided by
+	// the most specific entry (an address doesn't match when that entry is
+	// negated).
+	Matched bool `json:"matched"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *LookupCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	addr := net.ParseIP(c.ip)
+	if addr == nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid IP address: %s", c.ip),
+			Remediation: "Provide an IPv4 or IPv6 address, e.g. --ip 192.0.2.1",
+		}
+	}
+
+	serviceID, acls, err := c.acls(out)
+	if err != nil {
+		return err
+	}
+
+	var results []LookupResult
+	for _, a := range acls {
+		paginator := c.Globals.APIClient.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+			ACLID:     a.ID,
+			ServiceID: serviceID,
+		})
+		r := LookupResult{ACLID: a.ID, ACLName: a.Name}
+		err := eachPage(paginator, func(page []*fastly.ACLEntry) error {
+			for _, e := range page {
+				if entryMatches(e, addr) {
+					r.Entries = append(r.Entries, e)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"ACL ID":     a.ID,
+				"Service ID": serviceID,
+			})
+			return err
+		}
+		if len(r.Entries) > 0 {
+			r.Matched = !mostSpecific(r.Entries).Negated
+			results = append(results, r)
+		}
+	}
+
+	if c.json {
+		if results == nil {
+			results = []LookupResult{}
+		}
+		data, err := json.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+		return nil
+	}
+
+	if len(results) == 0 {
+		text.Info(out, "No ACL entries match %s (searched %d ACLs)", c.ip, len(acls))
+		return nil
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("ACL ID", "ACL NAME", "ENTRY ID", "IP", "SUBNET", "NEGATED", "COMMENT")
+	for _, r := range results {
+		for _, e := range r.Entries {
+			var subnet string
+			if e.Subnet != nil {
+				subnet = strconv.Itoa(*e.Subnet)
+			}
+			t.AddLine(r.ACLID, r.ACLName, e.ID, e.IP, subnet, e.Negated, e.Comment)
+		}
+	}
+	t.Print()
+
+	text.Break(out)
+	for _, r := range results {
+		e := mostSpecific(r.Entries)
+		if r.Matched {
+			fmt.Fprintf(out, "ACL %s matches %s (entry %s: %s)\n", r.ACLID, c.ip, e.ID, entryKey(e.IP, e.Subnet))
+		} else {
+			fmt.Fprintf(out, "ACL %s doesn't match %s (negated by entry %s: %s)\n", r.ACLID, c.ip, e.ID, entryKey(e.IP, e.Subnet))
+		}
+	}
+	return nil
+}
+
+// acls returns the service ID and the ACLs to search, which is either the ACL
+// set by --acl-id or every ACL of the service version.
+func (c *LookupCommand) acls(out io.Writer) (string, []*fastly.ACL, error) {
+	if c.aclID.WasSet {
+		serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return "", nil, err
+		}
+		if c.Globals.Verbose() {
+			cmd.DisplayServiceID(serviceID, flag, source, out)
+		}
+		return serviceID, []*fastly.ACL{{ID: c.aclID.Value}}, nil
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return "", nil, err
+	}
+
+	acls, err := c.Globals.APIClient.ListACLs(&fastly.ListACLsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return "", nil, err
+	}
+	return serviceID, acls, nil
+}
+
+// entryMatches reports whether the entry's address or subnet contains addr.
+func entryMatches(e *fastly.ACLEntry, addr net.IP) bool {
+	if e.Subnet == nil {
+		ip := net.ParseIP(e.IP)
+		return ip != nil && ip.Equal(addr)
+	}
+	_, network, err := net.ParseCIDR(entryKey(e.IP, e.Subnet))
+	return err == nil && network.Contains(addr)
+}
+
+// mostSpecific returns the matching entry with the longest prefix, which is
+// the one that decides whether the ACL matches.
+func mostSpecific(entries []*fastly.ACLEntry) *fastly.ACLEntry {
+	best, bestBits := entries[0], -1
+	for _, e := range entries {
+		bits := 128
+		if e.Subnet != nil {
+			bits = *e.Subnet
+		} else if ip := net.ParseIP(e.IP); ip != nil && ip.To4() != nil {
+			bits = 32
+		}
+		if bits > bestBits {
+			best, bestBits = e, bits
+		}
+	}
+	return best
+}
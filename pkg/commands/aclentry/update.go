@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/errors"
@@ -27,10 +28,17 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
 
 	// optional
+	c.CmdClause.Flag("batch-file", "JSON of create, update, upsert and delete operations, passed as file path or content, e.g. $(< batch.json)").Action(c.batchFile.Set).StringVar(&c.batchFile.Value)
 	c.CmdClause.Flag("comment", "A freeform descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
-	c.CmdClause.Flag("file", "Batch update json passed as file path or content, e.g. $(< batch.json)").Action(c.file.Set).StringVar(&c.file.Value)
+	c.CmdClause.Flag("file", "Deprecated alias of --batch-file").Hidden().Action(c.file.Set).StringVar(&c.file.Value)
 	c.CmdClause.Flag("id", "Alphanumeric string identifying an ACL Entry").Action(c.id.Set).StringVar(&c.id.Value)
 	c.CmdClause.Flag("ip", "An IP address").Action(c.ip.Set).StringVar(&c.ip.Value)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: "Render the results of --batch-file operations as JSON",
+		Dst:         &c.json,
+		Short:       'j',
+	})
 	c.CmdClause.Flag("negated", "Whether to negate the match").Action(c.negated.Set).BoolVar(&c.negated.Value)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
@@ -54,18 +62,55 @@ type UpdateCommand struct {
 	cmd.Base
 
 	aclID       string
+	batchFile   cmd.OptionalString
 	comment     cmd.OptionalString
 	file        cmd.OptionalString
 	id          cmd.OptionalString
 	ip          cmd.OptionalString
+	json        bool
 	manifest    manifest.Data
 	negated     cmd.OptionalBool
 	serviceName cmd.OptionalServiceNameID
 	subnet      cmd.OptionalInt
 }
 
+// The statuses of a batch operation.
+const (
+	batchApplied = "applied"
+	batchFailed  = "failed"
+	batchSkipped = "skipped"
+)
+
+// BatchResult is the outcome of a --batch-file operation.
+//
+// NOTE: The API applies each request of up to fastly.BatchModifyMaximumOperations
+// operations atomically and doesn't report on individual operations, so every
+// operation of a failed request is reported as failed, and the operations of
+// later requests as skipped.
+type BatchResult struct {
+	Operation fastly.BatchOperation `json:"op"`
+	ID        string                `json:"id,omitempty"`
+	IP        string                `json:"ip,omitempty"`
+	Subnet    *int                  `json:"subnet,omitempty"`
+	Status    string                `json:"status"`
+	Error     string                `json:"error,omitempty"`
+}
+
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return errors.ErrInvalidVerboseJSONCombo
+	}
+	if c.file.WasSet {
+		if c.batchFile.WasSet {
+			return errors.RemediationError{
+				Inner:       fmt.Errorf("--file and --batch-file can't be used together"),
+				Remediation: "Use --batch-file (--file is a deprecated alias of it).",
+			}
+		}
+		c.batchFile = c.file
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -74,22 +119,8 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		cmd.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	if c.file.WasSet {
-		input, err := c.constructBatchInput(serviceID)
-		if err != nil {
-			return err
-		}
-
-		err = c.Globals.APIClient.BatchModifyACLEntries(input)
-		if err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Service ID": serviceID,
-			})
-			return err
-		}
-
-		text.Success(out, "Updated %d ACL entries (service: %s)", len(input.Entries), serviceID)
-		return nil
+	if c.batchFile.WasSet {
+		return c.batchModify(serviceID, out)
 	}
 
 	input, err := c.constructInput(serviceID)
@@ -109,6 +140,81 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// batchModify applies the operations of --batch-file and reports the outcome
+// of each one.
+func (c *UpdateCommand) batchModify(serviceID string, out io.Writer) error {
+	input, err := c.constructBatchInput(serviceID)
+	if err != nil {
+		return err
+	}
+
+	applied, err := batchModify(c.Globals.APIClient, serviceID, c.aclID, input.Entries)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+	}
+	results := batchResults(input.Entries, applied, err)
+
+	if c.json {
+		data, jsonErr := json.Marshal(results)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		if _, jsonErr := out.Write(data); jsonErr != nil {
+			c.Globals.ErrLog.Add(jsonErr)
+			return fmt.Errorf("error: unable to write data to stdout: %w", jsonErr)
+		}
+		return err
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("OP", "ID", "IP", "SUBNET", "STATUS")
+	for _, r := range results {
+		var subnet string
+		if r.Subnet != nil {
+			subnet = strconv.Itoa(*r.Subnet)
+		}
+		t.AddLine(r.Operation, r.ID, r.IP, subnet, r.Status)
+	}
+	t.Print()
+	text.Break(out)
+
+	if err != nil {
+		return err
+	}
+	text.Success(out, "Updated %d ACL entries (service: %s)", len(input.Entries), serviceID)
+	return nil
+}
+
+// batchResults returns the outcome of each operation, given the number of
+// operations applied before err.
+func batchResults(ops []*fastly.BatchACLEntry, applied int, err error) []BatchResult {
+	results := make([]BatchResult, len(ops))
+	for i, op := range ops {
+		r := BatchResult{
+			Operation: op.Operation,
+			Subnet:    op.Subnet,
+			Status:    batchApplied,
+		}
+		if op.ID != nil {
+			r.ID = *op.ID
+		}
+		if op.IP != nil {
+			r.IP = *op.IP
+		}
+		if err != nil && i >= applied {
+			r.Status = batchSkipped
+			if i < applied+fastly.BatchModifyMaximumOperations {
+				r.Status = batchFailed
+				r.Error = err.Error()
+			}
+		}
+		results[i] = r
+	}
+	return results
+}
+
 // constructBatchInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *UpdateCommand) constructBatchInput(serviceID string) (*fastly.BatchModifyACLEntriesInput, error) {
 	var input fastly.BatchModifyACLEntriesInput
@@ -116,7 +222,7 @@ func (c *UpdateCommand) constructBatchInput(serviceID string) (*fastly.BatchModi
 	input.ACLID = c.aclID
 	input.ServiceID = serviceID
 
-	s := cmd.Content(c.file.Value)
+	s := cmd.Content(c.batchFile.Value)
 	bs := []byte(s)
 
 	err := json.Unmarshal(bs, &input)
@@ -129,7 +235,7 @@ func (c *UpdateCommand) constructBatchInput(serviceID string) (*fastly.BatchModi
 
 	if len(input.Entries) == 0 {
 		err := errors.RemediationError{
-			Inner:       fmt.Errorf("missing 'entries' %s", c.file.Value),
+			Inner:       fmt.Errorf("missing 'entries' %s", c.batchFile.Value),
 			Remediation: "Consult the API documentation for the JSON format: https://developer.fastly.com/reference/api/acls/acl-entry/#bulk-update-acl-entries",
 		}
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -138,6 +244,17 @@ func (c *UpdateCommand) constructBatchInput(serviceID string) (*fastly.BatchModi
 		return nil, err
 	}
 
+	for i, e := range input.Entries {
+		switch e.Operation {
+		case fastly.CreateBatchOperation, fastly.UpdateBatchOperation, fastly.UpsertBatchOperation, fastly.DeleteBatchOperation:
+		default:
+			return nil, errors.RemediationError{
+				Inner:       fmt.Errorf("invalid operation '%s' for entry %d", e.Operation, i+1),
+				Remediation: "Set each entry's 'op' to one of create, update, upsert or delete.",
+			}
+		}
+	}
+
 	return &input, nil
 }
 
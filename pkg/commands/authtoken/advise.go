@@ -0,0 +1,395 @@
+package authtoken
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/kingpin"
+)
+
+// NewAdviseCommand returns a usable command registered under the parent.
+func NewAdviseCommand(parent cmd.Registerer, g *global.Data, app *kingpin.Application, m manifest.Data) *AdviseCommand {
+	c := AdviseCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		app:      app,
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("advise", "Display the minimal token scope and services needed to run a workflow of CLI commands")
+
+	// optional
+	c.CmdClause.Flag("command", "A CLI command the token is needed for, e.g. 'compute deploy -s abc' (repeat flag per command)").StringsVar(&c.commands)
+	c.CmdClause.Flag("file", "Path to a shell script (e.g. a CI job) or shell history whose `fastly` commands the token is needed for").StringVar(&c.file)
+	return &c
+}
+
+// AdviseCommand works out the least privileged token needed by a workflow.
+type AdviseCommand struct {
+	cmd.Base
+
+	app      *kingpin.Application
+	commands []string
+	file     string
+	manifest manifest.Data
+}
+
+// Exec invokes the application logic for the command.
+func (c *AdviseCommand) Exec(_ io.Reader, out io.Writer) error {
+	commands := c.commands
+	if c.file != "" {
+		fromFile, err := readWorkflowFile(c.file)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error reading --file: %w", err)
+		}
+		commands = append(commands, fromFile...)
+	}
+	if len(commands) == 0 {
+		err := fsterr.RemediationError{
+			Inner:       errors.New("no commands to advise on"),
+			Remediation: "Provide the workflow's commands via --command (e.g. --command 'compute deploy') or --file (e.g. --file ci.sh).",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	var advice []commandAdvice
+	for _, line := range commands {
+		args, err := splitArgs(line)
+		if err != nil {
+			return fmt.Errorf("error parsing command '%s': %w", line, err)
+		}
+		if len(args) > 0 && filepath.Base(args[0]) == "fastly" {
+			args = args[1:]
+		}
+		advice = append(advice, c.advise(args))
+	}
+
+	c.print(advice, out)
+	return nil
+}
+
+// Scopes that aren't needed by any command.
+const (
+	scopeNone    = "none"
+	scopeUnknown = "unknown"
+)
+
+// commandAdvice is the scope and service a single command needs.
+type commandAdvice struct {
+	command string
+	scope   string
+	// service is the ID (or name) of the service the command acts on. It's
+	// empty for account-level commands, which need access to all services.
+	service string
+	// serviceScoped means the command acts on a single service.
+	serviceScoped bool
+}
+
+// localCommands don't call the Fastly API, so they can be run with any token
+// (or none).
+var localCommands = []string{
+	"auth-token advise",
+	"compute build",
+	"compute hashsum",
+	"compute init",
+	"compute pack",
+	"compute serve",
+	"compute validate",
+	"object-store diff",
+	"profile",
+	"update",
+	"version",
+}
+
+// readCommands only read from the API, in addition to any 'list', 'describe',
+// 'search' and 'count' command.
+var readCommands = []string{
+	"ip-list",
+	"pops",
+	"stats",
+	"whoami",
+}
+
+// advise determines the scope and service needed by the command.
+func (c *AdviseCommand) advise(args []string) commandAdvice {
+	a := commandAdvice{command: strings.Join(args, " "), scope: scopeUnknown}
+
+	ctx, err := c.app.ParseContext(args)
+	if ctx == nil || ctx.SelectedCommand == nil {
+		return a
+	}
+	if err != nil && c.Globals.Verbose() {
+		text.Warning(c.Globals.Output, "error parsing '%s': %s", a.command, err)
+	}
+	name := ctx.SelectedCommand.FullCommand()
+	flags := ctx.Elements.FlagMap()
+
+	switch {
+	case hasCommandPrefix(name, localCommands):
+		a.scope = scopeNone
+		return a
+	case name == "purge":
+		a.scope = "purge_select"
+		if _, ok := flags["all"]; ok {
+			a.scope = "purge_all"
+		}
+	case hasCommandPrefix(name, readCommands), isReadVerb(name):
+		a.scope = "global:read"
+	default:
+		a.scope = "global"
+	}
+
+	if ctx.SelectedCommand.GetFlag(cmd.FlagServiceIDName) != nil {
+		a.serviceScoped = true
+		md := c.manifest
+		if f, ok := flags[cmd.FlagServiceIDName]; ok && f.Value != nil {
+			md.Flag.ServiceID = *f.Value
+		}
+		a.service, _ = md.ServiceID()
+		if f, ok := flags[cmd.FlagServiceName]; ok && f.Value != nil && a.service == "" {
+			a.service = "name:" + *f.Value
+		}
+	}
+	return a
+}
+
+// print displays the advice for each command and the token to create.
+func (c *AdviseCommand) print(advice []commandAdvice, out io.Writer) {
+	t := text.NewTable(out)
+	t.AddHeader("COMMAND", "SCOPE", "SERVICE")
+	for _, a := range advice {
+		service := "-"
+		switch {
+		case a.scope == scopeNone || a.scope == scopeUnknown:
+		case !a.serviceScoped:
+			service = "(all)"
+		case a.service == "":
+			service = "(unknown)"
+		default:
+			service = a.service
+		}
+		t.AddLine(a.command, a.scope, service)
+	}
+	t.Print()
+	text.Break(out)
+
+	needed := map[string]bool{}
+	services := map[string]bool{}
+	allServices := false
+	var unknown, accountLevel, unresolved []string
+	for _, a := range advice {
+		switch a.scope {
+		case scopeUnknown:
+			unknown = append(unknown, a.command)
+			continue
+		case scopeNone:
+			continue
+		}
+		needed[a.scope] = true
+
+		switch {
+		case !a.serviceScoped:
+			allServices = true
+			accountLevel = append(accountLevel, a.command)
+		case a.service == "" || strings.HasPrefix(a.service, "name:"):
+			allServices = true
+			unresolved = append(unresolved, a.command)
+		default:
+			services[a.service] = true
+		}
+	}
+
+	for _, command := range unknown {
+		text.Warning(out, "'%s' isn't a recognised command, so its scope is unknown.", command)
+	}
+	for _, command := range unresolved {
+		text.Warning(out, "The service ID used by '%s' is unknown. Set --service-id (or $FASTLY_SERVICE_ID) so the token can be restricted to it.", command)
+	}
+
+	// The 'global' scope provides read access.
+	if needed["global"] {
+		delete(needed, "global:read")
+	}
+	scopes := make([]string, 0, len(needed))
+	for s := range needed {
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+
+	if len(scopes) == 0 {
+		if len(unknown) == 0 {
+			text.Info(out, "The workflow doesn't call the Fastly API, so no token is needed.")
+		}
+		return
+	}
+
+	create := "fastly auth-token create --password <password> --name <name>"
+	for _, s := range scopes {
+		create += " --scope " + s
+	}
+	if !allServices {
+		ids := make([]string, 0, len(services))
+		for s := range services {
+			ids = append(ids, s)
+		}
+		sort.Strings(ids)
+		create += " --services " + strings.Join(ids, ",")
+	} else if len(accountLevel) > 0 {
+		text.Info(out, "The token needs access to all services because of the account-level commands: %s", strings.Join(accountLevel, ", "))
+	}
+
+	text.Output(out, "%s %s", text.Bold("Minimal scope:"), strings.Join(scopes, ", "))
+	text.Output(out, "%s", text.Bold("Create the token with:"))
+	// The command isn't wrapped so it can be copied.
+	fmt.Fprintf(out, "\n  %s\n", create)
+}
+
+// hasCommandPrefix reports whether the command is, or is a subcommand of, one
+// of the given commands.
+func hasCommandPrefix(name string, commands []string) bool {
+	for _, c := range commands {
+		if name == c || strings.HasPrefix(name, c+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// isReadVerb reports whether the command only reads from the API.
+func isReadVerb(name string) bool {
+	verb := name[strings.LastIndex(name, " ")+1:]
+	return verb == "list" || verb == "describe" || verb == "search" || verb == "count"
+}
+
+// readWorkflowFile returns the `fastly` commands run by the shell script (or
+// shell history) at path.
+func readWorkflowFile(path string) ([]string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var commands []string
+	var line string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line += scanner.Text()
+		if strings.HasSuffix(line, "\\") {
+			line = strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		args, err := splitArgs(line)
+		line = ""
+		if err != nil {
+			continue
+		}
+		commands = append(commands, fastlyCommands(args)...)
+	}
+	return commands, scanner.Err()
+}
+
+// fastlyCommands returns the `fastly` commands within a shell command line,
+// e.g. `FOO=bar fastly compute deploy && fastly purge --all`.
+func fastlyCommands(args []string) []string {
+	var commands []string
+	for i := 0; i < len(args); i++ {
+		if filepath.Base(args[i]) != "fastly" {
+			continue
+		}
+		j := i + 1
+		for j < len(args) && !isShellOperator(args[j]) {
+			j++
+		}
+		if j > i+1 {
+			commands = append(commands, joinArgs(args[i+1:j]))
+		}
+		i = j
+	}
+	return commands
+}
+
+func isShellOperator(s string) bool {
+	switch s {
+	case "|", "||", "&", "&&", ";", ">", ">>", "<", "2>", "2>&1":
+		return true
+	}
+	return false
+}
+
+// joinArgs joins the arguments into a command line, quoting those containing
+// whitespace so the line can be split again.
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, " \t'") {
+			a = `"` + strings.ReplaceAll(a, `"`, `\"`) + `"`
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// splitArgs splits a command line into arguments like a POSIX shell, without
+// expanding variables. An unquoted # starts a comment.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case r == '#' && !inArg:
+			return args, nil
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
@@ -428,3 +429,89 @@ func listTokenOutputSummary(env bool) string {
 Foo   123       456      purge_all global:read  a, b
 Bar   456       789      global                 a, b`, msg)
 }
+
+func TestAdvise(t *testing.T) {
+	workflow := filepath.Join(t.TempDir(), "ci.sh")
+	script := `#!/bin/sh
+set -e
+# Deploy, then purge.
+fastly purge --key foo -s abc && fastly purge --all \
+  --service-id def
+FASTLY_DEBUG_MODE=true fastly dictionary-entry list --dictionary-id 123 -s abc | jq .
+echo "done"
+`
+	if err := os.WriteFile(workflow, []byte(script), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate no commands",
+			Args:      args("auth-token advise"),
+			WantError: "no commands to advise on",
+		},
+		{
+			Name:      "validate missing --file",
+			Args:      args("auth-token advise --file missing.sh"),
+			WantError: "error reading --file",
+		},
+		{
+			Name: "validate account-level commands need all services",
+			Args: []string{"auth-token", "advise", "--command", "compute deploy --service-id abc", "--command", "fastly purge --all -s abc", "--command", "service list", "--command", "compute build"},
+			WantOutputs: []string{
+				"compute deploy --service-id abc  global",
+				"service list                     global:read  (all)",
+				"compute build                    none",
+				"The token needs access to all services because of the account-level commands: service list",
+				"Minimal scope: global, purge_all",
+				"  fastly auth-token create --password <password> --name <name> --scope global --scope purge_all\n",
+			},
+		},
+		{
+			Name: "validate workflow file",
+			Args: args("auth-token advise --file " + workflow),
+			WantOutputs: []string{
+				"purge --key foo -s abc",
+				"purge --all --service-id def",
+				"dictionary-entry list --dictionary-id 123 -s abc",
+				"Minimal scope: global:read, purge_all, purge_select",
+				"--scope global:read --scope purge_all --scope purge_select --services abc,def",
+			},
+		},
+		{
+			Name: "validate unresolved service",
+			Args: []string{"auth-token", "advise", "--command", "backend list --service-name foo --version 1"},
+			WantOutputs: []string{
+				"global:read  name:foo",
+				"The service ID used by 'backend list --service-name foo --version 1' is unknown",
+			},
+		},
+		{
+			Name: "validate unknown and local commands",
+			Args: []string{"auth-token", "advise", "--command", "not-a-command", "--command", "compute serve"},
+			WantOutputs: []string{
+				"'not-a-command' isn't a recognised command",
+			},
+		},
+		{
+			Name:       "validate local commands",
+			Args:       []string{"auth-token", "advise", "--command", "compute serve", "--command", "version"},
+			WantOutput: "The workflow doesn't call the Fastly API, so no token is needed.",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"fmt"
 	"io"
 	"net"
 
@@ -27,6 +28,8 @@ type CreateCommand struct {
 	betweenBytesTimeout cmd.OptionalInt
 	comment             cmd.OptionalString
 	connectTimeout      cmd.OptionalInt
+	dryRun              bool
+	file                cmd.OptionalString
 	firstByteTimeout    cmd.OptionalInt
 	healthCheck         cmd.OptionalString
 	maxConn             cmd.OptionalInt
@@ -43,7 +46,9 @@ type CreateCommand struct {
 	sslCheckCert        cmd.OptionalBool
 	sslCiphers          cmd.OptionalString
 	sslClientCert       cmd.OptionalString
+	sslClientCertFile   cmd.OptionalString
 	sslClientKey        cmd.OptionalString
+	sslClientKeyFile    cmd.OptionalString
 	sslSNIHostname      cmd.OptionalString
 	useSSL              cmd.OptionalBool
 	weight              cmd.OptionalInt
@@ -78,6 +83,8 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 	c.CmdClause.Flag("between-bytes-timeout", "How long to wait between bytes in milliseconds").Action(c.betweenBytesTimeout.Set).IntVar(&c.betweenBytesTimeout.Value)
 	c.CmdClause.Flag("comment", "A descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
 	c.CmdClause.Flag("connect-timeout", "How long to wait for a timeout in milliseconds").Action(c.connectTimeout.Set).IntVar(&c.connectTimeout.Value)
+	c.CmdClause.Flag("dry-run", "Show how the backends of --file differ from the existing backends, without changing them").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("file", "Path to a TOML or JSON file defining multiple backends to create (existing backends of the same name are updated to match)").Action(c.file.Set).StringVar(&c.file.Value)
 	c.CmdClause.Flag("first-byte-timeout", "How long to wait for the first bytes in milliseconds").Action(c.firstByteTimeout.Set).IntVar(&c.firstByteTimeout.Value)
 	c.CmdClause.Flag("healthcheck", "The name of the healthcheck to use with this backend").Action(c.healthCheck.Set).StringVar(&c.healthCheck.Value)
 	c.CmdClause.Flag("max-conn", "Maximum number of connections").Action(c.maxConn.Set).IntVar(&c.maxConn.Value)
@@ -99,13 +106,15 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
-	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network").Action(c.shield.Set).StringVar(&c.shield.Value)
+	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network (picked from a list when omitted in interactive mode)").Action(c.shield.Set).StringVar(&c.shield.Value)
 	c.CmdClause.Flag("ssl-ca-cert", "CA certificate attached to origin").Action(c.sslCACert.Set).StringVar(&c.sslCACert.Value)
 	c.CmdClause.Flag("ssl-cert-hostname", "Overrides ssl_hostname, but only for cert verification. Does not affect SNI at all.").Action(c.sslCertHostname.Set).StringVar(&c.sslCertHostname.Value)
 	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Action(c.sslCheckCert.Set).BoolVar(&c.sslCheckCert.Value)
 	c.CmdClause.Flag("ssl-ciphers", "List of OpenSSL ciphers (https://www.openssl.org/docs/man1.0.2/man1/ciphers)").Action(c.sslCiphers.Set).StringVar(&c.sslCiphers.Value)
 	c.CmdClause.Flag("ssl-client-cert", "Client certificate attached to origin").Action(c.sslClientCert.Set).StringVar(&c.sslClientCert.Value)
+	c.CmdClause.Flag("ssl-client-cert-file", "Path to a PEM file containing the client certificate attached to origin, so it isn't passed on the command line (alternative to --ssl-client-cert)").Action(c.sslClientCertFile.Set).StringVar(&c.sslClientCertFile.Value)
 	c.CmdClause.Flag("ssl-client-key", "Client key attached to origin").Action(c.sslClientKey.Set).StringVar(&c.sslClientKey.Value)
+	c.CmdClause.Flag("ssl-client-key-file", "Path to a PEM file containing the client key attached to origin, so it isn't passed on the command line (alternative to --ssl-client-key)").Action(c.sslClientKeyFile.Set).StringVar(&c.sslClientKeyFile.Value)
 	c.CmdClause.Flag("ssl-sni-hostname", "Overrides ssl_hostname, but only for SNI in the handshake. Does not affect cert validation at all.").Action(c.sslSNIHostname.Set).StringVar(&c.sslSNIHostname.Value)
 	c.CmdClause.Flag("use-ssl", "Whether or not to use SSL to reach the backend").Action(c.useSSL.Set).BoolVar(&c.useSSL.Value)
 	c.CmdClause.Flag("weight", "Weight used to load balance this backend against others").Action(c.weight.Set).IntVar(&c.weight.Value)
@@ -114,7 +123,24 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.file.WasSet {
+		return c.createFromFile(out)
+	}
+	if c.dryRun {
+		return errors.RemediationError{
+			Inner:       fmt.Errorf("--dry-run can only be used with --file"),
+			Remediation: "Define the backends in a file and pass it with --file.",
+		}
+	}
+
+	if err := applyPEMFile(c.sslClientCertFile, &c.sslClientCert, "ssl-client-cert-file", "ssl-client-cert"); err != nil {
+		return err
+	}
+	if err := applyPEMFile(c.sslClientKeyFile, &c.sslClientKey, "ssl-client-key-file", "ssl-client-key"); err != nil {
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -131,6 +157,17 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		})
 		return err
 	}
+
+	if !c.shield.WasSet && canPickShield(c.Globals, in, out) {
+		shield, ok, err := pickShield(c.Globals, in, out, "no shielding")
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.shield.Value, c.shield.WasSet = shield, true
+		}
+	}
+
 	input := fastly.CreateBackendInput{
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion.Number,
@@ -243,6 +280,127 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// createFromFile creates (or updates) the backends defined by --file.
+func (c *CreateCommand) createFromFile(out io.Writer) error {
+	for _, set := range []bool{
+		c.address.WasSet, c.autoLoadBalance.WasSet, c.betweenBytesTimeout.WasSet, c.comment.WasSet,
+		c.connectTimeout.WasSet, c.firstByteTimeout.WasSet, c.healthCheck.WasSet, c.maxConn.WasSet,
+		c.maxTLSVersion.WasSet, c.minTLSVersion.WasSet, c.name.WasSet, c.overrideHost.WasSet,
+		c.port.WasSet, c.requestCondition.WasSet, c.shield.WasSet, c.sslCACert.WasSet,
+		c.sslCertHostname.WasSet, c.sslCheckCert.WasSet, c.sslCiphers.WasSet, c.sslClientCert.WasSet,
+		c.sslClientCertFile.WasSet, c.sslClientKey.WasSet, c.sslClientKeyFile.WasSet,
+		c.sslSNIHostname.WasSet, c.useSSL.WasSet, c.weight.WasSet,
+	} {
+		if set {
+			return errFileFlags
+		}
+	}
+
+	defs, err := readBackendsFile(c.file.Value)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	// NOTE: A dry run doesn't change anything, so the version needn't be
+	// editable (and isn't cloned).
+	opts := cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	}
+	if c.dryRun {
+		opts.AllowActiveLocked = true
+		opts.AutoCloneFlag = cmd.OptionalAutoClone{}
+	}
+	serviceID, serviceVersion, err := cmd.ServiceDetails(opts)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	backends, err := c.Globals.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+	existing := make(map[string]*fastly.Backend, len(backends))
+	for _, b := range backends {
+		existing[b.Name] = b
+	}
+
+	var created, updated, unchanged int
+	for _, d := range defs {
+		b, ok := existing[d.Name]
+		var changes []fieldChange
+		if ok {
+			changes = d.changes(b)
+		}
+
+		switch {
+		case !ok:
+			created++
+			if c.dryRun {
+				fmt.Fprintf(out, "+ %s\n", d.Name)
+				continue
+			}
+			if _, err := c.Globals.APIClient.CreateBackend(d.createInput(serviceID, serviceVersion.Number)); err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Backend":         d.Name,
+					"Service ID":      serviceID,
+					"Service Version": serviceVersion.Number,
+				})
+				return fmt.Errorf("error creating backend %s: %w", d.Name, err)
+			}
+			text.Output(out, "Created backend %s", d.Name)
+		case len(changes) > 0:
+			updated++
+			if c.dryRun {
+				fmt.Fprintf(out, "~ %s\n", d.Name)
+				for _, ch := range changes {
+					fmt.Fprintf(out, "    %s: %v -> %v\n", ch.Field, ch.From, ch.To)
+				}
+				continue
+			}
+			if _, err := c.Globals.APIClient.UpdateBackend(d.updateInput(serviceID, serviceVersion.Number)); err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Backend":         d.Name,
+					"Service ID":      serviceID,
+					"Service Version": serviceVersion.Number,
+				})
+				return fmt.Errorf("error updating backend %s: %w", d.Name, err)
+			}
+			text.Output(out, "Updated backend %s", d.Name)
+		default:
+			unchanged++
+			if c.dryRun {
+				fmt.Fprintf(out, "= %s\n", d.Name)
+			}
+		}
+	}
+
+	if c.dryRun {
+		text.Break(out)
+		text.Info(out, "Dry run: %d backends would be created, %d updated and %d are unchanged", created, updated, unchanged)
+		return nil
+	}
+	text.Success(out, "Created %d and updated %d backends, %d unchanged (service %s version %d)", created, updated, unchanged, serviceID, serviceVersion.Number)
+	return nil
+}
+
 // SetBackendHostDefaults configures the OverrideHost and SSLSNIHostname fields.
 //
 // By default we set the override_host and ssl_sni_hostname properties of the
@@ -0,0 +1,230 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/go-fastly/v7/fastly"
+	toml "github.com/pelletier/go-toml"
+)
+
+// backendsFile is a file of backend definitions, for `backend create --file`.
+type backendsFile struct {
+	Backends []backendDef `json:"backends" toml:"backends"`
+}
+
+// backendDef is the definition of a backend. Unset fields are left to the
+// API's defaults (or, for an existing backend, unchanged).
+type backendDef struct {
+	Name                string  `json:"name" toml:"name"`
+	Address             *string `json:"address" toml:"address"`
+	AutoLoadbalance     *bool   `json:"auto_loadbalance" toml:"auto_loadbalance"`
+	BetweenBytesTimeout *int    `json:"between_bytes_timeout" toml:"between_bytes_timeout"`
+	Comment             *string `json:"comment" toml:"comment"`
+	ConnectTimeout      *int    `json:"connect_timeout" toml:"connect_timeout"`
+	FirstByteTimeout    *int    `json:"first_byte_timeout" toml:"first_byte_timeout"`
+	HealthCheck         *string `json:"healthcheck" toml:"healthcheck"`
+	MaxConn             *int    `json:"max_conn" toml:"max_conn"`
+	MaxTLSVersion       *string `json:"max_tls_version" toml:"max_tls_version"`
+	MinTLSVersion       *string `json:"min_tls_version" toml:"min_tls_version"`
+	OverrideHost        *string `json:"override_host" toml:"override_host"`
+	Port                *int    `json:"port" toml:"port"`
+	RequestCondition    *string `json:"request_condition" toml:"request_condition"`
+	Shield              *string `json:"shield" toml:"shield"`
+	SSLCACert           *string `json:"ssl_ca_cert" toml:"ssl_ca_cert"`
+	SSLCertHostname     *string `json:"ssl_cert_hostname" toml:"ssl_cert_hostname"`
+	SSLCheckCert        *bool   `json:"ssl_check_cert" toml:"ssl_check_cert"`
+	SSLCiphers          *string `json:"ssl_ciphers" toml:"ssl_ciphers"`
+	SSLSNIHostname      *string `json:"ssl_sni_hostname" toml:"ssl_sni_hostname"`
+	UseSSL              *bool   `json:"use_ssl" toml:"use_ssl"`
+	Weight              *int    `json:"weight" toml:"weight"`
+}
+
+// fieldChange is a difference between a backend's definition and the backend.
+type fieldChange struct {
+	Field string
+	From  any
+	To    any
+}
+
+// readBackendsFile reads the backend definitions of a TOML or JSON file, the
+// format being decided by the file's extension.
+func readBackendsFile(path string) ([]backendDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var f backendsFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &f)
+	case ".toml":
+		err = toml.Unmarshal(data, &f)
+	default:
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("unsupported file type: %s", path),
+			Remediation: "Use a .toml or .json file.",
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	if len(f.Backends) == 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no backends defined in %s", path),
+			Remediation: "Define each backend as a [[backends]] table (TOML) or an item of a \"backends\" array (JSON).",
+		}
+	}
+	seen := make(map[string]bool, len(f.Backends))
+	for i, d := range f.Backends {
+		if d.Name == "" {
+			return nil, fmt.Errorf("backend %d of %s has no name", i+1, path)
+		}
+		if seen[d.Name] {
+			return nil, fmt.Errorf("backend %s is defined more than once in %s", d.Name, path)
+		}
+		seen[d.Name] = true
+	}
+	return f.Backends, nil
+}
+
+// changes returns the fields of the definition that differ from the backend.
+func (d backendDef) changes(b *fastly.Backend) []fieldChange {
+	var changes []fieldChange
+	add := func(field string, from, to any) {
+		if from != to {
+			changes = append(changes, fieldChange{Field: field, From: from, To: to})
+		}
+	}
+	str := func(field string, from string, to *string) {
+		if to != nil {
+			add(field, from, *to)
+		}
+	}
+	num := func(field string, from int, to *int) {
+		if to != nil {
+			add(field, from, *to)
+		}
+	}
+	flag := func(field string, from bool, to *bool) {
+		if to != nil {
+			add(field, from, *to)
+		}
+	}
+
+	str("address", b.Address, d.Address)
+	flag("auto_loadbalance", b.AutoLoadbalance, d.AutoLoadbalance)
+	num("between_bytes_timeout", b.BetweenBytesTimeout, d.BetweenBytesTimeout)
+	str("comment", b.Comment, d.Comment)
+	num("connect_timeout", b.ConnectTimeout, d.ConnectTimeout)
+	num("first_byte_timeout", b.FirstByteTimeout, d.FirstByteTimeout)
+	str("healthcheck", b.HealthCheck, d.HealthCheck)
+	num("max_conn", b.MaxConn, d.MaxConn)
+	str("max_tls_version", b.MaxTLSVersion, d.MaxTLSVersion)
+	str("min_tls_version", b.MinTLSVersion, d.MinTLSVersion)
+	str("override_host", b.OverrideHost, d.OverrideHost)
+	num("port", b.Port, d.Port)
+	str("request_condition", b.RequestCondition, d.RequestCondition)
+	str("shield", b.Shield, d.Shield)
+	str("ssl_ca_cert", b.SSLCACert, d.SSLCACert)
+	str("ssl_cert_hostname", b.SSLCertHostname, d.SSLCertHostname)
+	flag("ssl_check_cert", b.SSLCheckCert, d.SSLCheckCert)
+	str("ssl_ciphers", b.SSLCiphers, d.SSLCiphers)
+	str("ssl_sni_hostname", b.SSLSNIHostname, d.SSLSNIHostname)
+	flag("use_ssl", b.UseSSL, d.UseSSL)
+	num("weight", b.Weight, d.Weight)
+	return changes
+}
+
+// createInput returns the input to create the backend, applying the same
+// defaults as creating a single backend with flags.
+func (d backendDef) createInput(serviceID string, serviceVersion int) *fastly.CreateBackendInput {
+	input := &fastly.CreateBackendInput{
+		Address:             d.Address,
+		AutoLoadbalance:     cbool(d.AutoLoadbalance),
+		BetweenBytesTimeout: d.BetweenBytesTimeout,
+		Comment:             d.Comment,
+		ConnectTimeout:      d.ConnectTimeout,
+		FirstByteTimeout:    d.FirstByteTimeout,
+		HealthCheck:         d.HealthCheck,
+		MaxConn:             d.MaxConn,
+		MaxTLSVersion:       d.MaxTLSVersion,
+		MinTLSVersion:       d.MinTLSVersion,
+		Name:                fastly.String(d.Name),
+		OverrideHost:        d.OverrideHost,
+		Port:                d.Port,
+		RequestCondition:    d.RequestCondition,
+		SSLCACert:           d.SSLCACert,
+		SSLCertHostname:     d.SSLCertHostname,
+		SSLCheckCert:        cbool(d.SSLCheckCert),
+		SSLCiphers:          d.SSLCiphers,
+		SSLSNIHostname:      d.SSLSNIHostname,
+		ServiceID:           serviceID,
+		ServiceVersion:      serviceVersion,
+		Shield:              d.Shield,
+		UseSSL:              cbool(d.UseSSL),
+		Weight:              d.Weight,
+	}
+	if input.Port == nil && d.UseSSL != nil && *d.UseSSL {
+		input.Port = fastly.Int(443)
+	}
+	if d.Address != nil && d.OverrideHost == nil && d.SSLCertHostname == nil && d.SSLSNIHostname == nil {
+		overrideHost, sslSNIHostname, sslCertHostname := SetBackendHostDefaults(*d.Address)
+		input.OverrideHost = &overrideHost
+		input.SSLSNIHostname = &sslSNIHostname
+		input.SSLCertHostname = &sslCertHostname
+	}
+	return input
+}
+
+// updateInput returns the input to update an existing backend to match its
+// definition.
+func (d backendDef) updateInput(serviceID string, serviceVersion int) *fastly.UpdateBackendInput {
+	return &fastly.UpdateBackendInput{
+		Address:             d.Address,
+		AutoLoadbalance:     cbool(d.AutoLoadbalance),
+		BetweenBytesTimeout: d.BetweenBytesTimeout,
+		Comment:             d.Comment,
+		ConnectTimeout:      d.ConnectTimeout,
+		FirstByteTimeout:    d.FirstByteTimeout,
+		HealthCheck:         d.HealthCheck,
+		MaxConn:             d.MaxConn,
+		MaxTLSVersion:       d.MaxTLSVersion,
+		MinTLSVersion:       d.MinTLSVersion,
+		Name:                d.Name,
+		OverrideHost:        d.OverrideHost,
+		Port:                d.Port,
+		RequestCondition:    d.RequestCondition,
+		SSLCACert:           d.SSLCACert,
+		SSLCertHostname:     d.SSLCertHostname,
+		SSLCheckCert:        cbool(d.SSLCheckCert),
+		SSLCiphers:          d.SSLCiphers,
+		SSLSNIHostname:      d.SSLSNIHostname,
+		ServiceID:           serviceID,
+		ServiceVersion:      serviceVersion,
+		Shield:              d.Shield,
+		UseSSL:              cbool(d.UseSSL),
+		Weight:              d.Weight,
+	}
+}
+
+// cbool converts an optional bool into the type used by the API client.
+func cbool(b *bool) *fastly.Compatibool {
+	if b == nil {
+		return nil
+	}
+	return fastly.CBool(*b)
+}
+
+// errFileFlags means --file was combined with flags defining a single backend.
+var errFileFlags = fsterr.RemediationError{
+	Inner:       errors.New("--file can't be used with the flags defining a single backend"),
+	Remediation: "Define every backend in the file, or remove --file to create a single backend with flags.",
+}
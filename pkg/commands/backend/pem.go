@@ -0,0 +1,23 @@
+package backend
+
+import (
+	"encoding/pem"
+	"fmt"
+
+	"github.com/fastly/cli/pkg/cmd"
+)
+
+// applyPEMFile populates value from the PEM file referenced by file (if set),
+// so a client certificate or key isn't passed on the command line where it
+// would be kept in the shell history.
+func applyPEMFile(file cmd.OptionalString, value *cmd.OptionalString, fileFlag, valueFlag string) error {
+	if err := cmd.ApplyFileFlag(file, value, fileFlag, valueFlag); err != nil {
+		return err
+	}
+	if file.WasSet {
+		if block, _ := pem.Decode([]byte(value.Value)); block == nil {
+			return fmt.Errorf("error reading --%s: %s isn't PEM encoded", fileFlag, file.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// maxShieldMatches is the number of matching POPs listed by the picker.
+const maxShieldMatches = 20
+
+// canPickShield reports whether the user can be prompted to pick a shield POP,
+// i.e. prompts are enabled and the output is a terminal.
+func canPickShield(g *global.Data, in io.Reader, out io.Writer) bool {
+	if _, ok := in.(text.NoPromptReader); ok || in == nil {
+		return false
+	}
+	return !g.Flags.NonInteractive && !g.Flags.AutoYes && !g.Flags.AcceptDefaults && text.IsTTY(out)
+}
+
+// pickShield fetches the POPs that can be used as shields and prompts the user
+// to pick one, returning false if none was picked.
+func pickShield(g *global.Data, in io.Reader, out io.Writer, skip string) (string, bool, error) {
+	dcs, err := g.APIClient.AllDatacenters()
+	if err != nil {
+		g.ErrLog.Add(err)
+		return "", false, fmt.Errorf("error listing POPs: %w", err)
+	}
+	return PickShield(in, out, dcs, skip)
+}
+
+// PickShield prompts the user to search the shield POPs of the datacenters by
+// code, name or group, and then to select one of the matches. An empty answer
+// picks none, which is described by skip (e.g. "no shielding").
+//
+// NOTE: A single scanner reads every answer, as one scanner per prompt could
+// buffer (and so lose) the answers to later prompts.
+func PickShield(in io.Reader, out io.Writer, datacenters []fastly.Datacenter, skip string) (string, bool, error) {
+	var pops []fastly.Datacenter
+	for _, dc := range datacenters {
+		if dc.Shield != "" {
+			pops = append(pops, dc)
+		}
+	}
+	sort.Slice(pops, func(i, j int) bool {
+		return pops[i].Shield < pops[j].Shield
+	})
+
+	scanner := bufio.NewScanner(in)
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(out, text.Bold(prompt))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+
+	text.Break(out)
+	answer, err := ask(fmt.Sprintf("Shield POP (search by code, name or region; leave blank for %s): ", skip))
+	for err == nil {
+		if answer == "" {
+			return "", false, nil
+		}
+
+		matches, exact := searchPOPs(pops, answer)
+		switch {
+		case len(matches) == 0:
+			text.Warning(out, "No shield POPs match '%s'", answer)
+			answer, err = ask("Search again (leave blank to skip): ")
+			continue
+		case len(matches) == 1 || exact:
+			text.Info(out, "Using shield POP %s (%s)", matches[0].Shield, matches[0].Name)
+			return matches[0].Shield, true, nil
+		}
+
+		if len(matches) > maxShieldMatches {
+			matches = matches[:maxShieldMatches]
+		}
+		for i, dc := range matches {
+			fmt.Fprintf(out, "%3d. %-24s %s, %s\n", i+1, dc.Shield, dc.Name, dc.Group)
+		}
+		answer, err = ask(fmt.Sprintf("Select a POP [1-%d], search again, or leave blank to skip: ", len(matches)))
+		if n, convErr := strconv.Atoi(answer); convErr == nil && err == nil {
+			if n < 1 || n > len(matches) {
+				text.Warning(out, "Select a number between 1 and %d", len(matches))
+				answer, err = ask("Search again (leave blank to skip): ")
+				continue
+			}
+			return matches[n-1].Shield, true, nil
+		}
+	}
+	if err == io.EOF {
+		return "", false, nil
+	}
+	return "", false, err
+}
+
+// searchPOPs returns the POPs matching the query. When the shield or POP code
+// of a POP is exactly the query it's returned first, and exact is true.
+func searchPOPs(pops []fastly.Datacenter, query string) (matches []fastly.Datacenter, exact bool) {
+	q := strings.ToLower(query)
+	var exactMatches []fastly.Datacenter
+	for _, dc := range pops {
+		switch {
+		case strings.ToLower(dc.Shield) == q || strings.ToLower(dc.Code) == q:
+			exactMatches = append(exactMatches, dc)
+		case strings.Contains(strings.ToLower(dc.Shield), q),
+			strings.Contains(strings.ToLower(dc.Name), q),
+			strings.Contains(strings.ToLower(dc.Group), q):
+			matches = append(matches, dc)
+		}
+	}
+	return append(exactMatches, matches...), len(exactMatches) > 0
+}
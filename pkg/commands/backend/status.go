@@ -0,0 +1,232 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The issues flagged on a backend.
+const (
+	IssueHealthCheckMissing = "healthcheck-missing"
+	IssueNoHealthCheck      = "no-healthcheck"
+	IssueNoTLS              = "no-tls"
+	IssueShieldUnknown      = "shield-unknown"
+	IssueTLSNotVerified     = "tls-not-verified"
+)
+
+// StatusCommand calls the Fastly API to summarise the health checking, TLS
+// and shielding of the backends of a service version.
+type StatusCommand struct {
+	cmd.Base
+	manifest       manifest.Data
+	json           bool
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// BackendStatus is the summary of a backend.
+type BackendStatus struct {
+	Name        string              `json:"name"`
+	Address     string              `json:"address"`
+	Port        int                 `json:"port"`
+	UseSSL      bool                `json:"use_ssl"`
+	SSLVerified bool                `json:"ssl_check_cert"`
+	Shield      string              `json:"shield,omitempty"`
+	ShieldPOP   *fastly.Datacenter  `json:"shield_pop,omitempty"`
+	HealthCheck *fastly.HealthCheck `json:"healthcheck,omitempty"`
+	Issues      []string            `json:"issues"`
+}
+
+// NewStatusCommand returns a usable command registered under the parent.
+func NewStatusCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *StatusCommand {
+	c := StatusCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("status", "Show the healthchecks, TLS verification and shield POPs of the backends of a Fastly service version, flagging misconfigured backends")
+
+	// optional
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: cmd.FlagJSONDesc,
+		Dst:         &c.json,
+		Short:       'j',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc + " (defaults to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *StatusCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	backends, err := c.Globals.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+	healthChecks, err := c.Globals.APIClient.ListHealthChecks(&fastly.ListHealthChecksInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+	datacenters, err := c.Globals.APIClient.AllDatacenters()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	statuses := backendStatuses(backends, healthChecks, datacenters)
+
+	if c.json {
+		data, err := json.Marshal(statuses)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+		return nil
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("NAME", "ADDRESS", "PORT", "TLS", "VERIFIED", "SHIELD", "HEALTHCHECK", "ISSUES")
+	var flagged int
+	for _, s := range statuses {
+		shield := s.Shield
+		if s.ShieldPOP != nil {
+			shield = fmt.Sprintf("%s (%s)", s.Shield, s.ShieldPOP.Name)
+		}
+		var healthCheck string
+		if s.HealthCheck != nil {
+			healthCheck = fmt.Sprintf("%s %s", s.HealthCheck.Method, s.HealthCheck.Path)
+		}
+		if len(s.Issues) > 0 {
+			flagged++
+		}
+		t.AddLine(s.Name, s.Address, s.Port, s.UseSSL, s.SSLVerified, shield, healthCheck, strings.Join(s.Issues, ", "))
+	}
+	t.Print()
+
+	// NOTE: The API doesn't expose the live results of healthchecks, only how
+	// they're configured, so the issues are those of each backend's config.
+	text.Break(out)
+	if flagged > 0 {
+		text.Warning(out, "%d of %d backends have issues (service %s version %d)", flagged, len(statuses), serviceID, serviceVersion.Number)
+		return nil
+	}
+	text.Success(out, "No issues found with %d backends (service %s version %d)", len(statuses), serviceID, serviceVersion.Number)
+	return nil
+}
+
+// backendStatuses correlates each backend with its healthcheck and shield POP,
+// flagging any issues.
+func backendStatuses(backends []*fastly.Backend, healthChecks []*fastly.HealthCheck, datacenters []fastly.Datacenter) []BackendStatus {
+	checks := make(map[string]*fastly.HealthCheck, len(healthChecks))
+	for _, h := range healthChecks {
+		checks[h.Name] = h
+	}
+	pops := make(map[string]fastly.Datacenter, len(datacenters))
+	for _, dc := range datacenters {
+		pops[dc.Shield] = dc
+	}
+
+	statuses := make([]BackendStatus, 0, len(backends))
+	for _, b := range backends {
+		s := BackendStatus{
+			Name:        b.Name,
+			Address:     b.Address,
+			Port:        b.Port,
+			UseSSL:      b.UseSSL,
+			SSLVerified: b.UseSSL && b.SSLCheckCert,
+			Shield:      b.Shield,
+			Issues:      []string{},
+		}
+
+		switch h, ok := checks[b.HealthCheck]; {
+		case b.HealthCheck == "":
+			s.Issues = append(s.Issues, IssueNoHealthCheck)
+		case !ok:
+			s.Issues = append(s.Issues, IssueHealthCheckMissing)
+		default:
+			s.HealthCheck = h
+		}
+
+		switch {
+		case !b.UseSSL:
+			s.Issues = append(s.Issues, IssueNoTLS)
+		case !b.SSLCheckCert:
+			s.Issues = append(s.Issues, IssueTLSNotVerified)
+		}
+
+		if b.Shield != "" {
+			if dc, ok := pops[b.Shield]; ok {
+				s.ShieldPOP = &dc
+			} else {
+				s.Issues = append(s.Issues, IssueShieldUnknown)
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
@@ -39,7 +39,9 @@ type UpdateCommand struct {
 	SSLCheckCert        cmd.OptionalBool
 	SSLCACert           cmd.OptionalString
 	SSLClientCert       cmd.OptionalString
+	SSLClientCertFile   cmd.OptionalString
 	SSLClientKey        cmd.OptionalString
+	SSLClientKeyFile    cmd.OptionalString
 	SSLCertHostname     cmd.OptionalString
 	SSLSNIHostname      cmd.OptionalString
 	MinTLSVersion       cmd.OptionalString
@@ -97,13 +99,15 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
-	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network").Action(c.Shield.Set).StringVar(&c.Shield.Value)
+	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network (picked from a list when omitted in interactive mode)").Action(c.Shield.Set).StringVar(&c.Shield.Value)
 	c.CmdClause.Flag("ssl-ca-cert", "CA certificate attached to origin").Action(c.SSLCACert.Set).StringVar(&c.SSLCACert.Value)
 	c.CmdClause.Flag("ssl-cert-hostname", "Overrides ssl_hostname, but only for cert verification. Does not affect SNI at all.").Action(c.SSLCertHostname.Set).StringVar(&c.SSLCertHostname.Value)
 	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Action(c.SSLCheckCert.Set).BoolVar(&c.SSLCheckCert.Value)
 	c.CmdClause.Flag("ssl-ciphers", "List of OpenSSL ciphers (https://www.openssl.org/docs/man1.0.2/man1/ciphers)").Action(c.SSLCiphers.Set).StringVar(&c.SSLCiphers.Value)
 	c.CmdClause.Flag("ssl-client-cert", "Client certificate attached to origin").Action(c.SSLClientCert.Set).StringVar(&c.SSLClientCert.Value)
+	c.CmdClause.Flag("ssl-client-cert-file", "Path to a PEM file containing the client certificate attached to origin, so it isn't passed on the command line (alternative to --ssl-client-cert)").Action(c.SSLClientCertFile.Set).StringVar(&c.SSLClientCertFile.Value)
 	c.CmdClause.Flag("ssl-client-key", "Client key attached to origin").Action(c.SSLClientKey.Set).StringVar(&c.SSLClientKey.Value)
+	c.CmdClause.Flag("ssl-client-key-file", "Path to a PEM file containing the client key attached to origin, so it isn't passed on the command line (alternative to --ssl-client-key)").Action(c.SSLClientKeyFile.Set).StringVar(&c.SSLClientKeyFile.Value)
 	c.CmdClause.Flag("ssl-sni-hostname", "Overrides ssl_hostname, but only for SNI in the handshake. Does not affect cert validation at all.").Action(c.SSLSNIHostname.Set).StringVar(&c.SSLSNIHostname.Value)
 	c.CmdClause.Flag("use-ssl", "Whether or not to use SSL to reach the backend").Action(c.UseSSL.Set).BoolVar(&c.UseSSL.Value)
 	c.CmdClause.Flag("weight", "Weight used to load balance this backend against others").Action(c.Weight.Set).IntVar(&c.Weight.Value)
@@ -111,7 +115,14 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 }
 
 // Exec invokes the application logic for the command.
-func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *UpdateCommand) Exec(in io.Reader, out io.Writer) error {
+	if err := applyPEMFile(c.SSLClientCertFile, &c.SSLClientCert, "ssl-client-cert-file", "ssl-client-cert"); err != nil {
+		return err
+	}
+	if err := applyPEMFile(c.SSLClientKeyFile, &c.SSLClientKey, "ssl-client-key-file", "ssl-client-key"); err != nil {
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -129,6 +140,16 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if !c.Shield.WasSet && canPickShield(c.Globals, in, out) {
+		shield, ok, err := pickShield(c.Globals, in, out, "the current shield")
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.Shield.Value, c.Shield.WasSet = shield, true
+		}
+	}
+
 	input := &fastly.UpdateBackendInput{
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion.Number,
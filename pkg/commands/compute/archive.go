@@ -0,0 +1,134 @@
+package compute
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// SourceDateEpochEnvVar is the environment variable that sets the modification
+// time of every package archive entry, as seconds since the Unix epoch.
+//
+// Reference: https://reproducible-builds.org/specs/source-date-epoch/
+const SourceDateEpochEnvVar = "SOURCE_DATE_EPOCH"
+
+// defaultArchiveTime is the modification time of every package archive entry
+// when SOURCE_DATE_EPOCH isn't set. It's 1980 rather than the Unix epoch as
+// some tools warn about "implausibly old" timestamps.
+var defaultArchiveTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// archiveTime returns the modification time of the package archive entries.
+func archiveTime() (time.Time, error) {
+	v := os.Getenv(SourceDateEpochEnvVar)
+	if v == "" {
+		return defaultArchiveTime, nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s '%s': must be a number of seconds", SourceDateEpochEnvVar, v)
+	}
+	return time.Unix(secs, 0).UTC(), nil
+}
+
+// writeArchive writes the contents of dir to destination as a GZipped Tar
+// archive, with dir as the archive's top-level directory.
+//
+// The archive is reproducible: its entries are sorted, and their metadata is
+// normalised so it only depends on the file contents and whether the file is
+// executable.
+func writeArchive(dir, destination string) (err error) {
+	mtime, err := archiveTime()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o750); err != nil {
+		return err
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	// The gzip header's name and modification time are left empty.
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	// filepath.WalkDir walks in lexical order, so the entries are sorted.
+	root := filepath.Dir(dir)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    filepath.ToSlash(name),
+			ModTime: mtime,
+		}
+
+		switch {
+		case d.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			hdr.Mode = 0o755
+			return tw.WriteHeader(hdr)
+		case !d.Type().IsRegular():
+			return fmt.Errorf("non-regular file in package: %s", path)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = info.Size()
+		hdr.Mode = 0o644
+		if info.Mode()&0o111 != 0 {
+			hdr.Mode = 0o755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		return copyFileTo(tw, path)
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// copyFileTo copies the contents of the file at path to w.
+func copyFileTo(w io.Writer, path string) error {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // #nosec G307
+	_, err = io.Copy(w, f)
+	return err
+}
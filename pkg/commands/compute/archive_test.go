@@ -0,0 +1,160 @@
+package compute_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+func TestCreatePackageArchiveReproducible(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.WriteFile("fastly.toml", []byte(`name = "package"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("bin", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("bin", "main.wasm"), []byte("wasm"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	files := []string{"fastly.toml", "bin/main.wasm"}
+
+	archive := func(name string) []byte {
+		dest := filepath.Join(name, "package.tar.gz")
+		testutil.AssertNoError(t, compute.CreatePackageArchive(files, dest))
+		data, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first := archive("first")
+
+	// The modification times of the files don't affect the archive.
+	later := time.Now().Add(time.Hour)
+	for _, f := range files {
+		if err := os.Chtimes(f, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if second := archive("second"); !bytes.Equal(first, second) {
+		t.Fatal("want the archives to be identical")
+	}
+	if modTimes(t, first)["package/fastly.toml"].Year() != 1980 {
+		t.Fatalf("want the default archive time, have %v", modTimes(t, first))
+	}
+
+	t.Setenv(compute.SourceDateEpochEnvVar, "1700000000")
+	third := archive("third")
+	if bytes.Equal(first, third) {
+		t.Fatal("want SOURCE_DATE_EPOCH to change the archive")
+	}
+	for name, mtime := range modTimes(t, third) {
+		if mtime.Unix() != 1700000000 {
+			t.Fatalf("want %s to have the SOURCE_DATE_EPOCH time, have %v", name, mtime)
+		}
+	}
+
+	t.Setenv(compute.SourceDateEpochEnvVar, "yesterday")
+	err = compute.CreatePackageArchive(files, filepath.Join("fourth", "package.tar.gz"))
+	testutil.AssertErrorContains(t, err, "invalid SOURCE_DATE_EPOCH 'yesterday'")
+}
+
+// modTimes returns the modification time of each entry of the tar.gz data.
+func modTimes(t *testing.T, data []byte) map[string]time.Time {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make(map[string]time.Time)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return times
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		times[hdr.Name] = hdr.ModTime
+	}
+}
+
+func TestBuildVerifyReproducible(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script is a shell command")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, testcase := range []struct {
+		name                 string
+		build                string
+		wantError            string
+		wantRemediationError string
+		wantOutput           string
+	}{
+		{
+			name:       "reproducible build",
+			build:      "echo wasm > ./bin/main.wasm",
+			wantOutput: "Verified the build is reproducible",
+		},
+		{
+			name:                 "non-reproducible build",
+			build:                "head -c 16 /dev/urandom > ./bin/main.wasm",
+			wantError:            "the build isn't reproducible: bin/main.wasm differs between builds",
+			wantRemediationError: "such as timestamps",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			manifest := `name = "package"
+manifest_version = 2
+language = "other"
+[scripts]
+build = "` + testcase.build + `"
+`
+			if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("compute build --auto-yes --verify-reproducible"), &stdout)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
@@ -11,13 +11,14 @@ import (
 	"strings"
 
 	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/github"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 	"github.com/kennygrant/sanitize"
-	"github.com/mholt/archiver/v3"
 )
 
 // IgnoreFilePath is the filepath name of the Fastly ignore file.
@@ -29,10 +30,20 @@ const CustomPostBuildScriptMessage = "This project has a custom post build scrip
 
 // Flags represents the flags defined for the command.
 type Flags struct {
-	IncludeSrc  bool
-	Lang        string
-	PackageName string
-	Timeout     int
+	Frozen             bool
+	IncludeSrc         bool
+	Lang               string
+	NoCache            bool
+	Optimize           bool
+	PackageName        string
+	QuietSuccess       bool
+	Timeout            int
+	VerifyReproducible bool
+
+	// OutputPrefix prefixes each line of the compiler output. It isn't a flag,
+	// but is set by the composite commands when building several packages or
+	// rebuilding with --watch, so the output of each build can be told apart.
+	OutputPrefix string
 }
 
 // BuildCommand produces a deployable artifact from files on the local disk.
@@ -43,21 +54,32 @@ type BuildCommand struct {
 	// commands can set the values appropriately before calling Exec().
 	Flags    Flags
 	Manifest manifest.Data
+
+	cache            cmd.OptionalBool
+	viceroyVersioner github.AssetVersioner
+	wasmOptVersioner github.AssetVersioner
 }
 
 // NewBuildCommand returns a usable command registered under the parent.
-func NewBuildCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *BuildCommand {
+func NewBuildCommand(parent cmd.Registerer, g *global.Data, av, wasmOptAV github.AssetVersioner, m manifest.Data) *BuildCommand {
 	var c BuildCommand
 	c.Globals = g
 	c.Manifest = m
+	c.viceroyVersioner = av
+	c.wasmOptVersioner = wasmOptAV
 	c.CmdClause = parent.Command("build", "Build a Compute@Edge package locally")
 
 	// NOTE: when updating these flags, be sure to update the composite commands:
 	// `compute publish` and `compute serve`.
+	c.CmdClause.Flag("cache", fmt.Sprintf("Skip the build if nothing has changed since the last build (see %s), use --no-cache to force a rebuild", BuildCacheDir)).Action(c.cache.Set).NegatableBoolVar(&c.cache.Value)
+	c.CmdClause.Flag("frozen", fmt.Sprintf("Fail the build if the toolchain versions don't match those recorded in %s", LockFilename)).BoolVar(&c.Flags.Frozen)
 	c.CmdClause.Flag("include-source", "Include source code in built package").BoolVar(&c.Flags.IncludeSrc)
 	c.CmdClause.Flag("language", "Language type").StringVar(&c.Flags.Lang)
+	c.CmdClause.Flag("optimize", "Optimize the Wasm binary with wasm-opt before packaging it (downloaded on first use)").BoolVar(&c.Flags.Optimize)
 	c.CmdClause.Flag("package-name", "Package name").StringVar(&c.Flags.PackageName)
+	c.CmdClause.Flag("quiet-success", "Only print the compiler output if the build fails, even with --verbose").BoolVar(&c.Flags.QuietSuccess)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").IntVar(&c.Flags.Timeout)
+	c.CmdClause.Flag("verify-reproducible", "Build the package a second time and fail if the packages differ").BoolVar(&c.Flags.VerifyReproducible)
 
 	return &c
 }
@@ -71,10 +93,20 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		out = io.Discard
 	}
 
+	if c.cache.WasSet {
+		c.Flags.NoCache = !c.cache.Value
+	}
+
+	done := c.Globals.Progress.Phase("build")
+	defer func() {
+		done(err)
+	}()
+
 	spinner, err := text.NewSpinner(out)
 	if err != nil {
 		return err
 	}
+	spinner = c.Globals.Progress.Spinner(spinner, "build")
 
 	defer func(errLog fsterr.LogInterface) {
 		if err != nil {
@@ -157,7 +189,25 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	language, err := language(toolchain, c, in, out, spinner)
+	dest := filepath.Join("pkg", fmt.Sprintf("%s.tar.gz", packageName))
+	hookEnv := c.hookEnv(dest)
+
+	language, err := language(toolchain, c, hookEnv, in, out, spinner)
+	if err != nil {
+		return err
+	}
+
+	// wasm-opt is installed before checking the lockfile, so its version is
+	// recorded along with the toolchain versions (and is part of the cache key).
+	var wasmOpt string
+	if c.Flags.Optimize {
+		wasmOpt, err = c.wasmOpt(spinner)
+		if err != nil {
+			return err
+		}
+	}
+
+	versions, err := c.checkLockfile(language.Name, wasmOpt, out)
 	if err != nil {
 		return err
 	}
@@ -167,6 +217,42 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	// The pre_build script runs before the cache key is calculated, as it may
+	// generate project files.
+	preBuild := hook{
+		name:   "pre_build",
+		script: c.Manifest.File.Scripts.PreBuild,
+		env:    hookEnv,
+	}
+	if err := preBuild.run(c.Globals, spinner, in, out); err != nil {
+		return err
+	}
+
+	// The cache key is calculated before building, as the build may modify the
+	// project files (e.g. Cargo.lock), which the next build then picks up.
+	cacheKey, cacheErr := buildCacheKey(language.Name, versions, c.Flags, packageName)
+	if cacheErr != nil {
+		c.Globals.ErrLog.Add(cacheErr)
+		if c.Globals.Verbose() {
+			text.Warning(out, "Unable to calculate the build cache key, the package will be rebuilt: %s", cacheErr)
+		}
+	}
+	if cacheErr == nil && !c.Flags.NoCache && !c.Flags.VerifyReproducible && cachedBuild(cacheKey, dest) {
+		out = originalOut
+		if !c.Globals.Flags.Quiet {
+			text.Info(out, "Nothing has changed since the last build (use --no-cache to rebuild)")
+		}
+		text.Success(out, "Built package (%s)", dest)
+		return nil
+	}
+
+	// The build info is recorded before building, as the build outputs could
+	// otherwise be reported as uncommitted changes.
+	buildInfo, err := newBuildInfo(versions)
+	if err != nil {
+		return err
+	}
+
 	if err := language.Build(); err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Language": language.Name,
@@ -174,6 +260,12 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	if c.Flags.Optimize {
+		if err := c.optimize(wasmOpt, spinner, out); err != nil {
+			return err
+		}
+	}
+
 	err = spinner.Start()
 	if err != nil {
 		return err
@@ -181,8 +273,6 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	msg = "Creating package archive"
 	spinner.Message(msg + "...")
 
-	dest := filepath.Join("pkg", fmt.Sprintf("%s.tar.gz", packageName))
-
 	// NOTE: The minimum package requirement is `fastly.toml` and `main.wasm`.
 	files := []string{
 		manifest.Filename,
@@ -199,7 +289,7 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	err = CreatePackageArchive(files, dest)
+	err = createPackageArchive(files, dest, buildInfo)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Files":       files,
@@ -221,11 +311,168 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	if c.Flags.VerifyReproducible {
+		if err := c.verifyReproducible(language, files, versions, wasmOpt, dest, spinner, out); err != nil {
+			return err
+		}
+	}
+
+	if cacheErr == nil {
+		if err := writeBuildCache(cacheKey, dest); err != nil {
+			c.Globals.ErrLog.Add(err)
+			if c.Globals.Verbose() {
+				text.Warning(out, "Unable to write the build cache: %s", err)
+			}
+		}
+	}
+
 	out = originalOut
 	text.Success(out, "Built package (%s)", dest)
 	return nil
 }
 
+// verifyReproducible builds the package a second time, into a temporary
+// directory, and returns an error if it differs from the package at dest.
+//
+// NOTE: The build info is recreated, rather than reused, so a value that changes
+// between builds is detected.
+func (c *BuildCommand) verifyReproducible(language *Language, files []string, versions map[string]string, wasmOpt, dest string, spinner text.Spinner, out io.Writer) error {
+	wasm, err := fileSHA256(builtWasmPath)
+	if err != nil {
+		return err
+	}
+	pkg, err := fileSHA256(dest)
+	if err != nil {
+		return err
+	}
+
+	text.Info(out, "Building the package again to verify it's reproducible")
+	text.Break(out)
+	if err := language.Build(); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Language": language.Name,
+		})
+		return err
+	}
+	if c.Flags.Optimize {
+		if err := c.optimize(wasmOpt, spinner, out); err != nil {
+			return err
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "fastly-verify-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	info, err := newBuildInfo(versions)
+	if err != nil {
+		return err
+	}
+
+	// The package has the same filename, as it names the top-level directory.
+	verifyDest := filepath.Join(tmpDir, filepath.Base(dest))
+	if err := createPackageArchive(files, verifyDest, info); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Files":       files,
+			"Destination": verifyDest,
+		})
+		return fmt.Errorf("error creating package archive: %w", err)
+	}
+
+	remediation := fmt.Sprintf("Ensure the build doesn't embed values that change between builds, such as timestamps, random values or absolute paths. The archive timestamps are set from %s.", SourceDateEpochEnvVar)
+	wasm2, err := fileSHA256(builtWasmPath)
+	if err != nil {
+		return err
+	}
+	if wasm2 != wasm {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the build isn't reproducible: %s differs between builds (sha256 %s and %s)", builtWasmPath, wasm, wasm2),
+			Remediation: remediation,
+		}
+	}
+	pkg2, err := fileSHA256(verifyDest)
+	if err != nil {
+		return err
+	}
+	if pkg2 != pkg {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the build isn't reproducible: %s differs between builds (sha256 %s and %s)", dest, pkg, pkg2),
+			Remediation: remediation,
+		}
+	}
+
+	text.Success(out, "Verified the build is reproducible (sha256 %s)", pkg)
+	return nil
+}
+
+// checkLockfile compares the installed toolchain versions with those recorded
+// in the fastly.lock file, warning about any drift (or failing when --frozen
+// is set). The lockfile is created if it doesn't exist yet.
+//
+// The version of the wasm-opt binary used by --optimize (if set) is recorded
+// along with the toolchain versions.
+//
+// It returns the installed toolchain versions.
+func (c *BuildCommand) checkLockfile(language, wasmOpt string, out io.Writer) (map[string]string, error) {
+	var viceroy string
+	if c.viceroyVersioner != nil {
+		viceroy = c.viceroyVersioner.BinaryName()
+	}
+	versions := ToolVersions(language, viceroy)
+	if wasmOpt != "" {
+		if v := wasmOptVersion(wasmOpt); v != "" {
+			versions["wasm-opt"] = v
+		}
+	}
+
+	lock, err := ReadLockfile(LockFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		if c.Flags.Frozen {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("--frozen requires a %s file", LockFilename),
+				Remediation: fmt.Sprintf("Build without --frozen to record the toolchain versions in %s, then commit the file.", LockFilename),
+			}
+		}
+		lock = Lockfile{Language: language, Tools: versions}
+		if err := lock.Write(LockFilename); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", LockFilename, err)
+		}
+		if c.Globals.Verbose() {
+			text.Info(out, "Recorded the toolchain versions in %s", LockFilename)
+		}
+		return versions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", LockFilename, err)
+	}
+
+	drift := lock.Drift(language, versions)
+	if len(drift) > 0 {
+		list := strings.Join(drift, "\n\t")
+		remediation := fmt.Sprintf("Install the versions recorded in %s, or delete the file to record the installed versions.", LockFilename)
+		if c.Flags.Frozen {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("the toolchain versions don't match %s:\n\n\t%s", LockFilename, list),
+				Remediation: remediation,
+			}
+		}
+		text.Warning(out, "The toolchain versions don't match %s:\n\n\t%s\n\n%s", LockFilename, list, remediation)
+		text.Break(out)
+		return versions, nil
+	}
+
+	// Tools installed since the lockfile was created (e.g. Viceroy) are
+	// recorded so later builds can detect when they drift.
+	if !c.Flags.Frozen && lock.Add(versions) {
+		if err := lock.Write(LockFilename); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", LockFilename, err)
+		}
+	}
+	return versions, nil
+}
+
 // includeSourceCode calculates what source code files to include in the final
 // package.tar.gz that is uploaded to the Fastly API.
 //
@@ -308,7 +555,9 @@ func toolchain(c *BuildCommand) (string, error) {
 }
 
 // language returns a pointer to a supported language.
-func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, spinner text.Spinner) (*Language, error) {
+//
+// The hookEnv variables are passed to the [scripts.post_build] hook.
+func language(toolchain string, c *BuildCommand, hookEnv []string, in io.Reader, out io.Writer, spinner text.Spinner) (*Language, error) {
 	var language *Language
 	switch toolchain {
 	case "assemblyscript":
@@ -322,6 +571,7 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 				in,
 				out,
 				spinner,
+				hookEnv,
 			),
 		})
 	case "go":
@@ -335,6 +585,7 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 				in,
 				out,
 				spinner,
+				hookEnv,
 			),
 		})
 	case "javascript":
@@ -348,6 +599,7 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 				in,
 				out,
 				spinner,
+				hookEnv,
 			),
 		})
 	case "rust":
@@ -361,6 +613,7 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 				in,
 				out,
 				spinner,
+				hookEnv,
 			),
 		})
 	case "other":
@@ -373,6 +626,7 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 				in,
 				out,
 				spinner,
+				hookEnv,
 			),
 		})
 	default:
@@ -382,6 +636,17 @@ func language(toolchain string, c *BuildCommand, in io.Reader, out io.Writer, sp
 	return language, nil
 }
 
+// hookEnv returns the environment variables passed to the pre_build and
+// post_build hooks: the path of the package being built and, if the manifest
+// (or --service-id) identifies one, the service ID.
+func (c *BuildCommand) hookEnv(dest string) []string {
+	hookEnv := []string{PackagePathEnvVar + "=" + dest}
+	if serviceID, _ := c.Manifest.ServiceID(); serviceID != "" {
+		hookEnv = append(hookEnv, env.ServiceID+"="+serviceID)
+	}
+	return hookEnv
+}
+
 // binDir ensures a ./bin directory exists.
 // The directory is required so a main.wasm can be placed inside it.
 func binDir(c *BuildCommand) error {
@@ -402,13 +667,20 @@ func binDir(c *BuildCommand) error {
 }
 
 // CreatePackageArchive packages build artifacts as a Fastly package.
-// The package must be a GZipped Tar archive.
+// The package must be a GZipped Tar archive, and is reproducible (see
+// writeArchive).
 //
-// Due to a behavior of archiver.Archive() which recursively writes all files in
-// a provided directory to the archive we first copy our input files to a
-// temporary directory to ensure only the specified files are included and not
-// any in the directory which may be ignored.
+// As writeArchive recursively writes all files in a provided directory to the
+// archive we first copy our input files to a temporary directory to ensure
+// only the specified files are included and not any in the directory which may
+// be ignored.
 func CreatePackageArchive(files []string, destination string) error {
+	return createPackageArchive(files, destination, nil)
+}
+
+// createPackageArchive packages build artifacts as a Fastly package, recording
+// the build info (if any) in the package's fastly.toml manifest.
+func createPackageArchive(files []string, destination string, info *manifest.BuildInfo) error {
 	// Create temporary directory to copy files into.
 	p := make([]byte, 8)
 	n, err := rand.Read(p)
@@ -440,12 +712,13 @@ func CreatePackageArchive(files []string, destination string) error {
 			return fmt.Errorf("error copying file: %w", err)
 		}
 	}
+	if info != nil {
+		if err := writeBuildInfo(dir, info); err != nil {
+			return err
+		}
+	}
 
-	tar := archiver.NewTarGz()
-	tar.OverwriteExisting = true //
-	tar.MkdirAll = true          // make destination directory if it doesn't exist
-
-	return tar.Archive([]string{dir}, destination)
+	return writeArchive(dir, destination)
 }
 
 // FileNameWithoutExtension returns a filename with its extension stripped.
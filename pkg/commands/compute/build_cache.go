@@ -0,0 +1,388 @@
+package compute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/filesystem"
+	toml "github.com/pelletier/go-toml"
+)
+
+// BuildCacheDir is the project directory in which the hash of the last build
+// is recorded, so that `compute build` can skip unchanged projects.
+//
+// NOTE: This is a package level variable as it makes testing the behaviour of
+// the package easier because the test code can replace the value when running
+// the test suite.
+var BuildCacheDir = filepath.Join(".fastly", "cache")
+
+// buildCacheFilename is the file within BuildCacheDir recording the last build.
+const buildCacheFilename = "build.json"
+
+// builtWasmPath is the Wasm binary produced by the build.
+const builtWasmPath = "bin/main.wasm"
+
+// buildCacheVersion is hashed into every key, so changing how builds are
+// produced (or how the key is calculated) invalidates existing caches.
+const buildCacheVersion = "2"
+
+// buildCacheOutputDirs are the directories at the root of the project, or of a
+// source directory outside it, that contain the build output rather than
+// sources. They're only skipped at the root, as e.g. a Go project's pkg/... or
+// a Rust project's src/bin/ directories are sources.
+var buildCacheOutputDirs = map[string]bool{
+	".fastly": true,
+	"bin":     true,
+	"pkg":     true,
+	"target":  true,
+}
+
+// buildCacheSkipDirs are directories that don't affect the build output at any
+// depth, either because they're version control metadata (.git) or because
+// they're too large to hash and are instead represented by a lockfile
+// (node_modules).
+var buildCacheSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// buildCacheSkipFiles are files that are rewritten by the CLI itself and don't
+// affect the build output.
+var buildCacheSkipFiles = map[string]bool{
+	LockFilename:          true,
+	ServeManifestFilename: true,
+}
+
+// buildCacheEnvVars are the environment variables that affect the build
+// output, matched by name or, with a trailing '*', by prefix.
+var buildCacheEnvVars = []string{
+	"CARGO_*",
+	"CGO_*",
+	"GO*",
+	"NODE_ENV",
+	"NODE_OPTIONS",
+	"RUSTC*",
+	"RUSTFLAGS",
+	"RUSTUP_TOOLCHAIN",
+	SourceDateEpochEnvVar,
+}
+
+// buildCache records the inputs and outputs of the last successful build.
+type buildCache struct {
+	Key           string `json:"key"`
+	Package       string `json:"package"`
+	PackageSHA256 string `json:"package_sha256"`
+	WasmSHA256    string `json:"wasm_sha256"`
+}
+
+// buildCacheKey hashes everything that affects the build output: the project
+// files, the sources outside the project that it depends on, the toolchain
+// versions, the build environment variables and the build flags.
+func buildCacheKey(language string, versions map[string]string, flags Flags, packageName string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "version=%s\nlanguage=%s\npackage=%s\ninclude-source=%s\noptimize=%s\n", buildCacheVersion, language, packageName, strconv.FormatBool(flags.IncludeSrc), strconv.FormatBool(flags.Optimize))
+
+	tools := make([]string, 0, len(versions))
+	for t := range versions {
+		// Viceroy runs packages but doesn't build them.
+		if t != "viceroy" {
+			tools = append(tools, t)
+		}
+	}
+	sort.Strings(tools)
+	for _, t := range tools {
+		fmt.Fprintf(h, "tool=%s@%s\n", t, versions[t])
+	}
+
+	for _, kv := range buildCacheEnv(os.Environ()) {
+		fmt.Fprintf(h, "env=%s\n", kv)
+	}
+
+	project, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if err := hashTree(h, project, project); err != nil {
+		return "", err
+	}
+	for _, dir := range externalSources(project) {
+		if err := hashTree(h, dir, project); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// buildCacheEnv returns the environment variables of env (KEY=value) that
+// affect the build output, in sorted order.
+func buildCacheEnv(env []string) []string {
+	var vars []string
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, v := range buildCacheEnvVars {
+			if name == v || (strings.HasSuffix(v, "*") && strings.HasPrefix(name, strings.TrimSuffix(v, "*"))) {
+				vars = append(vars, kv)
+				break
+			}
+		}
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// hashTree hashes the files of the directory root, which are named by their
+// path relative to the project directory.
+//
+// NOTE: The project directory is skipped when it's within root, as it's hashed
+// separately.
+func hashTree(h io.Writer, root, project string) error {
+	// filepath.WalkDir walks in lexical order, so the hash is stable.
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if buildCacheSkipDirs[d.Name()] || path == project || (filepath.Dir(path) == root && buildCacheOutputDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name, err := filepath.Rel(project, path)
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || buildCacheSkipFiles[name] {
+			return nil
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "file=%s %s\n", filepath.ToSlash(name), sum)
+		return nil
+	})
+}
+
+// externalSources returns the directories outside the project that its build
+// reads sources from: the path dependencies of its Cargo.toml, go.mod or
+// package.json, and the Cargo, Go or npm workspace containing it.
+func externalSources(project string) []string {
+	var paths []string
+	if data, err := os.ReadFile(filepath.Join(project, "Cargo.toml")); err == nil {
+		paths = append(paths, cargoPathDeps(data)...)
+		paths = append(paths, findAncestor(project, func(dir string) bool {
+			return fileContains(filepath.Join(dir, "Cargo.toml"), "[workspace]")
+		}))
+	}
+	if data, err := os.ReadFile(filepath.Join(project, "go.mod")); err == nil {
+		paths = append(paths, goModReplacePaths(data)...)
+		paths = append(paths, findAncestor(project, func(dir string) bool {
+			return filesystem.FileExists(filepath.Join(dir, "go.work"))
+		}))
+	}
+	if data, err := os.ReadFile(filepath.Join(project, "package.json")); err == nil {
+		paths = append(paths, packageFileDeps(data)...)
+		paths = append(paths, findAncestor(project, func(dir string) bool {
+			return fileContains(filepath.Join(dir, "package.json"), `"workspaces"`)
+		}))
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(project, p)
+		}
+		p = filepath.Clean(p)
+		if rel, err := filepath.Rel(project, p); err != nil || !strings.HasPrefix(rel, "..") || seen[p] {
+			continue
+		}
+		seen[p] = true
+		dirs = append(dirs, p)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// cargoPathDeps returns the paths of the path dependencies of a Cargo.toml,
+// e.g. `shared = { path = "../shared" }`.
+func cargoPathDeps(data []byte) []string {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil
+	}
+	tables := []*toml.Tree{tree}
+	if target, ok := tree.GetPath([]string{"target"}).(*toml.Tree); ok {
+		for _, k := range target.Keys() {
+			if t, ok := target.GetPath([]string{k}).(*toml.Tree); ok {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	var paths []string
+	for _, t := range tables {
+		for _, section := range []string{"dependencies", "dev-dependencies", "build-dependencies"} {
+			deps, ok := t.GetPath([]string{section}).(*toml.Tree)
+			if !ok {
+				continue
+			}
+			for _, name := range deps.Keys() {
+				if dep, ok := deps.GetPath([]string{name}).(*toml.Tree); ok {
+					if p, ok := dep.GetPath([]string{"path"}).(string); ok {
+						paths = append(paths, p)
+					}
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// goModReplacePaths returns the local paths that modules are replaced with in
+// a go.mod, e.g. `replace example.com/shared => ../shared`.
+func goModReplacePaths(data []byte) []string {
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		_, target, ok := strings.Cut(line, "=>")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(target)
+		if len(fields) == 0 {
+			continue
+		}
+		if p := fields[0]; strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") || filepath.IsAbs(p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// packageFileDeps returns the local paths of the dependencies of a
+// package.json, e.g. `"shared": "file:../shared"`.
+func packageFileDeps(data []byte) []string {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	var paths []string
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+		for _, v := range deps {
+			for _, prefix := range []string{"file:", "link:"} {
+				if strings.HasPrefix(v, prefix) {
+					paths = append(paths, strings.TrimPrefix(v, prefix))
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// findAncestor returns the nearest parent directory of dir that matches, or an
+// empty string if none does.
+func findAncestor(dir string, match func(dir string) bool) string {
+	for parent := filepath.Dir(dir); parent != dir; dir, parent = parent, filepath.Dir(parent) {
+		if match(parent) {
+			return parent
+		}
+	}
+	return ""
+}
+
+// fileContains reports whether the file exists and contains s.
+func fileContains(path, s string) bool {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), s)
+}
+
+// cachedBuild reports whether the last build had the given key and its outputs
+// are unchanged, in which case the build can be skipped.
+func cachedBuild(key, dest string) bool {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(filepath.Join(BuildCacheDir, buildCacheFilename))
+	if err != nil {
+		return false
+	}
+	var bc buildCache
+	if err := json.Unmarshal(data, &bc); err != nil {
+		return false
+	}
+	if bc.Key != key || bc.Package != filepath.ToSlash(dest) {
+		return false
+	}
+	wasm, err := fileSHA256(builtWasmPath)
+	if err != nil || wasm != bc.WasmSHA256 {
+		return false
+	}
+	pkg, err := fileSHA256(dest)
+	return err == nil && pkg == bc.PackageSHA256
+}
+
+// writeBuildCache records the key and outputs of a successful build.
+func writeBuildCache(key, dest string) error {
+	wasm, err := fileSHA256(builtWasmPath)
+	if err != nil {
+		return err
+	}
+	pkg, err := fileSHA256(dest)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(buildCache{
+		Key:           key,
+		Package:       filepath.ToSlash(dest),
+		PackageSHA256: pkg,
+		WasmSHA256:    wasm,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(BuildCacheDir, 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(BuildCacheDir, buildCacheFilename), data, 0o600)
+}
+
+// fileSHA256 returns the hex encoded SHA256 digest of the file's contents.
+func fileSHA256(path string) (string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // #nosec G307
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
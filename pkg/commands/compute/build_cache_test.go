@@ -0,0 +1,175 @@
+package compute_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+func TestBuildCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script is a shell command")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The project replaces a Go module with a directory next to it, whose
+	// sources are outside of the project directory.
+	root := t.TempDir()
+	dir := filepath.Join(root, "package")
+	shared := filepath.Join(root, "shared")
+	for _, d := range []string{dir, shared} {
+		if err := os.Mkdir(d, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// The build script records each run outside of the project, so the runs
+	// don't change the project files.
+	runs := filepath.Join(t.TempDir(), "runs")
+	manifest := `name = "package"
+manifest_version = 2
+language = "other"
+[scripts]
+build = "echo run >> ` + runs + ` && touch ./bin/main.wasm"
+`
+	if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.src"), []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Only the output directories at the project root are skipped, so sources
+	// in e.g. a nested bin directory are hashed.
+	nested := filepath.Join(dir, "src", "bin", "main.src")
+	if err := os.MkdirAll(filepath.Dir(nested), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(nested, []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	gomod := "module example.com/package\n\nreplace example.com/shared => ../shared\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "shared.go"), []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		setup      func(t *testing.T)
+		wantOutput string
+		wantRuns   int
+	}{
+		{
+			name:     "first build",
+			args:     "compute build --auto-yes",
+			wantRuns: 1,
+		},
+		{
+			name:       "unchanged project is cached",
+			args:       "compute build --auto-yes",
+			wantOutput: "Nothing has changed since the last build",
+			wantRuns:   1,
+		},
+		{
+			name: "changed source is rebuilt",
+			args: "compute build --auto-yes",
+			setup: func(t *testing.T) {
+				if err := os.WriteFile(filepath.Join(dir, "main.src"), []byte("v2"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantRuns: 2,
+		},
+		{
+			name: "changed source in a nested bin directory is rebuilt",
+			args: "compute build --auto-yes",
+			setup: func(t *testing.T) {
+				if err := os.WriteFile(nested, []byte("v2"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantRuns: 3,
+		},
+		{
+			name:     "changed flags are rebuilt",
+			args:     "compute build --auto-yes --package-name renamed",
+			wantRuns: 4,
+		},
+		{
+			name: "changed output is rebuilt",
+			args: "compute build --auto-yes --package-name renamed",
+			setup: func(t *testing.T) {
+				if err := os.WriteFile(filepath.Join(dir, "bin", "main.wasm"), []byte("modified"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantRuns: 5,
+		},
+		{
+			name:     "--no-cache is rebuilt",
+			args:     "compute build --auto-yes --package-name renamed --no-cache",
+			wantRuns: 6,
+		},
+		{
+			name: "changed replaced module is rebuilt",
+			args: "compute build --auto-yes --package-name renamed",
+			setup: func(t *testing.T) {
+				if err := os.WriteFile(filepath.Join(shared, "shared.go"), []byte("v2"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantRuns: 7,
+		},
+		{
+			name: "changed build environment is rebuilt",
+			args: "compute build --auto-yes --package-name renamed",
+			setup: func(t *testing.T) {
+				t.Setenv("RUSTFLAGS", "-C opt-level=s")
+			},
+			wantRuns: 8,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.setup != nil {
+				testcase.setup(t)
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertNoError(t, err)
+			testutil.AssertStringContains(t, stdout.String(), "Built package")
+			if testcase.wantOutput != "" {
+				testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			} else {
+				testutil.AssertStringDoesntContain(t, stdout.String(), "Nothing has changed")
+			}
+
+			data, err := os.ReadFile(runs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := strings.Count(string(data), "run"); have != testcase.wantRuns {
+				t.Fatalf("want %d build runs, have %d", testcase.wantRuns, have)
+			}
+		})
+	}
+}
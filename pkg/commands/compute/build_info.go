@@ -0,0 +1,90 @@
+package compute
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/revision"
+	toml "github.com/pelletier/go-toml"
+)
+
+// newBuildInfo returns the build info of a package built from the current
+// directory with the toolchain versions.
+//
+// NOTE: The build time is the modification time of the package archive
+// entries, rather than the current time, so that builds remain reproducible.
+func newBuildInfo(versions map[string]string) (*manifest.BuildInfo, error) {
+	buildTime, err := archiveTime()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &manifest.BuildInfo{
+		BuildTime:  buildTime.Format(time.RFC3339),
+		CLIVersion: revision.AppVersion,
+		GitCommit:  gitCommit(),
+	}
+	if len(versions) > 0 {
+		info.Toolchain = versions
+	}
+	return info, nil
+}
+
+// gitCommit returns the commit of the Git repository containing the current
+// directory, with a "-dirty" suffix if it has uncommitted changes, or an empty
+// string if it isn't a Git repository (or Git isn't installed).
+func gitCommit() string {
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(output))
+	if status, err := exec.Command("git", "status", "--porcelain").Output(); err == nil && len(strings.TrimSpace(string(status))) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// writeBuildInfo appends the build info to the fastly.toml manifest in dir.
+//
+// NOTE: A manifest with existing build info (e.g. extracted from a package)
+// is re-encoded without it, rather than recording the build info twice.
+func writeBuildInfo(dir string, info *manifest.BuildInfo) error {
+	path := filepath.Join(dir, manifest.Filename)
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if tree.Has("build_info") {
+		if err := tree.Delete("build_info"); err != nil {
+			return fmt.Errorf("error removing the existing build info: %w", err)
+		}
+		data = []byte(tree.String())
+	}
+
+	b, err := toml.Marshal(struct {
+		BuildInfo *manifest.BuildInfo `toml:"build_info"`
+	}{info})
+	if err != nil {
+		return fmt.Errorf("error encoding build info: %w", err)
+	}
+	data = append(append(data, '\n'), b...)
+
+	if err := os.WriteFile(path, data, 0o644); err != nil { // #nosec G306
+		return fmt.Errorf("error writing build info: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// WasmOptArgs are the wasm-opt arguments used by --optimize, which optimise
+// for speed while detecting the Wasm features from the binary.
+var WasmOptArgs = []string{"-O"}
+
+// wasmOptRemediation explains how to use a wasm-opt install.
+const wasmOptRemediation = "Install wasm-opt (https://github.com/WebAssembly/binaryen) and set FASTLY_WASM_OPT_PATH to its path."
+
+// optimize runs the wasm-opt binary on the Wasm binary, reporting its size
+// before and after.
+func (c *BuildCommand) optimize(bin string, spinner text.Spinner, out io.Writer) error {
+	before, err := os.Stat(builtWasmPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", builtWasmPath, err)
+	}
+
+	err = spinner.Start()
+	if err != nil {
+		return err
+	}
+	msg := "Optimizing Wasm binary with wasm-opt"
+	spinner.Message(msg + "...")
+
+	args := append(append([]string{}, WasmOptArgs...), builtWasmPath, "-o", builtWasmPath)
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the binary is installed by the CLI (or provided by the user).
+	// #nosec
+	// nosemgrep
+	output, err := exec.Command(bin, args...).CombinedOutput()
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"wasm-opt": bin,
+			"Output":   string(output),
+		})
+		spinner.StopFailMessage(msg)
+		spinErr := spinner.StopFail()
+		if spinErr != nil {
+			return spinErr
+		}
+		return fmt.Errorf("error running wasm-opt: %w\n\n%s", err, output)
+	}
+
+	spinner.StopMessage(msg)
+	err = spinner.Stop()
+	if err != nil {
+		return err
+	}
+
+	after, err := os.Stat(builtWasmPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", builtWasmPath, err)
+	}
+	text.Info(out, "Optimized %s: %s", builtWasmPath, sizeChange(before.Size(), after.Size()))
+	text.Break(out)
+	return nil
+}
+
+// wasmOptVersion returns the version of the wasm-opt binary, or an empty
+// string if it can't be identified.
+//
+// The version output has the format: wasm-opt version 116 (version_116)
+func wasmOptVersion(bin string) string {
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the binary is installed by the CLI (or provided by the user).
+	// #nosec
+	// nosemgrep
+	output, err := exec.Command(bin, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	if match := regexp.MustCompile(`version (\d[^\s]*)`).FindSubmatch(output); len(match) == 2 {
+		return string(match[1])
+	}
+	return ""
+}
+
+// WasmOptDir is the directory, within InstallDir, that the binaryen release
+// providing wasm-opt is installed in (in a subdirectory named after the
+// release version).
+const WasmOptDir = "binaryen-versions"
+
+// wasmOpt returns the path of the wasm-opt binary of the binaryen release
+// pinned by the versioner, which is installed alongside Viceroy on first use.
+// The FASTLY_WASM_OPT_PATH environment variable can be set to use another
+// install.
+func (c *BuildCommand) wasmOpt(spinner text.Spinner) (string, error) {
+	if path := os.Getenv("FASTLY_WASM_OPT_PATH"); path != "" {
+		return filepath.Abs(path)
+	}
+	if c.wasmOptVersioner == nil {
+		return "", fsterr.RemediationError{
+			Inner:       errors.New("wasm-opt can't be installed"),
+			Remediation: wasmOptRemediation,
+		}
+	}
+
+	version, err := c.wasmOptVersioner.Version()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(InstallDir, WasmOptDir, version)
+	bin := filepath.Join(dir, "bin", c.wasmOptVersioner.BinaryName())
+	if filesystem.FileExists(bin) {
+		return bin, nil
+	}
+	if err := installWasmOpt(spinner, c.wasmOptVersioner, version, dir); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return "", fsterr.RemediationError{
+			Inner:       err,
+			Remediation: fmt.Sprintf("%s\n\nAlternatively: %s", fsterr.NetworkRemediation, wasmOptRemediation),
+		}
+	}
+	return bin, nil
+}
+
+// installWasmOpt downloads the binaryen release, which includes wasm-opt, and
+// installs it in dir, replacing any other installed release.
+//
+// NOTE: The entire release is installed, as wasm-opt depends on the binaryen
+// library on some platforms (e.g. macOS).
+func installWasmOpt(spinner text.Spinner, av github.AssetVersioner, version, dir string) error {
+	err := spinner.Start()
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("Fetching wasm-opt (binaryen release %s)", version)
+	spinner.Message(msg + "...")
+
+	tmpBin, err := av.DownloadVersion(version)
+	if err == nil {
+		// The binary is at <tmp>/<release>/bin/wasm-opt.
+		release := filepath.Dir(filepath.Dir(tmpBin))
+		defer os.RemoveAll(filepath.Dir(release))
+
+		if err = os.RemoveAll(filepath.Dir(dir)); err == nil {
+			err = os.MkdirAll(filepath.Dir(dir), 0o750)
+		}
+		if err == nil {
+			if err = os.Rename(release, dir); err != nil {
+				err = copyDir(release, dir)
+			}
+		}
+	}
+	if err != nil {
+		spinner.StopFailMessage(msg)
+		spinErr := spinner.StopFail()
+		if spinErr != nil {
+			return spinErr
+		}
+		return fmt.Errorf("error installing wasm-opt (binaryen release %s): %w", version, err)
+	}
+
+	spinner.StopMessage(msg)
+	return spinner.Stop()
+}
+
+// copyDir copies the directory tree at src to dst, keeping the file modes and
+// symlinks.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o750)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			if err := filesystem.CopyFile(path, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+	})
+}
+
+// sizeChange describes the change of a file's size, e.g.
+// "2.0 MB -> 1.5 MB (-25.0%)".
+func sizeChange(before, after int64) string {
+	var pct float64
+	if before > 0 {
+		pct = float64(after-before) / float64(before) * 100
+	}
+	return fmt.Sprintf("%s -> %s (%+.1f%%)", formatSize(before), formatSize(after), pct)
+}
+
+// formatSize formats a number of bytes with a decimal unit.
+func formatSize(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
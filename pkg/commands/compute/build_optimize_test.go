@@ -0,0 +1,221 @@
+package compute_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+func TestBuildOptimize(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script and fake wasm-opt are shell scripts")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := `name = "package"
+manifest_version = 2
+language = "other"
+[scripts]
+build = "printf abcd > ./bin/main.wasm"
+`
+	if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fake wasm-opt is called as: wasm-opt -O <input> -o <output>
+	tools := t.TempDir()
+	shrinks := filepath.Join(tools, "shrinks")
+	if err := os.WriteFile(shrinks, []byte("#!/bin/sh\nprintf ab > \"$4\"\n"), 0o700); err != nil { // #nosec G306
+		t.Fatal(err)
+	}
+	fails := filepath.Join(tools, "fails")
+	if err := os.WriteFile(fails, []byte("#!/bin/sh\necho '[parse exception: invalid code]'\nexit 1\n"), 0o700); err != nil { // #nosec G306
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name                 string
+		wasmOpt              string
+		wantError            string
+		wantRemediationError string
+		wantOutput           string
+		wantWasm             string
+	}{
+		{
+			name:      "validate wasm-opt failure",
+			wasmOpt:   fails,
+			wantError: "error running wasm-opt: exit status 1\n\n[parse exception: invalid code]",
+		},
+		{
+			name:       "success",
+			wasmOpt:    shrinks,
+			wantOutput: "Optimized bin/main.wasm: 4 B -> 2 B (-50.0%)",
+			wantWasm:   "ab",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			t.Setenv("FASTLY_WASM_OPT_PATH", testcase.wasmOpt)
+
+			var stdout threadsafe.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("compute build --auto-yes --optimize --no-cache"), &stdout)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantWasm != "" {
+				data, err := os.ReadFile(filepath.Join(dir, "bin", "main.wasm"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertString(t, testcase.wantWasm, string(data))
+			}
+		})
+	}
+}
+
+func TestBuildOptimizeDownload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script and fake wasm-opt are shell scripts")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := `name = "package"
+manifest_version = 2
+language = "other"
+[scripts]
+build = "printf abcd > ./bin/main.wasm"
+`
+	if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The release mirrors binaryen's, with wasm-opt in a versioned directory.
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, data := range map[string]string{
+		"binaryen-version_1/bin/wasm-opt":       "#!/bin/sh\n[ \"$1\" = --version ] && echo 'wasm-opt version 1 (version_1)' && exit 0\nprintf ab > \"$4\"\n",
+		"binaryen-version_1/lib/libbinaryen.so": "",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(archive.Bytes())
+	checksum := hex.EncodeToString(sum[:])
+
+	var downloads int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !strings.HasPrefix(r.URL.Path, "/download/"):
+			http.NotFound(w, r)
+		case strings.HasSuffix(r.URL.Path, ".tar.gz.sha256"):
+			fmt.Fprintf(w, "%s  %s\n", checksum, strings.TrimSuffix(path.Base(r.URL.Path), ".sha256"))
+		case strings.HasSuffix(r.URL.Path, ".tar.gz"):
+			downloads++
+			_, _ = w.Write(archive.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	defer func(dir string) {
+		compute.InstallDir = dir
+	}(compute.InstallDir)
+	compute.InstallDir = t.TempDir()
+	t.Setenv("FASTLY_WASM_OPT_PATH", "")
+
+	build := func(releaseURL string) (string, error) {
+		var stdout threadsafe.Buffer
+		opts := testutil.NewRunOpts(testutil.Args("compute build --auto-yes --optimize"), &stdout)
+		opts.Versioners.WasmOpt = github.NewBinaryen(github.BinaryenOpts{
+			HTTPClient: http.DefaultClient,
+			ReleaseURL: releaseURL,
+			Version:    "1",
+		})
+		err := app.Run(opts)
+		t.Log(stdout.String())
+		return stdout.String(), err
+	}
+
+	_, err = build(srv.URL + "/missing/version_%s/binaryen-version_%s-%s.tar.gz")
+	testutil.AssertErrorContains(t, err, "error installing wasm-opt (binaryen release 1): failed to request binaryen release checksum: 404 Not Found")
+	testutil.AssertRemediationErrorContains(t, err, "FASTLY_WASM_OPT_PATH")
+
+	releaseURL := srv.URL + "/download/version_%s/binaryen-version_%s-%s.tar.gz"
+	checksum = strings.Repeat("0", 64)
+	_, err = build(releaseURL)
+	testutil.AssertErrorContains(t, err, "doesn't match the published checksum")
+	if _, err := os.Stat(filepath.Join(compute.InstallDir, compute.WasmOptDir)); err == nil {
+		t.Error("want nothing installed when the checksum doesn't match")
+	}
+	checksum = hex.EncodeToString(sum[:])
+
+	// The second build uses the installed release rather than downloading it.
+	for i := 0; i < 2; i++ {
+		stdout, err := build(releaseURL)
+		testutil.AssertNoError(t, err)
+		if i == 0 {
+			testutil.AssertStringContains(t, stdout, "Optimized bin/main.wasm: 4 B -> 2 B (-50.0%)")
+		}
+	}
+	testutil.AssertEqual(t, 2, downloads)
+	for _, name := range []string{"bin/wasm-opt", "lib/libbinaryen.so"} {
+		if _, err := os.Stat(filepath.Join(compute.InstallDir, compute.WasmOptDir, "1", name)); err != nil {
+			t.Errorf("want %s installed: %v", name, err)
+		}
+	}
+
+	lock, err := compute.ReadLockfile(compute.LockFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "1", lock.Tools["wasm-opt"])
+}
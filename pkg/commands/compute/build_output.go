@@ -0,0 +1,72 @@
+package compute
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// prefixColors are the colors of the output prefixes, chosen by the name of
+// the package so a package keeps its color across builds.
+var prefixColors = []*color.Color{
+	color.New(color.Bold, color.FgCyan),
+	color.New(color.Bold, color.FgMagenta),
+	color.New(color.Bold, color.FgBlue),
+	color.New(color.Bold, color.FgGreen),
+	color.New(color.Bold, color.FgYellow),
+	color.New(color.Bold, color.FgHiCyan),
+	color.New(color.Bold, color.FgHiMagenta),
+	color.New(color.Bold, color.FgHiBlue),
+}
+
+// outputPrefix returns the colorized prefix of the output lines of the named
+// package, e.g. "[api] ".
+func outputPrefix(name string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	c := prefixColors[h.Sum32()%uint32(len(prefixColors))]
+	return c.Sprint("["+name+"]") + " "
+}
+
+// prefixWriter prefixes each line written to w, so the output of several
+// builds or local servers can be told apart.
+type prefixWriter struct {
+	mu     sync.Mutex
+	prefix string
+	w      io.Writer
+	buf    []byte
+}
+
+// Write implements the io.Writer interface.
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s", p.prefix, p.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes any final line that wasn't terminated by a newline.
+func (p *prefixWriter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.buf) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.buf)
+	p.buf = nil
+	return err
+}
@@ -714,6 +714,17 @@ func TestBuildOther(t *testing.T) {
 		wantOutput           []string
 		wantRemediationError string
 	}{
+		{
+			name: "validate --frozen without a lockfile",
+			args: args("compute build --language other --frozen"),
+			fastlyManifest: `
+			manifest_version = 2
+			name = "test"
+			[scripts]
+			build = "touch ./bin/main.wasm"`,
+			wantError:            "--frozen requires a fastly.lock file",
+			wantRemediationError: "Build without --frozen to record the toolchain versions",
+		},
 		{
 			name: "stop build process",
 			args: args("compute build --language other"),
@@ -781,6 +792,36 @@ func TestBuildOther(t *testing.T) {
 			},
 			wantError: "exit status 1", // because we have to trigger an error to see the post_build output
 		},
+		{
+			name: "quiet success hides the compiler output",
+			args: args("compute build --language other --verbose --quiet-success"),
+			fastlyManifest: `
+			manifest_version = 2
+			name = "test"
+			[scripts]
+			build = "printf 'compiling %s\\n' package && touch ./bin/main.wasm"`,
+			wantOutput: []string{
+				"Built package",
+			},
+			dontWantOutput: []string{
+				"Command output:",
+				"compiling package",
+			},
+		},
+		{
+			name: "quiet success displays the compiler output on failure",
+			args: args("compute build --language other --verbose --quiet-success"),
+			fastlyManifest: `
+			manifest_version = 2
+			name = "test"
+			[scripts]
+			build = "printf 'syntax error in %s\\n' package && exit 1"`,
+			wantOutput: []string{
+				"Command output:",
+				"syntax error in package",
+			},
+			wantError: "exit status 1",
+		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			if testcase.fastlyManifest != "" {
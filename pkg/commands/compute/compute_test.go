@@ -26,8 +26,8 @@ func TestPublishFlagDivergence(t *testing.T) {
 	acmd := kingpin.New("foo", "bar")
 
 	rcmd := compute.NewRootCommand(acmd, &g)
-	bcmd := compute.NewBuildCommand(rcmd.CmdClause, &g, data)
-	dcmd := compute.NewDeployCommand(rcmd.CmdClause, &g, data)
+	bcmd := compute.NewBuildCommand(rcmd.CmdClause, &g, nil, nil, data)
+	dcmd := compute.NewDeployCommand(rcmd.CmdClause, &g, bcmd, data)
 	pcmd := compute.NewPublishCommand(rcmd.CmdClause, &g, bcmd, dcmd, data)
 
 	buildFlags := getFlags(bcmd.CmdClause)
@@ -74,7 +74,7 @@ func TestServeFlagDivergence(t *testing.T) {
 	acmd := kingpin.New("foo", "bar")
 
 	rcmd := compute.NewRootCommand(acmd, &cfg)
-	bcmd := compute.NewBuildCommand(rcmd.CmdClause, &cfg, data)
+	bcmd := compute.NewBuildCommand(rcmd.CmdClause, &cfg, versioner, nil, data)
 	scmd := compute.NewServeCommand(rcmd.CmdClause, &cfg, bcmd, versioner, data)
 
 	buildFlags := getFlags(bcmd.CmdClause)
@@ -94,13 +94,33 @@ func TestServeFlagDivergence(t *testing.T) {
 	// We only want to be sure serve contains all build flags.
 	ignoreServeFlags := []string{
 		"addr",
+		"backend",
+		"client-ip",
+		"compose",
+		"compose-up",
 		"debug",
 		"env",
 		"file",
+		"fresh",
+		"live-reload",
+		"override-geo",
+		"package",
+		"persist-stores",
+		"profile-guest",
+		"profile-out",
+		"record",
+		"replay",
 		"skip-build",
+		"tls",
+		"tls-cert",
+		"tls-key",
 		"viceroy-path",
 		"watch",
+		"watch-debounce",
 		"watch-dir",
+		"watch-exclude",
+		"watch-include",
+		"workspace",
 	}
 
 	iter = serveFlags.MapRange()
@@ -3,6 +3,7 @@ package compute
 import (
 	"bytes"
 	"crypto/sha512"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -18,6 +19,7 @@ import (
 	"github.com/fastly/cli/pkg/api/undocumented"
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/commands/compute/setup"
+	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/lookup"
@@ -41,26 +43,66 @@ var PackageSizeLimit int64 = 50000000
 // DeployCommand deploys an artifact previously produced by build.
 type DeployCommand struct {
 	cmd.Base
+	cmd.JSONOutput
 
 	// NOTE: these are public so that the "publish" composite command can set the
 	// values appropriately before calling the Exec() function.
+	Async              bool
+	BackendCheck       bool
 	Comment            cmd.OptionalString
+	Concurrency        int
 	Domain             string
+	FailOnSizeIncrease string
 	Manifest           manifest.Data
 	Package            string
+	ServiceGroup       string
 	ServiceName        cmd.OptionalServiceNameID
 	ServiceVersion     cmd.OptionalServiceVersion
 	StatusCheckCode    int
 	StatusCheckOff     bool
 	StatusCheckPath    string
 	StatusCheckTimeout int
+	SummaryOut         string
+	VerifyKey          string
+	Workspace          string
+
+	build   *BuildCommand
+	summary *DeploySummary
+}
+
+// DeploySummary is a machine-readable summary of a deployment.
+type DeploySummary struct {
+	ServiceID       string           `json:"service_id"`
+	ServiceVersion  int              `json:"service_version"`
+	DeploymentID    string           `json:"deployment_id,omitempty"`
+	NewService      bool             `json:"new_service"`
+	Activated       bool             `json:"activated"`
+	PackageHash     string           `json:"package_hash"`
+	PackageUploaded bool             `json:"package_uploaded"`
+	Domains         []string         `json:"domains"`
+	Created         CreatedResources `json:"created"`
+	BackendChecks   []BackendCheck   `json:"backend_checks,omitempty"`
+	ServiceURL      string           `json:"service_url,omitempty"`
+	StartedAt       time.Time        `json:"started_at"`
+	FinishedAt      time.Time        `json:"finished_at"`
+	DurationSeconds float64          `json:"duration_seconds"`
+}
+
+// CreatedResources lists the service resources created from the fastly.toml
+// [setup] configuration (or from prompts) during a deployment.
+type CreatedResources struct {
+	Domains      []string `json:"domains"`
+	Backends     []string `json:"backends"`
+	Dictionaries []string `json:"dictionaries"`
+	ObjectStores []string `json:"object_stores"`
 }
 
 // NewDeployCommand returns a usable command registered under the parent.
-func NewDeployCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *DeployCommand {
+func NewDeployCommand(parent cmd.Registerer, g *global.Data, build *BuildCommand, m manifest.Data) *DeployCommand {
 	var c DeployCommand
 	c.Globals = g
 	c.Manifest = m
+	c.build = build
 	c.CmdClause = parent.Command("deploy", "Deploy a package to a Fastly Compute@Edge service")
 
 	// NOTE: when updating these flags, be sure to update the composite command:
@@ -83,23 +125,82 @@ func NewDeployCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *D
 		Dst:         &c.ServiceVersion.Value,
 		Name:        cmd.FlagVersionName,
 	})
+	c.CmdClause.Flag("async", "Return as soon as the service version is activated, with a deployment ID to check with `compute deploy-status`, rather than waiting for the service to be available").BoolVar(&c.Async)
+	c.CmdClause.Flag("backend-check", "Check each backend created by [setup] for a new service by requesting its origin, expecting a non-5xx status").BoolVar(&c.BackendCheck)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.Comment.Set).StringVar(&c.Comment.Value)
+	c.CmdClause.Flag("concurrency", "The number of services of a --service-group to deploy to at once").Default("4").IntVar(&c.Concurrency)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").StringVar(&c.Domain)
+	c.CmdClause.Flag("fail-on-size-increase", "Fail if the Wasm binary grew by more than the given percentage (e.g. 10%) since the last deploy").StringVar(&c.FailOnSizeIncrease)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.Package)
+	c.CmdClause.Flag("service-group", fmt.Sprintf("Deploy the package to several existing services concurrently: a comma-separated list of service IDs, or the name of a [service_groups] entry of the %s manifest", manifest.Filename)).StringVar(&c.ServiceGroup)
 	c.CmdClause.Flag("status-check-code", "Set the expected status response for the service availability check").IntVar(&c.StatusCheckCode)
 	c.CmdClause.Flag("status-check-off", "Disable the service availability check").BoolVar(&c.StatusCheckOff)
 	c.CmdClause.Flag("status-check-path", "Specify the URL path for the service availability check").Default("/").StringVar(&c.StatusCheckPath)
 	c.CmdClause.Flag("status-check-timeout", "Set a timeout (in seconds) for the service availability check").Default("120").IntVar(&c.StatusCheckTimeout)
+	c.CmdClause.Flag("summary-out", "Write a JSON summary of the deployment to the given file").StringVar(&c.SummaryOut)
+	c.CmdClause.Flag("verify-key", "Path to an Ed25519 public key (PEM), refusing to deploy a package unless it's signed with its private key").StringVar(&c.VerifyKey)
+	c.CmdClause.Flag("workspace", fmt.Sprintf("Path to a workspace file (e.g. %s) listing packages to build and deploy in dependency order", WorkspaceFilename)).StringVar(&c.Workspace)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: "Render a summary of the deployment as JSON (implies --non-interactive)",
+		Dst:         &c.JSONOutput.Enabled,
+		Short:       'j',
+	})
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.Async && (c.Workspace != "" || c.ServiceGroup != "") {
+		return errAsyncFlags
+	}
+	if c.Workspace != "" {
+		if c.ServiceGroup != "" {
+			return errServiceGroupFlags
+		}
+		return c.deployWorkspace(in, out)
+	}
+	if c.ServiceGroup != "" {
+		return c.deployServiceGroup(in, out)
+	}
+
+	summary := DeploySummary{StartedAt: time.Now().UTC()}
+
+	// NOTE: When rendering JSON, all other output is discarded and so prompts
+	// are disabled (as the user wouldn't be able to see them).
+	summaryOut := out
+	if c.JSONOutput.Enabled {
+		out = io.Discard
+		c.Globals.Flags.NonInteractive = true
+	}
+
+	if c.Globals.Config.Fastly.RequireActivationComment && strings.TrimSpace(c.Comment.Value) == "" {
+		c.Globals.ErrLog.Add(fsterr.ErrActivationCommentRequired)
+		return fsterr.ErrActivationCommentRequired
+	}
+
+	done := c.Globals.Progress.Phase("deploy")
+	defer func() {
+		done(err)
+	}()
+
 	fnActivateTrial, source, serviceID, pkgPath, hashSum, err := setupDeploy(c, out)
 	if err != nil {
 		return err
 	}
 
+	metric, lastMetric, err := c.checkSizeRegression(pkgPath, out)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Package path":          pkgPath,
+			"Fail on size increase": c.FailOnSizeIncrease,
+		})
+		return err
+	}
+
 	undoStack := undo.NewStack()
 	undoStack.Push(func() error {
 		// We'll only clean-up the service if it's a new service.
@@ -113,10 +214,11 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return nil
 	})
 
-	spinner, err := text.NewSpinner(out)
+	s, err := text.NewSpinner(out)
 	if err != nil {
 		return err
 	}
+	spinner := c.Globals.Progress.Spinner(s, "deploy")
 
 	newService, serviceID, serviceVersion, cont, err := serviceManagement(serviceID, source, c, in, out, fnActivateTrial, spinner)
 	if err != nil {
@@ -147,10 +249,30 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		undoStack.RunIfError(out, err)
 	}(c.Globals.ErrLog)
 
-	if err := processSetupCreation(
-		newService, domains, backends, dictionaries, objectStores, spinner, c,
+	// The [setup] resources are created in a phase of their own.
+	setupDone := c.Globals.Progress.Phase("setup")
+	err = processSetupCreation(
+		domains, backends, dictionaries, objectStores, c.Globals.Progress.Spinner(s, "setup"), c,
 		serviceID, serviceVersion.Number,
-	); err != nil {
+	)
+	setupDone(err)
+	if err != nil {
+		return err
+	}
+
+	summary.ServiceID = serviceID
+	summary.ServiceVersion = serviceVersion.Number
+	summary.NewService = newService
+	summary.PackageHash = hashSum
+	summary.Created = createdResources(domains, backends, dictionaries, objectStores)
+
+	hookEnv := []string{
+		env.ServiceID + "=" + serviceID,
+		fmt.Sprintf("%s=%d", ServiceVersionEnvVar, serviceVersion.Number),
+		PackagePathEnvVar + "=" + pkgPath,
+	}
+	preDeploy := hook{name: "pre_deploy", script: c.Manifest.File.Scripts.PreDeploy, env: hookEnv}
+	if err = preDeploy.run(c.Globals, spinner, in, out); err != nil {
 		return err
 	}
 
@@ -161,46 +283,123 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 	if !cont {
-		return nil
+		return c.writeSummary(summaryOut, summary)
 	}
+	summary.PackageUploaded = true
 
 	if err := processService(c, serviceID, serviceVersion.Number, spinner); err != nil {
 		return err
 	}
+	summary.Activated = true
+	summary.DeploymentID = DeploymentID(serviceID, serviceVersion.Number)
+
+	if err := cachePackage(pkgPath, serviceID, serviceVersion.Number); err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Failed to cache the deployed package for `compute rollback`: %s", err)
+	}
 
-	domain, err := getServiceDomain(c.Globals.APIClient, serviceID, serviceVersion.Number)
+	summary.Domains, err = getServiceDomains(c.Globals.APIClient, serviceID, serviceVersion.Number)
 	if err != nil {
 		return err
 	}
 
-	serviceURL := fmt.Sprintf("https://%s", domain)
+	serviceURL := fmt.Sprintf("https://%s", serviceDomain(summary.Domains))
+	summary.ServiceURL = serviceURL
 
-	if !c.StatusCheckOff && newService {
-		var status int
-		if status, err = checkingServiceAvailability(serviceURL+c.StatusCheckPath, spinner, c); err != nil {
-			if re, ok := err.(fsterr.RemediationError); ok {
-				text.Warning(out, re.Remediation)
-			}
-		}
+	// NOTE: The checks wait for the service to propagate, which --async leaves
+	// to `compute deploy-status`.
+	if !c.StatusCheckOff && newService && !c.Async {
+		c.checkAvailability(serviceURL, spinner, out)
+	}
 
-		// Because the service availability can return an error (which we ignore),
-		// then we need to check for the 'no error' scenarios.
-		if err == nil {
-			if validStatusCodeRange(c.StatusCheckCode) && status != c.StatusCheckCode {
-				// If the user set a specific status code expectation...
-				text.Warning(out, "The service path `%s` responded with a status code (%d) that didn't match what was expected (%d).", c.StatusCheckPath, status, c.StatusCheckCode)
-			} else if !validStatusCodeRange(c.StatusCheckCode) && status >= http.StatusBadRequest {
-				// If no status code was specified, and the actual status response was an error...
-				text.Info(out, "The service path `%s` responded with a non-successful status code (%d). Please check your application code if this is an unexpected response.", c.StatusCheckPath, status)
-			}
+	if c.BackendCheck && newService && !c.Async {
+		summary.BackendChecks = checkBackends(backends.Created(), serviceID, serviceVersion.Number, c.Globals.APIClient, c.Globals.HTTPClient, out)
+	}
+
+	if c.Manifest.File.Scripts.PostDeploy != "" {
+		// The service version is active, so a failing post_deploy script mustn't
+		// clean up a new service.
+		for undoStack.Len() > 0 {
+			undoStack.Pop()
+		}
+		postDeploy := hook{
+			name:   "post_deploy",
+			script: c.Manifest.File.Scripts.PostDeploy,
+			env:    append(hookEnv, ServiceURLEnvVar+"="+serviceURL),
+		}
+		if err = postDeploy.run(c.Globals, spinner, in, out); err != nil {
+			re := fsterr.Deduce(err)
+			re.Inner = fmt.Errorf("the package was deployed (service %s, version %d), but %w", serviceID, serviceVersion.Number, re.Inner)
+			return re
 		}
 	}
 
+	metric.DeployedAt = time.Now().UTC()
+	metric.ServiceID = serviceID
+	metric.ServiceVersion = serviceVersion.Number
+	metric.DurationSeconds = metric.DeployedAt.Sub(summary.StartedAt).Seconds()
+	if lastMetric != nil {
+		text.Info(out, "Deploy took %.1fs (%s since last deploy)", metric.DurationSeconds, formatChange(percentChange(lastMetric.DurationSeconds, metric.DurationSeconds)))
+	}
+	if err := recordDeployMetric(metric); err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Failed to record the deploy metrics: %s", err)
+	}
+
 	text.Break(out)
 	text.Description(out, "Manage this service at", fmt.Sprintf("%s%s", manageServiceBaseURL, serviceID))
 	text.Description(out, "View this service at", serviceURL)
 
+	if c.Async {
+		text.Success(out, "Started deployment %s (service %s, version %v)", summary.DeploymentID, serviceID, serviceVersion.Number)
+		text.Info(out, "Check whether the service is available with `fastly compute deploy-status %s`", summary.DeploymentID)
+		return c.writeSummary(summaryOut, summary)
+	}
 	text.Success(out, "Deployed package (service %s, version %v)", serviceID, serviceVersion.Number)
+	return c.writeSummary(summaryOut, summary)
+}
+
+// createdResources returns the names of the resources created by the [setup]
+// process.
+func createdResources(
+	domains *setup.Domains,
+	backends *setup.Backends,
+	dictionaries *setup.Dictionaries,
+	objectStores *setup.ObjectStores,
+) CreatedResources {
+	return CreatedResources{
+		Domains:      domains.Names(),
+		Backends:     backends.Names(),
+		Dictionaries: dictionaries.Names(),
+		ObjectStores: objectStores.Names(),
+	}
+}
+
+// writeSummary completes the deploy summary and writes it to the --summary-out
+// file and/or out (if --json is set).
+func (c *DeployCommand) writeSummary(out io.Writer, summary DeploySummary) error {
+	summary.FinishedAt = time.Now().UTC()
+	summary.DurationSeconds = summary.FinishedAt.Sub(summary.StartedAt).Seconds()
+	if summary.Domains == nil {
+		summary.Domains = []string{}
+	}
+	c.summary = &summary
+
+	if c.SummaryOut != "" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error encoding deploy summary: %w", err)
+		}
+		if err := os.WriteFile(c.SummaryOut, append(data, '\n'), 0o600); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error writing deploy summary: %w", err)
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, summary); ok {
+		return err
+	}
 	return nil
 }
 
@@ -243,6 +442,19 @@ func setupDeploy(c *DeployCommand, out io.Writer) (
 		return defaultActivator, source, serviceID, "", "", err
 	}
 
+	if c.VerifyKey != "" {
+		key, err := readVerifyKey(c.VerifyKey)
+		if err != nil {
+			return defaultActivator, source, serviceID, "", "", err
+		}
+		if err := verifyPackage(pkgPath, hashSum, key); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Package path": pkgPath,
+			})
+			return defaultActivator, source, serviceID, "", "", err
+		}
+	}
+
 	endpoint, _ := c.Globals.Endpoint()
 	fnActivateTrial = preconfigureActivateTrial(endpoint, token, c.Globals.HTTPClient)
 
@@ -811,7 +1023,9 @@ func getHashSum(contents map[string]*bytes.Buffer) (hash string, err error) {
 	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
-// pkgUpload uploads the package to the specified service and version.
+// pkgUpload uploads the package to the specified service and version,
+// displaying the progress of the upload and retrying it if it fails with a
+// transient error.
 func pkgUpload(spinner text.Spinner, client api.Interface, serviceID string, version int, path string) error {
 	err := spinner.Start()
 	if err != nil {
@@ -820,11 +1034,29 @@ func pkgUpload(spinner text.Spinner, client api.Interface, serviceID string, ver
 	msg := "Uploading package"
 	spinner.Message(msg + "...")
 
-	_, err = client.UpdatePackage(&fastly.UpdatePackageInput{
+	attempt := ""
+	lastPct := int64(-1)
+	progress := func(sent, total int64) {
+		// NOTE: The message is only updated when the percentage changes, as
+		// the progress is reported for every write to the connection.
+		if pct := sent * 100 / total; pct != lastPct {
+			lastPct = pct
+			spinner.Message(fmt.Sprintf("%s%s %s", msg, attempt, progressBar(sent, total)))
+		}
+	}
+	retry := func(n int, err error) {
+		attempt = fmt.Sprintf(" (attempt %d of %d)", n, UploadAttempts)
+		lastPct = -1
+		// NOTE: API errors span several lines, so only the first is displayed.
+		reason, _, _ := strings.Cut(err.Error(), "\n")
+		spinner.Message(fmt.Sprintf("%s%s, retrying after error: %s", msg, attempt, strings.TrimSuffix(reason, ":")))
+	}
+
+	err = uploadPackage(client, &fastly.UpdatePackageInput{
 		ServiceID:      serviceID,
 		ServiceVersion: version,
 		PackagePath:    path,
-	})
+	}, progress, retry)
 	if err != nil {
 		spinner.StopFailMessage(msg)
 		spinErr := spinner.StopFail()
@@ -888,50 +1120,74 @@ func constructSetupObjects(
 		return nil, nil, nil, nil, nil, fmt.Errorf("error configuring service domains: %w", err)
 	}
 
-	var (
-		backends     *setup.Backends
-		dictionaries *setup.Dictionaries
-		loggers      *setup.Loggers
-		objectStores *setup.ObjectStores
-	)
+	backends := &setup.Backends{
+		APIClient:      c.Globals.APIClient,
+		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
+		NonInteractive: c.Globals.Flags.NonInteractive,
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+		Setup:          c.Manifest.File.Setup.Backends,
+		Stdin:          in,
+		Stdout:         out,
+	}
 
-	if newService {
-		backends = &setup.Backends{
-			APIClient:      c.Globals.APIClient,
-			AcceptDefaults: c.Globals.Flags.AcceptDefaults,
-			NonInteractive: c.Globals.Flags.NonInteractive,
-			ServiceID:      serviceID,
-			ServiceVersion: serviceVersion,
-			Setup:          c.Manifest.File.Setup.Backends,
-			Stdin:          in,
-			Stdout:         out,
-		}
+	dictionaries := &setup.Dictionaries{
+		APIClient:      c.Globals.APIClient,
+		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
+		NonInteractive: c.Globals.Flags.NonInteractive,
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+		Setup:          c.Manifest.File.Setup.Dictionaries,
+		Stdin:          in,
+		Stdout:         out,
+	}
 
-		dictionaries = &setup.Dictionaries{
-			APIClient:      c.Globals.APIClient,
-			AcceptDefaults: c.Globals.Flags.AcceptDefaults,
-			NonInteractive: c.Globals.Flags.NonInteractive,
-			ServiceID:      serviceID,
-			ServiceVersion: serviceVersion,
-			Setup:          c.Manifest.File.Setup.Dictionaries,
-			Stdin:          in,
-			Stdout:         out,
-		}
+	loggers := &setup.Loggers{
+		Setup:  c.Manifest.File.Setup.Loggers,
+		Stdout: out,
+	}
 
-		loggers = &setup.Loggers{
-			Setup:  c.Manifest.File.Setup.Loggers,
-			Stdout: out,
-		}
+	objectStores := &setup.ObjectStores{
+		APIClient:      c.Globals.APIClient,
+		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
+		NonInteractive: c.Globals.Flags.NonInteractive,
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+		Setup:          c.Manifest.File.Setup.ObjectStores,
+		Stdin:          in,
+		Stdout:         out,
+	}
 
-		objectStores = &setup.ObjectStores{
-			APIClient:      c.Globals.APIClient,
-			AcceptDefaults: c.Globals.Flags.AcceptDefaults,
-			NonInteractive: c.Globals.Flags.NonInteractive,
-			ServiceID:      serviceID,
-			ServiceVersion: serviceVersion,
-			Setup:          c.Manifest.File.Setup.ObjectStores,
-			Stdin:          in,
-			Stdout:         out,
+	// An existing service may already have some of the [setup] resources, which
+	// are then neither prompted for nor created.
+	if !newService {
+		var existing []string
+		for _, r := range []struct {
+			kind     string
+			resource interface {
+				Predefined() bool
+				Validate() error
+				Existing() []string
+			}
+		}{
+			{"backends", backends},
+			{"dictionaries", dictionaries},
+			{"object stores", objectStores},
+		} {
+			if !r.resource.Predefined() {
+				continue
+			}
+			if err := r.resource.Validate(); err != nil {
+				errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion)
+				return nil, nil, nil, nil, nil, fmt.Errorf("error configuring service %s: %w", r.kind, err)
+			}
+			if names := r.resource.Existing(); len(names) > 0 {
+				existing = append(existing, fmt.Sprintf("%s (%s)", r.kind, strings.Join(names, ", ")))
+			}
+		}
+		if len(existing) > 0 && c.Globals.Verbose() {
+			text.Info(out, "The service already has these [setup] resources, so they won't be created: %s", strings.Join(existing, "; "))
+			text.Break(out)
 		}
 	}
 
@@ -957,8 +1213,6 @@ func processSetupConfig(
 		}
 	}
 
-	// IMPORTANT: The pointer refs in this block are not checked for nil.
-	// We presume if we're dealing with newService they have been set.
 	if newService {
 		// NOTE: A service can't be activated without at least one backend defined.
 		// This explains why the following block of code isn't wrapped in a call to
@@ -996,13 +1250,37 @@ func processSetupConfig(
 				return fmt.Errorf("error configuring service object stores: %w", err)
 			}
 		}
+		return nil
+	}
+
+	// For an existing service, only the [setup] resources it doesn't have yet
+	// are configured (see constructSetupObjects).
+	if backends.Predefined() && backends.Missing() {
+		err = backends.Configure()
+		if err != nil {
+			errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion)
+			return fmt.Errorf("error configuring service backends: %w", err)
+		}
+	}
+	if dictionaries.Predefined() && dictionaries.Missing() {
+		err = dictionaries.Configure()
+		if err != nil {
+			errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion)
+			return fmt.Errorf("error configuring service dictionaries: %w", err)
+		}
+	}
+	if objectStores.Predefined() && objectStores.Missing() {
+		err = objectStores.Configure()
+		if err != nil {
+			errLogService(c.Globals.ErrLog, err, serviceID, serviceVersion)
+			return fmt.Errorf("error configuring service object stores: %w", err)
+		}
 	}
 
 	return nil
 }
 
 func processSetupCreation(
-	newService bool,
 	domains *setup.Domains,
 	backends *setup.Backends,
 	dictionaries *setup.Dictionaries,
@@ -1027,45 +1305,43 @@ func processSetupCreation(
 		}
 	}
 
-	// IMPORTANT: The pointer refs in this block are not checked for nil.
-	// We presume if we're dealing with newService they have been set.
-	if newService {
-		backends.Spinner = spinner
-		dictionaries.Spinner = spinner
-		objectStores.Spinner = spinner
+	// NOTE: Only the configured resources are created, which for an existing
+	// service are the [setup] resources it doesn't have yet.
+	backends.Spinner = spinner
+	dictionaries.Spinner = spinner
+	objectStores.Spinner = spinner
 
-		if err := backends.Create(); err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Accept defaults": c.Globals.Flags.AcceptDefaults,
-				"Auto-yes":        c.Globals.Flags.AutoYes,
-				"Non-interactive": c.Globals.Flags.NonInteractive,
-				"Service ID":      serviceID,
-				"Service Version": serviceVersion,
-			})
-			return err
-		}
+	if err := backends.Create(); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Accept defaults": c.Globals.Flags.AcceptDefaults,
+			"Auto-yes":        c.Globals.Flags.AutoYes,
+			"Non-interactive": c.Globals.Flags.NonInteractive,
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion,
+		})
+		return err
+	}
 
-		if err := dictionaries.Create(); err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Accept defaults": c.Globals.Flags.AcceptDefaults,
-				"Auto-yes":        c.Globals.Flags.AutoYes,
-				"Non-interactive": c.Globals.Flags.NonInteractive,
-				"Service ID":      serviceID,
-				"Service Version": serviceVersion,
-			})
-			return err
-		}
+	if err := dictionaries.Create(); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Accept defaults": c.Globals.Flags.AcceptDefaults,
+			"Auto-yes":        c.Globals.Flags.AutoYes,
+			"Non-interactive": c.Globals.Flags.NonInteractive,
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion,
+		})
+		return err
+	}
 
-		if err := objectStores.Create(); err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Accept defaults": c.Globals.Flags.AcceptDefaults,
-				"Auto-yes":        c.Globals.Flags.AutoYes,
-				"Non-interactive": c.Globals.Flags.NonInteractive,
-				"Service ID":      serviceID,
-				"Service Version": serviceVersion,
-			})
-			return err
-		}
+	if err := objectStores.Create(); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Accept defaults": c.Globals.Flags.AcceptDefaults,
+			"Auto-yes":        c.Globals.Flags.AutoYes,
+			"Non-interactive": c.Globals.Flags.NonInteractive,
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion,
+		})
+		return err
 	}
 
 	return nil
@@ -1145,19 +1421,52 @@ func processService(c *DeployCommand, serviceID string, serviceVersion int, spin
 	return spinner.Stop()
 }
 
-func getServiceDomain(apiClient api.Interface, serviceID string, serviceVersion int) (string, error) {
+func getServiceDomains(apiClient api.Interface, serviceID string, serviceVersion int) ([]string, error) {
 	latestDomains, err := apiClient.ListDomains(&fastly.ListDomainsInput{
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	names := make([]string, 0, len(latestDomains))
+	for _, d := range latestDomains {
+		names = append(names, d.Name)
+	}
+	return names, nil
+}
+
+// serviceDomain returns the domain to use for the service URL.
+func serviceDomain(domains []string) string {
+	if len(domains) == 0 {
+		return ""
 	}
-	name := latestDomains[0].Name
+	name := domains[0]
 	if segs := strings.Split(name, "*."); len(segs) > 1 {
 		name = segs[1]
 	}
-	return name, nil
+	return name
+}
+
+// checkAvailability runs the service availability check, warning about a
+// service that isn't available. The check doesn't fail the deployment, as the
+// service version is already active.
+func (c *DeployCommand) checkAvailability(serviceURL string, spinner text.Spinner, out io.Writer) {
+	status, err := checkingServiceAvailability(serviceURL+c.StatusCheckPath, spinner, c)
+	if err != nil {
+		if re, ok := err.(fsterr.RemediationError); ok {
+			text.Warning(out, re.Remediation)
+		}
+		return
+	}
+
+	if validStatusCodeRange(c.StatusCheckCode) && status != c.StatusCheckCode {
+		// If the user set a specific status code expectation...
+		text.Warning(out, "The service path `%s` responded with a status code (%d) that didn't match what was expected (%d).", c.StatusCheckPath, status, c.StatusCheckCode)
+	} else if !validStatusCodeRange(c.StatusCheckCode) && status >= http.StatusBadRequest {
+		// If no status code was specified, and the actual status response was an error...
+		text.Info(out, "The service path `%s` responded with a non-successful status code (%d). Please check your application code if this is an unexpected response.", c.StatusCheckPath, status)
+	}
 }
 
 // checkingServiceAvailability pings the service URL until either there is a
@@ -0,0 +1,434 @@
+package compute
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// ServiceGroupDeploySummary is a machine-readable summary of a deployment to
+// a --service-group.
+type ServiceGroupDeploySummary struct {
+	PackageHash     string                 `json:"package_hash"`
+	Services        []ServiceDeploySummary `json:"services"`
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      time.Time              `json:"finished_at"`
+	DurationSeconds float64                `json:"duration_seconds"`
+}
+
+// ServiceDeploySummary is the outcome of deploying the package to a service of
+// a --service-group.
+type ServiceDeploySummary struct {
+	ServiceID      string `json:"service_id"`
+	ServiceVersion int    `json:"service_version,omitempty"`
+	ServiceURL     string `json:"service_url,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// errServiceGroupFlags is returned when flags that identify a single service
+// are combined with --service-group.
+var errServiceGroupFlags = fsterr.RemediationError{
+	Inner:       errors.New("--service-group can't be used with --service-id, --service-name, --version, --domain or --workspace"),
+	Remediation: "The package is deployed to the active (or else latest) version of each service of the group.",
+}
+
+// errAsyncFlags is returned when --async is combined with a deployment of
+// several packages or services.
+var errAsyncFlags = fsterr.RemediationError{
+	Inner:       errors.New("--async can't be used with --service-group or --workspace"),
+	Remediation: "Deploy each package or service separately with --async to check their deployments with `fastly compute deploy-status`.",
+}
+
+// serviceGroup returns the service IDs of the --service-group, which is either
+// the name of a [service_groups] entry of the manifest or a comma-separated
+// list of service IDs.
+func serviceGroup(group string, m manifest.File) ([]string, error) {
+	ids, ok := m.ServiceGroups[group]
+	if !ok {
+		ids = strings.Split(group, ",")
+	}
+
+	seen := make(map[string]bool, len(ids))
+	var services []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		services = append(services, id)
+	}
+	if len(services) == 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the service group '%s' has no services", group),
+			Remediation: fmt.Sprintf("Provide a comma-separated list of service IDs, or the name of a [service_groups] entry of the %s manifest.", manifest.Filename),
+		}
+	}
+	return services, nil
+}
+
+// deployServiceGroup uploads the same package to each service of the
+// --service-group, deploying to up to --concurrency services at once, and
+// reports the outcome for each service.
+//
+// Each service goes through the same steps as a single service deployment:
+// the pre_deploy hook, the package upload and activation, the availability
+// check and the post_deploy hook, with the package size and duration
+// recorded for each service.
+//
+// NOTE: A service that fails doesn't prevent the package being deployed to
+// the other services, so no service is rolled back.
+func (c *DeployCommand) deployServiceGroup(in io.Reader, out io.Writer) error {
+	if c.Manifest.Flag.ServiceID != "" || c.ServiceName.WasSet || c.ServiceVersion.WasSet || c.Domain != "" || c.Workspace != "" {
+		return errServiceGroupFlags
+	}
+	if c.Concurrency < 1 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --concurrency %d", c.Concurrency),
+			Remediation: "Set --concurrency to the number of services to deploy to at once, e.g. 4.",
+		}
+	}
+	if c.Globals.Config.Fastly.RequireActivationComment && strings.TrimSpace(c.Comment.Value) == "" {
+		c.Globals.ErrLog.Add(fsterr.ErrActivationCommentRequired)
+		return fsterr.ErrActivationCommentRequired
+	}
+
+	services, err := serviceGroup(c.ServiceGroup, c.Manifest.File)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	summary := ServiceGroupDeploySummary{StartedAt: time.Now().UTC()}
+
+	// NOTE: When rendering JSON, only the aggregate summary is written.
+	summaryOut := out
+	if c.JSONOutput.Enabled {
+		out = io.Discard
+		c.Globals.Flags.NonInteractive = true
+	}
+
+	_, _, _, pkgPath, hashSum, err := setupDeploy(c, out)
+	if err != nil {
+		return err
+	}
+	summary.PackageHash = hashSum
+
+	metric, lastMetric, err := c.checkSizeRegression(pkgPath, out)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Package path":          pkgPath,
+			"Fail on size increase": c.FailOnSizeIncrease,
+		})
+		return err
+	}
+
+	// The hooks are confirmed once, rather than for each service, as the
+	// services are deployed concurrently.
+	hooks := groupHooks{
+		preDeploy:  hook{name: "pre_deploy", script: c.Manifest.File.Scripts.PreDeploy},
+		postDeploy: hook{name: "post_deploy", script: c.Manifest.File.Scripts.PostDeploy},
+	}
+	for _, h := range []hook{hooks.preDeploy, hooks.postDeploy} {
+		if h.script == "" {
+			continue
+		}
+		if err := h.confirm(c.Globals, in, out); err != nil {
+			return err
+		}
+	}
+
+	text.Info(out, "Deploying the package to %d services: %s", len(services), strings.Join(services, ", "))
+	text.Break(out)
+
+	// The output of each service is written once it's deployed, so the output
+	// of the services isn't interleaved.
+	var mu sync.Mutex
+	results := make([]groupServiceResult, len(services))
+	sem := make(chan struct{}, c.Concurrency)
+	var wg sync.WaitGroup
+	for i, serviceID := range services {
+		wg.Add(1)
+		go func(i int, serviceID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var buf bytes.Buffer
+			results[i] = c.deployGroupService(serviceID, pkgPath, hooks, &buf)
+
+			mu.Lock()
+			defer mu.Unlock()
+			pw := &prefixWriter{prefix: outputPrefix(serviceID), w: out}
+			_, _ = pw.Write(bytes.TrimLeft(buf.Bytes(), "\n"))
+			_ = pw.Flush()
+		}(i, serviceID)
+	}
+	wg.Wait()
+	text.Break(out)
+
+	var failed int
+	for _, r := range results {
+		s := r.summary
+		summary.Services = append(summary.Services, s)
+		if s.Error != "" {
+			failed++
+			c.Globals.ErrLog.AddWithContext(errors.New(s.Error), map[string]any{
+				"Service ID": s.ServiceID,
+			})
+		}
+		if !r.activated {
+			continue
+		}
+		if err := cachePackage(pkgPath, s.ServiceID, s.ServiceVersion); err != nil {
+			c.Globals.ErrLog.Add(err)
+			text.Warning(out, "Failed to cache the deployed package for `compute rollback`: %s", err)
+		}
+
+		m := metric
+		m.DeployedAt = r.deployedAt
+		m.ServiceID = s.ServiceID
+		m.ServiceVersion = s.ServiceVersion
+		m.DurationSeconds = r.deployedAt.Sub(summary.StartedAt).Seconds()
+		if err := recordDeployMetric(m); err != nil {
+			c.Globals.ErrLog.Add(err)
+			text.Warning(out, "Failed to record the deploy metrics: %s", err)
+		}
+	}
+
+	summary.FinishedAt = time.Now().UTC()
+	summary.DurationSeconds = summary.FinishedAt.Sub(summary.StartedAt).Seconds()
+	if lastMetric != nil {
+		text.Info(out, "Deploy took %.1fs (%s since last deploy)", summary.DurationSeconds, formatChange(percentChange(lastMetric.DurationSeconds, summary.DurationSeconds)))
+	}
+	if err := c.writeServiceGroupSummary(summaryOut, out, summary); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error deploying the service group: %d of %d services weren't deployed", failed, len(services)),
+			Remediation: "Check the error of each failed service above. Re-run the command with a --service-group of the failed services once resolved.",
+		}
+	}
+	text.Success(out, "Deployed package to %d services", len(services))
+	return nil
+}
+
+// groupServiceResult is the outcome of deploying the package to a service of
+// a --service-group.
+type groupServiceResult struct {
+	summary ServiceDeploySummary
+	// activated is whether the service version was activated, even if a later
+	// step failed.
+	activated bool
+	// deployedAt is when the service version was activated.
+	deployedAt time.Time
+}
+
+// groupHooks are the [scripts] hooks run for each service of a
+// --service-group, which the user has already confirmed.
+type groupHooks struct {
+	preDeploy  hook
+	postDeploy hook
+}
+
+// deployGroupService deploys the package to a service of the --service-group,
+// writing its output to out.
+//
+// NOTE: It doesn't write to the command's output or prompt, so that it's safe
+// to call concurrently.
+func (c *DeployCommand) deployGroupService(serviceID, pkgPath string, hooks groupHooks, out io.Writer) (r groupServiceResult) {
+	s := &r.summary
+	s.ServiceID = serviceID
+	s.Status = WorkspaceFailed
+
+	// The spinner is discarded, as the services are deployed concurrently.
+	spinner, err := text.NewSpinner(io.Discard)
+	if err != nil {
+		s.Error = err.Error()
+		return r
+	}
+
+	client := c.Globals.APIClient
+	version, err := cloneServiceVersion(client, serviceID)
+	if err != nil {
+		s.Error = err.Error()
+		return r
+	}
+
+	hookEnv := []string{
+		env.ServiceID + "=" + serviceID,
+		fmt.Sprintf("%s=%d", ServiceVersionEnvVar, version),
+		PackagePathEnvVar + "=" + pkgPath,
+	}
+	preDeploy := hooks.preDeploy
+	preDeploy.env = hookEnv
+	if err := preDeploy.exec(c.Globals, spinner, out); err != nil {
+		s.Error = err.Error()
+		return r
+	}
+
+	if err := activatePackage(client, serviceID, version, pkgPath, c.Comment.Value); err != nil {
+		s.Error = err.Error()
+		return r
+	}
+	r.activated = true
+	r.deployedAt = time.Now().UTC()
+	s.ServiceVersion = version
+
+	domains, err := getServiceDomains(client, serviceID, version)
+	if err != nil {
+		s.Error = fmt.Sprintf("the package was deployed (version %d), but %s", version, err)
+		return r
+	}
+	s.ServiceURL = fmt.Sprintf("https://%s", serviceDomain(domains))
+
+	if !c.StatusCheckOff {
+		c.checkAvailability(s.ServiceURL, spinner, out)
+	}
+
+	postDeploy := hooks.postDeploy
+	postDeploy.env = append(hookEnv, ServiceURLEnvVar+"="+s.ServiceURL)
+	if err := postDeploy.exec(c.Globals, spinner, out); err != nil {
+		s.Error = fmt.Sprintf("the package was deployed (version %d), but %s", version, err)
+		return r
+	}
+
+	text.Success(out, "Deployed package (service %s, version %d)", serviceID, version)
+	s.Status = WorkspaceDeployed
+	return r
+}
+
+// writeServiceGroupSummary displays a table of the services and their
+// outcome, and writes the summary to --summary-out and as --json output if
+// requested.
+func (c *DeployCommand) writeServiceGroupSummary(summaryOut, out io.Writer, summary ServiceGroupDeploySummary) error {
+	services := make([]ServiceDeploySummary, len(summary.Services))
+	copy(services, summary.Services)
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Status > services[j].Status
+	})
+
+	t := text.NewTable(out)
+	t.AddHeader("SERVICE", "STATUS", "VERSION", "ERROR")
+	for _, s := range services {
+		version := ""
+		if s.ServiceVersion > 0 {
+			version = fmt.Sprint(s.ServiceVersion)
+		}
+		t.AddLine(s.ServiceID, s.Status, version, s.Error)
+	}
+	t.Print()
+
+	if c.SummaryOut != "" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error encoding deploy summary: %w", err)
+		}
+		if err := os.WriteFile(c.SummaryOut, append(data, '\n'), 0o600); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error writing deploy summary: %w", err)
+		}
+	}
+
+	if ok, err := c.WriteJSON(summaryOut, summary); ok {
+		return err
+	}
+	return nil
+}
+
+// deployPackage clones the active (or else latest) version of the service,
+// uploads the package to the clone and activates it, returning its number.
+func deployPackage(client api.Interface, serviceID, pkgPath, comment string) (int, error) {
+	version, err := cloneServiceVersion(client, serviceID)
+	if err != nil {
+		return 0, err
+	}
+	if err := activatePackage(client, serviceID, version, pkgPath, comment); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// cloneServiceVersion clones the active (or else latest) version of the
+// service, returning the number of the clone.
+func cloneServiceVersion(client api.Interface, serviceID string) (int, error) {
+	vs, err := client.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error listing service versions: %w", err)
+	}
+	if len(vs) == 0 {
+		return 0, fmt.Errorf("error listing service versions: no versions found")
+	}
+	base := vs[0]
+	for _, v := range vs {
+		if v.Active {
+			base = v
+			break
+		}
+		if v.Number > base.Number {
+			base = v
+		}
+	}
+
+	clone, err := client.CloneVersion(&fastly.CloneVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: base.Number,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error cloning service version %d: %w", base.Number, err)
+	}
+	return clone.Number, nil
+}
+
+// activatePackage uploads the package to the service version and activates
+// it.
+func activatePackage(client api.Interface, serviceID string, version int, pkgPath, comment string) error {
+	err := uploadPackage(client, &fastly.UpdatePackageInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+		PackagePath:    pkgPath,
+	}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error uploading package to version %d: %w", version, err)
+	}
+
+	if comment != "" {
+		_, err = client.UpdateVersion(&fastly.UpdateVersionInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Comment:        &comment,
+		})
+		if err != nil {
+			return fmt.Errorf("error setting comment for service version %d: %w", version, err)
+		}
+	}
+
+	_, err = client.ActivateVersion(&fastly.ActivateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return fmt.Errorf("error activating version %d: %w", version, err)
+	}
+	return nil
+}
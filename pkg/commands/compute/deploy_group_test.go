@@ -0,0 +1,246 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestDeployServiceGroup(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := `manifest_version = 2
+name = "group"
+
+[service_groups]
+regions = ["eu", "us", "apac"]
+`
+	if err := os.WriteFile("fastly.toml", []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("main.wasm", []byte("\x00asm\x01\x00\x00\x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute pack --wasm-binary ./main.wasm"), &stdout)
+	if err := app.Run(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// api fails to upload the package to the services in failing, recording
+	// the service versions that were activated.
+	api := func(activated *sync.Map, failing ...string) mock.API {
+		return mock.API{
+			ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				return []*fastly.Version{
+					{ServiceID: i.ServiceID, Number: 1, Active: true, Locked: true},
+					{ServiceID: i.ServiceID, Number: 2},
+				}, nil
+			},
+			CloneVersionFn: func(i *fastly.CloneVersionInput) (*fastly.Version, error) {
+				if i.ServiceVersion != 1 {
+					return nil, testutil.Err
+				}
+				return &fastly.Version{ServiceID: i.ServiceID, Number: 3}, nil
+			},
+			UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+				for _, id := range failing {
+					if i.ServiceID == id {
+						return nil, testutil.Err
+					}
+				}
+				return &fastly.Package{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion}, nil
+			},
+			UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+				if i.Comment == nil || *i.Comment != "release" {
+					return nil, testutil.Err
+				}
+				return &fastly.Version{ServiceID: i.ServiceID, Number: i.ServiceVersion}, nil
+			},
+			ActivateVersionFn: func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+				activated.Store(i.ServiceID, i.ServiceVersion)
+				return &fastly.Version{ServiceID: i.ServiceID, Number: i.ServiceVersion, Active: true}, nil
+			},
+			ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+				return []*fastly.Domain{{Name: i.ServiceID + ".example.com"}}, nil
+			},
+		}
+	}
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		failing       []string
+		wantError     string
+		wantOutput    []string
+		wantActivated []string
+	}{
+		{
+			name:      "validate incompatible flags",
+			args:      "compute deploy --service-group regions --service-id 123",
+			wantError: "--service-group can't be used with --service-id",
+		},
+		{
+			name:      "validate concurrency",
+			args:      "compute deploy --service-group regions --concurrency 0",
+			wantError: "invalid --concurrency 0",
+		},
+		{
+			name:      "validate empty group",
+			args:      "compute deploy --service-group ,",
+			wantError: "the service group ',' has no services",
+		},
+		{
+			name: "manifest group",
+			args: "compute deploy --service-group regions",
+			wantOutput: []string{
+				"Deploying the package to 3 services: eu, us, apac",
+				"eu       deployed  3",
+				"Deployed package to 3 services",
+			},
+			wantActivated: []string{"apac", "eu", "us"},
+		},
+		{
+			name:          "list of service IDs",
+			args:          "compute deploy --service-group a,b,a --comment release --concurrency 1",
+			wantOutput:    []string{"Deploying the package to 2 services: a, b"},
+			wantActivated: []string{"a", "b"},
+		},
+		{
+			name:          "partial failure",
+			args:          "compute deploy --service-group regions",
+			failing:       []string{"us"},
+			wantError:     "error deploying the service group: 1 of 3 services weren't deployed",
+			wantOutput:    []string{"us       failed", "error uploading package to version 3: " + testutil.Err.Error()},
+			wantActivated: []string{"apac", "eu"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var activated sync.Map
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --package pkg/package.tar.gz --status-check-off --token 123"), &stdout)
+			opts.APIClient = mock.APIClient(api(&activated, testcase.failing...))
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, id := range testcase.wantActivated {
+				if v, ok := activated.Load(id); !ok || v != 3 {
+					t.Errorf("want service %s version 3 activated, have %v", id, v)
+				}
+				if _, err := os.Stat(filepath.Join(compute.HistoryDir, id, "3.tar.gz")); err != nil {
+					t.Errorf("want the package cached for service %s: %v", id, err)
+				}
+			}
+			var n int
+			activated.Range(func(_, _ any) bool { n++; return true })
+			testutil.AssertEqual(t, len(testcase.wantActivated), n)
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var activated sync.Map
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args("compute deploy --service-group regions --json --package pkg/package.tar.gz --status-check-off --token 123"), &stdout)
+		opts.APIClient = mock.APIClient(api(&activated, "apac"))
+		err := app.Run(opts)
+		testutil.AssertErrorContains(t, err, "1 of 3 services weren't deployed")
+
+		var summary compute.ServiceGroupDeploySummary
+		if err := json.Unmarshal(stdout.Bytes(), &summary); err != nil {
+			t.Fatalf("%s: %s", err, stdout.String())
+		}
+		testutil.AssertEqual(t, []compute.ServiceDeploySummary{
+			{ServiceID: "eu", ServiceVersion: 3, ServiceURL: "https://eu.example.com", Status: compute.WorkspaceDeployed},
+			{ServiceID: "us", ServiceVersion: 3, ServiceURL: "https://us.example.com", Status: compute.WorkspaceDeployed},
+			{ServiceID: "apac", Status: compute.WorkspaceFailed, Error: "error uploading package to version 3: " + testutil.Err.Error()},
+		}, summary.Services)
+	})
+
+	// Each service runs the deploy hooks and the availability check, and its
+	// deployment is recorded in the metrics.
+	t.Run("deploy steps", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the hooks are shell scripts")
+		}
+		scripts := manifest + `
+[scripts]
+pre_deploy = "echo pre $FASTLY_SERVICE_ID $FASTLY_SERVICE_VERSION >> hooks.log"
+post_deploy = "echo post $FASTLY_SERVICE_ID $FASTLY_SERVICE_VERSION $FASTLY_SERVICE_URL >> hooks.log"
+`
+		if err := os.WriteFile("fastly.toml", []byte(scripts), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		defer os.WriteFile("fastly.toml", []byte(manifest), 0o600)
+		_ = os.Remove(compute.MetricsFile)
+
+		var checked sync.Map
+		var activated sync.Map
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args("compute deploy --service-group eu,us --auto-yes --package pkg/package.tar.gz --token 123"), &stdout)
+		opts.APIClient = mock.APIClient(api(&activated))
+		opts.HTTPClient = httpClientFunc(func(r *http.Request) (*http.Response, error) {
+			checked.Store(r.URL.String(), true)
+			return &http.Response{
+				Body:       io.NopCloser(strings.NewReader("OK")),
+				Status:     http.StatusText(http.StatusOK),
+				StatusCode: http.StatusOK,
+			}, nil
+		})
+		err := app.Run(opts)
+		t.Log(stdout.String())
+		testutil.AssertNoError(t, err)
+
+		data, err := os.ReadFile("hooks.log")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		sort.Strings(lines)
+		testutil.AssertEqual(t, []string{
+			"post eu 3 https://eu.example.com",
+			"post us 3 https://us.example.com",
+			"pre eu 3",
+			"pre us 3",
+		}, lines)
+
+		for _, url := range []string{"https://eu.example.com/", "https://us.example.com/"} {
+			if _, ok := checked.Load(url); !ok {
+				t.Errorf("want the availability of %s checked", url)
+			}
+		}
+
+		data, err = os.ReadFile(compute.MetricsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []string{`"service_id": "eu"`, `"service_id": "us"`} {
+			testutil.AssertStringContains(t, string(data), id)
+		}
+	})
+}
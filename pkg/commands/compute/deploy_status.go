@@ -0,0 +1,170 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// DeploymentID returns the ID of the deployment of a service version, as
+// returned by `compute deploy --async`.
+func DeploymentID(serviceID string, version int) string {
+	return fmt.Sprintf("%s@%d", serviceID, version)
+}
+
+// parseDeploymentID returns the service ID and version of a deployment ID.
+func parseDeploymentID(id string) (serviceID string, version int, err error) {
+	serviceID, v, ok := strings.Cut(id, "@")
+	if ok {
+		version, err = strconv.Atoi(v)
+	}
+	if !ok || err != nil || serviceID == "" || version < 1 {
+		return "", 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid deployment ID: %s", id),
+			Remediation: "A deployment ID is <service id>@<version>, as returned by `fastly compute deploy --async`.",
+		}
+	}
+	return serviceID, version, nil
+}
+
+// DeployStatus is the status of a deployment.
+type DeployStatus struct {
+	DeploymentID   string `json:"deployment_id"`
+	ServiceID      string `json:"service_id"`
+	ServiceVersion int    `json:"service_version"`
+	// Status is active, superseded (by a later active version) or inactive.
+	Status     string `json:"status"`
+	ServiceURL string `json:"service_url,omitempty"`
+	Available  bool   `json:"available"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// DeployStatusCommand reports the status of a deployment started by
+// `compute deploy --async`.
+type DeployStatusCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	id              string
+	statusCheckCode int
+	statusCheckPath string
+}
+
+// NewDeployStatusCommand returns a usable command registered under the parent.
+func NewDeployStatusCommand(parent cmd.Registerer, g *global.Data) *DeployStatusCommand {
+	var c DeployStatusCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("deploy-status", "Show whether a deployment started by `compute deploy --async` is active and available")
+
+	// required
+	c.CmdClause.Arg("id", "The deployment ID (<service id>@<version>)").Required().StringVar(&c.id)
+
+	// optional
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("status-check-code", "Set the expected status response for the service availability check").IntVar(&c.statusCheckCode)
+	c.CmdClause.Flag("status-check-path", "Specify the URL path for the service availability check").Default("/").StringVar(&c.statusCheckPath)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+//
+// It fails unless the deployed version is active and the service is
+// available, so it can be polled until the deployment has propagated.
+func (c *DeployStatusCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	_, s := c.Globals.Token()
+	if s == lookup.SourceUndefined {
+		return fsterr.ErrNoToken
+	}
+
+	serviceID, version, err := parseDeploymentID(c.id)
+	if err != nil {
+		return err
+	}
+
+	service, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{
+		ID: serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return fmt.Errorf("error fetching service details: %w", err)
+	}
+
+	status := DeployStatus{
+		DeploymentID:   DeploymentID(serviceID, version),
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+		Status:         "inactive",
+	}
+	switch active := service.ActiveVersion.Number; {
+	case service.ActiveVersion.Active && active == version:
+		status.Status = "active"
+	case service.ActiveVersion.Active && active > version:
+		status.Status = "superseded"
+	}
+
+	if status.Status == "active" {
+		domains, err := getServiceDomains(c.Globals.APIClient, serviceID, version)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": version,
+			})
+			return err
+		}
+		status.ServiceURL = fmt.Sprintf("https://%s", serviceDomain(domains))
+		status.Available, status.StatusCode, err = pingServiceURL(status.ServiceURL+c.statusCheckPath, c.Globals.HTTPClient, c.statusCheckCode)
+		if err != nil && c.Globals.Verbose() {
+			text.Info(out, "The service availability check failed: %s", err)
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, status); ok {
+		if err != nil {
+			return err
+		}
+		return status.err()
+	}
+
+	fmt.Fprintf(out, "Deployment: %s\n", status.DeploymentID)
+	fmt.Fprintf(out, "Status: %s\n", status.Status)
+	if status.ServiceURL != "" {
+		fmt.Fprintf(out, "Service URL: %s\n", status.ServiceURL)
+		fmt.Fprintf(out, "Available: %t", status.Available)
+		if status.StatusCode != 0 {
+			fmt.Fprintf(out, " (status: %d)", status.StatusCode)
+		}
+		fmt.Fprintln(out)
+	}
+	if err := status.err(); err != nil {
+		return err
+	}
+	text.Success(out, "Deployed package (service %s, version %d) is active and available", serviceID, version)
+	return nil
+}
+
+// err returns an error unless the deployment is active and available.
+func (s DeployStatus) err() error {
+	if s.Status != "active" {
+		return fmt.Errorf("deployment %s is %s", s.DeploymentID, s.Status)
+	}
+	if !s.Available {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("deployment %s is active but the service isn't available yet", s.DeploymentID),
+			Remediation: "The package might still be propagating across Fastly's global network, so check again shortly.",
+		}
+	}
+	return nil
+}
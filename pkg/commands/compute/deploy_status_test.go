@@ -0,0 +1,99 @@
+package compute_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestDeployStatus(t *testing.T) {
+	getServiceDetails := func(active int) func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{
+				ID:            i.ID,
+				ActiveVersion: fastly.Version{Number: active, Active: true},
+			}, nil
+		}
+	}
+	listDomains := func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return []*fastly.Domain{{Name: "example.com"}}, nil
+	}
+	response := func(status int) []*http.Response {
+		return []*http.Response{{
+			Body:       io.NopCloser(strings.NewReader("")),
+			Status:     http.StatusText(status),
+			StatusCode: status,
+		}}
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		name       string
+		args       []string
+		api        mock.API
+		httpRes    []*http.Response
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:      "validate deployment ID",
+			args:      args("compute deploy-status 123 --token 123"),
+			wantError: "invalid deployment ID: 123",
+		},
+		{
+			name:      "inactive",
+			args:      args("compute deploy-status 123@4 --token 123"),
+			api:       mock.API{GetServiceDetailsFn: getServiceDetails(3)},
+			wantError: "deployment 123@4 is inactive",
+		},
+		{
+			name:      "superseded",
+			args:      args("compute deploy-status 123@4 --token 123"),
+			api:       mock.API{GetServiceDetailsFn: getServiceDetails(5)},
+			wantError: "deployment 123@4 is superseded",
+		},
+		{
+			name: "not yet available",
+			args: args("compute deploy-status 123@4 --token 123"),
+			api: mock.API{
+				GetServiceDetailsFn: getServiceDetails(4),
+				ListDomainsFn:       listDomains,
+			},
+			httpRes:   response(http.StatusServiceUnavailable),
+			wantError: "deployment 123@4 is active but the service isn't available yet",
+		},
+		{
+			name: "success",
+			args: args("compute deploy-status 123@4 --token 123"),
+			api: mock.API{
+				GetServiceDetailsFn: getServiceDetails(4),
+				ListDomainsFn:       listDomains,
+			},
+			httpRes:    response(http.StatusOK),
+			wantOutput: "Deployed package (service 123, version 4) is active and available",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			if testcase.httpRes != nil {
+				opts.HTTPClient = mock.HTMLClient(testcase.httpRes, []error{nil})
+			}
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/errors"
@@ -88,8 +89,11 @@ func TestDeploy(t *testing.T) {
 		// The second is when we ping for service availability.
 		// In this test case the free trial activation isn't used.
 		// So we only define a single HTTP client call for service availability.
-		httpClientRes        []*http.Response
-		httpClientErr        []error
+		httpClientRes []*http.Response
+		httpClientErr []error
+		// httpClient handles the HTTP requests instead of httpClientRes and
+		// httpClientErr, for scenarios whose responses depend on the request.
+		httpClient           api.HTTPClient
 		manifest             string
 		name                 string
 		noManifest           bool
@@ -177,6 +181,31 @@ func TestDeploy(t *testing.T) {
 				"Deployed package (service 12345, version 1)",
 			},
 		},
+		// The service availability check is skipped by --async, so no HTTP client
+		// responses are defined.
+		{
+			name: "async",
+			args: args("compute deploy --token 123 --async"),
+			api: mock.API{
+				ActivateVersionFn: activateVersionOk,
+				CreateBackendFn:   createBackendOK,
+				CreateDomainFn:    createDomainOK,
+				CreateServiceFn:   createServiceOK,
+				GetPackageFn:      getPackageOk,
+				ListDomainsFn:     listDomainsOk,
+				UpdatePackageFn:   updatePackageOk,
+			},
+			stdin: []string{
+				"Y", // when prompted to create a new service
+			},
+			wantOutput: []string{
+				"Started deployment 12345@1 (service 12345, version 1)",
+				"fastly compute deploy-status 12345@1",
+			},
+			dontWantOutput: []string{
+				"Checking service availability",
+			},
+		},
 		{
 			name: "list versions error",
 			args: args("compute deploy --service-id 123 --token 123"),
@@ -509,6 +538,45 @@ func TestDeploy(t *testing.T) {
 				"Deployed package (service 123, version 4)",
 			},
 		},
+		{
+			name: "success with existing service and --json",
+			args: args("compute deploy --service-id 123 --token 123 --json"),
+			api: mock.API{
+				ActivateVersionFn:   activateVersionOk,
+				CloneVersionFn:      testutil.CloneVersionResult(4),
+				GetPackageFn:        getPackageOk,
+				GetServiceFn:        getServiceOK,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+				UpdatePackageFn:     updatePackageOk,
+			},
+			httpClientRes: []*http.Response{
+				{
+					Body:       io.NopCloser(strings.NewReader("success")),
+					Status:     http.StatusText(http.StatusOK),
+					StatusCode: http.StatusOK,
+				},
+			},
+			httpClientErr: []error{
+				nil,
+			},
+			wantOutput: []string{
+				`"service_id": "123"`,
+				`"service_version": 4`,
+				`"new_service": false`,
+				`"activated": true`,
+				`"package_uploaded": true`,
+				`"domains": [
+    "https://directly-careful-coyote.edgecompute.app"
+  ]`,
+				`"duration_seconds": `,
+			},
+			dontWantOutput: []string{
+				"Uploading package",
+				"Deployed package",
+			},
+		},
 		{
 			name: "success with path",
 			args: args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz --version latest"),
@@ -806,6 +874,97 @@ func TestDeploy(t *testing.T) {
 				"Creating domain '",
 			},
 		},
+		{
+			name: "success with setup.backends configuration and --backend-check",
+			args: args("compute deploy --token 123 --non-interactive --backend-check"),
+			api: mock.API{
+				ActivateVersionFn: activateVersionOk,
+				CreateBackendFn:   createBackendOK,
+				CreateDomainFn:    createDomainOK,
+				CreateServiceFn:   createServiceOK,
+				DeleteServiceFn:   deleteServiceOK,
+				GetPackageFn:      getPackageOk,
+				ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+					return []*fastly.Backend{
+						{Name: "foo_backend", UseSSL: true},
+						{Name: "bar_backend"},
+					}, nil
+				},
+				ListDomainsFn:   listDomainsOk,
+				UpdatePackageFn: updatePackageOk,
+			},
+			// Each origin is requested directly, so the backends are reported
+			// individually.
+			httpClient: httpClientFunc(func(r *http.Request) (*http.Response, error) {
+				status := http.StatusOK
+				if r.URL.String() == "http://httpbin.org:8080/" {
+					status = http.StatusServiceUnavailable
+				}
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader(http.StatusText(status))),
+					Status:     http.StatusText(status),
+					StatusCode: status,
+				}, nil
+			}),
+			manifest: `
+			name = "package"
+			manifest_version = 2
+			language = "rust"
+
+			[setup.backends.foo_backend]
+			address = "developer.fastly.com"
+			[setup.backends.bar_backend]
+			address = "httpbin.org"
+			port = 8080
+			`,
+			wantOutput: []string{
+				"Checking the origins of the backends created by [setup]...",
+				"Backend 'foo_backend' (https://developer.fastly.com:443) responded with status 200",
+				"Backend 'bar_backend' (http://httpbin.org:8080) responded with status 503",
+				"1 of 2 backends failed the check",
+				"SUCCESS: Deployed package (service 12345, version 1)",
+			},
+		},
+		{
+			name: "success with setup.backends configuration and a failing --backend-check",
+			args: args("compute deploy --token 123 --non-interactive --backend-check --status-check-off"),
+			api: mock.API{
+				ActivateVersionFn: activateVersionOk,
+				CreateBackendFn:   createBackendOK,
+				CreateDomainFn:    createDomainOK,
+				CreateServiceFn:   createServiceOK,
+				DeleteServiceFn:   deleteServiceOK,
+				GetPackageFn:      getPackageOk,
+				ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+					return []*fastly.Backend{{Name: "foo_backend", UseSSL: true}}, nil
+				},
+				ListDomainsFn:   listDomainsOk,
+				UpdatePackageFn: updatePackageOk,
+			},
+			httpClientRes: []*http.Response{
+				{
+					Body:       io.NopCloser(strings.NewReader("unavailable")),
+					Status:     http.StatusText(http.StatusServiceUnavailable),
+					StatusCode: http.StatusServiceUnavailable,
+				},
+			},
+			httpClientErr: []error{
+				nil,
+			},
+			manifest: `
+			name = "package"
+			manifest_version = 2
+			language = "rust"
+
+			[setup.backends.foo_backend]
+			address = "developr.fastly.com"
+			`,
+			wantOutput: []string{
+				"Backend 'foo_backend' (https://developr.fastly.com:443) responded with status 503",
+				"1 of 1 backends failed the check",
+				"SUCCESS: Deployed package (service 12345, version 1)",
+			},
+		},
 		{
 			name: "success with setup.backends configuration but no fields for the required resources",
 			args: args("compute deploy --token 123"),
@@ -1117,9 +1276,12 @@ func TestDeploy(t *testing.T) {
 				GetPackageFn:        getPackageOk,
 				GetServiceFn:        getServiceOK,
 				GetServiceDetailsFn: getServiceDetailsWasm,
-				ListDomainsFn:       listDomainsOk,
-				ListVersionsFn:      testutil.ListVersions,
-				UpdatePackageFn:     updatePackageOk,
+				ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+					return []*fastly.Backend{{Name: "fastly"}, {Name: "google"}, {Name: "other"}}, nil
+				},
+				ListDomainsFn:   listDomainsOk,
+				ListVersionsFn:  testutil.ListVersions,
+				UpdatePackageFn: updatePackageOk,
 			},
 			httpClientRes: []*http.Response{
 				{
@@ -1149,14 +1311,20 @@ func TestDeploy(t *testing.T) {
 			address = "facebook.com"
 			port = 443
 			`,
+			// The service already has the fastly and google backends, so only the
+			// facebook backend is prompted for and created.
 			wantOutput: []string{
+				"Configure a backend called 'facebook'",
+				"Creating backend 'facebook' (host: facebook.com, port: 443)",
 				"Uploading package",
 				"Activating service",
 				"SUCCESS: Deployed package (service 123, version 4)",
 			},
 			dontWantOutput: []string{
-				"Creating backend 'google' (host: beep.com, port: 123)",
-				"Creating backend 'facebook' (host: boop.com, port: 456)",
+				"Configure a backend called 'fastly'",
+				"Configure a backend called 'google'",
+				"Creating backend 'fastly'",
+				"Creating backend 'google'",
 			},
 		},
 		{
@@ -1169,9 +1337,12 @@ func TestDeploy(t *testing.T) {
 				GetPackageFn:        getPackageOk,
 				GetServiceFn:        getServiceOK,
 				GetServiceDetailsFn: getServiceDetailsWasm,
-				ListDomainsFn:       listDomainsOk,
-				ListVersionsFn:      testutil.ListVersions,
-				UpdatePackageFn:     updatePackageOk,
+				ListDictionariesFn: func(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+					return []*fastly.Dictionary{{Name: "dict_a"}}, nil
+				},
+				ListDomainsFn:   listDomainsOk,
+				ListVersionsFn:  testutil.ListVersions,
+				UpdatePackageFn: updatePackageOk,
 			},
 			httpClientRes: []*http.Response{
 				{
@@ -1211,6 +1382,64 @@ func TestDeploy(t *testing.T) {
 				"Creating dictionary item 'bar'",
 			},
 		},
+		{
+			name: "success with setup.dictionaries configuration and existing service without the dictionary",
+			args: args("compute deploy --service-id 123 --token 123 --verbose"),
+			api: mock.API{
+				ActivateVersionFn:      activateVersionOk,
+				CloneVersionFn:         testutil.CloneVersionResult(4),
+				CreateDictionaryFn:     createDictionaryOK,
+				CreateDictionaryItemFn: createDictionaryItemOK,
+				GetPackageFn:           getPackageOk,
+				GetServiceFn:           getServiceOK,
+				GetServiceDetailsFn:    getServiceDetailsWasm,
+				ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+					return []*fastly.Backend{{Name: "origin"}}, nil
+				},
+				ListDictionariesFn: func(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+					return []*fastly.Dictionary{{Name: "dict_b"}}, nil
+				},
+				ListDomainsFn:   listDomainsOk,
+				ListVersionsFn:  testutil.ListVersions,
+				UpdatePackageFn: updatePackageOk,
+			},
+			httpClientRes: []*http.Response{
+				{
+					Body:       io.NopCloser(strings.NewReader("success")),
+					Status:     http.StatusText(http.StatusOK),
+					StatusCode: http.StatusOK,
+				},
+			},
+			httpClientErr: []error{
+				nil,
+			},
+			manifest: `
+			name = "package"
+			manifest_version = 2
+			language = "rust"
+
+			[setup.backends.origin]
+			address = "example.com"
+			[setup.dictionaries.dict_a]
+			description = "My first dictionary"
+			[setup.dictionaries.dict_a.items.foo]
+			value = "my default value for foo"
+			[setup.dictionaries.dict_b]
+			description = "My second dictionary"
+			`,
+			wantOutput: []string{
+				"The service already has these [setup] resources, so they won't be created: backends (origin); dictionaries (dict_b)",
+				"Configuring dictionary 'dict_a'",
+				"Creating dictionary 'dict_a'",
+				"Creating dictionary item 'foo'",
+				"SUCCESS: Deployed package (service 123, version 4)",
+			},
+			dontWantOutput: []string{
+				"Configure a backend called 'origin'",
+				"Configuring dictionary 'dict_b'",
+				"Creating dictionary 'dict_b'",
+			},
+		},
 		{
 			name: "success with setup.dictionaries configuration and no existing service",
 			args: args("compute deploy --token 123"),
@@ -1588,8 +1817,11 @@ func TestDeploy(t *testing.T) {
 				GetServiceFn:        getServiceOK,
 				GetServiceDetailsFn: getServiceDetailsWasm,
 				ListDomainsFn:       listDomainsOk,
-				ListVersionsFn:      testutil.ListVersions,
-				UpdatePackageFn:     updatePackageOk,
+				ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+					return []*fastly.Resource{{Name: "store_one"}}, nil
+				},
+				ListVersionsFn:  testutil.ListVersions,
+				UpdatePackageFn: updatePackageOk,
 			},
 			httpClientRes: []*http.Response{
 				{
@@ -1841,6 +2073,9 @@ func TestDeploy(t *testing.T) {
 			if testcase.httpClientRes != nil || testcase.httpClientErr != nil {
 				opts.HTTPClient = mock.HTMLClient(testcase.httpClientRes, testcase.httpClientErr)
 			}
+			if testcase.httpClient != nil {
+				opts.HTTPClient = testcase.httpClient
+			}
 
 			if testcase.reduceSizeLimit {
 				compute.PackageSizeLimit = 1000000 // 1mb (our test package should above this)
@@ -1982,3 +2217,10 @@ func listDomainsError(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
 func listDomainsNone(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
 	return []*fastly.Domain{}, nil
 }
+
+// httpClientFunc is an HTTP client whose responses are returned by a function.
+type httpClientFunc func(*http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
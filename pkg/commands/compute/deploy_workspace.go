@@ -0,0 +1,203 @@
+package compute
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The status of a package deployed as part of a workspace.
+const (
+	WorkspaceDeployed = "deployed"
+	WorkspaceFailed   = "failed"
+	WorkspaceSkipped  = "skipped"
+)
+
+// WorkspaceDeploySummary is a machine-readable summary of a workspace
+// deployment.
+type WorkspaceDeploySummary struct {
+	Packages        []WorkspacePackageSummary `json:"packages"`
+	StartedAt       time.Time                 `json:"started_at"`
+	FinishedAt      time.Time                 `json:"finished_at"`
+	DurationSeconds float64                   `json:"duration_seconds"`
+}
+
+// WorkspacePackageSummary is the outcome of deploying a package of a
+// workspace.
+type WorkspacePackageSummary struct {
+	Name   string `json:"name"`
+	Dir    string `json:"dir"`
+	Status string `json:"status"`
+	// Error is why the package failed, or which dependency failed for a
+	// skipped package.
+	Error  string         `json:"error,omitempty"`
+	Deploy *DeploySummary `json:"deploy,omitempty"`
+}
+
+// errWorkspaceFlags is returned when flags that identify a single service or
+// package are combined with --workspace.
+var errWorkspaceFlags = fsterr.RemediationError{
+	Inner:       errors.New("--workspace can't be used with --service-id, --service-name, --version, --package or --domain"),
+	Remediation: "Set the service_id in each package's fastly.toml manifest instead, as each package is deployed to its own service.",
+}
+
+// deployWorkspace builds and deploys each package of the --workspace file in
+// dependency order, sharing the command's other flags between them.
+//
+// A package that fails to deploy causes the packages depending on it to be
+// skipped, but the other packages are still deployed.
+func (c *DeployCommand) deployWorkspace(in io.Reader, out io.Writer) error {
+	if c.Manifest.Flag.ServiceID != "" || c.ServiceName.WasSet || c.ServiceVersion.WasSet || c.Package != "" || c.Domain != "" {
+		return errWorkspaceFlags
+	}
+
+	w, err := ReadWorkspace(c.Workspace)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading --workspace file: %w", err)
+	}
+	packages, err := w.DeployOrder()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	summary := WorkspaceDeploySummary{StartedAt: time.Now().UTC()}
+
+	// NOTE: When rendering JSON, only the aggregate summary is written.
+	summaryOut := out
+	if c.JSONOutput.Enabled {
+		out = io.Discard
+		c.Globals.Flags.NonInteractive = true
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	failed := make(map[string]bool)
+	for _, p := range packages {
+		ps := WorkspacePackageSummary{Name: p.Name, Dir: p.Dir}
+
+		var failedDeps []string
+		for _, d := range p.DependsOn {
+			if failed[d] {
+				failedDeps = append(failedDeps, d)
+			}
+		}
+		if len(failedDeps) > 0 {
+			failed[p.Name] = true
+			ps.Status = WorkspaceSkipped
+			ps.Error = fmt.Sprintf("depends on %s, which wasn't deployed", strings.Join(failedDeps, ", "))
+			summary.Packages = append(summary.Packages, ps)
+			continue
+		}
+
+		text.Info(out, "Deploying package '%s' (%s)", p.Name, p.Dir)
+		text.Break(out)
+
+		ps.Deploy, err = c.deployWorkspacePackage(p, wd, in, out)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			failed[p.Name] = true
+			ps.Status = WorkspaceFailed
+			ps.Error = err.Error()
+			text.Error(out, "Package '%s' failed: %s", p.Name, err)
+		} else {
+			ps.Status = WorkspaceDeployed
+		}
+		text.Break(out)
+		summary.Packages = append(summary.Packages, ps)
+	}
+
+	summary.FinishedAt = time.Now().UTC()
+	summary.DurationSeconds = summary.FinishedAt.Sub(summary.StartedAt).Seconds()
+	if err := c.writeWorkspaceSummary(summaryOut, out, summary); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error deploying the workspace: %d of %d packages weren't deployed", len(failed), len(packages)),
+			Remediation: "Check the output of each failed package above, re-running the command with --verbose for more detail.",
+		}
+	}
+	return nil
+}
+
+// deployWorkspacePackage builds and deploys the package from within its own
+// directory, returning its deployment summary.
+func (c *DeployCommand) deployWorkspacePackage(p WorkspacePackage, wd string, in io.Reader, out io.Writer) (*DeploySummary, error) {
+	if err := os.Chdir(p.Dir); err != nil {
+		return nil, fmt.Errorf("error changing to the package directory: %w", err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	var m manifest.Data
+	m.File.SetErrLog(c.Globals.ErrLog)
+	m.File.SetOutput(out)
+	_ = m.File.Read(manifest.Filename)
+
+	b := *c.build
+	b.Manifest = m
+	b.Flags.OutputPrefix = outputPrefix(p.Name)
+	if err := b.Exec(in, out); err != nil {
+		return nil, err
+	}
+	text.Break(out)
+
+	d := *c
+	d.Manifest = m
+	d.Workspace = ""
+	d.SummaryOut = ""
+	d.JSONOutput.Enabled = false
+	d.summary = nil
+	if err := d.Exec(in, out); err != nil {
+		return nil, err
+	}
+	return d.summary, nil
+}
+
+// writeWorkspaceSummary displays a table of the packages and their outcome,
+// and writes the summary to --summary-out and as --json output if requested.
+func (c *DeployCommand) writeWorkspaceSummary(summaryOut, out io.Writer, summary WorkspaceDeploySummary) error {
+	t := text.NewTable(out)
+	t.AddHeader("PACKAGE", "STATUS", "SERVICE", "VERSION", "URL")
+	for _, p := range summary.Packages {
+		if p.Deploy == nil {
+			t.AddLine(p.Name, p.Status, "", "", "")
+			continue
+		}
+		t.AddLine(p.Name, p.Status, p.Deploy.ServiceID, p.Deploy.ServiceVersion, p.Deploy.ServiceURL)
+	}
+	t.Print()
+
+	if c.SummaryOut != "" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error encoding deploy summary: %w", err)
+		}
+		if err := os.WriteFile(c.SummaryOut, append(data, '\n'), 0o600); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error writing deploy summary: %w", err)
+		}
+	}
+
+	if ok, err := c.WriteJSON(summaryOut, summary); ok {
+		return err
+	}
+	return nil
+}
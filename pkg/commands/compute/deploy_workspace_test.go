@@ -0,0 +1,230 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestWorkspaceDeployOrder(t *testing.T) {
+	for _, testcase := range []struct {
+		name      string
+		packages  []compute.WorkspacePackage
+		wantOrder []string
+		wantError string
+	}{
+		{
+			name: "dependencies first",
+			packages: []compute.WorkspacePackage{
+				{Dir: "services/edge", DependsOn: []string{"api", "auth"}},
+				{Dir: "services/api", DependsOn: []string{"auth"}},
+				{Dir: "services/docs"},
+				{Dir: "services/auth"},
+			},
+			wantOrder: []string{"auth", "api", "edge", "docs"},
+		},
+		{
+			name: "unknown dependency",
+			packages: []compute.WorkspacePackage{
+				{Dir: "services/edge", DependsOn: []string{"api"}},
+			},
+			wantError: "package 'edge' depends on 'api', which isn't a package of the workspace",
+		},
+		{
+			name: "cycle",
+			packages: []compute.WorkspacePackage{
+				{Dir: "services/edge", DependsOn: []string{"api"}},
+				{Dir: "services/api", DependsOn: []string{"auth"}},
+				{Dir: "services/auth", DependsOn: []string{"edge"}},
+			},
+			wantError: "the workspace packages depend on each other in a cycle: edge -> api -> auth -> edge",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			w := compute.Workspace{Packages: testcase.packages}
+			packages, err := w.DeployOrder()
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				return
+			}
+			var order []string
+			for _, p := range packages {
+				order = append(order, p.Name)
+			}
+			testutil.AssertEqual(t, testcase.wantOrder, order)
+		})
+	}
+}
+
+func TestDeployWorkspace(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasm, err := os.ReadFile(filepath.Join(wd, "testdata", "pack", "main.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name         string
+		args         string
+		failingBuild string
+		wantError    string
+		wantOutput   []string
+		wantDeployed []string
+		wantStatus   map[string]string
+	}{
+		{
+			name:      "validate service flags",
+			args:      "compute deploy --workspace " + compute.WorkspaceFilename + " --service-id 123 --token 123",
+			wantError: "--workspace can't be used with --service-id, --service-name, --version, --package or --domain",
+		},
+		{
+			name:      "validate missing workspace file",
+			args:      "compute deploy --workspace missing.toml --token 123",
+			wantError: "error reading --workspace file",
+		},
+		{
+			name: "success",
+			args: "compute deploy --workspace " + compute.WorkspaceFilename + " --token 123",
+			wantOutput: []string{
+				"Deploying package 'api'",
+				"Deploying package 'edge'",
+				"Deploying package 'docs'",
+				"Deployed package (service 222, version 4)",
+			},
+			wantDeployed: []string{"111", "222", "333"},
+		},
+		{
+			name:         "failure skips the dependent packages",
+			args:         "compute deploy --workspace " + compute.WorkspaceFilename + " --token 123 --json --summary-out summary.json",
+			failingBuild: "api",
+			wantError:    "error deploying the workspace: 2 of 3 packages weren't deployed",
+			wantDeployed: []string{"333"},
+			wantStatus: map[string]string{
+				"api":  compute.WorkspaceFailed,
+				"edge": compute.WorkspaceSkipped,
+				"docs": compute.WorkspaceDeployed,
+			},
+		},
+		{
+			name: "prefixes the compiler output of each package",
+			args: "compute deploy --workspace " + compute.WorkspaceFilename + " --token 123 --verbose",
+			wantOutput: []string{
+				"[api] --------",
+				"[edge] --------",
+				"[docs] --------",
+			},
+			wantDeployed: []string{"111", "222", "333"},
+		},
+		{
+			name:         "publish",
+			args:         "compute publish --workspace " + compute.WorkspaceFilename + " --token 123 --status-check-off",
+			wantOutput:   []string{"Built package", "Deployed package (service 333, version 4)"},
+			wantDeployed: []string{"111", "222", "333"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			workspace := `[[packages]]
+dir = "services/edge"
+depends_on = ["api"]
+
+[[packages]]
+dir = "services/api"
+
+[[packages]]
+dir = "services/docs"
+`
+			if err := os.WriteFile(filepath.Join(dir, compute.WorkspaceFilename), []byte(workspace), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			for name, serviceID := range map[string]string{"api": "111", "edge": "222", "docs": "333"} {
+				pkgDir := filepath.Join(dir, "services", name)
+				if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				build := "mkdir -p bin && cp main.wasm bin/main.wasm"
+				if name == testcase.failingBuild {
+					build = "exit 1"
+				}
+				fastlyManifest := fmt.Sprintf(`manifest_version = 2
+name = "%s"
+language = "other"
+service_id = "%s"
+[scripts]
+build = "%s"
+`, name, serviceID, build)
+				if err := os.WriteFile(filepath.Join(pkgDir, manifest.Filename), []byte(fastlyManifest), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(pkgDir, "main.wasm"), wasm, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			var deployed []string
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(mock.API{
+				ActivateVersionFn:   activateVersionOk,
+				CloneVersionFn:      testutil.CloneVersionResult(4),
+				GetPackageFn:        getPackageOk,
+				GetServiceFn:        getServiceOK,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					deployed = append(deployed, i.ServiceID)
+					return updatePackageOk(i)
+				},
+			})
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantDeployed, deployed)
+
+			if testcase.wantStatus != nil {
+				var summary compute.WorkspaceDeploySummary
+				if err := json.Unmarshal(stdout.Bytes(), &summary); err != nil {
+					t.Fatal(err)
+				}
+				data, err := os.ReadFile("summary.json")
+				if err != nil {
+					t.Fatal(err)
+				}
+				var written compute.WorkspaceDeploySummary
+				if err := json.Unmarshal(data, &written); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, summary, written)
+
+				status := make(map[string]string)
+				for _, p := range summary.Packages {
+					status[p.Name] = p.Status
+				}
+				testutil.AssertEqual(t, testcase.wantStatus, status)
+				testutil.AssertString(t, "333", summary.Packages[2].Deploy.ServiceID)
+			}
+		})
+	}
+}
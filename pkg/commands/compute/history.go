@@ -0,0 +1,59 @@
+package compute
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/filesystem"
+)
+
+// HistoryDir is the project directory in which deployed packages are cached,
+// so that `compute rollback` can re-upload them.
+//
+// NOTE: This is a package level variable as it makes testing the behaviour of
+// the package easier because the test code can replace the value when running
+// the test suite.
+var HistoryDir = filepath.Join(".fastly", "history")
+
+// maxHistory is the number of deployed packages cached per service.
+const maxHistory = 10
+
+// historyPackagePath returns the path of the cached package for the given
+// service version.
+func historyPackagePath(serviceID string, version int) string {
+	return filepath.Join(HistoryDir, serviceID, fmt.Sprintf("%d.tar.gz", version))
+}
+
+// cachePackage copies a deployed package into the history cache, removing the
+// oldest cached packages for the service once maxHistory is exceeded.
+func cachePackage(pkgPath, serviceID string, version int) error {
+	if err := filesystem.CopyFile(pkgPath, historyPackagePath(serviceID, version)); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(HistoryDir, serviceID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var versions []int
+	for _, e := range entries {
+		if v, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".tar.gz")); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) <= maxHistory {
+		return nil
+	}
+	sort.Ints(versions)
+	for _, v := range versions[:len(versions)-maxHistory] {
+		if err := os.Remove(historyPackagePath(serviceID, v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	fstexec "github.com/fastly/cli/pkg/exec"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The environment variables passed to the [scripts] hooks, in addition to
+// FASTLY_SERVICE_ID (see env.ServiceID), which the build hooks only get when
+// the service is already known (i.e. from the manifest or --service-id).
+const (
+	// PackagePathEnvVar is the path of the package being built or deployed.
+	PackagePathEnvVar = "FASTLY_PACKAGE_PATH"
+	// ServiceVersionEnvVar is the service version being deployed (deploy hooks
+	// only).
+	ServiceVersionEnvVar = "FASTLY_SERVICE_VERSION"
+	// ServiceURLEnvVar is the URL of the deployed service (post_deploy only).
+	ServiceURLEnvVar = "FASTLY_SERVICE_URL"
+)
+
+// hook is a [scripts] hook defined in the fastly.toml manifest, such as
+// pre_deploy, which runs a shell command at a point of a command's lifecycle.
+type hook struct {
+	// name is the hook's key in the [scripts] section.
+	name string
+	// script is the shell command to run.
+	script string
+	// env is the list of KEY=VALUE environment variables describing the
+	// command's state, added to the CLI's environment.
+	env []string
+}
+
+// run executes the hook, if defined. Unless --auto-yes or --non-interactive
+// is set, the user is asked to confirm the script is safe to run first.
+//
+// A failing script returns an error, stopping the command.
+func (h hook) run(g *global.Data, spinner text.Spinner, in io.Reader, out io.Writer) error {
+	if h.script == "" {
+		return nil
+	}
+	if err := h.confirm(g, in, out); err != nil {
+		return err
+	}
+	return h.exec(g, spinner, out)
+}
+
+// confirm asks the user to confirm the script is safe to run, unless
+// --auto-yes or --non-interactive is set.
+func (h hook) confirm(g *global.Data, in io.Reader, out io.Writer) error {
+	if !g.Flags.AutoYes && !g.Flags.NonInteractive {
+		text.Info(out, "This project has a custom [scripts.%s] script defined in the fastly.toml manifest:\n", h.name)
+		text.Break(out)
+		text.Indent(out, 4, "%s", h.script)
+
+		label := fmt.Sprintf("\nAre you sure you want to run the %s script? [y/N] ", h.name)
+		answer, err := text.AskYesNo(out, label, in)
+		if err != nil {
+			return err
+		}
+		if !answer {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("[scripts.%s] stopped by user", h.name),
+				Remediation: fmt.Sprintf("Check the [scripts.%s] in the fastly.toml manifest is safe to execute or skip this prompt using either `--auto-yes` or `--non-interactive`.", h.name),
+			}
+		}
+		text.Break(out)
+	}
+	return nil
+}
+
+// exec runs the script without asking the user to confirm it.
+func (h hook) exec(g *global.Data, spinner text.Spinner, out io.Writer) error {
+	if h.script == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Running [scripts.%s]", h.name)
+	if !g.Verbose() {
+		if err := spinner.Start(); err != nil {
+			return err
+		}
+		spinner.Message(msg + "...")
+	}
+
+	name, args := Shell{}.Build(h.script)
+	s := fstexec.Streaming{
+		Command:        name,
+		Args:           args,
+		CI:             g.CI(),
+		Env:            h.env,
+		ForceOutput:    g.Verbose(),
+		Output:         out,
+		Spinner:        spinner,
+		SpinnerMessage: msg,
+		Verbose:        g.Verbose(),
+	}
+	if err := s.Exec(); err != nil {
+		g.ErrLog.AddWithContext(err, map[string]any{
+			"Script": h.script,
+		})
+		// NOTE: In non-verbose mode the spinner is stopped by Streaming.Exec().
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("[scripts.%s] failed: %w", h.name, err),
+			Remediation: fmt.Sprintf("Check the [scripts.%s] in the fastly.toml manifest, re-running the command with --verbose to see the script's output as it runs.", h.name),
+		}
+	}
+
+	if g.Verbose() {
+		return nil
+	}
+	spinner.StopMessage(msg)
+	return spinner.Stop()
+}
@@ -0,0 +1,218 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestBuildHooks(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Write: []testutil.FileIO{
+			{Src: "mock content", Dst: "bin/testfile"},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	for _, testcase := range []struct {
+		name                 string
+		args                 string
+		preBuild             string
+		postBuild            string
+		serviceID            string
+		stdin                string
+		wantError            string
+		wantRemediationError string
+		wantOutput           []string
+		wantHookOutput       string
+	}{
+		{
+			name:       "prompt declined",
+			args:       "compute build --language other --no-cache",
+			preBuild:   "echo $FASTLY_PACKAGE_PATH > hooks.txt",
+			stdin:      "N",
+			wantOutput: []string{"Are you sure you want to run the pre_build script?"},
+			wantError:  "[scripts.pre_build] stopped by user",
+		},
+		{
+			name:                 "failure",
+			args:                 "compute build --language other --no-cache --auto-yes",
+			preBuild:             "echo generating code && exit 1",
+			wantOutput:           []string{"generating code"},
+			wantError:            "[scripts.pre_build] failed",
+			wantRemediationError: "Check the [scripts.pre_build] in the fastly.toml manifest",
+		},
+		{
+			name:           "success",
+			args:           "compute build --language other --auto-yes",
+			preBuild:       "echo $FASTLY_PACKAGE_PATH > hooks.txt",
+			wantOutput:     []string{"Running [scripts.pre_build]", "Built package"},
+			wantHookOutput: filepath.Join("pkg", "test.tar.gz"),
+		},
+		{
+			name:           "success with a cached build",
+			args:           "compute build --language other --auto-yes",
+			preBuild:       "echo $FASTLY_PACKAGE_PATH > hooks.txt",
+			wantOutput:     []string{"Running [scripts.pre_build]", "Nothing has changed since the last build"},
+			wantHookOutput: filepath.Join("pkg", "test.tar.gz"),
+		},
+		{
+			name:           "post_build environment",
+			args:           "compute build --language other --no-cache --auto-yes",
+			preBuild:       "echo $FASTLY_SERVICE_ID $FASTLY_PACKAGE_PATH > hooks.txt",
+			postBuild:      "echo $FASTLY_SERVICE_ID $FASTLY_PACKAGE_PATH >> hooks.txt",
+			serviceID:      "123",
+			wantOutput:     []string{"Running [scripts.post_build]", "Built package"},
+			wantHookOutput: "123 " + filepath.Join("pkg", "test.tar.gz") + "\n123 " + filepath.Join("pkg", "test.tar.gz"),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			os.Remove("hooks.txt")
+			fastlyManifest := `
+			manifest_version = 2
+			name = "test"
+			service_id = "` + testcase.serviceID + `"
+			[scripts]
+			build = "touch ./bin/main.wasm"
+			pre_build = "` + testcase.preBuild + `"
+			post_build = "` + testcase.postBuild + `"`
+			if err := os.WriteFile(manifest.Filename, []byte(fastlyManifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			hookOutput, _ := os.ReadFile("hooks.txt")
+			testutil.AssertString(t, testcase.wantHookOutput, strings.TrimSpace(string(hookOutput)))
+		})
+	}
+}
+
+func TestDeployHooks(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasm, err := os.ReadFile(filepath.Join(wd, "testdata", "pack", "main.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.WriteFile("main.wasm", wasm, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		preDeploy  = `echo $FASTLY_SERVICE_ID $FASTLY_SERVICE_VERSION $FASTLY_PACKAGE_PATH > hooks.txt`
+		postDeploy = `echo $FASTLY_SERVICE_URL >> hooks.txt`
+	)
+	for _, testcase := range []struct {
+		name                 string
+		preDeploy            string
+		postDeploy           string
+		wantError            string
+		wantRemediationError string
+		wantHookOutput       string
+		wantUploaded         bool
+	}{
+		{
+			name:                 "pre_deploy failure",
+			preDeploy:            "exit 1",
+			postDeploy:           postDeploy,
+			wantError:            "[scripts.pre_deploy] failed",
+			wantRemediationError: "Check the [scripts.pre_deploy] in the fastly.toml manifest",
+		},
+		{
+			name:           "post_deploy failure",
+			preDeploy:      preDeploy,
+			postDeploy:     "exit 1",
+			wantError:      "the package was deployed (service 123, version 3), but [scripts.post_deploy] failed",
+			wantUploaded:   true,
+			wantHookOutput: "123 3 pkg/package.tar.gz",
+		},
+		{
+			name:           "success",
+			preDeploy:      preDeploy,
+			postDeploy:     postDeploy,
+			wantHookOutput: "123 3 pkg/package.tar.gz\nhttps://www.example.com",
+			wantUploaded:   true,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			os.Remove("hooks.txt")
+			fastlyManifest := `manifest_version = 2
+name = "hooks"
+[scripts]
+pre_deploy = "` + testcase.preDeploy + `"
+post_deploy = "` + testcase.postDeploy + `"
+`
+			if err := os.WriteFile(manifest.Filename, []byte(fastlyManifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var uploaded bool
+			api := mock.API{
+				ActivateVersionFn:   activateVersionOk,
+				GetPackageFn:        getPackageOk,
+				GetServiceFn:        getServiceOK,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+					return []*fastly.Domain{{Name: "www.example.com"}}, nil
+				},
+				ListVersionsFn: testutil.ListVersions,
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					uploaded = true
+					return updatePackageOk(i)
+				},
+			}
+			run := func(args string) error {
+				var stdout bytes.Buffer
+				opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+				opts.APIClient = mock.APIClient(api)
+				err := app.Run(opts)
+				t.Log(stdout.String())
+				return err
+			}
+			if err := run("compute pack --wasm-binary ./main.wasm"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := run("compute deploy --service-id 123 --token 123 --version latest --non-interactive --package pkg/package.tar.gz")
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			testutil.AssertBool(t, testcase.wantUploaded, uploaded)
+			hookOutput, _ := os.ReadFile("hooks.txt")
+			testutil.AssertString(t, testcase.wantHookOutput, strings.TrimSpace(string(hookOutput)))
+		})
+	}
+}
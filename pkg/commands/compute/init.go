@@ -29,6 +29,7 @@ import (
 
 var (
 	gitRepositoryRegEx        = regexp.MustCompile(`((git|ssh|http(s)?)|(git@[\w\.]+))(:(//)?)([\w\.@\:/\-~]+)(\.git)?(/)?`)
+	gitSCPLikeRegEx           = regexp.MustCompile(`^[\w\.\-]+@[\w\.\-]+:[^/]`)
 	fastlyOrgRegEx            = regexp.MustCompile(`^https:\/\/github\.com\/fastly`)
 	fastlyFileIgnoreListRegEx = regexp.MustCompile(`\.github|LICENSE|SECURITY\.md|CHANGELOG\.md|screenshot\.png`)
 )
@@ -42,6 +43,7 @@ type InitCommand struct {
 	cloneFrom string
 	language  string
 	manifest  manifest.Data
+	ref       string
 	tag       string
 }
 
@@ -57,7 +59,8 @@ func NewInitCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Ini
 	c.CmdClause.Flag("directory", "Destination to write the new package, defaulting to the current directory").Short('p').StringVar(&c.dir)
 	c.CmdClause.Flag("author", "Author(s) of the package").Short('a').StringsVar(&c.manifest.File.Authors)
 	c.CmdClause.Flag("language", "Language of the package").Short('l').HintOptions(Languages...).EnumVar(&c.language, Languages...)
-	c.CmdClause.Flag("from", "Local project directory, or Git repository URL, or URL referencing a .zip/.tar.gz file, containing a package template").Short('f').StringVar(&c.cloneFrom)
+	c.CmdClause.Flag("from", "Local project directory, or Git repository URL (including SSH URLs for private repositories), or URL referencing a .zip/.tar.gz file, containing a package template").Short('f').StringVar(&c.cloneFrom)
+	c.CmdClause.Flag("ref", "Git branch or tag to clone from the package template repository").StringVar(&c.ref)
 	c.CmdClause.Flag("branch", "Git branch name to clone from package template repository").Hidden().StringVar(&c.branch)
 	c.CmdClause.Flag("tag", "Git tag name to clone from package template repository").Hidden().StringVar(&c.tag)
 
@@ -80,6 +83,13 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		text.Warning(out, "When using the --from flag, the project language cannot be inferred. Please either use the --language flag to explicitly set the language or ensure the project's fastly.toml sets a valid language.")
 	}
 
+	if c.ref != "" && (c.branch != "" || c.tag != "") {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--ref can't be used with --branch or --tag"),
+			Remediation: "Use --ref to set the branch or tag to clone.",
+		}
+	}
+
 	text.Break(out)
 
 	cont, err := verifyDirectory(c.Globals.Flags, c.dir, out, in)
@@ -130,6 +140,15 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 	c.dir = dst
 
+	defaults, err := loadInitDefaults(c.Globals.Config.ComputeInit)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Template": c.Globals.Config.ComputeInit.Template,
+		})
+		return err
+	}
+	defer defaults.cleanup()
+
 	// Assign the default profile email if available.
 	email := ""
 	profileName, p := profile.Default(c.Globals.Config.Profiles)
@@ -145,8 +164,17 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		})
 		return err
 	}
+	if err := defaults.checkRequiredFields(desc, authors); err != nil {
+		return err
+	}
 
-	languages := NewLanguages(c.Globals.Config.StarterKits)
+	// The additional [compute-init] starter kits are only needed when the user
+	// will be prompted to select a starter kit.
+	kits := c.Globals.Config.StarterKits
+	if c.cloneFrom == "" && !mf.Exists() && c.language != "other" {
+		kits = defaults.starterKits(kits, c.Globals.HTTPClient, out)
+	}
+	languages := NewLanguages(kits)
 
 	var language *Language
 
@@ -186,6 +214,14 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		c.cloneFrom = from
 	}
 
+	// The flags override the starter kit's branch/tag.
+	switch {
+	case c.ref != "":
+		branch, tag = c.ref, ""
+	case c.branch != "" || c.tag != "":
+		branch, tag = c.branch, c.tag
+	}
+
 	// We only want to fetch a remote package if c.cloneFrom has been set.
 	// This can happen in two ways:
 	//
@@ -218,6 +254,13 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	if err := defaults.apply(c.dir, out); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Directory": c.dir,
+		})
+		return err
+	}
+
 	language, err = initializeLanguage(spinner, language, languages, mf.Language, wd, c.dir)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -641,6 +684,22 @@ func fetchPackageTemplate(
 		return spinner.Stop()
 	}
 
+	// Repositories that are only accessible using git (e.g. with SSH
+	// authentication) are cloned without trying a HTTP request first.
+	if requiresGit(c.cloneFrom) {
+		if err := clonePackageFromEndpoint(c.cloneFrom, branch, tag, c.dir); err != nil {
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+			return err
+		}
+
+		spinner.StopMessage(msg)
+		return spinner.Stop()
+	}
+
 	req, err := http.NewRequest("GET", c.cloneFrom, nil)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -691,6 +750,20 @@ func fetchPackageTemplate(
 		err := fmt.Errorf("failed to get package: %s", res.Status)
 		c.Globals.ErrLog.Add(err)
 
+		// A private repository isn't accessible to an anonymous HTTP request, but
+		// might be to git using a credential helper.
+		if isAuthStatus(res.StatusCode) && gitRepositoryRegEx.MatchString(c.cloneFrom) {
+			cloneErr := clonePackageFromEndpoint(c.cloneFrom, branch, tag, c.dir)
+			if cloneErr == nil {
+				spinner.StopMessage(msg)
+				return spinner.Stop()
+			}
+			c.Globals.ErrLog.Add(cloneErr)
+			re := fsterr.Deduce(cloneErr)
+			re.Inner = fmt.Errorf("%s (and cloning it with git failed: %w)", err, re.Inner)
+			err = re
+		}
+
 		spinner.StopFailMessage(msg)
 		spinErr := spinner.StopFail()
 		if spinErr != nil {
@@ -879,11 +952,15 @@ func clonePackageFromEndpoint(
 	// Disabling as there should be no vulnerability to cloning a remote repo.
 	/* #nosec */
 	c := exec.Command("git", args...)
+	c.Env = gitEnv()
 
 	// nosemgrep (invalid-usage-of-modified-variable)
 	stdoutStderr, err := c.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("error fetching package template: %w\n\n%s", err, stdoutStderr)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error fetching package template: %w\n\n%s", err, stdoutStderr),
+			Remediation: gitCloneRemediation,
+		}
 	}
 
 	if err := os.RemoveAll(filepath.Join(tempdir, ".git")); err != nil {
@@ -924,6 +1001,44 @@ func clonePackageFromEndpoint(
 	return nil
 }
 
+// gitCloneRemediation explains how git authenticates to a private repository.
+var gitCloneRemediation = strings.Join([]string{
+	"Check the repository and the branch or tag (see --ref) exist.",
+	"For a private repository, git must be able to authenticate without prompting:",
+	"use an SSH URL (e.g. git@github.com:org/repo.git) with a key loaded into your SSH agent (see `ssh-add -l`),",
+	"or a HTTPS URL with a Git credential helper configured (see `git config --global credential.helper`).",
+}, " ")
+
+// requiresGit indicates the package template is a repository that can only be
+// cloned using git, rather than fetched with a HTTP request.
+func requiresGit(from string) bool {
+	for _, scheme := range []string{"ssh://", "git+ssh://", "ssh+git://", "git://", "file://"} {
+		if strings.HasPrefix(from, scheme) {
+			return true
+		}
+	}
+	// e.g. git@github.com:org/repo.git
+	return gitSCPLikeRegEx.MatchString(from)
+}
+
+// isAuthStatus indicates the HTTP status is a response to a request lacking
+// authentication, which for some hosts (e.g. GitHub) is 404 Not Found.
+func isAuthStatus(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound
+}
+
+// gitEnv returns the environment git is run with.
+//
+// Prompts are disabled, as the spinner hides them, and so a private repository
+// is cloned using the credentials of an SSH agent or a Git credential helper.
+func gitEnv() []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" && os.Getenv("GIT_SSH") == "" {
+		env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	return env
+}
+
 func tempDir(prefix string) (abspath string, err error) {
 	abspath, err = filepath.Abs(filepath.Join(
 		os.TempDir(),
@@ -0,0 +1,249 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// InitDefaultsFilename is the file within a [compute-init] template that sets
+// defaults alongside those of the CLI config (with the same fields, and a
+// license path relative to the template).
+const InitDefaultsFilename = "fastly-init.toml"
+
+// initRequiredFields are the fastly.toml fields that [compute-init] can
+// require, and whether they're set.
+var initRequiredFields = map[string]func(desc string, authors []string) bool{
+	"authors": func(_ string, authors []string) bool {
+		for _, a := range authors {
+			if a != "" {
+				return true
+			}
+		}
+		return false
+	},
+	"description": func(desc string, _ []string) bool { return desc != "" },
+}
+
+// initDefaults are the organisation defaults applied by `compute init`.
+type initDefaults struct {
+	config.ComputeInit
+
+	// templateDir is the directory of the template (if any).
+	templateDir string
+	// fetched indicates templateDir is a temporary clone of the template.
+	fetched bool
+}
+
+// loadInitDefaults fetches the [compute-init] template (if any) and merges its
+// defaults with those of the CLI config, which take precedence.
+//
+// NOTE: The caller must call cleanup() to remove a fetched template.
+func loadInitDefaults(c config.ComputeInit) (d initDefaults, err error) {
+	d.ComputeInit = c
+	defer func() {
+		if err != nil {
+			d.cleanup()
+		}
+	}()
+
+	if c.Template != "" {
+		if fi, err := os.Stat(c.Template); err == nil && fi.IsDir() {
+			d.templateDir = c.Template
+		} else {
+			d.templateDir, err = tempDir("package-init-defaults")
+			if err != nil {
+				return d, fmt.Errorf("error creating temporary path for [compute-init] template: %w", err)
+			}
+			d.fetched = true
+			if err := clonePackageFromEndpoint(c.Template, "", "", d.templateDir); err != nil {
+				return d, fmt.Errorf("error fetching [compute-init] template: %w", err)
+			}
+		}
+		if err := d.mergeTemplateDefaults(); err != nil {
+			return d, err
+		}
+	}
+
+	return d, validateRequiredFields(d.RequiredFields)
+}
+
+// mergeTemplateDefaults merges the template's InitDefaultsFilename (if any)
+// into the defaults that aren't set by the CLI config.
+func (d *initDefaults) mergeTemplateDefaults() error {
+	path := filepath.Join(d.templateDir, InitDefaultsFilename)
+	if !filesystem.FileExists(path) {
+		return nil
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the template.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var t config.ComputeInit
+	if err := toml.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("error parsing %s of [compute-init] template: %w", InitDefaultsFilename, err)
+	}
+	if len(d.CodeOwners) == 0 {
+		d.CodeOwners = t.CodeOwners
+	}
+	if d.License == "" && t.License != "" {
+		d.License = filepath.Join(d.templateDir, t.License)
+	}
+	d.RequiredFields = append(d.RequiredFields, t.RequiredFields...)
+	d.StarterKitIndexes = append(d.StarterKitIndexes, t.StarterKitIndexes...)
+	d.StarterKits = d.StarterKits.Append(t.StarterKits)
+	return nil
+}
+
+// starterKits returns the official starter kits followed by the additional
+// starter kits of the defaults and their indexes.
+//
+// NOTE: An index that can't be fetched only produces a warning, so the
+// official starter kits remain usable.
+func (d initDefaults) starterKits(official config.StarterKitLanguages, client api.HTTPClient, out io.Writer) config.StarterKitLanguages {
+	kits := official.Append(d.StarterKits)
+	for _, url := range d.StarterKitIndexes {
+		index, err := fetchStarterKitIndex(url, client)
+		if err != nil {
+			text.Warning(out, "Failed to fetch the [compute-init] starter kit index %s: %s", url, err)
+			text.Break(out)
+			continue
+		}
+		kits = kits.Append(index)
+	}
+	return kits
+}
+
+// fetchStarterKitIndex fetches and decodes a JSON starter kit index.
+func fetchStarterKitIndex(url string, client api.HTTPClient) (index config.StarterKitLanguages, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return index, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return index, err
+	}
+	defer res.Body.Close() // #nosec G307
+	if res.StatusCode != http.StatusOK {
+		return index, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+	if err := json.NewDecoder(res.Body).Decode(&index); err != nil {
+		return index, fmt.Errorf("error parsing the index: %w", err)
+	}
+	return index, nil
+}
+
+// cleanup removes the template if it was fetched.
+func (d initDefaults) cleanup() {
+	if d.fetched {
+		_ = os.RemoveAll(d.templateDir)
+	}
+}
+
+// validateRequiredFields returns an error if a field can't be required.
+func validateRequiredFields(fields []string) error {
+	for _, f := range fields {
+		if _, ok := initRequiredFields[f]; !ok {
+			valid := make([]string, 0, len(initRequiredFields))
+			for v := range initRequiredFields {
+				valid = append(valid, v)
+			}
+			sort.Strings(valid)
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("[compute-init] requires an unsupported fastly.toml field '%s'", f),
+				Remediation: fmt.Sprintf("Update the required_fields of [compute-init] in the CLI config (or its template's %s). The supported fields are: %s.", InitDefaultsFilename, strings.Join(valid, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// checkRequiredFields returns an error if a required field isn't set.
+func (d initDefaults) checkRequiredFields(desc string, authors []string) error {
+	var missing []string
+	for _, f := range d.RequiredFields {
+		if !initRequiredFields[f](desc, authors) {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("your organisation requires the fastly.toml fields: %s", strings.Join(missing, ", ")),
+		Remediation: "Provide the fields when prompted (or set --author for the authors), as required by the [compute-init] defaults.",
+	}
+}
+
+// apply copies the template files, license and CODEOWNERS into dst,
+// replacing any that the package template provided.
+func (d initDefaults) apply(dst string, out io.Writer) error {
+	applied := make(map[string]bool)
+
+	if d.templateDir != "" {
+		err := filepath.Walk(d.templateDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(d.templateDir, path)
+			if err != nil {
+				return err
+			}
+			if rel == InitDefaultsFilename || strings.HasPrefix(rel, ".git"+string(filepath.Separator)) {
+				return nil
+			}
+			if err := filesystem.CopyFile(path, filepath.Join(dst, rel)); err != nil {
+				return err
+			}
+			applied[filepath.ToSlash(rel)] = true
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error copying [compute-init] template files: %w", err)
+		}
+	}
+
+	if d.License != "" {
+		if err := filesystem.CopyFile(d.License, filepath.Join(dst, "LICENSE")); err != nil {
+			return fmt.Errorf("error copying [compute-init] license: %w", err)
+		}
+		applied["LICENSE"] = true
+	}
+
+	if len(d.CodeOwners) > 0 {
+		data := []byte("* " + strings.Join(d.CodeOwners, " ") + "\n")
+		if err := os.WriteFile(filepath.Join(dst, "CODEOWNERS"), data, 0o644); err != nil { // #nosec G306
+			return fmt.Errorf("error writing [compute-init] CODEOWNERS: %w", err)
+		}
+		applied["CODEOWNERS"] = true
+	}
+
+	if len(applied) > 0 {
+		files := make([]string, 0, len(applied))
+		for f := range applied {
+			files = append(files, f)
+		}
+		sort.Strings(files)
+		text.Break(out)
+		text.Info(out, "Applied your organisation's defaults: %s", strings.Join(files, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestInitOrgDefaults(t *testing.T) {
+	// The template provides a CODEOWNERS file and its own defaults, which the
+	// CLI config partially overrides.
+	template := t.TempDir()
+	for name, content := range map[string]string{
+		".github/pull_request_template.md": "## Summary",
+		"LICENSE":                          "template license",
+		"fastly-init.toml": `code_owners = ["@acme/edge"]
+license = "LICENSE"
+required_fields = ["description"]`,
+	} {
+		path := filepath.Join(template, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	license := filepath.Join(t.TempDir(), "LICENSE")
+	if err := os.WriteFile(license, []byte("config license"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		args        string
+		computeInit config.ComputeInit
+		manifest    string
+		wantError   string
+		wantFiles   map[string]string
+		wantOutput  string
+	}{
+		{
+			name: "validate unsupported required field",
+			args: "compute init --language other --non-interactive",
+			computeInit: config.ComputeInit{
+				RequiredFields: []string{"homepage"},
+			},
+			wantError: "[compute-init] requires an unsupported fastly.toml field 'homepage'",
+		},
+		{
+			name: "validate required field from template",
+			args: "compute init --language other --non-interactive --author foo@example.com",
+			computeInit: config.ComputeInit{
+				Template: template,
+			},
+			wantError: "your organisation requires the fastly.toml fields: description",
+		},
+		{
+			name: "validate required field from config",
+			args: "compute init --language other --non-interactive",
+			computeInit: config.ComputeInit{
+				RequiredFields: []string{"authors"},
+			},
+			wantError: "your organisation requires the fastly.toml fields: authors",
+		},
+		{
+			name: "apply template",
+			args: "compute init --language other --non-interactive --author foo@example.com",
+			computeInit: config.ComputeInit{
+				Template: template,
+			},
+			manifest: `name = "package"
+description = "An edge app"
+manifest_version = 2`,
+			wantFiles: map[string]string{
+				".github/pull_request_template.md": "## Summary",
+				"CODEOWNERS":                       "* @acme/edge\n",
+				"LICENSE":                          "template license",
+			},
+			wantOutput: "Applied your organisation's defaults: .github/pull_request_template.md, CODEOWNERS, LICENSE",
+		},
+		{
+			name: "config overrides template",
+			args: "compute init --language other --non-interactive --author foo@example.com",
+			computeInit: config.ComputeInit{
+				CodeOwners: []string{"@acme/platform", "@acme/security"},
+				License:    license,
+				Template:   template,
+			},
+			manifest: `name = "package"
+description = "An edge app"
+manifest_version = 2`,
+			wantFiles: map[string]string{
+				"CODEOWNERS": "* @acme/platform @acme/security\n",
+				"LICENSE":    "config license",
+			},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := t.TempDir()
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(pwd)
+
+			if testcase.manifest != "" {
+				if err := os.WriteFile(filepath.Join(rootdir, "fastly.toml"), []byte(testcase.manifest), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.ConfigFile = config.File{ComputeInit: testcase.computeInit}
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			for name, want := range testcase.wantFiles {
+				have, err := os.ReadFile(filepath.Join(rootdir, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertString(t, want, string(have))
+			}
+			if _, err := os.Stat(filepath.Join(rootdir, "fastly-init.toml")); err == nil {
+				t.Error("want the template defaults file to be omitted")
+			}
+		})
+	}
+}
+
+func TestInitStarterKitRegistries(t *testing.T) {
+	// Each starter kit is a local directory, identified by its README.
+	kit := func(name string) string {
+		dir := t.TempDir()
+		for file, content := range map[string]string{
+			"fastly.toml": "manifest_version = 2\nlanguage = \"rust\"\n",
+			"README.md":   name,
+		} {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+	official, internal, indexed := kit("official"), kit("internal"), kit("indexed")
+
+	index, err := json.Marshal(config.StarterKitLanguages{
+		Rust: []config.StarterKit{{Name: "Acme Auth", Description: "From the index", Path: indexed}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(index)
+	}))
+	defer srv.Close()
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := t.TempDir()
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	// The name, description and author prompts are accepted before the third
+	// starter kit is chosen.
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute init --language rust"), &stdout)
+	opts.Stdin = iotest.OneByteReader(strings.NewReader("\n\n\n3\n"))
+	opts.HTTPClient = srv.Client()
+	opts.ConfigFile = config.File{
+		ComputeInit: config.ComputeInit{
+			StarterKitIndexes: []string{srv.URL + "/missing.json", srv.URL + "/index.json"},
+			StarterKits: config.StarterKitLanguages{
+				Rust: []config.StarterKit{{Name: "Acme Default", Description: "From the config", Path: internal}},
+			},
+		},
+		StarterKits: config.StarterKitLanguages{
+			Rust: []config.StarterKit{{Name: "Default", Path: official}},
+		},
+	}
+	err = app.Run(opts)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+
+	testutil.AssertStringContains(t, stdout.String(), "Failed to fetch the [compute-init] starter kit index "+srv.URL+"/missing.json: unexpected response status: 404 Not Found")
+	for _, s := range []string{"[1] Default", "[2] Acme Default", "[3] Acme Auth"} {
+		testutil.AssertStringContains(t, stdout.String(), s)
+	}
+	readme, err := os.ReadFile(filepath.Join(rootdir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "indexed", string(readme))
+}
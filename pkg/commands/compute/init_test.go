@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -428,3 +429,92 @@ func TestInit(t *testing.T) {
 		})
 	}
 }
+
+func TestInitFromGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in $PATH")
+	}
+
+	// The repository has a v1 tag, and a later commit on the main branch.
+	repo := t.TempDir()
+	git := func(args ...string) {
+		c := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		c.Dir = repo
+		if output, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	commit := func(readme string) {
+		if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte(readme), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-m", readme)
+	}
+	git("init", "--initial-branch", "main")
+	if err := os.WriteFile(filepath.Join(repo, manifest.Filename), []byte("manifest_version = 2\nlanguage = \"other\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	commit("v1")
+	git("tag", "v1")
+	commit("main")
+	from := "file://" + filepath.ToSlash(repo)
+
+	scenarios := []struct {
+		name                 string
+		args                 string
+		wantError            string
+		wantRemediationError string
+		wantReadme           string
+	}{
+		{
+			name:      "validate --ref with --branch",
+			args:      "compute init --from " + from + " --ref v1 --branch main",
+			wantError: "--ref can't be used with --branch or --tag",
+		},
+		{
+			name:                 "unknown ref",
+			args:                 "compute init --from " + from + " --ref v2 --language other --non-interactive",
+			wantError:            "error fetching package template",
+			wantRemediationError: "Git credential helper",
+		},
+		{
+			name:       "default branch",
+			args:       "compute init --from " + from + " --language other --non-interactive",
+			wantReadme: "main",
+		},
+		{
+			name:       "tag",
+			args:       "compute init --from " + from + " --ref v1 --language other --non-interactive",
+			wantReadme: "v1",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := t.TempDir()
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(pwd)
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			if testcase.wantReadme != "" {
+				readme, err := os.ReadFile(filepath.Join(rootdir, "README.md"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertString(t, testcase.wantReadme, string(readme))
+			}
+		})
+	}
+}
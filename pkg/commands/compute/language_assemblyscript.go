@@ -43,18 +43,23 @@ func NewAssemblyScript(
 	in io.Reader,
 	out io.Writer,
 	spinner text.Spinner,
+	postBuildEnv []string,
 ) *AssemblyScript {
 	return &AssemblyScript{
 		Shell: Shell{},
 
-		build:     fastlyManifest.Scripts.Build,
-		errlog:    globals.ErrLog,
-		input:     in,
-		output:    out,
-		postBuild: fastlyManifest.Scripts.PostBuild,
-		spinner:   spinner,
-		timeout:   flags.Timeout,
-		verbose:   globals.Verbose(),
+		build:        fastlyManifest.Scripts.Build,
+		ci:           globals.CI(),
+		errlog:       globals.ErrLog,
+		input:        in,
+		output:       out,
+		outputPrefix: flags.OutputPrefix,
+		postBuild:    fastlyManifest.Scripts.PostBuild,
+		postBuildEnv: postBuildEnv,
+		quietSuccess: flags.QuietSuccess,
+		spinner:      spinner,
+		timeout:      flags.Timeout,
+		verbose:      globals.Verbose(),
 	}
 }
 
@@ -64,6 +69,8 @@ type AssemblyScript struct {
 
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// errlog is an abstraction for recording errors to disk.
@@ -74,9 +81,16 @@ type AssemblyScript struct {
 	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -110,11 +124,15 @@ func (a *AssemblyScript) Build() error {
 		autoYes:        a.autoYes,
 		buildFn:        a.Shell.Build,
 		buildScript:    a.build,
+		ci:             a.ci,
 		errlog:         a.errlog,
 		in:             a.input,
 		nonInteractive: a.nonInteractive,
 		out:            a.output,
+		outputPrefix:   a.outputPrefix,
 		postBuild:      a.postBuild,
+		postBuildEnv:   a.postBuildEnv,
+		quietSuccess:   a.quietSuccess,
 		spinner:        a.spinner,
 		timeout:        a.timeout,
 		verbose:        a.verbose,
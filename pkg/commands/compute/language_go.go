@@ -37,18 +37,23 @@ func NewGo(
 	in io.Reader,
 	out io.Writer,
 	spinner text.Spinner,
+	postBuildEnv []string,
 ) *Go {
 	return &Go{
 		Shell: Shell{},
 
 		autoYes:        globals.Flags.AutoYes,
 		build:          fastlyManifest.Scripts.Build,
+		ci:             globals.CI(),
 		config:         globals.Config.Language.Go,
 		errlog:         globals.ErrLog,
 		input:          in,
 		nonInteractive: globals.Flags.NonInteractive,
 		output:         out,
+		outputPrefix:   flags.OutputPrefix,
 		postBuild:      fastlyManifest.Scripts.PostBuild,
+		postBuildEnv:   postBuildEnv,
+		quietSuccess:   flags.QuietSuccess,
 		spinner:        spinner,
 		timeout:        flags.Timeout,
 		verbose:        globals.Verbose(),
@@ -66,6 +71,8 @@ type Go struct {
 
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// config is the Go specific application configuration.
@@ -78,9 +85,16 @@ type Go struct {
 	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -112,11 +126,15 @@ func (g *Go) Build() error {
 		autoYes:        g.autoYes,
 		buildFn:        g.Shell.Build,
 		buildScript:    g.build,
+		ci:             g.ci,
 		errlog:         g.errlog,
 		in:             g.input,
 		nonInteractive: g.nonInteractive,
 		out:            g.output,
+		outputPrefix:   g.outputPrefix,
 		postBuild:      g.postBuild,
+		postBuildEnv:   g.postBuildEnv,
+		quietSuccess:   g.quietSuccess,
 		spinner:        g.spinner,
 		timeout:        g.timeout,
 		verbose:        g.verbose,
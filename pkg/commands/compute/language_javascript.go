@@ -45,17 +45,22 @@ func NewJavaScript(
 	in io.Reader,
 	out io.Writer,
 	spinner text.Spinner,
+	postBuildEnv []string,
 ) *JavaScript {
 	return &JavaScript{
 		Shell: Shell{},
 
 		autoYes:        globals.Flags.AutoYes,
 		build:          fastlyManifest.Scripts.Build,
+		ci:             globals.CI(),
 		errlog:         globals.ErrLog,
 		input:          in,
 		nonInteractive: globals.Flags.NonInteractive,
 		output:         out,
+		outputPrefix:   flags.OutputPrefix,
 		postBuild:      fastlyManifest.Scripts.PostBuild,
+		postBuildEnv:   postBuildEnv,
+		quietSuccess:   flags.QuietSuccess,
 		spinner:        spinner,
 		timeout:        flags.Timeout,
 		verbose:        globals.Verbose(),
@@ -68,6 +73,8 @@ type JavaScript struct {
 
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// errlog is an abstraction for recording errors to disk.
@@ -78,9 +85,16 @@ type JavaScript struct {
 	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -115,11 +129,15 @@ func (j *JavaScript) Build() error {
 		autoYes:        j.autoYes,
 		buildFn:        j.Shell.Build,
 		buildScript:    j.build,
+		ci:             j.ci,
 		errlog:         j.errlog,
 		in:             j.input,
 		nonInteractive: j.nonInteractive,
 		out:            j.output,
+		outputPrefix:   j.outputPrefix,
 		postBuild:      j.postBuild,
+		postBuildEnv:   j.postBuildEnv,
+		quietSuccess:   j.quietSuccess,
 		spinner:        j.spinner,
 		timeout:        j.timeout,
 		verbose:        j.verbose,
@@ -17,17 +17,22 @@ func NewOther(
 	in io.Reader,
 	out io.Writer,
 	spinner text.Spinner,
+	postBuildEnv []string,
 ) *Other {
 	return &Other{
 		Shell: Shell{},
 
 		autoYes:        globals.Flags.AutoYes,
 		build:          fastlyManifest.Scripts.Build,
+		ci:             globals.CI(),
 		errlog:         globals.ErrLog,
 		input:          in,
 		nonInteractive: globals.Flags.NonInteractive,
 		output:         out,
+		outputPrefix:   flags.OutputPrefix,
 		postBuild:      fastlyManifest.Scripts.PostBuild,
+		postBuildEnv:   postBuildEnv,
+		quietSuccess:   flags.QuietSuccess,
 		spinner:        spinner,
 		timeout:        flags.Timeout,
 		verbose:        globals.Verbose(),
@@ -40,6 +45,8 @@ type Other struct {
 
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// errlog is an abstraction for recording errors to disk.
@@ -50,9 +57,16 @@ type Other struct {
 	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -68,11 +82,15 @@ func (o Other) Build() error {
 		autoYes:        o.autoYes,
 		buildFn:        o.Shell.Build,
 		buildScript:    o.build,
+		ci:             o.ci,
 		errlog:         o.errlog,
 		in:             o.input,
 		nonInteractive: o.nonInteractive,
 		out:            o.output,
+		outputPrefix:   o.outputPrefix,
 		postBuild:      o.postBuild,
+		postBuildEnv:   o.postBuildEnv,
+		quietSuccess:   o.quietSuccess,
 		spinner:        o.spinner,
 		timeout:        o.timeout,
 		verbose:        o.verbose,
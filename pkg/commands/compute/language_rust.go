@@ -49,18 +49,23 @@ func NewRust(
 	in io.Reader,
 	out io.Writer,
 	spinner text.Spinner,
+	postBuildEnv []string,
 ) *Rust {
 	return &Rust{
 		Shell: Shell{},
 
 		autoYes:        globals.Flags.AutoYes,
 		build:          fastlyManifest.Scripts.Build,
+		ci:             globals.CI(),
 		config:         globals.Config.Language.Rust,
 		errlog:         globals.ErrLog,
 		input:          in,
 		nonInteractive: globals.Flags.NonInteractive,
 		output:         out,
+		outputPrefix:   flags.OutputPrefix,
 		postBuild:      fastlyManifest.Scripts.PostBuild,
+		postBuildEnv:   postBuildEnv,
+		quietSuccess:   flags.QuietSuccess,
 		spinner:        spinner,
 		timeout:        flags.Timeout,
 		verbose:        globals.Verbose(),
@@ -73,6 +78,8 @@ type Rust struct {
 
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// config is the Rust specific application configuration.
@@ -85,11 +92,18 @@ type Rust struct {
 	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
 	// projectRoot is the root directory where the Cargo.toml is located.
 	projectRoot string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -121,12 +135,16 @@ func (r *Rust) Build() error {
 		autoYes:                   r.autoYes,
 		buildFn:                   r.Shell.Build,
 		buildScript:               r.build,
+		ci:                        r.ci,
 		errlog:                    r.errlog,
 		in:                        r.input,
 		internalPostBuildCallback: r.ProcessLocation,
 		nonInteractive:            r.nonInteractive,
 		out:                       r.output,
+		outputPrefix:              r.outputPrefix,
 		postBuild:                 r.postBuild,
+		postBuildEnv:              r.postBuildEnv,
+		quietSuccess:              r.quietSuccess,
 		spinner:                   r.spinner,
 		timeout:                   r.timeout,
 		verbose:                   r.verbose,
@@ -38,6 +38,8 @@ type Toolchain interface {
 type BuildToolchain struct {
 	// autoYes is the --auto-yes flag.
 	autoYes bool
+	// ci is the CI system whose log syntax should be emitted.
+	ci text.CI
 	// buildFn constructs a `sh -c` command from the buildScript.
 	buildFn func(string) (string, []string)
 	// buildScript is the [scripts.build] within the fastly.toml manifest.
@@ -52,9 +54,16 @@ type BuildToolchain struct {
 	nonInteractive bool
 	// out is the users terminal stdout stream
 	out io.Writer
+	// outputPrefix prefixes each line of the compiler output.
+	outputPrefix string
 	// postBuild is a custom script executed after the build but before the Wasm
 	// binary is added to the .tar.gz archive.
 	postBuild string
+	// postBuildEnv is the list of KEY=VALUE environment variables passed to the
+	// postBuild script (see hook.env).
+	postBuildEnv []string
+	// quietSuccess is the --quiet-success flag.
+	quietSuccess bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -86,7 +95,7 @@ func (bt BuildToolchain) Build() error {
 		bt.spinner.Message(msg + "...")
 	}
 
-	err = bt.execCommand(cmd, args, msg)
+	err = bt.execCommand(cmd, args, nil, msg)
 	if err != nil {
 		// In verbose mode we'll have the failure status AFTER the error output.
 		// But we can't just call StopFailMessage() without first starting the spinner.
@@ -162,7 +171,7 @@ func (bt BuildToolchain) Build() error {
 		bt.spinner.Message(msg)
 
 		cmd, args := bt.buildFn(bt.postBuild)
-		err := bt.execCommand(cmd, args, msg)
+		err := bt.execCommand(cmd, args, bt.postBuildEnv, msg)
 		if err != nil {
 			// WARNING: Don't try to add 'StopFailMessage/StopFail' calls here.
 			// It is handled internally by fstexec.Streaming.Exec().
@@ -193,17 +202,33 @@ func (bt BuildToolchain) handleError(err error) error {
 // When the error occurs the command output is displayed.
 // This causes the spinner message to be displayed twice with different status.
 // By passing in the spinner and message we can short-circuit the spinner.
-func (bt BuildToolchain) execCommand(cmd string, args []string, spinMessage string) error {
+//
+// The compiler output is only displayed on failure, unless --verbose is set
+// without --quiet-success. When building several packages, or rebuilding with
+// --watch, each line of output is prefixed so the builds can be told apart.
+//
+// The env variables are added to the CLI's environment.
+func (bt BuildToolchain) execCommand(cmd string, args, env []string, spinMessage string) error {
+	output := bt.out
+	if bt.outputPrefix != "" {
+		pw := &prefixWriter{prefix: bt.outputPrefix, w: bt.out}
+		defer func() {
+			_ = pw.Flush()
+		}()
+		output = pw
+	}
+
 	s := fstexec.Streaming{
 		Command:        cmd,
 		Args:           args,
-		Env:            os.Environ(),
-		Output:         bt.out,
+		CI:             bt.ci,
+		Env:            append(os.Environ(), env...),
+		Output:         output,
 		Spinner:        bt.spinner,
 		SpinnerMessage: spinMessage,
 		Verbose:        bt.verbose,
 	}
-	if bt.verbose {
+	if bt.verbose && !bt.quietSuccess {
 		s.ForceOutput = true
 	}
 	if bt.timeout > 0 {
@@ -0,0 +1,141 @@
+package compute
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	toml "github.com/pelletier/go-toml"
+)
+
+// LockFilename is the name of the file recording the exact toolchain versions
+// a project was built with, so the whole team builds with the same versions.
+const LockFilename = "fastly.lock"
+
+// Lockfile represents the content of the fastly.lock file.
+type Lockfile struct {
+	Language string            `toml:"language"`
+	Tools    map[string]string `toml:"tools"`
+}
+
+// ReadLockfile reads the lockfile at path.
+func ReadLockfile(path string) (Lockfile, error) {
+	var l Lockfile
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return l, err
+	}
+	err = toml.Unmarshal(data, &l)
+	return l, err
+}
+
+// Write writes the lockfile to path.
+func (l Lockfile) Write(path string) error {
+	data, err := toml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte("# This file is generated by `fastly compute build`.\n"), data...), 0o644) // #nosec G306
+}
+
+// Drift describes the differences between the locked versions and the given
+// versions of the language's tools (an empty list if they match).
+//
+// NOTE: Tools that aren't both locked and installed are ignored, as a missing
+// toolchain fails the build anyway and Viceroy is only installed by `compute
+// serve`.
+func (l Lockfile) Drift(language string, versions map[string]string) []string {
+	if l.Language != language {
+		return []string{fmt.Sprintf("language: locked %s, found %s", l.Language, language)}
+	}
+
+	var drift []string
+	for t, found := range versions {
+		if locked, ok := l.Tools[t]; ok && locked != found {
+			drift = append(drift, fmt.Sprintf("%s: locked %s, found %s", t, locked, found))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// Add records the versions of the tools that aren't locked yet, and reports
+// whether any were added.
+func (l *Lockfile) Add(versions map[string]string) bool {
+	var added bool
+	for t, v := range versions {
+		if _, ok := l.Tools[t]; !ok {
+			if l.Tools == nil {
+				l.Tools = make(map[string]string)
+			}
+			l.Tools[t] = v
+			added = true
+		}
+	}
+	return added
+}
+
+// toolVersion describes how to identify the installed version of a tool.
+type toolVersion struct {
+	name    string
+	command []string
+	// pattern has one capture group matching the version.
+	pattern string
+}
+
+// languageTools are the tools whose versions are locked for each language.
+//
+// NOTE: The 'other' language is built by a custom script, so only Viceroy is
+// locked for it.
+var languageTools = map[string][]toolVersion{
+	"assemblyscript": {
+		{"node", []string{"node", "--version"}, `v(\d[^\s]+)`},
+		{"npm", []string{"npm", "--version"}, `(\d[^\s]+)`},
+	},
+	"go": {
+		{"go", []string{"go", "version"}, `go version go(\d[^\s]+)`},
+		{"tinygo", []string{"tinygo", "version"}, `tinygo version (\d[^\s]+)`},
+	},
+	"javascript": {
+		{"node", []string{"node", "--version"}, `v(\d[^\s]+)`},
+		{"npm", []string{"npm", "--version"}, `(\d[^\s]+)`},
+	},
+	"rust": {
+		{"cargo", []string{"cargo", "version", "--quiet"}, `cargo (\d[^\s]+)`},
+		{"rustc", []string{"rustc", "--version"}, `rustc (\d[^\s]+)`},
+	},
+}
+
+// ToolVersions returns the installed versions of the tools used to build the
+// language, as well as the version of Viceroy (if installed). Tools that
+// aren't installed are omitted.
+func ToolVersions(language, viceroyBinary string) map[string]string {
+	tools := languageTools[language]
+	if viceroyBinary != "" {
+		tools = append(tools, toolVersion{"viceroy", []string{filepath.Join(InstallDir, viceroyBinary), "--version"}, `viceroy (\d[^\s]+)`})
+	}
+
+	versions := make(map[string]string, len(tools))
+	for _, t := range tools {
+		// gosec flagged this:
+		// G204 (CWE-78): Subprocess launched with variable
+		// Disabling as the commands are hardcoded above.
+		// #nosec
+		// nosemgrep
+		output, err := exec.Command(t.command[0], t.command[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		if match := regexp.MustCompile(t.pattern).FindSubmatch(output); len(match) == 2 {
+			versions[t.name] = string(match[1])
+		}
+	}
+	return versions
+}
@@ -0,0 +1,73 @@
+package compute_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestLockfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), compute.LockFilename)
+	lock := compute.Lockfile{
+		Language: "rust",
+		Tools:    map[string]string{"cargo": "1.70.0", "rustc": "1.70.0"},
+	}
+	if err := lock.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := compute.ReadLockfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name      string
+		language  string
+		versions  map[string]string
+		wantDrift []string
+		wantAdded bool
+	}{
+		{
+			name:     "matching versions",
+			language: "rust",
+			versions: map[string]string{"cargo": "1.70.0", "rustc": "1.70.0"},
+		},
+		{
+			name:      "drifted versions",
+			language:  "rust",
+			versions:  map[string]string{"cargo": "1.71.0", "rustc": "1.71.1"},
+			wantDrift: []string{"cargo: locked 1.70.0, found 1.71.0", "rustc: locked 1.70.0, found 1.71.1"},
+		},
+		{
+			name:     "tool not installed",
+			language: "rust",
+			versions: map[string]string{"cargo": "1.70.0"},
+		},
+		{
+			name:      "tool not locked",
+			language:  "rust",
+			versions:  map[string]string{"cargo": "1.70.0", "rustc": "1.70.0", "viceroy": "0.6.0"},
+			wantAdded: true,
+		},
+		{
+			name:      "language changed",
+			language:  "go",
+			versions:  map[string]string{"go": "1.20.0"},
+			wantDrift: []string{"language: locked rust, found go"},
+			wantAdded: true,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertEqual(t, testcase.wantDrift, lock.Drift(testcase.language, testcase.versions))
+
+			l := compute.Lockfile{Language: lock.Language, Tools: map[string]string{}}
+			for k, v := range lock.Tools {
+				l.Tools[k] = v
+			}
+			testutil.AssertBool(t, testcase.wantAdded, l.Add(testcase.versions))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package compute
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/mholt/archiver/v3"
+)
+
+// MetricsFile is the project file in which the package size and duration of
+// each deployment is recorded, so that `compute deploy` can report changes.
+//
+// NOTE: This is a package level variable as it makes testing the behaviour of
+// the package easier because the test code can replace the value when running
+// the test suite.
+var MetricsFile = filepath.Join(".fastly", "deploy_metrics.json")
+
+// maxDeployMetrics is the number of deployments recorded in the MetricsFile.
+const maxDeployMetrics = 50
+
+// deployMetric records the package size and duration of a deployment.
+type deployMetric struct {
+	DeployedAt      time.Time `json:"deployed_at"`
+	ServiceID       string    `json:"service_id"`
+	ServiceVersion  int       `json:"service_version"`
+	PackageSize     int64     `json:"package_size"`
+	WasmSize        int64     `json:"wasm_size"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// readDeployMetrics returns the recorded deployments, oldest first.
+//
+// NOTE: A missing MetricsFile isn't an error, as nothing has been deployed yet.
+func readDeployMetrics() ([]deployMetric, error) {
+	data, err := os.ReadFile(MetricsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", MetricsFile, err)
+	}
+	var metrics []deployMetric
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", MetricsFile, err)
+	}
+	return metrics, nil
+}
+
+// recordDeployMetric appends a deployment to the MetricsFile, dropping the
+// oldest deployments once maxDeployMetrics is exceeded.
+func recordDeployMetric(m deployMetric) error {
+	metrics, err := readDeployMetrics()
+	if err != nil {
+		return err
+	}
+	metrics = append(metrics, m)
+	if len(metrics) > maxDeployMetrics {
+		metrics = metrics[len(metrics)-maxDeployMetrics:]
+	}
+
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding deploy metrics: %w", err)
+	}
+	if err := filesystem.MakeDirectoryIfNotExists(filepath.Dir(MetricsFile)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(MetricsFile, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", MetricsFile, err)
+	}
+	return nil
+}
+
+// wasmSize returns the uncompressed size of the package's Wasm binary.
+func wasmSize(pkgPath string) (size int64, err error) {
+	err = validate(pkgPath, func(f archiver.File) error {
+		if f.Name() == "main.wasm" {
+			size = f.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// percentChange returns the change from prev to cur as a percentage of prev.
+func percentChange(prev, cur float64) float64 {
+	if prev == 0 {
+		return 0
+	}
+	return (cur - prev) / prev * 100
+}
+
+// formatChange renders a percentage change with its sign, e.g. "+12%".
+func formatChange(pct float64) string {
+	pct = math.Round(pct)
+	if pct == 0 {
+		return "±0%"
+	}
+	return fmt.Sprintf("%+.0f%%", pct)
+}
+
+// parsePercent parses a percentage such as "10%" (the % is optional).
+func parsePercent(s string) (float64, error) {
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || pct < 0 {
+		return 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid percentage '%s'", s),
+			Remediation: "Provide a positive percentage, e.g. --fail-on-size-increase 10%",
+		}
+	}
+	return pct, nil
+}
+
+// checkSizeRegression reports the change in the package's size since the last
+// recorded deployment, failing if the Wasm binary grew by more than
+// --fail-on-size-increase. It returns the metric to record for this deployment
+// and the last recorded deployment (nil if there isn't one).
+func (c *DeployCommand) checkSizeRegression(pkgPath string, out io.Writer) (deployMetric, *deployMetric, error) {
+	var (
+		current deployMetric
+		limit   float64
+		err     error
+	)
+	if c.FailOnSizeIncrease != "" {
+		if limit, err = parsePercent(c.FailOnSizeIncrease); err != nil {
+			return current, nil, err
+		}
+	}
+
+	if current.PackageSize, err = packageSize(pkgPath); err != nil {
+		return current, nil, fmt.Errorf("error reading package size: %w", err)
+	}
+	if current.WasmSize, err = wasmSize(pkgPath); err != nil {
+		return current, nil, err
+	}
+
+	metrics, err := readDeployMetrics()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Unable to compare the package size with the last deployment: %s", err)
+		return current, nil, nil
+	}
+	if len(metrics) == 0 {
+		return current, nil, nil
+	}
+	previous := metrics[len(metrics)-1]
+
+	wasmChange := percentChange(float64(previous.WasmSize), float64(current.WasmSize))
+	text.Info(out, "Package size: %s, wasm %s (wasm %s, package %s since last deploy)",
+		formatSize(current.PackageSize),
+		formatSize(current.WasmSize),
+		formatChange(wasmChange),
+		formatChange(percentChange(float64(previous.PackageSize), float64(current.PackageSize))),
+	)
+	if c.FailOnSizeIncrease != "" && wasmChange > limit {
+		return current, nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the Wasm binary grew by %s since the last deploy (%s to %s), exceeding --fail-on-size-increase %s", formatChange(wasmChange), formatSize(previous.WasmSize), formatSize(current.WasmSize), c.FailOnSizeIncrease),
+			Remediation: "Reduce the size of the Wasm binary (`fastly compute pack --analyze` reports its largest sections), or raise the --fail-on-size-increase threshold.",
+		}
+	}
+	return current, &previous, nil
+}
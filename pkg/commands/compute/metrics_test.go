@@ -0,0 +1,105 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDeployMetrics(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A 1 kB Wasm binary, so that size changes are easily expressed.
+	wasm := append([]byte("\x00asm\x01\x00\x00\x00"), make([]byte, 992)...)
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.WriteFile("fastly.toml", []byte("manifest_version = 2\nname = \"metrics\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		GetPackageFn:        getPackageOk,
+		GetServiceFn:        getServiceOK,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		ListDomainsFn:       listDomainsOk,
+		ListVersionsFn:      testutil.ListVersions,
+		UpdatePackageFn:     updatePackageOk,
+	}
+	run := func(t *testing.T, args string) (string, error) {
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+		opts.APIClient = mock.APIClient(api)
+		err := app.Run(opts)
+		t.Log(stdout.String())
+		return stdout.String(), err
+	}
+	// pack packages a Wasm binary padded by the given number of bytes.
+	pack := func(t *testing.T, padding int) {
+		if err := os.WriteFile("main.wasm", append(wasm, make([]byte, padding)...), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := run(t, "compute pack --wasm-binary ./main.wasm")
+		testutil.AssertNoError(t, err)
+	}
+	deploy := "compute deploy --service-id 123 --token 123 --version latest --package pkg/package.tar.gz"
+	recorded := func(t *testing.T) int {
+		data, err := os.ReadFile(compute.MetricsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var metrics []map[string]any
+		if err := json.Unmarshal(data, &metrics); err != nil {
+			t.Fatal(err)
+		}
+		return len(metrics)
+	}
+
+	t.Run("first deploy", func(t *testing.T) {
+		pack(t, 0)
+		out, err := run(t, deploy+" --fail-on-size-increase 10%")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringDoesntContain(t, out, "since last deploy")
+		testutil.AssertEqual(t, 1, recorded(t))
+	})
+
+	t.Run("validate invalid percentage", func(t *testing.T) {
+		_, err := run(t, deploy+" --fail-on-size-increase ten")
+		testutil.AssertErrorContains(t, err, "invalid percentage 'ten'")
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		out, err := run(t, deploy+" --fail-on-size-increase 10%")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "(wasm ±0%, package ±0% since last deploy)")
+		testutil.AssertStringContains(t, out, "Deploy took")
+		testutil.AssertEqual(t, 2, recorded(t))
+	})
+
+	t.Run("size increase over the threshold", func(t *testing.T) {
+		pack(t, 200)
+		_, err := run(t, deploy+" --fail-on-size-increase 10%")
+		testutil.AssertErrorContains(t, err, "the Wasm binary grew by +20% since the last deploy")
+		testutil.AssertRemediationErrorContains(t, err, "compute pack --analyze")
+		testutil.AssertEqual(t, 2, recorded(t))
+	})
+
+	t.Run("size increase within the threshold", func(t *testing.T) {
+		out, err := run(t, deploy+" --fail-on-size-increase 25")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "wasm +20%")
+		testutil.AssertEqual(t, 3, recorded(t))
+	})
+}
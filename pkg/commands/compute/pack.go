@@ -1,6 +1,7 @@
 package compute
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"io"
 	"os"
@@ -12,13 +13,15 @@ import (
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
-	"github.com/mholt/archiver/v3"
 )
 
 // PackCommand takes a .wasm and builds the required tar/gzip package ready to be uploaded.
 type PackCommand struct {
 	cmd.Base
+	analyze    bool
 	manifest   manifest.Data
+	sbom       string
+	signKey    string
 	wasmBinary string
 }
 
@@ -30,6 +33,9 @@ func NewPackCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Pac
 
 	c.CmdClause = parent.Command("pack", "Package a pre-compiled Wasm binary for a Fastly Compute@Edge service")
 	c.CmdClause.Flag("wasm-binary", "Path to a pre-compiled Wasm binary").Short('w').Required().StringVar(&c.wasmBinary)
+	c.CmdClause.Flag("analyze", "Report the package's compressed and uncompressed size, its largest files and the size of each Wasm section").BoolVar(&c.analyze)
+	c.CmdClause.Flag("sbom", "Embed a Software Bill of Materials in the package, generated from the project's dependency lockfiles (see `compute sbom`)").HintOptions(SBOMFormats...).EnumVar(&c.sbom, SBOMFormats...)
+	c.CmdClause.Flag("sign-key", "Path to an Ed25519 private key (PEM) to sign the package with, for `compute deploy --verify-key`").StringVar(&c.signKey)
 
 	return &c
 }
@@ -53,6 +59,12 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	if err = c.manifest.File.ReadError(); err != nil {
 		return err
 	}
+	var signKey ed25519.PrivateKey
+	if c.signKey != "" {
+		if signKey, err = readSigningKey(c.signKey); err != nil {
+			return err
+		}
+	}
 	bin := "pkg/package/bin/main.wasm"
 	bindir := filepath.Dir(bin)
 	err = filesystem.MakeDirectoryIfNotExists(bindir)
@@ -149,6 +161,61 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	if c.sbom != "" {
+		err = spinner.Start()
+		if err != nil {
+			return err
+		}
+		msg = "Generating SBOM"
+		spinner.Message(msg + "...")
+
+		var data []byte
+		if data, _, err = generateSBOM(".", c.sbom, c.manifest.File.Name); err == nil {
+			dst = filepath.Join("pkg", "package", SBOMFilenames[c.sbom])
+			if err = os.WriteFile(dst, data, 0o644); err != nil { // #nosec G306
+				err = fmt.Errorf("error writing SBOM to '%s': %w", dst, err)
+			}
+		}
+		if err != nil {
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+			return err
+		}
+
+		spinner.StopMessage(msg)
+		err = spinner.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	if signKey != nil {
+		err = spinner.Start()
+		if err != nil {
+			return err
+		}
+		msg = "Signing package"
+		spinner.Message(msg + "...")
+
+		if err = signPackage("pkg/package", signKey); err != nil {
+			spinner.StopFailMessage(msg)
+			spinErr := spinner.StopFail()
+			if spinErr != nil {
+				return spinErr
+			}
+			return err
+		}
+
+		spinner.StopMessage(msg)
+		err = spinner.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
 	err = spinner.Start()
 	if err != nil {
 		return err
@@ -156,13 +223,10 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	msg = "Creating package.tar.gz file"
 	spinner.Message(msg + "...")
 
-	tar := archiver.NewTarGz()
-	tar.OverwriteExisting = true
 	{
 		dir := "pkg/package"
-		src := []string{dir}
 		dst := fmt.Sprintf("%s.tar.gz", dir)
-		if err = tar.Archive(src, dst); err != nil {
+		if err = writeArchive(dir, dst); err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Tar source":      dir,
 				"Tar destination": dst,
@@ -179,5 +243,16 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	}
 
 	spinner.StopMessage(msg)
-	return spinner.Stop()
+	if err = spinner.Stop(); err != nil {
+		return err
+	}
+
+	if c.analyze {
+		a, err := analyzePackage("pkg/package", "pkg/package.tar.gz", bin)
+		if err != nil {
+			return err
+		}
+		printPackageAnalysis(out, a)
+	}
+	return nil
 }
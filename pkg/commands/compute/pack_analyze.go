@@ -0,0 +1,245 @@
+package compute
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// packageSizeWarningRatio is the fraction of the PackageSizeLimit above which
+// `compute pack --analyze` warns the package is approaching the limit.
+const packageSizeWarningRatio = 0.8
+
+// largestFilesLimit is the number of package files listed by
+// `compute pack --analyze`.
+const largestFilesLimit = 10
+
+// wasmSectionNames are the names of the known Wasm section IDs.
+//
+// Reference: https://webassembly.github.io/spec/core/binary/modules.html#sections
+var wasmSectionNames = map[byte]string{
+	0:  "custom",
+	1:  "type",
+	2:  "import",
+	3:  "function",
+	4:  "table",
+	5:  "memory",
+	6:  "global",
+	7:  "export",
+	8:  "start",
+	9:  "element",
+	10: "code",
+	11: "data",
+	12: "datacount",
+}
+
+// wasmSection is a section of a Wasm binary.
+type wasmSection struct {
+	// Name is the section's name, e.g. "code", or "custom: name" for a custom
+	// section.
+	Name string
+	Size int64
+}
+
+// packageFile is a file within the package archive.
+type packageFile struct {
+	Path string
+	Size int64
+}
+
+// packageAnalysis describes the size of a package.
+type packageAnalysis struct {
+	Compressed   int64
+	Uncompressed int64
+	// Files are sorted by size, largest first.
+	Files []packageFile
+	// Sections are the sections of the Wasm binary, sorted by size, largest
+	// first.
+	Sections []wasmSection
+}
+
+// analyzePackage reports the size of the package archive, and of the files
+// in dir (the archive's contents) and sections of its Wasm binary.
+func analyzePackage(dir, archive, bin string) (packageAnalysis, error) {
+	var a packageAnalysis
+
+	fi, err := os.Stat(archive)
+	if err != nil {
+		return a, fmt.Errorf("error reading package size: %w", err)
+	}
+	a.Compressed = fi.Size()
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		a.Uncompressed += info.Size()
+		a.Files = append(a.Files, packageFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return a, fmt.Errorf("error reading package files: %w", err)
+	}
+	sort.SliceStable(a.Files, func(i, j int) bool {
+		return a.Files[i].Size > a.Files[j].Size
+	})
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Open(bin)
+	if err != nil {
+		return a, fmt.Errorf("error reading wasm binary: %w", err)
+	}
+	defer f.Close() // #nosec G307
+	if a.Sections, err = wasmSections(f); err != nil {
+		return a, fmt.Errorf("error parsing wasm binary '%s': %w", bin, err)
+	}
+	return a, nil
+}
+
+// wasmSections returns the size of each section of the Wasm binary, sorted by
+// size, largest first. Repeated sections (e.g. custom sections of the same
+// name) are combined.
+func wasmSections(r io.Reader) ([]wasmSection, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, errors.New("missing wasm header")
+	}
+	if string(header[:4]) != "\x00asm" {
+		return nil, errors.New("invalid wasm magic number")
+	}
+	if v := binary.LittleEndian.Uint32(header[4:]); v != 1 {
+		return nil, fmt.Errorf("unsupported wasm version %d", v)
+	}
+
+	sizes := make(map[string]int64)
+	var order []string
+	for {
+		id, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of section %d: %w", id, err)
+		}
+
+		name, ok := wasmSectionNames[id]
+		if !ok {
+			name = fmt.Sprintf("unknown (%d)", id)
+		}
+		remaining := int64(size)
+		if id == 0 {
+			n, err := binary.ReadUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("invalid name of custom section: %w", err)
+			}
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(br, buf); err != nil {
+				return nil, fmt.Errorf("invalid name of custom section: %w", err)
+			}
+			name = fmt.Sprintf("custom: %s", buf)
+			remaining -= int64(uvarintLen(n)) + int64(n)
+			if remaining < 0 {
+				return nil, fmt.Errorf("custom section '%s' is larger than its size", buf)
+			}
+		}
+		if _, err := io.CopyN(io.Discard, br, remaining); err != nil {
+			return nil, fmt.Errorf("truncated %s section", name)
+		}
+
+		if _, ok := sizes[name]; !ok {
+			order = append(order, name)
+		}
+		sizes[name] += int64(size)
+	}
+
+	sections := make([]wasmSection, 0, len(order))
+	for _, name := range order {
+		sections = append(sections, wasmSection{Name: name, Size: sizes[name]})
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].Size > sections[j].Size
+	})
+	return sections, nil
+}
+
+// uvarintLen returns the number of bytes of the LEB128 encoding of n.
+func uvarintLen(n uint64) int {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutUvarint(buf, n)
+}
+
+// printPackageAnalysis writes the package size analysis to out, warning when
+// the package is approaching the PackageSizeLimit.
+func printPackageAnalysis(out io.Writer, a packageAnalysis) {
+	text.Break(out)
+	text.Output(out, "%s %s compressed, %s uncompressed (limit %s compressed)",
+		text.Bold("Package size:"), formatSize(a.Compressed), formatSize(a.Uncompressed), formatSize(PackageSizeLimit))
+
+	text.Break(out)
+	text.Output(out, text.Bold("Largest files:"))
+	t := text.NewTable(out)
+	t.AddHeader("FILE", "SIZE", "SHARE")
+	for i, f := range a.Files {
+		if i == largestFilesLimit {
+			break
+		}
+		t.AddLine(f.Path, formatSize(f.Size), percent(f.Size, a.Uncompressed))
+	}
+	t.Print()
+
+	var total int64
+	for _, s := range a.Sections {
+		total += s.Size
+	}
+	text.Break(out)
+	text.Output(out, text.Bold("Wasm sections:"))
+	t = text.NewTable(out)
+	t.AddHeader("SECTION", "SIZE", "SHARE")
+	for _, s := range a.Sections {
+		t.AddLine(s.Name, formatSize(s.Size), percent(s.Size, total))
+	}
+	t.Print()
+
+	limit := float64(PackageSizeLimit)
+	switch {
+	case float64(a.Compressed) > limit:
+		text.Break(out)
+		text.Error(out, "The package exceeds the %s package size limit and will be rejected by `compute deploy`.", formatSize(PackageSizeLimit))
+	case float64(a.Compressed) > limit*packageSizeWarningRatio:
+		text.Break(out)
+		text.Warning(out, "The package is %s of the %s package size limit.", percent(a.Compressed, PackageSizeLimit), formatSize(PackageSizeLimit))
+	}
+}
+
+// percent formats n as a percentage of total.
+func percent(n, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)/float64(total)*100)
+}
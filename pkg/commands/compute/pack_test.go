@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/testutil"
 )
@@ -99,3 +100,92 @@ func TestPack(t *testing.T) {
 		})
 	}
 }
+
+func TestPackAnalyze(t *testing.T) {
+	// A Wasm binary with a type, code and custom "name" section.
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, 0x01, 0x04, 0x01, 0x60, 0x00, 0x00)
+	wasm = append(wasm, 0x0a, 0x64)
+	wasm = append(wasm, bytes.Repeat([]byte{0x00}, 100)...)
+	wasm = append(wasm, 0x00, 0x07, 0x04, 'n', 'a', 'm', 'e', 0x01, 0x02)
+
+	originalPackageSizeLimit := compute.PackageSizeLimit
+	defer func() { compute.PackageSizeLimit = originalPackageSizeLimit }()
+
+	for _, testcase := range []struct {
+		name       string
+		wasm       []byte
+		limit      int64
+		wantError  string
+		wantOutput []string
+	}{
+		{
+			name:  "success",
+			wasm:  wasm,
+			limit: originalPackageSizeLimit,
+			wantOutput: []string{
+				"Package size:",
+				"(limit 50.0 MB compressed)",
+				"bin/main.wasm",
+				"fastly.toml",
+				"code          100 B  90.1%",
+				"custom: name  7 B    6.3%",
+				"type          4 B    3.6%",
+			},
+		},
+		{
+			name:  "exceeds limit",
+			wasm:  wasm,
+			limit: 10,
+			wantOutput: []string{
+				"The package exceeds the 10 B package size limit",
+			},
+		},
+		{
+			name:      "invalid wasm",
+			wasm:      []byte("not wasm"),
+			limit:     originalPackageSizeLimit,
+			wantError: "invalid wasm magic number",
+		},
+		{
+			name:      "truncated section",
+			wasm:      wasm[:20],
+			limit:     originalPackageSizeLimit,
+			wantError: "truncated code section",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"analyze\"\n", Dst: manifest.Filename},
+					{Src: string(testcase.wasm), Dst: "main.wasm"},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(pwd)
+
+			compute.PackageSizeLimit = testcase.limit
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("compute pack --analyze --wasm-binary ./main.wasm"), &stdout)
+			err = app.Run(opts)
+
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
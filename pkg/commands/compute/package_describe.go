@@ -0,0 +1,289 @@
+package compute
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/go-fastly/v7/fastly"
+	"github.com/mholt/archiver/v3"
+	toml "github.com/pelletier/go-toml"
+)
+
+// PackageRootCommand is the parent command for the package subcommands.
+type PackageRootCommand struct {
+	cmd.Base
+	// no flags
+}
+
+// NewPackageRootCommand returns a new command registered in the parent.
+func NewPackageRootCommand(parent cmd.Registerer, g *global.Data) *PackageRootCommand {
+	var c PackageRootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("package", "Inspect Compute@Edge packages")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *PackageRootCommand) Exec(_ io.Reader, _ io.Writer) error {
+	panic("unreachable")
+}
+
+// PackageDescription describes a package and how it was built.
+type PackageDescription struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Language    string              `json:"language,omitempty"`
+	Authors     []string            `json:"authors,omitempty"`
+	Size        int64               `json:"size"`
+	HashSum     string              `json:"hashsum"`
+	BuildInfo   *manifest.BuildInfo `json:"build_info,omitempty"`
+
+	// The service version the package was uploaded to (remote packages only).
+	ServiceID      string     `json:"service_id,omitempty"`
+	ServiceVersion int        `json:"service_version,omitempty"`
+	UploadedAt     *time.Time `json:"uploaded_at,omitempty"`
+}
+
+// PackageDescribeCommand describes a local package, or the package of a
+// service version.
+type PackageDescribeCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	manifest       manifest.Data
+	path           string
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewPackageDescribeCommand returns a usable command registered under the parent.
+func NewPackageDescribeCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *PackageDescribeCommand {
+	var c PackageDescribeCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("describe", "Show the metadata and build info of a package, or of the package of a service version when --service-id, --service-name or --version is set")
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("package", "Path to a package tar.gz, defaulting to the project's package").Short('p').StringVar(&c.path)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceVersion.Set,
+		Name:        cmd.FlagVersionName,
+		Description: "'latest', 'active', or the number of a specific version (defaults to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *PackageDescribeCommand) Exec(_ io.Reader, out io.Writer) error {
+	var (
+		d   *PackageDescription
+		err error
+	)
+	if c.manifest.Flag.ServiceID != "" || c.serviceName.WasSet || c.serviceVersion.WasSet {
+		d, err = c.describeRemote(out)
+	} else {
+		d, err = c.describeLocal()
+	}
+	if err != nil {
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, d); ok {
+		return err
+	}
+
+	if d.ServiceID != "" {
+		fmt.Fprintf(out, "Service ID: %s\n", d.ServiceID)
+		fmt.Fprintf(out, "Service Version: %d\n", d.ServiceVersion)
+		if d.UploadedAt != nil {
+			fmt.Fprintf(out, "Uploaded at: %s\n", d.UploadedAt)
+		}
+	}
+	fmt.Fprintf(out, "Name: %s\n", d.Name)
+	fmt.Fprintf(out, "Description: %s\n", d.Description)
+	fmt.Fprintf(out, "Language: %s\n", d.Language)
+	fmt.Fprintf(out, "Authors: %s\n", strings.Join(d.Authors, ", "))
+	fmt.Fprintf(out, "Size: %s\n", formatSize(d.Size))
+	fmt.Fprintf(out, "Hashsum: %s\n", d.HashSum)
+
+	if d.BuildInfo == nil {
+		if d.ServiceID != "" {
+			fmt.Fprintf(out, "Build info: unavailable (only recorded in the package, use --package to reference a local copy)\n")
+		} else {
+			fmt.Fprintf(out, "Build info: none (the package wasn't built by `compute build`)\n")
+		}
+		return nil
+	}
+	fmt.Fprintf(out, "Build info:\n")
+	fmt.Fprintf(out, "\tBuilt at: %s\n", d.BuildInfo.BuildTime)
+	fmt.Fprintf(out, "\tCLI version: %s\n", d.BuildInfo.CLIVersion)
+	if d.BuildInfo.GitCommit != "" {
+		fmt.Fprintf(out, "\tGit commit: %s\n", d.BuildInfo.GitCommit)
+	}
+	if len(d.BuildInfo.Toolchain) > 0 {
+		tools := make([]string, 0, len(d.BuildInfo.Toolchain))
+		for t := range d.BuildInfo.Toolchain {
+			tools = append(tools, t)
+		}
+		sort.Strings(tools)
+		fmt.Fprintf(out, "\tToolchain:\n")
+		for _, t := range tools {
+			fmt.Fprintf(out, "\t\t%s: %s\n", t, d.BuildInfo.Toolchain[t])
+		}
+	}
+	return nil
+}
+
+// describeLocal describes the package at the --package path, or the
+// project's package.
+func (c *PackageDescribeCommand) describeLocal() (*PackageDescription, error) {
+	projectName, source := c.manifest.Name()
+	path, err := packagePath(c.path, projectName, source)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to identify the package: %w", err),
+			Remediation: "Run `fastly compute build` to produce a Compute@Edge package, alternatively use the --package flag to reference a package outside of the current project.",
+		}
+	}
+	d, err := readPackageDescription(path)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Package path": path,
+		})
+		return nil, err
+	}
+	return d, nil
+}
+
+// describeRemote describes the package of the service version (the active
+// version by default).
+//
+// NOTE: The API doesn't return the package's build info, so it's read from
+// the local package (--package, or the project's package) if the hashsums of
+// the packages match.
+func (c *PackageDescribeCommand) describeRemote(out io.Writer) (*PackageDescription, error) {
+	_, s := c.Globals.Token()
+	if s == lookup.SourceUndefined {
+		return nil, fsterr.ErrNoToken
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		ErrLog:             c.Globals.ErrLog,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return nil, err
+	}
+
+	p, err := c.Globals.APIClient.GetPackage(&fastly.GetPackageInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return nil, err
+	}
+
+	d := &PackageDescription{
+		Name:           p.Metadata.Name,
+		Description:    p.Metadata.Description,
+		Language:       p.Metadata.Language,
+		Authors:        p.Metadata.Authors,
+		Size:           p.Metadata.Size,
+		HashSum:        p.Metadata.HashSum,
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+		UploadedAt:     p.UpdatedAt,
+	}
+
+	projectName, source := c.manifest.Name()
+	if path, err := packagePath(c.path, projectName, source); err == nil && filesystem.FileExists(path) {
+		if local, err := readPackageDescription(path); err == nil && local.HashSum == d.HashSum {
+			d.BuildInfo = local.BuildInfo
+		}
+	}
+	return d, nil
+}
+
+// readPackageDescription reads the description of the package at path from
+// its fastly.toml manifest.
+func readPackageDescription(path string) (*PackageDescription, error) {
+	size, err := packageSize(path)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error reading package size: %w", err),
+			Remediation: "Run `fastly compute build` to produce a Compute@Edge package, alternatively use the --package flag to reference a package outside of the current project.",
+		}
+	}
+
+	contents := map[string]*bytes.Buffer{
+		"fastly.toml": {},
+		"main.wasm":   {},
+	}
+	if err := validate(path, func(f archiver.File) error {
+		switch fname := f.Name(); fname {
+		case "fastly.toml", "main.wasm":
+			if _, err := io.Copy(contents[fname], f); err != nil {
+				return fmt.Errorf("error reading %s: %w", fname, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var m manifest.File
+	if err := toml.Unmarshal(contents["fastly.toml"].Bytes(), &m); err != nil {
+		return nil, fmt.Errorf("error parsing the package's fastly.toml: %w", err)
+	}
+
+	hashSum, err := getHashSum(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackageDescription{
+		Name:        m.Name,
+		Description: m.Description,
+		Language:    m.Language,
+		Authors:     m.Authors,
+		Size:        size,
+		HashSum:     hashSum,
+		BuildInfo:   m.BuildInfo,
+	}, nil
+}
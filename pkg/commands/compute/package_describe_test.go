@@ -0,0 +1,197 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestPackageDescribe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the build script is a shell command")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := `name = "package"
+manifest_version = 2
+language = "other"
+authors = ["jane@example.com"]
+[scripts]
+build = "touch ./bin/main.wasm"
+`
+	if err := os.WriteFile("fastly.toml", []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".gitignore", []byte("bin/\npkg/\n.fastly/\nfastly.lock\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) string {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %s: %s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	commit := git("rev-parse", "HEAD")
+
+	t.Setenv(compute.SourceDateEpochEnvVar, "1700000000")
+	var stdout threadsafe.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute build --auto-yes"), &stdout)
+	if err := app.Run(opts); err != nil {
+		t.Log(stdout.String())
+		t.Fatal(err)
+	}
+
+	// The build info is only recorded in the package's manifest.
+	data, err := os.ReadFile("fastly.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertStringDoesntContain(t, string(data), "build_info")
+
+	var local compute.PackageDescription
+	{
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args("compute package describe --json"), &stdout)
+		if err := app.Run(opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &local); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutil.AssertEqual(t, "package", local.Name)
+	testutil.AssertEqual(t, []string{"jane@example.com"}, local.Authors)
+	if local.BuildInfo == nil {
+		t.Fatal("want build info, have none")
+	}
+	testutil.AssertEqual(t, "2023-11-14T22:13:20Z", local.BuildInfo.BuildTime)
+	testutil.AssertEqual(t, commit, local.BuildInfo.GitCommit)
+
+	listVersions := func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+		return []*fastly.Version{
+			{ServiceID: i.ServiceID, Number: 1, Active: true, Locked: true},
+			{ServiceID: i.ServiceID, Number: 2},
+		}, nil
+	}
+	getPackage := func(hashSum string) func(*fastly.GetPackageInput) (*fastly.Package, error) {
+		return func(i *fastly.GetPackageInput) (*fastly.Package, error) {
+			if i.ServiceVersion != 1 {
+				return nil, testutil.Err
+			}
+			return &fastly.Package{
+				ServiceID:      i.ServiceID,
+				ServiceVersion: i.ServiceVersion,
+				Metadata: fastly.PackageMetadata{
+					Name:     "package",
+					Language: "other",
+					Size:     1234,
+					HashSum:  hashSum,
+				},
+			}, nil
+		}
+	}
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		api           mock.API
+		wantError     string
+		wantOutput    []string
+		wantNotOutput []string
+	}{
+		{
+			name: "local package",
+			args: "compute package describe",
+			wantOutput: []string{
+				"Name: package",
+				"Authors: jane@example.com",
+				"Hashsum: " + local.HashSum,
+				"Built at: 2023-11-14T22:13:20Z",
+				"Git commit: " + commit,
+			},
+			wantNotOutput: []string{"Service ID"},
+		},
+		{
+			name:      "missing package",
+			args:      "compute package describe --package pkg/missing.tar.gz",
+			wantError: "error reading package size",
+		},
+		{
+			name: "deployed package matching the local package",
+			args: "compute package describe --service-id 123",
+			api: mock.API{
+				ListVersionsFn: listVersions,
+				GetPackageFn:   getPackage(local.HashSum),
+			},
+			wantOutput: []string{
+				"Service ID: 123",
+				"Service Version: 1",
+				"Size: 1.2 kB",
+				"Git commit: " + commit,
+			},
+		},
+		{
+			name: "deployed package differing from the local package",
+			args: "compute package describe --service-id 123",
+			api: mock.API{
+				ListVersionsFn: listVersions,
+				GetPackageFn:   getPackage("abc"),
+			},
+			wantOutput:    []string{"Hashsum: abc", "Build info: unavailable"},
+			wantNotOutput: []string{"Git commit"},
+		},
+		{
+			name: "package of a specific version",
+			args: "compute package describe --service-id 123 --version 2",
+			api: mock.API{
+				ListVersionsFn: listVersions,
+				GetPackageFn:   getPackage(local.HashSum),
+			},
+			wantError: testutil.Err.Error(),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token 123"), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.wantNotOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// PromoteCommand deploys the active package of one service to another,
+// without rebuilding it.
+type PromoteCommand struct {
+	cmd.Base
+
+	comment     cmd.OptionalString
+	fromService string
+	path        string
+	toService   string
+}
+
+// NewPromoteCommand returns a usable command registered under the parent.
+func NewPromoteCommand(parent cmd.Registerer, g *global.Data) *PromoteCommand {
+	var c PromoteCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("promote", "Deploy the active package of a service, unchanged, to another service (e.g. from staging to production)")
+
+	// required
+	c.CmdClause.Flag("from-service", "Service ID of the service whose active package is promoted").Required().StringVar(&c.fromService)
+	c.CmdClause.Flag("to-service", "Service ID of the service the package is deployed to").Required().StringVar(&c.toService)
+
+	// optional
+	c.CmdClause.Flag("comment", "Human-readable comment for the promoted service version (default: the service and version promoted from)").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("package", fmt.Sprintf("Path to a copy of the active package, if it's not in the local deploy history (%s)", HistoryDir)).Short('p').StringVar(&c.path)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+//
+// NOTE: The API doesn't support downloading a package, so the package is read
+// from the deploy history cached by `compute deploy` (or --package). It's
+// only promoted if its hashsum matches the active package's, so the exact
+// same package is deployed.
+func (c *PromoteCommand) Exec(_ io.Reader, out io.Writer) error {
+	_, s := c.Globals.Token()
+	if s == lookup.SourceUndefined {
+		return fsterr.ErrNoToken
+	}
+	if c.Globals.Config.Fastly.RequireActivationComment && strings.TrimSpace(c.comment.Value) == "" {
+		c.Globals.ErrLog.Add(fsterr.ErrActivationCommentRequired)
+		return fsterr.ErrActivationCommentRequired
+	}
+	if c.fromService == c.toService {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--from-service and --to-service are the same service"),
+			Remediation: "Use `fastly compute rollback --reupload-package` to redeploy a package to the same service.",
+		}
+	}
+
+	from, pkgPath, err := c.sourcePackage()
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"From service": c.fromService,
+			"Package path": c.path,
+		})
+		return err
+	}
+
+	comment := c.comment.Value
+	if !c.comment.WasSet {
+		comment = fmt.Sprintf("Promoted package from service %s version %d", c.fromService, from.Number)
+	}
+	version, err := deployPackage(c.Globals.APIClient, c.toService, pkgPath, comment)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"From service": c.fromService,
+			"To service":   c.toService,
+			"Package path": pkgPath,
+		})
+		return err
+	}
+
+	if err := cachePackage(pkgPath, c.toService, version); err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Unable to cache the package for `compute rollback --reupload-package`: %s", err)
+	}
+
+	text.Success(out, "Promoted the package of service %s (version %d) to service %s (version %d)", c.fromService, from.Number, c.toService, version)
+	return nil
+}
+
+// sourcePackage returns the active version of the service promoted from, and
+// the path of its package.
+func (c *PromoteCommand) sourcePackage() (*fastly.Version, string, error) {
+	vs, err := c.Globals.APIClient.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: c.fromService,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing service versions of service %s: %w", c.fromService, err)
+	}
+	active, err := cmd.GetActiveVersion(vs)
+	if err != nil {
+		return nil, "", fmt.Errorf("service %s: %w", c.fromService, err)
+	}
+	p, err := c.Globals.APIClient.GetPackage(&fastly.GetPackageInput{
+		ServiceID:      c.fromService,
+		ServiceVersion: active.Number,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting the package of service %s (version %d): %w", c.fromService, active.Number, err)
+	}
+
+	pkgPath := c.path
+	if pkgPath == "" {
+		pkgPath = historyPackagePath(c.fromService, active.Number)
+		if !filesystem.FileExists(pkgPath) {
+			return nil, "", fsterr.RemediationError{
+				Inner:       fmt.Errorf("no cached package found for service %s (version %d): %s", c.fromService, active.Number, pkgPath),
+				Remediation: "Packages are only cached when deployed from this project directory. Use the --package flag to reference a copy of the active package.",
+			}
+		}
+	}
+
+	d, err := readPackageDescription(pkgPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if d.HashSum != p.Metadata.HashSum {
+		return nil, "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("the package %s isn't the active package of service %s (version %d): hashsum %s doesn't match %s", pkgPath, c.fromService, active.Number, d.HashSum, p.Metadata.HashSum),
+			Remediation: "Use the --package flag to reference a copy of the active package.",
+		}
+	}
+	return active, pkgPath, nil
+}
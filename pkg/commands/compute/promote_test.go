@@ -0,0 +1,170 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestPromote(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join(compute.HistoryDir, "staging", "3.tar.gz"),
+			},
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: "copy.tar.gz",
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	var pkg compute.PackageDescription
+	{
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args("compute package describe --json --package copy.tar.gz"), &stdout)
+		if err := app.Run(opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &pkg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listVersions := func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+		if i.ServiceID == "staging" {
+			return []*fastly.Version{
+				{ServiceID: i.ServiceID, Number: 2, Locked: true},
+				{ServiceID: i.ServiceID, Number: 3, Active: true, Locked: true},
+			}, nil
+		}
+		return []*fastly.Version{
+			{ServiceID: i.ServiceID, Number: 7, Active: true, Locked: true},
+			{ServiceID: i.ServiceID, Number: 8},
+		}, nil
+	}
+	getPackage := func(hashSum string) func(i *fastly.GetPackageInput) (*fastly.Package, error) {
+		return func(i *fastly.GetPackageInput) (*fastly.Package, error) {
+			return &fastly.Package{
+				ServiceID:      i.ServiceID,
+				ServiceVersion: i.ServiceVersion,
+				Metadata:       fastly.PackageMetadata{HashSum: hashSum},
+			}, nil
+		}
+	}
+	deployAPI := func(wantPackage, wantComment string) mock.API {
+		return mock.API{
+			ListVersionsFn: listVersions,
+			GetPackageFn:   getPackage(pkg.HashSum),
+			CloneVersionFn: func(i *fastly.CloneVersionInput) (*fastly.Version, error) {
+				if i.ServiceID != "prod" || i.ServiceVersion != 7 {
+					return nil, testutil.Err
+				}
+				return &fastly.Version{ServiceID: i.ServiceID, Number: 9}, nil
+			},
+			UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+				if i.ServiceID != "prod" || i.ServiceVersion != 9 || i.PackagePath != wantPackage {
+					return nil, testutil.Err
+				}
+				return &fastly.Package{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion}, nil
+			},
+			UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+				if i.Comment == nil || *i.Comment != wantComment {
+					return nil, testutil.Err
+				}
+				return &fastly.Version{ServiceID: i.ServiceID, Number: i.ServiceVersion}, nil
+			},
+			ActivateVersionFn: func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+				if i.ServiceVersion != 9 {
+					return nil, testutil.Err
+				}
+				return &fastly.Version{ServiceID: i.ServiceID, Number: i.ServiceVersion, Active: true}, nil
+			},
+		}
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --to-service flag",
+			Args:      args("compute promote --from-service staging"),
+			WantError: "error parsing arguments: required flag --to-service not provided",
+		},
+		{
+			Name:      "validate same service",
+			Args:      args("compute promote --from-service staging --to-service staging --token 123"),
+			WantError: "--from-service and --to-service are the same service",
+		},
+		{
+			Name: "validate missing cached package",
+			Args: args("compute promote --from-service qa --to-service prod --token 123"),
+			API: mock.API{
+				ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+					return []*fastly.Version{{ServiceID: i.ServiceID, Number: 1, Active: true}}, nil
+				},
+				GetPackageFn: getPackage(pkg.HashSum),
+			},
+			WantError: "no cached package found for service qa (version 1)",
+		},
+		{
+			Name: "validate package differs from the active package",
+			Args: args("compute promote --from-service staging --to-service prod --token 123"),
+			API: mock.API{
+				ListVersionsFn: listVersions,
+				GetPackageFn:   getPackage("abc"),
+			},
+			WantError: "isn't the active package of service staging (version 3)",
+		},
+		{
+			Name:       "success from the deploy history",
+			Args:       args("compute promote --from-service staging --to-service prod --token 123"),
+			API:        deployAPI(filepath.Join(compute.HistoryDir, "staging", "3.tar.gz"), "Promoted package from service staging version 3"),
+			WantOutput: "Promoted the package of service staging (version 3) to service prod (version 9)",
+		},
+		{
+			Name:       "success from --package",
+			Args:       args("compute promote --from-service staging --to-service prod --package copy.tar.gz --comment release --token 123"),
+			API:        deployAPI("copy.tar.gz", "release"),
+			WantOutput: "Promoted the package of service staging (version 3) to service prod (version 9)",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+
+	// The promoted package is cached for `compute rollback --reupload-package`.
+	if _, err := os.Stat(filepath.Join(compute.HistoryDir, "prod", "9.tar.gz")); err != nil {
+		t.Fatalf("the promoted package wasn't cached: %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package compute
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -17,21 +19,35 @@ type PublishCommand struct {
 	deploy   *DeployCommand
 
 	// Build fields
-	includeSrc  cmd.OptionalBool
-	lang        cmd.OptionalString
-	packageName cmd.OptionalString
-	timeout     cmd.OptionalInt
+	frozen       cmd.OptionalBool
+	cache        cmd.OptionalBool
+	includeSrc   cmd.OptionalBool
+	lang         cmd.OptionalString
+	optimize     cmd.OptionalBool
+	packageName  cmd.OptionalString
+	quietSuccess cmd.OptionalBool
+	timeout      cmd.OptionalInt
+	verifyRepro  cmd.OptionalBool
 
 	// Deploy fields
+	async              bool
+	backendCheck       bool
 	comment            cmd.OptionalString
+	concurrency        int
+	jsonOutput         bool
 	domain             cmd.OptionalString
+	failOnSizeIncrease string
 	pkg                cmd.OptionalString
+	serviceGroup       string
 	serviceName        cmd.OptionalServiceNameID
 	serviceVersion     cmd.OptionalServiceVersion
 	statusCheckCode    int
 	statusCheckOff     bool
 	statusCheckPath    string
 	statusCheckTimeout int
+	summaryOut         string
+	verifyKey          string
+	workspace          string
 }
 
 // NewPublishCommand returns a usable command registered under the parent.
@@ -43,12 +59,20 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 	c.deploy = deploy
 	c.CmdClause = parent.Command("publish", "Build and deploy a Compute@Edge package to a Fastly service")
 
+	c.CmdClause.Flag("async", "Return as soon as the service version is activated, with a deployment ID to check with `compute deploy-status`, rather than waiting for the service to be available").BoolVar(&c.async)
+	c.CmdClause.Flag("cache", fmt.Sprintf("Skip the build if nothing has changed since the last build (see %s), use --no-cache to force a rebuild", BuildCacheDir)).Action(c.cache.Set).NegatableBoolVar(&c.cache.Value)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("backend-check", "Check each backend created by [setup] for a new service by requesting its origin, expecting a non-5xx status").BoolVar(&c.backendCheck)
+	c.CmdClause.Flag("concurrency", "The number of services of a --service-group to deploy to at once").Default("4").IntVar(&c.concurrency)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").Action(c.domain.Set).StringVar(&c.domain.Value)
+	c.CmdClause.Flag("fail-on-size-increase", "Fail if the Wasm binary grew by more than the given percentage (e.g. 10%) since the last deploy").StringVar(&c.failOnSizeIncrease)
+	c.CmdClause.Flag("frozen", fmt.Sprintf("Fail the build if the toolchain versions don't match those recorded in %s", LockFilename)).Action(c.frozen.Set).BoolVar(&c.frozen.Value)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
+	c.CmdClause.Flag("optimize", "Optimize the Wasm binary with wasm-opt before packaging it (downloaded on first use)").Action(c.optimize.Set).BoolVar(&c.optimize.Value)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').Action(c.pkg.Set).StringVar(&c.pkg.Value)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("quiet-success", "Only print the compiler output if the build fails, even with --verbose").Action(c.quietSuccess.Set).BoolVar(&c.quietSuccess.Value)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
 		Description: cmd.FlagServiceIDDesc,
@@ -61,10 +85,18 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.CmdClause.Flag("service-group", fmt.Sprintf("Deploy the package to several existing services concurrently: a comma-separated list of service IDs, or the name of a [service_groups] entry of the %s manifest", manifest.Filename)).StringVar(&c.serviceGroup)
 	c.CmdClause.Flag("status-check-code", "Set the expected status response for the service availability check to the root path").IntVar(&c.statusCheckCode)
 	c.CmdClause.Flag("status-check-off", "Disable the service availability check").BoolVar(&c.statusCheckOff)
 	c.CmdClause.Flag("status-check-path", "Specify the URL path for the service availability check").Default("/").StringVar(&c.statusCheckPath)
 	c.CmdClause.Flag("status-check-timeout", "Set a timeout (in seconds) for the service availability check").Default("120").IntVar(&c.statusCheckTimeout)
+	c.CmdClause.Flag("summary-out", "Write a JSON summary of the deployment to the given file").StringVar(&c.summaryOut)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: "Render a summary of the deployment as JSON (implies --non-interactive)",
+		Dst:         &c.jsonOutput,
+		Short:       'j',
+	})
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagVersionName,
 		Description: cmd.FlagVersionDesc,
@@ -72,6 +104,9 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 		Action:      c.serviceVersion.Set,
 	})
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
+	c.CmdClause.Flag("verify-key", "Path to an Ed25519 public key (PEM), refusing to deploy a package unless it's signed with its private key").StringVar(&c.verifyKey)
+	c.CmdClause.Flag("verify-reproducible", "Build the package a second time and fail if the packages differ").Action(c.verifyRepro.Set).BoolVar(&c.verifyRepro.Value)
+	c.CmdClause.Flag("workspace", fmt.Sprintf("Path to a workspace file (e.g. %s) listing packages to build and deploy in dependency order", WorkspaceFilename)).StringVar(&c.workspace)
 
 	return &c
 }
@@ -84,28 +119,57 @@ func NewPublishCommand(parent cmd.Registerer, g *global.Data, build *BuildComman
 // non-deterministic ways. It's best to leave those nested commands to handle
 // the progress indicator.
 func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if c.Globals.Verbose() && c.jsonOutput {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	// When rendering JSON only the deploy summary is written to out.
+	buildOut := out
+	if c.jsonOutput {
+		buildOut = io.Discard
+	}
+
 	// Reset the fields on the BuildCommand based on PublishCommand values.
+	if c.frozen.WasSet {
+		c.build.Flags.Frozen = c.frozen.Value
+	}
 	if c.includeSrc.WasSet {
 		c.build.Flags.IncludeSrc = c.includeSrc.Value
 	}
 	if c.lang.WasSet {
 		c.build.Flags.Lang = c.lang.Value
 	}
+	if c.optimize.WasSet {
+		c.build.Flags.Optimize = c.optimize.Value
+	}
+	if c.cache.WasSet {
+		c.build.Flags.NoCache = !c.cache.Value
+	}
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.quietSuccess.WasSet {
+		c.build.Flags.QuietSuccess = c.quietSuccess.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
+	if c.verifyRepro.WasSet {
+		c.build.Flags.VerifyReproducible = c.verifyRepro.Value
+	}
 	c.build.Manifest = c.manifest
 
-	err = c.build.Exec(in, out)
-	if err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
+	// The packages of a workspace are built by the deploy command, each within
+	// its own directory.
+	if c.workspace == "" {
+		err = c.build.Exec(in, buildOut)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
 
-	text.Break(out)
+		text.Break(buildOut)
+	}
 
 	// Reset the fields on the DeployCommand based on PublishCommand values.
 	if c.pkg.WasSet {
@@ -117,6 +181,15 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	if c.serviceVersion.WasSet {
 		c.deploy.ServiceVersion = c.serviceVersion // deploy's field is a cmd.OptionalServiceVersion
 	}
+	if c.async {
+		c.deploy.Async = c.async
+	}
+	if c.backendCheck {
+		c.deploy.BackendCheck = c.backendCheck
+	}
+	if c.concurrency > 0 {
+		c.deploy.Concurrency = c.concurrency
+	}
 	if c.domain.WasSet {
 		c.deploy.Domain = c.domain.Value
 	}
@@ -134,6 +207,24 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		c.deploy.StatusCheckTimeout = c.statusCheckTimeout
 	}
 	c.deploy.StatusCheckPath = c.statusCheckPath
+	if c.failOnSizeIncrease != "" {
+		c.deploy.FailOnSizeIncrease = c.failOnSizeIncrease
+	}
+	if c.serviceGroup != "" {
+		c.deploy.ServiceGroup = c.serviceGroup
+	}
+	if c.summaryOut != "" {
+		c.deploy.SummaryOut = c.summaryOut
+	}
+	if c.jsonOutput {
+		c.deploy.JSONOutput.Enabled = c.jsonOutput
+	}
+	if c.verifyKey != "" {
+		c.deploy.VerifyKey = c.verifyKey
+	}
+	if c.workspace != "" {
+		c.deploy.Workspace = c.workspace
+	}
 
 	err = c.deploy.Exec(in, out)
 	if err != nil {
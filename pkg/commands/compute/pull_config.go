@@ -0,0 +1,352 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+	"github.com/kennygrant/sanitize"
+)
+
+// placeholderKey is the key of the placeholder entry generated for each KV
+// store and secret store.
+const placeholderKey = "example"
+
+// PullConfigCommand generates the [local_server] configuration of the
+// project's fastly.toml from the resources of a service version.
+type PullConfigCommand struct {
+	cmd.Base
+
+	dir            string
+	force          bool
+	manifest       manifest.Data
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewPullConfigCommand returns a usable command registered under the parent.
+func NewPullConfigCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *PullConfigCommand {
+	var c PullConfigCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("pull-config", "Generate the [local_server] backends, dictionaries and linked stores of the fastly.toml manifest from a service version, with placeholder data files")
+
+	// optional
+	c.CmdClause.Flag("dir", "Directory, relative to the fastly.toml manifest, to write the placeholder data files to").Default("local_server").StringVar(&c.dir)
+	c.CmdClause.Flag("force", "Replace the [local_server] entries that are already configured").BoolVar(&c.force)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceVersion.Set,
+		Name:        cmd.FlagVersionName,
+		Description: "'latest', 'active', or the number of a specific version (defaults to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *PullConfigCommand) Exec(_ io.Reader, out io.Writer) error {
+	_, s := c.Globals.Token()
+	if s == lookup.SourceUndefined {
+		return fsterr.ErrNoToken
+	}
+
+	if err := c.manifest.File.ReadError(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fsterr.ErrReadingManifest
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		ErrLog:             c.Globals.ErrLog,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	r, err := c.listResources(serviceID, serviceVersion.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	g := localServerGenerator{
+		force: c.force,
+		ls:    &c.manifest.File.LocalServer,
+		out:   out,
+		dir:   c.dir,
+	}
+	if err := g.generate(r); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if g.added == 0 {
+		text.Info(out, "The [local_server] configuration already includes the resources of service %s (version %d)", serviceID, serviceVersion.Number)
+		return nil
+	}
+
+	if err := c.manifest.File.Write(manifest.Filename); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error saving package manifest: %w", err)
+	}
+
+	text.Break(out)
+	text.Success(out, "Added %d resources of service %s (version %d) to the [local_server] configuration", g.added, serviceID, serviceVersion.Number)
+	if len(g.files) > 0 {
+		text.Info(out, "Replace the placeholder data in %s with data for local testing.", strings.Join(g.files, ", "))
+	}
+	return nil
+}
+
+// serviceResources are the resources of a service version that are emulated
+// by the local server.
+type serviceResources struct {
+	backends     []*fastly.Backend
+	dictionaries []*fastly.Dictionary
+	// links are the stores linked to the service version.
+	links []*fastly.Resource
+}
+
+// listResources lists the resources of the service version.
+func (c *PullConfigCommand) listResources(serviceID string, serviceVersion int) (r serviceResources, err error) {
+	r.backends, err = c.Globals.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+	})
+	if err != nil {
+		return r, fmt.Errorf("error listing backends: %w", err)
+	}
+	r.dictionaries, err = c.Globals.APIClient.ListDictionaries(&fastly.ListDictionariesInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+	})
+	if err != nil {
+		return r, fmt.Errorf("error listing dictionaries: %w", err)
+	}
+	r.links, err = c.Globals.APIClient.ListResources(&fastly.ListResourcesInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion,
+	})
+	if err != nil {
+		return r, fmt.Errorf("error listing linked stores: %w", err)
+	}
+	return r, nil
+}
+
+// localServerGenerator adds [local_server] entries for service resources.
+type localServerGenerator struct {
+	force bool
+	ls    *manifest.LocalServer
+	out   io.Writer
+	// dir is the directory of the placeholder data files, relative to the
+	// manifest.
+	dir string
+
+	// added is the number of resources added.
+	added int
+	// files are the placeholder data files that were written.
+	files []string
+}
+
+// generate adds the [local_server] entries of the resources.
+func (g *localServerGenerator) generate(r serviceResources) error {
+	sort.Slice(r.backends, func(i, j int) bool { return r.backends[i].Name < r.backends[j].Name })
+	for _, b := range r.backends {
+		if _, ok := g.ls.Backends[b.Name]; ok && !g.force {
+			g.skip("backend", b.Name)
+			continue
+		}
+		if g.ls.Backends == nil {
+			g.ls.Backends = make(map[string]manifest.LocalBackend)
+		}
+		g.ls.Backends[b.Name] = localBackend(b)
+		g.add("backend", b.Name, g.ls.Backends[b.Name].URL)
+	}
+
+	sort.Slice(r.dictionaries, func(i, j int) bool { return r.dictionaries[i].Name < r.dictionaries[j].Name })
+	for _, d := range r.dictionaries {
+		if _, ok := g.ls.Dictionaries[d.Name]; ok && !g.force {
+			g.skip("dictionary", d.Name)
+			continue
+		}
+		file, err := g.placeholder(filepath.Join("dictionaries", sanitize.BaseName(d.Name)+".json"), "{}\n")
+		if err != nil {
+			return err
+		}
+		if g.ls.Dictionaries == nil {
+			g.ls.Dictionaries = make(map[string]manifest.LocalDictionary)
+		}
+		g.ls.Dictionaries[d.Name] = manifest.LocalDictionary{File: file, Format: "json"}
+		g.add("dictionary", d.Name, file)
+	}
+
+	sort.Slice(r.links, func(i, j int) bool { return r.links[i].Name < r.links[j].Name })
+	for _, l := range r.links {
+		if l.DeletedAt != nil {
+			continue
+		}
+		if err := g.generateStore(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateStore adds the [local_server] entry of a linked store.
+//
+// NOTE: The store's data isn't pulled, as it may be sensitive. A secret store
+// entry is read from an environment variable rather than a file, so secrets
+// aren't written to the project.
+func (g *localServerGenerator) generateStore(l *fastly.Resource) error {
+	name := l.Name
+	base := sanitize.BaseName(name)
+	switch storeKind(l.ResourceType) {
+	case "config":
+		if _, ok := g.ls.ConfigStores[name]; ok && !g.force {
+			g.skip("config store", name)
+			return nil
+		}
+		file, err := g.placeholder(filepath.Join("config_stores", base+".json"), "{}\n")
+		if err != nil {
+			return err
+		}
+		if g.ls.ConfigStores == nil {
+			g.ls.ConfigStores = make(map[string]manifest.LocalConfigStore)
+		}
+		g.ls.ConfigStores[name] = manifest.LocalConfigStore{File: file, Format: "json"}
+		g.add("config store", name, file)
+	case "kv":
+		if _, ok := g.ls.KVStores[name]; ok && !g.force {
+			g.skip("KV store", name)
+			return nil
+		}
+		file, err := g.placeholder(filepath.Join("kv_stores", base, placeholderKey), "placeholder value\n")
+		if err != nil {
+			return err
+		}
+		if g.ls.KVStores == nil {
+			g.ls.KVStores = make(map[string][]manifest.LocalKVStore)
+		}
+		g.ls.KVStores[name] = []manifest.LocalKVStore{{Key: placeholderKey, File: file}}
+		g.add("KV store", name, file)
+	case "secret":
+		if _, ok := g.ls.SecretStores[name]; ok && !g.force {
+			g.skip("secret store", name)
+			return nil
+		}
+		env := SecretStoreEnvVar(name, placeholderKey)
+		if g.ls.SecretStores == nil {
+			g.ls.SecretStores = make(map[string][]manifest.LocalSecretStore)
+		}
+		g.ls.SecretStores[name] = []manifest.LocalSecretStore{{Key: placeholderKey, Env: env}}
+		g.add("secret store", name, "$"+env)
+	default:
+		text.Warning(g.out, "Skipping %s '%s': the resource type isn't supported by the local server", l.ResourceType, name)
+	}
+	return nil
+}
+
+// placeholder writes a placeholder data file (unless it already exists) and
+// returns its path relative to the manifest.
+func (g *localServerGenerator) placeholder(name, data string) (string, error) {
+	path := filepath.Join(g.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return filepath.ToSlash(path), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("error creating placeholder data directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil { // #nosec G306
+		return "", fmt.Errorf("error writing placeholder data file: %w", err)
+	}
+	g.files = append(g.files, filepath.ToSlash(path))
+	return filepath.ToSlash(path), nil
+}
+
+// add reports a [local_server] entry was added.
+func (g *localServerGenerator) add(kind, name, detail string) {
+	g.added++
+	text.Output(g.out, "%s %s %s (%s)", text.BoldGreen("+"), kind, name, detail)
+}
+
+// skip reports an existing [local_server] entry was kept.
+func (g *localServerGenerator) skip(kind, name string) {
+	text.Output(g.out, "%s %s %s (already configured, use --force to replace it)", text.Bold("="), kind, name)
+}
+
+// localBackend returns the [local_server] entry of a backend, which uses the
+// backend's production address.
+func localBackend(b *fastly.Backend) manifest.LocalBackend {
+	scheme, port := "http", 80
+	if b.UseSSL {
+		scheme, port = "https", 443
+	}
+	url := fmt.Sprintf("%s://%s", scheme, b.Address)
+	if b.Port != 0 && b.Port != port {
+		url = fmt.Sprintf("%s:%d", url, b.Port)
+	}
+	lb := manifest.LocalBackend{
+		URL:          url,
+		OverrideHost: b.OverrideHost,
+	}
+	if b.UseSSL {
+		lb.CertHost = b.SSLCertHostname
+		lb.UseSNI = b.SSLSNIHostname != ""
+	}
+	return lb
+}
+
+// storeKind returns the kind of store ("config", "kv" or "secret") of a
+// resource link's type, or an empty string if it isn't a store.
+//
+// NOTE: The KV store was previously named the object store.
+func storeKind(resourceType string) string {
+	switch t := strings.ToLower(resourceType); {
+	case strings.HasPrefix(t, "config"):
+		return "config"
+	case strings.HasPrefix(t, "kv"), strings.HasPrefix(t, "object"):
+		return "kv"
+	case strings.HasPrefix(t, "secret"):
+		return "secret"
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestPullConfig(t *testing.T) {
+	api := mock.API{
+		ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+			return []*fastly.Version{
+				{ServiceID: i.ServiceID, Number: 1},
+				{ServiceID: i.ServiceID, Number: 2, Active: true, Locked: true},
+			}, nil
+		},
+		ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+			if i.ServiceVersion != 2 {
+				return nil, testutil.Err
+			}
+			return []*fastly.Backend{
+				{Name: "origin", Address: "www.example.com", Port: 443, UseSSL: true, OverrideHost: "example.com", SSLCertHostname: "www.example.com", SSLSNIHostname: "www.example.com"},
+				{Name: "api", Address: "api.example.com", Port: 8080},
+			}, nil
+		},
+		ListDictionariesFn: func(_ *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+			return []*fastly.Dictionary{{Name: "settings"}}, nil
+		},
+		ListResourcesFn: func(_ *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+			return []*fastly.Resource{
+				{Name: "flags", ResourceType: "config"},
+				{Name: "assets", ResourceType: "kv-store"},
+				{Name: "keys", ResourceType: "secret-store"},
+				{Name: "other", ResourceType: "unknown"},
+			}, nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name         string
+		args         string
+		manifest     string
+		wantError    string
+		wantOutput   []string
+		wantManifest func(t *testing.T, ls manifest.LocalServer)
+		wantFiles    map[string]string
+	}{
+		{
+			name:     "generate",
+			args:     "compute pull-config --service-id 123",
+			manifest: "manifest_version = 2\nname = \"demo\"\n\n[local_server.backends.api]\nurl = \"http://127.0.0.1:8080\"\n",
+			wantOutput: []string{
+				"= backend api (already configured, use --force to replace it)",
+				"+ backend origin (https://www.example.com)",
+				"+ dictionary settings (local_server/dictionaries/settings.json)",
+				"+ config store flags (local_server/config_stores/flags.json)",
+				"+ KV store assets (local_server/kv_stores/assets/example)",
+				"+ secret store keys ($FASTLY_SECRET_STORE_KEYS_EXAMPLE)",
+				"Skipping unknown 'other'",
+				"Added 5 resources of service 123 (version 2)",
+			},
+			wantManifest: func(t *testing.T, ls manifest.LocalServer) {
+				testutil.AssertEqual(t, "http://127.0.0.1:8080", ls.Backends["api"].URL)
+				testutil.AssertEqual(t, manifest.LocalBackend{
+					URL:          "https://www.example.com",
+					OverrideHost: "example.com",
+					CertHost:     "www.example.com",
+					UseSNI:       true,
+				}, ls.Backends["origin"])
+				testutil.AssertEqual(t, manifest.LocalDictionary{File: "local_server/dictionaries/settings.json", Format: "json"}, ls.Dictionaries["settings"])
+				testutil.AssertEqual(t, manifest.LocalConfigStore{File: "local_server/config_stores/flags.json", Format: "json"}, ls.ConfigStores["flags"])
+				testutil.AssertEqual(t, []manifest.LocalKVStore{{Key: "example", File: "local_server/kv_stores/assets/example"}}, ls.KVStores["assets"])
+				testutil.AssertEqual(t, []manifest.LocalSecretStore{{Key: "example", Env: "FASTLY_SECRET_STORE_KEYS_EXAMPLE"}}, ls.SecretStores["keys"])
+			},
+			wantFiles: map[string]string{
+				"local_server/dictionaries/settings.json": "{}\n",
+				"local_server/config_stores/flags.json":   "{}\n",
+				"local_server/kv_stores/assets/example":   "placeholder value\n",
+			},
+		},
+		{
+			name:     "force",
+			args:     "compute pull-config --service-id 123 --force --dir data",
+			manifest: "manifest_version = 2\nname = \"demo\"\n\n[local_server.backends.api]\nurl = \"http://127.0.0.1:8080\"\n",
+			wantOutput: []string{
+				"+ backend api (http://api.example.com:8080)",
+				"+ dictionary settings (data/dictionaries/settings.json)",
+				"Added 6 resources",
+			},
+			wantManifest: func(t *testing.T, ls manifest.LocalServer) {
+				testutil.AssertEqual(t, "http://api.example.com:8080", ls.Backends["api"].URL)
+			},
+		},
+		{
+			name:      "specific version",
+			args:      "compute pull-config --service-id 123 --version 1",
+			manifest:  "manifest_version = 2\nname = \"demo\"\n",
+			wantError: "error listing backends: " + testutil.Err.Error(),
+		},
+		{
+			name:      "no manifest",
+			args:      "compute pull-config --service-id 123",
+			wantError: "error reading package manifest",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			if testcase.manifest != "" {
+				if err := os.WriteFile(manifest.Filename, []byte(testcase.manifest), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --token 123"), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantManifest != nil {
+				var m manifest.File
+				if err := m.Read(manifest.Filename); err != nil {
+					t.Fatal(err)
+				}
+				testcase.wantManifest(t, m.LocalServer)
+			}
+			for name, want := range testcase.wantFiles {
+				have, err := os.ReadFile(filepath.FromSlash(name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, want, string(have))
+			}
+		})
+	}
+}
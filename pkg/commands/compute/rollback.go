@@ -0,0 +1,199 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// RollbackCommand reactivates a previously deployed service version.
+type RollbackCommand struct {
+	cmd.Base
+
+	comment        cmd.OptionalString
+	manifest       manifest.Data
+	reupload       bool
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewRollbackCommand returns a usable command registered under the parent.
+func NewRollbackCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *RollbackCommand {
+	var c RollbackCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("rollback", "Reactivate the service version deployed prior to the active version")
+	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("reupload-package", fmt.Sprintf("Clone the version and re-upload its package from the local deploy history (%s)", HistoryDir)).BoolVar(&c.reupload)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: "The version to roll back to (default: the most recent locked version prior to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RollbackCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Config.Fastly.RequireActivationComment && strings.TrimSpace(c.comment.Value) == "" {
+		c.Globals.ErrLog.Add(fsterr.ErrActivationCommentRequired)
+		return fsterr.ErrActivationCommentRequired
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	target, err := c.targetVersion(serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": c.serviceVersion.Value,
+		})
+		return err
+	}
+
+	version := target.Number
+	if c.reupload {
+		version, err = c.reuploadPackage(serviceID, target.Number, out)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": target.Number,
+			})
+			return err
+		}
+	}
+
+	if c.comment.WasSet {
+		_, err := c.Globals.APIClient.UpdateVersion(&fastly.UpdateVersionInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Comment:        &c.comment.Value,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": version,
+			})
+			return fmt.Errorf("error setting comment for service version %d: %w", version, err)
+		}
+	}
+
+	_, err = c.Globals.APIClient.ActivateVersion(&fastly.ActivateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": version,
+		})
+		return fmt.Errorf("error activating version: %w", err)
+	}
+
+	if version != target.Number {
+		text.Success(out, "Rolled back service %s to version %d (version %d with its package re-uploaded)", serviceID, version, target.Number)
+		return nil
+	}
+	text.Success(out, "Rolled back service %s to version %d", serviceID, version)
+	return nil
+}
+
+// targetVersion returns the version to roll back to.
+func (c *RollbackCommand) targetVersion(serviceID string) (*fastly.Version, error) {
+	if c.serviceVersion.Value != "" {
+		v, err := c.serviceVersion.Parse(serviceID, c.Globals.APIClient)
+		if err != nil {
+			return nil, err
+		}
+		if v.Active {
+			return nil, fmt.Errorf("service version %d is already active", v.Number)
+		}
+		return v, nil
+	}
+
+	vs, err := c.Globals.APIClient.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing service versions: %w", err)
+	}
+	active, err := cmd.GetActiveVersion(vs)
+	if err != nil {
+		return nil, err
+	}
+	return previousVersion(vs, active.Number)
+}
+
+// previousVersion returns the most recent locked version prior to the given
+// version. A version is locked once it has been activated, so this is the
+// version that was most likely deployed before the active one.
+func previousVersion(vs []*fastly.Version, before int) (*fastly.Version, error) {
+	var prev *fastly.Version
+	for _, v := range vs {
+		if v.Locked && !v.Active && v.Number < before && (prev == nil || v.Number > prev.Number) {
+			prev = v
+		}
+	}
+	if prev == nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no previously deployed version found prior to version %d", before),
+			Remediation: "Use the --version flag to specify the version to roll back to.",
+		}
+	}
+	return prev, nil
+}
+
+// reuploadPackage clones the given version and uploads the package cached for
+// it by `compute deploy`, returning the cloned version number.
+func (c *RollbackCommand) reuploadPackage(serviceID string, version int, out io.Writer) (int, error) {
+	pkgPath := historyPackagePath(serviceID, version)
+	if !filesystem.FileExists(pkgPath) {
+		return 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no cached package found for service version %d: %s", version, pkgPath),
+			Remediation: "Packages are only cached when deployed from this project directory. Retry the command without --reupload-package to reactivate the version as-is.",
+		}
+	}
+
+	clone, err := c.Globals.APIClient.CloneVersion(&fastly.CloneVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error cloning service version: %w", err)
+	}
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return 0, err
+	}
+	if err := pkgUpload(spinner, c.Globals.APIClient, serviceID, clone.Number, pkgPath); err != nil {
+		return 0, err
+	}
+	return clone.Number, nil
+}
@@ -0,0 +1,165 @@
+package compute_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestRollback(t *testing.T) {
+	// We're going to chdir to a deploy environment,
+	// so save the PWD to return to, afterwards.
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test environment
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join(compute.HistoryDir, "123", "2.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	// Before running the test, chdir into the build environment.
+	// When we're done, chdir back to our original location.
+	// This is so we can reliably copy the testdata/ fixtures.
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --service-id flag",
+			Args:      args("compute rollback"),
+			WantError: "error reading service: no service ID found",
+		},
+		{
+			Name: "validate no previous version",
+			Args: args("compute rollback -s 123"),
+			API: mock.API{
+				ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+					return []*fastly.Version{
+						{ServiceID: i.ServiceID, Number: 1, Active: true, Locked: true},
+					}, nil
+				},
+			},
+			WantError: "no previously deployed version found prior to version 1",
+		},
+		{
+			Name: "validate --version is already active",
+			Args: args("compute rollback -s 123 --version 3"),
+			API: mock.API{
+				ListVersionsFn: listRollbackVersions,
+			},
+			WantError: "service version 3 is already active",
+		},
+		{
+			Name: "validate ActivateVersion API error",
+			Args: args("compute rollback -s 123"),
+			API: mock.API{
+				ListVersionsFn:    listRollbackVersions,
+				ActivateVersionFn: activateVersionError,
+			},
+			WantError: "error activating version: " + testutil.Err.Error(),
+		},
+		{
+			Name: "success",
+			Args: args("compute rollback -s 123"),
+			API: mock.API{
+				ListVersionsFn:    listRollbackVersions,
+				ActivateVersionFn: activateRollbackVersion(2),
+			},
+			WantOutput: "Rolled back service 123 to version 2",
+		},
+		{
+			Name: "success with --version and --comment",
+			Args: args("compute rollback -s 123 --version 1 --comment bad-deploy"),
+			API: mock.API{
+				ListVersionsFn: listRollbackVersions,
+				UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+					if i.ServiceVersion != 1 || *i.Comment != "bad-deploy" {
+						return nil, fmt.Errorf("unexpected input: version %d, comment %s", i.ServiceVersion, *i.Comment)
+					}
+					return updateVersionOk(i)
+				},
+				ActivateVersionFn: activateRollbackVersion(1),
+			},
+			WantOutput: "Rolled back service 123 to version 1",
+		},
+		{
+			Name: "validate --reupload-package without a cached package",
+			Args: args("compute rollback -s 123 --version 1 --reupload-package"),
+			API: mock.API{
+				ListVersionsFn: listRollbackVersions,
+			},
+			WantError: "no cached package found for service version 1",
+		},
+		{
+			Name: "success with --reupload-package",
+			Args: args("compute rollback -s 123 --reupload-package"),
+			API: mock.API{
+				ListVersionsFn: listRollbackVersions,
+				CloneVersionFn: testutil.CloneVersionResult(5),
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					if i.ServiceVersion != 5 || i.PackagePath != filepath.Join(compute.HistoryDir, "123", "2.tar.gz") {
+						return nil, fmt.Errorf("unexpected input: version %d, path %s", i.ServiceVersion, i.PackagePath)
+					}
+					return updatePackageOk(i)
+				},
+				ActivateVersionFn: activateRollbackVersion(5),
+			},
+			WantOutputs: []string{
+				"Uploading package",
+				"Rolled back service 123 to version 5 (version 2 with its package re-uploaded)",
+			},
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err = app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func listRollbackVersions(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+	return []*fastly.Version{
+		{ServiceID: i.ServiceID, Number: 1, Locked: true},
+		{ServiceID: i.ServiceID, Number: 2, Locked: true},
+		{ServiceID: i.ServiceID, Number: 3, Active: true, Locked: true},
+		{ServiceID: i.ServiceID, Number: 4},
+	}, nil
+}
+
+func activateRollbackVersion(want int) func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+	return func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+		if i.ServiceVersion != want {
+			return nil, fmt.Errorf("unexpected version activated: %d", i.ServiceVersion)
+		}
+		return activateVersionOk(i)
+	}
+}
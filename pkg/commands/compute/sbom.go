@@ -0,0 +1,489 @@
+package compute
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/revision"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// SBOMFormats are the supported Software Bill of Materials formats.
+var SBOMFormats = []string{"cyclonedx", "spdx"}
+
+// SBOMFilenames are the names of the SBOM documents embedded in a package by
+// `compute pack --sbom`, keyed by format.
+var SBOMFilenames = map[string]string{
+	"cyclonedx": "sbom.cdx.json",
+	"spdx":      "sbom.spdx.json",
+}
+
+// sbomLockfiles are the dependency lockfiles an SBOM is generated from, and
+// their parsers.
+var sbomLockfiles = []struct {
+	name  string
+	parse func(data []byte) ([]sbomComponent, error)
+}{
+	{"Cargo.lock", parseCargoLock},
+	{"go.mod", parseGoMod},
+	{"package-lock.json", parsePackageLock},
+}
+
+// SBOMCommand generates a Software Bill of Materials for the project.
+type SBOMCommand struct {
+	cmd.Base
+	format   string
+	manifest manifest.Data
+	output   string
+}
+
+// NewSBOMCommand returns a usable command registered under the parent.
+func NewSBOMCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *SBOMCommand {
+	var c SBOMCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("sbom", "Generate a Software Bill of Materials (SBOM) from the project's dependency lockfiles (Cargo.lock, go.mod, package-lock.json)")
+	c.CmdClause.Flag("format", "SBOM document format").Default(SBOMFormats[0]).HintOptions(SBOMFormats...).EnumVar(&c.format, SBOMFormats...)
+	c.CmdClause.Flag("output", "Path to write the SBOM document to (default: stdout)").StringVar(&c.output)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *SBOMCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := c.manifest.File.ReadError(); err != nil {
+		return err
+	}
+
+	data, components, err := generateSBOM(".", c.format, c.manifest.File.Name)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if c.output == "" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(c.output, data, 0o644); err != nil { // #nosec G306
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Output": c.output,
+		})
+		return fmt.Errorf("error writing SBOM: %w", err)
+	}
+	text.Success(out, "Wrote %s SBOM of %d components to %s", c.format, components, c.output)
+	return nil
+}
+
+// sbomComponent is a dependency of the project.
+type sbomComponent struct {
+	Name    string
+	Version string
+	// PURL is the package URL (https://github.com/package-url/purl-spec).
+	PURL string
+	// SHA256 and SHA512 are hex encoded digests of the dependency's archive,
+	// when recorded by the lockfile.
+	SHA256 string
+	SHA512 string
+}
+
+// generateSBOM returns the SBOM document for the project in dir, and the
+// number of components it lists.
+//
+// The document's timestamp is the SOURCE_DATE_EPOCH, when set, so packages
+// embedding it remain reproducible.
+func generateSBOM(dir, format, name string) ([]byte, int, error) {
+	var (
+		components []sbomComponent
+		found      bool
+	)
+	for _, l := range sbomLockfiles {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as the lockfiles are within the project directory.
+		// #nosec
+		data, err := os.ReadFile(filepath.Join(dir, l.name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading %s: %w", l.name, err)
+		}
+		c, err := l.parse(data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error parsing %s: %w", l.name, err)
+		}
+		components = append(components, c...)
+		found = true
+	}
+	if !found {
+		return nil, 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no dependency lockfile found (looked for %s)", strings.Join(sbomLockfileNames(), ", ")),
+			Remediation: "Run `fastly compute build` first, as it installs the project's dependencies and so creates its lockfile.",
+		}
+	}
+	components = dedupeComponents(components)
+
+	created := time.Now().UTC()
+	if os.Getenv(SourceDateEpochEnvVar) != "" {
+		t, err := archiveTime()
+		if err != nil {
+			return nil, 0, err
+		}
+		created = t
+	}
+
+	var doc any
+	switch format {
+	case "spdx":
+		doc = spdxDocument(name, components, created)
+	default:
+		doc = cycloneDXDocument(name, components, created)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(data, '\n'), len(components), nil
+}
+
+// sbomLockfileNames returns the names of the supported lockfiles.
+func sbomLockfileNames() []string {
+	names := make([]string, 0, len(sbomLockfiles))
+	for _, l := range sbomLockfiles {
+		names = append(names, l.name)
+	}
+	return names
+}
+
+// dedupeComponents sorts the components by package URL, removing duplicates.
+func dedupeComponents(components []sbomComponent) []sbomComponent {
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].PURL < components[j].PURL
+	})
+	deduped := components[:0]
+	for i, c := range components {
+		if i > 0 && c.PURL == components[i-1].PURL {
+			continue
+		}
+		deduped = append(deduped, c)
+	}
+	return deduped
+}
+
+// parseCargoLock returns the crates of a Cargo.lock, excluding the project's
+// own (workspace) crates, which have no source.
+func parseCargoLock(data []byte) ([]sbomComponent, error) {
+	var lock struct {
+		Package []struct {
+			Name     string `toml:"name"`
+			Version  string `toml:"version"`
+			Source   string `toml:"source"`
+			Checksum string `toml:"checksum"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var components []sbomComponent
+	for _, p := range lock.Package {
+		if p.Source == "" {
+			continue
+		}
+		components = append(components, sbomComponent{
+			Name:    p.Name,
+			Version: p.Version,
+			PURL:    fmt.Sprintf("pkg:cargo/%s@%s", p.Name, p.Version),
+			SHA256:  p.Checksum,
+		})
+	}
+	return components, nil
+}
+
+// parseGoMod returns the required modules of a go.mod, which lists every
+// module the build uses (including indirect dependencies) since Go 1.17.
+func parseGoMod(data []byte) ([]sbomComponent, error) {
+	var (
+		components []sbomComponent
+		inRequire  bool
+	)
+	for i, line := range strings.Split(string(data), "\n") {
+		if j := strings.Index(line, "//"); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case inRequire:
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		default:
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid require on line %d", i+1)
+		}
+		module, version := strings.Trim(fields[0], `"`), fields[1]
+		components = append(components, sbomComponent{
+			Name:    module,
+			Version: version,
+			PURL:    fmt.Sprintf("pkg:golang/%s@%s", module, version),
+		})
+	}
+	return components, nil
+}
+
+// npmPackage is a package of a package-lock.json.
+type npmPackage struct {
+	Version      string                `json:"version"`
+	Integrity    string                `json:"integrity"`
+	Dev          bool                  `json:"dev"`
+	Link         bool                  `json:"link"`
+	Dependencies map[string]npmPackage `json:"dependencies"`
+}
+
+// parsePackageLock returns the packages of a package-lock.json, excluding
+// development dependencies as they aren't part of the Wasm binary.
+//
+// Lockfile versions 2 and 3 list the packages by their node_modules path,
+// while version 1 nests them as dependencies.
+func parsePackageLock(data []byte) ([]sbomComponent, error) {
+	var lock struct {
+		Packages     map[string]npmPackage `json:"packages"`
+		Dependencies map[string]npmPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var components []sbomComponent
+	add := func(name string, p npmPackage) {
+		if name == "" || p.Dev || p.Link || p.Version == "" {
+			return
+		}
+		c := sbomComponent{
+			Name:    name,
+			Version: p.Version,
+			PURL:    fmt.Sprintf("pkg:npm/%s@%s", strings.Replace(name, "@", "%40", 1), p.Version),
+		}
+		if digest, ok := strings.CutPrefix(p.Integrity, "sha512-"); ok {
+			if sum, err := base64.StdEncoding.DecodeString(digest); err == nil {
+				c.SHA512 = hex.EncodeToString(sum)
+			}
+		}
+		components = append(components, c)
+	}
+
+	if lock.Packages != nil {
+		for path, p := range lock.Packages {
+			// Packages outside of node_modules are the project's own.
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 {
+				continue
+			}
+			add(path[i+len("node_modules/"):], p)
+		}
+		return components, nil
+	}
+
+	var walk func(deps map[string]npmPackage)
+	walk = func(deps map[string]npmPackage) {
+		for name, p := range deps {
+			add(name, p)
+			walk(p.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return components, nil
+}
+
+// sbomSerial returns a UUID derived from the components, so that the same
+// dependencies always produce the same document.
+func sbomSerial(name string, components []sbomComponent) string {
+	h := sha256.New()
+	h.Write([]byte(name))
+	for _, c := range components {
+		h.Write([]byte("\n" + c.PURL))
+	}
+	s := hex.EncodeToString(h.Sum(nil))
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[0:8], s[8:12], s[12:16], s[16:20], s[20:32])
+}
+
+// cycloneDXDocument returns a CycloneDX 1.4 document.
+//
+// Reference: https://cyclonedx.org/docs/1.4/json/
+func cycloneDXDocument(name string, components []sbomComponent, created time.Time) any {
+	type hash struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	}
+	type component struct {
+		Type    string `json:"type"`
+		BOMRef  string `json:"bom-ref,omitempty"`
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		PURL    string `json:"purl,omitempty"`
+		Hashes  []hash `json:"hashes,omitempty"`
+	}
+	type tool struct {
+		Vendor  string `json:"vendor"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	type metadata struct {
+		Timestamp string    `json:"timestamp"`
+		Tools     []tool    `json:"tools"`
+		Component component `json:"component"`
+	}
+
+	libraries := make([]component, 0, len(components))
+	for _, c := range components {
+		l := component{
+			Type:    "library",
+			BOMRef:  c.PURL,
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.PURL,
+		}
+		if c.SHA256 != "" {
+			l.Hashes = append(l.Hashes, hash{Alg: "SHA-256", Content: c.SHA256})
+		}
+		if c.SHA512 != "" {
+			l.Hashes = append(l.Hashes, hash{Alg: "SHA-512", Content: c.SHA512})
+		}
+		libraries = append(libraries, l)
+	}
+
+	return struct {
+		BOMFormat    string      `json:"bomFormat"`
+		SpecVersion  string      `json:"specVersion"`
+		SerialNumber string      `json:"serialNumber"`
+		Version      int         `json:"version"`
+		Metadata     metadata    `json:"metadata"`
+		Components   []component `json:"components"`
+	}{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + sbomSerial(name, components),
+		Version:      1,
+		Metadata: metadata{
+			Timestamp: created.Format(time.RFC3339),
+			Tools:     []tool{{Vendor: "Fastly", Name: "fastly", Version: revision.AppVersion}},
+			Component: component{Type: "application", BOMRef: name, Name: name},
+		},
+		Components: libraries,
+	}
+}
+
+// spdxDocument returns an SPDX 2.3 document.
+//
+// Reference: https://spdx.github.io/spdx-spec/v2.3/
+func spdxDocument(name string, components []sbomComponent, created time.Time) any {
+	type checksum struct {
+		Algorithm     string `json:"algorithm"`
+		ChecksumValue string `json:"checksumValue"`
+	}
+	type externalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+	type pkg struct {
+		Name             string        `json:"name"`
+		SPDXID           string        `json:"SPDXID"`
+		VersionInfo      string        `json:"versionInfo,omitempty"`
+		DownloadLocation string        `json:"downloadLocation"`
+		FilesAnalyzed    bool          `json:"filesAnalyzed"`
+		Checksums        []checksum    `json:"checksums,omitempty"`
+		ExternalRefs     []externalRef `json:"externalRefs,omitempty"`
+	}
+	type creationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+	type relationship struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	}
+
+	root := "SPDXRef-Package-" + spdxID(name)
+	packages := []pkg{{Name: name, SPDXID: root, DownloadLocation: "NOASSERTION"}}
+	relationships := []relationship{{"SPDXRef-DOCUMENT", "DESCRIBES", root}}
+	for i, c := range components {
+		p := pkg{
+			Name:             c.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      c.Version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []externalRef{{"PACKAGE-MANAGER", "purl", c.PURL}},
+		}
+		if c.SHA256 != "" {
+			p.Checksums = append(p.Checksums, checksum{"SHA256", c.SHA256})
+		}
+		if c.SHA512 != "" {
+			p.Checksums = append(p.Checksums, checksum{"SHA512", c.SHA512})
+		}
+		packages = append(packages, p)
+		relationships = append(relationships, relationship{root, "DEPENDS_ON", p.SPDXID})
+	}
+
+	return struct {
+		SPDXVersion       string         `json:"spdxVersion"`
+		DataLicense       string         `json:"dataLicense"`
+		SPDXID            string         `json:"SPDXID"`
+		Name              string         `json:"name"`
+		DocumentNamespace string         `json:"documentNamespace"`
+		CreationInfo      creationInfo   `json:"creationInfo"`
+		Packages          []pkg          `json:"packages"`
+		Relationships     []relationship `json:"relationships"`
+	}{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", url.PathEscape(name), sbomSerial(name, components)),
+		CreationInfo: creationInfo{
+			Created:  created.Format(time.RFC3339),
+			Creators: []string{"Tool: fastly-" + revision.AppVersion},
+		},
+		Packages:      packages,
+		Relationships: relationships,
+	}
+}
+
+// spdxID returns name with the characters an SPDX identifier can't contain
+// replaced.
+func spdxID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '-'
+	}, name)
+}
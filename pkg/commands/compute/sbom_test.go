@@ -0,0 +1,212 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const sbomCargoLock = `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "fastly"
+version = "0.9.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "0a1b2c"
+
+[[package]]
+name = "my-project"
+version = "0.1.0"
+dependencies = ["fastly"]
+`
+
+const sbomGoMod = `module example.com/my-project
+
+go 1.20
+
+require github.com/fastly/compute-sdk-go v0.1.2
+
+require (
+	github.com/tetratelabs/wazero v1.0.0 // indirect
+)
+`
+
+const sbomPackageLock = `{
+  "name": "my-project",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "my-project"},
+    "node_modules/@fastly/js-compute": {"version": "1.5.0", "integrity": "sha512-AAEC"},
+    "node_modules/@fastly/js-compute/node_modules/acorn": {"version": "8.8.2"},
+    "node_modules/webpack": {"version": "5.75.0", "dev": true}
+  }
+}`
+
+const sbomPackageLockV1 = `{
+  "name": "my-project",
+  "lockfileVersion": 1,
+  "dependencies": {
+    "@fastly/js-compute": {
+      "version": "0.5.4",
+      "dependencies": {"acorn": {"version": "8.8.2"}}
+    },
+    "webpack": {"version": "5.75.0", "dev": true}
+  }
+}`
+
+func TestSBOM(t *testing.T) {
+	t.Setenv(compute.SourceDateEpochEnvVar, "1672531200")
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		files      map[string]string
+		wantError  string
+		wantOutput []string
+	}{
+		{
+			name:      "validate missing lockfile",
+			args:      "compute sbom",
+			wantError: "no dependency lockfile found (looked for Cargo.lock, go.mod, package-lock.json)",
+		},
+		{
+			name:      "validate invalid lockfile",
+			args:      "compute sbom",
+			files:     map[string]string{"package-lock.json": "{"},
+			wantError: "error parsing package-lock.json",
+		},
+		{
+			name:  "cyclonedx from Cargo.lock",
+			args:  "compute sbom",
+			files: map[string]string{"Cargo.lock": sbomCargoLock},
+			wantOutput: []string{
+				`"bomFormat": "CycloneDX"`,
+				`"timestamp": "2023-01-01T00:00:00Z"`,
+				`"purl": "pkg:cargo/fastly@0.9.1"`,
+				`"alg": "SHA-256",
+          "content": "0a1b2c"`,
+			},
+		},
+		{
+			name:  "spdx from go.mod",
+			args:  "compute sbom --format spdx",
+			files: map[string]string{"go.mod": sbomGoMod},
+			wantOutput: []string{
+				`"spdxVersion": "SPDX-2.3"`,
+				`"created": "2023-01-01T00:00:00Z"`,
+				`"referenceLocator": "pkg:golang/github.com/fastly/compute-sdk-go@v0.1.2"`,
+				`"referenceLocator": "pkg:golang/github.com/tetratelabs/wazero@v1.0.0"`,
+				`"relationshipType": "DEPENDS_ON"`,
+			},
+		},
+		{
+			name:  "cyclonedx from package-lock.json",
+			args:  "compute sbom",
+			files: map[string]string{"package-lock.json": sbomPackageLock},
+			wantOutput: []string{
+				`"purl": "pkg:npm/%40fastly/js-compute@1.5.0"`,
+				`"purl": "pkg:npm/acorn@8.8.2"`,
+				`"alg": "SHA-512",
+          "content": "000102"`,
+			},
+		},
+		{
+			name:  "cyclonedx from package-lock.json version 1",
+			args:  "compute sbom",
+			files: map[string]string{"package-lock.json": sbomPackageLockV1},
+			wantOutput: []string{
+				`"purl": "pkg:npm/%40fastly/js-compute@0.5.4"`,
+				`"purl": "pkg:npm/acorn@8.8.2"`,
+			},
+		},
+		{
+			name:       "output file",
+			args:       "compute sbom --output sbom.json",
+			files:      map[string]string{"Cargo.lock": sbomCargoLock, "go.mod": sbomGoMod},
+			wantOutput: []string{"Wrote cyclonedx SBOM of 3 components to sbom.json"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			write := []testutil.FileIO{
+				{Src: "manifest_version = 2\nname = \"my-project\"\n", Dst: manifest.Filename},
+			}
+			for name, content := range testcase.files {
+				write = append(write, testutil.FileIO{Src: content, Dst: name})
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{T: t, Write: write})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(pwd)
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err = app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantError == "" {
+				testutil.AssertBool(t, false, bytes.Contains(stdout.Bytes(), []byte("webpack")))
+			}
+		})
+	}
+}
+
+func TestPackSBOM(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{Src: filepath.Join("testdata", "pack", "main.wasm"), Dst: "main.wasm"},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"my-project\"\n", Dst: manifest.Filename},
+			{Src: sbomCargoLock, Dst: "Cargo.lock"},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute pack --wasm-binary ./main.wasm --sbom spdx"), &stdout)
+	err = app.Run(opts)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Generating SBOM")
+
+	var doc struct {
+		Name     string `json:"name"`
+		Packages []struct {
+			Name string `json:"name"`
+		} `json:"packages"`
+	}
+	data := readArchiveFile(t, filepath.Join("pkg", "package.tar.gz"), compute.SBOMFilenames["spdx"])
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "my-project", doc.Name)
+	testutil.AssertEqual(t, 2, len(doc.Packages))
+	testutil.AssertString(t, "fastly", doc.Packages[1].Name)
+}
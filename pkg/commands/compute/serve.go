@@ -41,20 +41,58 @@ type ServeCommand struct {
 	av       github.AssetVersioner
 
 	// Build fields
-	includeSrc  cmd.OptionalBool
-	lang        cmd.OptionalString
-	packageName cmd.OptionalString
-	timeout     cmd.OptionalInt
+	frozen       cmd.OptionalBool
+	cache        cmd.OptionalBool
+	includeSrc   cmd.OptionalBool
+	lang         cmd.OptionalString
+	optimize     cmd.OptionalBool
+	packageName  cmd.OptionalString
+	quietSuccess cmd.OptionalBool
+	timeout      cmd.OptionalInt
+	verifyRepro  cmd.OptionalBool
 
 	// Serve fields
 	addr           string
+	backends       []string
+	clientIP       string
+	compose        string
+	composeUp      bool
 	debug          bool
-	env            cmd.OptionalString
 	file           string
+	fresh          bool
+	liveReload     bool
+	geo            map[string]any
+	overrideGeo    []string
+	packages       []string
+	persistStores  bool
+	profileGuest   bool
+	profileOut     cmd.OptionalString
+	record         string
+	replay         string
 	skipBuild      bool
+	tls            bool
+	tlsCert        string
+	tlsKey         string
 	viceroyBinPath string
 	watch          bool
+	watchDebounce  time.Duration
 	watchDir       cmd.OptionalString
+	watchExclude   []string
+	watchInclude   []string
+	workspace      string
+}
+
+// watchOptions configures which files are watched by --watch and how quickly
+// changes trigger a restart.
+type watchOptions struct {
+	debounce time.Duration
+	dir      cmd.OptionalString
+	exclude  []string
+	include  []string
+	// stores are the files backing the local stores. They're watched even
+	// without --watch, and a change restarts the local server without
+	// rebuilding the project.
+	stores []string
 }
 
 // NewServeCommand returns a usable command registered under the parent.
@@ -69,17 +107,41 @@ func NewServeCommand(parent cmd.Registerer, g *global.Data, build *BuildCommand,
 	c.manifest = m
 
 	c.CmdClause.Flag("addr", "The IPv4 address and port to listen on").Default("127.0.0.1:7676").StringVar(&c.addr)
+	c.CmdClause.Flag("backend", "Override a [local_server.backends] URL, as <name>=<url> or <name>=service:<compose service>:<port> (set flag once per backend)").StringsVar(&c.backends)
+	c.CmdClause.Flag("cache", fmt.Sprintf("Skip the build if nothing has changed since the last build (see %s), use --no-cache to force a rebuild", BuildCacheDir)).Action(c.cache.Set).NegatableBoolVar(&c.cache.Value)
+	c.CmdClause.Flag("client-ip", "Geolocate local requests as this IP address, using its [local_server.geolocation] entry").StringVar(&c.clientIP)
+	c.CmdClause.Flag("compose", "Path to a docker compose file whose services can be used as backends (see --backend)").StringVar(&c.compose)
+	c.CmdClause.Flag("compose-up", "Start the --compose services before serving, and stop them afterwards").BoolVar(&c.composeUp)
 	c.CmdClause.Flag("debug", "Run the server in Debug Adapter mode").Hidden().BoolVar(&c.debug)
-	c.CmdClause.Flag("env", "The environment configuration to use (e.g. stage)").Action(c.env.Set).StringVar(&c.env.Value)
 	c.CmdClause.Flag("file", "The Wasm file to run").Default("bin/main.wasm").StringVar(&c.file)
+	c.CmdClause.Flag("fresh", "Reset the store data kept by --persist-stores to the [local_server] values of the manifest").BoolVar(&c.fresh)
+	c.CmdClause.Flag("frozen", fmt.Sprintf("Fail the build if the toolchain versions don't match those recorded in %s", LockFilename)).Action(c.frozen.Set).BoolVar(&c.frozen.Value)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
+	c.CmdClause.Flag("live-reload", "Reload the browser after each rebuild, by injecting a script into HTML responses (requires --watch)").BoolVar(&c.liveReload)
+	c.CmdClause.Flag("optimize", "Optimize the Wasm binary with wasm-opt before packaging it (downloaded on first use)").Action(c.optimize.Set).BoolVar(&c.optimize.Value)
+	c.CmdClause.Flag("override-geo", "Override the geolocation data of local requests, as comma separated <field>=<value> pairs, e.g. country_code=GB,city=London (set flag once per override)").StringsVar(&c.overrideGeo)
+	c.CmdClause.Flag("package", "Serve a package alongside others, as <dir> or [<host>][/<path>]=<dir> to route requests for the host and/or path prefix to it (set flag once per package)").StringsVar(&c.packages)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("persist-stores", fmt.Sprintf("Serve the emulated stores from data files in %s, so edits made to those files are kept across runs. Data written by the package at runtime isn't kept", ServeStateDir)).BoolVar(&c.persistStores)
+	c.CmdClause.Flag("quiet-success", "Only print the compiler output if the build fails, even with --verbose").Action(c.quietSuccess.Set).BoolVar(&c.quietSuccess.Value)
+	c.CmdClause.Flag("profile-guest", "Profile the Wasm guest under Viceroy, writing one profile per request").BoolVar(&c.profileGuest)
+	c.CmdClause.Flag("profile-out", fmt.Sprintf("The directory guest profiles are written to (default: %s)", GuestProfileDir)).Action(c.profileOut.Set).StringVar(&c.profileOut.Value)
+	c.CmdClause.Flag("record", "Append each incoming request to a newline delimited JSON file (e.g. requests.ndjson)").StringVar(&c.record)
+	c.CmdClause.Flag("replay", "Resend the requests recorded by --record once the local server is running").StringVar(&c.replay)
 	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.skipBuild)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
+	c.CmdClause.Flag("tls", "Serve over HTTPS using a self-signed certificate (generated on first use)").BoolVar(&c.tls)
+	c.CmdClause.Flag("tls-cert", "Path to a PEM encoded certificate to serve over HTTPS with (requires --tls-key)").StringVar(&c.tlsCert)
+	c.CmdClause.Flag("tls-key", "Path to the PEM encoded private key of --tls-cert").StringVar(&c.tlsKey)
+	c.CmdClause.Flag("verify-reproducible", "Build the package a second time and fail if the packages differ").Action(c.verifyRepro.Set).BoolVar(&c.verifyRepro.Value)
 	c.CmdClause.Flag("viceroy-path", "The path to a user installed version of the Viceroy binary").StringVar(&c.viceroyBinPath)
 	c.CmdClause.Flag("watch", "Watch for file changes, then rebuild project and restart local server").BoolVar(&c.watch)
+	c.CmdClause.Flag("watch-debounce", "How long to wait after the last file change before rebuilding (e.g. 500ms, 2s)").Default("1s").DurationVar(&c.watchDebounce)
 	c.CmdClause.Flag("watch-dir", "The directory to watch files from (can be relative or absolute). Defaults to current directory.").Action(c.watchDir.Set).StringVar(&c.watchDir.Value)
+	c.CmdClause.Flag("watch-exclude", "A .gitignore style pattern of files not to watch, in addition to .fastlyignore (set flag once per pattern)").StringsVar(&c.watchExclude)
+	c.CmdClause.Flag("watch-include", "A .gitignore style pattern of files to watch, e.g. 'src/**/*.rs' (set flag once per pattern, default: all files)").StringsVar(&c.watchInclude)
+	c.CmdClause.Flag("workspace", fmt.Sprintf("Path to a workspace file (e.g. %s) listing the packages to serve together and their routes", WorkspaceFilename)).StringVar(&c.workspace)
 
 	return &c
 }
@@ -89,6 +151,79 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	if c.skipBuild && c.watch {
 		return fsterr.ErrIncompatibleServeFlags
 	}
+	if !c.watch && (len(c.watchInclude) > 0 || len(c.watchExclude) > 0) {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--watch-include and --watch-exclude require --watch"),
+			Remediation: "Add the --watch flag to rebuild and restart the local server on file changes.",
+		}
+	}
+	if (c.tlsCert == "") != (c.tlsKey == "") {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--tls-cert and --tls-key must be set together"),
+			Remediation: "Provide both the certificate and its private key, or use --tls to serve a self-signed certificate.",
+		}
+	}
+	if c.composeUp && c.compose == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--compose-up requires --compose"),
+			Remediation: "Provide the path to the docker compose file via --compose.",
+		}
+	}
+	if c.liveReload && !c.watch {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--live-reload requires --watch"),
+			Remediation: "Add the --watch flag to rebuild and restart the local server on file changes.",
+		}
+	}
+	if c.fresh && !c.persistStores {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--fresh requires --persist-stores"),
+			Remediation: "Add the --persist-stores flag to keep the data of the emulated stores across runs.",
+		}
+	}
+	if c.profileOut.WasSet && !c.profileGuest {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--profile-out requires --profile-guest"),
+			Remediation: "Add the --profile-guest flag to profile the Wasm guest.",
+		}
+	}
+	if c.watchDebounce < 0 {
+		return fmt.Errorf("--watch-debounce can't be negative: %s", c.watchDebounce)
+	}
+	if c.clientIP != "" && net.ParseIP(c.clientIP) == nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --client-ip: %s", c.clientIP),
+			Remediation: "Provide an IPv4 or IPv6 address, e.g. --client-ip 203.0.113.7",
+		}
+	}
+	c.geo, err = ParseGeoOverrides(c.overrideGeo)
+	if err != nil {
+		return err
+	}
+
+	var workspace Workspace
+	if c.workspace != "" || len(c.packages) > 0 {
+		if c.watch || c.debug || c.tls || c.tlsCert != "" || c.record != "" || c.replay != "" || c.profileGuest {
+			return fsterr.RemediationError{
+				Inner:       errors.New("--package and --workspace can't be used with --watch, --tls, --record, --replay or --profile-guest"),
+				Remediation: "Serve the package on its own to use those flags.",
+			}
+		}
+		workspace, err = c.readWorkspace()
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+
+	var replay []RecordedRequest
+	if c.replay != "" {
+		replay, err = ReadRecordedRequests(c.replay)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error reading --replay file: %w", err)
+		}
+	}
 
 	if runtime.GOARCH == "386" {
 		return fsterr.RemediationError{
@@ -97,7 +232,8 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
-	if !c.skipBuild {
+	// The packages of a workspace are built in their own directories.
+	if !c.skipBuild && len(workspace.Packages) == 0 {
 		err = c.Build(in, out)
 		if err != nil {
 			return err
@@ -116,6 +252,97 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	wd, err := os.Getwd()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	manifestPath := localManifestPath(wd, c.Globals.Flags.Env)
+	stores := LocalStoreFiles(c.Globals.Manifest.File.LocalServer, filepath.Dir(manifestPath))
+
+	if c.composeUp {
+		composeDown, err := ComposeUp(c.compose, out)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer composeDown()
+	}
+	backends, err := ResolveLocalBackends(c.backends, c.compose)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if len(workspace.Packages) > 0 {
+		return c.serveWorkspace(workspace, bin, backends, out)
+	}
+
+	overrides := c.localOverrides(backends)
+	overrides.Env = c.serviceEnv(out)
+
+	// Viceroy only serves plain HTTP and doesn't record requests, so a proxy in
+	// front of it handles those features.
+	proxyOpts := LocalProxyOptions{
+		TLS:      c.tls || c.tlsCert != "",
+		CertFile: c.tlsCert,
+		KeyFile:  c.tlsKey,
+		CertDir:  filepath.Join(InstallDir, "serve-tls"),
+	}
+	if c.record != "" {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we trust the source of the variable.
+		// #nosec
+		f, err := os.OpenFile(c.record, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error opening --record file: %w", err)
+		}
+		defer f.Close()
+		proxyOpts.Record = f
+	}
+	if c.liveReload {
+		proxyOpts.LiveReload = NewLiveReload()
+	}
+
+	addr, listenURL := c.addr, "http://"+c.addr
+	if proxyOpts.Enabled() {
+		p, err := StartLocalProxy(c.addr, proxyOpts)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer p.Close()
+
+		addr, listenURL = p.Upstream, p.URL()
+		if proxyOpts.TLS && c.tlsCert == "" {
+			text.Info(out, "Serving HTTPS with a self-signed certificate. To avoid browser warnings, add it to your trusted certificates: %s", p.CertFile)
+		}
+		if c.record != "" {
+			text.Info(out, "Recording requests to %s", c.record)
+		}
+		if c.liveReload {
+			text.Info(out, "Browsers viewing HTML pages will reload after each rebuild")
+		}
+		if c.Globals.Verbose() {
+			text.Info(out, "Proxying %s to Viceroy on %s", listenURL, addr)
+		}
+	}
+
+	if c.replay != "" {
+		go func() {
+			if err := waitForServer(addr, 30*time.Second); err != nil {
+				text.Error(out, "%s", err)
+				return
+			}
+			text.Info(out, "Replaying %d requests from %s", len(replay), c.replay)
+			if err := ReplayRequests(replay, listenURL, out); err != nil {
+				text.Error(out, "%s", err)
+			}
+		}()
+	}
+
 	err = spinner.Start()
 	if err != nil {
 		return err
@@ -129,8 +356,57 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	var profileDir string
+	if c.profileGuest {
+		profileDir = GuestProfileDir
+		if c.profileOut.WasSet {
+			profileDir = c.profileOut.Value
+		}
+		profileDir, err = filepath.Abs(profileDir)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		if err := os.MkdirAll(profileDir, 0o750); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error creating guest profile directory: %w", err)
+		}
+		defer displayGuestProfiles(profileDir, time.Now(), out)
+	}
+
+	var restarted bool
 	for {
-		err = local(bin, c.file, c.addr, c.env.Value, c.debug, c.watch, c.watchDir, c.Globals.Verbose(), out, c.Globals.ErrLog)
+		if restarted && proxyOpts.LiveReload != nil {
+			go func() {
+				if err := waitForServer(addr, 30*time.Second); err == nil {
+					proxyOpts.LiveReload.Reload()
+				}
+			}()
+		}
+		restarted = true
+
+		wo := watchOptions{
+			debounce: c.watchDebounce,
+			dir:      c.watchDir,
+			exclude:  c.watchExclude,
+			include:  c.watchInclude,
+			stores:   stores,
+		}
+		// NOTE: The manifest is prepared each time the local server starts, so
+		// that changes to the files backing the stores are merged into the data
+		// persisted by --persist-stores, which Viceroy is then run with.
+		servePath, stopLocalServer, err := PrepareLocalServer(manifestPath, overrides, c.Globals.Verbose(), out)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		overrides.FreshStores = false
+
+		err = local(bin, servePath, c.file, addr, listenURL, profileDir, c.debug, c.watch, wo, c.Globals.Verbose(), out, c.Globals.ErrLog)
+		stopLocalServer()
+		if err == fsterr.ErrViceroyReload {
+			continue
+		}
 		if err != nil {
 			if err != fsterr.ErrViceroyRestart {
 				if err == fsterr.ErrSignalInterrupt || err == fsterr.ErrSignalKilled {
@@ -153,21 +429,57 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 }
 
+// localOverrides returns the [local_server] settings overridden by flags.
+func (c *ServeCommand) localOverrides(backends map[string]string) LocalOverrides {
+	return LocalOverrides{
+		Backends:      backends,
+		ClientIP:      c.clientIP,
+		Geo:           c.geo,
+		Environment:   c.Globals.Flags.Env,
+		PersistStores: c.persistStores,
+		FreshStores:   c.fresh,
+	}
+}
+
 // Build constructs and executes the build logic.
 func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
 	// Reset the fields on the BuildCommand based on ServeCommand values.
+	if c.frozen.WasSet {
+		c.build.Flags.Frozen = c.frozen.Value
+	}
 	if c.includeSrc.WasSet {
 		c.build.Flags.IncludeSrc = c.includeSrc.Value
 	}
 	if c.lang.WasSet {
 		c.build.Flags.Lang = c.lang.Value
 	}
+	if c.optimize.WasSet {
+		c.build.Flags.Optimize = c.optimize.Value
+	}
+	if c.cache.WasSet {
+		c.build.Flags.NoCache = !c.cache.Value
+	}
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.quietSuccess.WasSet {
+		c.build.Flags.QuietSuccess = c.quietSuccess.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
+	if c.verifyRepro.WasSet {
+		c.build.Flags.VerifyReproducible = c.verifyRepro.Value
+	}
+	// Rebuilds are interleaved with the local server's output, so the
+	// compiler output is prefixed to tell them apart.
+	if c.watch {
+		name := c.manifest.File.Name
+		if name == "" {
+			name = "build"
+		}
+		c.build.Flags.OutputPrefix = outputPrefix(name)
+	}
 
 	err := c.build.Exec(in, out)
 	if err != nil {
@@ -213,6 +525,9 @@ func (c *ServeCommand) setBackendsWithDefaultOverrideHostIfMissing(out io.Writer
 // latest version and install it in the same directory as the application
 // configuration data.
 //
+// A version pinned by [local_server] viceroy_version in the manifest is used
+// instead of the latest.
+//
 // In the case of a network failure we fallback to the latest installed version of the
 // Viceroy binary as long as one is installed and has the correct permissions.
 func GetViceroy(
@@ -243,6 +558,11 @@ func GetViceroy(
 		return filepath.Abs(path)
 	}
 
+	// A version pinned in fastly.toml is used instead of the latest release.
+	if version := g.Manifest.File.LocalServer.ViceroyVersion; version != "" {
+		return getPinnedViceroy(spinner, out, av, g, version)
+	}
+
 	bin = filepath.Join(InstallDir, av.BinaryName())
 
 	// NOTE: When checking if Viceroy is installed we don't use
@@ -364,6 +684,83 @@ func GetViceroy(
 	return bin, nil
 }
 
+// PinnedViceroyDir is the directory, within InstallDir, that the Viceroy
+// versions pinned by [local_server] viceroy_version are installed in.
+const PinnedViceroyDir = "viceroy-versions"
+
+// getPinnedViceroy returns the path to the Viceroy release pinned in the
+// manifest, downloading it if it isn't installed.
+//
+// NOTE: Each pinned version is installed in its own directory so switching
+// between projects doesn't replace the auto-updated install.
+func getPinnedViceroy(spinner text.Spinner, out io.Writer, av github.AssetVersioner, g *global.Data, version string) (string, error) {
+	pinned, err := semver.ParseTolerant(version)
+	if err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid [local_server] viceroy_version '%s': %w", version, err),
+			Remediation: "Set viceroy_version in fastly.toml to a Viceroy release, e.g. \"0.5.1\".",
+		}
+	}
+
+	bin := filepath.Join(InstallDir, PinnedViceroyDir, pinned.String(), av.BinaryName())
+
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the variables come from trusted sources.
+	/* #nosec */
+	// nosemgrep
+	if output, err := exec.Command(bin, "--version").CombinedOutput(); err == nil {
+		if installed := strings.Fields(string(output)); len(installed) > 1 && installed[1] == pinned.String() {
+			if g.Verbose() {
+				text.Info(out, "Using Viceroy %s pinned by [local_server] viceroy_version: %s", pinned, bin)
+				text.Break(out)
+			}
+			return bin, nil
+		}
+	}
+
+	err = spinner.Start()
+	if err != nil {
+		return bin, err
+	}
+	msg := fmt.Sprintf("Fetching Viceroy release %s (pinned by [local_server] viceroy_version)", pinned)
+	spinner.Message(msg + "...")
+
+	tmpBin, err := av.DownloadVersion(pinned.String())
+	if err == nil {
+		defer os.RemoveAll(tmpBin)
+		err = os.MkdirAll(filepath.Dir(bin), 0o750)
+	}
+	if err == nil {
+		if err = os.Rename(tmpBin, bin); err != nil {
+			err = filesystem.CopyFile(tmpBin, bin)
+		}
+	}
+	if err != nil {
+		g.ErrLog.Add(err)
+		spinner.StopFailMessage(msg)
+		spinErr := spinner.StopFail()
+		if spinErr != nil {
+			return bin, spinErr
+		}
+		return bin, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error installing Viceroy %s: %w", pinned, err),
+			Remediation: fmt.Sprintf("Check %s is a Viceroy release (https://github.com/fastly/Viceroy/releases). %s", pinned, fsterr.NetworkRemediation),
+		}
+	}
+
+	spinner.StopMessage(msg)
+	if err := spinner.Stop(); err != nil {
+		return bin, err
+	}
+
+	err = setBinPerms(bin)
+	if err != nil {
+		g.ErrLog.Add(err)
+	}
+	return bin, err
+}
+
 // checkViceroyEnvVar indicates if the CLI should use a Viceroy binary exposed
 // on the user's $PATH.
 func checkViceroyEnvVar(value string) bool {
@@ -562,24 +959,17 @@ func setBinPerms(bin string) error {
 }
 
 // local spawns a subprocess that runs the compiled binary.
-func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.OptionalString, verbose bool, out io.Writer, errLog fsterr.LogInterface) error {
-	if env != "" {
-		env = "." + env
-	}
-
-	wd, err := os.Getwd()
-	if err != nil {
-		errLog.Add(err)
-		return err
-	}
-
-	manifestPath := filepath.Join(wd, fmt.Sprintf("fastly%s.toml", env))
+func local(bin, manifestPath, file, addr, listenURL, profileDir string, debug, watch bool, wo watchOptions, verbose bool, out io.Writer, errLog fsterr.LogInterface) error {
 	args := []string{"-C", manifestPath, "--addr", addr, file}
 
 	if debug {
 		args = append(args, "--debug")
 	}
 
+	if profileDir != "" {
+		args = append(args, "--profile-guest="+profileDir)
+	}
+
 	if verbose {
 		text.Break(out)
 		text.Output(out, "%s: %s", text.BoldYellow("Manifest"), manifestPath)
@@ -589,7 +979,7 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 	} else {
 		// IMPORTANT: Viceroy 0.4.0 changed its INFO log output behind a -v flag.
 		// We display the address unless in verbose mode to avoid duplicate output.
-		text.Info(out, "Listening on http://%s", addr)
+		text.Info(out, "Listening on %s", listenURL)
 	}
 
 	s := &fstexec.Streaming{
@@ -602,19 +992,28 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 	}
 	s.MonitorSignals()
 
+	// stop ends the file watchers once the process has finished, so a watcher
+	// never signals a process that has already been restarted.
+	stop := make(chan struct{})
+	defer close(stop)
+
 	restart := make(chan bool)
+	reload := make(chan bool)
+	if len(wo.stores) > 0 {
+		go watchStoreFiles(wo.stores, wo.debounce, verbose, s, out, reload, stop)
+	}
 	if watch {
 		root := "."
-		if watchDir.WasSet {
-			root = watchDir.Value
+		if wo.dir.WasSet {
+			root = wo.dir.Value
 		}
 
 		if verbose {
 			text.Info(out, "Watching files for changes (using --watch-dir=%s). To ignore certain files, define patterns within a .fastlyignore config file (uses .fastlyignore from --watch-dir).", root)
 		}
 
-		gi := ignoreFiles(watchDir)
-		go watchFiles(root, gi, verbose, s, out, restart)
+		gi := ignoreFiles(wo.dir)
+		go watchFiles(root, gi, compilePatterns(wo.include), compilePatterns(wo.exclude), wo.debounce, verbose, s, out, restart, stop)
 	}
 
 	// NOTE: Once we run the viceroy executable, then it can be stopped by one of
@@ -648,6 +1047,9 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 			case <-restart:
 				s.SignalCh <- syscall.SIGTERM
 				return fsterr.ErrViceroyRestart
+			case <-reload:
+				s.SignalCh <- syscall.SIGTERM
+				return fsterr.ErrViceroyReload
 			case <-time.After(1 * time.Second):
 				return fsterr.ErrSignalKilled
 			}
@@ -660,16 +1062,28 @@ func local(bin, file, addr, env string, debug, watch bool, watchDir cmd.Optional
 
 // watchFiles watches the language source directory and restarts the viceroy
 // executable when changes are detected.
-func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Streaming, out io.Writer, restart chan<- bool) {
+//
+// If include is non-nil, only files matching its patterns are watched, while
+// files matching exclude are never watched. Changes are debounced so that a
+// burst of changes (e.g. saving several files) results in a single restart.
+//
+// The watcher is closed when a change is detected or stop is closed.
+func watchFiles(root string, gi, include, exclude *ignore.GitIgnore, debounceDelay time.Duration, verbose bool, s *fstexec.Streaming, out io.Writer, restart chan<- bool, stop <-chan struct{}) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer watcher.Close()
 
-	done := make(chan bool)
-	debounced := debounce.New(1 * time.Second)
+	done := make(chan bool, 1)
+	debounced := debounce.New(debounceDelay)
 	eventHandler := func(modifiedFile string, _ fsnotify.Op) {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
 		// NOTE: We avoid describing the file operation (e.g. created, modified,
 		// deleted, renamed etc) rather than checking the fsnotify.Op iota/enum type
 		// because the output can be confusing depending on the application used to
@@ -717,7 +1131,10 @@ func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Stre
 			log.Fatal(err)
 		}
 
-		restart <- true
+		select {
+		case restart <- true:
+		case <-stop:
+		}
 	}
 
 	go func() {
@@ -754,7 +1171,7 @@ func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Stre
 		if gi == nil && entry.IsDir() {
 			watchFile(path, watcher, verbose, &buf)
 		}
-		if gi != nil && !entry.IsDir() && !gi.MatchesPath(path) {
+		if gi != nil && !entry.IsDir() && !gi.MatchesPath(path) && watched(root, path, include, exclude) {
 			// If there is an ignore file, we avoid watching directories and instead
 			// will only add files that don't match the exclusion patterns defined.
 			watchFile(path, watcher, verbose, &buf)
@@ -772,12 +1189,99 @@ func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Stre
 		text.Break(out)
 	}
 
-	<-done
+	select {
+	case <-done:
+	case <-stop:
+	}
+}
+
+// watchStoreFiles watches the files backing the local stores and restarts the
+// viceroy executable, without rebuilding the project, when they change.
+//
+// NOTE: The parent directories are watched (rather than the files) because
+// editors commonly replace a file when saving it, which would otherwise stop
+// the file from being watched.
+func watchStoreFiles(files []string, debounceDelay time.Duration, verbose bool, s *fstexec.Streaming, out io.Writer, reload chan<- bool, stop <-chan struct{}) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		text.Warning(out, "Unable to watch the local store files for changes: %s", err)
+		return
+	}
+	defer watcher.Close()
+
+	watching := make(map[string]bool, len(files))
+	for _, f := range files {
+		watching[f] = true
+	}
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		dir := filepath.Dir(f)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := watcher.Add(dir); err != nil {
+			text.Warning(out, "Unable to watch '%s' for changes: %s", dir, err)
+		}
+	}
+	if verbose {
+		text.Info(out, "Watching the files backing the local stores for changes:\n\t%s", strings.Join(files, "\n\t"))
+	}
+
+	done := make(chan bool, 1)
+	debounced := debounce.New(debounceDelay)
+	eventHandler := func(modifiedFile string) {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		text.Break(out)
+		text.Output(out, "%s Reloading local server (%s)", text.BoldGreen("✓"), modifiedFile)
+
+		// NOTE: See watchFiles() for why the process is killed and the watcher
+		// closed before signalling the reload.
+		done <- true
+		if err := s.Signal(os.Kill); err != nil {
+			log.Fatal(err)
+		}
+
+		select {
+		case reload <- true:
+		case <-stop:
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !watching[filepath.Clean(event.Name)] {
+				continue
+			}
+			debounced(func() {
+				eventHandler(event.Name)
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			text.Output(out, "error event while watching files: %v", err)
+		case <-done:
+			return
+		case <-stop:
+			return
+		}
+	}
 }
 
 // ignoreFiles returns the specific ignore rules being respected.
 //
-// NOTE: We also ignore the .git directory.
+// NOTE: We also ignore the .git and .fastly directories (the latter contains
+// the build cache, which is written by every build).
 func ignoreFiles(watchDir cmd.OptionalString) *ignore.GitIgnore {
 	var patterns []string
 
@@ -796,11 +1300,33 @@ func ignoreFiles(watchDir cmd.OptionalString) *ignore.GitIgnore {
 		patterns = append(patterns, readIgnoreFile(file)...)
 	}
 
-	patterns = append(patterns, ".git/")
+	patterns = append(patterns, ".git/", ".fastly/")
 
 	return ignore.CompileIgnoreLines(patterns...)
 }
 
+// compilePatterns compiles the .gitignore style patterns provided via the
+// --watch-include and --watch-exclude flags (nil if there are none).
+func compilePatterns(patterns []string) *ignore.GitIgnore {
+	if len(patterns) == 0 {
+		return nil
+	}
+	return ignore.CompileIgnoreLines(patterns...)
+}
+
+// watched indicates if the file (relative to the watched root directory)
+// matches the include patterns (if any) and none of the exclude patterns.
+func watched(root, path string, include, exclude *ignore.GitIgnore) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	if include != nil && !include.MatchesPath(rel) {
+		return false
+	}
+	return exclude == nil || !exclude.MatchesPath(rel)
+}
+
 // readIgnoreFile reads path and splits content into lines.
 //
 // NOTE: If there's an error reading the given path, then we'll return an empty
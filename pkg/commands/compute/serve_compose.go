@@ -0,0 +1,133 @@
+package compute
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// composeServicePrefix is the prefix of a --backend value that references a
+// docker compose service, e.g. api=service:api:8080.
+const composeServicePrefix = "service:"
+
+// ResolveLocalBackends parses the --backend flag values (name=URL or
+// name=service:<compose service>:<port>) into a map of backend names to URLs.
+//
+// Compose services are resolved to the host port docker publishes the
+// service's container port on, so the compose file must publish the port.
+func ResolveLocalBackends(values []string, composeFile string) (map[string]string, error) {
+	backends := make(map[string]string, len(values))
+	for _, v := range values {
+		name, target, ok := strings.Cut(v, "=")
+		if !ok || name == "" || target == "" {
+			return nil, fmt.Errorf("invalid --backend '%s': expected <name>=<url> or <name>=service:<compose service>:<port>", v)
+		}
+
+		if !strings.HasPrefix(target, composeServicePrefix) {
+			u, err := url.Parse(target)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return nil, fmt.Errorf("invalid --backend '%s': '%s' isn't a URL", v, target)
+			}
+			backends[name] = target
+			continue
+		}
+
+		service, port, ok := strings.Cut(strings.TrimPrefix(target, composeServicePrefix), ":")
+		if !ok || service == "" || port == "" {
+			return nil, fmt.Errorf("invalid --backend '%s': expected <name>=service:<compose service>:<port>", v)
+		}
+		if composeFile == "" {
+			return nil, fmt.Errorf("--backend '%s' references a compose service but --compose isn't set", v)
+		}
+		addr, err := composePort(composeFile, service, port)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving --backend '%s': %w", v, err)
+		}
+		backends[name] = "http://" + addr
+	}
+	return backends, nil
+}
+
+// composePort returns the local address docker publishes the service's
+// container port on.
+func composePort(composeFile, service, port string) (string, error) {
+	output, err := composeOutput(composeFile, "port", service, port)
+	if err != nil {
+		return "", err
+	}
+
+	// The output is e.g. 0.0.0.0:49153 (with a line per published address).
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return "", fmt.Errorf("port %s of compose service '%s' isn't published", port, service)
+	}
+	_, hostPort, err := net.SplitHostPort(lines[0])
+	if err != nil || hostPort == "0" {
+		return "", fmt.Errorf("unexpected output from `docker compose port`: %s", lines[0])
+	}
+	return net.JoinHostPort("127.0.0.1", hostPort), nil
+}
+
+// ComposeUp starts the compose services in the background, and returns a
+// function that stops them.
+func ComposeUp(composeFile string, out io.Writer) (func(), error) {
+	text.Info(out, "Starting docker compose services (%s)", composeFile)
+	if _, err := composeOutput(composeFile, "up", "--detach"); err != nil {
+		return nil, err
+	}
+	return func() {
+		text.Info(out, "Stopping docker compose services (%s)", composeFile)
+		if _, err := composeOutput(composeFile, "down"); err != nil {
+			text.Warning(out, "%s", err)
+		}
+	}, nil
+}
+
+// composeOutput runs `docker compose` with the given compose file.
+func composeOutput(composeFile string, args ...string) ([]byte, error) {
+	args = append([]string{"compose", "--file", composeFile}, args...)
+
+	var stderr bytes.Buffer
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the arguments are provided by the user.
+	// #nosec
+	// nosemgrep
+	c := exec.Command("docker", args...)
+	c.Stderr = &stderr
+	c.Env = os.Environ()
+	output, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running `docker %s`: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// overrideLocalBackends sets the URL of the [local_server.backends] given via
+// --backend, adding any that aren't defined.
+//
+// It reports whether the manifest tree was modified.
+func overrideLocalBackends(tree *toml.Tree, backends map[string]string, verbose bool, out io.Writer) bool {
+	for name, u := range backends {
+		path := []string{"local_server", "backends", name}
+		tree.SetPath(append(path, "url"), u)
+		// The TLS settings of the original backend don't apply to the override.
+		if bt, ok := tree.GetPath(path).(*toml.Tree); ok && strings.HasPrefix(u, "http://") {
+			for _, k := range []string{"ca_certificate_file", "cert_host", "insecure_skip_verify", "use_sni"} {
+				_ = bt.Delete(k)
+			}
+		}
+		if verbose {
+			text.Info(out, "[local_server.backends.%s] url is overridden with %s", name, u)
+		}
+	}
+	return len(backends) > 0
+}
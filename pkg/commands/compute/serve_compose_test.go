@@ -0,0 +1,169 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+	toml "github.com/pelletier/go-toml"
+)
+
+// fakeDocker puts a `docker` script in the PATH that logs its arguments to the
+// returned file and publishes the 'api' compose service's port 8080.
+func fakeDocker(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake docker binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "docker.log")
+	script := `#!/bin/sh
+echo "$@" >> ` + log + `
+if [ "$4" = "port" ]; then
+  if [ "$5" = "api" ] && [ "$6" = "8080" ]; then
+    echo "0.0.0.0:49153"
+    echo "[::]:49153"
+    exit 0
+  fi
+  echo "no port $6/tcp for container $5" >&2
+  exit 1
+fi
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o700); err != nil { // #nosec G306
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestServeComposeFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute serve --compose-up"), &stdout)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "--compose-up requires --compose")
+}
+
+func TestResolveLocalBackends(t *testing.T) {
+	fakeDocker(t)
+
+	scenarios := []struct {
+		name    string
+		values  []string
+		compose string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:   "url",
+			values: []string{"origin=http://localhost:3000"},
+			want:   map[string]string{"origin": "http://localhost:3000"},
+		},
+		{
+			name:    "compose service",
+			values:  []string{"api=service:api:8080", "origin=https://example.com"},
+			compose: "docker-compose.yml",
+			want:    map[string]string{"api": "http://127.0.0.1:49153", "origin": "https://example.com"},
+		},
+		{
+			name:    "validate missing name",
+			values:  []string{"http://localhost:3000"},
+			wantErr: "invalid --backend 'http://localhost:3000': expected <name>=<url> or <name>=service:<compose service>:<port>",
+		},
+		{
+			name:    "validate invalid url",
+			values:  []string{"origin=localhost"},
+			wantErr: "invalid --backend 'origin=localhost': 'localhost' isn't a URL",
+		},
+		{
+			name:    "validate missing port",
+			values:  []string{"api=service:api"},
+			compose: "docker-compose.yml",
+			wantErr: "expected <name>=service:<compose service>:<port>",
+		},
+		{
+			name:    "validate missing --compose",
+			values:  []string{"api=service:api:8080"},
+			wantErr: "--backend 'api=service:api:8080' references a compose service but --compose isn't set",
+		},
+		{
+			name:    "validate unpublished port",
+			values:  []string{"api=service:api:9090"},
+			compose: "docker-compose.yml",
+			wantErr: "no port 9090/tcp for container api",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			backends, err := compute.ResolveLocalBackends(testcase.values, testcase.compose)
+			testutil.AssertErrorContains(t, err, testcase.wantErr)
+			if err == nil {
+				testutil.AssertEqual(t, testcase.want, backends)
+			}
+		})
+	}
+}
+
+func TestComposeUp(t *testing.T) {
+	log := fakeDocker(t)
+
+	var stdout bytes.Buffer
+	down, err := compute.ComposeUp("docker-compose.yml", &stdout)
+	testutil.AssertNoError(t, err)
+	down()
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, []string{
+		"compose --file docker-compose.yml up --detach",
+		"compose --file docker-compose.yml down",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+	testutil.AssertStringContains(t, stdout.String(), "Stopping docker compose services")
+}
+
+func TestPrepareLocalServerBackendOverrides(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "fastly.toml")
+	content := `name = "package"
+manifest_version = 2
+language = "rust"
+
+[local_server.backends.api]
+url = "https://api.example.com"
+override_host = "api.example.com"
+insecure_skip_verify = true
+`
+	if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{
+		Backends: map[string]string{
+			"api":    "http://127.0.0.1:49153",
+			"origin": "http://localhost:3000",
+		},
+	}, true, &stdout)
+	testutil.AssertNoError(t, err)
+	defer stop()
+	testutil.AssertString(t, filepath.Join(dir, compute.ServeManifestFilename), path)
+	testutil.AssertStringContains(t, stdout.String(), "[local_server.backends.api] url is overridden with http://127.0.0.1:49153")
+
+	tree, err := toml.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "http://127.0.0.1:49153", tree.GetPath([]string{"local_server", "backends", "api", "url"}).(string))
+	testutil.AssertString(t, "api.example.com", tree.GetPath([]string{"local_server", "backends", "api", "override_host"}).(string))
+	testutil.AssertEqual(t, false, tree.HasPath([]string{"local_server", "backends", "api", "insecure_skip_verify"}))
+	testutil.AssertString(t, "http://localhost:3000", tree.GetPath([]string{"local_server", "backends", "origin", "url"}).(string))
+}
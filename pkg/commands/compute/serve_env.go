@@ -0,0 +1,92 @@
+package compute
+
+import (
+	"io"
+	"os"
+
+	svcenv "github.com/fastly/cli/pkg/commands/env"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// serviceEnv fetches the environment variables of the package's service (see
+// `fastly env`) so the local server can emulate them. It returns nil if the
+// package has no service yet, or the variables can't be fetched (e.g. there's
+// no API token), as serving doesn't otherwise need the API.
+func (c *ServeCommand) serviceEnv(out io.Writer) *svcenv.Vars {
+	serviceID := c.Globals.Manifest.File.ServiceID
+	if serviceID == "" {
+		return nil
+	}
+	if token, _ := c.Globals.Token(); token == "" {
+		return nil
+	}
+	vars, err := svcenv.Fetch(c.Globals.APIClient, serviceID)
+	if err != nil {
+		if c.Globals.Verbose() {
+			text.Info(out, "The environment variables of service %s aren't emulated locally: %s", serviceID, err)
+		}
+		return nil
+	}
+	return &vars
+}
+
+// emulateServiceEnv adds the service's environment variables to the
+// [local_server] config and secret stores its code opens them with (see
+// svcenv.LinkName), unless those stores are defined locally. Secret values
+// can't be fetched, so they're read from their SecretStoreEnvVar environment
+// variable.
+//
+// It reports whether the manifest tree was modified.
+func emulateServiceEnv(tree *toml.Tree, vars *svcenv.Vars, verbose bool, out io.Writer) (bool, error) {
+	if vars == nil {
+		return false, nil
+	}
+	var modified bool
+	name := svcenv.LinkName
+
+	if len(vars.Config) > 0 {
+		if tree.HasPath([]string{"local_server", "config_stores", name}) || tree.HasPath([]string{"local_server", "dictionaries", name}) {
+			if verbose {
+				text.Info(out, "[local_server.config_stores.%s] is defined locally, so the service's environment variables aren't used", name)
+			}
+		} else {
+			contents := make(map[string]any, len(vars.Config))
+			for k, v := range vars.Config {
+				contents[k] = v
+			}
+			store, err := toml.TreeFromMap(map[string]any{"format": "inline-toml", "contents": contents})
+			if err != nil {
+				return false, err
+			}
+			tree.SetPath([]string{"local_server", "config_stores", name}, store)
+			modified = true
+			if verbose {
+				text.Info(out, "Config store '%s' emulates the service's %d environment variables", name, len(vars.Config))
+			}
+		}
+	}
+
+	if len(vars.Secrets) > 0 && !tree.HasPath([]string{"local_server", "secret_stores", name}) {
+		var entries []*toml.Tree
+		for _, key := range vars.Secrets {
+			v := SecretStoreEnvVar(name, key)
+			data, ok := os.LookupEnv(v)
+			if !ok {
+				text.Warning(out, "Secret environment variable %s has no local value. Set the environment variable %s or define it in [local_server.secret_stores.%s].", key, v, name)
+				continue
+			}
+			entry, err := toml.TreeFromMap(map[string]any{"key": key, "data": data})
+			if err != nil {
+				return false, err
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) > 0 {
+			tree.SetPath([]string{"local_server", "secret_stores", name}, entries)
+			modified = true
+		}
+	}
+
+	return modified, nil
+}
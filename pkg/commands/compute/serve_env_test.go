@@ -0,0 +1,87 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/compute"
+	svcenv "github.com/fastly/cli/pkg/commands/env"
+	"github.com/fastly/cli/pkg/testutil"
+	toml "github.com/pelletier/go-toml"
+)
+
+func TestPrepareLocalServerServiceEnv(t *testing.T) {
+	t.Setenv(compute.SecretStoreEnvVar(svcenv.LinkName, "API_KEY"), "local-key")
+
+	vars := &svcenv.Vars{
+		Config:  map[string]string{"API_URL": "https://example.com"},
+		Secrets: []string{"API_KEY", "UNSET"},
+	}
+	scenarios := []struct {
+		name        string
+		local       string
+		wantConfig  map[string]any
+		wantSecrets map[string]string
+		wantOutputs []string
+	}{
+		{
+			name:        "emulated",
+			wantConfig:  map[string]any{"API_URL": "https://example.com"},
+			wantSecrets: map[string]string{"API_KEY": "local-key"},
+			wantOutputs: []string{"Secret environment variable UNSET has no local value", "FASTLY_SECRET_STORE_ENV_UNSET"},
+		},
+		{
+			name: "defined locally",
+			local: `
+			[local_server.config_stores.env]
+			format = "inline-toml"
+			[local_server.config_stores.env.contents]
+			API_URL = "http://localhost:8080"
+			`,
+			wantConfig:  map[string]any{"API_URL": "http://localhost:8080"},
+			wantSecrets: map[string]string{"API_KEY": "local-key"},
+			wantOutputs: []string{"[local_server.config_stores.env] is defined locally"},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "fastly.toml")
+			content := "name = \"package\"\nmanifest_version = 2\nlanguage = \"rust\"\n" + testcase.local
+			if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{Env: vars}, true, &stdout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stop()
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+
+			tree, err := toml.LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Config stores are emulated by Viceroy as dictionaries.
+			contents, _ := tree.GetPath([]string{"local_server", "dictionaries", svcenv.LinkName, "contents"}).(*toml.Tree)
+			if contents == nil {
+				t.Fatalf("want the env config store, got:\n%s", tree)
+			}
+			testutil.AssertEqual(t, testcase.wantConfig, contents.ToMap())
+
+			entries, _ := tree.GetPath([]string{"local_server", "secret_stores", svcenv.LinkName}).([]*toml.Tree)
+			secrets := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				secrets[entry.Get("key").(string)], _ = entry.Get("data").(string)
+			}
+			testutil.AssertEqual(t, testcase.wantSecrets, secrets)
+		})
+	}
+}
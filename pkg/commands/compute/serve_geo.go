@@ -0,0 +1,200 @@
+package compute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// LoopbackAddresses are the client addresses of requests to the local server,
+// whose geolocation data is overridden by --client-ip and --override-geo.
+var LoopbackAddresses = []string{"127.0.0.1", "::1"}
+
+// geoFields are the geolocation fields supported by Viceroy, and whether
+// their value is an integer ("int"), a float ("float") or a string ("").
+var geoFields = map[string]string{
+	"as_name":           "",
+	"as_number":         "int",
+	"area_code":         "int",
+	"city":              "",
+	"conn_speed":        "",
+	"conn_type":         "",
+	"continent":         "",
+	"country_code":      "",
+	"country_code3":     "",
+	"country_name":      "",
+	"gmt_offset":        "int",
+	"latitude":          "float",
+	"longitude":         "float",
+	"metro_code":        "int",
+	"postal_code":       "",
+	"proxy_description": "",
+	"proxy_type":        "",
+	"region":            "",
+	"utc_offset":        "int",
+}
+
+// ParseGeoOverrides parses the --override-geo values, each a comma separated
+// list of <field>=<value> pairs (e.g. country_code=GB,city=London).
+func ParseGeoOverrides(values []string) (map[string]any, error) {
+	geo := make(map[string]any)
+	for _, v := range values {
+		for _, pair := range strings.Split(v, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			k, val, ok := strings.Cut(pair, "=")
+			k = strings.TrimSpace(k)
+			if !ok || k == "" {
+				return nil, fmt.Errorf("invalid --override-geo '%s': expected <field>=<value>", pair)
+			}
+			kind, ok := geoFields[k]
+			if !ok {
+				fields := make([]string, 0, len(geoFields))
+				for f := range geoFields {
+					fields = append(fields, f)
+				}
+				sort.Strings(fields)
+				return nil, fmt.Errorf("invalid --override-geo field '%s' (valid fields: %s)", k, strings.Join(fields, ", "))
+			}
+			switch kind {
+			case "int":
+				i, err := strconv.ParseInt(val, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid --override-geo '%s': %s must be an integer", pair, k)
+				}
+				geo[k] = i
+			case "float":
+				f, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid --override-geo '%s': %s must be a number", pair, k)
+				}
+				geo[k] = f
+			default:
+				geo[k] = val
+			}
+		}
+	}
+	return geo, nil
+}
+
+// overrideGeolocation sets the geolocation data of the loopback addresses to
+// that of the --client-ip entry of [local_server.geolocation], with any
+// --override-geo fields on top. A "json" geolocation file is inlined so the
+// loopback entries can be added.
+//
+// It reports whether the manifest tree was modified.
+func overrideGeolocation(tree *toml.Tree, dir, clientIP string, geo map[string]any, verbose bool, out io.Writer) (bool, error) {
+	if clientIP == "" && len(geo) == 0 {
+		return false, nil
+	}
+
+	addresses, err := localGeolocationAddresses(tree, dir)
+	if err != nil {
+		return false, err
+	}
+
+	data := make(map[string]any)
+	if clientIP != "" {
+		entry, ok := addresses[clientIP].(map[string]any)
+		if !ok && len(geo) == 0 {
+			return false, fmt.Errorf("--client-ip %s has no [local_server.geolocation] entry (set one, or use --override-geo to provide the data)", clientIP)
+		}
+		for k, v := range entry {
+			data[k] = v
+		}
+	}
+	for k, v := range geo {
+		data[k] = v
+	}
+	for _, addr := range LoopbackAddresses {
+		addresses[addr] = data
+	}
+
+	geolocation := map[string]any{
+		"format":    "inline-toml",
+		"addresses": addresses,
+	}
+	if v, ok := tree.GetPath([]string{"local_server", "geolocation", "use_default_loopback"}).(bool); ok {
+		geolocation["use_default_loopback"] = v
+	}
+	t, err := toml.TreeFromMap(geolocation)
+	if err != nil {
+		return false, fmt.Errorf("error setting [local_server.geolocation]: %w", err)
+	}
+	tree.SetPath([]string{"local_server", "geolocation"}, t)
+
+	if verbose {
+		if clientIP != "" {
+			text.Info(out, "Local requests are geolocated as %s", clientIP)
+		}
+		if len(geo) > 0 {
+			fields := make([]string, 0, len(geo))
+			for k := range geo {
+				fields = append(fields, k)
+			}
+			sort.Strings(fields)
+			text.Info(out, "Geolocation fields are overridden: %s", strings.Join(fields, ", "))
+		}
+	}
+	return true, nil
+}
+
+// localGeolocationAddresses returns the addresses of [local_server.geolocation],
+// reading them from its file when the format is "json".
+func localGeolocationAddresses(tree *toml.Tree, dir string) (map[string]any, error) {
+	addresses := make(map[string]any)
+	format, _ := tree.GetPath([]string{"local_server", "geolocation", "format"}).(string)
+	if format != "json" {
+		if t, ok := tree.GetPath([]string{"local_server", "geolocation", "addresses"}).(*toml.Tree); ok {
+			addresses = t.ToMap()
+		}
+		return addresses, nil
+	}
+
+	file, _ := tree.GetPath([]string{"local_server", "geolocation", "file"}).(string)
+	if file == "" {
+		return nil, fmt.Errorf("[local_server.geolocation] has format \"json\" but no file")
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading [local_server.geolocation] file: %w", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var entries map[string]map[string]any
+	if err := d.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("error parsing [local_server.geolocation] file %s: %w", file, err)
+	}
+	for addr, entry := range entries {
+		for k, v := range entry {
+			// TOML distinguishes integers from floats, so the JSON numbers are
+			// converted to whichever they represent.
+			if n, ok := v.(json.Number); ok {
+				if i, err := n.Int64(); err == nil {
+					entry[k] = i
+				} else if f, err := n.Float64(); err == nil {
+					entry[k] = f
+				}
+			}
+		}
+		addresses[addr] = entry
+	}
+	return addresses, nil
+}
@@ -0,0 +1,135 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+	toml "github.com/pelletier/go-toml"
+)
+
+func TestParseGeoOverrides(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		values    []string
+		want      map[string]any
+		wantError string
+	}{
+		{
+			name:   "typed fields",
+			values: []string{"country_code=GB,city=London", "as_number=5089,latitude=51.5"},
+			want:   map[string]any{"country_code": "GB", "city": "London", "as_number": int64(5089), "latitude": 51.5},
+		},
+		{
+			name:      "unknown field",
+			values:    []string{"country=GB"},
+			wantError: "invalid --override-geo field 'country'",
+		},
+		{
+			name:      "invalid integer",
+			values:    []string{"as_number=abc"},
+			wantError: "as_number must be an integer",
+		},
+		{
+			name:      "missing value",
+			values:    []string{"country_code"},
+			wantError: "expected <field>=<value>",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			got, err := compute.ParseGeoOverrides(testcase.values)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err == nil {
+				testutil.AssertEqual(t, testcase.want, got)
+			}
+		})
+	}
+}
+
+func TestPrepareLocalServerGeolocation(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		geo       string
+		files     map[string]string
+		overrides compute.LocalOverrides
+		want      map[string]any
+		wantError string
+	}{
+		{
+			name: "client ip",
+			geo: `
+			[local_server.geolocation]
+			format = "inline-toml"
+			[local_server.geolocation.addresses]
+			[local_server.geolocation.addresses."203.0.113.7"]
+			country_code = "GB"
+			as_number = 5089
+			`,
+			overrides: compute.LocalOverrides{ClientIP: "203.0.113.7", Geo: map[string]any{"city": "London"}},
+			want:      map[string]any{"country_code": "GB", "as_number": int64(5089), "city": "London"},
+		},
+		{
+			name: "json file",
+			geo: `
+			[local_server.geolocation]
+			format = "json"
+			file = "geo.json"
+			`,
+			files:     map[string]string{"geo.json": `{"203.0.113.7": {"country_code": "FR", "latitude": 48.85}}`},
+			overrides: compute.LocalOverrides{ClientIP: "203.0.113.7"},
+			want:      map[string]any{"country_code": "FR", "latitude": 48.85},
+		},
+		{
+			name:      "override without manifest entries",
+			overrides: compute.LocalOverrides{Geo: map[string]any{"country_code": "JP"}},
+			want:      map[string]any{"country_code": "JP"},
+		},
+		{
+			name:      "unknown client ip",
+			overrides: compute.LocalOverrides{ClientIP: "203.0.113.7"},
+			wantError: "--client-ip 203.0.113.7 has no [local_server.geolocation] entry",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "fastly.toml")
+			content := "name = \"package\"\nmanifest_version = 2\nlanguage = \"rust\"\n" + testcase.geo
+			if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			for name, data := range testcase.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stdout bytes.Buffer
+			path, stop, err := compute.PrepareLocalServer(manifestPath, testcase.overrides, true, &stdout)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				return
+			}
+			defer stop()
+			testutil.AssertString(t, filepath.Join(dir, compute.ServeManifestFilename), path)
+
+			tree, err := toml.LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, "inline-toml", tree.GetPath([]string{"local_server", "geolocation", "format"}).(string))
+			for _, addr := range compute.LoopbackAddresses {
+				entry, ok := tree.GetPath([]string{"local_server", "geolocation", "addresses", addr}).(*toml.Tree)
+				if !ok {
+					t.Fatalf("want an entry for %s, got:\n%s", addr, tree)
+				}
+				testutil.AssertEqual(t, testcase.want, entry.ToMap())
+			}
+		})
+	}
+}
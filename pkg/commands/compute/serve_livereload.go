@@ -0,0 +1,127 @@
+package compute
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// LiveReloadPath is the path of the server-sent events endpoint that
+// browsers listen on for reloads (see --live-reload).
+const LiveReloadPath = "/__fastly/live-reload"
+
+// liveReloadScript is injected into HTML responses, reloading the page when
+// the LiveReloadPath endpoint sends a reload event.
+var liveReloadScript = []byte(`<script>new EventSource("` + LiveReloadPath + `").addEventListener("reload", function () { location.reload(); });</script>`)
+
+// LiveReload notifies the browsers connected to its endpoint when the local
+// server has restarted so they can reload the page.
+type LiveReload struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+// NewLiveReload returns a LiveReload without any connected browsers.
+func NewLiveReload() *LiveReload {
+	return &LiveReload{clients: make(map[chan struct{}]bool)}
+}
+
+// Reload notifies every connected browser to reload.
+func (lr *LiveReload) Reload() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for c := range lr.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+			// A reload is already pending for the browser.
+		}
+	}
+}
+
+// ServeHTTP implements the http.Handler interface, streaming a reload event
+// to the browser each time Reload is called.
+func (lr *LiveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	c := make(chan struct{}, 1)
+	lr.mu.Lock()
+	lr.clients[c] = true
+	lr.mu.Unlock()
+	defer func() {
+		lr.mu.Lock()
+		delete(lr.clients, c)
+		lr.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-c:
+			if _, err := fmt.Fprint(w, "event: reload\ndata: {}\n\n"); err != nil {
+				return
+			}
+			f.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handler serves the LiveReloadPath endpoint, passing other requests to next.
+func (lr *LiveReload) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == LiveReloadPath {
+			lr.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// injectLiveReload adds the live reload script to an uncompressed HTML
+// response. It's used as a httputil.ReverseProxy's ModifyResponse.
+func injectLiveReload(resp *http.Response) error {
+	if resp.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mt != "text/html" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	body = InjectLiveReload(body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
+
+// InjectLiveReload inserts the live reload script before the closing body tag
+// of the HTML document, or appends it if there isn't one.
+func InjectLiveReload(html []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(html), []byte("</body>"))
+	if i < 0 {
+		return append(html, liveReloadScript...)
+	}
+	out := make([]byte, 0, len(html)+len(liveReloadScript))
+	out = append(out, html[:i]...)
+	out = append(out, liveReloadScript...)
+	return append(out, html[i:]...)
+}
@@ -0,0 +1,376 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	svcenv "github.com/fastly/cli/pkg/commands/env"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// ServeManifestFilename is the name of the manifest generated for Viceroy when
+// the [local_server] configuration needs resolving by the CLI. It's written to
+// the same directory as the project manifest so relative file paths still
+// resolve, and is removed once the local server stops.
+const ServeManifestFilename = ".fastly-serve.toml"
+
+// SecretStoreEnvPrefix is the prefix of the environment variables from which
+// `compute serve` reads the [setup.secret_stores] entries that have no
+// [local_server.secret_stores] value (e.g. FASTLY_SECRET_STORE_<STORE>_<KEY>).
+const SecretStoreEnvPrefix = "FASTLY_SECRET_STORE_"
+
+// LocalOverrides are the [local_server] settings overridden by flags.
+type LocalOverrides struct {
+	// Backends maps backend names to the URLs given via --backend.
+	Backends map[string]string
+	// ClientIP is the IP address whose [local_server.geolocation] entry is
+	// used for local requests (see --client-ip).
+	ClientIP string
+	// Geo are the geolocation fields given via --override-geo.
+	Geo map[string]any
+	// Env are the environment variables of the package's service (see
+	// `fastly env`), or nil if they aren't emulated.
+	Env *svcenv.Vars
+	// Environment is the manifest environment selected via the global --env
+	// flag, whose [env.<name>.local_server] section is applied.
+	Environment string
+	// PersistStores serves the emulated stores from data files in the
+	// ServeStateDir (see --persist-stores), having reset them if FreshStores
+	// is set (see --fresh).
+	PersistStores bool
+	FreshStores   bool
+}
+
+// PrepareLocalServer resolves the parts of the [local_server] configuration
+// that Viceroy can't handle itself:
+//
+//   - the [env.<name>.local_server] section of the selected manifest
+//     environment (see overrideLocalEnv).
+//   - backends overridden via --backend (see overrideLocalBackends).
+//   - geolocation overridden via --client-ip and --override-geo (see
+//     overrideGeolocation).
+//   - the service's environment variables (see emulateServiceEnv).
+//   - backends with custom TLS settings (see prepareLocalBackends).
+//   - secret stores backed by environment variables (see
+//     prepareLocalSecretStores).
+//   - config stores and KV stores (see prepareLocalStores).
+//   - the data files of the emulated stores kept across runs (see
+//     persistLocalStores).
+//
+// If anything was resolved, a copy of the manifest is written for Viceroy.
+// The returned path is the manifest Viceroy should be run with, and the
+// returned function releases any resources (e.g. the generated manifest).
+//
+// NOTE: The generated manifest can contain secret values, so it's only
+// readable by the current user.
+func PrepareLocalServer(manifestPath string, o LocalOverrides, verbose bool, out io.Writer) (string, func(), error) {
+	tree, err := toml.LoadFile(manifestPath)
+	if err != nil {
+		// Viceroy will report the issue with the manifest.
+		return manifestPath, func() {}, nil
+	}
+
+	environment := overrideLocalEnv(tree, o.Environment, verbose, out)
+	overridden := overrideLocalBackends(tree, o.Backends, verbose, out)
+
+	geo, err := overrideGeolocation(tree, filepath.Dir(manifestPath), o.ClientIP, o.Geo, verbose, out)
+	if err != nil {
+		return "", nil, err
+	}
+
+	emulated, err := emulateServiceEnv(tree, o.Env, verbose, out)
+	if err != nil {
+		return "", nil, err
+	}
+
+	stop, proxied, err := prepareLocalBackends(tree, filepath.Dir(manifestPath), verbose, out)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resolved, err := prepareLocalSecretStores(tree, verbose, out)
+	if err != nil {
+		stop()
+		return "", nil, err
+	}
+
+	translated, err := prepareLocalStores(tree, verbose, out)
+	if err != nil {
+		stop()
+		return "", nil, err
+	}
+
+	var persisted bool
+	if o.PersistStores {
+		dir := filepath.Dir(manifestPath)
+		persisted, err = persistLocalStores(tree, dir, filepath.Join(dir, ServeStateDir), o.FreshStores, verbose, out)
+		if err != nil {
+			stop()
+			return "", nil, err
+		}
+	}
+
+	if !environment && !overridden && !geo && !emulated && !proxied && !resolved && !translated && !persisted {
+		return manifestPath, stop, nil
+	}
+
+	path := filepath.Join(filepath.Dir(manifestPath), ServeManifestFilename)
+	if err := os.WriteFile(path, []byte(tree.String()), 0o600); err != nil {
+		stop()
+		return "", nil, fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return path, func() {
+		stop()
+		_ = os.Remove(path)
+	}, nil
+}
+
+// localManifestPath returns the path of the manifest in dir that Viceroy is
+// run with. This is the fastly.<env>.toml manifest if there is one for the
+// environment selected via the global --env flag, otherwise the fastly.toml
+// manifest (whose [env.<env>] section is applied by PrepareLocalServer).
+func localManifestPath(dir, env string) string {
+	if env != "" {
+		path := filepath.Join(dir, fmt.Sprintf("fastly.%s.toml", env))
+		if filesystem.FileExists(path) {
+			return path
+		}
+	}
+	return filepath.Join(dir, manifest.Filename)
+}
+
+// localServerTables are the [local_server] tables whose entries are
+// overridden by name by a manifest environment.
+var localServerTables = map[string]bool{
+	"backends":      true,
+	"config_stores": true,
+	"dictionaries":  true,
+	"kv_stores":     true,
+	"object_stores": true,
+	"secret_stores": true,
+}
+
+// overrideLocalEnv merges the [env.<name>.local_server] section of the
+// manifest environment into [local_server], in the same way as
+// manifest.File.ApplyEnv.
+//
+// It reports whether the manifest tree was modified.
+func overrideLocalEnv(tree *toml.Tree, name string, verbose bool, out io.Writer) bool {
+	if name == "" {
+		return false
+	}
+	local, ok := tree.GetPath([]string{"env", name, "local_server"}).(*toml.Tree)
+	if !ok {
+		return false
+	}
+	for _, k := range local.Keys() {
+		v := local.Get(k)
+		if t, ok := v.(*toml.Tree); ok && localServerTables[k] {
+			for _, entry := range t.Keys() {
+				tree.SetPath([]string{"local_server", k, entry}, t.Get(entry))
+			}
+		} else {
+			tree.SetPath([]string{"local_server", k}, v)
+		}
+	}
+	if verbose {
+		text.Info(out, "[local_server] is overridden by [env.%s.local_server]", name)
+	}
+	return true
+}
+
+// prepareLocalSecretStores resolves the [local_server.secret_stores] entries
+// that read their value from an environment variable (`env`), and adds any
+// [setup.secret_stores] entries missing from the local configuration using
+// the value of their SecretStoreEnvPrefix environment variable.
+//
+// It reports whether the manifest tree was modified.
+func prepareLocalSecretStores(tree *toml.Tree, verbose bool, out io.Writer) (bool, error) {
+	var modified bool
+
+	local, _ := tree.GetPath([]string{"local_server", "secret_stores"}).(*toml.Tree)
+	if local != nil {
+		for _, store := range local.Keys() {
+			entries, _ := local.GetPath([]string{store}).([]*toml.Tree)
+			for _, entry := range entries {
+				v, ok := entry.Get("env").(string)
+				if !ok {
+					continue
+				}
+				key, _ := entry.Get("key").(string)
+				data, ok := os.LookupEnv(v)
+				if !ok {
+					return false, fmt.Errorf("[local_server.secret_stores.%s] entry '%s' reads from the environment variable %s which isn't set", store, key, v)
+				}
+				_ = entry.Delete("env")
+				entry.Set("data", data)
+				modified = true
+				if verbose {
+					text.Info(out, "Secret store '%s' entry '%s' is read from $%s", store, key, v)
+				}
+			}
+		}
+	}
+
+	setup, _ := tree.GetPath([]string{"setup", "secret_stores"}).(*toml.Tree)
+	if setup == nil {
+		return modified, nil
+	}
+	for _, store := range setup.Keys() {
+		keys, _ := setup.GetPath([]string{store, "entries"}).(*toml.Tree)
+		if keys == nil {
+			continue
+		}
+
+		var entries []*toml.Tree
+		if local != nil {
+			entries, _ = local.GetPath([]string{store}).([]*toml.Tree)
+		}
+		defined := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			if key, ok := entry.Get("key").(string); ok {
+				defined[key] = true
+			}
+		}
+
+		var added bool
+		for _, key := range keys.Keys() {
+			if defined[key] {
+				continue
+			}
+			v := SecretStoreEnvVar(store, key)
+			data, ok := os.LookupEnv(v)
+			if !ok {
+				text.Warning(out, "[setup.secret_stores.%s] entry '%s' has no local value. Set the environment variable %s or define it in [local_server.secret_stores.%s].", store, key, v, store)
+				continue
+			}
+			entry, err := toml.TreeFromMap(map[string]any{"key": key, "data": data})
+			if err != nil {
+				return false, err
+			}
+			entries = append(entries, entry)
+			added = true
+			if verbose {
+				text.Info(out, "Secret store '%s' entry '%s' is read from $%s", store, key, v)
+			}
+		}
+		if added {
+			tree.SetPath([]string{"local_server", "secret_stores", store}, entries)
+			modified = true
+		}
+	}
+
+	return modified, nil
+}
+
+// localStoreAliases maps the [local_server] store sections that Viceroy
+// emulates under a different name to the section Viceroy reads.
+//
+// Config stores share their runtime interface with dictionaries, and KV stores
+// are the successor of object stores.
+var localStoreAliases = []struct{ name, viceroy string }{
+	{"config_stores", "dictionaries"},
+	{"kv_stores", "object_stores"},
+}
+
+// prepareLocalStores moves the [local_server.config_stores] and
+// [local_server.kv_stores] definitions to the sections read by Viceroy, so
+// the stores a service uses in production can be emulated under their own
+// name locally. Config stores without a `format` default to "json" when
+// backed by a file, and "inline-toml" otherwise.
+//
+// It reports whether the manifest tree was modified.
+func prepareLocalStores(tree *toml.Tree, verbose bool, out io.Writer) (bool, error) {
+	var modified bool
+	for _, alias := range localStoreAliases {
+		stores, _ := tree.GetPath([]string{"local_server", alias.name}).(*toml.Tree)
+		if stores == nil {
+			continue
+		}
+		for _, name := range stores.Keys() {
+			if tree.HasPath([]string{"local_server", alias.viceroy, name}) {
+				return false, fmt.Errorf("[local_server.%s.%s] has the same name as [local_server.%s.%s]", alias.name, name, alias.viceroy, name)
+			}
+			store := stores.GetPath([]string{name})
+			if cs, ok := store.(*toml.Tree); ok && !cs.Has("format") {
+				format := "inline-toml"
+				if cs.Has("file") {
+					format = "json"
+				}
+				cs.Set("format", format)
+			}
+			tree.SetPath([]string{"local_server", alias.viceroy, name}, store)
+			if verbose {
+				text.Info(out, "[local_server.%s.%s] is emulated by Viceroy as [local_server.%s.%s]", alias.name, name, alias.viceroy, name)
+			}
+		}
+		_ = tree.DeletePath([]string{"local_server", alias.name})
+		modified = true
+	}
+	return modified, nil
+}
+
+// LocalStoreFiles returns the files backing the stores emulated by the local
+// server, relative to dir, so changes to them can be reloaded.
+func LocalStoreFiles(ls manifest.LocalServer, dir string) []string {
+	var files []string
+	add := func(file string) {
+		if file == "" {
+			return
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		files = append(files, filepath.Clean(file))
+	}
+	for _, s := range ls.ConfigStores {
+		add(s.File)
+	}
+	for _, d := range ls.Dictionaries {
+		add(d.File)
+	}
+	for _, entries := range ls.KVStores {
+		for _, e := range entries {
+			add(e.File)
+		}
+	}
+	for _, entries := range ls.ObjectStores {
+		for _, e := range entries {
+			add(e.File)
+		}
+	}
+	for _, entries := range ls.SecretStores {
+		for _, e := range entries {
+			add(e.File)
+		}
+	}
+
+	sort.Strings(files)
+	unique := files[:0]
+	for i, f := range files {
+		if i == 0 || f != files[i-1] {
+			unique = append(unique, f)
+		}
+	}
+	return unique
+}
+
+// envVarUnsafe matches the characters that aren't used in env var names.
+var envVarUnsafe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// SecretStoreEnvVar returns the environment variable from which `compute
+// serve` reads a [setup.secret_stores] entry without a local value.
+func SecretStoreEnvVar(store, key string) string {
+	name := func(s string) string {
+		return envVarUnsafe.ReplaceAllString(strings.ToUpper(s), "_")
+	}
+	return SecretStoreEnvPrefix + name(store) + "_" + name(key)
+}
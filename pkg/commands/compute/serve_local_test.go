@@ -0,0 +1,383 @@
+package compute_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/testutil"
+	toml "github.com/pelletier/go-toml"
+)
+
+func TestPrepareLocalSecretStores(t *testing.T) {
+	t.Setenv("LOCAL_API_KEY", "from-env")
+	t.Setenv(compute.SecretStoreEnvVar("my-store", "db.password"), "from-setup-env")
+
+	scenarios := []struct {
+		name        string
+		stores      string
+		wantError   string
+		wantData    map[string]string
+		wantOutputs []string
+	}{
+		{
+			name: "no env entries",
+			stores: `
+			[[local_server.secret_stores.my-store]]
+			key = "api_key"
+			data = "abc"
+			`,
+		},
+		{
+			name: "env entry",
+			stores: `
+			[[local_server.secret_stores.my-store]]
+			key = "api_key"
+			env = "LOCAL_API_KEY"
+			`,
+			wantData:    map[string]string{"api_key": "from-env"},
+			wantOutputs: []string{"Secret store 'my-store' entry 'api_key' is read from $LOCAL_API_KEY"},
+		},
+		{
+			name: "validate unset env entry",
+			stores: `
+			[[local_server.secret_stores.my-store]]
+			key = "api_key"
+			env = "LOCAL_MISSING_KEY"
+			`,
+			wantError: "[local_server.secret_stores.my-store] entry 'api_key' reads from the environment variable LOCAL_MISSING_KEY which isn't set",
+		},
+		{
+			name: "setup entries",
+			stores: `
+			[setup.secret_stores.my-store]
+			description = "My store"
+			[setup.secret_stores.my-store.entries.api_key]
+			[setup.secret_stores.my-store.entries."db.password"]
+			[setup.secret_stores.my-store.entries.unset]
+
+			[[local_server.secret_stores.my-store]]
+			key = "api_key"
+			data = "abc"
+			`,
+			wantData: map[string]string{"api_key": "abc", "db.password": "from-setup-env"},
+			wantOutputs: []string{
+				"Secret store 'my-store' entry 'db.password' is read from $FASTLY_SECRET_STORE_MY_STORE_DB_PASSWORD",
+				"[setup.secret_stores.my-store] entry 'unset' has no local value",
+			},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "fastly.toml")
+			content := "name = \"package\"\nmanifest_version = 2\nlanguage = \"rust\"\n" + testcase.stores
+			if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{}, true, &stdout)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				return
+			}
+			defer stop()
+
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if len(testcase.wantData) == 0 {
+				testutil.AssertString(t, manifestPath, path)
+				return
+			}
+			testutil.AssertString(t, filepath.Join(dir, compute.ServeManifestFilename), path)
+
+			tree, err := toml.LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, _ := tree.GetPath([]string{"local_server", "secret_stores", "my-store"}).([]*toml.Tree)
+			got := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				if entry.Has("env") {
+					t.Fatalf("want env removed from entry %s", entry.Get("key"))
+				}
+				got[entry.Get("key").(string)], _ = entry.Get("data").(string)
+			}
+			testutil.AssertEqual(t, testcase.wantData, got)
+		})
+	}
+}
+
+func TestPrepareLocalStores(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		stores    string
+		wantError string
+		want      map[string]any
+	}{
+		{
+			name: "no config or KV stores",
+			stores: `
+			[local_server.dictionaries.my-dict]
+			format = "inline-toml"
+			[local_server.dictionaries.my-dict.contents]
+			foo = "bar"
+			`,
+		},
+		{
+			name: "config stores and KV stores",
+			stores: `
+			[local_server.config_stores.inline]
+			[local_server.config_stores.inline.contents]
+			foo = "bar"
+			[local_server.config_stores.from-file]
+			file = "config.json"
+			[[local_server.kv_stores.my-kv]]
+			key = "a"
+			data = "1"
+			[[local_server.kv_stores.my-kv]]
+			key = "b"
+			file = "b.txt"
+			`,
+			want: map[string]any{
+				"dictionaries.inline.format":       "inline-toml",
+				"dictionaries.inline.contents.foo": "bar",
+				"dictionaries.from-file.format":    "json",
+				"dictionaries.from-file.file":      "config.json",
+				"object_stores.my-kv":              2,
+				"config_stores":                    nil,
+				"kv_stores":                        nil,
+			},
+		},
+		{
+			name: "validate name collision",
+			stores: `
+			[local_server.dictionaries.shared]
+			format = "inline-toml"
+			[local_server.config_stores.shared]
+			file = "config.json"
+			`,
+			wantError: "[local_server.config_stores.shared] has the same name as [local_server.dictionaries.shared]",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "fastly.toml")
+			content := "name = \"package\"\nmanifest_version = 2\nlanguage = \"rust\"\n" + testcase.stores
+			if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{}, false, &stdout)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				return
+			}
+			defer stop()
+
+			if testcase.want == nil {
+				testutil.AssertString(t, manifestPath, path)
+				return
+			}
+			tree, err := toml.LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range testcase.want {
+				got := tree.Get("local_server." + key)
+				switch want := want.(type) {
+				case nil:
+					if got != nil {
+						t.Errorf("want local_server.%s unset, got: %v", key, got)
+					}
+				case int:
+					entries, _ := got.([]*toml.Tree)
+					testutil.AssertEqual(t, want, len(entries))
+				default:
+					testutil.AssertEqual(t, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalStoreFiles(t *testing.T) {
+	ls := manifest.LocalServer{
+		ConfigStores: map[string]manifest.LocalConfigStore{
+			"config": {File: "config.json"},
+			"inline": {Contents: map[string]string{"foo": "bar"}},
+		},
+		Dictionaries: map[string]manifest.LocalDictionary{
+			"dict": {File: "/abs/dict.json", Format: "json"},
+		},
+		KVStores: map[string][]manifest.LocalKVStore{
+			"kv": {{Key: "a", File: "data/a.txt"}, {Key: "b", Data: "b"}, {Key: "c", File: "config.json"}},
+		},
+		SecretStores: map[string][]manifest.LocalSecretStore{
+			"secrets": {{Key: "s", File: "secret.txt"}},
+		},
+	}
+
+	want := []string{
+		"/abs/dict.json",
+		filepath.Join("/project", "config.json"),
+		filepath.Join("/project", "data", "a.txt"),
+		filepath.Join("/project", "secret.txt"),
+	}
+	testutil.AssertEqual(t, want, compute.LocalStoreFiles(ls, "/project"))
+}
+
+func TestPrepareLocalServerEnv(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "fastly.toml")
+	content := `name = "package"
+manifest_version = 2
+language = "rust"
+
+[local_server.backends.origin]
+url = "http://127.0.0.1:8080"
+
+[local_server.backends.static]
+url = "http://127.0.0.1:8081"
+
+[env.staging.local_server]
+viceroy_version = "0.9.0"
+
+[env.staging.local_server.backends.origin]
+url = "http://127.0.0.1:9090"
+
+[[env.staging.local_server.secret_stores.keys]]
+key = "api_key"
+data = "staging"
+`
+	if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no environment", func(t *testing.T) {
+		path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{}, false, io.Discard)
+		testutil.AssertNoError(t, err)
+		defer stop()
+		testutil.AssertString(t, manifestPath, path)
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		var stdout bytes.Buffer
+		path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{Environment: "staging"}, true, &stdout)
+		testutil.AssertNoError(t, err)
+		defer stop()
+		testutil.AssertStringContains(t, stdout.String(), "[local_server] is overridden by [env.staging.local_server]")
+		testutil.AssertString(t, filepath.Join(dir, compute.ServeManifestFilename), path)
+
+		tree, err := toml.LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, "http://127.0.0.1:9090", tree.GetPath([]string{"local_server", "backends", "origin", "url"}))
+		testutil.AssertEqual(t, "http://127.0.0.1:8081", tree.GetPath([]string{"local_server", "backends", "static", "url"}))
+		testutil.AssertEqual(t, "0.9.0", tree.GetPath([]string{"local_server", "viceroy_version"}))
+		entries, _ := tree.GetPath([]string{"local_server", "secret_stores", "keys"}).([]*toml.Tree)
+		if len(entries) != 1 || entries[0].Get("data") != "staging" {
+			t.Fatalf("want the staging secret store, have %v", entries)
+		}
+	})
+}
+
+func TestPrepareLocalServerPersistStores(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "fastly.toml")
+	writeManifest := func(value string) {
+		content := `name = "package"
+manifest_version = 2
+language = "rust"
+
+[local_server.config_stores.config]
+contents = { greeting = "` + value + `", other = "kept" }
+
+[[local_server.kv_stores.kv]]
+key = "a"
+data = "1"
+
+[local_server.dictionaries.dict]
+file = "dict.json"
+format = "json"
+`
+		if err := os.WriteFile(manifestPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statePath := filepath.Join(dir, compute.ServeStateDir, "dictionaries", "config.json")
+	prepare := func(fresh bool) {
+		path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{PersistStores: true, FreshStores: fresh}, false, io.Discard)
+		testutil.AssertNoError(t, err)
+		defer stop()
+
+		tree, err := toml.LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, filepath.Join(compute.ServeStateDir, "dictionaries", "config.json"), tree.GetPath([]string{"local_server", "dictionaries", "config", "file"}))
+		testutil.AssertEqual(t, filepath.Join(compute.ServeStateDir, "object_stores", "kv.json"), tree.GetPath([]string{"local_server", "object_stores", "kv", "file"}))
+	}
+	assertState := func(want string) {
+		t.Helper()
+		data, err := os.ReadFile(statePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertString(t, want, string(data))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "dict.json"), []byte(`{"a": "1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest("hello")
+	prepare(false)
+	assertState("{\n  \"greeting\": \"hello\",\n  \"other\": \"kept\"\n}\n")
+
+	// Edits to the persisted data are kept across runs.
+	if err := os.WriteFile(statePath, []byte(`{"greeting": "hello", "other": "edited", "added": "yes"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prepare(false)
+	assertState("{\n  \"added\": \"yes\",\n  \"greeting\": \"hello\",\n  \"other\": \"edited\"\n}\n")
+
+	// A value changed in the manifest takes effect.
+	writeManifest("bonjour")
+	prepare(false)
+	assertState("{\n  \"added\": \"yes\",\n  \"greeting\": \"bonjour\",\n  \"other\": \"edited\"\n}\n")
+
+	// --fresh resets the persisted data to the manifest.
+	prepare(true)
+	assertState("{\n  \"greeting\": \"bonjour\",\n  \"other\": \"kept\"\n}\n")
+
+	// A change to a file backing a store takes effect when the local server
+	// is next prepared, e.g. once it's reloaded.
+	if err := os.WriteFile(filepath.Join(dir, "dict.json"), []byte(`{"a": "2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prepare(false)
+	data, err := os.ReadFile(filepath.Join(dir, compute.ServeStateDir, "dictionaries", "dict.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "{\n  \"a\": \"2\"\n}\n", string(data))
+}
+
+func TestServePersistStoresFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute serve --fresh"), &stdout)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "--fresh requires --persist-stores")
+}
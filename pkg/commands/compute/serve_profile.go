@@ -0,0 +1,58 @@
+package compute
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// GuestProfileDir is the default directory Viceroy writes guest profiles to.
+const GuestProfileDir = "guest-profiles"
+
+// GuestProfiles returns the profiles in dir written since the given time.
+//
+// NOTE: Viceroy writes one profile per request, and the directory may contain
+// profiles from earlier sessions, so only the newer ones are returned.
+func GuestProfiles(dir string, since time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		if !fi.ModTime().Before(since) {
+			profiles = append(profiles, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// displayGuestProfiles displays the profiles collected during the session and
+// how to view them.
+func displayGuestProfiles(dir string, since time.Time, out io.Writer) {
+	profiles, err := GuestProfiles(dir, since)
+	if err != nil || len(profiles) == 0 {
+		text.Info(out, "No guest profiles were written to %s (profiles are written once a request completes)", dir)
+		return
+	}
+
+	text.Break(out)
+	text.Info(out, "%d guest profiles written to %s (one per request):", len(profiles), dir)
+	for _, p := range profiles {
+		text.Output(out, "  %s", p)
+	}
+	text.Break(out)
+	text.Output(out, "To view a profile, open https://profiler.firefox.com/ and select 'Load a profile from file'.")
+}
@@ -0,0 +1,50 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestServeProfileFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute serve --profile-out profiles"), &stdout)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "--profile-out requires --profile-guest")
+}
+
+func TestGuestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Minute)
+
+	old := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(old, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(old, start.Add(-time.Hour), start.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.json", "a.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := compute.GuestProfiles(dir, start)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, profiles)
+
+	_, err = compute.GuestProfiles(filepath.Join(dir, "missing"), start)
+	if err == nil {
+		t.Fatal("want error for missing directory")
+	}
+}
@@ -0,0 +1,238 @@
+package compute
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalProxyOptions configures the proxy in front of Viceroy.
+type LocalProxyOptions struct {
+	// TLS serves HTTPS using CertFile and KeyFile or, if neither is set, a
+	// self-signed certificate generated in CertDir.
+	TLS      bool
+	CertFile string
+	KeyFile  string
+	CertDir  string
+	// Record is the file incoming requests are appended to (see --record).
+	Record io.Writer
+	// LiveReload is notified of restarts so browsers reload, and injects the
+	// script that listens for them into HTML responses (see --live-reload).
+	LiveReload *LiveReload
+}
+
+// Enabled reports whether a proxy is needed.
+func (o LocalProxyOptions) Enabled() bool {
+	return o.TLS || o.Record != nil || o.LiveReload != nil
+}
+
+// LocalProxy sits in front of Viceroy to provide features Viceroy doesn't
+// support, such as serving HTTPS and recording requests. Viceroy listens on a
+// random local port (Upstream) while the proxy listens on the address
+// requested by the user (Addr).
+type LocalProxy struct {
+	// Addr is the address the proxy listens on.
+	Addr string
+	// CertFile is the certificate being served (if TLS is enabled).
+	CertFile string
+	// Upstream is the address Viceroy should listen on.
+	Upstream string
+
+	server *http.Server
+}
+
+// URL returns the URL the local server is reachable at.
+func (p *LocalProxy) URL() string {
+	if p.CertFile != "" {
+		return "https://" + p.Addr
+	}
+	return "http://" + p.Addr
+}
+
+// StartLocalProxy starts a proxy on addr forwarding requests to Viceroy.
+func StartLocalProxy(addr string, opts LocalProxyOptions) (*LocalProxy, error) {
+	var cfg *tls.Config
+	if opts.TLS {
+		if opts.CertFile == "" && opts.KeyFile == "" {
+			var err error
+			opts.CertFile, opts.KeyFile, err = selfSignedCert(opts.CertDir, addr)
+			if err != nil {
+				return nil, fmt.Errorf("error generating self-signed certificate: %w", err)
+			}
+		}
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+		}
+		cfg = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	upstream, err := freeLocalAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		ln = tls.NewListener(ln, cfg)
+	}
+
+	rp := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = upstream
+			if cfg != nil {
+				req.Header.Set("X-Forwarded-Proto", "https")
+			}
+			if opts.LiveReload != nil {
+				// The script can only be injected into uncompressed responses,
+				// which the transport then requests (decompressing them itself).
+				req.Header.Del("Accept-Encoding")
+			}
+		},
+	}
+	var handler http.Handler = rp
+	if opts.LiveReload != nil {
+		rp.ModifyResponse = injectLiveReload
+		handler = opts.LiveReload.handler(handler)
+	}
+	if opts.Record != nil {
+		handler = recordRequests(handler, opts.Record)
+	}
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	p := &LocalProxy{
+		Addr:     ln.Addr().String(),
+		Upstream: upstream,
+		server:   srv,
+	}
+	if cfg != nil {
+		p.CertFile = opts.CertFile
+	}
+	return p, nil
+}
+
+// Close stops the proxy.
+func (p *LocalProxy) Close() error {
+	return p.server.Close()
+}
+
+// freeLocalAddr returns a local address with a port that's currently unused.
+func freeLocalAddr() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	return ln.Addr().String(), nil
+}
+
+// selfSignedCert returns the paths to a self-signed certificate (and its key)
+// valid for localhost and the host of addr, generating it if the existing one
+// is missing, expired or doesn't cover the host.
+func selfSignedCert(dir, addr string) (certFile, keyFile string, err error) {
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", err
+	}
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if host != "" && host != "0.0.0.0" && host != "::" {
+		hosts = append(hosts, host)
+	}
+
+	if validCert(certFile, keyFile, hosts) {
+		return certFile, keyFile, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Fastly CLI"}, CommonName: "compute serve"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return "", "", err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+// validCert reports whether the certificate and key can be loaded, the
+// certificate is valid for at least another day and it covers the hosts.
+func validCert(certFile, keyFile string, hosts []string) bool {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil || time.Now().Add(24*time.Hour).After(cert.NotAfter) {
+		return false
+	}
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,284 @@
+package compute_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestServeProxyFlags(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --tls-cert without --tls-key",
+			Args:      args("compute serve --tls-cert cert.pem"),
+			WantError: "--tls-cert and --tls-key must be set together",
+		},
+		{
+			Name:      "validate --tls-key without --tls-cert",
+			Args:      args("compute serve --tls-key key.pem"),
+			WantError: "--tls-cert and --tls-key must be set together",
+		},
+		{
+			Name:      "validate --live-reload without --watch",
+			Args:      args("compute serve --live-reload"),
+			WantError: "--live-reload requires --watch",
+		},
+		{
+			Name:      "validate missing --replay file",
+			Args:      args("compute serve --replay missing.ndjson"),
+			WantError: "error reading --replay file",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+		})
+	}
+}
+
+func TestStartLocalProxyTLS(t *testing.T) {
+	certDir := t.TempDir()
+
+	// The self-signed certificate is generated on first use.
+	lt, err := compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{TLS: true, CertDir: certDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, filepath.Join(certDir, "cert.pem"), lt.CertFile)
+	assertLocalProxy(t, lt)
+	lt.Close()
+
+	cert, err := os.ReadFile(lt.CertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Later runs reuse the certificate while it's valid.
+	lt, err = compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{TLS: true, CertDir: certDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lt.Close()
+	reused, err := os.ReadFile(lt.CertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, string(cert), string(reused))
+
+	// A certificate and key can be provided explicitly.
+	certFile, keyFile := filepath.Join(certDir, "cert.pem"), filepath.Join(certDir, "key.pem")
+	lt, err = compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{TLS: true, CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLocalProxy(t, lt)
+	lt.Close()
+
+	_, err = compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{TLS: true, CertFile: certFile, KeyFile: filepath.Join(certDir, "missing.pem")})
+	testutil.AssertErrorContains(t, err, "error loading TLS certificate")
+}
+
+func TestStartLocalProxyRecord(t *testing.T) {
+	var record bytes.Buffer
+	lt, err := compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{Record: &record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lt.Close()
+	testutil.AssertString(t, "http://"+lt.Addr, lt.URL())
+	assertLocalProxy(t, lt)
+
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	if err := os.WriteFile(path, record.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqs, err := compute.ReadRecordedRequests(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, 1, len(reqs))
+	testutil.AssertString(t, "GET", reqs[0].Method)
+	testutil.AssertString(t, "/hello", reqs[0].URL)
+	testutil.AssertString(t, lt.Addr, reqs[0].Host)
+}
+
+func TestStartLocalProxyLiveReload(t *testing.T) {
+	lr := compute.NewLiveReload()
+	lt, err := compute.StartLocalProxy("127.0.0.1:0", compute.LocalProxyOptions{LiveReload: lr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lt.Close()
+
+	ln, err := net.Listen("tcp", lt.Upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/data.json" {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"body":"</body>"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, "<html><BODY>hello</BODY></html>")
+		}),
+	}
+	go func() {
+		_ = upstream.Serve(ln)
+	}()
+	defer upstream.Close()
+
+	get := func(path string) string {
+		resp, err := http.Get(lt.URL() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	testutil.AssertString(t, string(compute.InjectLiveReload([]byte("<html><BODY>hello</BODY></html>"))), get("/"))
+	testutil.AssertStringContains(t, get("/"), `EventSource("`+compute.LiveReloadPath+`")`)
+	testutil.AssertString(t, `{"body":"</body>"}`, get("/data.json"))
+
+	// Browsers are sent a reload event on each restart.
+	resp, err := http.Get(lt.URL() + compute.LiveReloadPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	testutil.AssertString(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	lr.Reload()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "event: reload\n", line)
+}
+
+func TestInjectLiveReload(t *testing.T) {
+	got := string(compute.InjectLiveReload([]byte("<p>no body tag</p>")))
+	if !strings.HasPrefix(got, "<p>no body tag</p><script>") {
+		t.Fatalf("want the script appended, got %s", got)
+	}
+	got = string(compute.InjectLiveReload([]byte("<body>a</body>b</body>")))
+	if !strings.HasSuffix(got, "</script></body>") {
+		t.Fatalf("want the script before the last closing body tag, got %s", got)
+	}
+}
+
+func TestReplayRequests(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, fmt.Sprintf("%s %s %s %s %s", r.Method, r.Host, r.URL.RequestURI(), r.Header.Get("X-Test"), body))
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	content := `{"method":"GET","host":"example.com","url":"/a?b=c","header":{"X-Test":["1"]}}
+
+{"method":"POST","host":"example.com","url":"/redirect","body":"aGVsbG8="}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqs, err := compute.ReadRecordedRequests(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	err = compute.ReplayRequests(reqs, srv.URL, &stdout)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{
+		"GET example.com /a?b=c 1 ",
+		"POST example.com /redirect  hello",
+	}, received)
+	testutil.AssertStringContains(t, stdout.String(), "GET /a?b=c → 200")
+	testutil.AssertStringContains(t, stdout.String(), "POST /redirect → 302")
+	testutil.AssertStringContains(t, stdout.String(), "Replayed 2 requests")
+
+	if err := os.WriteFile(path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = compute.ReadRecordedRequests(path)
+	testutil.AssertErrorContains(t, err, "line 1")
+}
+
+// assertLocalProxy checks requests are proxied to the upstream address.
+func assertLocalProxy(t *testing.T, lt *compute.LocalProxy) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", lt.Upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", r.Header.Get("X-Forwarded-Proto"), r.URL.Path)
+		}),
+	}
+	go func() {
+		_ = upstream.Serve(ln)
+	}()
+	defer upstream.Close()
+
+	pool := x509.NewCertPool()
+	if lt.CertFile != "" {
+		pem, err := os.ReadFile(lt.CertFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.AppendCertsFromPEM(pem)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	resp, err := client.Get(lt.URL() + "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	want := " /hello"
+	if lt.CertFile != "" {
+		want = "https" + want
+	}
+	testutil.AssertString(t, want, string(body))
+}
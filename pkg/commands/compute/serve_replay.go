@@ -0,0 +1,151 @@
+package compute
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// RecordedRequest is a request recorded by `compute serve --record`, stored
+// as one JSON object per line.
+type RecordedRequest struct {
+	Time   time.Time   `json:"time"`
+	Method string      `json:"method"`
+	Host   string      `json:"host"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	// Body is base64 encoded by encoding/json, so binary bodies are preserved.
+	Body []byte `json:"body,omitempty"`
+}
+
+// recordRequests wraps the handler so that each request is appended to w
+// before being handled.
+func recordRequests(next http.Handler, w io.Writer) http.Handler {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		mu.Lock()
+		_ = enc.Encode(RecordedRequest{
+			Time:   time.Now().UTC(),
+			Method: r.Method,
+			Host:   r.Host,
+			URL:    r.URL.RequestURI(),
+			Header: r.Header,
+			Body:   body,
+		})
+		mu.Unlock()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// ReadRecordedRequests reads the requests recorded in the file at path.
+func ReadRecordedRequests(path string) ([]RecordedRequest, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reqs []RecordedRequest
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r RecordedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("error parsing %s line %d: %w", path, line, err)
+		}
+		reqs = append(reqs, r)
+	}
+	return reqs, scanner.Err()
+}
+
+// ReplayRequests resends the requests to the local server at baseURL (e.g.
+// http://127.0.0.1:7676) in the order they were recorded, displaying the
+// status of each response.
+func ReplayRequests(reqs []RecordedRequest, baseURL string, out io.Writer) error {
+	client := &http.Client{
+		// The recorded responses (e.g. redirects) are what's being debugged, so
+		// they're displayed rather than followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Transport: &http.Transport{
+			// gosec flagged this:
+			// G402 (CWE-295): TLS InsecureSkipVerify set true
+			// Disabling as the local server uses a self-signed certificate.
+			// #nosec
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	var failed int
+	start := time.Now()
+	for _, r := range reqs {
+		req, err := http.NewRequest(r.Method, baseURL+r.URL, bytes.NewReader(r.Body))
+		if err != nil {
+			return fmt.Errorf("error creating request %s %s: %w", r.Method, r.URL, err)
+		}
+		for k, v := range r.Header {
+			req.Header[k] = v
+		}
+		req.Host = r.Host
+
+		t := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			failed++
+			text.Output(out, "%s %s: %s", r.Method, r.URL, err)
+			continue
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		text.Output(out, "%s %s → %d (%s)", r.Method, r.URL, resp.StatusCode, time.Since(t).Round(time.Millisecond))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d replayed requests failed", failed, len(reqs))
+	}
+	text.Info(out, "Replayed %d requests in %s", len(reqs), time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// waitForServer waits until addr accepts connections.
+func waitForServer(addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("local server didn't start listening on %s within %s", addr, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
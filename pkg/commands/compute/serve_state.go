@@ -0,0 +1,229 @@
+package compute
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// ServeStateDir is the project-local directory, relative to the manifest, in
+// which `compute serve --persist-stores` keeps the data files of the emulated
+// stores, so that edits to them survive restarts (see --fresh).
+var ServeStateDir = filepath.Join(".fastly", "serve-state")
+
+// persistedStoreSections are the [local_server] sections, read by Viceroy,
+// whose stores are persisted.
+var persistedStoreSections = []string{"dictionaries", "object_stores", "secret_stores"}
+
+// persistLocalStores points Viceroy at a JSON file per emulated store within
+// stateDir, which is created from the manifest's values the first time and
+// reused on later runs, so edits made to it are kept when the local server
+// restarts.
+//
+// The values the manifest had when a store was last persisted are recorded
+// alongside it, so that a value later added, changed or removed in the
+// manifest (or the files it references) still takes effect while the other
+// values of the store are kept. If fresh is set the persisted stores are first
+// removed.
+//
+// NOTE: Viceroy keeps the data written to stores at runtime in memory and
+// never writes it back, so that data isn't persisted. The files can contain
+// secret values, so they're only readable by the current user.
+//
+// It reports whether the manifest tree was modified.
+func persistLocalStores(tree *toml.Tree, manifestDir, stateDir string, fresh, verbose bool, out io.Writer) (bool, error) {
+	if fresh {
+		if err := os.RemoveAll(stateDir); err != nil {
+			return false, fmt.Errorf("error removing %s: %w", stateDir, err)
+		}
+		if verbose {
+			text.Info(out, "The persisted local store data in %s was reset", stateDir)
+		}
+	}
+
+	var modified bool
+	for _, section := range persistedStoreSections {
+		stores, _ := tree.GetPath([]string{"local_server", section}).(*toml.Tree)
+		if stores == nil {
+			continue
+		}
+		for _, name := range stores.Keys() {
+			seed, err := localStoreValues(section, name, stores.Get(name), manifestDir)
+			if err != nil {
+				return false, err
+			}
+			path := filepath.Join(stateDir, section, name+".json")
+			if err := persistStore(path, seed); err != nil {
+				return false, err
+			}
+			// Viceroy resolves the files of stores relative to the manifest.
+			file, err := filepath.Rel(manifestDir, path)
+			if err != nil {
+				file = path
+			}
+			store, err := toml.TreeFromMap(map[string]any{"file": file, "format": "json"})
+			if err != nil {
+				return false, err
+			}
+			tree.SetPath([]string{"local_server", section, name}, store)
+			modified = true
+			if verbose {
+				text.Info(out, "[local_server.%s.%s] is persisted in %s", section, name, path)
+			}
+		}
+	}
+	return modified, nil
+}
+
+// persistStore merges the manifest's values of a store (seed) into the
+// persisted values at path, recording the seed for the next merge.
+func persistStore(path string, seed map[string]string) error {
+	seedPath := path + ".seed"
+	state, err := readStoreFile(path)
+	if err != nil {
+		return err
+	}
+	previous, err := readStoreFile(seedPath)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		state = make(map[string]string, len(seed))
+	}
+	for k, v := range seed {
+		if old, ok := previous[k]; !ok || old != v {
+			state[k] = v
+		}
+	}
+	for k := range previous {
+		if _, ok := seed[k]; !ok {
+			delete(state, k)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := writeStoreFile(path, state); err != nil {
+		return err
+	}
+	return writeStoreFile(seedPath, seed)
+}
+
+// readStoreFile reads a persisted store, returning nil if it doesn't exist.
+func readStoreFile(path string) (map[string]string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the path is within the project's state directory.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("error parsing %s, which must be a JSON object of string values (use --fresh to reset it): %w", path, err)
+	}
+	return values, nil
+}
+
+// writeStoreFile writes a store as a JSON object, unless the file already has
+// the same contents, so an unchanged store isn't reported to file watchers.
+func writeStoreFile(path string, values map[string]string) error {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the path is within the project's state directory.
+	// #nosec
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// localStoreValues returns the values of a store as defined in the manifest,
+// reading any files relative to the manifest directory.
+//
+// Dictionaries are a table of contents or a JSON file, while object and secret
+// stores are a list of entries, whose value is its data or the contents of a
+// file, or a JSON file.
+func localStoreValues(section, name string, store any, manifestDir string) (map[string]string, error) {
+	resolve := func(file string) string {
+		if filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(manifestDir, file)
+	}
+
+	values := make(map[string]string)
+	switch s := store.(type) {
+	case *toml.Tree:
+		if file, ok := s.Get("file").(string); ok {
+			if format, _ := s.Get("format").(string); format != "" && format != "json" {
+				return nil, fmt.Errorf("[local_server.%s.%s] has the unsupported format '%s' for a file", section, name, format)
+			}
+			v, err := readStoreFile(resolve(file))
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				return nil, fmt.Errorf("[local_server.%s.%s] file %s doesn't exist", section, name, file)
+			}
+			return v, nil
+		}
+		contents, _ := s.Get("contents").(*toml.Tree)
+		if contents == nil {
+			return values, nil
+		}
+		for _, k := range contents.Keys() {
+			values[k] = fmt.Sprint(contents.Get(k))
+		}
+
+	case []*toml.Tree:
+		for _, entry := range s {
+			key, _ := entry.Get("key").(string)
+			if data, ok := entry.Get("data").(string); ok {
+				values[key] = data
+				continue
+			}
+			file, ok := entry.Get("file").(string)
+			if !ok {
+				file, ok = entry.Get("path").(string)
+			}
+			if !ok {
+				return nil, fmt.Errorf("[local_server.%s.%s] entry '%s' has no data, file or path", section, name, key)
+			}
+			// gosec flagged this:
+			// G304 (CWE-22): Potential file inclusion via variable
+			// Disabling as the path is defined by the user's manifest.
+			// #nosec
+			data, err := os.ReadFile(resolve(file))
+			if err != nil {
+				return nil, fmt.Errorf("error reading [local_server.%s.%s] entry '%s': %w", section, name, key, err)
+			}
+			values[key] = string(data)
+		}
+
+	default:
+		return nil, fmt.Errorf("[local_server.%s.%s] isn't a table or a list of entries", section, name)
+	}
+	return values, nil
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/config"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -111,3 +113,83 @@ func TestGetViceroy(t *testing.T) {
 		t.Fatalf("binary was not moved to the install directory: %s", err)
 	}
 }
+
+// TestGetViceroyPinned validates that the Viceroy version pinned in the
+// manifest is installed alongside, rather than replacing, the latest release.
+func TestGetViceroyPinned(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake viceroy binary is a shell script")
+	}
+
+	rootdir := t.TempDir()
+	defer func(dir string) { compute.InstallDir = dir }(compute.InstallDir)
+	compute.InstallDir = filepath.Join(rootdir, "install")
+
+	downloaded := filepath.Join(rootdir, "viceroy")
+	if err := os.WriteFile(downloaded, []byte("#!/bin/sh\necho viceroy 1.2.3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	g := global.Data{ErrLog: fsterr.MockLog{}}
+	g.Manifest.File.LocalServer.ViceroyVersion = "v1.2.3"
+
+	var out bytes.Buffer
+	spinner, err := text.NewSpinner(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	av := mock.AssetVersioner{
+		AssetVersion:   "2.0.0",
+		BinaryFilename: "viceroy",
+		DownloadOK:     true,
+		DownloadedFile: downloaded,
+	}
+	bin, err := compute.GetViceroy(spinner, &out, av, &g, "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, filepath.Join(compute.InstallDir, compute.PinnedViceroyDir, "1.2.3", "viceroy"), bin)
+	testutil.AssertStringContains(t, out.String(), "Fetching Viceroy release 1.2.3")
+
+	// The installed version is reused without downloading it again.
+	out.Reset()
+	av.DownloadOK = false
+	_, err = compute.GetViceroy(spinner, &out, av, &g, "")
+	testutil.AssertNoError(t, err)
+	if strings.Contains(out.String(), "Fetching") {
+		t.Fatalf("want pinned version to be reused, got: %s", out.String())
+	}
+
+	g.Manifest.File.LocalServer.ViceroyVersion = "latest"
+	_, err = compute.GetViceroy(spinner, &out, av, &g, "")
+	testutil.AssertErrorContains(t, err, "invalid [local_server] viceroy_version 'latest'")
+}
+
+func TestServeWatchFlags(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate --watch-include without --watch",
+			Args:      args("compute serve --watch-include src/**/*.rs"),
+			WantError: "--watch-include and --watch-exclude require --watch",
+		},
+		{
+			Name:      "validate --watch-exclude without --watch",
+			Args:      args("compute serve --watch-exclude *.md"),
+			WantError: "--watch-include and --watch-exclude require --watch",
+		},
+		{
+			Name:      "validate negative --watch-debounce",
+			Args:      args("compute serve --watch --watch-debounce=-1s"),
+			WantError: "--watch-debounce can't be negative: -1s",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package compute
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// prepareLocalBackends starts a local proxy for each [local_server.backends]
+// entry that sets `insecure_skip_verify` or `ca_certificate_file`, so that
+// HTTPS development origins using self-signed certificates can be reached by
+// Viceroy without disabling TLS for every backend.
+//
+// Viceroy sends plain HTTP requests to the proxy, which forwards them to the
+// backend over HTTPS using the backend's TLS settings. The manifest tree is
+// updated to point the backends at their proxy, and the returned function
+// stops the proxies.
+func prepareLocalBackends(tree *toml.Tree, dir string, verbose bool, out io.Writer) (stop func(), proxied bool, err error) {
+	var servers []*http.Server
+	stop = func() {
+		for _, srv := range servers {
+			_ = srv.Close()
+		}
+	}
+
+	backends, ok := tree.GetPath([]string{"local_server", "backends"}).(*toml.Tree)
+	if !ok {
+		return stop, false, nil
+	}
+
+	for _, name := range backends.Keys() {
+		bt, ok := backends.GetPath([]string{name}).(*toml.Tree)
+		if !ok {
+			continue
+		}
+		var lb manifest.LocalBackend
+		if err := bt.Unmarshal(&lb); err != nil {
+			stop()
+			return nil, false, fmt.Errorf("error parsing [local_server.backends.%s]: %w", name, err)
+		}
+		if !lb.InsecureSkipVerify && lb.CACertificateFile == "" {
+			continue
+		}
+
+		target, err := url.Parse(lb.URL)
+		if err != nil || target.Scheme != "https" {
+			stop()
+			return nil, false, fmt.Errorf("[local_server.backends.%s] sets `insecure_skip_verify` or `ca_certificate_file` but its url (%s) isn't an https URL", name, lb.URL)
+		}
+
+		cfg, err := localBackendTLSConfig(lb, dir)
+		if err != nil {
+			stop()
+			return nil, false, fmt.Errorf("[local_server.backends.%s]: %w", name, err)
+		}
+
+		srv, addr, err := startTLSProxy(target, cfg)
+		if err != nil {
+			stop()
+			return nil, false, fmt.Errorf("error starting proxy for [local_server.backends.%s]: %w", name, err)
+		}
+		servers = append(servers, srv)
+
+		u := *target
+		u.Scheme = "http"
+		u.Host = addr
+		bt.Set("url", u.String())
+		for _, k := range []string{"ca_certificate_file", "cert_host", "insecure_skip_verify", "use_sni"} {
+			_ = bt.Delete(k)
+		}
+
+		if verbose {
+			text.Info(out, "[local_server.backends.%s] (%s) is proxied via %s", name, lb.URL, u.String())
+		}
+	}
+
+	return stop, len(servers) > 0, nil
+}
+
+// localBackendTLSConfig returns the TLS configuration for connecting to the
+// backend. Relative CA file paths are resolved against dir.
+func localBackendTLSConfig(lb manifest.LocalBackend, dir string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: lb.CertHost,
+		// gosec flagged this:
+		// G402 (CWE-295): TLS InsecureSkipVerify may be true
+		// Disabling as the user explicitly opted in for a local dev server.
+		// #nosec
+		InsecureSkipVerify: lb.InsecureSkipVerify,
+	}
+
+	if lb.CACertificateFile != "" {
+		path := lb.CACertificateFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we trust the source of the variable.
+		// #nosec
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_certificate_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in ca_certificate_file: %s", path)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
+
+// startTLSProxy listens on a random local port and forwards requests to the
+// target using the given TLS configuration.
+func startTLSProxy(target *url.URL, cfg *tls.Config) (*http.Server, string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+	addr := ln.Addr().String()
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			// The Host header is left as-is when Viceroy sets it (e.g. via
+			// override_host), otherwise it would reference the proxy.
+			if req.Host == addr {
+				req.Host = target.Host
+			}
+		},
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: cfg,
+		},
+	}
+
+	srv := &http.Server{
+		Handler:           proxy,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	return srv, addr, nil
+}
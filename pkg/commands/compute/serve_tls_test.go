@@ -0,0 +1,149 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+	toml "github.com/pelletier/go-toml"
+)
+
+func TestPrepareLocalBackends(t *testing.T) {
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer origin.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: origin.Certificate().Raw})
+
+	scenarios := []struct {
+		name          string
+		backends      string
+		wantError     string
+		wantProxied   []string
+		wantUnproxied map[string]string
+	}{
+		{
+			name: "no TLS settings",
+			backends: `
+			[local_server.backends.plain]
+			url = "http://example.com"
+			`,
+		},
+		{
+			name: "ca_certificate_file and insecure_skip_verify",
+			backends: fmt.Sprintf(`
+			[local_server.backends.plain]
+			url = "http://example.com"
+			[local_server.backends.custom_ca]
+			url = "%[1]s"
+			ca_certificate_file = "ca.pem"
+			[local_server.backends.insecure]
+			url = "%[1]s"
+			insecure_skip_verify = true
+			`, origin.URL),
+			wantProxied:   []string{"custom_ca", "insecure"},
+			wantUnproxied: map[string]string{"plain": "http://example.com"},
+		},
+		{
+			name: "validate non-https url",
+			backends: `
+			[local_server.backends.plain]
+			url = "http://example.com"
+			insecure_skip_verify = true
+			`,
+			wantError: "[local_server.backends.plain] sets `insecure_skip_verify` or `ca_certificate_file` but its url (http://example.com) isn't an https URL",
+		},
+		{
+			name: "validate missing ca_certificate_file",
+			backends: fmt.Sprintf(`
+			[local_server.backends.custom_ca]
+			url = "%s"
+			ca_certificate_file = "missing.pem"
+			`, origin.URL),
+			wantError: "[local_server.backends.custom_ca]: error reading ca_certificate_file",
+		},
+		{
+			name: "validate invalid ca_certificate_file",
+			backends: fmt.Sprintf(`
+			[local_server.backends.custom_ca]
+			url = "%s"
+			ca_certificate_file = "fastly.toml"
+			`, origin.URL),
+			wantError: "no PEM certificates found in ca_certificate_file",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, "fastly.toml")
+			manifest := "name = \"package\"\nmanifest_version = 2\nlanguage = \"rust\"\n" + testcase.backends
+			if err := os.WriteFile(manifestPath, []byte(manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "ca.pem"), ca, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout bytes.Buffer
+			path, stop, err := compute.PrepareLocalServer(manifestPath, compute.LocalOverrides{}, true, &stdout)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				return
+			}
+			defer stop()
+
+			if len(testcase.wantProxied) == 0 {
+				testutil.AssertString(t, manifestPath, path)
+				return
+			}
+			testutil.AssertString(t, filepath.Join(dir, compute.ServeManifestFilename), path)
+
+			tree, err := toml.LoadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range testcase.wantUnproxied {
+				testutil.AssertString(t, want, tree.GetPath([]string{"local_server", "backends", name, "url"}).(string))
+			}
+			for _, name := range testcase.wantProxied {
+				testutil.AssertStringContains(t, stdout.String(), fmt.Sprintf("[local_server.backends.%s] (%s) is proxied via", name, origin.URL))
+
+				u := tree.GetPath([]string{"local_server", "backends", name, "url"}).(string)
+				if !strings.HasPrefix(u, "http://127.0.0.1:") {
+					t.Fatalf("want backend %s to be proxied, got url: %s", name, u)
+				}
+				if tree.HasPath([]string{"local_server", "backends", name, "insecure_skip_verify"}) || tree.HasPath([]string{"local_server", "backends", name, "ca_certificate_file"}) {
+					t.Fatalf("want TLS settings removed from backend %s", name)
+				}
+
+				resp, err := http.Get(u)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, http.StatusOK, resp.StatusCode)
+				testutil.AssertString(t, "ok", string(body))
+			}
+
+			stop()
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("want generated manifest removed, got: %v", err)
+			}
+		})
+	}
+}
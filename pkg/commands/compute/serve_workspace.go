@@ -0,0 +1,397 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+	toml "github.com/pelletier/go-toml"
+)
+
+// WorkspaceFilename is the conventional name of a workspace file.
+const WorkspaceFilename = "fastly-workspace.toml"
+
+// Workspace is a set of Compute@Edge packages served together by
+// `compute serve`, with requests routed between them by host and path, or
+// deployed together by `compute deploy` in dependency order.
+type Workspace struct {
+	Packages []WorkspacePackage `toml:"packages"`
+}
+
+// WorkspacePackage is a package of a workspace and the requests routed to it.
+//
+// A package without a host or path receives the requests that aren't routed
+// to any other package.
+type WorkspacePackage struct {
+	// Name identifies the package in the output (default: the directory name).
+	Name string `toml:"name"`
+	// Dir is the package's project directory (containing fastly.toml).
+	Dir string `toml:"dir"`
+	// Host routes requests for the host (e.g. api.localhost) to the package.
+	Host string `toml:"host"`
+	// Path routes requests whose path starts with the prefix (e.g. /api) to the
+	// package.
+	Path string `toml:"path"`
+	// DependsOn lists the names of the packages that `compute deploy` must
+	// deploy before this one.
+	DependsOn []string `toml:"depends_on"`
+}
+
+// route describes the requests routed to the package.
+func (p WorkspacePackage) route() string {
+	if p.Host == "" && p.Path == "" {
+		return "(default)"
+	}
+	return p.Host + p.Path
+}
+
+// ReadWorkspace reads the workspace file at path. Package directories are
+// relative to the workspace file.
+func ReadWorkspace(path string) (Workspace, error) {
+	var w Workspace
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return w, err
+	}
+	if err := toml.Unmarshal(data, &w); err != nil {
+		return w, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	for i, p := range w.Packages {
+		if p.Dir != "" && !filepath.IsAbs(p.Dir) {
+			w.Packages[i].Dir = filepath.Join(filepath.Dir(path), p.Dir)
+		}
+	}
+	return w, nil
+}
+
+// ParsePackageFlag parses a --package value, given as <dir> or
+// [<host>][/<path>]=<dir>.
+func ParsePackageFlag(v string) (WorkspacePackage, error) {
+	var p WorkspacePackage
+	route, dir, ok := strings.Cut(v, "=")
+	if !ok {
+		route, dir = "", v
+	}
+	if dir == "" {
+		return p, fmt.Errorf("invalid --package '%s': expected <dir> or [<host>][/<path>]=<dir>", v)
+	}
+	p.Dir = dir
+	p.Host, p.Path = route, ""
+	if i := strings.Index(route, "/"); i >= 0 {
+		p.Host, p.Path = route[:i], route[i:]
+	}
+	return p, nil
+}
+
+// Validate normalises the packages and checks their routes don't overlap.
+func (w *Workspace) Validate() error {
+	if err := w.normalise(); err != nil {
+		return err
+	}
+	routes := make(map[string]string)
+	for _, p := range w.Packages {
+		if other, ok := routes[p.route()]; ok {
+			return fmt.Errorf("packages '%s' and '%s' have the same route: %s", other, p.Name, p.route())
+		}
+		routes[p.route()] = p.Name
+	}
+	return nil
+}
+
+// normalise defaults the package names and routes, checking each package has
+// a dir and a unique name.
+func (w *Workspace) normalise() error {
+	if len(w.Packages) == 0 {
+		return errors.New("the workspace has no packages")
+	}
+	names := make(map[string]bool)
+	for i := range w.Packages {
+		p := &w.Packages[i]
+		if p.Dir == "" {
+			return fmt.Errorf("package %d of the workspace has no dir", i+1)
+		}
+		if p.Name == "" {
+			p.Name = filepath.Base(filepath.Clean(p.Dir))
+		}
+		p.Host = strings.ToLower(p.Host)
+		if p.Path != "" && !strings.HasPrefix(p.Path, "/") {
+			p.Path = "/" + p.Path
+		}
+		p.Path = strings.TrimSuffix(p.Path, "/")
+
+		if names[p.Name] {
+			return fmt.Errorf("more than one package is named '%s' (set a unique name for each package)", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+// DeployOrder normalises the packages and returns them in the order they're
+// deployed: each package after the packages it depends on, otherwise in the
+// order of the workspace file.
+func (w *Workspace) DeployOrder() ([]WorkspacePackage, error) {
+	if err := w.normalise(); err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(w.Packages))
+	for i, p := range w.Packages {
+		index[p.Name] = i
+	}
+	for _, p := range w.Packages {
+		for _, d := range p.DependsOn {
+			if _, ok := index[d]; !ok {
+				return nil, fmt.Errorf("package '%s' depends on '%s', which isn't a package of the workspace", p.Name, d)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		order = make([]WorkspacePackage, 0, len(w.Packages))
+		state = make([]int, len(w.Packages))
+		path  []string
+		visit func(i int) error
+	)
+	visit = func(i int) error {
+		p := w.Packages[i]
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("the workspace packages depend on each other in a cycle: %s -> %s", strings.Join(path, " -> "), p.Name)
+		}
+		state[i] = visiting
+		path = append(path, p.Name)
+		for _, d := range p.DependsOn {
+			if err := visit(index[d]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, p)
+		return nil
+	}
+	for i := range w.Packages {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Route returns the package the request is routed to, or false if it doesn't
+// match any package's route.
+//
+// The most specific route wins: a host and path, then a host, then the longest
+// path, then the default package.
+func (w Workspace) Route(r *http.Request) (WorkspacePackage, bool) {
+	host := strings.ToLower(r.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	var (
+		match WorkspacePackage
+		score = -1
+	)
+	for _, p := range w.Packages {
+		if p.Host != "" && p.Host != host {
+			continue
+		}
+		if p.Path != "" && r.URL.Path != p.Path && !strings.HasPrefix(r.URL.Path, p.Path+"/") {
+			continue
+		}
+		s := len(p.Path)
+		if p.Host != "" {
+			// A host match outranks any path.
+			s += 1 << 16
+		}
+		if s > score {
+			match, score = p, s
+		}
+	}
+	return match, score >= 0
+}
+
+// readWorkspace returns the packages given by --workspace and --package.
+func (c *ServeCommand) readWorkspace() (Workspace, error) {
+	var w Workspace
+	if c.workspace != "" {
+		var err error
+		w, err = ReadWorkspace(c.workspace)
+		if err != nil {
+			return w, fmt.Errorf("error reading --workspace file: %w", err)
+		}
+	}
+	for _, v := range c.packages {
+		p, err := ParsePackageFlag(v)
+		if err != nil {
+			return w, err
+		}
+		w.Packages = append(w.Packages, p)
+	}
+	return w, w.Validate()
+}
+
+// serveWorkspace builds each package of the workspace, runs them under Viceroy
+// and routes the requests received on --addr between them.
+func (c *ServeCommand) serveWorkspace(w Workspace, bin string, backends map[string]string, out io.Writer) error {
+	upstreams := make(map[string]*httputil.ReverseProxy, len(w.Packages))
+	for _, p := range w.Packages {
+		if !c.skipBuild {
+			if err := buildPackage(p, c.Globals.Verbose(), c.quietSuccess.Value, out); err != nil {
+				c.Globals.ErrLog.Add(err)
+				return err
+			}
+		}
+
+		dir, err := filepath.Abs(p.Dir)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		manifestPath, stopLocalServer, err := PrepareLocalServer(localManifestPath(dir, c.Globals.Flags.Env), c.localOverrides(backends), c.Globals.Verbose(), out)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error preparing package '%s': %w", p.Name, err)
+		}
+		defer stopLocalServer()
+
+		addr, err := freeLocalAddr()
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		logs := &prefixWriter{prefix: outputPrefix(p.Name), w: out}
+		file := c.file
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		stopViceroy, err := startViceroy(bin, manifestPath, file, addr, logs, 30*time.Second)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error starting package '%s': %w", p.Name, err)
+		}
+		defer stopViceroy()
+
+		upstreams[p.Name] = httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: addr})
+		if c.Globals.Verbose() {
+			text.Info(out, "Package '%s' is running on %s", p.Name, addr)
+		}
+	}
+
+	ln, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error listening on %s: %w", c.addr, err)
+	}
+	srv := &http.Server{
+		Handler:           workspaceHandler(w, upstreams),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	text.Break(out)
+	text.Info(out, "Serving %d packages on http://%s", len(w.Packages), c.addr)
+	t := text.NewTable(out)
+	t.AddHeader("PACKAGE", "ROUTE", "DIR")
+	for _, p := range w.Packages {
+		t.AddLine(p.Name, p.route(), p.Dir)
+	}
+	t.Print()
+	text.Break(out)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	<-sig
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+	text.Info(out, "Local server stopped")
+	return nil
+}
+
+// workspaceHandler proxies each request to the package it's routed to.
+func workspaceHandler(w Workspace, upstreams map[string]*httputil.ReverseProxy) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		p, ok := w.Route(r)
+		if !ok {
+			routes := make([]string, 0, len(w.Packages))
+			for _, p := range w.Packages {
+				routes = append(routes, p.route())
+			}
+			sort.Strings(routes)
+			http.Error(rw, fmt.Sprintf("no package is routed %s%s (routes: %s)", r.Host, r.URL.Path, strings.Join(routes, ", ")), http.StatusBadGateway)
+			return
+		}
+		upstreams[p.Name].ServeHTTP(rw, r)
+	})
+}
+
+// buildPackage builds the package in its own directory by running the CLI's
+// `compute build` command, prefixing each line of its output with the name of
+// the package.
+func buildPackage(p WorkspacePackage, verbose, quietSuccess bool, out io.Writer) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"compute", "build"}
+	if verbose {
+		args = append(args, "--verbose")
+	}
+	if quietSuccess {
+		args = append(args, "--quiet-success")
+	}
+
+	text.Info(out, "Building package '%s' (%s)", p.Name, p.Dir)
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the command is the CLI itself.
+	// #nosec
+	// nosemgrep
+	build := exec.Command(self, args...)
+	logs := &prefixWriter{prefix: outputPrefix(p.Name), w: out}
+	defer func() {
+		_ = logs.Flush()
+	}()
+	build.Dir = p.Dir
+	build.Stdout = logs
+	build.Stderr = logs
+	if err := build.Run(); err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error building package '%s': %w", p.Name, err),
+			Remediation: fmt.Sprintf("Run `fastly compute build` in %s to see the error, or use --skip-build to serve the existing build.", p.Dir),
+		}
+	}
+	return nil
+}
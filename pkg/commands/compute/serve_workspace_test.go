@@ -0,0 +1,121 @@
+package compute_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestServeWorkspaceFlags(t *testing.T) {
+	scenarios := []struct {
+		args      string
+		wantError string
+	}{
+		{
+			args:      "compute serve --package api=services/api --watch",
+			wantError: "--package and --workspace can't be used with --watch, --tls, --record, --replay or --profile-guest",
+		},
+		{
+			args:      "compute serve --package services/api --package other/api",
+			wantError: "more than one package is named 'api'",
+		},
+		{
+			args:      "compute serve --package /api=services/a --package /api/=services/b",
+			wantError: "packages 'a' and 'b' have the same route: /api",
+		},
+		{
+			args:      "compute serve --package api=",
+			wantError: "invalid --package 'api=': expected <dir> or [<host>][/<path>]=<dir>",
+		},
+		{
+			args:      "compute serve --workspace missing.toml",
+			wantError: "error reading --workspace file",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.args, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+func TestReadWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, compute.WorkspaceFilename)
+	content := `[[packages]]
+dir = "services/edge"
+
+[[packages]]
+name = "api"
+dir = "services/api-v2"
+host = "api.localhost"
+
+[[packages]]
+dir = "/srv/assets"
+path = "static/"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := compute.ReadWorkspace(path)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, w.Validate())
+	testutil.AssertEqual(t, []compute.WorkspacePackage{
+		{Name: "edge", Dir: filepath.Join(dir, "services/edge")},
+		{Name: "api", Dir: filepath.Join(dir, "services/api-v2"), Host: "api.localhost"},
+		{Name: "assets", Dir: "/srv/assets", Path: "/static"},
+	}, w.Packages)
+}
+
+func TestWorkspaceRoute(t *testing.T) {
+	w := compute.Workspace{}
+	for _, v := range []string{
+		"services/edge",
+		"/api=services/api",
+		"/api/v2=services/apiv2",
+		"Admin.localhost=services/admin",
+		"admin.localhost/api=services/adminapi",
+	} {
+		p, err := compute.ParsePackageFlag(v)
+		testutil.AssertNoError(t, err)
+		w.Packages = append(w.Packages, p)
+	}
+	testutil.AssertNoError(t, w.Validate())
+
+	for _, testcase := range []struct {
+		url  string
+		want string
+	}{
+		{url: "http://127.0.0.1:7676/", want: "edge"},
+		{url: "http://127.0.0.1:7676/apis", want: "edge"},
+		{url: "http://127.0.0.1:7676/api", want: "api"},
+		{url: "http://127.0.0.1:7676/api/v1/users", want: "api"},
+		{url: "http://127.0.0.1:7676/api/v2/users", want: "apiv2"},
+		{url: "http://admin.localhost:7676/", want: "admin"},
+		{url: "http://admin.localhost:7676/api/v2", want: "adminapi"},
+	} {
+		t.Run(testcase.url, func(t *testing.T) {
+			p, ok := w.Route(httptest.NewRequest("GET", testcase.url, nil))
+			if !ok {
+				t.Fatalf("want %s to be routed", testcase.url)
+			}
+			testutil.AssertString(t, testcase.want, p.Name)
+		})
+	}
+
+	// Without a default package, unmatched requests aren't routed.
+	w.Packages = w.Packages[1:]
+	if p, ok := w.Route(httptest.NewRequest("GET", "http://127.0.0.1:7676/other", nil)); ok {
+		t.Fatalf("want no route, got %s", p.Name)
+	}
+}
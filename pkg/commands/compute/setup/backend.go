@@ -31,6 +31,7 @@ type Backends struct {
 	Stdout         io.Writer
 
 	// Private
+	existing map[string]bool
 	required []Backend
 }
 
@@ -128,6 +129,37 @@ func (b *Backends) Predefined() bool {
 	return len(b.Setup) > 0
 }
 
+// Validate checks which of the [setup.backends] already exist on the service
+// version, so that only the missing backends are configured and created.
+func (b *Backends) Validate() error {
+	available, err := b.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      b.ServiceID,
+		ServiceVersion: b.ServiceVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching service backends: %w", err)
+	}
+	b.existing = make(map[string]bool)
+	for _, bk := range available {
+		if _, ok := b.Setup[bk.Name]; ok {
+			b.existing[bk.Name] = true
+		}
+	}
+	return nil
+}
+
+// Missing indicates if any of the [setup.backends] don't exist on the service
+// version.
+func (b *Backends) Missing() bool {
+	return len(b.existing) < len(b.Setup)
+}
+
+// Existing returns the names of the [setup.backends] that already exist on the
+// service version.
+func (b *Backends) Existing() []string {
+	return sortedNames(b.existing)
+}
+
 // isOriginless indicates if the required backend is originless.
 func (b *Backends) isOriginless() bool {
 	return len(b.required) == 1 && b.required[0].Name == "originless" && b.required[0].Address == "127.0.0.1"
@@ -138,6 +170,9 @@ func (b *Backends) isOriginless() bool {
 func (b *Backends) checkPredefined() error {
 	var i int
 	for name, settings := range b.Setup {
+		if b.existing[name] {
+			continue
+		}
 		if !b.AcceptDefaults && !b.NonInteractive {
 			if i > 0 {
 				text.Break(b.Stdout)
@@ -292,3 +327,21 @@ func (b *Backends) validateAddress(input string) error {
 	}
 	return nil
 }
+
+// Names returns the names of the backends to be created.
+func (b *Backends) Names() []string {
+	names := make([]string, 0, len(b.required))
+	for _, bk := range b.required {
+		names = append(names, bk.Name)
+	}
+	return names
+}
+
+// Created returns the backends created by the setup process, excluding the
+// originless placeholder (which has no origin to reach).
+func (b *Backends) Created() []Backend {
+	if b.isOriginless() {
+		return nil
+	}
+	return b.required
+}
@@ -28,6 +28,7 @@ type Dictionaries struct {
 	Stdout         io.Writer
 
 	// Private
+	existing map[string]bool
 	required []Dictionary
 }
 
@@ -50,6 +51,9 @@ type DictionaryItem struct {
 // Configure prompts the user for specific values related to the service resource.
 func (d *Dictionaries) Configure() error {
 	for name, settings := range d.Setup {
+		if d.existing[name] {
+			continue
+		}
 		if !d.AcceptDefaults && !d.NonInteractive {
 			text.Break(d.Stdout)
 			text.Output(d.Stdout, "Configuring dictionary '%s'", name)
@@ -183,3 +187,44 @@ func (d *Dictionaries) Create() error {
 func (d *Dictionaries) Predefined() bool {
 	return len(d.Setup) > 0
 }
+
+// Validate checks which of the [setup.dictionaries] already exist on the
+// service version, so that only the missing dictionaries are configured and
+// created.
+func (d *Dictionaries) Validate() error {
+	available, err := d.APIClient.ListDictionaries(&fastly.ListDictionariesInput{
+		ServiceID:      d.ServiceID,
+		ServiceVersion: d.ServiceVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching service dictionaries: %w", err)
+	}
+	d.existing = make(map[string]bool)
+	for _, dict := range available {
+		if _, ok := d.Setup[dict.Name]; ok {
+			d.existing[dict.Name] = true
+		}
+	}
+	return nil
+}
+
+// Missing indicates if any of the [setup.dictionaries] don't exist on the
+// service version.
+func (d *Dictionaries) Missing() bool {
+	return len(d.existing) < len(d.Setup)
+}
+
+// Existing returns the names of the [setup.dictionaries] that already exist on
+// the service version.
+func (d *Dictionaries) Existing() []string {
+	return sortedNames(d.existing)
+}
+
+// Names returns the names of the dictionaries to be created.
+func (d *Dictionaries) Names() []string {
+	names := make([]string, 0, len(d.required))
+	for _, dict := range d.required {
+		names = append(names, dict.Name)
+	}
+	return names
+}
@@ -218,3 +218,12 @@ func generateDomainName() string {
 	// rand.Seed(time.Now().UnixNano())
 	return fmt.Sprintf("%s.%s", petname.Generate(3, "-"), defaultTopLevelDomain)
 }
+
+// Names returns the names of the domains to be created.
+func (d *Domains) Names() []string {
+	names := make([]string, 0, len(d.required))
+	for _, domain := range d.required {
+		names = append(names, domain.Name)
+	}
+	return names
+}
@@ -1,5 +1,7 @@
 package setup
 
+import "sort"
+
 // Interface represents the behaviour of a [setup] resource.
 type Interface interface {
 	// Configure prompts the user for specific values related to the service resource.
@@ -19,3 +21,13 @@ type Interface interface {
 	// Validate checks if the service has the required resources.
 	Validate() error
 }
+
+// sortedNames returns the keys of the set in order.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -28,6 +28,7 @@ type ObjectStores struct {
 	Stdout         io.Writer
 
 	// Private
+	existing map[string]bool
 	required []ObjectStore
 }
 
@@ -48,6 +49,9 @@ type ObjectStoreItem struct {
 // Configure prompts the user for specific values related to the service resource.
 func (o *ObjectStores) Configure() error {
 	for name, settings := range o.Setup {
+		if o.existing[name] {
+			continue
+		}
 		if !o.AcceptDefaults && !o.NonInteractive {
 			text.Break(o.Stdout)
 			text.Output(o.Stdout, "Configuring object store '%s'", name)
@@ -207,3 +211,44 @@ func (o *ObjectStores) Create() error {
 func (o *ObjectStores) Predefined() bool {
 	return len(o.Setup) > 0
 }
+
+// Validate checks which of the [setup.object_stores] are already linked to the
+// service version, so that only the missing object stores are configured and
+// created.
+func (o *ObjectStores) Validate() error {
+	available, err := o.APIClient.ListResources(&fastly.ListResourcesInput{
+		ServiceID:      o.ServiceID,
+		ServiceVersion: o.ServiceVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching service resource links: %w", err)
+	}
+	o.existing = make(map[string]bool)
+	for _, r := range available {
+		if _, ok := o.Setup[r.Name]; ok {
+			o.existing[r.Name] = true
+		}
+	}
+	return nil
+}
+
+// Missing indicates if any of the [setup.object_stores] aren't linked to the
+// service version.
+func (o *ObjectStores) Missing() bool {
+	return len(o.existing) < len(o.Setup)
+}
+
+// Existing returns the names of the [setup.object_stores] that are already
+// linked to the service version.
+func (o *ObjectStores) Existing() []string {
+	return sortedNames(o.existing)
+}
+
+// Names returns the names of the object stores to be created.
+func (o *ObjectStores) Names() []string {
+	names := make([]string, 0, len(o.required))
+	for _, store := range o.required {
+		names = append(names, store.Name)
+	}
+	return names
+}
@@ -0,0 +1,174 @@
+package compute
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/mholt/archiver/v3"
+)
+
+// PackageSignatureFilename is the file within a package that holds the
+// detached signature of its metadata.
+const PackageSignatureFilename = "signature.json"
+
+// signatureAlgorithm is the only supported signature algorithm.
+const signatureAlgorithm = "ed25519"
+
+// keyRemediation explains how to create a signing key pair.
+const keyRemediation = "Use an Ed25519 key pair in PEM format, e.g. `openssl genpkey -algorithm ed25519 -out sign.pem` for the private key (used by `compute pack --sign-key`), and `openssl pkey -in sign.pem -pubout -out verify.pem` for the public key (used by `compute deploy --verify-key`)."
+
+// PackageSignature is the detached signature of a package's metadata.
+//
+// The signed hashsum is the SHA-512 digest of the package's fastly.toml and
+// main.wasm, which is the hashsum the Fastly API records for the package.
+type PackageSignature struct {
+	Algorithm string `json:"algorithm"`
+	HashSum   string `json:"hashsum"`
+	Signature []byte `json:"signature"`
+}
+
+// readSigningKey reads a PEM encoded Ed25519 private key (PKCS #8).
+func readSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, keyError(path, err)
+	}
+	k, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, keyError(path, fmt.Errorf("unsupported key type %T", key))
+	}
+	return k, nil
+}
+
+// readVerifyKey reads a PEM encoded Ed25519 public key (PKIX).
+func readVerifyKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, keyError(path, err)
+	}
+	k, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, keyError(path, fmt.Errorf("unsupported key type %T", key))
+	}
+	return k, nil
+}
+
+// readPEM reads the first PEM block of the file at path.
+func readPEM(path string) (*pem.Block, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the user's choice of key.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, keyError(path, errors.New("no PEM data found"))
+	}
+	return block, nil
+}
+
+// keyError describes an invalid key.
+func keyError(path string, err error) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid key '%s': %w", path, err),
+		Remediation: keyRemediation,
+	}
+}
+
+// signPackage writes the signature of the package directory's metadata to
+// its PackageSignatureFilename.
+func signPackage(dir string, key ed25519.PrivateKey) error {
+	contents := make(map[string]*bytes.Buffer)
+	for name, path := range map[string]string{
+		"fastly.toml": filepath.Join(dir, "fastly.toml"),
+		"main.wasm":   filepath.Join(dir, "bin", "main.wasm"),
+	} {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as the paths are within the package directory.
+		// #nosec
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+		contents[name] = bytes.NewBuffer(data)
+	}
+	hashSum, err := getHashSum(contents)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(PackageSignature{
+		Algorithm: signatureAlgorithm,
+		HashSum:   hashSum,
+		Signature: ed25519.Sign(key, []byte(hashSum)),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, PackageSignatureFilename), data, 0o644); err != nil { // #nosec G306
+		return fmt.Errorf("error writing package signature: %w", err)
+	}
+	return nil
+}
+
+// verifyPackage returns an error unless the package contains a valid
+// signature of its hashsum, made with the private key of key.
+func verifyPackage(path, hashSum string, key ed25519.PublicKey) error {
+	var data []byte
+	err := validate(path, func(f archiver.File) error {
+		if f.Name() != PackageSignatureFilename {
+			return nil
+		}
+		var err error
+		data, err = io.ReadAll(f)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", PackageSignatureFilename, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the package isn't signed"),
+			Remediation: "Sign the package with `fastly compute pack --sign-key`.",
+		}
+	}
+
+	var sig PackageSignature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return fmt.Errorf("error parsing %s: %w", PackageSignatureFilename, err)
+	}
+	if sig.Algorithm != signatureAlgorithm {
+		return fmt.Errorf("unsupported package signature algorithm '%s'", sig.Algorithm)
+	}
+	if sig.HashSum != hashSum || !ed25519.Verify(key, []byte(hashSum), sig.Signature) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the package signature is invalid"),
+			Remediation: "The package was modified after it was signed, or was signed with another key. Sign the package again with `fastly compute pack --sign-key`, using the private key of the --verify-key.",
+		}
+	}
+	return nil
+}
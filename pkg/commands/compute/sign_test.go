@@ -0,0 +1,185 @@
+package compute_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestPackageSigning(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasm, err := os.ReadFile(filepath.Join(wd, "testdata", "pack", "main.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for name, content := range map[string][]byte{
+		"fastly.toml": []byte("manifest_version = 2\nname = \"signed\"\n"),
+		"main.wasm":   wasm,
+	} {
+		if err := os.WriteFile(name, content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	signKey, verifyKey := writeKeyPair(t, "signer")
+	_, otherVerifyKey := writeKeyPair(t, "other")
+
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		GetPackageFn:        getPackageOk,
+		GetServiceFn:        getServiceOK,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		ListDomainsFn:       listDomainsOk,
+		ListVersionsFn:      testutil.ListVersions,
+		UpdatePackageFn:     updatePackageOk,
+	}
+	run := func(t *testing.T, args string) (string, error) {
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+		opts.APIClient = mock.APIClient(api)
+		err := app.Run(opts)
+		t.Log(stdout.String())
+		return stdout.String(), err
+	}
+	pack := func(t *testing.T, args string) {
+		_, err := run(t, "compute pack --wasm-binary ./main.wasm"+args)
+		testutil.AssertNoError(t, err)
+	}
+	deploy := "compute deploy --service-id 123 --token 123 --version latest --verify-key " + verifyKey + " --package "
+
+	t.Run("validate invalid signing key", func(t *testing.T) {
+		_, err := run(t, "compute pack --wasm-binary ./main.wasm --sign-key "+verifyKey)
+		testutil.AssertErrorContains(t, err, "invalid key '"+verifyKey+"'")
+		testutil.AssertRemediationErrorContains(t, err, "openssl genpkey -algorithm ed25519")
+	})
+
+	t.Run("validate unsigned package", func(t *testing.T) {
+		pack(t, "")
+		_, err := run(t, deploy+"pkg/package.tar.gz")
+		testutil.AssertErrorContains(t, err, "the package isn't signed")
+	})
+
+	t.Run("validate package signed with another key", func(t *testing.T) {
+		pack(t, " --sign-key "+signKey)
+		_, err := run(t, "compute deploy --service-id 123 --token 123 --version latest --package pkg/package.tar.gz --verify-key "+otherVerifyKey)
+		testutil.AssertErrorContains(t, err, "the package signature is invalid")
+	})
+
+	t.Run("validate tampered package", func(t *testing.T) {
+		pack(t, " --sign-key "+signKey)
+		signature := readArchiveFile(t, filepath.Join("pkg", "package.tar.gz"), compute.PackageSignatureFilename)
+
+		// Repackage the signature with a modified Wasm binary.
+		tampered := t.TempDir()
+		for name, content := range map[string][]byte{
+			"fastly.toml":                     []byte("manifest_version = 2\nname = \"signed\"\n"),
+			filepath.Join("bin", "main.wasm"): append(wasm, 0),
+			compute.PackageSignatureFilename:  signature,
+		} {
+			path := filepath.Join(tampered, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Chdir(tampered); err != nil {
+			t.Fatal(err)
+		}
+		err := compute.CreatePackageArchive([]string{"fastly.toml", "bin/main.wasm", compute.PackageSignatureFilename}, filepath.Join(dir, "pkg", "tampered.tar.gz"))
+		if err := os.Chdir(dir); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertNoError(t, err)
+
+		_, err = run(t, deploy+"pkg/tampered.tar.gz")
+		testutil.AssertErrorContains(t, err, "the package signature is invalid")
+		testutil.AssertRemediationErrorContains(t, err, "modified after it was signed")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		pack(t, " --sign-key "+signKey)
+		out, err := run(t, deploy+"pkg/package.tar.gz")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Deployed package (service 123, version 3)")
+	})
+}
+
+// writeKeyPair writes a PEM encoded Ed25519 key pair to the working directory,
+// returning the paths of the private and public keys.
+func writeKeyPair(t *testing.T, name string) (private, public string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, public = name+".pem", name+".pub.pem"
+	if err := os.WriteFile(private, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(public, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return private, public
+}
+
+// readArchiveFile returns the content of the named file within the package.
+func readArchiveFile(t *testing.T, path, name string) []byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			t.Fatalf("%s not found in %s", name, path)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(hdr.Name) == name {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return data
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// StartersRootCommand is the parent command for the starter kit subcommands.
+type StartersRootCommand struct {
+	cmd.Base
+	// no flags
+}
+
+// NewStartersRootCommand returns a new command registered in the parent.
+func NewStartersRootCommand(parent cmd.Registerer, g *global.Data) *StartersRootCommand {
+	var c StartersRootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("starters", "Discover the starter kits available to `compute init`")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *StartersRootCommand) Exec(_ io.Reader, _ io.Writer) error {
+	panic("unreachable")
+}
+
+// StarterKitListing is a starter kit of a language.
+type StarterKitListing struct {
+	Language string `json:"language"`
+	config.StarterKit
+}
+
+// StartersListCommand lists the starter kits available to `compute init`.
+type StartersListCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	language string
+	search   string
+}
+
+// NewStartersListCommand returns a usable command registered under the parent.
+func NewStartersListCommand(parent cmd.Registerer, g *global.Data) *StartersListCommand {
+	var c StartersListCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("list", "List the official starter kits, and those of the [compute-init] config and its starter kit indexes")
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+
+	// NOTE: The 'other' language (last in Languages) has no starter kits.
+	languages := Languages[:len(Languages)-1]
+	c.CmdClause.Flag("language", "Only list the starter kits of the language").Short('l').HintOptions(languages...).EnumVar(&c.language, languages...)
+	c.CmdClause.Flag("search", "Only list the starter kits whose name, description, tags or repository contain the term (case-insensitive)").Short('s').StringVar(&c.search)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *StartersListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	defaults, err := loadInitDefaults(c.Globals.Config.ComputeInit)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	defer defaults.cleanup()
+
+	// NOTE: Index warnings would make the JSON output invalid.
+	warnings := out
+	if c.JSONOutput.Enabled {
+		warnings = io.Discard
+	}
+	kits := filterStarterKits(defaults.starterKits(c.Globals.Config.StarterKits, c.Globals.HTTPClient, warnings), c.language, c.search)
+
+	if ok, err := c.WriteJSON(out, kits); ok {
+		return err
+	}
+
+	if len(kits) == 0 {
+		text.Info(out, "No starter kits found")
+		return nil
+	}
+
+	if c.Globals.Verbose() {
+		for _, k := range kits {
+			fmt.Fprintf(out, "\nLanguage: %s\n", k.Language)
+			fmt.Fprintf(out, "Name: %s\n", k.Name)
+			fmt.Fprintf(out, "Description: %s\n", k.Description)
+			fmt.Fprintf(out, "Path: %s\n", k.Path)
+			if k.Branch != "" {
+				fmt.Fprintf(out, "Branch: %s\n", k.Branch)
+			}
+			if k.Tag != "" {
+				fmt.Fprintf(out, "Tag: %s\n", k.Tag)
+			}
+			if len(k.Tags) > 0 {
+				fmt.Fprintf(out, "Tags: %s\n", strings.Join(k.Tags, ", "))
+			}
+		}
+		fmt.Fprintln(out)
+		return nil
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("LANGUAGE", "NAME", "DESCRIPTION", "TAGS")
+	for _, k := range kits {
+		t.AddLine(k.Language, k.Name, k.Description, strings.Join(k.Tags, ", "))
+	}
+	t.Print()
+	text.Info(out, "Run `fastly compute init --language <language>` to select a starter kit, or `--verbose` to display the repository of each.")
+	return nil
+}
+
+// filterStarterKits returns the starter kits of the language (or of every
+// language if empty) that match the search term (if any).
+func filterStarterKits(kits config.StarterKitLanguages, language, search string) []StarterKitListing {
+	byLanguage := map[string][]config.StarterKit{
+		"rust":           kits.Rust,
+		"javascript":     kits.JavaScript,
+		"go":             kits.Go,
+		"assemblyscript": kits.AssemblyScript,
+	}
+	search = strings.ToLower(search)
+
+	listings := []StarterKitListing{}
+	for _, l := range Languages {
+		if language != "" && l != language {
+			continue
+		}
+		for _, k := range byLanguage[l] {
+			if search != "" && !starterKitMatches(k, search) {
+				continue
+			}
+			listings = append(listings, StarterKitListing{Language: l, StarterKit: k})
+		}
+	}
+	return listings
+}
+
+// starterKitMatches reports whether the lowercase search term is contained in
+// the starter kit's name, description, tags or path.
+func starterKitMatches(k config.StarterKit, search string) bool {
+	fields := append([]string{k.Name, k.Description, k.Path}, k.Tags...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), search) {
+			return true
+		}
+	}
+	return false
+}
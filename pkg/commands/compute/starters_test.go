@@ -0,0 +1,136 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestStartersList(t *testing.T) {
+	index := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"go":[{"name":"Auth proxy","description":"Verify JWTs","path":"https://git.example.com/go-auth","tags":["jwt","security"]}]}`))
+	}))
+	defer index.Close()
+
+	official := config.StarterKitLanguages{
+		Rust: []config.StarterKit{
+			{Name: "Default", Description: "A basic starter kit", Path: "https://github.com/fastly/compute-starter-kit-rust-default", Tags: []string{"basic"}},
+			{Name: "Authentication at Edge", Description: "OAuth 2.0 flows", Path: "https://github.com/fastly/compute-rust-auth"},
+		},
+		JavaScript: []config.StarterKit{
+			{Name: "Default", Description: "A basic starter kit", Path: "https://github.com/fastly/compute-starter-kit-javascript-default", Branch: "main"},
+		},
+	}
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		indexes       []string
+		wantError     string
+		wantOutput    []string
+		wantNotOutput []string
+		wantJSON      []compute.StarterKitListing
+	}{
+		{
+			name: "all languages",
+			args: "compute starters list",
+			wantOutput: []string{
+				"LANGUAGE    NAME",
+				"rust        Default                 A basic starter kit  basic",
+				"rust        Authentication at Edge  OAuth 2.0 flows",
+				"javascript  Default",
+			},
+		},
+		{
+			name:          "language",
+			args:          "compute starters list --language javascript",
+			wantOutput:    []string{"javascript  Default"},
+			wantNotOutput: []string{"rust"},
+		},
+		{
+			name:          "search",
+			args:          "compute starters list --search AUTH",
+			indexes:       []string{"/index.json"},
+			wantOutput:    []string{"Authentication at Edge", "go        Auth proxy", "jwt, security"},
+			wantNotOutput: []string{"Default"},
+		},
+		{
+			name:          "search tags",
+			args:          "compute starters list --search basic",
+			wantOutput:    []string{"A basic starter kit  basic"},
+			wantNotOutput: []string{"Authentication"},
+		},
+		{
+			name:       "no matches",
+			args:       "compute starters list --language go",
+			wantOutput: []string{"No starter kits found"},
+		},
+		{
+			name:       "unavailable index",
+			args:       "compute starters list --language go",
+			indexes:    []string{"/missing.json"},
+			wantOutput: []string{"Failed to fetch the [compute-init] starter kit index", "No starter kits found"},
+		},
+		{
+			name:       "verbose",
+			args:       "compute starters list --language javascript --verbose",
+			wantOutput: []string{"Path: https://github.com/fastly/compute-starter-kit-javascript-default", "Branch: main"},
+		},
+		{
+			name:    "json",
+			args:    "compute starters list --json --language go",
+			indexes: []string{"/index.json", "/missing.json"},
+			wantJSON: []compute.StarterKitListing{{
+				Language: "go",
+				StarterKit: config.StarterKit{
+					Name:        "Auth proxy",
+					Description: "Verify JWTs",
+					Path:        "https://git.example.com/go-auth",
+					Tags:        []string{"jwt", "security"},
+				},
+			}},
+		},
+		{
+			name:      "verbose and json",
+			args:      "compute starters list --json --verbose",
+			wantError: "invalid flag combination, --verbose and --json",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.ConfigFile.StarterKits = official
+			for _, path := range testcase.indexes {
+				opts.ConfigFile.ComputeInit.StarterKitIndexes = append(opts.ConfigFile.ComputeInit.StarterKitIndexes, index.URL+path)
+			}
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.wantNotOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+			if testcase.wantJSON != nil {
+				var got []compute.StarterKitListing
+				if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, testcase.wantJSON, got)
+			}
+		})
+	}
+}
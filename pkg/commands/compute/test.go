@@ -0,0 +1,254 @@
+package compute
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The environment variables exposing the local server to the test command.
+const (
+	// LocalURLEnvVar is the URL of the local server, e.g. http://127.0.0.1:1234.
+	LocalURLEnvVar = "FASTLY_LOCAL_URL"
+	// LocalAddrEnvVar is the address of the local server, e.g. 127.0.0.1:1234.
+	LocalAddrEnvVar = "FASTLY_LOCAL_ADDR"
+)
+
+// TestCommand builds the package, runs it locally under Viceroy and runs the
+// project's integration tests against it.
+type TestCommand struct {
+	cmd.Base
+	manifest manifest.Data
+	build    *BuildCommand
+	av       github.AssetVersioner
+
+	// Build fields
+	frozen      cmd.OptionalBool
+	cache       cmd.OptionalBool
+	includeSrc  cmd.OptionalBool
+	lang        cmd.OptionalString
+	optimize    cmd.OptionalBool
+	packageName cmd.OptionalString
+	timeout     cmd.OptionalInt
+	verifyRepro cmd.OptionalBool
+
+	// Test fields
+	command        string
+	file           string
+	skipBuild      bool
+	startTimeout   time.Duration
+	viceroyBinPath string
+}
+
+// NewTestCommand returns a usable command registered under the parent.
+func NewTestCommand(parent cmd.Registerer, g *global.Data, build *BuildCommand, av github.AssetVersioner, m manifest.Data) *TestCommand {
+	var c TestCommand
+
+	c.build = build
+	c.av = av
+
+	c.Globals = g
+	c.CmdClause = parent.Command("test", "Build and run a Compute@Edge package locally, then run integration tests against it")
+	c.manifest = m
+
+	c.CmdClause.Flag("cache", fmt.Sprintf("Skip the build if nothing has changed since the last build (see %s), use --no-cache to force a rebuild", BuildCacheDir)).Action(c.cache.Set).NegatableBoolVar(&c.cache.Value)
+	c.CmdClause.Flag("command", "The test command to run (overrides [scripts.test] in fastly.toml)").StringVar(&c.command)
+	c.CmdClause.Flag("file", "The Wasm file to run").Default("bin/main.wasm").StringVar(&c.file)
+	c.CmdClause.Flag("frozen", fmt.Sprintf("Fail the build if the toolchain versions don't match those recorded in %s", LockFilename)).Action(c.frozen.Set).BoolVar(&c.frozen.Value)
+	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
+	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
+	c.CmdClause.Flag("optimize", "Optimize the Wasm binary with wasm-opt before packaging it (downloaded on first use)").Action(c.optimize.Set).BoolVar(&c.optimize.Value)
+	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.skipBuild)
+	c.CmdClause.Flag("start-timeout", "How long to wait for the local server to start").Default("30s").DurationVar(&c.startTimeout)
+	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
+	c.CmdClause.Flag("verify-reproducible", "Build the package a second time and fail if the packages differ").Action(c.verifyRepro.Set).BoolVar(&c.verifyRepro.Value)
+	c.CmdClause.Flag("viceroy-path", "The path to a user installed version of the Viceroy binary").StringVar(&c.viceroyBinPath)
+
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *TestCommand) Exec(in io.Reader, out io.Writer) error {
+	command := c.command
+	if command == "" {
+		command = c.Globals.Manifest.File.Scripts.Test
+	}
+	if command == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("no test command"),
+			Remediation: fmt.Sprintf("Define the command in fastly.toml (e.g. [scripts] test = \"go test ./...\") or use the --command flag. The tests can read the local server's URL from $%s.", LocalURLEnvVar),
+		}
+	}
+
+	if !c.skipBuild {
+		if err := c.Build(in, out); err != nil {
+			return err
+		}
+	}
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+	bin, err := GetViceroy(spinner, out, c.av, c.Globals, c.viceroyBinPath)
+	if err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	manifestPath, stopLocalServer, err := PrepareLocalServer(localManifestPath(wd, c.Globals.Flags.Env), LocalOverrides{Environment: c.Globals.Flags.Env}, c.Globals.Verbose(), out)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	defer stopLocalServer()
+
+	addr, err := freeLocalAddr()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	var logs bytes.Buffer
+	stopViceroy, err := startViceroy(bin, manifestPath, c.file, addr, &logs, c.startTimeout)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Output(out, "%s", logs.String())
+		return err
+	}
+	defer stopViceroy()
+
+	url := "http://" + addr
+	text.Info(out, "Local server listening on %s", url)
+	text.Break(out)
+
+	name, args := Shell{}.Build(command)
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the test command is provided by the user.
+	// #nosec
+	// nosemgrep
+	test := exec.Command(name, args...)
+	test.Env = append(os.Environ(), LocalURLEnvVar+"="+url, LocalAddrEnvVar+"="+addr)
+	test.Stdin = in
+	test.Stdout = out
+	test.Stderr = out
+	err = test.Run()
+	// Viceroy is stopped before its output is read.
+	stopViceroy()
+
+	text.Break(out)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		if c.Globals.Verbose() || logs.Len() > 0 {
+			text.Output(out, "%s\n%s", text.BoldYellow("Local server output:"), logs.String())
+		}
+		return fmt.Errorf("test command failed: %w", err)
+	}
+	if c.Globals.Verbose() {
+		text.Output(out, "%s\n%s", text.BoldYellow("Local server output:"), logs.String())
+	}
+	text.Success(out, "Tests passed")
+	return nil
+}
+
+// Build constructs and executes the build logic.
+func (c *TestCommand) Build(in io.Reader, out io.Writer) error {
+	// Reset the fields on the BuildCommand based on TestCommand values.
+	if c.frozen.WasSet {
+		c.build.Flags.Frozen = c.frozen.Value
+	}
+	if c.includeSrc.WasSet {
+		c.build.Flags.IncludeSrc = c.includeSrc.Value
+	}
+	if c.lang.WasSet {
+		c.build.Flags.Lang = c.lang.Value
+	}
+	if c.optimize.WasSet {
+		c.build.Flags.Optimize = c.optimize.Value
+	}
+	if c.cache.WasSet {
+		c.build.Flags.NoCache = !c.cache.Value
+	}
+	if c.packageName.WasSet {
+		c.build.Flags.PackageName = c.packageName.Value
+	}
+	if c.timeout.WasSet {
+		c.build.Flags.Timeout = c.timeout.Value
+	}
+	if c.verifyRepro.WasSet {
+		c.build.Flags.VerifyReproducible = c.verifyRepro.Value
+	}
+
+	err := c.build.Exec(in, out)
+	if err != nil {
+		return err
+	}
+
+	text.Break(out)
+
+	return nil
+}
+
+// startViceroy runs Viceroy in the background, writing its output to logs, and
+// waits for it to accept connections. The returned function stops it.
+func startViceroy(bin, manifestPath, file, addr string, logs io.Writer, timeout time.Duration) (func(), error) {
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the variables come from trusted sources.
+	// #nosec
+	// nosemgrep
+	viceroy := exec.Command(bin, "-C", manifestPath, "--addr", addr, file)
+	viceroy.Stdout = logs
+	viceroy.Stderr = logs
+	if err := viceroy.Start(); err != nil {
+		return nil, fmt.Errorf("error starting Viceroy: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- viceroy.Wait()
+	}()
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			_ = viceroy.Process.Kill()
+			<-exited
+		})
+	}
+
+	deadline := time.After(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			return stop, nil
+		}
+		select {
+		case err := <-exited:
+			return nil, fmt.Errorf("the local server exited before it started listening: %v", err)
+		case <-deadline:
+			stop()
+			return nil, fmt.Errorf("the local server didn't start listening on %s within %s", addr, timeout)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package compute_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// fakeViceroy writes a `viceroy` script to dir that runs the given shell body,
+// where $ADDR is the value of the --addr flag.
+func fakeViceroy(t *testing.T, dir, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake viceroy binary is a shell script")
+	}
+
+	path := filepath.Join(dir, "viceroy")
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--addr" ]; then ADDR="$2"; fi
+  shift
+done
+` + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil { // #nosec G306
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTest(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	manifest := `name = "package"
+manifest_version = 2
+language = "rust"
+`
+	if err := os.WriteFile(filepath.Join(dir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	exits := fakeViceroy(t, dir, `echo "error: no such file"; exit 1`)
+
+	type scenario struct {
+		name       string
+		args       []string
+		wantError  string
+		wantOutput []string
+	}
+	scenarios := []scenario{
+		{
+			name:      "validate missing test command",
+			args:      testutil.Args("compute test --skip-build"),
+			wantError: "no test command",
+		},
+		{
+			name:       "validate local server exits",
+			args:       testutil.Args("compute test --skip-build --command true --start-timeout 5s --viceroy-path " + exits),
+			wantError:  "the local server exited before it started listening",
+			wantOutput: []string{"error: no such file"},
+		},
+	}
+
+	// The success scenarios need a server that listens on --addr.
+	if python, err := exec.LookPath("python3"); err == nil {
+		listens := fakeViceroy(t, t.TempDir(), `exec `+python+` -m http.server --bind "${ADDR%:*}" "${ADDR##*:}"`)
+		scenarios = append(scenarios,
+			scenario{
+				name:       "success",
+				args:       []string{"compute", "test", "--skip-build", "--viceroy-path", listens, "--command", `echo "testing $FASTLY_LOCAL_URL" && test -n "$FASTLY_LOCAL_ADDR"`},
+				wantOutput: []string{"Local server listening on http://127.0.0.1:", "testing http://127.0.0.1:", "Tests passed"},
+			},
+			scenario{
+				name:      "validate failing tests",
+				args:      []string{"compute", "test", "--skip-build", "--viceroy-path", listens, "--command", "exit 3"},
+				wantError: "test command failed: exit status 3",
+			},
+		)
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
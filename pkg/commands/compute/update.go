@@ -11,7 +11,6 @@ import (
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
-	"github.com/fastly/go-fastly/v7/fastly"
 	"github.com/kennygrant/sanitize"
 )
 
@@ -110,42 +109,14 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		}
 	}()
 
-	err = spinner.Start()
+	err = pkgUpload(spinner, c.Globals.APIClient, serviceID, serviceVersion.Number, packagePath)
 	if err != nil {
-		return err
-	}
-	msg := "Uploading package"
-	spinner.Message(msg + "...")
-
-	_, err = c.Globals.APIClient.UpdatePackage(&fastly.UpdatePackageInput{
-		ServiceID:      serviceID,
-		ServiceVersion: serviceVersion.Number,
-		PackagePath:    packagePath,
-	})
-	if err != nil {
-		c.Globals.ErrLog.AddWithContext(err, map[string]any{
-			"Service ID":      serviceID,
-			"Service Version": serviceVersion.Number,
-		})
-
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return spinErr
-		}
-
 		return fsterr.RemediationError{
-			Inner:       fmt.Errorf("error uploading package: %w", err),
+			Inner:       err,
 			Remediation: "Run `fastly compute build` to produce a Compute@Edge package, alternatively use the --package flag to reference a package outside of the current project.",
 		}
 	}
 
-	spinner.StopMessage(msg)
-	err = spinner.Stop()
-	if err != nil {
-		return err
-	}
-
 	text.Success(out, "Updated package (service %s, version %v)", serviceID, serviceVersion.Number)
 	return nil
 }
@@ -0,0 +1,160 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// UploadAttempts is the number of times a package upload is attempted before
+// giving up, when the upload fails with a transient error.
+//
+// NOTE: These are package level variables as it makes testing the behaviour
+// of the package easier because the test code can replace the values when
+// running the test suite.
+var (
+	UploadAttempts   = 3
+	UploadRetryDelay = 2 * time.Second
+)
+
+// uploadProgress is called as the package is sent to the API.
+type uploadProgress func(sent, total int64)
+
+// uploadPackage uploads a package, retrying the upload when it fails with a
+// transient error (e.g. a dropped connection or a 5xx response). The retry
+// function is called before each retry.
+//
+// NOTE: The API doesn't support resuming a partial upload, so each retry sends
+// the whole package. The delay between attempts doubles after each attempt.
+func uploadPackage(client api.Interface, input *fastly.UpdatePackageInput, progress uploadProgress, retry func(attempt int, err error)) error {
+	if progress != nil {
+		defer withUploadProgress(client, progress)()
+	}
+
+	delay := UploadRetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		if _, err = client.UpdatePackage(input); err == nil {
+			return nil
+		}
+		if attempt >= UploadAttempts || !isTransientUploadError(err) {
+			break
+		}
+		if retry != nil {
+			retry(attempt+1, err)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
+
+// isTransientUploadError indicates if an upload failed for a reason that's
+// likely to be resolved by retrying it.
+func isTransientUploadError(err error) bool {
+	// NOTE: The request is wrapped in a url.Error (a net.Error) when blocked by
+	// read-only mode, which retrying can't resolve.
+	if errors.Is(err, api.ErrReadOnly) {
+		return false
+	}
+	var httpErr *fastly.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError ||
+			httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode == http.StatusRequestTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// withUploadProgress reports the progress of package uploads made by the API
+// client, returning a function that stops reporting.
+//
+// NOTE: Only the API client of the CLI (rather than a test mock) makes HTTP
+// requests, so progress isn't reported otherwise.
+func withUploadProgress(client api.Interface, progress uploadProgress) func() {
+	c, ok := client.(*fastly.Client)
+	if !ok || c.HTTPClient == nil {
+		return func() {}
+	}
+	original := c.HTTPClient
+	hc := *original
+	hc.Transport = progressTransport{next: hc.Transport, progress: progress}
+	c.HTTPClient = &hc
+	return func() {
+		c.HTTPClient = original
+	}
+}
+
+// progressTransport reports the progress of the request body of package
+// uploads.
+type progressTransport struct {
+	next     http.RoundTripper
+	progress uploadProgress
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodPut || !strings.HasSuffix(req.URL.Path, "/package") || req.Body == nil || req.ContentLength <= 0 {
+		return next.RoundTrip(req)
+	}
+	r := req.Clone(req.Context())
+	r.Body = &progressReader{ReadCloser: req.Body, total: r.ContentLength, progress: t.progress}
+	return next.RoundTrip(r)
+}
+
+// progressReader reports the bytes read from the wrapped reader.
+type progressReader struct {
+	io.ReadCloser
+	mu       sync.Mutex
+	sent     int64
+	total    int64
+	progress uploadProgress
+}
+
+// Read implements io.Reader.
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.mu.Lock()
+	r.sent += int64(n)
+	sent := r.sent
+	r.mu.Unlock()
+	r.progress(sent, r.total)
+	return n, err
+}
+
+// progressBar renders the progress of an upload, e.g.
+// [##########----------] 50% (6.0 MB of 12.0 MB).
+func progressBar(sent, total int64) string {
+	const width = 20
+	if total <= 0 {
+		return ""
+	}
+	if sent > total {
+		sent = total
+	}
+	filled := int(sent * width / total)
+	return fmt.Sprintf("[%s%s] %d%% (%s of %s)",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", width-filled),
+		sent*100/total,
+		formatSize(sent),
+		formatSize(total),
+	)
+}
@@ -0,0 +1,162 @@
+package compute_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestUploadRetry(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	delay := compute.UploadRetryDelay
+	compute.UploadRetryDelay = 0
+	defer func() { compute.UploadRetryDelay = delay }()
+
+	unavailable := &fastly.HTTPError{StatusCode: http.StatusServiceUnavailable}
+	invalid := &fastly.HTTPError{StatusCode: http.StatusBadRequest}
+
+	for _, testcase := range []struct {
+		name         string
+		errs         []error
+		wantError    string
+		wantAttempts int
+	}{
+		{
+			name:         "transient error is retried",
+			errs:         []error{unavailable},
+			wantAttempts: 2,
+		},
+		{
+			name:         "invalid package isn't retried",
+			errs:         []error{invalid},
+			wantError:    "error uploading package",
+			wantAttempts: 1,
+		},
+		{
+			name:         "attempts are limited",
+			errs:         []error{unavailable, unavailable, unavailable, unavailable},
+			wantError:    "error uploading package",
+			wantAttempts: compute.UploadAttempts,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var attempts int
+			api := mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					attempts++
+					if attempts <= len(testcase.errs) {
+						return nil, testcase.errs[attempts-1]
+					}
+					return updatePackageOk(i)
+				},
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args("compute update -s 123 --version 1 --package pkg/package.tar.gz -t 123 --autoclone"), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertEqual(t, testcase.wantAttempts, attempts)
+			if testcase.wantError == "" {
+				testutil.AssertStringContains(t, stdout.String(), "Updated package (service 123, version 4)")
+			}
+		})
+	}
+}
+
+// TestUploadProgress validates the whole package is sent through the progress
+// reporting HTTP transport of the API client, and resent after a transient
+// error.
+func TestUploadProgress(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd)
+
+	delay := compute.UploadRetryDelay
+	compute.UploadRetryDelay = 0
+	defer func() { compute.UploadRetryDelay = delay }()
+
+	pkg, err := os.ReadFile(filepath.Join("pkg", "package.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var uploads []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/service/123/version":
+			fmt.Fprint(w, `[{"number":1,"service_id":"123","active":false,"locked":false}]`)
+		case r.Method == http.MethodPut && r.URL.Path == "/service/123/version/1/package":
+			body, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, len(body))
+			if !bytes.Contains(body, pkg) {
+				t.Errorf("the uploaded form doesn't contain the package")
+			}
+			if len(uploads) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				fmt.Fprint(w, `{"msg":"bad gateway"}`)
+				return
+			}
+			fmt.Fprint(w, `{"service_id":"123","version":1}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("compute update -s 123 --version 1 --package pkg/package.tar.gz --token abc --endpoint "+srv.URL), &stdout)
+	opts.APIClient = app.FastlyAPIClient
+	err = app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(uploads))
+	testutil.AssertEqual(t, uploads[0], uploads[1])
+	testutil.AssertStringContains(t, stdout.String(), "Updated package (service 123, version 1)")
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +26,7 @@ func TestCreateEntryCommand(t *testing.T) {
 		itemValue = "the-value"
 	)
 	now := time.Now()
+	t.Setenv("FASTLY_TEST_CONFIG_STORE_VALUE", itemValue)
 
 	scenarios := []testutil.TestScenario{
 		{
@@ -72,6 +76,30 @@ func TestCreateEntryCommand(t *testing.T) {
 				UpdatedAt: &now,
 			}),
 		},
+		{
+			Args:      testutil.Args(fmt.Sprintf("%s create --store-id %s --key %s --value %s --value-from-env FASTLY_TEST_CONFIG_STORE_VALUE", configstoreentry.RootName, storeID, itemKey, itemValue)),
+			WantError: "multiple item values provided",
+		},
+		{
+			Args:      testutil.Args(fmt.Sprintf("%s create --store-id %s --key %s --stdin --value-from-env FASTLY_TEST_CONFIG_STORE_VALUE", configstoreentry.RootName, storeID, itemKey)),
+			WantError: "multiple item values provided",
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s create --store-id %s --key %s --value-from-env FASTLY_TEST_CONFIG_STORE_VALUE", configstoreentry.RootName, storeID, itemKey)),
+			API: mock.API{
+				CreateConfigStoreItemFn: func(i *fastly.CreateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+					if i.Value != itemValue {
+						return nil, fmt.Errorf("unexpected value: %s", i.Value)
+					}
+					return &fastly.ConfigStoreItem{
+						StoreID: i.StoreID,
+						Key:     i.Key,
+						Value:   i.Value,
+					}, nil
+				},
+			},
+			WantOutput: fstfmt.Success("Created config store item %s in store %s", itemKey, storeID),
+		},
 	}
 
 	for _, testcase := range scenarios {
@@ -371,6 +399,83 @@ func TestUpdateEntryCommand(t *testing.T) {
 	}
 }
 
+func TestImportEntryCommand(t *testing.T) {
+	const storeID = "store-id-123"
+	file := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(file, []byte(`{"flag": "on", "limit": "10"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := map[string]string{"flag": "off", "limit": "10"}
+	api := mock.API{
+		ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+			var items []*fastly.ConfigStoreItem
+			for k, v := range remote {
+				items = append(items, &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: k, Value: v})
+			}
+			return items, nil
+		},
+		UpdateConfigStoreItemFn: func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+			remote[i.Key] = i.Value
+			return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: i.Value}, nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		stdin      string
+		hotfix     map[string]string
+		wantError  string
+		wantOutput string
+		wantRemote map[string]string
+	}{
+		{
+			name:      "validate missing --file flag",
+			args:      " import --store-id " + storeID,
+			wantError: "error parsing arguments: required flag --file not provided",
+		},
+		{
+			name:       "validate conflict is skipped",
+			args:       " import --store-id " + storeID + " --file " + file,
+			stdin:      "s\n",
+			wantOutput: "Skipped: flag",
+			wantRemote: map[string]string{"flag": "off", "limit": "10"},
+		},
+		{
+			name:       "validate conflict keeping the local value",
+			args:       " import --store-id " + storeID + " --file " + file,
+			stdin:      "l\n",
+			wantOutput: "(0 created, 1 updated, 0 deleted)",
+			wantRemote: map[string]string{"flag": "on", "limit": "10"},
+		},
+		{
+			name:       "validate remote hotfix is detected",
+			args:       " import --store-id " + storeID + " --file " + file,
+			stdin:      "r\n",
+			hotfix:     map[string]string{"limit": "20"},
+			wantOutput: "Updated " + file + " with the remote values of: limit",
+			wantRemote: map[string]string{"flag": "on", "limit": "20"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			for k, v := range testcase.hotfix {
+				remote[k] = v
+			}
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(configstoreentry.RootName+testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantRemote != nil {
+				testutil.AssertEqual(t, testcase.wantRemote, remote)
+			}
+		})
+	}
+}
+
 func printConfigStoreItem(i *fastly.ConfigStoreItem) string {
 	var b bytes.Buffer
 	text.PrintConfigStoreItem(&b, "", i)
@@ -41,10 +41,11 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 	})
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        "value",
-		Description: "Item value. Required unless --stdin is set",
+		Description: "Item value. Required unless --stdin, --value-from-env or --value-from-file is set",
 		Dst:         &c.input.Value,
 		Required:    false,
 	})
+	c.RegisterValueSourceFlags(&c.valueSource)
 
 	// Optional.
 	c.RegisterFlagBool(c.JSONFlag()) // --json
@@ -57,9 +58,10 @@ type CreateCommand struct {
 	cmd.Base
 	cmd.JSONOutput
 
-	input    fastly.CreateConfigStoreItemInput
-	stdin    bool
-	manifest manifest.Data
+	input       fastly.CreateConfigStoreItemInput
+	stdin       bool
+	manifest    manifest.Data
+	valueSource cmd.ValueSource
 }
 
 // Exec invokes the application logic for the command.
@@ -68,6 +70,14 @@ func (cmd *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if cmd.stdin && (cmd.valueSource.Env != "" || cmd.valueSource.File != "") {
+		return errMultipleValues
+	}
+	if err := cmd.valueSource.Resolve(&cmd.input.Value); err != nil {
+		cmd.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	if cmd.stdin {
 		// Determine if 'in' has data available.
 		if in == nil || text.IsTTY(in) {
@@ -19,7 +19,12 @@ var errNoSTDINData = fsterr.RemediationError{
 
 var errNoValue = fsterr.RemediationError{
 	Inner:       errors.New("no value provided"),
-	Remediation: "Use --value or --stdin to specify item value",
+	Remediation: "Use --value, --value-from-env, --value-from-file or --stdin to specify item value",
+}
+
+var errMultipleValues = fsterr.RemediationError{
+	Inner:       errors.New("multiple item values provided"),
+	Remediation: "Provide only one of --value, --value-from-env, --value-from-file or --stdin",
 }
 
 var errMaxKeyLen = fsterr.RemediationError{
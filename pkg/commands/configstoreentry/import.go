@@ -0,0 +1,143 @@
+package configstoreentry
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/kvsync"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ImportCommand {
+	c := ImportCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+
+	c.CmdClause = parent.Command("import", "Import config store items from a local JSON file, prompting before overwriting items changed remotely")
+
+	// Required.
+	c.RegisterFlag(cmd.StoreIDFlag(&c.storeID)) // --store-id
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "file",
+		Description: "Path to a JSON object of the items, e.g. {\"key\": \"value\"}",
+		Dst:         &c.file,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("prefer", "Resolve items changed remotely since the last import without prompting, keeping the local or remote value").HintOptions(kvsync.Preferences...).EnumVar(&c.prefer, kvsync.Preferences...)
+
+	return &c
+}
+
+// ImportCommand calls the Fastly API to make the items of a config store match
+// a local file.
+type ImportCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	file     string
+	manifest manifest.Data
+	prefer   string
+	storeID  string
+}
+
+// Exec invokes the application logic for the command.
+func (cmd *ImportCommand) Exec(in io.Reader, out io.Writer) error {
+	if cmd.Globals.Verbose() && cmd.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	items, err := cmd.Globals.APIClient.ListConfigStoreItems(&fastly.ListConfigStoreItemsInput{
+		StoreID: cmd.storeID,
+	})
+	if err != nil {
+		cmd.Globals.ErrLog.Add(err)
+		return err
+	}
+	remote := make(map[string]string, len(items))
+	for _, i := range items {
+		remote[i.Key] = i.Value
+	}
+
+	msgs := out
+	if cmd.JSONOutput.Enabled {
+		msgs = io.Discard
+	}
+	result, err := kvsync.Sync(kvsync.Options{
+		File:           cmd.file,
+		Target:         kvsync.Target(cmd.storeID),
+		Remote:         remote,
+		Apply:          cmd.apply,
+		Prefer:         cmd.prefer,
+		NonInteractive: cmd.Globals.Flags.NonInteractive || cmd.Globals.Flags.AutoYes || cmd.JSONOutput.Enabled,
+		In:             in,
+		Out:            msgs,
+	})
+	if err != nil {
+		cmd.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Store ID": cmd.storeID,
+			"File":     cmd.file,
+		})
+		return err
+	}
+
+	if ok, err := cmd.WriteJSON(out, result); ok {
+		return err
+	}
+
+	if len(result.KeptRemote) > 0 {
+		text.Info(out, "Updated %s with the remote values of: %s", cmd.file, strings.Join(result.KeptRemote, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		text.Warning(out, "Skipped: %s", strings.Join(result.Skipped, ", "))
+	}
+	text.Success(out, "Imported %s into config store %s (%d created, %d updated, %d deleted)", cmd.file, cmd.storeID, result.Created, result.Updated, result.Deleted)
+	return nil
+}
+
+// apply modifies the config store items one at a time, as the API doesn't
+// support batch modifications.
+func (cmd *ImportCommand) apply(changes []kvsync.Change) error {
+	for _, ch := range changes {
+		if len(ch.Key) > maxKeyLen {
+			return fmt.Errorf("%w: %s", errMaxKeyLen, ch.Key)
+		}
+		if len(ch.Value) > maxValueLen {
+			return fmt.Errorf("%w: %s", errMaxValueLen, ch.Key)
+		}
+	}
+
+	for _, ch := range changes {
+		var err error
+		switch ch.Action {
+		case kvsync.Create, kvsync.Update:
+			_, err = cmd.Globals.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
+				Key:     ch.Key,
+				StoreID: cmd.storeID,
+				Upsert:  true,
+				Value:   ch.Value,
+			})
+		case kvsync.Delete:
+			err = cmd.Globals.APIClient.DeleteConfigStoreItem(&fastly.DeleteConfigStoreItemInput{
+				Key:     ch.Key,
+				StoreID: cmd.storeID,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("error applying %s of item '%s': %w", ch.Action, ch.Key, err)
+		}
+	}
+	return nil
+}
@@ -41,10 +41,11 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 	})
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        "value",
-		Description: "Item value. Required unless --stdin is set",
+		Description: "Item value. Required unless --stdin, --value-from-env or --value-from-file is set",
 		Dst:         &c.input.Value,
 		Required:    false,
 	})
+	c.RegisterValueSourceFlags(&c.valueSource)
 
 	// Optional.
 	c.RegisterFlagBool(c.JSONFlag()) // --json
@@ -63,9 +64,10 @@ type UpdateCommand struct {
 	cmd.Base
 	cmd.JSONOutput
 
-	input    fastly.UpdateConfigStoreItemInput
-	stdin    bool
-	manifest manifest.Data
+	input       fastly.UpdateConfigStoreItemInput
+	stdin       bool
+	manifest    manifest.Data
+	valueSource cmd.ValueSource
 }
 
 // Exec invokes the application logic for the command.
@@ -74,6 +76,14 @@ func (cmd *UpdateCommand) Exec(in io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if cmd.stdin && (cmd.valueSource.Env != "" || cmd.valueSource.File != "") {
+		return errMultipleValues
+	}
+	if err := cmd.valueSource.Resolve(&cmd.input.Value); err != nil {
+		cmd.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	if cmd.stdin {
 		// Determine if 'in' has data available.
 		if in == nil || text.IsTTY(in) {
@@ -0,0 +1,159 @@
+package contact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestList(t *testing.T) {
+	server := httptest.NewServer(customerHandler(t, nil))
+	defer server.Close()
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate the contacts are listed",
+			API:  mock.API{ListCustomerUsersFn: listCustomerUsers},
+			Args: args("contacts list --token 123 --endpoint " + server.URL),
+			WantOutputs: []string{
+				"TYPE       NAME           EMAIL                 PHONE     USER ID",
+				"billing                                                   u9",
+				"security   Security Team  security@example.com            u3",
+				"emergency  On call        oncall@example.com    555-0100",
+				"The billing contact is assigned to u9, which isn't a user of the customer account.",
+			},
+		},
+		{
+			Name:       "validate --json",
+			API:        mock.API{ListCustomerUsersFn: listCustomerUsers},
+			Args:       args("contacts list --customer-id abc --json --token 123 --endpoint " + server.URL),
+			WantOutput: `"contact_type": "emergency"`,
+		},
+		{
+			Name: "validate ListCustomerUsers API error",
+			API: mock.API{
+				ListCustomerUsersFn: func(i *fastly.ListCustomerUsersInput) ([]*fastly.User, error) {
+					return nil, testutil.Err
+				},
+			},
+			Args:      args("contacts list --token 123 --endpoint " + server.URL),
+			WantError: testutil.Err.Error(),
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		wantForm url.Values
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing contact flags",
+				Args:      args("contacts update --token 123"),
+				WantError: "no contact roles to assign",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate the contacts are assigned by ID and login",
+				API:        mock.API{ListCustomerUsersFn: listCustomerUsers},
+				Args:       args("contacts update --security SECURITY@example.com --technical u4 --token 123"),
+				WantOutput: "Updated the contacts of customer abc: security (user u3), technical (user u4)",
+			},
+			wantForm: url.Values{
+				"security_contact_id":  {"u3"},
+				"technical_contact_id": {"u4"},
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate unknown login",
+				API:       mock.API{ListCustomerUsersFn: listCustomerUsers},
+				Args:      args("contacts update --billing nobody@example.com --token 123"),
+				WantError: "error parsing --billing: no user of customer abc has the login 'nobody@example.com'",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var form url.Values
+			server := httptest.NewServer(customerHandler(t, &form))
+			defer server.Close()
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(append(testcase.Args, "--endpoint", server.URL), &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			testutil.AssertEqual(t, testcase.wantForm, form)
+		})
+	}
+}
+
+// customerHandler serves the customer API endpoints, recording the form of an
+// update request.
+func customerHandler(t *testing.T, form *url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cust := customer.Customer{
+			ID:                "abc",
+			BillingContactID:  "u9",
+			SecurityContactID: "u3",
+		}
+		switch {
+		case r.URL.Path == "/customer/abc" && r.Method == http.MethodPut:
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			*form = r.PostForm
+			cust.SecurityContactID = r.PostForm.Get("security_contact_id")
+			cust.TechnicalContactID = r.PostForm.Get("technical_contact_id")
+			_ = json.NewEncoder(w).Encode(cust)
+		case r.URL.Path == "/current_customer", r.URL.Path == "/customer/abc":
+			_ = json.NewEncoder(w).Encode(cust)
+		case r.URL.Path == "/customer/abc/contacts":
+			_ = json.NewEncoder(w).Encode([]customer.Contact{
+				{ID: "c1", ContactType: "security", Name: "Security Team", Email: "security@example.com", UserID: "u3"},
+				{ID: "c2", ContactType: "emergency", Name: "On call", Email: "oncall@example.com", Phone: "555-0100"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func listCustomerUsers(i *fastly.ListCustomerUsersInput) ([]*fastly.User, error) {
+	return []*fastly.User{
+		{ID: "u3", Login: "security@example.com", Name: "Security Team", CustomerID: i.CustomerID},
+		{ID: "u4", Login: "ops@example.com", Name: "Ops", CustomerID: i.CustomerID},
+	}, nil
+}
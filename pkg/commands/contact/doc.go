@@ -0,0 +1,3 @@
+// Package contact contains commands to inspect and assign the contacts of the
+// Fastly customer account.
+package contact
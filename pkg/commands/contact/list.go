@@ -0,0 +1,127 @@
+package contact
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewListCommand returns a usable command registered under the parent.
+func NewListCommand(parent cmd.Registerer, g *global.Data) *ListCommand {
+	var c ListCommand
+	c.CmdClause = parent.Command("list", "List the contacts of the customer account, including the users assigned the billing, legal, security and technical contact roles")
+	c.Globals = g
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagCustomerIDName,
+		Description: customerIDDesc,
+		Dst:         &c.customerID.Value,
+		Action:      c.customerID.Set,
+	})
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// ListCommand calls the Fastly API to list the contacts of a customer.
+type ListCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	customerID cmd.OptionalCustomerID
+}
+
+// Exec invokes the application logic for the command.
+func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	client, err := newClient(c.Globals)
+	if err != nil {
+		return err
+	}
+
+	cust, err := getCustomer(client, &c.customerID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": c.customerID.Value,
+		})
+		return err
+	}
+	users, err := c.Globals.APIClient.ListCustomerUsers(&fastly.ListCustomerUsersInput{
+		CustomerID: cust.ID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": cust.ID,
+		})
+		return err
+	}
+	contacts, err := client.ListContacts(cust.ID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": cust.ID,
+		})
+		return err
+	}
+
+	rs, unknown := listContacts(cust, users, contacts)
+
+	if ok, err := c.WriteJSON(out, rs); ok {
+		return err
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("TYPE", "NAME", "EMAIL", "PHONE", "USER ID")
+	for _, r := range rs {
+		t.AddLine(r.ContactType, r.Name, r.Email, r.Phone, r.UserID)
+	}
+	t.Print()
+
+	for _, role := range unknown {
+		text.Warning(out, "The %s contact is assigned to %s, which isn't a user of the customer account. Run `fastly contacts update --%s` to assign it to a current user.", role, cust.ContactID(role), role)
+	}
+	return nil
+}
+
+// listContacts returns the users assigned each contact role, followed by the
+// other contacts of the customer, and the roles assigned to unknown users.
+func listContacts(cust *customer.Customer, users []*fastly.User, contacts []customer.Contact) (rs []customer.Contact, unknown []string) {
+	rs = []customer.Contact{}
+	byID := make(map[string]*fastly.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	assigned := make(map[string]bool)
+	for _, role := range customer.Roles {
+		id := cust.ContactID(role)
+		if id == "" {
+			continue
+		}
+		r := customer.Contact{
+			ContactType: role,
+			UserID:      id,
+			CustomerID:  cust.ID,
+		}
+		if u, ok := byID[id]; ok {
+			r.Name = u.Name
+			r.Email = u.Login
+		} else {
+			unknown = append(unknown, role)
+		}
+		rs = append(rs, r)
+		assigned[role+"/"+id] = true
+	}
+
+	for _, ct := range contacts {
+		if ct.UserID != "" && assigned[ct.ContactType+"/"+ct.UserID] {
+			continue
+		}
+		rs = append(rs, ct)
+	}
+	return rs, unknown
+}
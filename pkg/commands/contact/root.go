@@ -0,0 +1,57 @@
+package contact
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+)
+
+// customerIDDesc is the description of the --customer-id flag.
+const customerIDDesc = "Alphanumeric string identifying the customer (falls back to FASTLY_CUSTOMER_ID, then the customer of the authenticated user)"
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	cmd.Base
+	// no flags
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent cmd.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("contacts", "Audit and update the billing, legal, security and technical contacts of the customer account")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, _ io.Writer) error {
+	panic("unreachable")
+}
+
+// newClient returns a client of the customer API endpoints.
+func newClient(g *global.Data) (customer.Client, error) {
+	token, s := g.Token()
+	if s == lookup.SourceUndefined {
+		return customer.Client{}, fsterr.ErrNoToken
+	}
+	endpoint, _ := g.Endpoint()
+	return customer.Client{
+		Endpoint:   endpoint,
+		Token:      token,
+		HTTPClient: g.HTTPClient,
+	}, nil
+}
+
+// getCustomer returns the customer identified by the --customer-id flag, or
+// else the customer of the authenticated user.
+func getCustomer(client customer.Client, customerID *cmd.OptionalCustomerID) (*customer.Customer, error) {
+	if customerID.Parse() == nil {
+		return client.Get(customerID.Value)
+	}
+	return client.GetCurrent()
+}
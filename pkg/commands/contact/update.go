@@ -0,0 +1,149 @@
+package contact
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api/customer"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewUpdateCommand returns a usable command registered under the parent.
+func NewUpdateCommand(parent cmd.Registerer, g *global.Data) *UpdateCommand {
+	var c UpdateCommand
+	c.CmdClause = parent.Command("update", "Assign the billing, legal, security or technical contact roles of the customer account to users")
+	c.Globals = g
+	c.CmdClause.Flag(customer.RoleBilling, "ID or login email of the user to assign the billing contact role").StringVar(&c.billing)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagCustomerIDName,
+		Description: customerIDDesc,
+		Dst:         &c.customerID.Value,
+		Action:      c.customerID.Set,
+	})
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag(customer.RoleLegal, "ID or login email of the user to assign the legal contact role").StringVar(&c.legal)
+	c.CmdClause.Flag(customer.RoleSecurity, "ID or login email of the user to assign the security contact role").StringVar(&c.security)
+	c.CmdClause.Flag(customer.RoleTechnical, "ID or login email of the user to assign the technical contact role").StringVar(&c.technical)
+	return &c
+}
+
+// UpdateCommand calls the Fastly API to assign the contact roles of a
+// customer.
+type UpdateCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	billing    string
+	customerID cmd.OptionalCustomerID
+	legal      string
+	security   string
+	technical  string
+}
+
+// Exec invokes the application logic for the command.
+func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	assign := make(map[string]string)
+	for role, user := range map[string]string{
+		customer.RoleBilling:   c.billing,
+		customer.RoleLegal:     c.legal,
+		customer.RoleSecurity:  c.security,
+		customer.RoleTechnical: c.technical,
+	} {
+		if user != "" {
+			assign[role] = user
+		}
+	}
+	if len(assign) == 0 {
+		return fsterr.RemediationError{
+			Inner:       errors.New("error parsing arguments: no contact roles to assign"),
+			Remediation: "Provide at least one of --billing, --legal, --security or --technical, e.g. --security security-team@example.com.",
+		}
+	}
+
+	client, err := newClient(c.Globals)
+	if err != nil {
+		return err
+	}
+	cust, err := getCustomer(client, &c.customerID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": c.customerID.Value,
+		})
+		return err
+	}
+
+	if err := c.resolveLogins(cust.ID, assign); err != nil {
+		return err
+	}
+
+	r, err := client.UpdateContacts(cust.ID, assign)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Customer ID": cust.ID,
+			"Contacts":    assign,
+		})
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, r); ok {
+		return err
+	}
+
+	var roles []string
+	for _, role := range customer.Roles {
+		if _, ok := assign[role]; ok {
+			roles = append(roles, fmt.Sprintf("%s (user %s)", role, r.ContactID(role)))
+		}
+	}
+	text.Success(out, "Updated the contacts of customer %s: %s", r.ID, strings.Join(roles, ", "))
+	return nil
+}
+
+// resolveLogins replaces the login emails in assign with the IDs of the users
+// of the customer.
+func (c *UpdateCommand) resolveLogins(customerID string, assign map[string]string) error {
+	var users []*fastly.User
+	for role, user := range assign {
+		if !strings.Contains(user, "@") {
+			continue
+		}
+		if users == nil {
+			var err error
+			users, err = c.Globals.APIClient.ListCustomerUsers(&fastly.ListCustomerUsersInput{
+				CustomerID: customerID,
+			})
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Customer ID": customerID,
+				})
+				return err
+			}
+		}
+
+		var id string
+		for _, u := range users {
+			if strings.EqualFold(u.Login, user) {
+				id = u.ID
+				break
+			}
+		}
+		if id == "" {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("error parsing --%s: no user of customer %s has the login '%s'", role, customerID, user),
+				Remediation: "Run `fastly user list` to see the users of the customer account.",
+			}
+		}
+		assign[role] = id
+	}
+	return nil
+}
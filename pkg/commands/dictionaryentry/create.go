@@ -1,21 +1,26 @@
 package dictionaryentry
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/go-fastly/v7/fastly"
 )
 
-// CreateCommand calls the Fastly API to create a dictionary item.
+// CreateCommand calls the Fastly API to create dictionary items.
 type CreateCommand struct {
 	cmd.Base
 	manifest    manifest.Data
 	Input       fastly.CreateDictionaryItemInput
+	keys        []string
 	serviceName cmd.OptionalServiceNameID
+	valueSource cmd.ValueSource
+	values      []string
 }
 
 // NewCreateCommand returns a usable command registered under the parent.
@@ -26,12 +31,11 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		},
 		manifest: m,
 	}
-	c.CmdClause = parent.Command("create", "Create a new item on a Fastly edge dictionary")
+	c.CmdClause = parent.Command("create", "Create new items on a Fastly edge dictionary")
 
 	// required
 	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.Input.DictionaryID)
-	c.CmdClause.Flag("key", "Dictionary item key").Required().StringVar(&c.Input.ItemKey)
-	c.CmdClause.Flag("value", "Dictionary item value").Required().StringVar(&c.Input.ItemValue)
+	c.CmdClause.Flag("key", "Dictionary item key (set multiple times, paired with --value, to create several items in a single batch request)").Required().StringsVar(&c.keys)
 
 	// optional
 	c.RegisterFlag(cmd.StringFlagOpts{
@@ -46,11 +50,37 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.CmdClause.Flag("value", "Dictionary item value (required unless --value-from-env or --value-from-file is set)").StringsVar(&c.values)
+	c.RegisterValueSourceFlags(&c.valueSource)
 	return &c
 }
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if len(c.keys) > 1 {
+		return c.createBatch(out)
+	}
+
+	c.Input.ItemKey = c.keys[0]
+	if len(c.values) > 1 {
+		return errMismatchedValues
+	}
+	// NOTE: An empty value is valid, so only the absence of every flag is an
+	// error.
+	if len(c.values) == 0 && c.valueSource.Env == "" && c.valueSource.File == "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("no value provided"),
+			Remediation: "Use --value, --value-from-env or --value-from-file to specify the item value.",
+		}
+	}
+	if len(c.values) == 1 {
+		c.Input.ItemValue = c.values[0]
+	}
+	if err := c.valueSource.Resolve(&c.Input.ItemValue); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -73,3 +103,45 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	return nil
 }
+
+// createBatch creates several items using the batch API, rather than making a
+// request per item.
+func (c *CreateCommand) createBatch(out io.Writer) error {
+	if len(c.values) != len(c.keys) {
+		return errMismatchedValues
+	}
+	if c.valueSource.Env != "" || c.valueSource.File != "" {
+		return errBatchValueSource
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	items := make([]*fastly.BatchDictionaryItem, 0, len(c.keys))
+	for i, key := range c.keys {
+		if key == "" {
+			return fmt.Errorf("an empty value is not allowed for the '--key' flag")
+		}
+		items = append(items, &fastly.BatchDictionaryItem{
+			ItemKey:   key,
+			ItemValue: c.values[i],
+			Operation: fastly.CreateBatchOperation,
+		})
+	}
+
+	if err := batchModify(c.Globals.APIClient, serviceID, c.Input.DictionaryID, items); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.Input.DictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+
+	text.Success(out, "Created %d dictionary items (service %s, dictionary %s)", len(items), serviceID, c.Input.DictionaryID)
+	return nil
+}
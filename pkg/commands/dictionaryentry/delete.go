@@ -10,11 +10,12 @@ import (
 	"github.com/fastly/go-fastly/v7/fastly"
 )
 
-// DeleteCommand calls the Fastly API to delete a service.
+// DeleteCommand calls the Fastly API to delete dictionary items.
 type DeleteCommand struct {
 	cmd.Base
 	manifest    manifest.Data
 	Input       fastly.DeleteDictionaryItemInput
+	keys        []string
 	serviceName cmd.OptionalServiceNameID
 }
 
@@ -26,11 +27,11 @@ func NewDeleteCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *D
 		},
 		manifest: m,
 	}
-	c.CmdClause = parent.Command("delete", "Delete an item from a Fastly edge dictionary")
+	c.CmdClause = parent.Command("delete", "Delete items from a Fastly edge dictionary")
 
 	// required
 	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.Input.DictionaryID)
-	c.CmdClause.Flag("key", "Dictionary item key").Required().StringVar(&c.Input.ItemKey)
+	c.CmdClause.Flag("key", "Dictionary item key (set multiple times to delete several items in a single batch request)").Required().StringsVar(&c.keys)
 
 	// optional
 	c.RegisterFlag(cmd.StringFlagOpts{
@@ -60,6 +61,26 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	c.Input.ServiceID = serviceID
 
+	if len(c.keys) > 1 {
+		items := make([]*fastly.BatchDictionaryItem, 0, len(c.keys))
+		for _, key := range c.keys {
+			items = append(items, &fastly.BatchDictionaryItem{
+				ItemKey:   key,
+				Operation: fastly.DeleteBatchOperation,
+			})
+		}
+		if err := batchModify(c.Globals.APIClient, serviceID, c.Input.DictionaryID, items); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Dictionary ID": c.Input.DictionaryID,
+				"Service ID":    serviceID,
+			})
+			return err
+		}
+		text.Success(out, "Deleted %d dictionary items (service %s, dictionary %s)", len(items), serviceID, c.Input.DictionaryID)
+		return nil
+	}
+
+	c.Input.ItemKey = c.keys[0]
 	err = c.Globals.APIClient.DeleteDictionaryItem(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -3,7 +3,9 @@ package dictionaryentry_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -181,6 +183,7 @@ func TestDictionaryItemsList(t *testing.T) {
 }
 
 func TestDictionaryItemCreate(t *testing.T) {
+	t.Setenv("FASTLY_TEST_DICTIONARY_VALUE", "secret")
 	args := testutil.Args
 	scenarios := []struct {
 		args       []string
@@ -203,6 +206,58 @@ func TestDictionaryItemCreate(t *testing.T) {
 			api:        mock.API{CreateDictionaryItemFn: createDictionaryItemOK},
 			wantOutput: "\nSUCCESS: Created dictionary item foo (service 123, dictionary 456)\n",
 		},
+		{
+			args:      args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo"),
+			api:       mock.API{CreateDictionaryItemFn: createDictionaryItemOK},
+			wantError: "no value provided",
+		},
+		{
+			args: append(args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value"), ""),
+			api: mock.API{CreateDictionaryItemFn: func(i *fastly.CreateDictionaryItemInput) (*fastly.DictionaryItem, error) {
+				if i.ItemValue != "" {
+					return nil, fmt.Errorf("unexpected value: %s", i.ItemValue)
+				}
+				return createDictionaryItemOK(i)
+			}},
+			wantOutput: "\nSUCCESS: Created dictionary item foo (service 123, dictionary 456)\n",
+		},
+		{
+			args:      args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar --value-from-env FASTLY_TEST_DICTIONARY_VALUE"),
+			api:       mock.API{CreateDictionaryItemFn: createDictionaryItemOK},
+			wantError: "multiple item values provided",
+		},
+		{
+			args: args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value-from-env FASTLY_TEST_DICTIONARY_VALUE"),
+			api: mock.API{CreateDictionaryItemFn: func(i *fastly.CreateDictionaryItemInput) (*fastly.DictionaryItem, error) {
+				if i.ItemValue != "secret" {
+					return nil, fmt.Errorf("unexpected value: %s", i.ItemValue)
+				}
+				return createDictionaryItemOK(i)
+			}},
+			wantOutput: "\nSUCCESS: Created dictionary item foo (service 123, dictionary 456)\n",
+		},
+		{
+			args: args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar --key baz --value qux"),
+			api: mock.API{BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				return assertBatchItems(i, fastly.CreateBatchOperation, "foo=bar", "baz=qux")
+			}},
+			wantOutput: "\nSUCCESS: Created 2 dictionary items (service 123, dictionary 456)\n",
+		},
+		{
+			args: append(args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar --key baz --value"), ""),
+			api: mock.API{BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				return assertBatchItems(i, fastly.CreateBatchOperation, "foo=bar", "baz=")
+			}},
+			wantOutput: "\nSUCCESS: Created 2 dictionary items (service 123, dictionary 456)\n",
+		},
+		{
+			args:      args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar --key baz"),
+			wantError: "the number of --key and --value flags differ",
+		},
+		{
+			args:      args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --key baz --value-from-env FASTLY_TEST_DICTIONARY_VALUE"),
+			wantError: "the number of --key and --value flags differ",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -241,6 +296,17 @@ func TestDictionaryItemUpdate(t *testing.T) {
 			api:        mock.API{UpdateDictionaryItemFn: updateDictionaryItemOK},
 			wantOutput: updateDictionaryItemOutput,
 		},
+		{
+			args:     args("dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value-from-file filePath"),
+			fileData: "bar\n",
+			api: mock.API{UpdateDictionaryItemFn: func(i *fastly.UpdateDictionaryItemInput) (*fastly.DictionaryItem, error) {
+				if i.ItemValue != "bar" {
+					return nil, fmt.Errorf("unexpected value: %q", i.ItemValue)
+				}
+				return updateDictionaryItemOK(i)
+			}},
+			wantOutput: updateDictionaryItemOutput,
+		},
 		{
 			args:      args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
 			fileData:  `{invalid": "json"}`,
@@ -266,6 +332,28 @@ func TestDictionaryItemUpdate(t *testing.T) {
 			api:        mock.API{BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsOK},
 			wantOutput: "\nSUCCESS: Made 4 modifications of Dictionary 456 on service 123\n",
 		},
+		{
+			args:     args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
+			fileData: batchModifyInput(fastly.BatchModifyMaximumOperations + 1),
+			api: mock.API{BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				if len(i.Items) > fastly.BatchModifyMaximumOperations {
+					return fmt.Errorf("too many items in a batch: %d", len(i.Items))
+				}
+				return nil
+			}},
+			wantOutput: "\nSUCCESS: Made 1001 modifications of Dictionary 456 on service 123\n",
+		},
+		{
+			args: args("dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar --key baz --value qux"),
+			api: mock.API{BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				return assertBatchItems(i, fastly.UpsertBatchOperation, "foo=bar", "baz=qux")
+			}},
+			wantOutput: "\nSUCCESS: Upserted 2 dictionary items (service 123, dictionary 456)\n",
+		},
+		{
+			args:      args("dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar --value baz"),
+			wantError: "the number of --key and --value flags differ",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -316,6 +404,13 @@ func TestDictionaryItemDelete(t *testing.T) {
 			api:        mock.API{DeleteDictionaryItemFn: deleteDictionaryItemOK},
 			wantOutput: "\nSUCCESS: Deleted dictionary item foo (service 123, dictionary 456)\n",
 		},
+		{
+			args: args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key foo --key baz"),
+			api: mock.API{BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				return assertBatchItems(i, fastly.DeleteBatchOperation, "foo=", "baz=")
+			}},
+			wantOutput: "\nSUCCESS: Deleted 2 dictionary items (service 123, dictionary 456)\n",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -471,6 +566,32 @@ var dictionaryItemBatchModifyInputOK = `
 	]
 }`
 
+// batchModifyInput returns a batch file that upserts n items.
+func batchModifyInput(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"op": "upsert", "item_key": "key-%d", "item_value": "value"}`, i)
+	}
+	return `{"items": [` + strings.Join(items, ",") + `]}`
+}
+
+// assertBatchItems checks that a batch request holds the key=value items, in
+// order, all with the given operation.
+func assertBatchItems(i *fastly.BatchModifyDictionaryItemsInput, op fastly.BatchOperation, want ...string) error {
+	if i.ServiceID != "123" || i.DictionaryID != "456" {
+		return fmt.Errorf("unexpected service or dictionary: %s, %s", i.ServiceID, i.DictionaryID)
+	}
+	if len(i.Items) != len(want) {
+		return fmt.Errorf("unexpected number of items: %d", len(i.Items))
+	}
+	for n, item := range i.Items {
+		if item.Operation != op || item.ItemKey+"="+item.ItemValue != want[n] {
+			return fmt.Errorf("unexpected item: %s %s=%s", item.Operation, item.ItemKey, item.ItemValue)
+		}
+	}
+	return nil
+}
+
 func batchModifyDictionaryItemsOK(i *fastly.BatchModifyDictionaryItemsInput) error {
 	return nil
 }
@@ -480,3 +601,181 @@ func batchModifyDictionaryItemsError(i *fastly.BatchModifyDictionaryItemsInput)
 }
 
 var errTest = errors.New("an expected error occurred")
+
+func TestDictionaryItemSync(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(file, []byte(`{"foo": "local", "new": "value"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := []*fastly.DictionaryItem{
+		{ItemKey: "foo", ItemValue: "hotfix"},
+		{ItemKey: "other", ItemValue: "unmanaged"},
+	}
+	var batches []*fastly.BatchDictionaryItem
+	api := mock.API{
+		NewListDictionaryItemsPaginatorFn: func(i *fastly.ListDictionaryItemsInput) fastly.PaginatorDictionaryItems {
+			return &dictionaryItemsPage{items: remote}
+		},
+		BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+			batches = append(batches, i.Items...)
+			return nil
+		},
+	}
+
+	args := testutil.Args
+	for _, testcase := range []struct {
+		name        string
+		args        []string
+		wantError   string
+		wantOutput  string
+		wantBatches []*fastly.BatchDictionaryItem
+		wantLocal   string
+	}{
+		{
+			name:      "validate missing --file flag",
+			args:      args("dictionary-entry sync --service-id 123 --dictionary-id 456"),
+			wantError: "error parsing arguments: required flag --file not provided",
+		},
+		{
+			name:      "validate conflict isn't overwritten when prompts are disabled",
+			args:      args("dictionary-entry sync --service-id 123 --dictionary-id 456 --non-interactive --file " + file),
+			wantError: "1 keys changed remotely since the last sync and weren't synced: foo",
+			wantBatches: []*fastly.BatchDictionaryItem{
+				{ItemKey: "new", ItemValue: "value", Operation: fastly.CreateBatchOperation},
+			},
+		},
+		{
+			name:       "validate --prefer remote",
+			args:       args("dictionary-entry sync --service-id 123 --dictionary-id 456 --prefer remote --file " + file),
+			wantOutput: "Synced dictionary 456 with " + file + " (0 created, 0 updated, 0 deleted)",
+			wantLocal:  `"foo": "hotfix"`,
+		},
+		{
+			name:       "validate --delete",
+			args:       args("dictionary-entry sync --service-id 123 --dictionary-id 456 --delete --file " + file),
+			wantOutput: "Synced dictionary 456 with " + file + " (0 created, 0 updated, 1 deleted)",
+			wantBatches: []*fastly.BatchDictionaryItem{
+				{ItemKey: "other", Operation: fastly.DeleteBatchOperation},
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			batches = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantBatches, batches)
+			if testcase.wantLocal != "" {
+				data, err := os.ReadFile(file)
+				testutil.AssertNoError(t, err)
+				testutil.AssertStringContains(t, string(data), testcase.wantLocal)
+			}
+			// NOTE: The created item is now part of the remote dictionary.
+			for _, b := range batches {
+				remote = append(remote, &fastly.DictionaryItem{ItemKey: b.ItemKey, ItemValue: b.ItemValue})
+			}
+		})
+	}
+}
+
+func TestDictionaryItemImportExport(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "items.csv")
+	if err := os.WriteFile(csvFile, []byte("key,value\nfoo,local\nnew,\"a,b\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jsonFile := filepath.Join(dir, "export.json")
+
+	remote := []*fastly.DictionaryItem{
+		{ItemKey: "foo", ItemValue: "remote"},
+		{ItemKey: "old", ItemValue: "value"},
+	}
+	var batches []*fastly.BatchDictionaryItem
+	api := mock.API{
+		NewListDictionaryItemsPaginatorFn: func(i *fastly.ListDictionaryItemsInput) fastly.PaginatorDictionaryItems {
+			return &dictionaryItemsPage{items: remote}
+		},
+		BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+			batches = append(batches, i.Items...)
+			return nil
+		},
+	}
+
+	args := testutil.Args
+	for _, testcase := range []struct {
+		name        string
+		args        []string
+		wantError   string
+		wantOutput  string
+		wantBatches []*fastly.BatchDictionaryItem
+	}{
+		{
+			name:      "validate missing --file flag",
+			args:      args("dictionary-entry import --service-id 123 --dictionary-id 456"),
+			wantError: "error parsing arguments: required flag --file not provided",
+		},
+		{
+			name:       "validate --dry-run",
+			args:       args("dictionary-entry import --service-id 123 --dictionary-id 456 --dry-run --file " + csvFile),
+			wantOutput: "Dry run: 1 items would be created, 1 updated and 1 deleted in dictionary 456",
+		},
+		{
+			name:       "import",
+			args:       args("dictionary-entry import --service-id 123 --dictionary-id 456 --file " + csvFile),
+			wantOutput: "Imported " + csvFile + " into dictionary 456 (1 created, 1 updated, 1 deleted)",
+			wantBatches: []*fastly.BatchDictionaryItem{
+				{ItemKey: "foo", ItemValue: "local", Operation: fastly.UpdateBatchOperation},
+				{ItemKey: "new", ItemValue: "a,b", Operation: fastly.CreateBatchOperation},
+				{ItemKey: "old", Operation: fastly.DeleteBatchOperation},
+			},
+		},
+		{
+			name:       "export as CSV",
+			args:       args("dictionary-entry export --service-id 123 --dictionary-id 456 --format csv"),
+			wantOutput: "key,value\nfoo,remote\nold,value\n",
+		},
+		{
+			name:       "export to a file",
+			args:       args("dictionary-entry export --service-id 123 --dictionary-id 456 --file " + jsonFile),
+			wantOutput: "Exported 2 items of dictionary 456 to " + jsonFile,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			batches = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantBatches, batches)
+		})
+	}
+
+	data, err := os.ReadFile(jsonFile)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "{\n  \"foo\": \"remote\",\n  \"old\": \"value\"\n}\n", string(data))
+}
+
+// dictionaryItemsPage returns the items as a single page.
+type dictionaryItemsPage struct {
+	items []*fastly.DictionaryItem
+	done  bool
+}
+
+func (p *dictionaryItemsPage) HasNext() bool {
+	return !p.done
+}
+
+func (p *dictionaryItemsPage) Remaining() int {
+	return 0
+}
+
+func (p *dictionaryItemsPage) GetNext() ([]*fastly.DictionaryItem, error) {
+	p.done = true
+	return p.items, nil
+}
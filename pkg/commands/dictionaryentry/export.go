@@ -0,0 +1,92 @@
+package dictionaryentry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ExportCommand calls the Fastly API to write the items of a dictionary to a
+// local file.
+type ExportCommand struct {
+	cmd.Base
+
+	dictionaryID string
+	file         string
+	format       string
+	manifest     manifest.Data
+	serviceName  cmd.OptionalServiceNameID
+}
+
+// NewExportCommand returns a usable command registered under the parent.
+func NewExportCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ExportCommand {
+	c := ExportCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("export", "Export the items of a Fastly edge dictionary as CSV or JSON, to restore with `dictionary-entry import`")
+
+	// required
+	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.dictionaryID)
+
+	// optional
+	c.CmdClause.Flag("file", "Path to write the items to (defaults to stdout)").StringVar(&c.file)
+	c.CmdClause.Flag("format", "The format of the items (defaults to the --file extension, or else json)").HintOptions(formats...).EnumVar(&c.format, formats...)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *ExportCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() && c.file != "" {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	items, err := listItems(c.Globals.APIClient, serviceID, c.dictionaryID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.dictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+
+	format := fileFormat(c.file, c.format)
+	if c.file == "" {
+		return writeItems(out, items, format)
+	}
+
+	var buf bytes.Buffer
+	if err := writeItems(&buf, items, format); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.file, buf.Bytes(), 0o600); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error writing %s: %w", c.file, err)
+	}
+	text.Success(out, "Exported %d items of dictionary %s to %s", len(items), c.dictionaryID, c.file)
+	return nil
+}
@@ -0,0 +1,146 @@
+package dictionaryentry
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/kvsync"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ImportCommand calls the Fastly API to replace the items of a dictionary with
+// those of a local file.
+type ImportCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	dictionaryID string
+	dryRun       bool
+	file         string
+	format       string
+	manifest     manifest.Data
+	serviceName  cmd.OptionalServiceNameID
+}
+
+// ImportResult summarises an import.
+type ImportResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Changes []kvsync.Change `json:"changes"`
+	Created int             `json:"created"`
+	Updated int             `json:"updated"`
+	Deleted int             `json:"deleted"`
+}
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ImportCommand {
+	c := ImportCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("import", "Replace the items of a Fastly edge dictionary with those of a local CSV or JSON file")
+
+	// required
+	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.dictionaryID)
+	c.CmdClause.Flag("file", "Path to a CSV file of key,value rows or a JSON object of the dictionary items, e.g. {\"key\": \"value\"}").Required().StringVar(&c.file)
+
+	// optional
+	c.CmdClause.Flag("dry-run", "Display the items that would be added, updated and deleted, without modifying the dictionary").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("format", "The format of the file (defaults to the file extension, or else json)").HintOptions(formats...).EnumVar(&c.format, formats...)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *ImportCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	local, err := readItems(c.file, fileFormat(c.file, c.format))
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"File": c.file,
+		})
+		return err
+	}
+	remote, err := listItems(c.Globals.APIClient, serviceID, c.dictionaryID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.dictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+
+	result := ImportResult{
+		DryRun:  c.dryRun,
+		Changes: kvsync.Replace(local, remote),
+	}
+	for _, ch := range result.Changes {
+		switch ch.Action {
+		case kvsync.Create:
+			result.Created++
+		case kvsync.Update:
+			result.Updated++
+		case kvsync.Delete:
+			result.Deleted++
+		}
+	}
+
+	if !c.dryRun && len(result.Changes) > 0 {
+		if err := applyChanges(c.Globals.APIClient, serviceID, c.dictionaryID, result.Changes); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Dictionary ID": c.dictionaryID,
+				"Service ID":    serviceID,
+				"File":          c.file,
+			})
+			return err
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, result); ok {
+		return err
+	}
+
+	if c.dryRun {
+		for _, ch := range result.Changes {
+			switch ch.Action {
+			case kvsync.Create:
+				text.Output(out, "+ %s: %q", ch.Key, ch.Value)
+			case kvsync.Update:
+				text.Output(out, "~ %s: %q -> %q", ch.Key, remote[ch.Key], ch.Value)
+			case kvsync.Delete:
+				text.Output(out, "- %s", ch.Key)
+			}
+		}
+		text.Info(out, "Dry run: %d items would be created, %d updated and %d deleted in dictionary %s", result.Created, result.Updated, result.Deleted, c.dictionaryID)
+		return nil
+	}
+	text.Success(out, "Imported %s into dictionary %s (%d created, %d updated, %d deleted)", c.file, c.dictionaryID, result.Created, result.Updated, result.Deleted)
+	return nil
+}
@@ -0,0 +1,178 @@
+package dictionaryentry
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/kvsync"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The formats of an import or export file.
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// formats are the values of the --format flag.
+var formats = []string{formatCSV, formatJSON}
+
+// errMismatchedValues means the --key and --value flags weren't paired up.
+var errMismatchedValues = fsterr.RemediationError{
+	Inner:       errors.New("the number of --key and --value flags differ"),
+	Remediation: "Provide a --value for every --key, in the same order.",
+}
+
+// errBatchValueSource means a value source was used with multiple items.
+var errBatchValueSource = fsterr.RemediationError{
+	Inner:       errors.New("--value-from-env and --value-from-file can only be used with a single --key"),
+	Remediation: "Use --value for each item when providing multiple --key flags.",
+}
+
+// csvHeader is the header row of a CSV file of dictionary items.
+var csvHeader = []string{"key", "value"}
+
+// listItems returns every item of the dictionary.
+func listItems(client api.Interface, serviceID, dictionaryID string) (map[string]string, error) {
+	paginator := client.NewListDictionaryItemsPaginator(&fastly.ListDictionaryItemsInput{
+		DictionaryID: dictionaryID,
+		ServiceID:    serviceID,
+	})
+	items := make(map[string]string)
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range data {
+			items[i.ItemKey] = i.ItemValue
+		}
+	}
+	return items, nil
+}
+
+// applyChanges modifies the dictionary items in batches of the maximum size
+// accepted by the API.
+func applyChanges(client api.Interface, serviceID, dictionaryID string, changes []kvsync.Change) error {
+	ops := map[kvsync.Action]fastly.BatchOperation{
+		kvsync.Create: fastly.CreateBatchOperation,
+		kvsync.Update: fastly.UpdateBatchOperation,
+		kvsync.Delete: fastly.DeleteBatchOperation,
+	}
+	items := make([]*fastly.BatchDictionaryItem, 0, len(changes))
+	for _, ch := range changes {
+		items = append(items, &fastly.BatchDictionaryItem{
+			ItemKey:   ch.Key,
+			ItemValue: ch.Value,
+			Operation: ops[ch.Action],
+		})
+	}
+	return batchModify(client, serviceID, dictionaryID, items)
+}
+
+// batchModify sends the batch operations to the dictionary in as few
+// requests as possible (see api.BatchModify).
+func batchModify(client api.Interface, serviceID, dictionaryID string, items []*fastly.BatchDictionaryItem) error {
+	_, err := api.BatchModify(items, func(batch []*fastly.BatchDictionaryItem) error {
+		return client.BatchModifyDictionaryItems(&fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: dictionaryID,
+			Items:        batch,
+			ServiceID:    serviceID,
+		})
+	})
+	return err
+}
+
+// fileFormat returns the format of a file, which is either set explicitly or
+// derived from the file's extension (defaulting to JSON).
+func fileFormat(file, format string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(file), "."+formatCSV) {
+		return formatCSV
+	}
+	return formatJSON
+}
+
+// readItems reads a file of dictionary items, which is either a JSON object of
+// string values or a CSV file of key,value rows (with an optional header row).
+func readItems(file, format string) (map[string]string, error) {
+	if format == formatJSON {
+		return kvsync.ReadLocal(file)
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as we require a user to configure their own environment.
+	/* #nosec */
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = len(csvHeader)
+	items := make(map[string]string)
+	for row := 1; ; row++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("error parsing %s: %w", file, err),
+				Remediation: "The file must be a CSV file of key,value rows.",
+			}
+		}
+		if row == 1 && record[0] == csvHeader[0] && record[1] == csvHeader[1] {
+			continue
+		}
+		if _, ok := items[record[0]]; ok {
+			return nil, fmt.Errorf("error parsing %s: duplicate key '%s' on row %d", file, record[0], row)
+		}
+		items[record[0]] = record[1]
+	}
+	return items, nil
+}
+
+// writeItems writes the dictionary items in the given format, sorted by key so
+// the file diffs cleanly when kept in version control.
+func writeItems(w io.Writer, items map[string]string, format string) error {
+	if format == formatJSON {
+		// NOTE: encoding/json sorts the keys of a map.
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := cw.Write([]string{k, items[k]}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,123 @@
+package dictionaryentry
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/kvsync"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SyncCommand calls the Fastly API to make the items of a dictionary match a
+// local file.
+type SyncCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	delete       bool
+	dictionaryID string
+	file         string
+	manifest     manifest.Data
+	prefer       string
+	serviceName  cmd.OptionalServiceNameID
+}
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *SyncCommand {
+	c := SyncCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("sync", "Sync the items of a Fastly edge dictionary with a local JSON file, prompting before overwriting items changed remotely")
+
+	// required
+	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.dictionaryID)
+	c.CmdClause.Flag("file", "Path to a JSON object of the dictionary items, e.g. {\"key\": \"value\"}").Required().StringVar(&c.file)
+
+	// optional
+	c.CmdClause.Flag("delete", "Delete the remote items that aren't in the file, including those never synced before, so the dictionary exactly matches the file").BoolVar(&c.delete)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("prefer", "Resolve items changed remotely since the last sync without prompting, keeping the local or remote value").HintOptions(kvsync.Preferences...).EnumVar(&c.prefer, kvsync.Preferences...)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *SyncCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	remote, err := listItems(c.Globals.APIClient, serviceID, c.dictionaryID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.dictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+
+	msgs := out
+	if c.JSONOutput.Enabled {
+		msgs = io.Discard
+	}
+	result, err := kvsync.Sync(kvsync.Options{
+		File:   c.file,
+		Target: kvsync.Target(serviceID, c.dictionaryID),
+		Remote: remote,
+		Apply: func(changes []kvsync.Change) error {
+			return applyChanges(c.Globals.APIClient, serviceID, c.dictionaryID, changes)
+		},
+		Prefer:         c.prefer,
+		Delete:         c.delete,
+		NonInteractive: c.Globals.Flags.NonInteractive || c.Globals.Flags.AutoYes || c.JSONOutput.Enabled,
+		In:             in,
+		Out:            msgs,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.dictionaryID,
+			"Service ID":    serviceID,
+			"File":          c.file,
+		})
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, result); ok {
+		return err
+	}
+
+	if len(result.KeptRemote) > 0 {
+		text.Info(out, "Updated %s with the remote values of: %s", c.file, strings.Join(result.KeptRemote, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		text.Warning(out, "Skipped: %s", strings.Join(result.Skipped, ", "))
+	}
+	text.Success(out, "Synced dictionary %s with %s (%d created, %d updated, %d deleted)", c.dictionaryID, c.file, result.Created, result.Updated, result.Deleted)
+	return nil
+}
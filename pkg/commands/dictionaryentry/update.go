@@ -20,8 +20,11 @@ type UpdateCommand struct {
 	Input       fastly.UpdateDictionaryItemInput
 	InputBatch  fastly.BatchModifyDictionaryItemsInput
 	file        cmd.OptionalString
+	keys        []string
 	manifest    manifest.Data
 	serviceName cmd.OptionalServiceNameID
+	valueSource cmd.ValueSource
+	values      []string
 }
 
 // NewUpdateCommand returns a usable command registered under the parent.
@@ -39,7 +42,7 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 
 	// optional
 	c.CmdClause.Flag("file", "Batch update json file").Action(c.file.Set).StringVar(&c.file.Value)
-	c.CmdClause.Flag("key", "Dictionary item key").StringVar(&c.Input.ItemKey)
+	c.CmdClause.Flag("key", "Dictionary item key (set multiple times, paired with --value, to upsert several items in a single batch request)").StringsVar(&c.keys)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
 		Description: cmd.FlagServiceIDDesc,
@@ -52,7 +55,8 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
-	c.CmdClause.Flag("value", "Dictionary item value").StringVar(&c.Input.ItemValue)
+	c.CmdClause.Flag("value", "Dictionary item value").StringsVar(&c.values)
+	c.RegisterValueSourceFlags(&c.valueSource)
 	return &c
 }
 
@@ -79,6 +83,24 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return nil
 	}
 
+	if len(c.keys) > 1 {
+		return c.upsertBatch(out)
+	}
+	if len(c.values) > 1 {
+		return errMismatchedValues
+	}
+	if len(c.keys) == 1 {
+		c.Input.ItemKey = c.keys[0]
+	}
+	if len(c.values) == 1 {
+		c.Input.ItemValue = c.values[0]
+	}
+
+	if err := c.valueSource.Resolve(&c.Input.ItemValue); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	if c.Input.ItemKey == "" || c.Input.ItemValue == "" {
 		return fmt.Errorf("an empty value is not allowed for either the '--key' or '--value' flags")
 	}
@@ -118,7 +140,7 @@ func (c *UpdateCommand) batchModify(out io.Writer) error {
 		return fmt.Errorf("item key not found in file %s", c.file.Value)
 	}
 
-	err = c.Globals.APIClient.BatchModifyDictionaryItems(&c.InputBatch)
+	err = batchModify(c.Globals.APIClient, c.InputBatch.ServiceID, c.InputBatch.DictionaryID, c.InputBatch.Items)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return err
@@ -127,3 +149,37 @@ func (c *UpdateCommand) batchModify(out io.Writer) error {
 	text.Success(out, "Made %d modifications of Dictionary %s on service %s", len(c.InputBatch.Items), c.Input.DictionaryID, c.InputBatch.ServiceID)
 	return nil
 }
+
+// upsertBatch updates or inserts several items using the batch API, rather
+// than making a request per item.
+func (c *UpdateCommand) upsertBatch(out io.Writer) error {
+	if len(c.values) != len(c.keys) {
+		return errMismatchedValues
+	}
+	if c.valueSource.Env != "" || c.valueSource.File != "" {
+		return errBatchValueSource
+	}
+
+	items := make([]*fastly.BatchDictionaryItem, 0, len(c.keys))
+	for i, key := range c.keys {
+		if key == "" || c.values[i] == "" {
+			return fmt.Errorf("an empty value is not allowed for either the '--key' or '--value' flags")
+		}
+		items = append(items, &fastly.BatchDictionaryItem{
+			ItemKey:   key,
+			ItemValue: c.values[i],
+			Operation: fastly.UpsertBatchOperation,
+		})
+	}
+
+	if err := batchModify(c.Globals.APIClient, c.Input.ServiceID, c.Input.DictionaryID, items); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.Input.DictionaryID,
+			"Service ID":    c.Input.ServiceID,
+		})
+		return err
+	}
+
+	text.Success(out, "Upserted %d dictionary items (service %s, dictionary %s)", len(items), c.Input.ServiceID, c.Input.DictionaryID)
+	return nil
+}
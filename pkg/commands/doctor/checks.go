@@ -0,0 +1,360 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/whoami"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/useragent"
+)
+
+// toolchain describes the tools used to build a language.
+type toolchain struct {
+	name string
+	// language is the key of the tools in compute.ToolVersions.
+	language string
+	// tools are the tools that must be installed.
+	tools []string
+	// constraints are the supported versions of each tool (if constrained).
+	constraints map[string]string
+	// remediation is how to install the toolchain.
+	remediation string
+}
+
+// toolchains returns the toolchains of each language, keyed by the language
+// of the fastly.toml manifest.
+func (c *RootCommand) toolchains() map[string]toolchain {
+	cfg := c.Globals.Config.Language
+	js := toolchain{
+		name:        "JavaScript toolchain",
+		language:    "javascript",
+		tools:       []string{"node", "npm"},
+		remediation: "Install Node.js, which includes npm: https://nodejs.org/",
+	}
+	return map[string]toolchain{
+		"rust": {
+			name:        "Rust toolchain",
+			language:    "rust",
+			tools:       []string{"cargo", "rustc"},
+			constraints: map[string]string{"cargo": cfg.Rust.ToolchainConstraint},
+			remediation: "Install Rust using rustup: https://rustup.rs/",
+		},
+		"go": {
+			name:     "Go toolchain",
+			language: "go",
+			tools:    []string{"go", "tinygo"},
+			constraints: map[string]string{
+				"go":     cfg.Go.ToolchainConstraint,
+				"tinygo": cfg.Go.TinyGoConstraint,
+			},
+			remediation: "Install Go (https://go.dev/doc/install) and TinyGo (https://tinygo.org/getting-started/install/)",
+		},
+		"javascript":     js,
+		"assemblyscript": js,
+	}
+}
+
+// toolchainChecks checks the toolchain of the project's language or, outside
+// of a project, every toolchain.
+//
+// NOTE: Outside of a project any toolchain could go unused, so a missing tool
+// is only a warning.
+func (c *RootCommand) toolchainChecks() []Check {
+	toolchains := c.toolchains()
+
+	if language := c.manifest.File.Language; c.manifest.File.Exists() && language != "" {
+		tc, ok := toolchains[language]
+		if !ok {
+			return []Check{{
+				Name:   "Toolchain",
+				Status: CheckSkipped,
+				Detail: fmt.Sprintf("the '%s' language is built by its [scripts.build]", language),
+			}}
+		}
+		return []Check{c.toolchainCheck(tc, true)}
+	}
+
+	var checks []Check
+	for _, language := range []string{"rust", "go", "javascript"} {
+		checks = append(checks, c.toolchainCheck(toolchains[language], false))
+	}
+	return checks
+}
+
+// toolchainCheck checks the tools of the toolchain are installed, and meet
+// the version constraints.
+//
+// NOTE: Like `compute build`, a version that doesn't meet its constraint is
+// only a warning, as the project may still compile successfully.
+func (c *RootCommand) toolchainCheck(tc toolchain, required bool) Check {
+	missingStatus := CheckWarning
+	if required {
+		missingStatus = CheckFailed
+	}
+
+	check := Check{Name: tc.name, Status: CheckOK}
+	versions := compute.ToolVersions(tc.language, "")
+	tools := make([]string, 0, len(versions))
+	for t := range versions {
+		tools = append(tools, t)
+	}
+	sort.Strings(tools)
+
+	var found, missing, unsupported []string
+	for _, t := range tools {
+		found = append(found, fmt.Sprintf("%s %s", t, versions[t]))
+		if constraint := tc.constraints[t]; constraint != "" && !meetsConstraint(versions[t], constraint) {
+			unsupported = append(unsupported, fmt.Sprintf("%s %s doesn't meet the constraint '%s'", t, versions[t], constraint))
+		}
+	}
+	for _, t := range tc.tools {
+		if _, ok := versions[t]; !ok {
+			missing = append(missing, t)
+		}
+	}
+
+	var details []string
+	if len(found) > 0 {
+		details = append(details, strings.Join(found, ", "))
+	}
+	switch {
+	case len(missing) > 0:
+		check.Status = missingStatus
+		details = append(details, fmt.Sprintf("%s not found", strings.Join(missing, ", ")))
+		check.Remediation = tc.remediation
+	case len(unsupported) > 0:
+		check.Status = CheckWarning
+		details = append(details, unsupported...)
+		check.Remediation = fmt.Sprintf("Install a supported version of the %s.", strings.ToLower(tc.name))
+	}
+
+	if tc.language == "rust" && len(missing) == 0 {
+		target := c.Globals.Config.Language.Rust.WasmWasiTarget
+		ok, err := rustTargetInstalled(target)
+		switch {
+		case err != nil:
+			check.Status = worse(check.Status, CheckWarning)
+			details = append(details, fmt.Sprintf("unable to list the installed targets: %s", err))
+			check.Remediation = strings.TrimSpace(check.Remediation + " Install rustup to manage the Rust compilation targets: https://rustup.rs/")
+		case !ok:
+			check.Status = worse(check.Status, missingStatus)
+			details = append(details, fmt.Sprintf("target %s not installed", target))
+			check.Remediation = strings.TrimSpace(fmt.Sprintf("%s Run `rustup target add %s`.", check.Remediation, target))
+		default:
+			details = append(details, fmt.Sprintf("target %s", target))
+		}
+	}
+
+	check.Detail = strings.Join(details, "; ")
+	return check
+}
+
+// meetsConstraint reports whether the version meets the constraint. A version
+// or constraint that can't be parsed isn't reported as unsupported.
+func meetsConstraint(version, constraint string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return true
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	return c.Check(v)
+}
+
+// rustTargetInstalled reports whether the Rust compilation target is
+// installed.
+func rustTargetInstalled(target string) (bool, error) {
+	output, err := exec.Command("rustup", "target", "list", "--installed").Output()
+	if err != nil {
+		return false, err
+	}
+	for _, t := range strings.Fields(string(output)) {
+		if t == target {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// viceroyCheck checks that Viceroy, used by `compute serve` and `compute
+// test`, is installed.
+//
+// NOTE: Those commands install Viceroy when it's missing, so it's only a
+// warning (e.g. for users without network access when serving locally).
+func (c *RootCommand) viceroyCheck() Check {
+	check := Check{Name: "Viceroy"}
+
+	bin := "viceroy"
+	if c.viceroyVersioner != nil {
+		bin = c.viceroyVersioner.BinaryName()
+	}
+	bin = filepath.Join(compute.InstallDir, bin)
+	if usePath := os.Getenv("FASTLY_VICEROY_USE_PATH"); usePath == "1" || strings.EqualFold(usePath, "true") {
+		path, err := exec.LookPath("viceroy")
+		if err != nil {
+			check.Status = CheckFailed
+			check.Detail = "not found in $PATH (FASTLY_VICEROY_USE_PATH is set)"
+			check.Remediation = "Install Viceroy in your $PATH (https://github.com/fastly/Viceroy), or unset FASTLY_VICEROY_USE_PATH for the CLI to install it."
+			return check
+		}
+		bin = path
+	}
+
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as we lookup the binary in a trusted location.
+	// #nosec
+	// nosemgrep
+	output, err := exec.Command(bin, "--version").Output()
+	if err != nil {
+		check.Status = CheckWarning
+		check.Detail = "not installed"
+		check.Remediation = "Run `fastly compute serve`, which installs Viceroy when it's first run."
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = strings.TrimSpace(string(output))
+	return check
+}
+
+// apiChecks checks the API is reachable and the token is valid, using a
+// single request to the API's token verification endpoint.
+func (c *RootCommand) apiChecks() []Check {
+	endpoint, _ := c.Globals.Endpoint()
+	reachability := Check{Name: "API connectivity"}
+	token := Check{Name: "API token"}
+
+	t, source := c.Globals.Token()
+	if source == lookup.SourceUndefined {
+		token.Status = CheckFailed
+		token.Detail = "no token configured"
+		token.Remediation = fsterr.AuthRemediation
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/verify", nil)
+	if err != nil {
+		reachability.Status = CheckFailed
+		reachability.Detail = err.Error()
+		reachability.Remediation = "Check the API endpoint is a valid URL."
+		return []Check{reachability, skipToken(token)}
+	}
+	if t != "" {
+		req.Header.Set("Fastly-Key", t)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", useragent.Name)
+
+	resp, err := c.Globals.HTTPClient.Do(req)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		reachability.Status = CheckFailed
+		reachability.Detail = err.Error()
+		reachability.Remediation = fmt.Sprintf("Check your network connection, any proxy settings (e.g. HTTPS_PROXY) and that the API endpoint %s is correct.", endpoint)
+		return []Check{reachability, skipToken(token)}
+	}
+	defer resp.Body.Close() // #nosec G307
+
+	reachability.Status = CheckOK
+	reachability.Detail = endpoint
+	if token.Status != "" {
+		return []Check{reachability, token}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var v whoami.VerifyResponse
+		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+			token.Status = CheckWarning
+			token.Detail = fmt.Sprintf("unable to decode the API response: %s", err)
+			break
+		}
+		token.Status = CheckOK
+		token.Detail = fmt.Sprintf("%s <%s> (scope: %s)", v.User.Name, v.User.Login, v.Token.Scope)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		token.Status = CheckFailed
+		token.Detail = fmt.Sprintf("rejected by the API: %s", resp.Status)
+		token.Remediation = fsterr.AuthRemediation
+	default:
+		token.Status = CheckWarning
+		token.Detail = fmt.Sprintf("unable to verify: %s", resp.Status)
+	}
+	return []Check{reachability, token}
+}
+
+// skipToken returns the token check, skipped unless it already failed.
+func skipToken(token Check) Check {
+	if token.Status == "" {
+		token.Status = CheckSkipped
+		token.Detail = "the API isn't reachable"
+	}
+	return token
+}
+
+// manifestCheck checks the fastly.toml manifest in the current directory (if
+// any) is valid.
+func (c *RootCommand) manifestCheck() Check {
+	check := Check{Name: "fastly.toml manifest"}
+	remediation := fmt.Sprintf("Check the fastly.toml manifest against the reference: %s", manifest.SpecURL)
+
+	if err := c.manifest.File.ReadError(); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			check.Status = CheckSkipped
+			check.Detail = "no fastly.toml in the current directory"
+			return check
+		}
+		check.Status = CheckFailed
+		check.Detail = err.Error()
+		check.Remediation = remediation
+		if re := fsterr.Deduce(err); re.Remediation != "" {
+			check.Remediation = re.Remediation
+		}
+		return check
+	}
+	if !c.manifest.File.Exists() {
+		check.Status = CheckSkipped
+		check.Detail = "no fastly.toml in the current directory"
+		return check
+	}
+
+	var problems []string
+	if c.manifest.File.Name == "" {
+		problems = append(problems, "missing name")
+	}
+	if c.manifest.File.Language == "" {
+		problems = append(problems, "missing language")
+	}
+	if len(problems) > 0 {
+		check.Status = CheckFailed
+		check.Detail = strings.Join(problems, ", ")
+		check.Remediation = remediation
+		return check
+	}
+
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("%s (%s)", c.manifest.File.Name, c.manifest.File.Language)
+	return check
+}
+
+// worse returns the more severe of two statuses.
+func worse(a, b string) string {
+	severity := map[string]int{CheckSkipped: 0, CheckOK: 1, CheckWarning: 2, CheckFailed: 3}
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
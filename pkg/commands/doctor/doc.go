@@ -0,0 +1,3 @@
+// Package doctor contains commands to diagnose issues with the environment
+// the CLI is run in.
+package doctor
@@ -0,0 +1,169 @@
+package doctor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/doctor"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDoctor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify" || r.Header.Get("Fastly-Key") != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user":{"name":"Jane","login":"jane@example.com"},"token":{"scope":"global"}}`))
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(dir string) { compute.InstallDir = dir }(compute.InstallDir)
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		manifest   string
+		tools      map[string]string
+		wantError  string
+		wantOutput []string
+		wantStatus map[string]string
+	}{
+		{
+			name:     "success",
+			args:     "doctor --token valid --endpoint " + srv.URL,
+			manifest: "manifest_version = 2\nname = \"demo\"\nlanguage = \"rust\"\n",
+			tools: map[string]string{
+				"cargo":   "echo cargo 1.70.0",
+				"rustc":   "echo rustc 1.70.0",
+				"rustup":  "echo wasm32-wasi",
+				"viceroy": "echo viceroy 0.5.0",
+			},
+			wantOutput: []string{
+				"Rust toolchain        ok",
+				"cargo 1.70.0, rustc 1.70.0; target wasm32-wasi",
+				"viceroy 0.5.0",
+				"Jane <jane@example.com> (scope: global)",
+				"demo (rust)",
+				"No problems found",
+			},
+		},
+		{
+			name:     "failures",
+			args:     "doctor --token invalid --endpoint " + srv.URL,
+			manifest: "manifest_version = 2\nname = \"demo\"\nlanguage = \"rust\"\n",
+			tools: map[string]string{
+				"cargo":  "echo cargo 1.40.0",
+				"rustc":  "echo rustc 1.40.0",
+				"rustup": "echo x86_64-unknown-linux-gnu",
+			},
+			wantError: "2 of 5 checks failed",
+			wantOutput: []string{
+				"cargo 1.40.0 doesn't meet the constraint '>= 1.56.1'",
+				"Rust toolchain: Install a supported version of the rust toolchain. Run `rustup target add wasm32-wasi`.",
+				"rejected by the API: 401 Unauthorized",
+				"Viceroy: Run `fastly compute serve`",
+			},
+		},
+		{
+			name: "outside a project",
+			args: "doctor --json --token valid --endpoint " + srv.URL,
+			tools: map[string]string{
+				"go":      "echo go version go1.20.1 linux/amd64",
+				"node":    "echo v18.0.0",
+				"npm":     "echo 9.0.0",
+				"viceroy": "echo viceroy 0.5.0",
+			},
+			wantStatus: map[string]string{
+				"Rust toolchain":       doctor.CheckWarning,
+				"Go toolchain":         doctor.CheckWarning,
+				"JavaScript toolchain": doctor.CheckOK,
+				"Viceroy":              doctor.CheckOK,
+				"API connectivity":     doctor.CheckOK,
+				"API token":            doctor.CheckOK,
+				"fastly.toml manifest": doctor.CheckSkipped,
+			},
+		},
+		{
+			name:      "unreachable API",
+			args:      "doctor --json --token valid --endpoint " + closed.URL,
+			manifest:  "manifest_version = 2\nname = \"demo\"\nlanguage = \"other\"\n",
+			tools:     map[string]string{"viceroy": "echo viceroy 0.5.0"},
+			wantError: "1 of 5 checks failed",
+			wantStatus: map[string]string{
+				"Toolchain":            doctor.CheckSkipped,
+				"Viceroy":              doctor.CheckOK,
+				"API connectivity":     doctor.CheckFailed,
+				"API token":            doctor.CheckSkipped,
+				"fastly.toml manifest": doctor.CheckOK,
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(wd)
+
+			bin := filepath.Join(dir, "bin")
+			if err := os.Mkdir(bin, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, script := range testcase.tools {
+				if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", bin)
+			compute.InstallDir = bin
+
+			if testcase.manifest != "" {
+				if err := os.WriteFile(manifest.Filename, []byte(testcase.manifest), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.ConfigFile.Language = config.Language{
+				Go:   config.Go{ToolchainConstraint: ">= 1.17", TinyGoConstraint: ">= 0.24.0-0"},
+				Rust: config.Rust{ToolchainConstraint: ">= 1.56.1", WasmWasiTarget: "wasm32-wasi"},
+			}
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantStatus != nil {
+				var checks []doctor.Check
+				if err := json.Unmarshal(stdout.Bytes(), &checks); err != nil {
+					t.Fatal(err)
+				}
+				status := make(map[string]string)
+				for _, c := range checks {
+					status[c.Name] = c.Status
+				}
+				testutil.AssertEqual(t, testcase.wantStatus, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The status of a check.
+const (
+	CheckOK      = "ok"
+	CheckWarning = "warning"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// Check is the outcome of a diagnostic check.
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Remediation is how to fix a failed check (or address a warning).
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	manifest         manifest.Data
+	viceroyVersioner github.AssetVersioner
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent cmd.Registerer, g *global.Data, av github.AssetVersioner, m manifest.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.manifest = m
+	c.viceroyVersioner = av
+	c.CmdClause = parent.Command("doctor", "Diagnose common problems with the language toolchains, Viceroy, API token, API connectivity and fastly.toml manifest")
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	checks := c.toolchainChecks()
+	checks = append(checks, c.viceroyCheck())
+	checks = append(checks, c.apiChecks()...)
+	checks = append(checks, c.manifestCheck())
+
+	var failed int
+	for _, check := range checks {
+		if check.Status == CheckFailed {
+			failed++
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, checks); ok {
+		if err != nil {
+			return err
+		}
+		return failedError(failed, len(checks))
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("CHECK", "STATUS", "DETAIL")
+	for _, check := range checks {
+		t.AddLine(check.Name, check.Status, check.Detail)
+	}
+	t.Print()
+
+	for _, check := range checks {
+		if check.Remediation == "" {
+			continue
+		}
+		switch check.Status {
+		case CheckFailed:
+			text.Error(out, "%s: %s", check.Name, check.Remediation)
+		case CheckWarning:
+			text.Warning(out, "%s: %s", check.Name, check.Remediation)
+		}
+	}
+
+	if failed == 0 {
+		text.Success(out, "No problems found")
+	}
+	return failedError(failed, len(checks))
+}
+
+// failedError returns an error if any of the checks failed.
+func failedError(failed, total int) error {
+	if failed == 0 {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%d of %d checks failed", failed, total),
+		Remediation: "Follow the remediation of each failed check, then run `fastly doctor` again.",
+	}
+}
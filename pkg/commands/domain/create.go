@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/errors"
@@ -22,8 +25,10 @@ type CreateCommand struct {
 	// optional
 	autoClone   cmd.OptionalAutoClone
 	comment     cmd.OptionalString
+	file        cmd.OptionalString
 	name        cmd.OptionalString
 	serviceName cmd.OptionalServiceNameID
+	wildcard    bool
 }
 
 // NewCreateCommand returns a usable command registered under the parent.
@@ -50,6 +55,7 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Dst:    &c.autoClone.Value,
 	})
 	c.CmdClause.Flag("comment", "A descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("file", "Path to a file listing multiple domains to create, one per line and optionally followed by a comment (each domain is then validated)").Action(c.file.Set).StringVar(&c.file.Value)
 	c.CmdClause.Flag("name", "Domain name").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
@@ -63,11 +69,31 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
+	c.CmdClause.Flag("wildcard", "Create a wildcard domain (*.<name>) and check it's covered by a TLS certificate or subscription").BoolVar(&c.wildcard)
 	return &c
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.file.WasSet {
+		if c.name.WasSet || c.wildcard {
+			return errFileFlags
+		}
+		return c.createFromFile(out)
+	}
+
+	if c.wildcard {
+		if !c.name.WasSet || c.name.Value == "" {
+			return errors.RemediationError{
+				Inner:       fmt.Errorf("the --wildcard flag requires a domain name"),
+				Remediation: "Provide the domain with --name (e.g. --name example.com --wildcard creates *.example.com).",
+			}
+		}
+		if !strings.HasPrefix(c.name.Value, wildcardPrefix) {
+			c.name.Value = wildcardPrefix + c.name.Value
+		}
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -104,5 +130,183 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	text.Success(out, "Created domain %s (service %s version %d)", d.Name, d.ServiceID, d.ServiceVersion)
+
+	if strings.HasPrefix(d.Name, wildcardPrefix) {
+		if err := c.checkTLSCoverage(d.Name, in, out); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Domain": d.Name,
+			})
+			return err
+		}
+	}
+	return nil
+}
+
+// createFromFile creates the domains listed by --file, and then validates
+// each of them to summarise which are pointed at Fastly.
+func (c *CreateCommand) createFromFile(out io.Writer) error {
+	defs, err := readDomainsFile(c.file.Value)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+	existing := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		existing[strings.ToLower(d.Name)] = true
+	}
+
+	var created int
+	for _, d := range defs {
+		if existing[d.Name] {
+			text.Output(out, "Domain %s already exists", d.Name)
+			continue
+		}
+		input := fastly.CreateDomainInput{
+			Name:           fastly.String(d.Name),
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion.Number,
+		}
+		switch {
+		case d.Comment != "":
+			input.Comment = fastly.String(d.Comment)
+		case c.comment.WasSet:
+			input.Comment = &c.comment.Value
+		}
+		if _, err := c.Globals.APIClient.CreateDomain(&input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Domain":          d.Name,
+				"Service ID":      serviceID,
+				"Service Version": serviceVersion.Number,
+			})
+			return fmt.Errorf("error creating domain %s (%d of %d domains created): %w", d.Name, created, len(defs), err)
+		}
+		created++
+		text.Output(out, "Created domain %s", d.Name)
+	}
+
+	// NOTE: A failed validation doesn't fail the command, as the domains have
+	// been created and the DNS of a new domain is often changed afterwards.
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("DOMAIN", "VALID", "CNAME", "ERROR")
+	var valid int
+	for _, d := range defs {
+		r, err := c.Globals.APIClient.ValidateDomain(&fastly.ValidateDomainInput{
+			Name:           d.Name,
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion.Number,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Domain":          d.Name,
+				"Service ID":      serviceID,
+				"Service Version": serviceVersion.Number,
+			})
+			t.AddLine(d.Name, false, "", err.Error())
+			continue
+		}
+		if r.Valid {
+			valid++
+		}
+		t.AddLine(d.Name, r.Valid, r.CName, "")
+	}
+	t.Print()
+
+	text.Success(out, "Created %d domains (service %s version %d)", created, serviceID, serviceVersion.Number)
+	if valid < len(defs) {
+		text.Warning(out, "%d of %d domains are pointed at Fastly. Point the others at Fastly with a CNAME record, and then run `fastly domain validate --all`.", valid, len(defs))
+		return nil
+	}
+	text.Info(out, "All %d domains are pointed at Fastly", len(defs))
+	return nil
+}
+
+// wildcardPrefix is the prefix of a wildcard domain name.
+const wildcardPrefix = "*."
+
+// checkTLSCoverage checks a wildcard domain is covered by a TLS certificate or
+// subscription, and offers to create a subscription if it isn't.
+//
+// NOTE: Without TLS coverage HTTPS requests to the wildcard domain fail, which
+// otherwise isn't noticed until traffic is sent to it.
+func (c *CreateCommand) checkTLSCoverage(domain string, in io.Reader, out io.Writer) error {
+	subs, err := c.Globals.APIClient.ListTLSSubscriptions(&fastly.ListTLSSubscriptionsInput{
+		FilterTLSDomainsID: domain,
+	})
+	if err != nil {
+		return fmt.Errorf("error listing TLS subscriptions: %w", err)
+	}
+	if len(subs) > 0 {
+		text.Info(out, "The domain %s is covered by TLS subscription '%s' (state: %s)", domain, subs[0].ID, subs[0].State)
+		return nil
+	}
+
+	certs, err := c.Globals.APIClient.ListCustomTLSCertificates(&fastly.ListCustomTLSCertificatesInput{
+		FilterTLSDomainsID: domain,
+	})
+	if err != nil {
+		return fmt.Errorf("error listing TLS certificates: %w", err)
+	}
+	for _, cert := range certs {
+		if cert.NotAfter == nil || cert.NotAfter.After(time.Now()) {
+			text.Info(out, "The domain %s is covered by TLS certificate '%s'", domain, cert.ID)
+			return nil
+		}
+	}
+
+	text.Break(out)
+	text.Warning(out, "The domain %s isn't covered by a TLS certificate or subscription, so HTTPS requests to it will fail.", domain)
+	if c.Globals.Flags.NonInteractive {
+		text.Info(out, "To create a TLS subscription run:\n\n\t$ fastly tls-subscription create --domain '%s'", domain)
+		return nil
+	}
+	if !c.Globals.Flags.AutoYes {
+		text.Break(out)
+		answer, err := text.AskYesNo(out, text.BoldYellow(fmt.Sprintf("Create a TLS subscription for %s: [y/N] ", domain)), in)
+		if err != nil {
+			return err
+		}
+		if !answer {
+			return nil
+		}
+	}
+
+	sub, err := c.Globals.APIClient.CreateTLSSubscription(&fastly.CreateTLSSubscriptionInput{
+		Domains: []*fastly.TLSDomain{{ID: domain}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating TLS subscription: %w", err)
+	}
+	text.Success(out, "Created TLS subscription '%s' for %s", sub.ID, domain)
+	text.Info(out, "Wildcard certificates are validated with a DNS challenge. To see the DNS records to create run:\n\n\t$ fastly tls-subscription describe --id %s --include tls_authorizations", sub.ID)
 	return nil
 }
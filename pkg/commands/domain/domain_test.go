@@ -2,12 +2,18 @@ package domain_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/domain"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
@@ -53,6 +59,253 @@ func TestDomainCreate(t *testing.T) {
 	}
 }
 
+func TestDomainCreateWildcard(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	noSubscriptions := func(_ *fastly.ListTLSSubscriptionsInput) ([]*fastly.TLSSubscription, error) {
+		return nil, nil
+	}
+	createSubscription := func(i *fastly.CreateTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+		if len(i.Domains) != 1 || i.Domains[0].ID != "*.example.com" {
+			return nil, errTest
+		}
+		return &fastly.TLSSubscription{ID: "sub123"}, nil
+	}
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		stdin         string
+		api           mock.API
+		wantError     string
+		wantOutput    []string
+		wantNotOutput []string
+	}{
+		{
+			name:      "missing name",
+			args:      "domain create --service-id 123 --version 3 --wildcard",
+			wantError: "the --wildcard flag requires a domain name",
+		},
+		{
+			name: "covered by a subscription",
+			args: "domain create --service-id 123 --version 3 --name example.com --wildcard",
+			api: mock.API{
+				ListTLSSubscriptionsFn: func(i *fastly.ListTLSSubscriptionsInput) ([]*fastly.TLSSubscription, error) {
+					if i.FilterTLSDomainsID != "*.example.com" {
+						return nil, errTest
+					}
+					return []*fastly.TLSSubscription{{ID: "sub456", State: "issued"}}, nil
+				},
+			},
+			wantOutput:    []string{"Created domain *.example.com", "covered by TLS subscription 'sub456' (state: issued)"},
+			wantNotOutput: []string{"isn't covered"},
+		},
+		{
+			name: "covered by a certificate",
+			args: "domain create --service-id 123 --version 3 --name *.example.com",
+			api: mock.API{
+				ListTLSSubscriptionsFn: noSubscriptions,
+				ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+					return []*fastly.CustomTLSCertificate{{ID: "cert123"}}, nil
+				},
+			},
+			wantOutput: []string{"covered by TLS certificate 'cert123'"},
+		},
+		{
+			name:  "create subscription when prompted",
+			args:  "domain create --service-id 123 --version 3 --name example.com --wildcard",
+			stdin: "y",
+			api: mock.API{
+				ListTLSSubscriptionsFn: noSubscriptions,
+				ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+					return []*fastly.CustomTLSCertificate{{ID: "cert123", NotAfter: &expired}}, nil
+				},
+				CreateTLSSubscriptionFn: createSubscription,
+			},
+			wantOutput: []string{
+				"isn't covered by a TLS certificate or subscription",
+				"Created TLS subscription 'sub123' for *.example.com",
+				"fastly tls-subscription describe --id sub123",
+			},
+		},
+		{
+			name:  "decline prompt",
+			args:  "domain create --service-id 123 --version 3 --name example.com --wildcard",
+			stdin: "n",
+			api: mock.API{
+				ListTLSSubscriptionsFn:      noSubscriptions,
+				ListCustomTLSCertificatesFn: listCertificatesNone,
+			},
+			wantOutput:    []string{"isn't covered"},
+			wantNotOutput: []string{"Created TLS subscription"},
+		},
+		{
+			name: "auto-yes",
+			args: "domain create --service-id 123 --version 3 --name example.com --wildcard --auto-yes",
+			api: mock.API{
+				ListTLSSubscriptionsFn:      noSubscriptions,
+				ListCustomTLSCertificatesFn: listCertificatesNone,
+				CreateTLSSubscriptionFn:     createSubscription,
+			},
+			wantOutput: []string{"Created TLS subscription 'sub123'"},
+		},
+		{
+			name: "non-interactive",
+			args: "domain create --service-id 123 --version 3 --name example.com --wildcard --non-interactive",
+			api: mock.API{
+				ListTLSSubscriptionsFn:      noSubscriptions,
+				ListCustomTLSCertificatesFn: listCertificatesNone,
+			},
+			wantOutput:    []string{"fastly tls-subscription create --domain '*.example.com'"},
+			wantNotOutput: []string{"Created TLS subscription"},
+		},
+		{
+			name: "subscription lookup fails",
+			args: "domain create --service-id 123 --version 3 --name example.com --wildcard",
+			api: mock.API{
+				ListTLSSubscriptionsFn: func(_ *fastly.ListTLSSubscriptionsInput) ([]*fastly.TLSSubscription, error) {
+					return nil, errTest
+				},
+			},
+			wantError: "error listing TLS subscriptions: " + errTest.Error(),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			testcase.api.ListVersionsFn = testutil.ListVersions
+			testcase.api.CreateDomainFn = createDomainOK
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.wantNotOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestDomainCreateFromFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	domains := writeFile("domains.txt", `
+# Production
+www.example.com  Main website
+API.example.com.
+old.example.com
+`)
+	duplicate := writeFile("duplicate.txt", "www.example.com\nwww.example.com\n")
+	empty := writeFile("empty.txt", "# nothing here\n")
+
+	var created []*fastly.CreateDomainInput
+	createDomain := func(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
+		created = append(created, i)
+		return createDomainOK(i)
+	}
+	listDomains := func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return []*fastly.Domain{{Name: "old.example.com"}}, nil
+	}
+	validate := func(i *fastly.ValidateDomainInput) (*fastly.DomainValidationResult, error) {
+		switch i.Name {
+		case "api.example.com":
+			return &fastly.DomainValidationResult{Valid: false, CName: "example.com"}, nil
+		case "old.example.com":
+			return nil, errTest
+		}
+		return &fastly.DomainValidationResult{Valid: true, CName: "t.sni.global.fastly.net"}, nil
+	}
+
+	for _, testcase := range []struct {
+		name        string
+		args        string
+		api         mock.API
+		wantError   string
+		wantOutput  []string
+		wantCreated []string
+	}{
+		{
+			name:      "--file with --name",
+			args:      "domain create --service-id 123 --version 3 --file " + domains + " --name www.test.com",
+			wantError: "--file can't be used with --name or --wildcard",
+		},
+		{
+			name:      "duplicate domain",
+			args:      "domain create --service-id 123 --version 3 --file " + duplicate,
+			wantError: "domain www.example.com is listed more than once",
+		},
+		{
+			name:      "no domains",
+			args:      "domain create --service-id 123 --version 3 --file " + empty,
+			wantError: "no domains listed in",
+		},
+		{
+			name: "create and validate",
+			args: "domain create --service-id 123 --version 1 --autoclone --file " + domains + " --comment bulk",
+			api: mock.API{
+				CloneVersionFn:   testutil.CloneVersionResult(4),
+				CreateDomainFn:   createDomain,
+				ListDomainsFn:    listDomains,
+				ValidateDomainFn: validate,
+			},
+			wantOutput: []string{
+				"Created domain www.example.com",
+				"Created domain api.example.com",
+				"Domain old.example.com already exists",
+				"www.example.com  true   t.sni.global.fastly.net",
+				"api.example.com  false  example.com",
+				"old.example.com  false                           " + errTest.Error(),
+				"Created 2 domains (service 123 version 4)",
+				"1 of 3 domains are pointed at Fastly",
+			},
+			wantCreated: []string{"www.example.com:Main website", "api.example.com:bulk"},
+		},
+		{
+			name: "create error",
+			args: "domain create --service-id 123 --version 3 --file " + domains,
+			api: mock.API{
+				CreateDomainFn: createDomainError,
+				ListDomainsFn:  listDomains,
+			},
+			wantError: "error creating domain www.example.com (0 of 3 domains created)",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			created = nil
+			testcase.api.ListVersionsFn = testutil.ListVersions
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantCreated != nil {
+				var got []string
+				for _, i := range created {
+					got = append(got, fmt.Sprintf("%s:%s", *i.Name, *i.Comment))
+				}
+				testutil.AssertEqual(t, testcase.wantCreated, got)
+			}
+		})
+	}
+}
+
 func TestDomainList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -332,6 +585,159 @@ func TestDomainValidate(t *testing.T) {
 	}
 }
 
+func TestDomainVerify(t *testing.T) {
+	resolver := fakeResolver{
+		cnames: map[string]string{
+			"www.example.com":           "dualstack.j.sni.global.fastly.net.",
+			"shop.example.com":          "shops.other-cdn.com.",
+			"fastly-verify.example.com": "dualstack.j.sni.global.fastly.net.",
+		},
+		addrs: map[string][]string{
+			"www.example.com":           {"151.101.1.57", "2a04:4e42::313"},
+			"example.com":               {"151.101.1.57", "203.0.113.10"},
+			"shop.example.com":          {"198.51.100.7"},
+			"fastly-verify.example.com": {"151.101.65.57"},
+		},
+	}
+	defer func(r domain.Resolver) { domain.DNSResolver = r }(domain.DNSResolver)
+	domain.DNSResolver = resolver
+
+	listDomains := func(names ...string) func(*fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			if i.ServiceVersion != 1 {
+				return nil, errTest
+			}
+			var ds []*fastly.Domain
+			for _, n := range names {
+				ds = append(ds, &fastly.Domain{Name: n})
+			}
+			return ds, nil
+		}
+	}
+	allIPs := func() (fastly.IPAddrs, fastly.IPAddrs, error) {
+		return fastly.IPAddrs{"151.101.0.0/16"}, fastly.IPAddrs{"2a04:4e40::/29"}, nil
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "all domains point at Fastly",
+			Args: args("domain verify --service-id 123"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("www.example.com", "*.example.com"),
+				AllIPsFn:       allIPs,
+			},
+			WantOutputs: []string{
+				"www.example.com  ok      dualstack.j.sni.global.fastly.net  151.101.1.57, 2a04:4e42::313",
+				"*.example.com    ok      dualstack.j.sni.global.fastly.net  151.101.65.57",
+				"All 2 domains point at Fastly (service 123 version 1)",
+			},
+		},
+		{
+			Name: "misconfigured domains",
+			Args: args("domain verify --service-id 123"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("www.example.com", "example.com", "shop.example.com", "new.example.com"),
+				AllIPsFn:       allIPs,
+			},
+			WantError: "3 of 4 domains don't point at Fastly",
+			WantOutputs: []string{
+				"example.com       misconfigured",
+				"shop.example.com  misconfigured  shops.other-cdn.com",
+				"new.example.com   unresolved",
+				"isn't a Fastly hostname",
+				"(203.0.113.10) as well as Fastly's",
+			},
+		},
+		{
+			Name: "single domain",
+			Args: args("domain verify --service-id 123 --name WWW.example.com --version 1"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("www.example.com", "shop.example.com"),
+				AllIPsFn:       allIPs,
+			},
+			WantOutput: "All 1 domains point at Fastly",
+		},
+		{
+			Name: "unknown domain",
+			Args: args("domain verify --service-id 123 --name nope.example.com"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("www.example.com"),
+			},
+			WantError: "the domain nope.example.com isn't on service 123 version 1",
+		},
+		{
+			Name: "json",
+			Args: args("domain verify --service-id 123 --json"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("shop.example.com"),
+				AllIPsFn:       allIPs,
+			},
+			WantOutput: `"domain":"shop.example.com","status":"misconfigured","cname":"shops.other-cdn.com","addresses":["198.51.100.7"],"foreign_addresses":["198.51.100.7"]`,
+		},
+		{
+			Name: "AllIPs error",
+			Args: args("domain verify --service-id 123"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains("www.example.com"),
+				AllIPsFn: func() (fastly.IPAddrs, fastly.IPAddrs, error) {
+					return nil, nil, errTest
+				},
+			},
+			WantError: "error listing Fastly's addresses: " + errTest.Error(),
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// fakeResolver resolves the domains of its maps, and no others.
+type fakeResolver struct {
+	cnames map[string]string
+	addrs  map[string][]string
+}
+
+func (r fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if cname, ok := r.cnames[host]; ok {
+		return cname, nil
+	}
+	if _, ok := r.addrs[host]; ok {
+		return host + ".", nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r.addrs[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
 var errTest = errors.New("fixture error")
 
 func createDomainOK(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
@@ -342,6 +748,10 @@ func createDomainOK(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
 	}, nil
 }
 
+func listCertificatesNone(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+	return nil, nil
+}
+
 func createDomainError(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
 	return nil, errTest
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// domainDef is a domain read from a file, for `domain create --file`.
+type domainDef struct {
+	Name    string
+	Comment string
+}
+
+// readDomainsFile reads the domains of a file, which has one domain per line
+// optionally followed by a comment (e.g. `www.example.com Main website`).
+// Blank lines and lines starting with '#' are ignored.
+func readDomainsFile(path string) ([]domainDef, error) {
+	f, err := os.Open(path) // #nosec G304 (CWE-22)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	defer f.Close() // #nosec G307
+
+	var defs []domainDef
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var d domainDef
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			d.Name, d.Comment = line[:i], strings.TrimSpace(line[i:])
+		} else {
+			d.Name = line
+		}
+		d.Name = strings.ToLower(strings.TrimSuffix(d.Name, "."))
+		if strings.Contains(d.Name, "/") || strings.Contains(d.Name, ":") {
+			return nil, fmt.Errorf("invalid domain on line %d of %s: %s", n, path, d.Name)
+		}
+		if seen[d.Name] {
+			return nil, fmt.Errorf("domain %s is listed more than once in %s", d.Name, path)
+		}
+		seen[d.Name] = true
+		defs = append(defs, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if len(defs) == 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no domains listed in %s", path),
+			Remediation: "List one domain per line, optionally followed by a comment.",
+		}
+	}
+	return defs, nil
+}
+
+// errFileFlags means --file was combined with flags defining a single domain.
+var errFileFlags = fsterr.RemediationError{
+	Inner:       errors.New("--file can't be used with --name or --wildcard"),
+	Remediation: "List every domain in the file, or remove --file to create a single domain.",
+}
@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// Resolver looks up the DNS records of a domain.
+type Resolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// DNSResolver is the resolver used by `domain verify`.
+var DNSResolver Resolver = net.DefaultResolver
+
+// DNSTimeout is the time allowed for each DNS lookup.
+var DNSTimeout = 5 * time.Second
+
+// FastlyCNAMESuffixes are the suffixes of the hostnames that Fastly publishes
+// for domains to be pointed at (e.g. dualstack.j.sni.global.fastly.net).
+var FastlyCNAMESuffixes = []string{".fastly.net", ".fastlylb.net"}
+
+// The DNS statuses of a domain.
+const (
+	DNSStatusOK            = "ok"
+	DNSStatusMisconfigured = "misconfigured"
+	DNSStatusUnresolved    = "unresolved"
+)
+
+// NewVerifyCommand returns a usable command registered under the parent.
+func NewVerifyCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *VerifyCommand {
+	c := VerifyCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("verify", "Resolve the domains of a Fastly service version and check their DNS records point at Fastly")
+
+	// optional
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: cmd.FlagJSONDesc,
+		Dst:         &c.json,
+		Short:       'j',
+	})
+	c.CmdClause.Flag("name", "Verify only this domain of the service version").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: cmd.FlagVersionDesc + " (defaults to the active version)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// VerifyCommand resolves the domains of a service version and checks their
+// CNAME, A and AAAA records against Fastly's hostnames and addresses.
+type VerifyCommand struct {
+	cmd.Base
+
+	json           bool
+	manifest       manifest.Data
+	name           cmd.OptionalString
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// DNSResult is the outcome of verifying the DNS records of a domain.
+type DNSResult struct {
+	Domain    string   `json:"domain"`
+	Status    string   `json:"status"`
+	CNAME     string   `json:"cname,omitempty"`
+	Addresses []string `json:"addresses"`
+	// Foreign is the addresses that aren't Fastly's.
+	Foreign []string `json:"foreign_addresses,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Hint    string   `json:"hint,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *VerifyCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+	if c.name.WasSet {
+		var found []*fastly.Domain
+		for _, d := range domains {
+			if strings.EqualFold(d.Name, c.name.Value) {
+				found = append(found, d)
+			}
+		}
+		if len(found) == 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("the domain %s isn't on service %s version %d", c.name.Value, serviceID, serviceVersion.Number),
+				Remediation: "Run `fastly domain list` to see the domains of the service version.",
+			}
+		}
+		domains = found
+	}
+
+	v4, v6, err := c.Globals.APIClient.AllIPs()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error listing Fastly's addresses: %w", err)
+	}
+	networks := parseNetworks(append(v4, v6...))
+
+	results := make([]DNSResult, 0, len(domains))
+	for _, d := range domains {
+		results = append(results, verifyDomain(d.Name, networks))
+	}
+
+	if c.json {
+		data, err := json.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+		return nil
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("DOMAIN", "STATUS", "CNAME", "ADDRESSES")
+	var failed int
+	for _, r := range results {
+		if r.Status != DNSStatusOK {
+			failed++
+		}
+		t.AddLine(r.Domain, r.Status, r.CNAME, strings.Join(r.Addresses, ", "))
+	}
+	t.Print()
+
+	if failed == 0 {
+		text.Success(out, "All %d domains point at Fastly (service %s version %d)", len(results), serviceID, serviceVersion.Number)
+		return nil
+	}
+	for _, r := range results {
+		if r.Hint != "" {
+			text.Warning(out, "%s: %s", r.Domain, r.Hint)
+		}
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%d of %d domains don't point at Fastly", failed, len(results)),
+		Remediation: "Update the DNS records of the domains, and then run `fastly domain verify` again (DNS changes can take a while to propagate).",
+	}
+}
+
+// verifyDomain resolves the domain and checks whether it points at Fastly,
+// either by a CNAME to a Fastly hostname or by addresses in Fastly's networks.
+//
+// NOTE: A wildcard domain can't be resolved, so a name it covers is resolved
+// in its place.
+func verifyDomain(domain string, networks []*net.IPNet) DNSResult {
+	r := DNSResult{Domain: domain, Addresses: []string{}}
+	host := domain
+	if strings.HasPrefix(host, wildcardPrefix) {
+		host = "fastly-verify." + strings.TrimPrefix(host, wildcardPrefix)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DNSTimeout)
+	defer cancel()
+
+	addrs, err := DNSResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		r.Status = DNSStatusUnresolved
+		if err != nil {
+			r.Error = err.Error()
+		}
+		r.Hint = "doesn't resolve. Create a CNAME record pointing it at a Fastly hostname (e.g. dualstack.j.sni.global.fastly.net), or A/AAAA records with Fastly's anycast addresses for an apex domain."
+		return r
+	}
+	for _, a := range addrs {
+		r.Addresses = append(r.Addresses, a.IP.String())
+		if !inNetworks(a.IP, networks) {
+			r.Foreign = append(r.Foreign, a.IP.String())
+		}
+	}
+
+	// NOTE: A name without a CNAME record is its own canonical name.
+	if cname, err := DNSResolver.LookupCNAME(ctx, host); err == nil {
+		cname = strings.TrimSuffix(strings.ToLower(cname), ".")
+		if cname != host {
+			r.CNAME = cname
+		}
+	}
+
+	switch {
+	case len(r.Foreign) == 0:
+		r.Status = DNSStatusOK
+	case r.CNAME != "" && !isFastlyHostname(r.CNAME):
+		r.Status = DNSStatusMisconfigured
+		r.Hint = fmt.Sprintf("is a CNAME of %s, which isn't a Fastly hostname. Point the CNAME record at Fastly (e.g. dualstack.j.sni.global.fastly.net).", r.CNAME)
+	case len(r.Foreign) < len(r.Addresses):
+		r.Status = DNSStatusMisconfigured
+		r.Hint = fmt.Sprintf("resolves to addresses that aren't Fastly's (%s) as well as Fastly's. Remove the A/AAAA records of other providers.", strings.Join(r.Foreign, ", "))
+	default:
+		r.Status = DNSStatusMisconfigured
+		r.Hint = fmt.Sprintf("resolves to addresses that aren't Fastly's (%s). Replace the A/AAAA records with a CNAME record pointing at a Fastly hostname, or with Fastly's anycast addresses for an apex domain.", strings.Join(r.Foreign, ", "))
+	}
+	return r
+}
+
+// isFastlyHostname reports whether the hostname is one published by Fastly.
+func isFastlyHostname(host string) bool {
+	for _, suffix := range FastlyCNAMESuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetworks parses Fastly's address ranges, ignoring any that are invalid.
+func parseNetworks(cidrs []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			networks = append(networks, n)
+		}
+	}
+	return networks
+}
+
+// inNetworks reports whether the address is in any of the networks.
+func inNetworks(ip net.IP, networks []*net.IPNet) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,6 @@
+// Package env contains commands to inspect and manipulate the environment
+// variables of a Compute@Edge service.
+//
+// The variables are stored in a config store (and, for secrets, a secret
+// store) named after the service, and linked to the service as "env".
+package env
@@ -0,0 +1,215 @@
+package env_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/env"
+	fstfmt "github.com/fastly/cli/pkg/fmt"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// envAPI returns an API whose environment stores for service 123 hold the
+// given config items and secrets.
+func envAPI(items map[string]string, secrets ...string) mock.API {
+	return mock.API{
+		ListConfigStoresFn: func() ([]*fastly.ConfigStore, error) {
+			stores := []*fastly.ConfigStore{{ID: "other", Name: "other"}}
+			if items != nil {
+				stores = append(stores, &fastly.ConfigStore{ID: "cs", Name: env.StoreName("123")})
+			}
+			return stores, nil
+		},
+		ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+			var o []*fastly.ConfigStoreItem
+			for k, v := range items {
+				o = append(o, &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: k, Value: v})
+			}
+			return o, nil
+		},
+		ListSecretStoresFn: func(i *fastly.ListSecretStoresInput) (*fastly.SecretStores, error) {
+			if len(secrets) == 0 {
+				return &fastly.SecretStores{}, nil
+			}
+			return &fastly.SecretStores{Data: []fastly.SecretStore{{ID: "ss", Name: env.StoreName("123")}}}, nil
+		},
+		ListSecretsFn: func(i *fastly.ListSecretsInput) (*fastly.Secrets, error) {
+			var o fastly.Secrets
+			for _, name := range secrets {
+				o.Data = append(o.Data, fastly.Secret{Name: name})
+			}
+			return &o, nil
+		},
+	}
+}
+
+func TestEnvSet(t *testing.T) {
+	created := envAPI(nil)
+	created.CreateConfigStoreFn = func(i *fastly.CreateConfigStoreInput) (*fastly.ConfigStore, error) {
+		if i.Name != env.StoreName("123") {
+			return nil, errors.New("unexpected store name: " + i.Name)
+		}
+		return &fastly.ConfigStore{ID: "cs", Name: i.Name}, nil
+	}
+	created.UpdateConfigStoreItemFn = func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+		if i.StoreID != "cs" || !i.Upsert || i.Key != "API_URL" || i.Value != "https://example.com" {
+			return nil, errors.New("unexpected input")
+		}
+		return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: i.Value}, nil
+	}
+
+	var deleted string
+	moved := envAPI(map[string]string{}, "API_URL")
+	moved.UpdateConfigStoreItemFn = created.UpdateConfigStoreItemFn
+	moved.DeleteSecretFn = func(i *fastly.DeleteSecretInput) error {
+		deleted = i.ID + "/" + i.Name
+		return nil
+	}
+
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing value",
+			Args:      testutil.Args("env set --service-id 123 --key API_URL"),
+			WantError: "no value provided",
+		},
+		{
+			Name:       "creates the store",
+			Args:       testutil.Args("env set --service-id 123 --key API_URL --value https://example.com"),
+			API:        created,
+			WantOutput: "fastly resource-link create --resource-id cs --name env --service-id 123 --version latest --autoclone",
+		},
+		{
+			Name:       "replaces a secret",
+			Args:       testutil.Args("env set --service-id 123 --key API_URL --value https://example.com"),
+			API:        moved,
+			WantOutput: fstfmt.Success("Set environment variable API_URL for service 123"),
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+	testutil.AssertString(t, "ss/API_URL", deleted)
+}
+
+func TestEnvGet(t *testing.T) {
+	api := envAPI(map[string]string{"API_URL": "https://example.com"}, "API_KEY")
+	scenarios := []testutil.TestScenario{
+		{
+			Name:       "config",
+			Args:       testutil.Args("env get --service-id 123 --key API_URL"),
+			API:        api,
+			WantOutput: "https://example.com\n",
+		},
+		{
+			Name:      "secret",
+			Args:      testutil.Args("env get --service-id 123 --key API_KEY"),
+			API:       api,
+			WantError: "API_KEY is a secret environment variable, whose value can't be read",
+		},
+		{
+			Name:      "unset",
+			Args:      testutil.Args("env get --service-id 123 --key NOPE"),
+			API:       api,
+			WantError: "environment variable NOPE isn't set for service 123",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestEnvList(t *testing.T) {
+	api := envAPI(map[string]string{"B": "2", "A": "1"}, "SECRET")
+	scenarios := []testutil.TestScenario{
+		{
+			Args: testutil.Args("env list --service-id 123"),
+			API:  api,
+			WantOutput: strings.TrimSpace(`
+KEY     VALUE
+A       1
+B       2
+SECRET  (secret)
+`) + "\n",
+		},
+		{
+			Args:       testutil.Args("env list --service-id 123 --json"),
+			API:        api,
+			WantOutput: fstfmt.EncodeJSON(env.Vars{Config: map[string]string{"A": "1", "B": "2"}, Secrets: []string{"SECRET"}}),
+		},
+		{
+			Args:       testutil.Args("env list --service-id 123"),
+			API:        envAPI(nil),
+			WantOutput: "KEY  VALUE\n",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
+
+func TestEnvUnset(t *testing.T) {
+	var deleted string
+	api := envAPI(map[string]string{"API_URL": "https://example.com"})
+	api.DeleteConfigStoreItemFn = func(i *fastly.DeleteConfigStoreItemInput) error {
+		deleted = i.StoreID + "/" + i.Key
+		return nil
+	}
+
+	scenarios := []testutil.TestScenario{
+		{
+			Args:       testutil.Args("env unset --service-id 123 --key API_URL"),
+			API:        api,
+			WantOutput: fstfmt.Success("Unset environment variable API_URL for service 123"),
+		},
+		{
+			Args:      testutil.Args("env unset --service-id 123 --key NOPE"),
+			API:       api,
+			WantError: "environment variable NOPE isn't set for service 123",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+	testutil.AssertString(t, "cs/API_URL", deleted)
+}
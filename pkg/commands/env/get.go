@@ -0,0 +1,90 @@
+package env
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+)
+
+// NewGetCommand returns a usable command registered under the parent.
+func NewGetCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *GetCommand {
+	c := GetCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("get", "Print the value of an environment variable of a service")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "key",
+		Short:       'k',
+		Description: "Variable name",
+		Dst:         &c.key,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// GetCommand calls the Fastly API to read an environment variable.
+type GetCommand struct {
+	cmd.Base
+
+	key         string
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+}
+
+// Exec invokes the application logic for the command.
+func (c *GetCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	s, err := loadStores(c.Globals.APIClient, serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	if v, ok := s.items[c.key]; ok {
+		fmt.Fprintln(out, v)
+		return nil
+	}
+	if s.hasSecret(c.key) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("%s is a secret environment variable, whose value can't be read", c.key),
+			Remediation: "Set it again with `fastly env set --secret` to change its value.",
+		}
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("environment variable %s isn't set for service %s", c.key, serviceID),
+		Remediation: "Run `fastly env list` to see the service's environment variables.",
+	}
+}
@@ -0,0 +1,198 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/commands/secretstoreentry"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewSetCommand returns a usable command registered under the parent.
+func NewSetCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *SetCommand {
+	c := SetCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("set", "Set an environment variable of a service, creating its store if needed")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "key",
+		Short:       'k',
+		Description: "Variable name",
+		Dst:         &c.key,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        "secret",
+		Description: "Store the variable in the service's secret store, so its value can't be read back",
+		Dst:         &c.secret,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "value",
+		Description: "Variable value. Required unless --value-from-env or --value-from-file is set",
+		Dst:         &c.value,
+	})
+	c.RegisterValueSourceFlags(&c.valueSource)
+
+	return &c
+}
+
+// SetCommand calls the Fastly API to set an environment variable.
+type SetCommand struct {
+	cmd.Base
+
+	key         string
+	manifest    manifest.Data
+	secret      bool
+	serviceName cmd.OptionalServiceNameID
+	value       string
+	valueSource cmd.ValueSource
+}
+
+// Exec invokes the application logic for the command.
+//
+// NOTE: A variable is either plain or secret, so setting it removes it from
+// the other store.
+func (c *SetCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := c.valueSource.Resolve(&c.value); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if c.value == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("no value provided"),
+			Remediation: "Provide the value via --value, --value-from-env or --value-from-file.",
+		}
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	s, err := loadStores(c.Globals.APIClient, serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	if c.secret {
+		err = c.setSecret(s, serviceID, out)
+	} else {
+		err = c.setConfig(s, serviceID, out)
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Key":        c.key,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	kind := "environment variable"
+	if c.secret {
+		kind = "secret environment variable"
+	}
+	text.Success(out, "Set %s %s for service %s", kind, c.key, serviceID)
+	return nil
+}
+
+// setConfig sets the variable in the config store.
+func (c *SetCommand) setConfig(s *stores, serviceID string, out io.Writer) error {
+	if s.config == nil {
+		cs, err := c.Globals.APIClient.CreateConfigStore(&fastly.CreateConfigStoreInput{Name: StoreName(serviceID)})
+		if err != nil {
+			return fmt.Errorf("error creating config store: %w", err)
+		}
+		s.config = cs
+		linkInfo(out, "config store", cs.ID, serviceID)
+	}
+
+	_, err := c.Globals.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
+		StoreID: s.config.ID,
+		Key:     c.key,
+		Value:   c.value,
+		Upsert:  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.hasSecret(c.key) {
+		return c.Globals.APIClient.DeleteSecret(&fastly.DeleteSecretInput{ID: s.secret.ID, Name: c.key})
+	}
+	return nil
+}
+
+// setSecret sets the variable in the secret store.
+//
+// NOTE: Secrets can't be updated, so an existing secret is replaced.
+func (c *SetCommand) setSecret(s *stores, serviceID string, out io.Writer) error {
+	if s.secret == nil {
+		ss, err := c.Globals.APIClient.CreateSecretStore(&fastly.CreateSecretStoreInput{Name: StoreName(serviceID)})
+		if err != nil {
+			return fmt.Errorf("error creating secret store: %w", err)
+		}
+		s.secret = ss
+		linkInfo(out, "secret store", ss.ID, serviceID)
+	}
+
+	wrapped, clientKey, err := secretstoreentry.EncryptSecret(c.Globals.APIClient, []byte(c.value))
+	if err != nil {
+		return err
+	}
+	if s.hasSecret(c.key) {
+		if err := c.Globals.APIClient.DeleteSecret(&fastly.DeleteSecretInput{ID: s.secret.ID, Name: c.key}); err != nil {
+			return err
+		}
+	}
+	_, err = c.Globals.APIClient.CreateSecret(&fastly.CreateSecretInput{
+		ID:        s.secret.ID,
+		Name:      c.key,
+		Secret:    wrapped,
+		ClientKey: clientKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.items[c.key]; ok {
+		return c.Globals.APIClient.DeleteConfigStoreItem(&fastly.DeleteConfigStoreItemInput{StoreID: s.config.ID, Key: c.key})
+	}
+	return nil
+}
+
+// linkInfo explains how to link a newly created store to the service, which
+// requires a new service version.
+func linkInfo(out io.Writer, kind, storeID, serviceID string) {
+	text.Description(out, fmt.Sprintf("Created the %s of the service's environment variables. Link it to the service so its code can read them", kind), fmt.Sprintf("fastly resource-link create --resource-id %s --name %s --service-id %s --version latest --autoclone", storeID, LinkName, serviceID))
+}
@@ -0,0 +1,121 @@
+package env
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// LinkName is the name the stores are linked to a service with, and so the
+// name the service's code opens them with (e.g. ConfigStore::open("env")).
+const LinkName = "env"
+
+// StoreName returns the name of the config and secret stores holding the
+// environment variables of the service.
+func StoreName(serviceID string) string {
+	return fmt.Sprintf("%s_env", serviceID)
+}
+
+// Vars are the environment variables of a service.
+type Vars struct {
+	// Config maps the names of the plain variables to their values.
+	Config map[string]string `json:"config"`
+	// Secrets are the names of the secret variables, whose values can't be
+	// read back.
+	Secrets []string `json:"secrets"`
+}
+
+// Fetch returns the environment variables of the service. A service without
+// any variables has no stores, which isn't an error.
+func Fetch(client api.Interface, serviceID string) (Vars, error) {
+	s, err := loadStores(client, serviceID)
+	if err != nil {
+		return Vars{}, err
+	}
+	return Vars{Config: s.items, Secrets: s.secrets}, nil
+}
+
+// stores are the config and secret stores of a service's environment
+// variables, either of which may not exist yet.
+type stores struct {
+	config  *fastly.ConfigStore
+	secret  *fastly.SecretStore
+	items   map[string]string
+	secrets []string
+}
+
+// hasSecret reports whether key is a secret variable.
+func (s *stores) hasSecret(key string) bool {
+	for _, name := range s.secrets {
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loadStores looks up the stores of the service's environment variables and
+// reads their keys (and the values of the config store).
+func loadStores(client api.Interface, serviceID string) (*stores, error) {
+	name := StoreName(serviceID)
+	s := &stores{items: make(map[string]string)}
+
+	css, err := client.ListConfigStores()
+	if err != nil {
+		return nil, fmt.Errorf("error listing config stores: %w", err)
+	}
+	for _, cs := range css {
+		if cs.Name == name {
+			s.config = cs
+			break
+		}
+	}
+	if s.config != nil {
+		items, err := client.ListConfigStoreItems(&fastly.ListConfigStoreItemsInput{StoreID: s.config.ID})
+		if err != nil {
+			return nil, fmt.Errorf("error listing config store items: %w", err)
+		}
+		for _, item := range items {
+			s.items[item.Key] = item.Value
+		}
+	}
+
+	var cursor string
+	for s.secret == nil {
+		o, err := client.ListSecretStores(&fastly.ListSecretStoresInput{Cursor: cursor})
+		if err != nil {
+			return nil, fmt.Errorf("error listing secret stores: %w", err)
+		}
+		for i := range o.Data {
+			if o.Data[i].Name == name {
+				s.secret = &o.Data[i]
+				break
+			}
+		}
+		if o.Meta.NextCursor == "" {
+			break
+		}
+		cursor = o.Meta.NextCursor
+	}
+	if s.secret != nil {
+		cursor = ""
+		for {
+			o, err := client.ListSecrets(&fastly.ListSecretsInput{ID: s.secret.ID, Cursor: cursor})
+			if err != nil {
+				return nil, fmt.Errorf("error listing secrets: %w", err)
+			}
+			for _, secret := range o.Data {
+				s.secrets = append(s.secrets, secret.Name)
+			}
+			if o.Meta.NextCursor == "" {
+				break
+			}
+			cursor = o.Meta.NextCursor
+		}
+		sort.Strings(s.secrets)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,100 @@
+package env
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewUnsetCommand returns a usable command registered under the parent.
+func NewUnsetCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *UnsetCommand {
+	c := UnsetCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("unset", "Remove an environment variable of a service")
+
+	// Required.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "key",
+		Short:       'k',
+		Description: "Variable name",
+		Dst:         &c.key,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// UnsetCommand calls the Fastly API to remove an environment variable.
+type UnsetCommand struct {
+	cmd.Base
+
+	key         string
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+}
+
+// Exec invokes the application logic for the command.
+func (c *UnsetCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	s, err := loadStores(c.Globals.APIClient, serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	_, isConfig := s.items[c.key]
+	switch {
+	case s.hasSecret(c.key):
+		err = c.Globals.APIClient.DeleteSecret(&fastly.DeleteSecretInput{ID: s.secret.ID, Name: c.key})
+	case isConfig:
+		err = c.Globals.APIClient.DeleteConfigStoreItem(&fastly.DeleteConfigStoreItemInput{StoreID: s.config.ID, Key: c.key})
+	default:
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("environment variable %s isn't set for service %s", c.key, serviceID),
+			Remediation: "Run `fastly env list` to see the service's environment variables.",
+		}
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Key":        c.key,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	text.Success(out, "Unset environment variable %s for service %s", c.key, serviceID)
+	return nil
+}
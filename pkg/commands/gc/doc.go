@@ -0,0 +1,3 @@
+// Package gc contains a command to find and delete the stale resources of a
+// Fastly account.
+package gc
@@ -0,0 +1,313 @@
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The kinds of stale resources.
+const (
+	KindACL          = "acl"
+	KindCertificate  = "tls-certificate"
+	KindConfigStore  = "config-store"
+	KindDictionary   = "dictionary"
+	KindObjectStore  = "kv-store"
+	KindSecretStore  = "secret-store"
+	KindDraftService = "service"
+)
+
+// certificatesPageSize is the number of TLS certificates listed per request.
+const certificatesPageSize = 100
+
+// Candidate is a stale resource that can be deleted.
+type Candidate struct {
+	Kind           string `json:"kind"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	ServiceID      string `json:"service_id,omitempty"`
+	ServiceVersion int    `json:"service_version,omitempty"`
+	Reason         string `json:"reason"`
+	Deleted        bool   `json:"deleted"`
+	Error          string `json:"error,omitempty"`
+}
+
+// service is a service of the account and its versions.
+type service struct {
+	*fastly.Service
+	versions []*fastly.Version
+}
+
+// latest returns the latest version of the service.
+func (s service) latest() *fastly.Version {
+	var latest *fastly.Version
+	for _, v := range s.versions {
+		if latest == nil || v.Number > latest.Number {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// neverActivated reports whether none of the versions of the service has ever
+// been activated.
+func (s service) neverActivated() bool {
+	if s.ActiveVersion != 0 {
+		return false
+	}
+	for _, v := range s.versions {
+		if v.Active || v.Deployed {
+			return false
+		}
+	}
+	return true
+}
+
+// finder looks up the stale resources of the account.
+type finder struct {
+	client api.Interface
+	now    time.Time
+	// olderThan is how long a never-activated service must have existed for
+	// before it's considered stale.
+	olderThan time.Duration
+}
+
+// services returns every service of the account and its versions.
+func (f finder) services() ([]service, error) {
+	var services []service
+	paginator := f.client.NewListServicesPaginator(&fastly.ListServicesInput{})
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, fmt.Errorf("error listing services: %w", err)
+		}
+		for _, s := range data {
+			versions, err := f.client.ListVersions(&fastly.ListVersionsInput{ServiceID: s.ID})
+			if err != nil {
+				return nil, fmt.Errorf("error listing the versions of service %s: %w", s.ID, err)
+			}
+			services = append(services, service{Service: s, versions: versions})
+		}
+	}
+	return services, nil
+}
+
+// draftServices returns the services that have never been activated and were
+// created before the --older-than cutoff.
+func (f finder) draftServices(services []service) []Candidate {
+	var candidates []Candidate
+	cutoff := f.now.Add(-f.olderThan)
+	for _, s := range services {
+		if !s.neverActivated() || s.CreatedAt == nil || s.CreatedAt.After(cutoff) {
+			continue
+		}
+		candidates = append(candidates, Candidate{
+			Kind:      KindDraftService,
+			ID:        s.ID,
+			Name:      s.Name,
+			ServiceID: s.ID,
+			Reason:    fmt.Sprintf("never activated, created %s", s.CreatedAt.Format(time.RFC3339)),
+		})
+	}
+	return candidates
+}
+
+// unusedVersionResources returns the empty ACLs and dictionaries of the latest
+// version of each activated service, when that version is an inactive draft.
+//
+// NOTE: Only editable drafts are considered, so the cleanup never changes the
+// active (or any locked) version of a service.
+func (f finder) unusedVersionResources(services []service) ([]Candidate, error) {
+	var candidates []Candidate
+	for _, s := range services {
+		if s.neverActivated() {
+			continue
+		}
+		v := s.latest()
+		if v == nil || v.Active || v.Locked {
+			continue
+		}
+
+		acls, err := f.client.ListACLs(&fastly.ListACLsInput{ServiceID: s.ID, ServiceVersion: v.Number})
+		if err != nil {
+			return nil, fmt.Errorf("error listing the ACLs of service %s version %d: %w", s.ID, v.Number, err)
+		}
+		for _, a := range acls {
+			entries, err := f.client.ListACLEntries(&fastly.ListACLEntriesInput{ACLID: a.ID, ServiceID: s.ID, PerPage: 1})
+			if err != nil {
+				return nil, fmt.Errorf("error listing the entries of ACL %s: %w", a.ID, err)
+			}
+			if len(entries) > 0 {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Kind:           KindACL,
+				ID:             a.ID,
+				Name:           a.Name,
+				ServiceID:      s.ID,
+				ServiceVersion: v.Number,
+				Reason:         "no entries, on an inactive draft version",
+			})
+		}
+
+		dictionaries, err := f.client.ListDictionaries(&fastly.ListDictionariesInput{ServiceID: s.ID, ServiceVersion: v.Number})
+		if err != nil {
+			return nil, fmt.Errorf("error listing the dictionaries of service %s version %d: %w", s.ID, v.Number, err)
+		}
+		for _, d := range dictionaries {
+			info, err := f.client.GetDictionaryInfo(&fastly.GetDictionaryInfoInput{ID: d.ID, ServiceID: s.ID, ServiceVersion: v.Number})
+			if err != nil {
+				return nil, fmt.Errorf("error getting the info of dictionary %s: %w", d.ID, err)
+			}
+			if info.ItemCount > 0 {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Kind:           KindDictionary,
+				ID:             d.ID,
+				Name:           d.Name,
+				ServiceID:      s.ID,
+				ServiceVersion: v.Number,
+				Reason:         "no items, on an inactive draft version",
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// storesUncheckable returns why the unlinked stores can't be determined, or an
+// empty string if they can.
+//
+// NOTE: Stores belong to the account, while a token limited to some services
+// can only list those, so a store linked to any other service would appear
+// unlinked.
+func (f finder) storesUncheckable() string {
+	t, err := f.client.GetTokenSelf()
+	if err != nil {
+		return fmt.Sprintf("the services the API token can access couldn't be determined: %s", err)
+	}
+	if len(t.Services) > 0 {
+		return fmt.Sprintf("the API token is limited to %d services, so the stores linked to other services can't be detected", len(t.Services))
+	}
+	return ""
+}
+
+// unlinkedStores returns the config, secret and KV stores that aren't linked
+// to any version of any service.
+//
+// NOTE: Every version is checked, as a store linked to an older version is
+// still needed to roll back to it, and one linked to a draft is about to be
+// used.
+func (f finder) unlinkedStores(services []service) ([]Candidate, error) {
+	linked := make(map[string]bool)
+	for _, s := range services {
+		for _, v := range s.versions {
+			resources, err := f.client.ListResources(&fastly.ListResourcesInput{ServiceID: s.ID, ServiceVersion: v.Number})
+			if err != nil {
+				return nil, fmt.Errorf("error listing the resource links of service %s version %d: %w", s.ID, v.Number, err)
+			}
+			for _, r := range resources {
+				linked[r.ResourceID] = true
+			}
+		}
+	}
+
+	var candidates []Candidate
+	unlinked := func(kind, id, name string) {
+		if !linked[id] {
+			candidates = append(candidates, Candidate{Kind: kind, ID: id, Name: name, Reason: "not linked to any service"})
+		}
+	}
+
+	configStores, err := f.client.ListConfigStores()
+	if err != nil {
+		return nil, fmt.Errorf("error listing config stores: %w", err)
+	}
+	for _, cs := range configStores {
+		unlinked(KindConfigStore, cs.ID, cs.Name)
+	}
+
+	secretInput := &fastly.ListSecretStoresInput{}
+	for {
+		o, err := f.client.ListSecretStores(secretInput)
+		if err != nil {
+			return nil, fmt.Errorf("error listing secret stores: %w", err)
+		}
+		for _, ss := range o.Data {
+			unlinked(KindSecretStore, ss.ID, ss.Name)
+		}
+		if secretInput.Cursor = o.Meta.NextCursor; secretInput.Cursor == "" {
+			break
+		}
+	}
+
+	objectInput := &fastly.ListObjectStoresInput{}
+	for {
+		o, err := f.client.ListObjectStores(objectInput)
+		if err != nil {
+			return nil, fmt.Errorf("error listing kv stores: %w", err)
+		}
+		for _, kv := range o.Data {
+			unlinked(KindObjectStore, kv.ID, kv.Name)
+		}
+		if objectInput.Cursor = o.Meta["next_cursor"]; objectInput.Cursor == "" {
+			break
+		}
+	}
+
+	return candidates, nil
+}
+
+// expiredCertificates returns the custom TLS certificates that have expired.
+func (f finder) expiredCertificates() ([]Candidate, error) {
+	var candidates []Candidate
+	for page := 1; ; page++ {
+		// NOTE: The filter is by date, so certificates expiring later today are
+		// excluded by checking NotAfter.
+		certs, err := f.client.ListCustomTLSCertificates(&fastly.ListCustomTLSCertificatesInput{
+			FilterNotAfter: f.now.UTC().AddDate(0, 0, 1).Format("2006-01-02"),
+			PageNumber:     page,
+			PageSize:       certificatesPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing TLS certificates: %w", err)
+		}
+		for _, c := range certs {
+			if c.NotAfter == nil || c.NotAfter.After(f.now) {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Kind:   KindCertificate,
+				ID:     c.ID,
+				Name:   c.Name,
+				Reason: fmt.Sprintf("expired %s", c.NotAfter.Format(time.RFC3339)),
+			})
+		}
+		if len(certs) < certificatesPageSize {
+			return candidates, nil
+		}
+	}
+}
+
+// remove deletes the stale resource.
+func remove(client api.Interface, c Candidate) error {
+	switch c.Kind {
+	case KindACL:
+		return client.DeleteACL(&fastly.DeleteACLInput{Name: c.Name, ServiceID: c.ServiceID, ServiceVersion: c.ServiceVersion})
+	case KindCertificate:
+		return client.DeleteCustomTLSCertificate(&fastly.DeleteCustomTLSCertificateInput{ID: c.ID})
+	case KindConfigStore:
+		return client.DeleteConfigStore(&fastly.DeleteConfigStoreInput{ID: c.ID})
+	case KindDictionary:
+		return client.DeleteDictionary(&fastly.DeleteDictionaryInput{Name: c.Name, ServiceID: c.ServiceID, ServiceVersion: c.ServiceVersion})
+	case KindObjectStore:
+		return client.DeleteObjectStore(&fastly.DeleteObjectStoreInput{ID: c.ID})
+	case KindSecretStore:
+		return client.DeleteSecretStore(&fastly.DeleteSecretStoreInput{ID: c.ID})
+	case KindDraftService:
+		return client.DeleteService(&fastly.DeleteServiceInput{ID: c.ID})
+	}
+	return fmt.Errorf("unknown resource kind: %s", c.Kind)
+}
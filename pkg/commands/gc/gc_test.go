@@ -0,0 +1,221 @@
+package gc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/gc"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestGC(t *testing.T) {
+	var deleted []string
+	gcAPI := func() mock.API {
+		deleted = nil
+		old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		recent := time.Now()
+		expired := time.Now().Add(-time.Hour)
+		valid := time.Now().Add(24 * time.Hour)
+		return mock.API{
+			NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+				return &servicesPaginator{services: []*fastly.Service{
+					{ID: "draft", Name: "old draft", CreatedAt: &old},
+					{ID: "new", Name: "new draft", CreatedAt: &recent},
+					{ID: "live", Name: "live", ActiveVersion: 1, CreatedAt: &old},
+				}}
+			},
+			ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				if i.ServiceID == "live" {
+					return []*fastly.Version{
+						{ServiceID: i.ServiceID, Number: 1, Deployed: true, Locked: true},
+						{ServiceID: i.ServiceID, Number: 2, Active: true, Deployed: true, Locked: true},
+						{ServiceID: i.ServiceID, Number: 3},
+					}, nil
+				}
+				return []*fastly.Version{{ServiceID: i.ServiceID, Number: 1}}, nil
+			},
+			ListACLsFn: func(i *fastly.ListACLsInput) ([]*fastly.ACL, error) {
+				return []*fastly.ACL{{ID: "acl-empty", Name: "blocklist"}, {ID: "acl-full", Name: "allowlist"}}, nil
+			},
+			ListACLEntriesFn: func(i *fastly.ListACLEntriesInput) ([]*fastly.ACLEntry, error) {
+				if i.ACLID == "acl-full" {
+					return []*fastly.ACLEntry{{IP: "1.2.3.4"}}, nil
+				}
+				return nil, nil
+			},
+			ListDictionariesFn: func(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+				return []*fastly.Dictionary{{ID: "dict-full", Name: "routes"}}, nil
+			},
+			GetDictionaryInfoFn: func(i *fastly.GetDictionaryInfoInput) (*fastly.DictionaryInfo, error) {
+				return &fastly.DictionaryInfo{ItemCount: 3}, nil
+			},
+			ListResourcesFn: func(i *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+				if i.ServiceID == "live" && i.ServiceVersion == 3 {
+					return []*fastly.Resource{{ResourceID: "cs-linked"}}, nil
+				}
+				// A store linked only to an older version is still in use.
+				if i.ServiceID == "live" && i.ServiceVersion == 1 {
+					return []*fastly.Resource{{ResourceID: "cs-rollback"}}, nil
+				}
+				return nil, nil
+			},
+			GetTokenSelfFn: func() (*fastly.Token, error) {
+				return &fastly.Token{}, nil
+			},
+			ListConfigStoresFn: func() ([]*fastly.ConfigStore, error) {
+				return []*fastly.ConfigStore{{ID: "cs-linked", Name: "linked"}, {ID: "cs-rollback", Name: "rollback"}, {ID: "cs-unlinked", Name: "unlinked"}}, nil
+			},
+			ListSecretStoresFn: func(i *fastly.ListSecretStoresInput) (*fastly.SecretStores, error) {
+				return &fastly.SecretStores{}, nil
+			},
+			ListObjectStoresFn: func(i *fastly.ListObjectStoresInput) (*fastly.ListObjectStoresResponse, error) {
+				return &fastly.ListObjectStoresResponse{}, nil
+			},
+			ListCustomTLSCertificatesFn: func(i *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+				return []*fastly.CustomTLSCertificate{
+					{ID: "cert-expired", Name: "expired", NotAfter: &expired},
+					{ID: "cert-valid", Name: "valid", NotAfter: &valid},
+				}, nil
+			},
+			DeleteServiceFn: func(i *fastly.DeleteServiceInput) error {
+				deleted = append(deleted, i.ID)
+				return nil
+			},
+			DeleteACLFn: func(i *fastly.DeleteACLInput) error {
+				deleted = append(deleted, i.Name)
+				return nil
+			},
+			DeleteConfigStoreFn: func(i *fastly.DeleteConfigStoreInput) error {
+				deleted = append(deleted, i.ID)
+				return nil
+			},
+			DeleteCustomTLSCertificateFn: func(i *fastly.DeleteCustomTLSCertificateInput) error {
+				return testutil.Err
+			},
+		}
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		stdin        string
+		limitedToken bool
+		wantDeleted  []string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate --older-than",
+				Args:      args("gc --older-than=-1 --token 123"),
+				WantError: "invalid --older-than: -1",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate report without deleting when prompts are disabled",
+				Args: args("gc --non-interactive --token 123"),
+				WantOutputs: []string{
+					"service          draft",
+					"acl              acl-empty",
+					"config-store     cs-unlinked",
+					"tls-certificate  cert-expired",
+					"Found 4 stale resources. Re-run with --yes to delete them.",
+				},
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate declining the prompt",
+				Args:       args("gc --token 123"),
+				WantOutput: "Found 4 stale resources",
+			},
+			stdin: "n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate --yes",
+				Args:      args("gc --yes --token 123"),
+				WantError: "failed to delete 1 of 4 stale resources",
+				WantOutputs: []string{
+					"Deleted service 'old draft' (draft)",
+					"Deleted acl 'blocklist' (service live version 3)",
+					"Failed to delete tls-certificate 'expired' (cert-expired): test error",
+				},
+			},
+			wantDeleted: []string{"draft", "blocklist", "cs-unlinked"},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate stores aren't deleted with a token limited to some services",
+				Args:      args("gc --yes --token 123"),
+				WantError: "failed to delete 1 of 3 stale resources",
+				WantOutputs: []string{
+					"Unlinked stores weren't looked for, as the API token is limited to 1 services",
+					"Deleted service 'old draft' (draft)",
+				},
+			},
+			limitedToken: true,
+			wantDeleted:  []string{"draft", "blocklist"},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			api := gcAPI()
+			if testcase.limitedToken {
+				api.GetTokenSelfFn = func() (*fastly.Token, error) {
+					return &fastly.Token{Services: []string{"live"}}, nil
+				}
+			}
+			opts.APIClient = mock.APIClient(api)
+			if testcase.stdin != "" {
+				opts.Stdin = bytes.NewBufferString(testcase.stdin)
+			}
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantDeleted, deleted)
+		})
+	}
+
+	// The JSON report lists each stale resource and whether it was deleted.
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(args("gc --json --token 123"), &stdout)
+	opts.APIClient = mock.APIClient(gcAPI())
+	testutil.AssertNoError(t, app.Run(opts))
+	var report gc.Report
+	testutil.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	testutil.AssertEqual(t, 4, len(report.Candidates))
+	testutil.AssertEqual(t, 0, report.Deleted)
+	testutil.AssertEqual(t, gc.KindDraftService, report.Candidates[0].Kind)
+}
+
+// servicesPaginator returns a single page of services.
+type servicesPaginator struct {
+	services []*fastly.Service
+	done     bool
+}
+
+func (p *servicesPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p *servicesPaginator) Remaining() int {
+	return 0
+}
+
+func (p *servicesPaginator) GetNext() ([]*fastly.Service, error) {
+	p.done = true
+	return p.services, nil
+}
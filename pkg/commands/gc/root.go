@@ -0,0 +1,190 @@
+package gc
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Report is the outcome of a cleanup.
+type Report struct {
+	Candidates []Candidate `json:"candidates"`
+	Deleted    int         `json:"deleted"`
+	Failed     int         `json:"failed"`
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	olderThan int
+	yes       bool
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent cmd.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("gc", "Find and delete stale resources: unlinked stores, empty ACLs and dictionaries on draft versions, expired TLS certificates and never-activated services")
+	c.CmdClause.Flag("older-than", "The number of days a never-activated service must have existed for to be deleted").Default("30").IntVar(&c.olderThan)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("yes", "Delete the stale resources without prompting (otherwise they're only reported when prompts are disabled)").BoolVar(&c.yes)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.olderThan < 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --older-than: %d", c.olderThan),
+			Remediation: "Set --older-than to a number of days, e.g. --older-than 30.",
+		}
+	}
+
+	candidates, warnings, err := c.find()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	report := Report{Candidates: candidates, Warnings: warnings}
+	msgs := out
+	if c.JSONOutput.Enabled {
+		msgs = io.Discard
+	}
+	for _, w := range warnings {
+		text.Warning(msgs, "%s", w)
+	}
+	if len(candidates) == 0 {
+		text.Success(msgs, "No stale resources found")
+	} else {
+		c.printCandidates(msgs, candidates)
+	}
+
+	del := c.yes || c.Globals.Flags.AutoYes
+	_, noPrompt := in.(text.NoPromptReader)
+	if len(candidates) > 0 && !del && !noPrompt && !c.JSONOutput.Enabled && !c.Globals.Flags.NonInteractive {
+		text.Break(out)
+		del, err = text.AskYesNo(out, fmt.Sprintf("Delete these %d stale resources? [y/N] ", len(candidates)), in)
+		if err != nil {
+			return err
+		}
+	}
+
+	if del {
+		text.Break(msgs)
+		for i := range report.Candidates {
+			cand := &report.Candidates[i]
+			if err := remove(c.Globals.APIClient, *cand); err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Kind": cand.Kind,
+					"ID":   cand.ID,
+				})
+				cand.Error = err.Error()
+				report.Failed++
+				text.Error(msgs, "Failed to delete %s: %s", describe(*cand), err)
+				continue
+			}
+			cand.Deleted = true
+			report.Deleted++
+			text.Output(msgs, "Deleted %s", describe(*cand))
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, report); ok {
+		if err != nil {
+			return err
+		}
+		return failedError(report)
+	}
+
+	if len(candidates) > 0 {
+		text.Break(out)
+		switch {
+		case !del:
+			text.Info(out, "Found %d stale resources. Re-run with --yes to delete them.", len(candidates))
+		case report.Failed == 0:
+			text.Success(out, "Deleted %d stale resources", report.Deleted)
+		}
+	}
+	return failedError(report)
+}
+
+// find returns the stale resources of the account, and warnings about the
+// resources that couldn't be checked.
+func (c *RootCommand) find() ([]Candidate, []string, error) {
+	f := finder{
+		client:    c.Globals.APIClient,
+		now:       time.Now(),
+		olderThan: time.Duration(c.olderThan) * 24 * time.Hour,
+	}
+	services, err := f.services()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	candidates := f.draftServices(services)
+	resources, err := f.unusedVersionResources(services)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates = append(candidates, resources...)
+	// NOTE: Deleting a store that's in use can't be undone, so the stores
+	// aren't considered unless every service can be checked for links to them.
+	if reason := f.storesUncheckable(); reason != "" {
+		warnings = append(warnings, fmt.Sprintf("Unlinked stores weren't looked for, as %s.", reason))
+	} else {
+		stores, err := f.unlinkedStores(services)
+		if err != nil {
+			return nil, nil, err
+		}
+		candidates = append(candidates, stores...)
+	}
+	certs, err := f.expiredCertificates()
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(candidates, certs...), warnings, nil
+}
+
+// printCandidates displays a table of the stale resources.
+func (c *RootCommand) printCandidates(out io.Writer, candidates []Candidate) {
+	t := text.NewTable(out)
+	t.AddHeader("KIND", "ID", "NAME", "SERVICE", "REASON")
+	for _, cand := range candidates {
+		svc := cand.ServiceID
+		if cand.ServiceVersion != 0 {
+			svc = fmt.Sprintf("%s (version %d)", cand.ServiceID, cand.ServiceVersion)
+		}
+		t.AddLine(cand.Kind, cand.ID, cand.Name, svc, cand.Reason)
+	}
+	t.Print()
+}
+
+// describe formats a stale resource for display.
+func describe(c Candidate) string {
+	if c.ServiceVersion != 0 {
+		return fmt.Sprintf("%s '%s' (service %s version %d)", c.Kind, c.Name, c.ServiceID, c.ServiceVersion)
+	}
+	return fmt.Sprintf("%s '%s' (%s)", c.Kind, c.Name, c.ID)
+}
+
+// failedError returns an error if any of the deletions failed.
+func failedError(r Report) error {
+	if r.Failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to delete %d of %d stale resources", r.Failed, len(r.Candidates))
+}
@@ -24,6 +24,7 @@ type CreateCommand struct {
 	// optional
 	AccountName       cmd.OptionalString
 	AutoClone         cmd.OptionalAutoClone
+	CredentialsFile   cmd.OptionalString
 	Dataset           cmd.OptionalString
 	EndpointName      cmd.OptionalString // Can't shadow cmd.Base method Name().
 	Format            cmd.OptionalString
@@ -61,6 +62,7 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Action: c.AutoClone.Set,
 		Dst:    &c.AutoClone.Value,
 	})
+	common.CredentialsFile(c.CmdClause, &c.CredentialsFile)
 	c.CmdClause.Flag("dataset", "Your BigQuery dataset").Action(c.Dataset.Set).StringVar(&c.Dataset.Value)
 	common.Format(c.CmdClause, &c.Format)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
@@ -136,6 +138,13 @@ func (c *CreateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	// NOTE: The credentials file is read before the service version is
+	// (auto)cloned, so an invalid file doesn't leave an unused version behind.
+	if err := common.ApplyCredentialsFile(c.CredentialsFile, &c.SecretKey, &c.User); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
@@ -25,6 +25,7 @@ type UpdateCommand struct {
 	// optional
 	AccountName       cmd.OptionalString
 	AutoClone         cmd.OptionalAutoClone
+	CredentialsFile   cmd.OptionalString
 	Dataset           cmd.OptionalString
 	Format            cmd.OptionalString
 	FormatVersion     cmd.OptionalInt
@@ -63,6 +64,7 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 		Action: c.AutoClone.Set,
 		Dst:    &c.AutoClone.Value,
 	})
+	common.CredentialsFile(c.CmdClause, &c.CredentialsFile)
 	c.CmdClause.Flag("dataset", "Your BigQuery dataset").Action(c.Dataset.Set).StringVar(&c.Dataset.Value)
 	common.Format(c.CmdClause, &c.Format)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
@@ -139,6 +141,13 @@ func (c *UpdateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	// NOTE: The credentials file is read before the service version is
+	// (auto)cloned, so an invalid file doesn't leave an unused version behind.
+	if err := common.ApplyCredentialsFile(c.CredentialsFile, &c.SecretKey, &c.User); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
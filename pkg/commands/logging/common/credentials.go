@@ -0,0 +1,66 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/kingpin"
+)
+
+// CredentialsFile defines the credentials-file flag.
+func CredentialsFile(cmd *kingpin.CmdClause, c *cmd.OptionalString) {
+	cmd.Flag("credentials-file", "Path to a Google Cloud service account JSON key file. The private_key and client_email fields are used unless --secret-key or --user are set").Action(c.Set).StringVar(&c.Value)
+}
+
+// ServiceAccount represents the fields of a Google Cloud service account JSON
+// key file that are used to configure a logging endpoint.
+type ServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// ReadServiceAccount reads and validates a Google Cloud service account JSON
+// key file.
+func ReadServiceAccount(path string) (ServiceAccount, error) {
+	var sa ServiceAccount
+	data, err := cmd.ReadValueFile(path)
+	if err != nil {
+		return sa, fmt.Errorf("error reading --credentials-file: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), &sa); err != nil {
+		return sa, fmt.Errorf("error parsing --credentials-file: %w", err)
+	}
+	if sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return sa, fmt.Errorf("error parsing --credentials-file: client_email and private_key fields are required")
+	}
+	return sa, nil
+}
+
+// ApplyCredentialsFile populates the secret key and user values from the
+// service account JSON key file referenced by file (if set).
+//
+// NOTE: Values explicitly set via --secret-key or --user take precedence.
+func ApplyCredentialsFile(file cmd.OptionalString, secretKey, user *cmd.OptionalString) error {
+	if !file.WasSet {
+		return nil
+	}
+	sa, err := ReadServiceAccount(file.Value)
+	if err != nil {
+		return err
+	}
+	if !secretKey.WasSet {
+		secretKey.WasSet = true
+		secretKey.Value = sa.PrivateKey
+	}
+	if !user.WasSet {
+		user.WasSet = true
+		user.Value = sa.ClientEmail
+	}
+	return nil
+}
+
+// HECTokenFile defines the hec-token-file flag.
+func HECTokenFile(cmd *kingpin.CmdClause, c *cmd.OptionalString) {
+	cmd.Flag("hec-token-file", "Path to a file containing the Splunk HTTP Event Collector token, so it isn't passed on the command line (alternative to --auth-token)").Action(c.Set).StringVar(&c.Value)
+}
@@ -25,6 +25,7 @@ type CreateCommand struct {
 	// optional
 	AccountName       cmd.OptionalString
 	AutoClone         cmd.OptionalAutoClone
+	CredentialsFile   cmd.OptionalString
 	Bucket            cmd.OptionalString
 	CompressionCodec  cmd.OptionalString
 	EndpointName      cmd.OptionalString // Can't shadow cmd.Base method Name().
@@ -66,6 +67,7 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 		Action: c.AutoClone.Set,
 		Dst:    &c.AutoClone.Value,
 	})
+	common.CredentialsFile(c.CmdClause, &c.CredentialsFile)
 	c.CmdClause.Flag("bucket", "The bucket of the GCS bucket").Action(c.Bucket.Set).StringVar(&c.Bucket.Value)
 	common.CompressionCodec(c.CmdClause, &c.CompressionCodec)
 	common.Format(c.CmdClause, &c.Format)
@@ -158,6 +160,13 @@ func (c *CreateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	// NOTE: The credentials file is read before the service version is
+	// (auto)cloned, so an invalid file doesn't leave an unused version behind.
+	if err := common.ApplyCredentialsFile(c.CredentialsFile, &c.SecretKey, &c.User); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
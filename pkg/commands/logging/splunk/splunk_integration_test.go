@@ -3,6 +3,7 @@ package splunk_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -38,6 +39,28 @@ func TestSplunkCreate(t *testing.T) {
 			},
 			wantError: errTest.Error(),
 		},
+		{
+			args: args("logging splunk create --service-id 123 --version 1 --name log --url example.com --hec-token-file ./testdata/hec-token --autoclone"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				CreateSplunkFn: func(i *fastly.CreateSplunkInput) (*fastly.Splunk, error) {
+					if i.Token == nil || *i.Token != "hec-secret" {
+						return nil, fmt.Errorf("unexpected token: %v", i.Token)
+					}
+					return createSplunkOK(i)
+				},
+			},
+			wantOutput: "Created Splunk logging endpoint log (service 123 version 4)",
+		},
+		{
+			args:      args("logging splunk create --service-id 123 --version 1 --name log --url example.com --hec-token-file ./testdata/hec-token --auth-token tkn --autoclone"),
+			wantError: "--auth-token and --hec-token-file can't be used together",
+		},
+		{
+			args:      args("logging splunk create --service-id 123 --version 1 --name log --url example.com --hec-token-file ./testdata/missing --autoclone"),
+			wantError: "error reading --hec-token-file",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -194,6 +217,20 @@ func TestSplunkUpdate(t *testing.T) {
 			},
 			wantOutput: "Updated Splunk logging endpoint log (service 123 version 4)",
 		},
+		{
+			args: args("logging splunk update --service-id 123 --version 1 --name logs --hec-token-file ./testdata/hec-token --autoclone"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				UpdateSplunkFn: func(i *fastly.UpdateSplunkInput) (*fastly.Splunk, error) {
+					if i.Token == nil || *i.Token != "hec-secret" {
+						return nil, fmt.Errorf("unexpected token: %v", i.Token)
+					}
+					return updateSplunkOK(i)
+				},
+			},
+			wantOutput: "Updated Splunk logging endpoint log (service 123 version 4)",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -28,6 +28,7 @@ type UpdateCommand struct {
 	URL               cmd.OptionalString
 	Format            cmd.OptionalString
 	FormatVersion     cmd.OptionalInt
+	HECTokenFile      cmd.OptionalString
 	ResponseCondition cmd.OptionalString
 	Placement         cmd.OptionalString
 	Token             cmd.OptionalString
@@ -65,6 +66,7 @@ func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *U
 	c.CmdClause.Flag("new-name", "New name of the Splunk logging object").Action(c.NewName.Set).StringVar(&c.NewName.Value)
 	common.Format(c.CmdClause, &c.Format)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
+	common.HECTokenFile(c.CmdClause, &c.HECTokenFile)
 	c.CmdClause.Flag("placement", "	Where in the generated VCL the logging call should be placed, overriding any format_version default. Can be none or waf_debug. This field is not required and has no default value").Action(c.Placement.Set).StringVar(&c.Placement.Value)
 	common.ResponseCondition(c.CmdClause, &c.ResponseCondition)
 	c.RegisterFlag(cmd.StringFlagOpts{
@@ -145,6 +147,13 @@ func (c *UpdateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	// NOTE: The token file is read before the service version is (auto)cloned,
+	// so an invalid file doesn't leave an unused version behind.
+	if err := cmd.ApplyFileFlag(c.HECTokenFile, &c.Token, "hec-token-file", "auth-token"); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
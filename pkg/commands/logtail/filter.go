@@ -0,0 +1,290 @@
+package logtail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// The output formats of the --format flag.
+const (
+	formatPlain  = "plain"
+	formatJSON   = "json"
+	formatLogfmt = "logfmt"
+)
+
+// formats are the values of the --format flag.
+var formats = []string{formatPlain, formatJSON, formatLogfmt}
+
+// The message fields matched by --status and --path, in order of preference.
+var (
+	statusFields = []string{"status", "status_code", "statusCode"}
+	pathFields   = []string{"path", "url", "uri"}
+)
+
+// filter matches logs against the client-side filters.
+//
+// NOTE: --status, --path and --field match the fields of messages logged as a
+// JSON object or in logfmt (key=value) format. Other messages don't match them.
+type filter struct {
+	grep   *regexp.Regexp
+	status []statusRange
+	path   *regexp.Regexp
+	fields map[string]*regexp.Regexp
+}
+
+// statusRange is an inclusive range of status codes, e.g. 500-599 for 5xx.
+type statusRange struct {
+	min, max int
+}
+
+// newFilter validates and compiles the filter flags.
+func newFilter(grep, status, path string, fields []string) (*filter, error) {
+	var (
+		f   filter
+		err error
+	)
+	if grep != "" {
+		if f.grep, err = regexp.Compile(grep); err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --grep regular expression: %w", err),
+				Remediation: "Provide a Go regular expression (https://pkg.go.dev/regexp/syntax), e.g. --grep 'timeout|refused'.",
+			}
+		}
+	}
+	if status != "" {
+		if f.status, err = parseStatus(status); err != nil {
+			return nil, err
+		}
+	}
+	if path != "" {
+		f.path = wildcard(path, true)
+	}
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok || k == "" {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --field '%s'", field),
+				Remediation: "Provide a field as key=value, where the value may contain * wildcards, e.g. --field region=eu-*.",
+			}
+		}
+		if f.fields == nil {
+			f.fields = make(map[string]*regexp.Regexp)
+		}
+		f.fields[k] = wildcard(v, false)
+	}
+	return &f, nil
+}
+
+// parseStatus parses a comma-separated list of status codes or classes, e.g.
+// 404,5xx.
+func parseStatus(s string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, code := range strings.Split(s, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if len(code) == 3 && strings.HasSuffix(code, "xx") && code[0] >= '1' && code[0] <= '5' {
+			class := int(code[0]-'0') * 100
+			ranges = append(ranges, statusRange{class, class + 99})
+			continue
+		}
+		n, err := strconv.Atoi(code)
+		if err != nil || n < 100 || n > 599 {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --status '%s'", code),
+				Remediation: "Provide status codes or classes, separated by commas, e.g. --status 404,5xx.",
+			}
+		}
+		ranges = append(ranges, statusRange{n, n})
+	}
+	return ranges, nil
+}
+
+// wildcard compiles a pattern where * matches any characters. A prefix
+// pattern also matches any value it's a prefix of.
+func wildcard(pattern string, prefix bool) *regexp.Regexp {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if !prefix {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// match indicates if the log matches every filter.
+func (f *filter) match(l Log) bool {
+	if f == nil {
+		return true
+	}
+	if f.grep != nil && !f.grep.MatchString(l.Message) {
+		return false
+	}
+	if f.status == nil && f.path == nil && f.fields == nil {
+		return true
+	}
+
+	fields := parseFields(l.Message)
+	if f.status != nil {
+		code, err := strconv.Atoi(firstField(fields, statusFields))
+		if err != nil || !inRanges(code, f.status) {
+			return false
+		}
+	}
+	if f.path != nil {
+		p := firstField(fields, pathFields)
+		if u, err := url.Parse(p); err == nil && u.Path != "" {
+			p = u.Path
+		}
+		if p == "" || !f.path.MatchString(p) {
+			return false
+		}
+	}
+	for k, re := range f.fields {
+		v, ok := fields[k]
+		if !ok || !re.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// firstField returns the value of the first of the keys set in fields.
+func firstField(fields map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// inRanges indicates if the status code is within any of the ranges.
+func inRanges(code int, ranges []statusRange) bool {
+	for _, r := range ranges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFields returns the top-level fields of a message logged as a JSON
+// object or in logfmt format, rendering non-string values as JSON.
+func parseFields(message string) map[string]string {
+	message = strings.TrimSpace(message)
+	if strings.HasPrefix(message, "{") {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(message), &obj); err == nil {
+			fields := make(map[string]string, len(obj))
+			for k, raw := range obj {
+				var s string
+				if err := json.Unmarshal(raw, &s); err == nil {
+					fields[k] = s
+					continue
+				}
+				fields[k] = string(raw)
+			}
+			return fields
+		}
+	}
+	return parseLogfmt(message)
+}
+
+// errLogfmt indicates a message isn't in logfmt format.
+var errLogfmt = errors.New("not logfmt")
+
+// parseLogfmt returns the key=value pairs of a logfmt message, or nil if the
+// message isn't in logfmt format.
+func parseLogfmt(message string) map[string]string {
+	fields := make(map[string]string)
+	rest := message
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		var (
+			k, v string
+			err  error
+		)
+		k, v, rest, err = nextLogfmtPair(rest)
+		if err != nil {
+			return nil
+		}
+		fields[k] = v
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// nextLogfmtPair parses the key=value pair at the start of s, where the value
+// may be double-quoted.
+func nextLogfmtPair(s string) (key, value, rest string, err error) {
+	eq := strings.IndexByte(s, '=')
+	if eq <= 0 || strings.ContainsAny(s[:eq], " \t\"") {
+		return "", "", "", errLogfmt
+	}
+	key, s = s[:eq], s[eq+1:]
+	if strings.HasPrefix(s, `"`) {
+		end := 1
+		for end < len(s) && (s[end] != '"' || s[end-1] == '\\') {
+			end++
+		}
+		if end == len(s) {
+			return "", "", "", errLogfmt
+		}
+		value, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", "", "", errLogfmt
+		}
+		return key, value, s[end+1:], nil
+	}
+	end := strings.IndexAny(s, " \t")
+	if end < 0 {
+		end = len(s)
+	}
+	return key, s[:end], s[end:], nil
+}
+
+// formatLog renders the log in the --format output format.
+func formatLog(l Log, format string) string {
+	switch format {
+	case formatJSON:
+		entry := struct {
+			Stream       string            `json:"stream"`
+			RequestID    string            `json:"request_id"`
+			SequenceNum  int               `json:"sequence_number"`
+			RequestStart time.Time         `json:"request_start"`
+			Message      string            `json:"message"`
+			Fields       map[string]string `json:"fields,omitempty"`
+		}{l.Stream, l.RequestID, l.SequenceNum, l.RequestStartFromRaw().UTC(), l.Message, parseFields(l.Message)}
+		// NOTE: Marshalling can't fail as every field is a string or number.
+		data, _ := json.Marshal(entry)
+		return string(data)
+	case formatLogfmt:
+		pairs := []string{
+			"time=" + l.RequestStartFromRaw().UTC().Format(time.RFC3339Nano),
+			"stream=" + logfmtValue(l.Stream),
+			"request_id=" + logfmtValue(l.RequestID),
+			"seq=" + strconv.Itoa(l.SequenceNum),
+			"msg=" + logfmtValue(l.Message),
+		}
+		return strings.Join(pairs, " ")
+	}
+	return l.String()
+}
+
+// logfmtValue quotes a logfmt value if required.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\"=\n\r") {
+		return strconv.Quote(v)
+	}
+	return v
+}
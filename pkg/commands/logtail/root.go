@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -35,7 +36,8 @@ type RootCommand struct {
 	cfg         cfg
 	dieCh       chan struct{} // channel to end output/printing
 	doneCh      chan struct{} // channel to signal we've reached the end of the run
-	hClient     *http.Client  // TODO: this will go away when GET is in go-fastly
+	filter      *filter
+	hClient     *http.Client // TODO: this will go away when GET is in go-fastly
 	manifest    manifest.Data
 	serviceName cmd.OptionalServiceNameID
 	token       string // TODO: this will go away when GET is in go-fastly
@@ -64,11 +66,35 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 	c.CmdClause.Flag("sort-buffer", "Duration of sort buffer for received logs").Default("1s").DurationVar(&c.cfg.sortBuffer)
 	c.CmdClause.Flag("search-padding", "Time beyond from/to to consider in searches").Default("2s").DurationVar(&c.cfg.searchPadding)
 	c.CmdClause.Flag("stream", "Output: stdout, stderr, both (default)").StringVar(&c.cfg.stream)
+	c.CmdClause.Flag("grep", "Only show logs whose message matches the regular expression").StringVar(&c.cfg.grep)
+	c.CmdClause.Flag("status", "Only show logs whose status field matches the status codes or classes, e.g. 404,5xx").StringVar(&c.cfg.status)
+	c.CmdClause.Flag("path", "Only show logs whose path (or url) field starts with the path, which may contain * wildcards, e.g. /api/*/users").StringVar(&c.cfg.logPath)
+	c.CmdClause.Flag("field", "Only show logs with a field matching key=value, where the value may contain * wildcards (repeatable)").StringsVar(&c.cfg.fields)
+	c.CmdClause.Flag("format", "Output format: plain (default), json, logfmt").Default(formatPlain).HintOptions(formats...).EnumVar(&c.cfg.format, formats...)
+	c.CmdClause.Flag("out", "Write the logs to a file rather than stdout, rotating it once it reaches --max-size").StringVar(&c.cfg.out)
+	c.CmdClause.Flag("max-size", "The size at which the --out file is rotated, e.g. 512KB, 10MB").Default("10MB").StringVar(&c.cfg.maxSize)
+	c.CmdClause.Flag("max-files", "The number of rotated --out files to keep, as FILE.1 (newest) to FILE.N").Default("5").IntVar(&c.cfg.maxFiles)
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	var err error
+	c.filter, err = newFilter(c.cfg.grep, c.cfg.status, c.cfg.logPath, c.cfg.fields)
+	if err != nil {
+		return err
+	}
+	maxSize, err := parseSize(c.cfg.maxSize)
+	if err != nil {
+		return err
+	}
+	if c.cfg.maxFiles < 1 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --max-files %d", c.cfg.maxFiles),
+			Remediation: "Keep at least one rotated file, e.g. --max-files 5.",
+		}
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -100,11 +126,25 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	// Logs are written to the --out file, while status messages are still
+	// displayed in the terminal.
+	logs := out
+	if c.cfg.out != "" {
+		w, err := newRotatingWriter(c.cfg.out, maxSize, c.cfg.maxFiles)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer w.Close()
+		logs = w
+		text.Info(out, "Writing logs to %s (rotated at %s, keeping %d files)", c.cfg.out, c.cfg.maxSize, c.cfg.maxFiles)
+	}
+
 	sigs := make(chan os.Signal, 2)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	// Start the output loop.
-	go c.outputLoop(out)
+	go c.outputLoop(logs)
 
 	// Start tailing the logs.
 	go c.tail(out)
@@ -432,7 +472,9 @@ func (c *RootCommand) printLogs(out io.Writer, logs []Log) {
 		filtered := filterStream(c.cfg.stream, logs)
 
 		for _, l := range filtered {
-			fmt.Fprintln(out, l.String())
+			if c.filter.match(l) {
+				fmt.Fprintln(out, formatLog(l, c.cfg.format))
+			}
 		}
 	}
 }
@@ -478,6 +520,21 @@ type (
 		// customer wants to consume.
 		// Undefined == both stderr and stdout.
 		stream string
+
+		// grep, status, logPath and fields filter the logs client-side
+		// (see filter).
+		grep    string
+		status  string
+		logPath string
+		fields  []string
+		// format is the output format of each log.
+		format string
+
+		// out is the file the logs are written to, which is rotated once
+		// it reaches maxSize, keeping maxFiles rotated files.
+		out      string
+		maxSize  string
+		maxFiles int
 	}
 
 	// Log defines the message envelope that compute@edge (C@E) wraps the
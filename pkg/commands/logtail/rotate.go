@@ -0,0 +1,134 @@
+package logtail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// sizeUnits are the suffixes accepted by --max-size, largest first.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseSize parses a size such as 512KB or 10MB, where units are multiples of
+// 1024 bytes and a bare number is in bytes.
+func parseSize(s string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(v, u.suffix) {
+			v, mult = strings.TrimSpace(strings.TrimSuffix(v, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --max-size '%s'", s),
+			Remediation: "Provide a positive size in bytes, or with a KB, MB or GB suffix, e.g. --max-size 10MB.",
+		}
+	}
+	return n * mult, nil
+}
+
+// rotatingWriter writes to a file, rotating it once it reaches maxSize so that
+// path.1 is the most recent rotated file and at most maxFiles are kept.
+//
+// NOTE: A single write is never split across files, so that each log line is
+// whole within a file, which means a file may exceed maxSize by a line.
+type rotatingWriter struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	f        *os.File
+	size     int64
+}
+
+// newRotatingWriter opens the file for appending, so an existing file is
+// rotated by the first write that would take it past maxSize.
+func newRotatingWriter(path string, maxSize int64, maxFiles int) (*rotatingWriter, error) {
+	w := &rotatingWriter{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write implements the io.Writer interface.
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file.
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
+
+// open opens the file for appending, recording its current size.
+func (w *rotatingWriter) open() error {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we require a user to provide the path of the output file.
+	// #nosec
+	// nosemgrep
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening --out file: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error reading --out file: %w", err)
+	}
+	w.f, w.size = f, fi.Size()
+	return nil
+}
+
+// rotate shifts each rotated file up by one, dropping the oldest, and moves
+// the current file to path.1 before opening a new one.
+func (w *rotatingWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("error closing --out file: %w", err)
+	}
+	if err := os.Remove(w.rotated(w.maxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing rotated --out file: %w", err)
+	}
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(w.rotated(i), w.rotated(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error rotating --out file: %w", err)
+		}
+	}
+	if err := os.Rename(w.path, w.rotated(1)); err != nil {
+		return fmt.Errorf("error rotating --out file: %w", err)
+	}
+	return w.open()
+}
+
+// rotated returns the path of the nth most recent rotated file.
+func (w *rotatingWriter) rotated(n int) string {
+	return fmt.Sprintf("%s.%d", w.path, n)
+}
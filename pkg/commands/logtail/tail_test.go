@@ -3,6 +3,7 @@ package logtail
 import (
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -339,3 +340,186 @@ func TestHighSequence(t *testing.T) {
 		}
 	}
 }
+
+// TestFilter validates the logs matched by the client-side filters.
+func TestFilter(t *testing.T) {
+	logs := []Log{
+		{RequestID: "plain", Message: "connection refused by origin"},
+		{RequestID: "json-500", Message: `{"status":500,"path":"/api/v1/users?id=1","region":"eu-west"}`},
+		{RequestID: "json-404", Message: `{"status_code":"404","url":"https://example.com/static/app.js"}`},
+		{RequestID: "logfmt-503", Message: `status=503 path=/api/v2/orders msg="upstream timeout" region=us-east`},
+	}
+
+	for _, test := range []struct {
+		name   string
+		grep   string
+		status string
+		path   string
+		fields []string
+		want   []string
+	}{
+		{
+			name: "no filters",
+			want: []string{"plain", "json-500", "json-404", "logfmt-503"},
+		},
+		{
+			name: "grep",
+			grep: "refused|timeout",
+			want: []string{"plain", "logfmt-503"},
+		},
+		{
+			name:   "status class",
+			status: "5xx",
+			want:   []string{"json-500", "logfmt-503"},
+		},
+		{
+			name:   "status codes",
+			status: "404, 503",
+			want:   []string{"json-404", "logfmt-503"},
+		},
+		{
+			name: "path prefix",
+			path: "/api/",
+			want: []string{"json-500", "logfmt-503"},
+		},
+		{
+			name: "path wildcard",
+			path: "/api/*/users",
+			want: []string{"json-500"},
+		},
+		{
+			name: "path of url",
+			path: "/static",
+			want: []string{"json-404"},
+		},
+		{
+			name:   "fields",
+			fields: []string{"region=*-east"},
+			want:   []string{"logfmt-503"},
+		},
+		{
+			name:   "combined",
+			status: "5xx",
+			fields: []string{"region=eu-*"},
+			want:   []string{"json-500"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := newFilter(test.grep, test.status, test.path, test.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, l := range logs {
+				if f.match(l) {
+					got = append(got, l.RequestID)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Fatalf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, invalid := range []struct {
+		grep, status string
+		fields       []string
+	}{
+		{grep: "("},
+		{status: "6xx"},
+		{status: "ok"},
+		{fields: []string{"region"}},
+	} {
+		if _, err := newFilter(invalid.grep, invalid.status, "", invalid.fields); err == nil {
+			t.Errorf("expected an error for %+v", invalid)
+		}
+	}
+}
+
+// TestFormatLog validates the output formats.
+func TestFormatLog(t *testing.T) {
+	l := Log{
+		SequenceNum:  2,
+		RequestStart: 1601480668000000,
+		Stream:       "stdout",
+		RequestID:    "abc",
+		Message:      `status=200 msg="all good"`,
+	}
+
+	for _, test := range []struct {
+		format string
+		want   string
+	}{
+		{formatPlain, "stdout |      abc | status=200 msg=\"all good\""},
+		{formatJSON, `{"stream":"stdout","request_id":"abc","sequence_number":2,"request_start":"2020-09-30T15:44:28Z","message":"status=200 msg=\"all good\"","fields":{"msg":"all good","status":"200"}}`},
+		{formatLogfmt, `time=2020-09-30T15:44:28Z stream=stdout request_id=abc seq=2 msg="status=200 msg=\"all good\""`},
+	} {
+		if got := formatLog(l, test.format); got != test.want {
+			t.Errorf("format %s: want %s, got %s", test.format, test.want, got)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "100", want: 100},
+		{in: "100B", want: 100},
+		{in: "512KB", want: 512 << 10},
+		{in: "10mb", want: 10 << 20},
+		{in: "1 GB", want: 1 << 30},
+		{in: "0", wantErr: true},
+		{in: "-1MB", wantErr: true},
+		{in: "10TB", wantErr: true},
+	} {
+		got, err := parseSize(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: want error %t, got %v", test.in, test.wantErr, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: want %d, got %d", test.in, test.want, got)
+		}
+	}
+}
+
+func TestRotatingWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tail.log")
+	if err := os.WriteFile(path, []byte("existing\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newRotatingWriter(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each write rotates the file, as two lines exceed the maximum size, and
+	// only the two most recent rotated files are kept.
+	for name, want := range map[string]string{
+		path:        "line 4\n",
+		path + ".1": "line 3\n",
+		path + ".2": "line 2\n",
+	} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: want %q, got %q", name, want, got)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("want %s.3 to be removed, got %v", path, err)
+	}
+}
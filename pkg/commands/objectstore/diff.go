@@ -0,0 +1,132 @@
+package objectstore
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/commands/objectstoreentry"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// DiffCommand compares the content of two object stores (or an object store
+// and a local directory) using their manifests.
+type DiffCommand struct {
+	cmd.Base
+	exitCode bool
+	from     string
+	to       string
+}
+
+// NewDiffCommand returns a usable command registered under the parent.
+func NewDiffCommand(parent cmd.Registerer, g *global.Data) *DiffCommand {
+	c := DiffCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("diff", "Compare two object store manifests (see `object-store-entry list --output-manifest`) or a manifest and a local directory")
+	c.CmdClause.Flag("from", "Path to the manifest (or directory) to compare from").Required().StringVar(&c.from)
+	c.CmdClause.Flag("to", "Path to the manifest (or directory) to compare to").Required().StringVar(&c.to)
+
+	// optional
+	c.CmdClause.Flag("exit-code", "Exit with an error if there are differences").BoolVar(&c.exitCode)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *DiffCommand) Exec(_ io.Reader, out io.Writer) error {
+	from, err := objectstoreentry.ReadManifest(c.from)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading --from: %w", err)
+	}
+	to, err := objectstoreentry.ReadManifest(c.to)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading --to: %w", err)
+	}
+
+	d := diffManifests(from, to)
+	for _, e := range d.added {
+		text.Output(out, "%s %s (%d bytes, %s)", text.BoldGreen("+"), e.Key, e.Size, e.ContentType)
+	}
+	for _, e := range d.removed {
+		text.Output(out, "%s %s", text.BoldRed("-"), e.Key)
+	}
+	for _, ch := range d.changed {
+		text.Output(out, "%s %s (%s)", text.BoldYellow("~"), ch.from.Key, describeChange(ch.from, ch.to))
+	}
+
+	if len(d.added)+len(d.removed)+len(d.changed) == 0 {
+		text.Success(out, "No differences (%d keys)", d.unchanged)
+		return nil
+	}
+	text.Break(out)
+	text.Info(out, "%d added, %d removed, %d changed, %d unchanged", len(d.added), len(d.removed), len(d.changed), d.unchanged)
+	if c.exitCode {
+		return fmt.Errorf("the manifests differ")
+	}
+	return nil
+}
+
+// manifestDiff is the result of comparing two manifests.
+type manifestDiff struct {
+	added     []objectstoreentry.ManifestEntry
+	removed   []objectstoreentry.ManifestEntry
+	changed   []entryChange
+	unchanged int
+}
+
+// entryChange is a key whose value differs between the manifests.
+type entryChange struct {
+	from objectstoreentry.ManifestEntry
+	to   objectstoreentry.ManifestEntry
+}
+
+// diffManifests compares the manifests, with the results sorted by key.
+func diffManifests(from, to objectstoreentry.Manifest) manifestDiff {
+	var d manifestDiff
+
+	fromKeys := make(map[string]objectstoreentry.ManifestEntry, len(from.Entries))
+	for _, e := range from.Entries {
+		fromKeys[e.Key] = e
+	}
+	toKeys := make(map[string]bool, len(to.Entries))
+	for _, e := range to.Entries {
+		toKeys[e.Key] = true
+		f, ok := fromKeys[e.Key]
+		switch {
+		case !ok:
+			d.added = append(d.added, e)
+		case f != e:
+			d.changed = append(d.changed, entryChange{from: f, to: e})
+		default:
+			d.unchanged++
+		}
+	}
+	for _, e := range from.Entries {
+		if !toKeys[e.Key] {
+			d.removed = append(d.removed, e)
+		}
+	}
+
+	sort.Slice(d.added, func(i, j int) bool { return d.added[i].Key < d.added[j].Key })
+	sort.Slice(d.removed, func(i, j int) bool { return d.removed[i].Key < d.removed[j].Key })
+	sort.Slice(d.changed, func(i, j int) bool { return d.changed[i].from.Key < d.changed[j].from.Key })
+	return d
+}
+
+// describeChange summarises how an entry changed.
+func describeChange(from, to objectstoreentry.ManifestEntry) string {
+	switch {
+	case from.ContentType != to.ContentType:
+		return fmt.Sprintf("content type %s → %s", from.ContentType, to.ContentType)
+	case from.Size != to.Size:
+		return fmt.Sprintf("size %d → %d bytes", from.Size, to.Size)
+	default:
+		return "content changed"
+	}
+}
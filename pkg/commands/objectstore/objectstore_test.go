@@ -0,0 +1,163 @@
+package objectstore_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/objectstoreentry"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest := func(name string, entries ...objectstoreentry.ManifestEntry) string {
+		var buf bytes.Buffer
+		if err := (objectstoreentry.Manifest{Entries: entries}).Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, buf.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	blue := writeManifest("blue.json",
+		objectstoreentry.NewManifestEntry("index.html", []byte("<html>blue</html>")),
+		objectstoreentry.NewManifestEntry("app.js", []byte("v1")),
+		objectstoreentry.NewManifestEntry("old.css", []byte("body{}")),
+		objectstoreentry.NewManifestEntry("same.txt", []byte("same")),
+	)
+	green := writeManifest("green.json",
+		objectstoreentry.NewManifestEntry("index.html", []byte("<html>green</html>")),
+		objectstoreentry.NewManifestEntry("app.js", []byte("v2")),
+		objectstoreentry.NewManifestEntry("new.css", []byte("body{}")),
+		objectstoreentry.NewManifestEntry("same.txt", []byte("same")),
+	)
+
+	local := filepath.Join(dir, "local")
+	if err := os.MkdirAll(local, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"index.html": "<html>green</html>", "app.js": "v2", "new.css": "body{}", "same.txt": "same"} {
+		if err := os.WriteFile(filepath.Join(local, k), []byte(v), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --from flag",
+			Args:      args("object-store diff --to " + green),
+			WantError: "error parsing arguments: required flag --from not provided",
+		},
+		{
+			Name:      "validate missing manifest file",
+			Args:      args("object-store diff --from " + filepath.Join(dir, "missing.json") + " --to " + green),
+			WantError: "error reading --from",
+		},
+		{
+			Name: "validate differences",
+			Args: args("object-store diff --from " + blue + " --to " + green),
+			WantOutputs: []string{
+				"+ new.css (6 bytes, text/css; charset=utf-8)",
+				"- old.css",
+				"~ app.js (content changed)",
+				"~ index.html (size 17 → 18 bytes)",
+				"1 added, 1 removed, 2 changed, 1 unchanged",
+			},
+		},
+		{
+			Name:      "validate --exit-code",
+			Args:      args("object-store diff --from " + blue + " --to " + green + " --exit-code"),
+			WantError: "the manifests differ",
+		},
+		{
+			Name:       "validate manifest matching a local directory",
+			Args:       args("object-store diff --from " + green + " --to " + local + " --exit-code"),
+			WantOutput: "No differences (4 keys)",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	args := testutil.Args
+	api := mock.API{
+		GetObjectStoreFn: func(i *fastly.GetObjectStoreInput) (*fastly.ObjectStore, error) {
+			return &fastly.ObjectStore{ID: i.ID, Name: "assets"}, nil
+		},
+	}
+	scenarios := []struct {
+		testutil.TestScenario
+		wantRemediationError string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing --name flag",
+				Args:      args("object-store update --store-id 123 --token 123"),
+				WantError: "error parsing arguments: required flag --name not provided",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate GetObjectStore API error",
+				API: mock.API{
+					GetObjectStoreFn: func(i *fastly.GetObjectStoreInput) (*fastly.ObjectStore, error) {
+						return nil, testutil.Err
+					},
+				},
+				Args:      args("object-store update --store-id 123 --name static --token 123"),
+				WantError: testutil.Err.Error(),
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate unchanged name",
+				API:       api,
+				Args:      args("object-store update --store-id 123 --name assets --token 123"),
+				WantError: "object store 123 is already named 'assets'",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate rename is unsupported",
+				API:       api,
+				Args:      args("object-store update --store-id 123 --name static --token 123"),
+				WantError: "object store 'assets' can't be renamed",
+			},
+			wantRemediationError: "fastly object-store create --name static",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package objectstore
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// UpdateCommand renames an object store, which the Fastly API doesn't support
+// and so explains how to migrate to a store with the new name instead.
+type UpdateCommand struct {
+	cmd.Base
+	manifest manifest.Data
+	Input    fastly.GetObjectStoreInput
+	name     string
+}
+
+// NewUpdateCommand returns a usable command registered under the parent.
+func NewUpdateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *UpdateCommand {
+	c := UpdateCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("update", "Update an object store")
+	c.CmdClause.Flag("store-id", "Store ID").Short('s').Required().StringVar(&c.Input.ID)
+	c.CmdClause.Flag("name", "New name for the object store").Short('n').Required().StringVar(&c.name)
+
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *UpdateCommand) Exec(_ io.Reader, _ io.Writer) error {
+	o, err := c.Globals.APIClient.GetObjectStore(&c.Input)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if o.Name == c.name {
+		return fmt.Errorf("object store %s is already named '%s'", o.ID, o.Name)
+	}
+
+	err = fsterr.RemediationError{
+		Inner: fmt.Errorf("object store '%s' can't be renamed as the Fastly API doesn't support renaming object stores", o.Name),
+		Remediation: fmt.Sprintf(`Copy the entries to a new object store instead:
+
+  fastly object-store create --name %[2]s
+  fastly object-store-entry list --store-id %[1]s
+  fastly object-store-entry describe --store-id %[1]s --key-name <key>            (for each key)
+  fastly object-store-entry create --store-id <new-store-id> --key-name <key> --value <value>
+
+Then link the new store to your services in place of the old one (see `+"`fastly resource-link`"+`), and delete the old store:
+
+  fastly object-store delete --store-id %[1]s`, o.ID, c.name),
+	}
+	c.Globals.ErrLog.Add(err)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/fastly/cli/pkg/cmd"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -16,9 +17,10 @@ import (
 // ListCommand calls the Fastly API to list the keys for a given object store.
 type ListCommand struct {
 	cmd.Base
-	json     bool
-	manifest manifest.Data
-	Input    fastly.ListObjectStoreKeysInput
+	json           bool
+	manifest       manifest.Data
+	outputManifest string
+	Input          fastly.ListObjectStoreKeysInput
 }
 
 // NewListCommand returns a usable command registered under the parent.
@@ -39,6 +41,7 @@ func NewListCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Lis
 		Dst:         &c.json,
 		Short:       'j',
 	})
+	c.CmdClause.Flag("output-manifest", "Write a manifest of every key's size, hash and content type to a file (--output-manifest=- for stdout), for use with `object-store diff`").StringVar(&c.outputManifest)
 	return &c
 }
 
@@ -48,6 +51,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.outputManifest != "" {
+		return c.writeManifest(out)
+	}
+
 	o, err := c.Globals.APIClient.ListObjectStoreKeys(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -77,3 +84,61 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 	return nil
 }
+
+// writeManifest fetches every key of the store (and its value) to generate a
+// manifest of the store's content.
+func (c *ListCommand) writeManifest(out io.Writer) error {
+	m := Manifest{StoreID: c.Input.ID}
+
+	input := c.Input
+	for {
+		o, err := c.Globals.APIClient.ListObjectStoreKeys(&input)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		for _, k := range o.Data {
+			v, err := c.Globals.APIClient.GetObjectStoreKey(&fastly.GetObjectStoreKeyInput{
+				ID:  c.Input.ID,
+				Key: k,
+			})
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Store ID": c.Input.ID,
+					"Key":      k,
+				})
+				return fmt.Errorf("error getting key '%s': %w", k, err)
+			}
+			m.Entries = append(m.Entries, NewManifestEntry(k, []byte(v)))
+		}
+		if input.Cursor = o.Meta["next_cursor"]; input.Cursor == "" {
+			break
+		}
+	}
+
+	if c.outputManifest == "-" {
+		return m.Write(out)
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	f, err := os.Create(c.outputManifest)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error creating manifest file: %w", err)
+	}
+	if err := m.Write(f); err != nil {
+		_ = f.Close()
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error writing manifest file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error writing manifest file: %w", err)
+	}
+
+	text.Success(out, "Wrote a manifest of %d keys to %s", len(m.Entries), c.outputManifest)
+	return nil
+}
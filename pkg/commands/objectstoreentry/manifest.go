@@ -0,0 +1,114 @@
+package objectstoreentry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// Manifest describes the content of an object store, so it can be compared
+// with a local directory or another store (e.g. to validate a blue/green
+// deployment of static assets).
+type Manifest struct {
+	StoreID string          `json:"store_id,omitempty"`
+	Entries []ManifestEntry `json:"entries"`
+}
+
+// ManifestEntry describes a single key.
+type ManifestEntry struct {
+	Key         string `json:"key"`
+	Size        int    `json:"size"`
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+}
+
+// NewManifestEntry describes the key and its value.
+//
+// NOTE: Object stores don't record a content type, so it's deduced from the
+// key's file extension (falling back to sniffing the value) in the same way
+// for stores and local directories.
+func NewManifestEntry(key string, value []byte) ManifestEntry {
+	sum := sha256.Sum256(value)
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = http.DetectContentType(value)
+	}
+	return ManifestEntry{
+		Key:         key,
+		Size:        len(value),
+		Hash:        "sha256:" + hex.EncodeToString(sum[:]),
+		ContentType: ct,
+	}
+}
+
+// Write writes the manifest as JSON, with the entries sorted by key.
+func (m Manifest) Write(w io.Writer) error {
+	sort.Slice(m.Entries, func(i, j int) bool {
+		return m.Entries[i].Key < m.Entries[j].Key
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// ReadManifest reads the manifest file at path. If path is a directory, a
+// manifest of the files it contains is generated instead, using each file's
+// slash-separated path relative to the directory as its key.
+func ReadManifest(path string) (Manifest, error) {
+	var m Manifest
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return m, err
+	}
+	if fi.IsDir() {
+		return dirManifest(path)
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("error parsing manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// dirManifest generates a manifest of the files within dir.
+func dirManifest(dir string) (Manifest, error) {
+	var m Manifest
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as the path is within the directory provided by the user.
+		// #nosec
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		m.Entries = append(m.Entries, NewManifestEntry(filepath.ToSlash(rel), data))
+		return nil
+	})
+	return m, err
+}
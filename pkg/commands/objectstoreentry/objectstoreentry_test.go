@@ -0,0 +1,197 @@
+package objectstoreentry_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/objectstoreentry"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestListOutputManifest(t *testing.T) {
+	values := map[string]string{
+		"index.html":    "<html></html>",
+		"img/logo.png":  "\x89PNG\r\n\x1a\n",
+		"data/no-ext":   "plain text",
+		"styles/a.css":  "body{}",
+		"scripts/a.js":  "alert(1)",
+		"missing-value": "",
+	}
+	pages := map[string]*fastly.ListObjectStoreKeysResponse{
+		"":   {Data: []string{"index.html", "img/logo.png", "data/no-ext"}, Meta: map[string]string{"next_cursor": "c2"}},
+		"c2": {Data: []string{"styles/a.css", "scripts/a.js"}},
+	}
+	api := mock.API{
+		ListObjectStoreKeysFn: func(i *fastly.ListObjectStoreKeysInput) (*fastly.ListObjectStoreKeysResponse, error) {
+			return pages[i.Cursor], nil
+		},
+		GetObjectStoreKeyFn: func(i *fastly.GetObjectStoreKeyInput) (string, error) {
+			return values[i.Key], nil
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("object-store-entry list --store-id 123 --output-manifest "+path+" --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Wrote a manifest of 5 keys to "+path)
+
+	m, err := objectstoreentry.ReadManifest(path)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "123", m.StoreID)
+	want := []objectstoreentry.ManifestEntry{
+		objectstoreentry.NewManifestEntry("data/no-ext", []byte("plain text")),
+		objectstoreentry.NewManifestEntry("img/logo.png", []byte("\x89PNG\r\n\x1a\n")),
+		objectstoreentry.NewManifestEntry("index.html", []byte("<html></html>")),
+		objectstoreentry.NewManifestEntry("scripts/a.js", []byte("alert(1)")),
+		objectstoreentry.NewManifestEntry("styles/a.css", []byte("body{}")),
+	}
+	testutil.AssertEqual(t, want, m.Entries)
+	testutil.AssertString(t, "text/plain; charset=utf-8", m.Entries[0].ContentType)
+	testutil.AssertString(t, "image/png", m.Entries[1].ContentType)
+	testutil.AssertEqual(t, 13, m.Entries[2].Size)
+
+	// The manifest of a local directory with the same content is identical.
+	dir := t.TempDir()
+	for _, e := range want {
+		p := filepath.Join(dir, filepath.FromSlash(e.Key))
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(values[e.Key]), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dm, err := objectstoreentry.ReadManifest(dir)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, want, dm.Entries)
+
+	stdout.Reset()
+	api.GetObjectStoreKeyFn = func(i *fastly.GetObjectStoreKeyInput) (string, error) {
+		return "", testutil.Err
+	}
+	opts = testutil.NewRunOpts(testutil.Args("object-store-entry list --store-id 123 --output-manifest=- --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err = app.Run(opts)
+	testutil.AssertErrorContains(t, err, "error getting key 'index.html': test error")
+}
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"a.txt": "a", "b/c.txt": "c", "d.txt": "d"}
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	// The upload fails on the last key, so the checkpoint records the others.
+	var (
+		inserted []string
+		failed   bool
+	)
+	api := mock.API{
+		InsertObjectStoreKeyFn: func(i *fastly.InsertObjectStoreKeyInput) error {
+			if i.Key == "d.txt" && !failed {
+				failed = true
+				return errors.New("rate limited")
+			}
+			if i.ID != "123" || i.Value != files[i.Key] {
+				return fmt.Errorf("unexpected input: %+v", i)
+			}
+			inserted = append(inserted, i.Key)
+			return nil
+		},
+	}
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("object-store-entry upload --store-id 123 --dir "+dir+" --checkpoint "+checkpoint+" --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "error uploading d.txt: rate limited")
+	testutil.AssertEqual(t, []string{"a.txt", "b/c.txt"}, inserted)
+
+	cp, err := objectstoreentry.ReadCheckpoint(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "123", cp.StoreID)
+	testutil.AssertEqual(t, []string{"a.txt", "b/c.txt"}, cp.Uploaded)
+
+	// Resuming uploads the remaining key and removes the checkpoint.
+	stdout.Reset()
+	opts = testutil.NewRunOpts(testutil.Args("object-store-entry upload --resume "+checkpoint+" --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err = app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{"a.txt", "b/c.txt", "d.txt"}, inserted)
+	testutil.AssertStringContains(t, stdout.String(), "Uploaded 1 keys to object store 123 (2 already uploaded)")
+	if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+		t.Fatalf("want the checkpoint removed, got: %v", err)
+	}
+
+	// A checkpoint can't be resumed against another store.
+	if err := os.WriteFile(checkpoint, []byte(`{"store_id":"123","dir":"`+filepath.ToSlash(dir)+`"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts = testutil.NewRunOpts(testutil.Args("object-store-entry upload --resume "+checkpoint+" --store-id 789 --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err = app.Run(opts)
+	testutil.AssertErrorContains(t, err, "the checkpoint is of an upload of")
+}
+
+func TestUploadInterrupted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are sent as SIGINT")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	api := mock.API{
+		InsertObjectStoreKeyFn: func(i *fastly.InsertObjectStoreKeyInput) error {
+			if i.Key == "a" {
+				// The upload stops before the next key.
+				p, err := os.FindProcess(os.Getpid())
+				if err != nil {
+					return err
+				}
+				if err := p.Signal(os.Interrupt); err != nil {
+					return err
+				}
+				time.Sleep(200 * time.Millisecond)
+			}
+			return nil
+		},
+	}
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("object-store-entry upload --store-id 123 --dir "+dir+" --checkpoint "+checkpoint+" --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "upload interrupted")
+
+	cp, err := objectstoreentry.ReadCheckpoint(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, []string{"a"}, cp.Uploaded)
+}
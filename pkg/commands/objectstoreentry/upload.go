@@ -0,0 +1,228 @@
+package objectstoreentry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// Checkpoint records the progress of an interrupted upload, so it can be
+// resumed with --resume rather than starting over.
+type Checkpoint struct {
+	StoreID string    `json:"store_id"`
+	Dir     string    `json:"dir"`
+	Time    time.Time `json:"time"`
+	// Uploaded are the keys that were uploaded before the interruption.
+	Uploaded []string `json:"uploaded"`
+}
+
+// ReadCheckpoint reads the checkpoint file at path.
+func ReadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("error parsing checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// write writes the checkpoint to path.
+func (cp Checkpoint) write(path string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// UploadCommand calls the Fastly API to insert the files of a directory into
+// an object store.
+type UploadCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	checkpoint string
+	dir        string
+	resume     string
+	storeID    string
+}
+
+// NewUploadCommand returns a usable command registered under the parent.
+func NewUploadCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *UploadCommand {
+	c := UploadCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("upload", "Insert each file of a directory, keyed by its path relative to the directory. An interrupted upload can be resumed with --resume")
+	c.CmdClause.Flag("store-id", "Store ID (required unless --resume is set)").Short('s').StringVar(&c.storeID)
+	c.CmdClause.Flag("dir", "The directory to upload (required unless --resume is set)").StringVar(&c.dir)
+
+	// optional
+	c.CmdClause.Flag("checkpoint", "The file the progress is written to if the upload is interrupted (default: fastly-upload-<store id>.json)").StringVar(&c.checkpoint)
+	c.CmdClause.Flag("resume", "Resume the upload recorded by a checkpoint file, skipping the keys already uploaded").StringVar(&c.resume)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *UploadCommand) Exec(_ io.Reader, out io.Writer) error {
+	// The directory is recorded as an absolute path, so an upload can be
+	// resumed from another directory.
+	if c.dir != "" {
+		dir, err := filepath.Abs(c.dir)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		c.dir = dir
+	}
+
+	var cp Checkpoint
+	if c.resume != "" {
+		var err error
+		cp, err = ReadCheckpoint(c.resume)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error reading --resume checkpoint: %w", err)
+		}
+		if (c.storeID != "" && c.storeID != cp.StoreID) || (c.dir != "" && c.dir != cp.Dir) {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("the checkpoint is of an upload of %s to store %s", cp.Dir, cp.StoreID),
+				Remediation: "Omit --store-id and --dir when resuming, or resume the matching checkpoint.",
+			}
+		}
+		c.storeID, c.dir = cp.StoreID, cp.Dir
+		if c.checkpoint == "" {
+			c.checkpoint = c.resume
+		}
+	}
+	if c.storeID == "" || c.dir == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--store-id and --dir are required"),
+			Remediation: "Provide the store and directory to upload, or --resume an interrupted upload.",
+		}
+	}
+	if c.checkpoint == "" {
+		c.checkpoint = fmt.Sprintf("fastly-upload-%s.json", c.storeID)
+	}
+	cp.StoreID, cp.Dir = c.storeID, c.dir
+
+	keys, err := dirKeys(c.dir)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading --dir: %w", err)
+	}
+	uploaded := make(map[string]bool, len(cp.Uploaded))
+	for _, k := range cp.Uploaded {
+		uploaded[k] = true
+	}
+	skipped := len(uploaded)
+
+	// An interrupt stops the upload between keys, so the checkpoint records
+	// exactly the keys that were uploaded.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	var n int
+	for _, key := range keys {
+		if uploaded[key] {
+			continue
+		}
+		select {
+		case <-sig:
+			return c.interrupted(cp, errors.New("upload interrupted"), n, out)
+		default:
+		}
+
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as the path is within the directory provided by the user.
+		// #nosec
+		data, err := os.ReadFile(filepath.Join(c.dir, filepath.FromSlash(key)))
+		if err == nil {
+			err = c.Globals.APIClient.InsertObjectStoreKey(&fastly.InsertObjectStoreKeyInput{
+				ID:    c.storeID,
+				Key:   key,
+				Value: string(data),
+			})
+		}
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Store ID": c.storeID,
+				"Key":      key,
+			})
+			return c.interrupted(cp, fmt.Errorf("error uploading %s: %w", key, err), n, out)
+		}
+		cp.Uploaded = append(cp.Uploaded, key)
+		n++
+		if c.Globals.Verbose() {
+			text.Output(out, "Uploaded %s", key)
+		}
+	}
+
+	// The upload is complete, so any checkpoint is obsolete.
+	_ = os.Remove(c.checkpoint)
+	if skipped > 0 {
+		text.Success(out, "Uploaded %d keys to object store %s (%d already uploaded)", n, c.storeID, skipped)
+		return nil
+	}
+	text.Success(out, "Uploaded %d keys to object store %s", n, c.storeID)
+	return nil
+}
+
+// interrupted writes the checkpoint and returns err with how to resume.
+func (c *UploadCommand) interrupted(cp Checkpoint, err error, n int, out io.Writer) error {
+	cp.Time = time.Now().UTC()
+	if werr := cp.write(c.checkpoint); werr != nil {
+		c.Globals.ErrLog.Add(werr)
+		return fmt.Errorf("%w (and the checkpoint couldn't be written: %s)", err, werr)
+	}
+	text.Info(out, "Uploaded %d keys before stopping (%d in total)", n, len(cp.Uploaded))
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: fmt.Sprintf("The progress was saved to %s. Continue the upload with:\n\n\t$ fastly object-store-entry upload --resume %s", c.checkpoint, c.checkpoint),
+	}
+}
+
+// dirKeys returns the keys of the files within dir (their slash-separated
+// path relative to dir), sorted so uploads are in a stable order.
+func dirKeys(dir string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
@@ -0,0 +1,126 @@
+package propagate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// acl is an ACL identified by name, whose entries are keyed by IP and subnet.
+type acl struct {
+	globals *global.Data
+	name    string
+	// ids maps each service to the ID of the service's ACL.
+	ids map[string]string
+	// entries maps each service to the ACL's entries.
+	entries map[string]map[string]*fastly.ACLEntry
+}
+
+func newACL(g *global.Data, name string) *acl {
+	return &acl{
+		globals: g,
+		name:    name,
+		ids:     make(map[string]string),
+		entries: make(map[string]map[string]*fastly.ACLEntry),
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (a *acl) String() string {
+	return fmt.Sprintf("acl '%s'", a.name)
+}
+
+// fetch implements the resource interface.
+func (a *acl) fetch(serviceID string) (map[string]string, error) {
+	client := a.globals.APIClient
+
+	version, err := resolveVersion(client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := client.GetACL(&fastly.GetACLInput{
+		Name:           a.name,
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s on service %s version %d: %w", a, serviceID, version, err)
+	}
+	a.ids[serviceID] = r.ID
+
+	paginator := client.NewListACLEntriesPaginator(&fastly.ListACLEntriesInput{
+		ACLID:     r.ID,
+		PerPage:   100,
+		ServiceID: serviceID,
+	})
+	entries := make(map[string]*fastly.ACLEntry)
+	values := make(map[string]string)
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, fmt.Errorf("error listing entries of %s on service %s: %w", a, serviceID, err)
+		}
+		for _, e := range data {
+			k := aclEntryKey(e)
+			entries[k] = e
+			values[k] = aclEntryValue(e)
+		}
+	}
+	a.entries[serviceID] = entries
+	return values, nil
+}
+
+// apply implements the resource interface.
+func (a *acl) apply(sourceID, targetID string, changes []change) error {
+	ops := make([]*fastly.BatchACLEntry, 0, len(changes))
+	for _, ch := range changes {
+		op := &fastly.BatchACLEntry{Operation: ch.op}
+		if ch.op != fastly.CreateBatchOperation {
+			op.ID = fastly.String(a.entries[targetID][ch.key].ID)
+		}
+		if ch.op != fastly.DeleteBatchOperation {
+			e := a.entries[sourceID][ch.key]
+			op.IP = fastly.String(e.IP)
+			op.Subnet = e.Subnet
+			op.Negated = fastly.CBool(e.Negated)
+			op.Comment = fastly.String(e.Comment)
+		}
+		ops = append(ops, op)
+	}
+
+	_, err := api.BatchModify(ops, func(batch []*fastly.BatchACLEntry) error {
+		return a.globals.APIClient.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     a.ids[targetID],
+			Entries:   batch,
+			ServiceID: targetID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error updating entries of %s on service %s: %w", a, targetID, err)
+	}
+	return nil
+}
+
+// aclEntryKey identifies an ACL entry by its IP and subnet.
+func aclEntryKey(e *fastly.ACLEntry) string {
+	if e.Subnet == nil {
+		return e.IP
+	}
+	return e.IP + "/" + strconv.Itoa(*e.Subnet)
+}
+
+// aclEntryValue describes the attributes of an ACL entry that are compared.
+func aclEntryValue(e *fastly.ACLEntry) string {
+	var attrs []string
+	if e.Negated {
+		attrs = append(attrs, "negated")
+	}
+	if e.Comment != "" {
+		attrs = append(attrs, strconv.Quote(e.Comment))
+	}
+	return strings.Join(attrs, " ")
+}
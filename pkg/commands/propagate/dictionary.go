@@ -0,0 +1,91 @@
+package propagate
+
+import (
+	"fmt"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// dictionary is a dictionary identified by name, whose items are keyed by
+// item key.
+type dictionary struct {
+	globals *global.Data
+	name    string
+	// ids maps each service to the ID of the service's dictionary.
+	ids map[string]string
+}
+
+func newDictionary(g *global.Data, name string) *dictionary {
+	return &dictionary{
+		globals: g,
+		name:    name,
+		ids:     make(map[string]string),
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (d *dictionary) String() string {
+	return fmt.Sprintf("dictionary '%s'", d.name)
+}
+
+// fetch implements the resource interface.
+func (d *dictionary) fetch(serviceID string) (map[string]string, error) {
+	client := d.globals.APIClient
+
+	version, err := resolveVersion(client, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := client.GetDictionary(&fastly.GetDictionaryInput{
+		Name:           d.name,
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s on service %s version %d: %w", d, serviceID, version, err)
+	}
+	d.ids[serviceID] = r.ID
+
+	paginator := client.NewListDictionaryItemsPaginator(&fastly.ListDictionaryItemsInput{
+		DictionaryID: r.ID,
+		PerPage:      100,
+		ServiceID:    serviceID,
+	})
+	items := make(map[string]string)
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, fmt.Errorf("error listing items of %s on service %s: %w", d, serviceID, err)
+		}
+		for _, item := range data {
+			items[item.ItemKey] = item.ItemValue
+		}
+	}
+	return items, nil
+}
+
+// apply implements the resource interface.
+func (d *dictionary) apply(_, targetID string, changes []change) error {
+	ops := make([]*fastly.BatchDictionaryItem, 0, len(changes))
+	for _, ch := range changes {
+		ops = append(ops, &fastly.BatchDictionaryItem{
+			Operation: ch.op,
+			ItemKey:   ch.key,
+			ItemValue: ch.to,
+		})
+	}
+
+	_, err := api.BatchModify(ops, func(batch []*fastly.BatchDictionaryItem) error {
+		return d.globals.APIClient.BatchModifyDictionaryItems(&fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: d.ids[targetID],
+			Items:        batch,
+			ServiceID:    targetID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error updating items of %s on service %s: %w", d, targetID, err)
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package propagate contains commands to copy ACL and dictionary entries
+// between Fastly services.
+package propagate
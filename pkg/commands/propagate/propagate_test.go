@@ -0,0 +1,256 @@
+package propagate_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestPropagate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		wantNoOutput []string
+		wantBatches  []string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing --resource flag",
+				Args:      args("propagate --service-id src --to-services a --token 123"),
+				WantError: "error parsing arguments: required flag --resource not provided",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate invalid --resource flag",
+				Args:      args("propagate --resource snippet:foo --service-id src --to-services a --token 123"),
+				WantError: "invalid --resource 'snippet:foo'",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate --to-services only contains the source service",
+				Args:      args("propagate --resource acl:allowlist --service-id src --to-services src --token 123"),
+				WantError: "no services to propagate to",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate GetACL API error for the source service",
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					GetACLFn: func(i *fastly.GetACLInput) (*fastly.ACL, error) {
+						return nil, testutil.Err
+					},
+				},
+				Args:      args("propagate --resource acl:allowlist --service-id src --to-services a --token 123"),
+				WantError: "error getting acl 'allowlist' on service src version 1: test error",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate ACL --dry-run",
+				API:  aclAPI(),
+				Args: args("propagate --resource acl:allowlist --service-id src --to-services a,b --dry-run --token 123"),
+				WantOutputs: []string{
+					"Propagating to service a (acl 'allowlist')",
+					`+ 1.2.3.4/32: "office"`,
+					`~ 10.0.0.0/8: negated → "internal"`,
+					"Propagating to service b (acl 'allowlist')",
+					"Already up to date",
+					"Dry run: no changes were made to 2 services",
+				},
+			},
+			wantNoOutput: []string{"9.9.9.9", "Applied"},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate ACL --prune",
+				API:  aclAPI(),
+				Args: args("propagate --resource acl:allowlist --service-id src --to-services a,b --prune --token 123"),
+				WantOutputs: []string{
+					"- 9.9.9.9",
+					"Applied 3 changes",
+					"Propagated acl 'allowlist' to 2 services",
+				},
+			},
+			wantBatches: []string{
+				"a/acl-a: create 1.2.3.4/32 negated=false comment=office",
+				"a/acl-a: delete a-9",
+				"a/acl-a: update a-10 10.0.0.0/8 negated=false comment=internal",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate per-service errors don't stop other services",
+				API:  aclAPI(),
+				Args: args("propagate --resource acl:allowlist --service-id src --to-services c,b,a --token 123"),
+				WantOutputs: []string{
+					"ERROR: error getting acl 'allowlist' on service c version 1: test error",
+					"Applied 2 changes",
+				},
+				WantError: "failed to propagate acl 'allowlist' to 1 of 3 services: c",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate dictionary",
+				API:  dictionaryAPI(),
+				Args: args("propagate --resource dictionary:redirects --service-id src --to-services a --prune --token 123"),
+				WantOutputs: []string{
+					"Propagating to service a (dictionary 'redirects')",
+					"+ /new: /newer",
+					"~ /old: /older → /new",
+					"- /stale: /gone",
+					"Propagated dictionary 'redirects' to 1 services",
+				},
+			},
+			wantBatches: []string{
+				"a/dict-a: create /new=/newer",
+				"a/dict-a: delete /stale=",
+				"a/dict-a: update /old=/new",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var batches []string
+			testcase.API.BatchModifyACLEntriesFn = func(i *fastly.BatchModifyACLEntriesInput) error {
+				for _, e := range i.Entries {
+					s := fmt.Sprintf("%s/%s: %s", i.ServiceID, i.ACLID, e.Operation)
+					if e.ID != nil {
+						s += " " + *e.ID
+					}
+					if e.IP != nil {
+						s += fmt.Sprintf(" %s/%d negated=%t comment=%s", *e.IP, *e.Subnet, bool(*e.Negated), *e.Comment)
+					}
+					batches = append(batches, s)
+				}
+				return nil
+			}
+			testcase.API.BatchModifyDictionaryItemsFn = func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				for _, item := range i.Items {
+					batches = append(batches, fmt.Sprintf("%s/%s: %s %s=%s", i.ServiceID, i.DictionaryID, item.Operation, item.ItemKey, item.ItemValue))
+				}
+				return nil
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.wantNoOutput {
+				if strings.Contains(stdout.String(), s) {
+					t.Errorf("want output not to contain %q, got: %s", s, stdout.String())
+				}
+			}
+			if testcase.wantBatches != nil {
+				sort.Strings(batches)
+				testutil.AssertEqual(t, testcase.wantBatches, batches)
+			}
+		})
+	}
+}
+
+// aclEntries are the entries of the 'allowlist' ACL on each service. Service
+// 'b' is already up to date and service 'c' has no such ACL.
+var aclEntries = map[string][]*fastly.ACLEntry{
+	"src": {
+		{ID: "src-1", IP: "1.2.3.4", Subnet: fastly.Int(32), Comment: "office"},
+		{ID: "src-10", IP: "10.0.0.0", Subnet: fastly.Int(8), Comment: "internal"},
+	},
+	"a": {
+		{ID: "a-10", IP: "10.0.0.0", Subnet: fastly.Int(8), Negated: true},
+		{ID: "a-9", IP: "9.9.9.9"},
+	},
+	"b": {
+		{ID: "b-1", IP: "1.2.3.4", Subnet: fastly.Int(32), Comment: "office"},
+		{ID: "b-10", IP: "10.0.0.0", Subnet: fastly.Int(8), Comment: "internal"},
+	},
+}
+
+func aclAPI() mock.API {
+	return mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetACLFn: func(i *fastly.GetACLInput) (*fastly.ACL, error) {
+			if _, ok := aclEntries[i.ServiceID]; !ok {
+				return nil, testutil.Err
+			}
+			return &fastly.ACL{ID: "acl-" + i.ServiceID, Name: i.Name, ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion}, nil
+		},
+		NewListACLEntriesPaginatorFn: func(i *fastly.ListACLEntriesInput) fastly.PaginatorACLEntries {
+			return &mockACLPaginator{entries: aclEntries[i.ServiceID]}
+		},
+	}
+}
+
+func dictionaryAPI() mock.API {
+	items := map[string][]*fastly.DictionaryItem{
+		"src": {
+			{ItemKey: "/new", ItemValue: "/newer"},
+			{ItemKey: "/old", ItemValue: "/new"},
+		},
+		"a": {
+			{ItemKey: "/old", ItemValue: "/older"},
+			{ItemKey: "/stale", ItemValue: "/gone"},
+		},
+	}
+	return mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetDictionaryFn: func(i *fastly.GetDictionaryInput) (*fastly.Dictionary, error) {
+			return &fastly.Dictionary{ID: "dict-" + i.ServiceID, Name: i.Name, ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion}, nil
+		},
+		NewListDictionaryItemsPaginatorFn: func(i *fastly.ListDictionaryItemsInput) fastly.PaginatorDictionaryItems {
+			return &mockDictionaryItemPaginator{items: items[i.ServiceID]}
+		},
+	}
+}
+
+type mockACLPaginator struct {
+	done    bool
+	entries []*fastly.ACLEntry
+}
+
+func (p *mockACLPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p mockACLPaginator) Remaining() int {
+	return 0
+}
+
+func (p *mockACLPaginator) GetNext() ([]*fastly.ACLEntry, error) {
+	p.done = true
+	return p.entries, nil
+}
+
+type mockDictionaryItemPaginator struct {
+	done  bool
+	items []*fastly.DictionaryItem
+}
+
+func (p *mockDictionaryItemPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p mockDictionaryItemPaginator) Remaining() int {
+	return 0
+}
+
+func (p *mockDictionaryItemPaginator) GetNext() ([]*fastly.DictionaryItem, error) {
+	p.done = true
+	return p.items, nil
+}
@@ -0,0 +1,251 @@
+package propagate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *RootCommand {
+	var c RootCommand
+	c.CmdClause = parent.Command("propagate", "Copy the entries of an ACL or dictionary to the equivalently named resource on other services")
+	c.Globals = g
+	c.manifest = m
+
+	// required
+	c.CmdClause.Flag("resource", "The resource whose entries are copied, as 'acl:<name>' or 'dictionary:<name>'").Required().StringVar(&c.resource)
+	c.CmdClause.Flag("to-services", "Comma-separated list of the IDs of the services to copy the entries to").Required().StringVar(&c.toServices)
+
+	// optional
+	c.CmdClause.Flag("dry-run", "Display the changes for each service without making them").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("prune", "Delete the entries that don't exist in the source resource").BoolVar(&c.prune)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: "Service ID of the service to copy the entries from (falls back to FASTLY_SERVICE_ID, then fastly.toml)",
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: "The name of the service to copy the entries from",
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	cmd.Base
+
+	dryRun      bool
+	manifest    manifest.Data
+	prune       bool
+	resource    string
+	serviceName cmd.OptionalServiceNameID
+	toServices  string
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	res, err := c.parseResource()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	targets := splitServices(c.toServices, serviceID)
+	if len(targets) == 0 {
+		err := errors.RemediationError{
+			Inner:       fmt.Errorf("no services to propagate to"),
+			Remediation: "Provide the IDs of other services via --to-services (e.g. --to-services svc1,svc2).",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	src, err := res.fetch(serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Resource":   c.resource,
+		})
+		return err
+	}
+
+	var failed []string
+	for _, target := range targets {
+		text.Break(out)
+		text.Output(out, "%s service %s (%s)", text.Bold("Propagating to"), target, res)
+
+		if err := c.propagate(res, src, serviceID, target, out); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": target,
+				"Resource":   c.resource,
+			})
+			text.Error(out, "%s", err)
+			failed = append(failed, target)
+		}
+	}
+
+	text.Break(out)
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to propagate %s to %d of %d services: %s", res, len(failed), len(targets), strings.Join(failed, ", "))
+	}
+	if c.dryRun {
+		text.Info(out, "Dry run: no changes were made to %d services", len(targets))
+		return nil
+	}
+	text.Success(out, "Propagated %s to %d services", res, len(targets))
+	return nil
+}
+
+// propagate displays and applies the changes needed for the target resource to
+// match the source resource.
+func (c *RootCommand) propagate(res resource, src map[string]string, sourceID, targetID string, out io.Writer) error {
+	dst, err := res.fetch(targetID)
+	if err != nil {
+		return err
+	}
+
+	changes := diffEntries(src, dst, c.prune)
+	if len(changes) == 0 {
+		text.Output(out, "Already up to date")
+		return nil
+	}
+	for _, ch := range changes {
+		switch ch.op {
+		case fastly.CreateBatchOperation:
+			text.Output(out, "%s %s", text.BoldGreen("+"), describe(ch.key, ch.to))
+		case fastly.DeleteBatchOperation:
+			text.Output(out, "%s %s", text.BoldRed("-"), describe(ch.key, ch.from))
+		default:
+			text.Output(out, "%s %s: %s → %s", text.BoldYellow("~"), ch.key, ch.from, ch.to)
+		}
+	}
+
+	if c.dryRun {
+		return nil
+	}
+	if err := res.apply(sourceID, targetID, changes); err != nil {
+		return err
+	}
+	text.Output(out, "Applied %d changes", len(changes))
+	return nil
+}
+
+// parseResource parses the --resource flag.
+func (c *RootCommand) parseResource() (resource, error) {
+	kind, name, ok := strings.Cut(c.resource, ":")
+	if ok && name != "" {
+		switch kind {
+		case "acl":
+			return newACL(c.Globals, name), nil
+		case "dictionary":
+			return newDictionary(c.Globals, name), nil
+		}
+	}
+	return nil, errors.RemediationError{
+		Inner:       fmt.Errorf("invalid --resource '%s'", c.resource),
+		Remediation: "Use 'acl:<name>' or 'dictionary:<name>' (e.g. --resource acl:allowlist).",
+	}
+}
+
+// resource is an ACL or dictionary whose entries can be propagated.
+type resource interface {
+	fmt.Stringer
+	// fetch returns the entries of the resource on the service, as a map of
+	// each entry's identity to its (comparable) value.
+	fetch(serviceID string) (map[string]string, error)
+	// apply makes the changes to the resource on the target service.
+	apply(sourceID, targetID string, changes []change) error
+}
+
+// change is a modification of a single entry.
+type change struct {
+	op   fastly.BatchOperation
+	key  string
+	from string
+	to   string
+}
+
+// diffEntries returns the changes (sorted by key) needed for dst to match src.
+// Entries missing from src are only deleted when prune is set.
+func diffEntries(src, dst map[string]string, prune bool) []change {
+	var changes []change
+	for k, v := range src {
+		old, ok := dst[k]
+		switch {
+		case !ok:
+			changes = append(changes, change{op: fastly.CreateBatchOperation, key: k, to: v})
+		case old != v:
+			changes = append(changes, change{op: fastly.UpdateBatchOperation, key: k, from: old, to: v})
+		}
+	}
+	if prune {
+		for k, v := range dst {
+			if _, ok := src[k]; !ok {
+				changes = append(changes, change{op: fastly.DeleteBatchOperation, key: k, from: v})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].key < changes[j].key
+	})
+	return changes
+}
+
+// describe formats an entry for display.
+func describe(key, value string) string {
+	if value == "" {
+		return key
+	}
+	return fmt.Sprintf("%s: %s", key, value)
+}
+
+// splitServices parses the --to-services flag, ignoring blank and duplicate
+// IDs and the source service.
+func splitServices(s, sourceID string) []string {
+	var ids []string
+	seen := map[string]bool{sourceID: true}
+	for _, id := range strings.Split(s, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// resolveVersion returns the version of the service used to look up the
+// resource by name (the active version, otherwise the latest).
+func resolveVersion(client api.Interface, serviceID string) (int, error) {
+	var sv cmd.OptionalServiceVersion
+	v, err := sv.Parse(serviceID, client)
+	if err != nil {
+		return 0, err
+	}
+	return v.Number, nil
+}
@@ -0,0 +1,203 @@
+package purge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// maxPurgeKeys is the maximum number of surrogate keys the API purges in a
+// single request.
+const maxPurgeKeys = 256
+
+// purgeItem is a URL or surrogate key read from a file, along with the result
+// of purging it.
+type purgeItem struct {
+	url   bool
+	value string
+	id    string
+	err   error
+}
+
+// purgeItems purges the URLs and surrogate keys listed in --file and
+// --keys-file, along with those of --key, printing the result of each.
+//
+// Each URL is purged by its own request, while the surrogate keys are purged
+// in batches of maxPurgeKeys. At most --concurrency requests are made at once.
+func (c *RootCommand) purgeItems(serviceID string, out io.Writer) error {
+	if c.concurrency < 1 {
+		return fmt.Errorf("error parsing arguments: --concurrency must be at least 1")
+	}
+
+	var items []*purgeItem
+	if c.file != "" {
+		lines, err := readLines(c.file)
+		if err != nil {
+			return err
+		}
+		for _, l := range lines {
+			items = append(items, &purgeItem{url: isURL(l), value: l})
+		}
+	}
+	if c.keysFile != "" {
+		lines, err := readLines(c.keysFile)
+		if err != nil {
+			return err
+		}
+		for _, l := range lines {
+			items = append(items, &purgeItem{value: l})
+		}
+	}
+	for _, k := range c.keys {
+		items = append(items, &purgeItem{value: k})
+	}
+	if len(items) == 0 {
+		return errors.RemediationError{
+			Inner:       fmt.Errorf("no URLs or surrogate keys to purge"),
+			Remediation: "Provide a file with one URL or surrogate key per line.",
+		}
+	}
+
+	var (
+		batches [][]*purgeItem
+		keys    []*purgeItem
+		urls    int
+	)
+	for _, item := range items {
+		if item.url {
+			batches = append(batches, []*purgeItem{item})
+			urls++
+			continue
+		}
+		keys = append(keys, item)
+	}
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > maxPurgeKeys {
+			n = maxPurgeKeys
+		}
+		batches = append(batches, keys[:n])
+		keys = keys[n:]
+	}
+
+	pending := make(chan []*purgeItem)
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency && i < len(batches); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range pending {
+				c.purgeBatch(serviceID, batch)
+			}
+		}()
+	}
+	for _, batch := range batches {
+		pending <- batch
+	}
+	close(pending)
+	wg.Wait()
+
+	var (
+		failed   int
+		firstErr error
+	)
+	t := text.NewTable(out)
+	t.AddHeader("TYPE", "ITEM", "ID", "STATUS")
+	for _, item := range items {
+		kind := "key"
+		if item.url {
+			kind = "url"
+		}
+		status := "purged"
+		if item.err != nil {
+			status = fmt.Sprintf("failed: %s", item.err)
+			failed++
+			if firstErr == nil {
+				firstErr = item.err
+			}
+		}
+		t.AddLine(kind, item.value, item.id, status)
+	}
+	t.Print()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to purge %d of %d items: %w", failed, len(items), firstErr)
+	}
+	text.Success(out, "Purged %d URLs and %d surrogate keys (soft: %t)", urls, len(items)-urls, c.soft.Value)
+	return nil
+}
+
+// purgeBatch purges a single URL, or a batch of surrogate keys, recording the
+// result on each item.
+func (c *RootCommand) purgeBatch(serviceID string, batch []*purgeItem) {
+	if batch[0].url {
+		p, err := c.Globals.APIClient.Purge(&fastly.PurgeInput{
+			URL:  batch[0].value,
+			Soft: c.soft.Value,
+		})
+		if err != nil {
+			batch[0].err = err
+			return
+		}
+		batch[0].id = p.ID
+		return
+	}
+
+	keys := make([]string, 0, len(batch))
+	for _, item := range batch {
+		keys = append(keys, item.value)
+	}
+	m, err := c.Globals.APIClient.PurgeKeys(&fastly.PurgeKeysInput{
+		ServiceID: serviceID,
+		Keys:      keys,
+		Soft:      c.soft.Value,
+	})
+	for _, item := range batch {
+		switch id, ok := m[item.value]; {
+		case err != nil:
+			item.err = err
+		case !ok:
+			item.err = fmt.Errorf("the API didn't return a purge ID")
+		default:
+			item.id = id
+		}
+	}
+}
+
+// isURL reports whether a line of --file is a URL rather than a surrogate key.
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// readLines returns the lines of a file, ignoring blank lines and surrounding
+// whitespace.
+func readLines(path string) ([]string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the path variable.
+	/* #nosec */
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // #nosec G307
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if l := strings.TrimSpace(scanner.Text()); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return lines, nil
+}
@@ -2,10 +2,19 @@ package purge_test
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/go-fastly/v7/fastly"
@@ -13,43 +22,133 @@ import (
 
 func TestPurgeAll(t *testing.T) {
 	args := testutil.Args
-	scenarios := []testutil.TestScenario{
+	scenarios := []struct {
+		testutil.TestScenario
+		stdin string
+	}{
 		{
-			Name:      "validate missing token",
-			Args:      args("purge --all"),
-			WantError: "no token provided",
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing token",
+				Args:      args("purge --all"),
+				WantError: "no token provided",
+			},
 		},
 		{
-			Name:      "validate missing --service-id flag",
-			Args:      args("purge --all --token 123"),
-			WantError: "error reading service: no service ID found",
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing --service-id flag",
+				Args:      args("purge --all --token 123"),
+				WantError: "error reading service: no service ID found",
+			},
 		},
 		{
-			Name:      "validate --soft flag isn't usable",
-			Args:      args("purge --all --service-id 123 --soft --token 456"),
-			WantError: "purge-all requests cannot be done in soft mode (--soft) and will always immediately invalidate all cached content associated with the service",
+			TestScenario: testutil.TestScenario{
+				Name:      "validate --soft flag isn't usable",
+				Args:      args("purge --all --service-id 123 --soft --token 456"),
+				WantError: "purge-all requests cannot be done in soft mode (--soft) and will always immediately invalidate all cached content associated with the service",
+			},
 		},
 		{
-			Name: "validate PurgeAll API error",
-			API: mock.API{
-				PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
-					return nil, testutil.Err
+			TestScenario: testutil.TestScenario{
+				Name: "validate --yes requires --service-id",
+				API: mock.API{
+					ListServicesFn: func(i *fastly.ListServicesInput) ([]*fastly.Service, error) {
+						return []*fastly.Service{{ID: "123", Name: "Foo"}}, nil
+					},
 				},
+				Args:      args("purge --all --service-name Foo --yes --token 456"),
+				WantError: "the --yes flag requires the service to be given explicitly via --service-id",
 			},
-			Args:      args("purge --all --service-id 123 --token 456"),
-			WantError: testutil.Err.Error(),
 		},
 		{
-			Name: "validate PurgeAll API success",
-			API: mock.API{
-				PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
-					return &fastly.Purge{
-						Status: "ok",
-					}, nil
+			TestScenario: testutil.TestScenario{
+				Name: "validate GetServiceDetails API error",
+				API: mock.API{
+					GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+						return nil, testutil.Err
+					},
 				},
+				Args:      args("purge --all --service-id 123 --yes --token 456"),
+				WantError: "error fetching service details: " + testutil.Err.Error(),
 			},
-			Args:       args("purge --all --service-id 123 --token 456"),
-			WantOutput: "Purge all status: ok",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate confirmation is required when non-interactive",
+				API: mock.API{
+					GetServiceDetailsFn: getServiceDetailsOK,
+					ListDomainsFn:       listDomainsOK,
+				},
+				Args:      args("purge --all --service-id 123 --non-interactive --token 456"),
+				WantError: "purge-all requires confirmation",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate mismatched service name aborts",
+				API: mock.API{
+					GetServiceDetailsFn: getServiceDetailsOK,
+					ListDomainsFn:       listDomainsOK,
+				},
+				Args:      args("purge --all --service-id 123 --token 456"),
+				WantError: "purge-all aborted: the service name did not match",
+			},
+			stdin: "Bar",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate PurgeAll API error",
+				API: mock.API{
+					GetServiceDetailsFn: getServiceDetailsOK,
+					ListDomainsFn:       listDomainsOK,
+					PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+						return nil, testutil.Err
+					},
+				},
+				Args:      args("purge --all --service-id 123 --yes --token 456"),
+				WantError: testutil.Err.Error(),
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate PurgeAll API success with --yes",
+				API: mock.API{
+					GetServiceDetailsFn: getServiceDetailsOK,
+					ListDomainsFn:       listDomainsOK,
+					PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+						return &fastly.Purge{
+							Status: "ok",
+						}, nil
+					},
+				},
+				Args: args("purge --all --service-id 123 --yes --token 456"),
+				WantOutputs: []string{
+					"This will immediately invalidate ALL cached content for service 'Foo' (123).",
+					"Domains affected (2):",
+					"www.example.com",
+					"api.example.com",
+					"Purge all status: ok",
+				},
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate PurgeAll API success with confirmation",
+				API: mock.API{
+					GetServiceDetailsFn: getServiceDetailsOK,
+					ListDomainsFn:       listDomainsOK,
+					PurgeAllFn: func(i *fastly.PurgeAllInput) (*fastly.Purge, error) {
+						return &fastly.Purge{
+							Status: "ok",
+						}, nil
+					},
+				},
+				Args: args("purge --all --service-id 123 --token 456"),
+				WantOutputs: []string{
+					"Type the service name (Foo) to confirm:",
+					"Purge all status: ok",
+				},
+			},
+			stdin: "Foo",
 		},
 	}
 
@@ -59,9 +158,63 @@ func TestPurgeAll(t *testing.T) {
 			var stdout bytes.Buffer
 			opts := testutil.NewRunOpts(testcase.Args, &stdout)
 			opts.APIClient = mock.APIClient(testcase.API)
+			opts.Stdin = strings.NewReader(testcase.stdin)
 			err := app.Run(opts)
 			testutil.AssertErrorContains(t, err, testcase.WantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestPurgeSoftProfileDefault(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		softPurge  bool
+		wantOutput string
+	}{
+		{
+			args:       args("purge --key foobar --service-id 123 --token 456"),
+			wantOutput: "Purged key: foobar (soft: false)",
+		},
+		{
+			args:       args("purge --key foobar --service-id 123 --token 456"),
+			softPurge:  true,
+			wantOutput: "Purged key: foobar (soft: true)",
+		},
+		{
+			args:       args("purge --key foobar --service-id 123 --hard --token 456"),
+			softPurge:  true,
+			wantOutput: "Purged key: foobar (soft: false)",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(mock.API{
+				PurgeKeyFn: func(i *fastly.PurgeKeyInput) (*fastly.Purge, error) {
+					return &fastly.Purge{
+						Status: "ok",
+						ID:     "123",
+					}, nil
+				},
+			})
+			opts.ConfigFile.Profiles = config.Profiles{
+				"user": &config.Profile{
+					Default:   true,
+					Token:     "456",
+					SoftPurge: testcase.softPurge,
+				},
+			}
+			err := app.Run(opts)
+			testutil.AssertNoError(t, err)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
 		})
 	}
 }
@@ -105,7 +258,7 @@ func TestPurgeKeys(t *testing.T) {
 				},
 			},
 			Args:       args("purge --file ./testdata/keys --service-id 123 --token 456"),
-			WantOutput: "KEY  ID\nbar  456\nbaz  789\nfoo  123\n",
+			WantOutput: "TYPE  ITEM  ID   STATUS\nkey   foo   123  purged\nkey   bar   456  purged\nkey   baz   789  purged\n",
 		},
 	}
 
@@ -123,6 +276,84 @@ func TestPurgeKeys(t *testing.T) {
 	}
 }
 
+func TestPurgeFiles(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	var keys []string
+	for i := 0; i < 300; i++ {
+		keys = append(keys, fmt.Sprintf("key%d", i))
+	}
+	if err := os.WriteFile(keysFile, []byte(strings.Join(keys, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu      sync.Mutex
+		batches []int
+		urls    []string
+	)
+	api := mock.API{
+		PurgeFn: func(i *fastly.PurgeInput) (*fastly.Purge, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			urls = append(urls, i.URL)
+			if i.URL == "https://example.com/b" {
+				return nil, testutil.Err
+			}
+			return &fastly.Purge{Status: "ok", ID: "url-id"}, nil
+		},
+		PurgeKeysFn: func(i *fastly.PurgeKeysInput) (map[string]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, len(i.Keys))
+			m := make(map[string]string, len(i.Keys))
+			for _, k := range i.Keys {
+				m[k] = k + "-id"
+			}
+			return m, nil
+		},
+	}
+	run := func(args string) (string, error) {
+		mu.Lock()
+		batches, urls = nil, nil
+		mu.Unlock()
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+		opts.APIClient = mock.APIClient(api)
+		err := app.Run(opts)
+		return stdout.String(), err
+	}
+
+	t.Run("keys are batched", func(t *testing.T) {
+		stdout, err := run("purge --keys-file " + keysFile + " --service-id 123 --token 456 --concurrency 2")
+		testutil.AssertNoError(t, err)
+		sort.Ints(batches)
+		testutil.AssertEqual(t, []int{44, 256}, batches)
+		testutil.AssertStringContains(t, stdout, "key   key299  key299-id  purged")
+		testutil.AssertStringContains(t, stdout, "Purged 0 URLs and 300 surrogate keys (soft: false)")
+	})
+
+	t.Run("urls", func(t *testing.T) {
+		stdout, err := run("purge --file ./testdata/urls --service-id 123 --token 456")
+		testutil.AssertErrorContains(t, err, "failed to purge 1 of 2 items: "+testutil.Err.Error())
+		sort.Strings(urls)
+		testutil.AssertEqual(t, []string{"https://example.com/a", "https://example.com/b"}, urls)
+		testutil.AssertStringContains(t, stdout, "url   https://example.com/a  url-id  purged")
+		testutil.AssertStringContains(t, stdout, "url   https://example.com/b          failed: "+testutil.Err.Error())
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		stdout, err := run("purge --file ./testdata/mixed --service-id 123 --token 456 --soft")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, []int{1}, batches)
+		testutil.AssertStringContains(t, stdout, "Purged 1 URLs and 1 surrogate keys (soft: true)")
+	})
+
+	t.Run("validate --concurrency", func(t *testing.T) {
+		_, err := run("purge --file ./testdata/mixed --service-id 123 --token 456 --concurrency 0")
+		testutil.AssertErrorContains(t, err, "--concurrency must be at least 1")
+	})
+}
+
 // assertKeys validates that the --file flag is parsed correctly. It does this
 // by ensuring the internal logic has parsed the given file and generated the
 // correct []string type.
@@ -262,3 +493,81 @@ func TestPurgeURL(t *testing.T) {
 		})
 	}
 }
+
+func getServiceDetailsOK(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+	return &fastly.ServiceDetail{
+		ID:   i.ID,
+		Name: "Foo",
+		ActiveVersion: fastly.Version{
+			Number: 1,
+		},
+	}, nil
+}
+
+func listDomainsOK(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+	return []*fastly.Domain{
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "www.example.com"},
+		{ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion, Name: "api.example.com"},
+	}, nil
+}
+
+func TestPurgeMultipleKeys(t *testing.T) {
+	var keys []string
+	api := mock.API{
+		PurgeKeysFn: func(i *fastly.PurgeKeysInput) (map[string]string, error) {
+			keys = i.Keys
+			if !i.Soft {
+				return nil, testutil.Err
+			}
+			return map[string]string{"foo": "123", "bar": "456"}, nil
+		},
+	}
+
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("purge --key foo --key bar --soft --service-id 123 --token 456"), &stdout)
+	opts.APIClient = mock.APIClient(api)
+	err := app.Run(opts)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{"foo", "bar"}, keys)
+	testutil.AssertStringContains(t, stdout.String(), "key   bar   456  purged")
+	testutil.AssertStringContains(t, stdout.String(), "Purged 0 URLs and 2 surrogate keys (soft: true)")
+}
+
+func TestPurgeVerify(t *testing.T) {
+	var age string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Fastly-Debug") != "1" {
+			t.Errorf("want the Fastly-Debug header, have %v", r.Header)
+		}
+		w.Header().Set("Age", age)
+		w.Header().Set("X-Cache", "MISS, HIT")
+	}))
+	defer srv.Close()
+
+	api := mock.API{
+		PurgeKeyFn: func(i *fastly.PurgeKeyInput) (*fastly.Purge, error) {
+			return &fastly.Purge{Status: "ok", ID: "123"}, nil
+		},
+	}
+	run := func(args string) (string, error) {
+		var stdout bytes.Buffer
+		opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+		opts.APIClient = mock.APIClient(api)
+		err := app.Run(opts)
+		return stdout.String(), err
+	}
+
+	age = "0"
+	stdout, err := run("purge --key foo --service-id 123 --token 456 --verify-url " + srv.URL)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout, srv.URL+"  MISS, HIT  0    purged")
+
+	age = "3600"
+	stdout, err = run("purge --key foo --service-id 123 --token 456 --verify-url " + srv.URL)
+	testutil.AssertErrorContains(t, err, "failed to verify the purge of 1 of 1 URLs")
+	testutil.AssertStringContains(t, stdout, "cached before the purge")
+
+	stdout, err = run("purge --key foo --soft --service-id 123 --token 456 --verify-url " + srv.URL)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout, "1 of 1 URLs were served from the cache")
+}
@@ -1,12 +1,9 @@
 package purge
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"sort"
+	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/errors"
@@ -26,8 +23,11 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 
 	// optional
 	c.CmdClause.Flag("all", "Purge everything from a service").BoolVar(&c.all)
-	c.CmdClause.Flag("file", "Purge a service of a newline delimited list of Surrogate Keys").StringVar(&c.file)
-	c.CmdClause.Flag("key", "Purge a service of objects tagged with a Surrogate Key").StringVar(&c.key)
+	c.CmdClause.Flag("concurrency", "The maximum number of purge requests made at once by --file, --keys-file and multiple --key flags").Default("10").IntVar(&c.concurrency)
+	c.CmdClause.Flag("file", "Purge a newline delimited list of URLs (starting with http:// or https://) and Surrogate Keys of a service").StringVar(&c.file)
+	c.CmdClause.Flag("hard", "Force a hard purge, overriding the profile's `soft_purge` setting").BoolVar(&c.hard)
+	c.CmdClause.Flag("key", "Purge a service of objects tagged with a Surrogate Key (set flag once per key, multiple keys are purged in batches)").StringsVar(&c.keys)
+	c.CmdClause.Flag("keys-file", "Purge a service of a newline delimited list of Surrogate Keys").StringVar(&c.keysFile)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
 		Description: cmd.FlagServiceIDDesc,
@@ -40,8 +40,10 @@ func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Roo
 		Description: cmd.FlagServiceDesc,
 		Dst:         &c.serviceName.Value,
 	})
-	c.CmdClause.Flag("soft", "A 'soft' purge marks affected objects as stale rather than making them inaccessible").BoolVar(&c.soft)
+	c.CmdClause.Flag("soft", "A 'soft' purge marks affected objects as stale rather than making them inaccessible (default: the profile's `soft_purge` setting)").Action(c.soft.Set).BoolVar(&c.soft.Value)
 	c.CmdClause.Flag("url", "Purge an individual URL").StringVar(&c.url)
+	c.CmdClause.Flag("verify-url", "After purging, request a URL (e.g. one tagged with the purged keys) and check its cache state headers show it wasn't served from the cache (set flag once per URL)").StringsVar(&c.verifyURLs)
+	c.CmdClause.Flag("yes", "Skip the purge-all confirmation prompt (requires --service-id)").BoolVar(&c.yes)
 
 	return &c
 }
@@ -52,16 +54,25 @@ type RootCommand struct {
 	cmd.Base
 
 	all         bool
+	concurrency int
 	file        string
-	key         string
+	hard        bool
+	keys        []string
+	keysFile    string
 	manifest    manifest.Data
 	serviceName cmd.OptionalServiceNameID
-	soft        bool
+	soft        cmd.OptionalBool
 	url         string
+	verifyURLs  []string
+	yes         bool
+
+	// purgeStart is when the purge was requested, to tell the objects cached
+	// since then apart from those the purge didn't invalidate.
+	purgeStart time.Time
 }
 
 // Exec implements the command interface.
-func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
 	_, s := c.Globals.Token()
 	if s == lookup.SourceUndefined {
 		return errors.ErrNoToken
@@ -83,13 +94,27 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if c.all {
-		if c.soft {
+		if c.soft.WasSet && c.soft.Value {
 			return errors.RemediationError{
 				Inner:       fmt.Errorf("purge-all requests cannot be done in soft mode (--soft) and will always immediately invalidate all cached content associated with the service"),
 				Remediation: "The --soft flag should not be used with --all so retry command without it.",
 			}
 		}
-		err := c.purgeAll(serviceID, out)
+		if c.yes && (source != manifest.SourceFlag || flag != "--service-id") {
+			return errors.RemediationError{
+				Inner:       fmt.Errorf("the --yes flag requires the service to be given explicitly via --service-id"),
+				Remediation: "Retry the command with --service-id, or omit --yes to confirm interactively.",
+			}
+		}
+		err := c.confirmPurgeAll(serviceID, in, out)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return err
+		}
+		c.purgeStart = time.Now()
+		err = c.purgeAll(serviceID, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
@@ -97,31 +122,45 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 			return err
 		}
-		return nil
+		return c.verify(out)
+	}
+
+	if c.hard && c.soft.Value {
+		return fmt.Errorf("error parsing arguments: --hard and --soft are mutually exclusive")
+	}
+	if !c.soft.WasSet && !c.hard {
+		if _, p := c.Globals.Profile(); p != nil {
+			c.soft.Value = p.SoftPurge
+		}
 	}
 
-	if c.file != "" {
-		err := c.purgeKeys(serviceID, out)
+	c.purgeStart = time.Now()
+
+	if c.file != "" || c.keysFile != "" || len(c.keys) > 1 {
+		err := c.purgeItems(serviceID, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Service ID": serviceID,
-				"File":       c.file,
+				"Service ID":  serviceID,
+				"File":        c.file,
+				"Keys":        c.keys,
+				"Keys File":   c.keysFile,
+				"Concurrency": c.concurrency,
 			})
 			return err
 		}
-		return nil
+		return c.verify(out)
 	}
 
-	if c.key != "" {
-		err := c.purgeKey(serviceID, out)
+	if len(c.keys) == 1 {
+		err := c.purgeKey(serviceID, c.keys[0], out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
-				"Key":        c.key,
+				"Key":        c.keys[0],
 			})
 			return err
 		}
-		return nil
+		return c.verify(out)
 	}
 
 	if c.url != "" {
@@ -132,126 +171,107 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 			return err
 		}
-		return nil
+		return c.verify(out)
 	}
 
 	return nil
 }
 
-func (c *RootCommand) purgeAll(serviceID string, out io.Writer) error {
-	p, err := c.Globals.APIClient.PurgeAll(&fastly.PurgeAllInput{
-		ServiceID: serviceID,
+// confirmPurgeAll displays the service that will be purged, along with the
+// domains it serves, and requires the user to confirm by typing the service
+// name (unless --yes was provided).
+func (c *RootCommand) confirmPurgeAll(serviceID string, in io.Reader, out io.Writer) error {
+	s, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{
+		ID: serviceID,
 	})
 	if err != nil {
-		c.Globals.ErrLog.AddWithContext(err, map[string]any{
-			"Service ID": serviceID,
+		return fmt.Errorf("error fetching service details: %w", err)
+	}
+
+	text.Warning(out, "This will immediately invalidate ALL cached content for service '%s' (%s).", s.Name, serviceID)
+
+	// NOTE: The API doesn't expose a count of cached objects, so the domains
+	// served by the active version are displayed as an indication of impact.
+	if s.ActiveVersion.Number > 0 {
+		domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+			ServiceID:      serviceID,
+			ServiceVersion: s.ActiveVersion.Number,
 		})
-		return err
+		if err == nil {
+			text.Break(out)
+			text.Output(out, "Domains affected (%d):", len(domains))
+			for _, d := range domains {
+				text.Output(out, "  %s", d.Name)
+			}
+		}
+	}
+	text.Break(out)
+
+	if c.yes {
+		return nil
+	}
+	if c.Globals.Flags.NonInteractive || c.Globals.Flags.AutoYes {
+		return errors.RemediationError{
+			Inner:       fmt.Errorf("purge-all requires confirmation"),
+			Remediation: "Use --yes together with --service-id to purge all content without a prompt.",
+		}
 	}
-	text.Success(out, "Purge all status: %s", p.Status)
-	return nil
-}
 
-func (c *RootCommand) purgeKeys(serviceID string, out io.Writer) error {
-	keys, err := populateKeys(c.file, c.Globals.ErrLog)
+	name, err := text.Input(out, text.BoldYellow(fmt.Sprintf("Type the service name (%s) to confirm: ", s.Name)), in)
 	if err != nil {
-		c.Globals.ErrLog.AddWithContext(err, map[string]any{
-			"Service ID": serviceID,
-		})
-		return err
+		return fmt.Errorf("error reading input: %w", err)
+	}
+	if name != s.Name {
+		return fmt.Errorf("purge-all aborted: the service name did not match")
 	}
+	text.Break(out)
+	return nil
+}
 
-	m, err := c.Globals.APIClient.PurgeKeys(&fastly.PurgeKeysInput{
+func (c *RootCommand) purgeAll(serviceID string, out io.Writer) error {
+	p, err := c.Globals.APIClient.PurgeAll(&fastly.PurgeAllInput{
 		ServiceID: serviceID,
-		Keys:      keys,
-		Soft:      c.soft,
 	})
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID": serviceID,
-			"Keys":       keys,
-			"Soft":       c.soft,
 		})
 		return err
 	}
-
-	sortedKeys := make([]string, 0, len(m))
-	for k := range m {
-		sortedKeys = append(sortedKeys, k)
-	}
-	sort.Strings(sortedKeys)
-
-	t := text.NewTable(out)
-	t.AddHeader("KEY", "ID")
-	for _, k := range sortedKeys {
-		t.AddLine(k, m[k])
-	}
-	t.Print()
-
+	text.Success(out, "Purge all status: %s", p.Status)
 	return nil
 }
 
-func (c *RootCommand) purgeKey(serviceID string, out io.Writer) error {
+func (c *RootCommand) purgeKey(serviceID, key string, out io.Writer) error {
 	p, err := c.Globals.APIClient.PurgeKey(&fastly.PurgeKeyInput{
 		ServiceID: serviceID,
-		Key:       c.key,
-		Soft:      c.soft,
+		Key:       key,
+		Soft:      c.soft.Value,
 	})
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID": serviceID,
-			"Key":        c.key,
-			"Soft":       c.soft,
+			"Key":        key,
+			"Soft":       c.soft.Value,
 		})
 		return err
 	}
-	text.Success(out, "Purged key: %s (soft: %t). Status: %s, ID: %s", c.key, c.soft, p.Status, p.ID)
+	text.Success(out, "Purged key: %s (soft: %t). Status: %s, ID: %s", key, c.soft.Value, p.Status, p.ID)
 	return nil
 }
 
 func (c *RootCommand) purgeURL(out io.Writer) error {
 	p, err := c.Globals.APIClient.Purge(&fastly.PurgeInput{
 		URL:  c.url,
-		Soft: c.soft,
+		Soft: c.soft.Value,
 	})
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"URL":  c.url,
-			"Soft": c.soft,
+			"Soft": c.soft.Value,
 		})
 		return err
 	}
-	text.Success(out, "Purged URL: %s (soft: %t). Status: %s, ID: %s", c.url, c.soft, p.Status, p.ID)
+	text.Success(out, "Purged URL: %s (soft: %t). Status: %s, ID: %s", c.url, c.soft.Value, p.Status, p.ID)
 	return nil
 }
-
-// populateKeys opens the given file path, initializes a scanner, and appends
-// each line of the file (expected to be a surrogate key) to a slice.
-func populateKeys(fpath string, errLog errors.LogInterface) (keys []string, err error) {
-	var (
-		file io.Reader
-		path string
-	)
-
-	if path, err = filepath.Abs(fpath); err == nil {
-		if _, err = os.Stat(path); err == nil {
-			// gosec flagged this:
-			// G304 (CWE-22): Potential file inclusion via variable
-			// Disabling as we trust the source of the fpath variable.
-			/* #nosec */
-			if file, err = os.Open(path); err == nil {
-				scanner := bufio.NewScanner(file)
-				for scanner.Scan() {
-					keys = append(keys, scanner.Text())
-				}
-				err = scanner.Err()
-			}
-		}
-	}
-
-	if err != nil {
-		errLog.Add(err)
-		return nil, err
-	}
-	return keys, nil
-}
@@ -0,0 +1,131 @@
+package purge
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/useragent"
+)
+
+// verifyAttempts is how many times a URL is requested before it's reported as
+// still cached, as a purge takes a moment to reach every POP.
+const verifyAttempts = 3
+
+// verifyInterval is the time between the requests of a URL.
+const verifyInterval = 500 * time.Millisecond
+
+// cacheCheck is the cache state of a --verify-url.
+type cacheCheck struct {
+	url    string
+	xCache string
+	age    string
+	cached bool
+	err    error
+}
+
+// verify requests each --verify-url, reporting whether it was served from an
+// object cached before the purge.
+//
+// A soft purge marks objects as stale, which may still be served while they're
+// revalidated, so for a soft purge a URL still being cached is a warning
+// rather than an error.
+func (c *RootCommand) verify(out io.Writer) error {
+	if len(c.verifyURLs) == 0 {
+		return nil
+	}
+
+	var failed int
+	checks := make([]cacheCheck, 0, len(c.verifyURLs))
+	for _, u := range c.verifyURLs {
+		var check cacheCheck
+		for attempt := 1; attempt <= verifyAttempts; attempt++ {
+			check = c.checkCache(u)
+			if check.err == nil && !check.cached {
+				break
+			}
+			if attempt < verifyAttempts {
+				time.Sleep(verifyInterval)
+			}
+		}
+		if check.err != nil || check.cached {
+			failed++
+		}
+		checks = append(checks, check)
+	}
+
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("URL", "X-CACHE", "AGE", "RESULT")
+	for _, check := range checks {
+		result := "purged"
+		switch {
+		case check.err != nil:
+			result = fmt.Sprintf("failed: %s", check.err)
+		case check.cached:
+			result = "cached before the purge"
+		}
+		t.AddLine(check.url, check.xCache, check.age, result)
+	}
+	t.Print()
+
+	if failed == 0 {
+		return nil
+	}
+	if c.soft.Value {
+		text.Warning(out, "%d of %d URLs were served from the cache, which is expected of stale objects while they're revalidated after a soft purge.", failed, len(checks))
+		return nil
+	}
+	return fmt.Errorf("failed to verify the purge of %d of %d URLs", failed, len(checks))
+}
+
+// checkCache requests a URL and checks whether its response is an object
+// cached before the purge started.
+func (c *RootCommand) checkCache(u string) cacheCheck {
+	check := cacheCheck{url: u}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	req.Header.Set("User-Agent", useragent.Name)
+	// Fastly-Debug exposes the cache state headers of services that hide them.
+	req.Header.Set("Fastly-Debug", "1")
+
+	resp, err := c.Globals.HTTPClient.Do(req)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	check.xCache = resp.Header.Get("X-Cache")
+	check.age = resp.Header.Get("Age")
+	check.cached = cachedBefore(resp.Header, time.Since(c.purgeStart))
+	return check
+}
+
+// cachedBefore reports whether a response was served from an object cached for
+// longer than since.
+//
+// The Age header says how long the object has been cached, so an object
+// cached again since the purge isn't mistaken for one the purge missed. If
+// it's missing the X-Cache header of the edge, the last of the values of a
+// shielded request, is used instead.
+func cachedBefore(h http.Header, since time.Duration) bool {
+	if age, err := strconv.Atoi(h.Get("Age")); err == nil {
+		// The Age header is rounded down to the second.
+		return time.Duration(age)*time.Second > since+time.Second
+	}
+	xCache := h.Get("X-Cache")
+	if i := strings.LastIndex(xCache, ","); i >= 0 {
+		xCache = xCache[i+1:]
+	}
+	return strings.Contains(strings.ToUpper(xCache), "HIT")
+}
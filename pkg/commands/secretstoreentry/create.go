@@ -2,12 +2,16 @@ package secretstoreentry
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 
+	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/cmd"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
@@ -22,6 +26,17 @@ const (
 	maxSecretLen = maxSecretKiB * 1024
 )
 
+// Charsets are the alphabets a --generate secret can be drawn from.
+var Charsets = map[string]string{
+	"alphanumeric": "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	"base64":       "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/",
+	"base64url":    "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_",
+	"hex":          "0123456789abcdef",
+}
+
+// charsetNames is the sorted list of Charsets keys, for the --charset flag.
+var charsetNames = []string{"alphanumeric", "base64", "base64url", "hex"}
+
 // The signing key is a public key that is used to sign client keys.
 // It's meant to be a long-lived key and infrequently (if ever) rotated.
 // Hardcoding it in the CLI gives us the benefit of distributing it via
@@ -56,8 +71,12 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 	c.RegisterFlag(cmd.StoreIDFlag(&c.Input.ID))  // --store-id
 
 	// Optional.
-	c.RegisterFlag(secretFileFlag(&c.secretFile))       // --file
-	c.RegisterFlagBool(c.JSONFlag())                    // --json
+	c.CmdClause.Flag("charset", "Characters a --generate secret is drawn from").Default("base64").HintOptions(charsetNames...).EnumVar(&c.charset, charsetNames...)
+	c.RegisterFlag(secretFileFlag(&c.secretFile))      // --file
+	c.RegisterFlagInt(secretGenerateFlag(&c.generate)) // --generate
+	c.CmdClause.Flag("generate-file", "Write a --generate secret to this file (created with 0600 permissions) instead of printing it").PlaceHolder("PATH").StringVar(&c.generateFile)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("recreate-if-exists", "Replace the value of the secret if it already exists, rather than failing").BoolVar(&c.recreateIfExists)
 	c.RegisterFlagBool(secretStdinFlag(&c.secretSTDIN)) // --stdin
 
 	return &c
@@ -68,10 +87,14 @@ type CreateCommand struct {
 	cmd.Base
 	cmd.JSONOutput
 
-	Input       fastly.CreateSecretInput
-	manifest    manifest.Data
-	secretFile  string
-	secretSTDIN bool
+	Input            fastly.CreateSecretInput
+	charset          string
+	generate         int
+	generateFile     string
+	manifest         manifest.Data
+	recreateIfExists bool
+	secretFile       string
+	secretSTDIN      bool
 }
 
 var errMultipleSecretValue = fsterr.RemediationError{
@@ -79,6 +102,21 @@ var errMultipleSecretValue = fsterr.RemediationError{
 	Remediation: "Use one of --file or --stdin flag",
 }
 
+var errMultipleSecretSource = fsterr.RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination, --generate with --file or --stdin"),
+	Remediation: "Use one of --generate, --file or --stdin flag",
+}
+
+var errGenerateFileWithoutGenerate = fsterr.RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination, --generate-file without --generate"),
+	Remediation: "Use --generate to generate a secret value to write to the file",
+}
+
+var errGenerateJSON = fsterr.RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination, --generate and --json"),
+	Remediation: "Use --generate-file so the generated secret isn't lost, as the JSON output doesn't include it",
+}
+
 var errNoSTDINData = fsterr.RemediationError{
 	Inner:       fmt.Errorf("unable to read from STDIN"),
 	Remediation: "Provide data to STDIN, or use --file to read from a file",
@@ -97,9 +135,41 @@ func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 	if c.secretFile != "" && c.secretSTDIN {
 		return errMultipleSecretValue
 	}
+	if c.generate != 0 && (c.secretFile != "" || c.secretSTDIN) {
+		return errMultipleSecretSource
+	}
+	if c.generateFile != "" && c.generate == 0 {
+		return errGenerateFileWithoutGenerate
+	}
+	if c.generate != 0 && c.generateFile == "" && c.JSONOutput.Enabled {
+		return errGenerateJSON
+	}
 
-	// Read secret's value: either from STDIN, a file, or prompt.
+	// Read secret's value: either generated, from STDIN, a file, or prompt.
 	switch {
+	case c.generate != 0:
+		if c.generate < 0 || c.generate > maxSecretLen {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --generate length: %d", c.generate),
+				Remediation: fmt.Sprintf("Provide a length between 1 and %d characters", maxSecretLen),
+			}
+		}
+		secret, err := GenerateSecret(c.generate, Charsets[c.charset])
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		c.Input.Secret = secret
+
+		// The file is written before the secret is created so that a failure to
+		// write it doesn't leave a stored secret whose value nobody knows.
+		if c.generateFile != "" {
+			if err := writeSecretFile(c.generateFile, secret); err != nil {
+				c.Globals.ErrLog.Add(err)
+				return err
+			}
+		}
+
 	case c.secretSTDIN:
 		// Determine if 'in' has data available.
 		if in == nil || text.IsTTY(in) {
@@ -129,52 +199,128 @@ func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 	if len(c.Input.Secret) > maxSecretLen {
 		return errMaxSecretLength
 	}
+	generated := string(c.Input.Secret)
 
-	ck, err := c.Globals.APIClient.CreateClientKey()
+	o, recreated, err := c.create()
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
+		if c.generateFile != "" {
+			_ = os.Remove(c.generateFile)
+		}
 		return err
 	}
 
-	sk, err := c.Globals.APIClient.GetSigningKey()
-	if err != nil {
-		c.Globals.ErrLog.Add(err)
+	// TODO: Use this approach across the code base.
+	var v any = o
+	if c.recreateIfExists {
+		v = struct {
+			*fastly.Secret
+			Recreated bool `json:"recreated"`
+		}{o, recreated}
+	}
+	if ok, err := c.WriteJSON(out, v); ok {
 		return err
 	}
 
-	if !bytes.Equal(sk, signingKey) && os.Getenv("FASTLY_USE_API_SIGNING_KEY") == "" {
-		err := fmt.Errorf("API signing key does not match expected value")
-		c.Globals.ErrLog.Add(err)
-		return err
+	action := "Created"
+	if recreated {
+		action = "Recreated"
 	}
+	text.Success(out, "%s secret %s in store %s (digest %s)", action, o.Name, c.Input.ID, hex.EncodeToString(o.Digest))
 
-	if !ck.ValidateSignature(sk) {
-		err := fmt.Errorf("unable to validate signature of client key")
-		c.Globals.ErrLog.Add(err)
-		return err
+	if c.generate != 0 {
+		text.Break(out)
+		if c.generateFile != "" {
+			text.Info(out, "The generated secret was written to %s", c.generateFile)
+		} else {
+			text.Info(out, "The generated secret is shown below. It can't be retrieved again, so store it somewhere safe now.")
+			text.Break(out)
+			fmt.Fprintln(out, generated)
+		}
 	}
 
-	wrapped, err := ck.Encrypt(c.Input.Secret)
+	return nil
+}
+
+// create encrypts the secret and creates it in the store, recreating it if it
+// already exists and --recreate-if-exists is set.
+func (c *CreateCommand) create() (o *fastly.Secret, recreated bool, err error) {
+	wrapped, clientKey, err := EncryptSecret(c.Globals.APIClient, c.Input.Secret)
 	if err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
+		return nil, false, err
 	}
 
 	c.Input.Secret = wrapped
-	c.Input.ClientKey = ck.PublicKey
+	c.Input.ClientKey = clientKey
+
+	o, err = c.Globals.APIClient.CreateSecret(&c.Input)
+	if err == nil || !c.recreateIfExists || !isConflict(err) {
+		return o, false, err
+	}
+	o, err = recreateSecret(c.Globals, &c.Input)
+	return o, err == nil, err
+}
+
+// GenerateSecret returns n characters chosen uniformly at random, using a
+// cryptographically secure source, from the charset.
+func GenerateSecret(n int, charset string) ([]byte, error) {
+	size := big.NewInt(int64(len(charset)))
+	secret := make([]byte, n)
+	for i := range secret {
+		r, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return nil, fmt.Errorf("error generating secret: %w", err)
+		}
+		secret[i] = charset[r.Int64()]
+	}
+	return secret, nil
+}
 
-	o, err := c.Globals.APIClient.CreateSecret(&c.Input)
+// writeSecretFile writes the secret to a new file only the user can read.
+// An existing file is never overwritten, as it might hold another secret.
+func writeSecretFile(path string, secret []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
 	if err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
+		if errors.Is(err, os.ErrExist) {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("error writing generated secret: %s already exists", path),
+				Remediation: "Provide a path to a file that doesn't exist yet for --generate-file",
+			}
+		}
+		return fmt.Errorf("error writing generated secret: %w", err)
+	}
+	if _, err := f.Write(append(secret, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing generated secret: %w", err)
 	}
+	return f.Close()
+}
 
-	// TODO: Use this approach across the code base.
-	if ok, err := c.WriteJSON(out, o); ok {
-		return err
+// EncryptSecret encrypts the secret with a new client key, having validated
+// the key's signature, and returns the encrypted secret and the client's
+// public key to create the secret with.
+func EncryptSecret(client api.Interface, secret []byte) (wrapped, clientKey []byte, err error) {
+	ck, err := client.CreateClientKey()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	text.Success(out, "Created secret %s in store %s (digest %s)", o.Name, c.Input.ID, hex.EncodeToString(o.Digest))
+	sk, err := client.GetSigningKey()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return nil
+	if !bytes.Equal(sk, signingKey) && os.Getenv("FASTLY_USE_API_SIGNING_KEY") == "" {
+		return nil, nil, fmt.Errorf("API signing key does not match expected value")
+	}
+
+	if !ck.ValidateSignature(sk) {
+		return nil, nil, fmt.Errorf("unable to validate signature of client key")
+	}
+
+	wrapped, err = ck.Encrypt(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wrapped, ck.PublicKey, nil
 }
@@ -32,3 +32,12 @@ func secretStdinFlag(dst *bool) cmd.BoolFlagOpts {
 		Required:    false,
 	}
 }
+
+func secretGenerateFlag(dst *int) cmd.IntFlagOpts {
+	return cmd.IntFlagOpts{
+		Name:        "generate",
+		Description: "Generate a random secret value of this many characters instead of prompting (see --charset)",
+		Dst:         dst,
+		Required:    false,
+	}
+}
@@ -0,0 +1,76 @@
+package secretstoreentry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/useragent"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// isConflict reports whether err is the API's response to creating a secret
+// that already exists.
+func isConflict(err error) bool {
+	var httpErr *fastly.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict
+}
+
+// recreateSecret replaces the value of an existing secret, using the PATCH
+// method of the secrets endpoint, which go-fastly doesn't support.
+//
+// NOTE: The secret of the input must already be encrypted with its client key.
+func recreateSecret(g *global.Data, i *fastly.CreateSecretInput) (*fastly.Secret, error) {
+	token, s := g.Token()
+	if s == lookup.SourceUndefined {
+		return nil, fsterr.ErrNoToken
+	}
+	endpoint, _ := g.Endpoint()
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(struct {
+		Name      string `json:"name"`
+		Secret    []byte `json:"secret"`
+		ClientKey []byte `json:"client_key,omitempty"`
+	}{
+		Name:      i.Name,
+		Secret:    i.Secret,
+		ClientKey: i.ClientKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/resources/stores/secret/" + url.PathEscape(i.ID) + "/secrets"
+	req, err := http.NewRequest(http.MethodPatch, strings.TrimSuffix(endpoint, "/")+path, &body)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing API request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", useragent.Name)
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing API request: %w", err)
+	}
+	defer resp.Body.Close() // #nosec G307
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("error recreating secret %s: %s", i.Name, resp.Status)
+	}
+
+	var o fastly.Secret
+	if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+		return nil, fmt.Errorf("error decoding API response: %w", err)
+	}
+	return &o, nil
+}
@@ -5,11 +5,15 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -93,6 +97,26 @@ func TestCreateSecretCommand(t *testing.T) {
 			args:      fmt.Sprintf("create --store-id %s --name %s --stdin", storeID, secretName),
 			wantError: "unable to read from STDIN",
 		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --generate 32 --stdin", storeID, secretName),
+			wantError: "invalid flag combination, --generate with --file or --stdin",
+		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --generate-file %s", storeID, secretName, secretFile),
+			wantError: "invalid flag combination, --generate-file without --generate",
+		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --generate 32 --json", storeID, secretName),
+			wantError: "invalid flag combination, --generate and --json",
+		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --generate 65537", storeID, secretName),
+			wantError: "invalid --generate length: 65537",
+		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --generate 32 --generate-file %s", storeID, secretName, secretFile),
+			wantError: "error writing generated secret: " + secretFile + " already exists",
+		},
 		// Read from STDIN.
 		{
 			args:  fmt.Sprintf("create --store-id %s --name %s --stdin", storeID, secretName),
@@ -197,6 +221,231 @@ func TestCreateSecretCommand(t *testing.T) {
 	}
 }
 
+func TestCreateSecretCommandGenerate(t *testing.T) {
+	const (
+		storeID    = "store123"
+		secretName = "testsecret"
+	)
+
+	ckPub, ckPriv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skPub, skPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ck := &fastly.ClientKey{
+		PublicKey: ckPub[:],
+		Signature: ed25519.Sign(skPriv, ckPub[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	t.Setenv("FASTLY_USE_API_SIGNING_KEY", "1")
+
+	for _, testcase := range []struct {
+		name         string
+		args         string
+		charset      string
+		length       int
+		createErr    error
+		generateFile bool
+		wantError    string
+	}{
+		{
+			name:    "printed",
+			args:    "--generate 32",
+			charset: "base64",
+			length:  32,
+		},
+		{
+			name:    "charset",
+			args:    "--generate 64 --charset hex",
+			charset: "hex",
+			length:  64,
+		},
+		{
+			name:         "written to file",
+			args:         "--generate 16 --charset alphanumeric --json",
+			charset:      "alphanumeric",
+			length:       16,
+			generateFile: true,
+		},
+		{
+			name:         "file removed on failure",
+			args:         "--generate 16",
+			createErr:    errors.New("store not found"),
+			generateFile: true,
+			wantError:    "store not found",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := fmt.Sprintf("%s create --store-id %s --name %s %s", secretstoreentry.RootNameSecret, storeID, secretName, testcase.args)
+			secretFile := path.Join(t.TempDir(), "secret")
+			if testcase.generateFile {
+				args += " --generate-file " + secretFile
+			}
+
+			var stored string
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+			opts.APIClient = mock.APIClient(mock.API{
+				CreateClientKeyFn: func() (*fastly.ClientKey, error) { return ck, nil },
+				GetSigningKeyFn:   func() (ed25519.PublicKey, error) { return skPub, nil },
+				CreateSecretFn: func(i *fastly.CreateSecretInput) (*fastly.Secret, error) {
+					if testcase.createErr != nil {
+						return nil, testcase.createErr
+					}
+					plaintext, ok := box.OpenAnonymous(nil, i.Secret, ckPub, ckPriv)
+					if !ok {
+						return nil, errors.New("failed to decrypt")
+					}
+					stored = string(plaintext)
+					return &fastly.Secret{Name: i.Name, Digest: []byte("digest")}, nil
+				},
+			})
+			err := app.Run(opts)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+
+			data, fileErr := os.ReadFile(secretFile)
+			if testcase.wantError != "" {
+				if !os.IsNotExist(fileErr) {
+					t.Fatalf("want %s removed, got %v", secretFile, fileErr)
+				}
+				return
+			}
+
+			testutil.AssertEqual(t, testcase.length, len(stored))
+			for _, r := range stored {
+				if !strings.ContainsRune(secretstoreentry.Charsets[testcase.charset], r) {
+					t.Fatalf("secret %q contains %q, which isn't in the %s charset", stored, r, testcase.charset)
+				}
+			}
+
+			if testcase.generateFile {
+				if fileErr != nil {
+					t.Fatal(fileErr)
+				}
+				testutil.AssertString(t, stored+"\n", string(data))
+				if runtime.GOOS != "windows" {
+					fi, err := os.Stat(secretFile)
+					if err != nil {
+						t.Fatal(err)
+					}
+					testutil.AssertEqual(t, os.FileMode(0o600), fi.Mode().Perm())
+				}
+				testutil.AssertBool(t, false, strings.Contains(stdout.String(), stored))
+				return
+			}
+			testutil.AssertStringContains(t, stdout.String(), "It can't be retrieved again")
+			testutil.AssertStringContains(t, stdout.String(), "\n"+stored+"\n")
+		})
+	}
+}
+
+func TestCreateSecretCommandRecreate(t *testing.T) {
+	const (
+		storeID    = "store123"
+		secretName = "testsecret"
+	)
+
+	ckPub, ckPriv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skPub, skPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ck := &fastly.ClientKey{
+		PublicKey: ckPub[:],
+		Signature: ed25519.Sign(skPriv, ckPub[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	t.Setenv("FASTLY_USE_API_SIGNING_KEY", "1")
+
+	for _, testcase := range []struct {
+		name          string
+		args          string
+		exists        bool
+		wantError     string
+		wantOutput    string
+		wantRecreated bool
+	}{
+		{
+			name:       "created",
+			args:       "--recreate-if-exists --json",
+			wantOutput: `"recreated": false`,
+		},
+		{
+			name:          "recreated",
+			args:          "--recreate-if-exists --json",
+			exists:        true,
+			wantOutput:    `"recreated": true`,
+			wantRecreated: true,
+		},
+		{
+			name:          "recreated text",
+			args:          "--recreate-if-exists",
+			exists:        true,
+			wantOutput:    "Recreated secret testsecret in store store123",
+			wantRecreated: true,
+		},
+		{
+			name:      "exists without flag",
+			exists:    true,
+			wantError: "409 - Conflict",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var recreated string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != "/resources/stores/secret/"+storeID+"/secrets" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				var body struct {
+					Name   string `json:"name"`
+					Secret []byte `json:"secret"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				plaintext, ok := box.OpenAnonymous(nil, body.Secret, ckPub, ckPriv)
+				if !ok {
+					t.Error("failed to decrypt")
+				}
+				recreated = string(plaintext)
+				_ = json.NewEncoder(w).Encode(fastly.Secret{Name: body.Name, Digest: []byte("digest")})
+			}))
+			defer server.Close()
+
+			args := fmt.Sprintf("%s create --store-id %s --name %s --stdin --token 123 --endpoint %s %s", secretstoreentry.RootNameSecret, storeID, secretName, server.URL, testcase.args)
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(strings.TrimSpace(args)), &stdout)
+			opts.Stdin = strings.NewReader("the secret")
+			opts.APIClient = mock.APIClient(mock.API{
+				CreateClientKeyFn: func() (*fastly.ClientKey, error) { return ck, nil },
+				GetSigningKeyFn:   func() (ed25519.PublicKey, error) { return skPub, nil },
+				CreateSecretFn: func(i *fastly.CreateSecretInput) (*fastly.Secret, error) {
+					if testcase.exists {
+						return nil, &fastly.HTTPError{StatusCode: http.StatusConflict}
+					}
+					return &fastly.Secret{Name: i.Name, Digest: []byte("digest")}, nil
+				},
+			})
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantRecreated {
+				testutil.AssertString(t, "the secret", recreated)
+			} else {
+				testutil.AssertString(t, "", recreated)
+			}
+		})
+	}
+}
+
 func TestGetSecretCommand(t *testing.T) {
 	const (
 		storeID     = "testid"
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/diff"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// DiffCommand calls the Fastly API to compare the configuration of two
+// services.
+type DiffCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	serviceA string
+	serviceB string
+	versionA cmd.OptionalServiceVersion
+	versionB cmd.OptionalServiceVersion
+}
+
+// NewDiffCommand returns a usable command registered under the parent.
+func NewDiffCommand(parent cmd.Registerer, g *global.Data) *DiffCommand {
+	var c DiffCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("diff", "Compare the configuration of two Fastly services")
+
+	// required
+	c.CmdClause.Flag("service-a", "Service ID of the service to compare from").Required().StringVar(&c.serviceA)
+	c.CmdClause.Flag("service-b", "Service ID of the service to compare to").Required().StringVar(&c.serviceB)
+
+	// optional
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "version-a",
+		Description: "Version of service A: 'latest', 'active', or the number of a specific version (default: active)",
+		Dst:         &c.versionA.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "version-b",
+		Description: "Version of service B: 'latest', 'active', or the number of a specific version (default: active)",
+		Dst:         &c.versionB.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *DiffCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	a, va, err := c.fetch(c.serviceA, &c.versionA)
+	if err != nil {
+		return err
+	}
+	b, vb, err := c.fetch(c.serviceB, &c.versionB)
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(diff.Normalize(a, c.serviceA), diff.Normalize(b, c.serviceB))
+
+	if ok, err := c.WriteJSON(out, diffOutput{
+		ServiceA: serviceVersion{ID: c.serviceA, Version: va.Number},
+		ServiceB: serviceVersion{ID: c.serviceB, Version: vb.Number},
+		Changes:  changes,
+	}); ok {
+		return err
+	}
+
+	if len(changes) == 0 {
+		text.Info(out, "No differences found between service %s version %d and service %s version %d", c.serviceA, va.Number, c.serviceB, vb.Number)
+		return nil
+	}
+
+	diff.Print(out, fmt.Sprintf("service %s version %d", c.serviceA, va.Number), fmt.Sprintf("service %s version %d", c.serviceB, vb.Number), changes)
+	return nil
+}
+
+// fetch resolves the service version and retrieves its configuration.
+func (c *DiffCommand) fetch(serviceID string, version *cmd.OptionalServiceVersion) (diff.Config, *fastly.Version, error) {
+	v, err := version.Parse(serviceID, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": version.Value,
+		})
+		return nil, nil, err
+	}
+
+	cfg, err := diff.Fetch(c.Globals.APIClient, serviceID, v.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": v.Number,
+		})
+		return nil, nil, err
+	}
+	return cfg, v, nil
+}
+
+// diffOutput is the structure rendered when the --json flag is set.
+type diffOutput struct {
+	ServiceA serviceVersion `json:"service_a"`
+	ServiceB serviceVersion `json:"service_b"`
+	Changes  []diff.Change  `json:"changes"`
+}
+
+type serviceVersion struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
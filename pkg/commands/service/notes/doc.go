@@ -0,0 +1,3 @@
+// Package notes contains commands to store and show the operational notes,
+// such as a runbook, of a Fastly service.
+package notes
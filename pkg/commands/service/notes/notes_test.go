@@ -0,0 +1,188 @@
+package notes_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestNotesSet(t *testing.T) {
+	runbook := filepath.Join(t.TempDir(), "runbook.md")
+	if err := os.WriteFile(runbook, []byte("# Runbook\n\nPage the origin team.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	large := filepath.Join(t.TempDir(), "large.md")
+	if err := os.WriteFile(large, bytes.Repeat([]byte("a"), 8001), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		created bool
+		item    *fastly.UpdateConfigStoreItemInput
+	)
+	updateItem := func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+		item = i
+		return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: i.Value}, nil
+	}
+
+	scenarios := []struct {
+		name        string
+		args        string
+		stdin       string
+		api         mock.API
+		wantError   string
+		wantOutput  string
+		wantCreated bool
+		wantValue   string
+	}{
+		{
+			name:      "validate missing --file and --stdin flags",
+			args:      "service notes set --service-id 123",
+			wantError: "no notes provided",
+		},
+		{
+			name:      "validate notes too large",
+			args:      "service notes set --service-id 123 --file " + large,
+			wantError: "the notes are 8001 bytes, more than the maximum of 8000",
+		},
+		{
+			name: "success with existing store",
+			args: "service notes set --service-id 123 --file " + runbook,
+			api: mock.API{
+				ListConfigStoresFn: func() ([]*fastly.ConfigStore, error) {
+					return []*fastly.ConfigStore{{ID: "other", Name: "other"}, {ID: "store-id", Name: "service-notes"}}, nil
+				},
+				UpdateConfigStoreItemFn: updateItem,
+			},
+			wantOutput: "Stored the notes of service 123 (33 bytes) in config store service-notes",
+			wantValue:  "# Runbook\n\nPage the origin team.\n",
+		},
+		{
+			name:  "success creating the store from STDIN",
+			args:  "service notes set --service-id 123 --stdin --store runbooks",
+			stdin: "restart the origin",
+			api: mock.API{
+				ListConfigStoresFn: func() ([]*fastly.ConfigStore, error) {
+					return nil, nil
+				},
+				CreateConfigStoreFn: func(i *fastly.CreateConfigStoreInput) (*fastly.ConfigStore, error) {
+					created = true
+					return &fastly.ConfigStore{ID: "store-id", Name: i.Name}, nil
+				},
+				UpdateConfigStoreItemFn: updateItem,
+			},
+			wantOutput:  "Stored the notes of service 123 (18 bytes) in config store runbooks",
+			wantCreated: true,
+			wantValue:   "restart the origin",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			created, item = false, nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantCreated, created)
+			if testcase.wantValue != "" {
+				testutil.AssertEqual(t, &fastly.UpdateConfigStoreItemInput{
+					StoreID: "store-id",
+					Key:     "123",
+					Value:   testcase.wantValue,
+					Upsert:  true,
+				}, item)
+			}
+		})
+	}
+}
+
+func TestNotesShow(t *testing.T) {
+	listStores := func() ([]*fastly.ConfigStore, error) {
+		return []*fastly.ConfigStore{{ID: "store-id", Name: "service-notes"}}, nil
+	}
+
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate missing store",
+			Args: testutil.Args("service notes show --service-id 123"),
+			API: mock.API{
+				ListConfigStoresFn: func() ([]*fastly.ConfigStore, error) {
+					return nil, nil
+				},
+			},
+			WantError: "the service has no notes",
+		},
+		{
+			Name: "validate missing notes",
+			Args: testutil.Args("service notes show --service-id 123"),
+			API: mock.API{
+				ListConfigStoresFn: listStores,
+				GetConfigStoreItemFn: func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+					return nil, &fastly.HTTPError{StatusCode: http.StatusNotFound}
+				},
+			},
+			WantError: "the service has no notes",
+		},
+		{
+			Name: "validate API error",
+			Args: testutil.Args("service notes show --service-id 123"),
+			API: mock.API{
+				ListConfigStoresFn: listStores,
+				GetConfigStoreItemFn: func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+					return nil, testutil.Err
+				},
+			},
+			WantError: testutil.Err.Error(),
+		},
+		{
+			Name: "success",
+			Args: testutil.Args("service notes show --service-id 123"),
+			API: mock.API{
+				ListConfigStoresFn: listStores,
+				GetConfigStoreItemFn: func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+					if i.StoreID != "store-id" || i.Key != "123" {
+						return nil, testutil.Err
+					}
+					return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: "# Runbook"}, nil
+				},
+			},
+			WantOutput: "# Runbook\n",
+		},
+		{
+			Name: "success with --json",
+			Args: testutil.Args("service notes show --service-id 123 --json"),
+			API: mock.API{
+				ListConfigStoresFn: listStores,
+				GetConfigStoreItemFn: func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
+					return &fastly.ConfigStoreItem{StoreID: i.StoreID, Key: i.Key, Value: "# Runbook"}, nil
+				},
+			},
+			WantOutput: `"item_value": "# Runbook"`,
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewSetCommand returns a usable command registered under the parent.
+func NewSetCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *SetCommand {
+	c := SetCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("set", "Store the operational notes of a service, replacing any existing notes")
+
+	// One of these must be set.
+	c.CmdClause.Flag("file", "Path to the notes (e.g. runbook.md). Required unless --stdin is set").StringVar(&c.file)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        "stdin",
+		Description: "Read the notes from STDIN. If set, --file will be ignored",
+		Dst:         &c.stdin,
+	})
+
+	// optional
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("store", "The name of the config store the notes are kept in (created if it doesn't exist)").Default(DefaultStoreName).StringVar(&c.store)
+
+	return &c
+}
+
+// SetCommand calls the Fastly API to store the notes of a service.
+type SetCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	file        string
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+	stdin       bool
+	store       string
+}
+
+// Exec invokes the application logic for the command.
+func (c *SetCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	if !c.stdin && c.file == "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("no notes provided"),
+			Remediation: "Provide the notes with --file or --stdin.",
+		}
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	var notes []byte
+	if c.stdin {
+		notes, err = io.ReadAll(in)
+	} else {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we trust the source of the path variable.
+		/* #nosec */
+		notes, err = os.ReadFile(c.file)
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error reading the notes: %w", err)
+	}
+	if len(notes) == 0 {
+		return fmt.Errorf("error reading the notes: no notes provided")
+	}
+	if len(notes) > MaxNotesSize {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the notes are %d bytes, more than the maximum of %d", len(notes), MaxNotesSize),
+			Remediation: "Shorten the notes, e.g. by linking to the full runbook.",
+		}
+	}
+
+	store, err := findStore(c.Globals.APIClient, c.store)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if store == nil {
+		store, err = c.Globals.APIClient.CreateConfigStore(&fastly.CreateConfigStoreInput{
+			Name: c.store,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Store": c.store,
+			})
+			return fmt.Errorf("error creating config store %s: %w", c.store, err)
+		}
+		if c.Globals.Verbose() {
+			text.Info(out, "Created config store %s (%s)", store.Name, store.ID)
+		}
+	}
+
+	item, err := c.Globals.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
+		StoreID: store.ID,
+		Key:     serviceID,
+		Value:   string(notes),
+		Upsert:  true,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Store ID":   store.ID,
+		})
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, item); ok {
+		return err
+	}
+
+	text.Success(out, "Stored the notes of service %s (%d bytes) in config store %s", serviceID, len(notes), store.Name)
+	return nil
+}
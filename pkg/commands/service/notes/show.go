@@ -0,0 +1,102 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewShowCommand returns a usable command registered under the parent.
+func NewShowCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ShowCommand {
+	c := ShowCommand{
+		Base: cmd.Base{
+			Globals: g,
+		},
+		manifest: m,
+	}
+	c.CmdClause = parent.Command("show", "Show the operational notes of a service")
+
+	// optional
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("store", "The name of the config store the notes are kept in").Default(DefaultStoreName).StringVar(&c.store)
+
+	return &c
+}
+
+// ShowCommand calls the Fastly API to show the notes of a service.
+type ShowCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+	store       string
+}
+
+// Exec invokes the application logic for the command.
+func (c *ShowCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	store, err := findStore(c.Globals.APIClient, c.store)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if store == nil {
+		return errNoNotes
+	}
+
+	item, err := c.Globals.APIClient.GetConfigStoreItem(&fastly.GetConfigStoreItemInput{
+		StoreID: store.ID,
+		Key:     serviceID,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return errNoNotes
+		}
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Store ID":   store.ID,
+		})
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, item); ok {
+		return err
+	}
+
+	// The notes are written as is, so they can be piped to a file or pager.
+	fmt.Fprint(out, item.Value)
+	if !strings.HasSuffix(item.Value, "\n") {
+		fmt.Fprintln(out)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fastly/cli/pkg/api"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// DefaultStoreName is the config store in which the notes of every service
+// are kept, keyed by service ID.
+const DefaultStoreName = "service-notes"
+
+// MaxNotesSize is the maximum size, in bytes, of the notes of a service, as
+// limited by the size of a config store item's value.
+const MaxNotesSize = 8000
+
+// errNoNotes is returned when a service has no notes.
+var errNoNotes = fsterr.RemediationError{
+	Inner:       errors.New("the service has no notes"),
+	Remediation: "Store the notes of the service with `fastly service notes set --file <path>`.",
+}
+
+// findStore returns the config store with the given name, or nil if there's
+// none.
+func findStore(client api.Interface, name string) (*fastly.ConfigStore, error) {
+	stores, err := client.ListConfigStores()
+	if err != nil {
+		return nil, fmt.Errorf("error listing config stores: %w", err)
+	}
+	for _, s := range stores {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// isNotFound reports whether err is the API's response to a missing resource.
+func isNotFound(err error) bool {
+	var httpErr *fastly.HTTPError
+	return errors.As(err, &httpErr) && httpErr.IsNotFound()
+}
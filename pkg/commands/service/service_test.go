@@ -320,6 +320,83 @@ func TestServiceSearch(t *testing.T) {
 	}
 }
 
+func TestServiceDiff(t *testing.T) {
+	args := testutil.Args
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetSettingsFn: func(i *fastly.GetSettingsInput) (*fastly.Settings, error) {
+			return &fastly.Settings{DefaultTTL: 3600}, nil
+		},
+		ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{{Name: i.ServiceID + ".example.com"}}, nil
+		},
+		ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+			port := 443
+			if i.ServiceID == "456" {
+				port = 8443
+			}
+			return []*fastly.Backend{{Name: "origin", Port: port}}, nil
+		},
+		ListHeadersFn: func(i *fastly.ListHeadersInput) ([]*fastly.Header, error) {
+			return nil, nil
+		},
+		ListVCLsFn: func(i *fastly.ListVCLsInput) ([]*fastly.VCL, error) {
+			return nil, nil
+		},
+		ListSnippetsFn: func(i *fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
+			return []*fastly.Snippet{{Name: "logging", Content: "log \"service " + i.ServiceID + "\";"}}, nil
+		},
+	}
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("service diff --service-a 123"),
+			wantError: "error parsing arguments: required flag --service-b not provided",
+		},
+		{
+			args:       args("service diff --service-a 123 --service-b 123"),
+			api:        api,
+			wantOutput: "No differences found between service 123 version 1 and service 123 version 1",
+		},
+		{
+			args:       args("service diff --service-a 123 --service-b 456"),
+			api:        api,
+			wantOutput: "@@ backends/origin (modified) @@\n-  port: 443\n+  port: 8443",
+		},
+		{
+			args:       args("service diff --service-a 123 --service-b 456 --version-b 3"),
+			api:        api,
+			wantOutput: "+++ service 456 version 3",
+		},
+		{
+			args:       args("service diff --service-a 123 --service-b 456 --json"),
+			api:        api,
+			wantOutput: `"name": "456.example.com",`,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantError == "" {
+				// Service IDs referenced within the configuration are normalized.
+				if strings.Contains(stdout.String(), "snippets/logging") {
+					t.Errorf("unexpected snippet change in output: %s", stdout.String())
+				}
+			}
+		})
+	}
+}
+
 func TestServiceUpdate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
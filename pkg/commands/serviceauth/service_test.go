@@ -3,6 +3,9 @@ package serviceauth_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -215,6 +218,124 @@ func TestServiceAuthDelete(t *testing.T) {
 	}
 }
 
+func TestServiceAuthSync(t *testing.T) {
+	const permissions = `services:
+  - service_id: svc1
+    users:
+      - user_id: a
+        permission: full
+      - user_id: c
+`
+	for _, testcase := range []struct {
+		name        string
+		args        string
+		file        string
+		stdin       string
+		updateFn    func(*fastly.UpdateServiceAuthorizationInput) (*fastly.ServiceAuthorization, error)
+		wantError   string
+		wantOutput  []string
+		wantChanges []string
+	}{
+		{
+			name:      "invalid permission",
+			args:      "service-auth sync --auto-yes",
+			file:      "services:\n  - service_id: svc1\n    users:\n      - user_id: a\n        permission: admin\n",
+			wantError: "invalid permissions file",
+		},
+		{
+			name:      "duplicate user",
+			args:      "service-auth sync --auto-yes",
+			file:      "services:\n  - service_id: svc1\n    users:\n      - user_id: a\n      - user_id: a\n",
+			wantError: "service 'svc1': user 'a' is listed more than once",
+		},
+		{
+			name: "dry run",
+			args: "service-auth sync --dry-run",
+			file: permissions,
+			wantOutput: []string{
+				"a: read_only → full",
+				"b: full",
+				"c: read_only",
+				"Dry run: 3 changes were not made",
+			},
+		},
+		{
+			name:       "removal declined",
+			args:       "service-auth sync",
+			file:       permissions,
+			stdin:      "N",
+			wantOutput: []string{"Are you sure you want to remove 1 service authorizations?"},
+		},
+		{
+			name:        "apply",
+			args:        "service-auth sync --auto-yes",
+			file:        permissions,
+			wantOutput:  []string{"Applied 3 service authorization changes"},
+			wantChanges: []string{"update auth-a full", "delete auth-b", "create svc1 c read_only"},
+		},
+		{
+			name:        "apply failure",
+			args:        "service-auth sync --auto-yes",
+			file:        permissions,
+			updateFn:    updateServiceAuthError,
+			wantError:   "failed to apply 1 of 3 service authorization changes",
+			wantChanges: []string{"delete auth-b", "create svc1 c read_only"},
+		},
+		{
+			name:       "already up to date",
+			args:       "service-auth sync",
+			file:       "services:\n  - service_id: svc1\n    users:\n      - user_id: a\n      - user_id: b\n        permission: full\n",
+			wantOutput: []string{"Already up to date", "The service authorizations already match"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "permissions.yaml")
+			if err := os.WriteFile(path, []byte(testcase.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var changes []string
+			updateFn := testcase.updateFn
+			if updateFn == nil {
+				updateFn = func(i *fastly.UpdateServiceAuthorizationInput) (*fastly.ServiceAuthorization, error) {
+					changes = append(changes, fmt.Sprintf("update %s %s", i.ID, i.Permission))
+					return &fastly.ServiceAuthorization{ID: i.ID}, nil
+				}
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args+" --from-file "+path), &stdout)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			opts.APIClient = mock.APIClient(mock.API{
+				ListServiceAuthorizationsFn: func(*fastly.ListServiceAuthorizationsInput) (*fastly.ServiceAuthorizations, error) {
+					return &fastly.ServiceAuthorizations{Items: []*fastly.ServiceAuthorization{
+						{ID: "auth-a", Permission: "read_only", Service: &fastly.SAService{ID: "svc1"}, User: &fastly.SAUser{ID: "a"}},
+						{ID: "auth-b", Permission: "full", Service: &fastly.SAService{ID: "svc1"}, User: &fastly.SAUser{ID: "b"}},
+						{ID: "auth-z", Permission: "full", Service: &fastly.SAService{ID: "svc2"}, User: &fastly.SAUser{ID: "z"}},
+					}}, nil
+				},
+				CreateServiceAuthorizationFn: func(i *fastly.CreateServiceAuthorizationInput) (*fastly.ServiceAuthorization, error) {
+					changes = append(changes, fmt.Sprintf("create %s %s %s", i.Service.ID, i.User.ID, i.Permission))
+					return &fastly.ServiceAuthorization{ID: "new"}, nil
+				},
+				DeleteServiceAuthorizationFn: func(i *fastly.DeleteServiceAuthorizationInput) error {
+					changes = append(changes, "delete "+i.ID)
+					return nil
+				},
+				UpdateServiceAuthorizationFn: updateFn,
+			})
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantChanges, changes)
+		})
+	}
+}
+
 var errTest = errors.New("fixture error")
 
 func createServiceAuthError(*fastly.CreateServiceAuthorizationInput) (*fastly.ServiceAuthorization, error) {
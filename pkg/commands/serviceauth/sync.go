@@ -0,0 +1,310 @@
+package serviceauth
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+	"gopkg.in/yaml.v2"
+)
+
+// permissionsRemediation explains the format of a permissions file.
+const permissionsRemediation = `A permissions file is a YAML file such as:
+
+  services:
+    - service_id: SU1Z0isxPaozGVKXdv0eY
+      users:
+        - user_id: 1cSiJb8B5pXMsL2pJwyP5m
+          permission: full         # optional, defaults to read_only
+        - user_id: 4N1J9K3FLDHh3Kc4aoW4Cb
+
+Permissions are one of: full, read_only, purge_select, purge_all.`
+
+// PermissionsFile is a declarative list of the users authorized for each
+// service.
+type PermissionsFile struct {
+	Services []ServicePermissions `yaml:"services"`
+}
+
+// ServicePermissions are the users authorized for a service.
+//
+// NOTE: The service's authorizations of any other users are removed.
+type ServicePermissions struct {
+	ServiceID string           `yaml:"service_id"`
+	Users     []UserPermission `yaml:"users"`
+}
+
+// UserPermission is the permission of a user for a service.
+type UserPermission struct {
+	UserID     string `yaml:"user_id"`
+	Permission string `yaml:"permission"`
+}
+
+// ReadPermissionsFile reads and validates the permissions file at path.
+func ReadPermissionsFile(path string) (*PermissionsFile, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the user's choice of file.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading permissions file: %w", err)
+	}
+
+	var f PermissionsFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing permissions file '%s': %w", path, err),
+			Remediation: permissionsRemediation,
+		}
+	}
+	if err := f.validate(); err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid permissions file '%s': %w", path, err),
+			Remediation: permissionsRemediation,
+		}
+	}
+	return &f, nil
+}
+
+// validate checks the file, setting the default permission.
+func (f *PermissionsFile) validate() error {
+	if len(f.Services) == 0 {
+		return fmt.Errorf("no services")
+	}
+	services := make(map[string]bool)
+	for i, s := range f.Services {
+		if s.ServiceID == "" {
+			return fmt.Errorf("service %d: missing service_id", i+1)
+		}
+		if services[s.ServiceID] {
+			return fmt.Errorf("service '%s' is listed more than once", s.ServiceID)
+		}
+		services[s.ServiceID] = true
+
+		users := make(map[string]bool)
+		for j, u := range s.Users {
+			if u.UserID == "" {
+				return fmt.Errorf("service '%s': user %d: missing user_id", s.ServiceID, j+1)
+			}
+			if users[u.UserID] {
+				return fmt.Errorf("service '%s': user '%s' is listed more than once", s.ServiceID, u.UserID)
+			}
+			users[u.UserID] = true
+
+			switch {
+			case u.Permission == "":
+				f.Services[i].Users[j].Permission = "read_only"
+			case !validPermission(u.Permission):
+				return fmt.Errorf("service '%s': user '%s': unsupported permission '%s'", s.ServiceID, u.UserID, u.Permission)
+			}
+		}
+	}
+	return nil
+}
+
+func validPermission(p string) bool {
+	for _, v := range Permissions {
+		if p == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncCommand reconciles the service authorizations with a permissions file.
+type SyncCommand struct {
+	cmd.Base
+	dryRun   bool
+	fromFile string
+}
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent cmd.Registerer, g *global.Data) *SyncCommand {
+	var c SyncCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("sync", "Add, update and remove the service authorizations of the services in a permissions file to match it")
+
+	// required
+	c.CmdClause.Flag("from-file", "Path to a YAML file listing the users authorized for each service, and their permission").Required().StringVar(&c.fromFile)
+
+	// optional
+	c.CmdClause.Flag("dry-run", "Display the changes for each service without making them").BoolVar(&c.dryRun)
+	return &c
+}
+
+// authChange is a modification of a single service authorization.
+type authChange struct {
+	op     fastly.BatchOperation
+	userID string
+	// id is the ID of the existing service authorization (update and delete).
+	id   string
+	from string
+	to   string
+}
+
+// Exec invokes the application logic for the command.
+func (c *SyncCommand) Exec(in io.Reader, out io.Writer) error {
+	f, err := ReadPermissionsFile(c.fromFile)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	existing, err := c.listAll()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	changes := make(map[string][]authChange, len(f.Services))
+	var total, removals int
+	for _, s := range f.Services {
+		text.Break(out)
+		text.Output(out, "%s service %s", text.Bold("Syncing"), s.ServiceID)
+
+		cs := diffAuthorizations(s.Users, existing[s.ServiceID])
+		if len(cs) == 0 {
+			text.Output(out, "Already up to date")
+			continue
+		}
+		for _, ch := range cs {
+			switch ch.op {
+			case fastly.CreateBatchOperation:
+				text.Output(out, "%s %s: %s", text.BoldGreen("+"), ch.userID, ch.to)
+			case fastly.DeleteBatchOperation:
+				text.Output(out, "%s %s: %s", text.BoldRed("-"), ch.userID, ch.from)
+				removals++
+			default:
+				text.Output(out, "%s %s: %s → %s", text.BoldYellow("~"), ch.userID, ch.from, ch.to)
+			}
+		}
+		changes[s.ServiceID] = cs
+		total += len(cs)
+	}
+	text.Break(out)
+
+	if total == 0 {
+		text.Success(out, "The service authorizations already match %s", c.fromFile)
+		return nil
+	}
+	if c.dryRun {
+		text.Info(out, "Dry run: %d changes were not made", total)
+		return nil
+	}
+
+	// NOTE: Removing access is confirmed as it can lock users out of a service.
+	if removals > 0 && !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
+		label := fmt.Sprintf("Are you sure you want to remove %d service authorizations? [y/N] ", removals)
+		answer, err := text.AskYesNo(out, label, in)
+		if err != nil {
+			return err
+		}
+		if !answer {
+			return nil
+		}
+		text.Break(out)
+	}
+
+	var failed int
+	for _, s := range f.Services {
+		for _, ch := range changes[s.ServiceID] {
+			if err := c.apply(s.ServiceID, ch); err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Service ID": s.ServiceID,
+					"User ID":    ch.userID,
+				})
+				text.Error(out, "Failed to sync user %s of service %s: %s", ch.userID, s.ServiceID, err)
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d of %d service authorization changes", failed, total)
+	}
+
+	text.Success(out, "Applied %d service authorization changes", total)
+	return nil
+}
+
+// listAll returns the active service authorizations, keyed by service ID.
+func (c *SyncCommand) listAll() (map[string][]*fastly.ServiceAuthorization, error) {
+	auths := make(map[string][]*fastly.ServiceAuthorization)
+	input := fastly.ListServiceAuthorizationsInput{PageNumber: 1, PageSize: 100}
+	for {
+		resp, err := c.Globals.APIClient.ListServiceAuthorizations(&input)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Items {
+			if a.DeletedAt != nil || a.Service == nil || a.User == nil {
+				continue
+			}
+			auths[a.Service.ID] = append(auths[a.Service.ID], a)
+		}
+		if resp.Info.Links.Next == "" || len(resp.Items) == 0 {
+			return auths, nil
+		}
+		input.PageNumber++
+	}
+}
+
+// apply makes the change to the service's authorizations.
+func (c *SyncCommand) apply(serviceID string, ch authChange) error {
+	var err error
+	switch ch.op {
+	case fastly.CreateBatchOperation:
+		_, err = c.Globals.APIClient.CreateServiceAuthorization(&fastly.CreateServiceAuthorizationInput{
+			Permission: ch.to,
+			Service:    &fastly.SAService{ID: serviceID},
+			User:       &fastly.SAUser{ID: ch.userID},
+		})
+	case fastly.DeleteBatchOperation:
+		err = c.Globals.APIClient.DeleteServiceAuthorization(&fastly.DeleteServiceAuthorizationInput{
+			ID: ch.id,
+		})
+	default:
+		_, err = c.Globals.APIClient.UpdateServiceAuthorization(&fastly.UpdateServiceAuthorizationInput{
+			ID:         ch.id,
+			Permission: ch.to,
+		})
+	}
+	return err
+}
+
+// diffAuthorizations returns the changes (sorted by user ID) needed for the
+// existing authorizations of a service to match the wanted users.
+func diffAuthorizations(want []UserPermission, existing []*fastly.ServiceAuthorization) []authChange {
+	wanted := make(map[string]string, len(want))
+	for _, u := range want {
+		wanted[u.UserID] = u.Permission
+	}
+
+	var changes []authChange
+	found := make(map[string]bool, len(existing))
+	for _, a := range existing {
+		found[a.User.ID] = true
+		p, ok := wanted[a.User.ID]
+		switch {
+		case !ok:
+			changes = append(changes, authChange{op: fastly.DeleteBatchOperation, userID: a.User.ID, id: a.ID, from: a.Permission})
+		case p != a.Permission:
+			changes = append(changes, authChange{op: fastly.UpdateBatchOperation, userID: a.User.ID, id: a.ID, from: a.Permission, to: p})
+		}
+	}
+	for _, u := range want {
+		if !found[u.UserID] {
+			changes = append(changes, authChange{op: fastly.CreateBatchOperation, userID: u.UserID, to: u.Permission})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].userID < changes[j].userID
+	})
+	return changes
+}
@@ -1,7 +1,9 @@
 package serviceversion
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/errors"
@@ -19,6 +21,8 @@ type ActivateCommand struct {
 	serviceName    cmd.OptionalServiceNameID
 	serviceVersion cmd.OptionalServiceVersion
 	autoClone      cmd.OptionalAutoClone
+	comment        cmd.OptionalString
+	latestDraft    bool
 }
 
 // NewActivateCommand returns a usable command registered under the parent.
@@ -43,26 +47,40 @@ func NewActivateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data)
 		Name:        cmd.FlagVersionName,
 		Description: cmd.FlagVersionDesc,
 		Dst:         &c.serviceVersion.Value,
-		Required:    true,
 	})
 	c.RegisterAutoCloneFlag(cmd.AutoCloneFlagOpts{
 		Action: c.autoClone.Set,
 		Dst:    &c.autoClone.Value,
 	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.comment.Set,
+		Name:        "comment",
+		Description: "Human-readable summary of the changes being activated",
+		Dst:         &c.comment.Value,
+	})
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        "latest-draft",
+		Description: "Activate the draft version staged via `service-version stage` (instead of --version)",
+		Dst:         &c.latestDraft,
+	})
 	return &c
 }
 
 // Exec invokes the application logic for the command.
 func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
-	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
-		AutoCloneFlag:      c.autoClone,
-		APIClient:          c.Globals.APIClient,
-		Manifest:           c.manifest,
-		Out:                out,
-		ServiceNameFlag:    c.serviceName,
-		ServiceVersionFlag: c.serviceVersion,
-		VerboseMode:        c.Globals.Flags.Verbose,
-	})
+	if c.Globals.Config.Fastly.RequireActivationComment && strings.TrimSpace(c.comment.Value) == "" {
+		err := errors.ErrActivationCommentRequired
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if c.latestDraft == (c.serviceVersion.Value != "") {
+		err := fmt.Errorf("error parsing arguments: exactly one of --version or --latest-draft must be provided")
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, serviceVersion, err := c.serviceDetails(out)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID":      serviceID,
@@ -74,6 +92,21 @@ func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
 	c.Input.ServiceID = serviceID
 	c.Input.ServiceVersion = serviceVersion.Number
 
+	if c.comment.WasSet {
+		_, err := c.Globals.APIClient.UpdateVersion(&fastly.UpdateVersionInput{
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion.Number,
+			Comment:        &c.comment.Value,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersion.Number,
+			})
+			return fmt.Errorf("error setting comment for service version %d: %w", serviceVersion.Number, err)
+		}
+	}
+
 	ver, err := c.Globals.APIClient.ActivateVersion(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -83,6 +116,40 @@ func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if staged, ok := c.Globals.Config.Staged[serviceID]; ok && staged == c.Input.ServiceVersion {
+		delete(c.Globals.Config.Staged, serviceID)
+		if err := c.Globals.Config.Write(c.Globals.Path); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error clearing staged version: %w", err)
+		}
+	}
+
 	text.Success(out, "Activated service %s version %d", ver.ServiceID, c.Input.ServiceVersion)
 	return nil
 }
+
+// serviceDetails returns the service ID and the version to be activated,
+// resolving the staged draft version when --latest-draft is set.
+func (c *ActivateCommand) serviceDetails(out io.Writer) (string, *fastly.Version, error) {
+	if !c.latestDraft {
+		return cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+			AutoCloneFlag:      c.autoClone,
+			APIClient:          c.Globals.APIClient,
+			Manifest:           c.manifest,
+			Out:                out,
+			ServiceNameFlag:    c.serviceName,
+			ServiceVersionFlag: c.serviceVersion,
+			VerboseMode:        c.Globals.Flags.Verbose,
+		})
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, nil)
+	if err != nil {
+		return serviceID, nil, err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+	v, err := stagedVersion(c.Globals, serviceID)
+	return serviceID, v, err
+}
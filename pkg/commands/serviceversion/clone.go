@@ -1,6 +1,7 @@
 package serviceversion
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/cmd"
@@ -16,6 +17,9 @@ type CloneCommand struct {
 	cmd.Base
 	manifest       manifest.Data
 	Input          fastly.CloneVersionInput
+	allowPartial   bool
+	fromService    string
+	mapping        string
 	serviceName    cmd.OptionalServiceNameID
 	serviceVersion cmd.OptionalServiceVersion
 }
@@ -26,6 +30,9 @@ func NewCloneCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Cl
 	c.Globals = g
 	c.manifest = m
 	c.CmdClause = parent.Command("clone", "Clone a Fastly service version")
+	c.CmdClause.Flag("allow-partial", "Clone a version of --from-service even though some of its resources (e.g. dictionaries and logging endpoints) can't be cloned").BoolVar(&c.allowPartial)
+	c.CmdClause.Flag("from-service", "Service ID of the service whose --version is cloned into a new version of the target service").StringVar(&c.fromService)
+	c.CmdClause.Flag("mapping", "Path to a JSON file mapping source domains to target domains and backend names to target addresses (used with --from-service)").StringVar(&c.mapping)
 	c.RegisterFlag(cmd.StringFlagOpts{
 		Name:        cmd.FlagServiceIDName,
 		Description: cmd.FlagServiceIDDesc,
@@ -48,7 +55,19 @@ func NewCloneCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *Cl
 }
 
 // Exec invokes the application logic for the command.
-func (c *CloneCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CloneCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.fromService != "" {
+		return c.cloneFromService(in, out)
+	}
+	if c.mapping != "" {
+		err := errors.RemediationError{
+			Inner:       fmt.Errorf("--mapping requires --from-service"),
+			Remediation: "Use --mapping together with --from-service to clone a version of another service.",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
 		AllowActiveLocked:  true,
 		APIClient:          c.Globals.APIClient,
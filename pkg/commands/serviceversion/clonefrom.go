@@ -0,0 +1,399 @@
+package serviceversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// CloneMapping describes how the resources of the source service are adapted
+// when cloned into another service (see the --mapping flag).
+//
+// Domains are unique across all Fastly services, so every source domain must
+// be mapped to a new domain (or skipped). Backends without a mapping keep
+// their address.
+type CloneMapping struct {
+	// Domains maps a source domain name to the target domain name. An empty
+	// target skips the domain.
+	Domains map[string]string `json:"domains"`
+	// Backends maps a source backend name to the target backend address.
+	Backends map[string]string `json:"backends"`
+}
+
+// cloneFromService creates a new version of the target service containing the
+// configuration of --version of the --from-service service.
+//
+// NOTE: Only the resources listed in cloneResources are copied. Any other
+// resources of the source version are an error unless --allow-partial is set.
+// The new version is left as a draft so it can be reviewed before activation.
+func (c *CloneCommand) cloneFromService(in io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+	if serviceID == c.fromService {
+		err := errors.RemediationError{
+			Inner:       fmt.Errorf("--from-service is the same as the target service (%s)", serviceID),
+			Remediation: "Omit --from-service to clone a version within the same service.",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	from, err := c.serviceVersion.Parse(c.fromService, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      c.fromService,
+			"Service Version": c.serviceVersion.Value,
+		})
+		return err
+	}
+
+	mapping, err := c.readMapping()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	res, err := fetchCloneResources(c.Globals.APIClient, c.fromService, from.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      c.fromService,
+			"Service Version": from.Number,
+		})
+		return err
+	}
+
+	uncloned, err := fetchUncloned(c.Globals.APIClient, c.fromService, from.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      c.fromService,
+			"Service Version": from.Number,
+		})
+		return err
+	}
+	var list string
+	for _, u := range uncloned {
+		list += "\n\t" + u.String()
+	}
+	if len(uncloned) > 0 && !c.allowPartial {
+		err := errors.RemediationError{
+			Inner:       fmt.Errorf("service %s version %d has resources that can't be cloned:\n%s", c.fromService, from.Number, list),
+			Remediation: "Use --allow-partial to clone the other resources, and then recreate these resources in the new version.",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if err := c.mapResources(res, mapping, in, out); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	comment := fmt.Sprintf("Cloned from service %s version %d", c.fromService, from.Number)
+	ver, err := c.Globals.APIClient.CreateVersion(&fastly.CreateVersionInput{
+		ServiceID: serviceID,
+		Comment:   &comment,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return fmt.Errorf("error creating service version: %w", err)
+	}
+
+	if err := res.create(c.Globals.APIClient, serviceID, ver.Number); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": ver.Number,
+		})
+		return errors.RemediationError{
+			Inner:       err,
+			Remediation: fmt.Sprintf("Service %s version %d was only partially cloned. Fix the issue and clone again, then delete or ignore the partial draft version.", serviceID, ver.Number),
+		}
+	}
+
+	if len(uncloned) > 0 {
+		text.Warning(out, "These resources weren't cloned, and need to be recreated in service %s version %d:\n%s", serviceID, ver.Number, list)
+		text.Success(out, "Partially cloned service %s version %d to service %s version %d", c.fromService, from.Number, serviceID, ver.Number)
+		return nil
+	}
+	text.Success(out, "Cloned service %s version %d to service %s version %d", c.fromService, from.Number, serviceID, ver.Number)
+	return nil
+}
+
+// readMapping reads the --mapping file, if set.
+func (c *CloneCommand) readMapping() (CloneMapping, error) {
+	var m CloneMapping
+	if c.mapping == "" {
+		return m, nil
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the source of the variable.
+	// #nosec
+	data, err := os.ReadFile(c.mapping)
+	if err != nil {
+		return m, fmt.Errorf("error reading --mapping file: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("error parsing --mapping file: %w", err)
+	}
+	return m, nil
+}
+
+// mapResources applies the mapping to the source domains and backends,
+// prompting for any domain or backend the mapping doesn't cover.
+func (c *CloneCommand) mapResources(res *cloneResources, mapping CloneMapping, in io.Reader, out io.Writer) error {
+	interactive := !c.Globals.Flags.NonInteractive
+
+	domains := res.domains[:0]
+	for _, d := range res.domains {
+		name, ok := mapping.Domains[d.Name]
+		if !ok {
+			if !interactive {
+				return errors.RemediationError{
+					Inner:       fmt.Errorf("no mapping for domain '%s'", d.Name),
+					Remediation: "Domains are unique across services. Map each domain to a new domain (or \"\" to skip it) in the --mapping file.",
+				}
+			}
+			var err error
+			name, err = text.Input(out, fmt.Sprintf("Domain to use in place of '%s' (leave blank to skip): ", d.Name), in)
+			if err != nil {
+				return fmt.Errorf("error reading input: %w", err)
+			}
+		}
+		if name == "" {
+			text.Info(out, "Skipping domain '%s'", d.Name)
+			continue
+		}
+		d.Name = name
+		domains = append(domains, d)
+	}
+	res.domains = domains
+
+	for _, b := range res.backends {
+		addr, ok := mapping.Backends[b.Name]
+		if !ok && interactive {
+			var err error
+			addr, err = text.Input(out, fmt.Sprintf("Address for backend '%s': [%s] ", b.Name, b.Address), in)
+			if err != nil {
+				return fmt.Errorf("error reading input: %w", err)
+			}
+		}
+		if addr == "" || addr == b.Address {
+			continue
+		}
+		// Hostnames derived from the old address would no longer match.
+		for _, h := range []*string{&b.OverrideHost, &b.SSLCertHostname, &b.SSLSNIHostname, &b.Hostname} {
+			if *h == b.Address {
+				*h = addr
+			}
+		}
+		b.Address = addr
+	}
+	return nil
+}
+
+// cloneResources is the configuration of a service version that can be cloned
+// into another service.
+type cloneResources struct {
+	settings     *fastly.Settings
+	conditions   []*fastly.Condition
+	healthChecks []*fastly.HealthCheck
+	backends     []*fastly.Backend
+	domains      []*fastly.Domain
+	headers      []*fastly.Header
+	vcls         []*fastly.VCL
+	snippets     []*fastly.Snippet
+}
+
+// fetchCloneResources retrieves the configuration of the service version.
+func fetchCloneResources(client api.Interface, serviceID string, version int) (*cloneResources, error) {
+	var (
+		res cloneResources
+		err error
+	)
+	if res.settings, err = client.GetSettings(&fastly.GetSettingsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching settings: %w", err)
+	}
+	if res.conditions, err = client.ListConditions(&fastly.ListConditionsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching conditions: %w", err)
+	}
+	if res.healthChecks, err = client.ListHealthChecks(&fastly.ListHealthChecksInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching health checks: %w", err)
+	}
+	if res.backends, err = client.ListBackends(&fastly.ListBackendsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching backends: %w", err)
+	}
+	if res.domains, err = client.ListDomains(&fastly.ListDomainsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching domains: %w", err)
+	}
+	if res.headers, err = client.ListHeaders(&fastly.ListHeadersInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching headers: %w", err)
+	}
+	if res.vcls, err = client.ListVCLs(&fastly.ListVCLsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching VCLs: %w", err)
+	}
+	if res.snippets, err = client.ListSnippets(&fastly.ListSnippetsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		return nil, fmt.Errorf("error fetching snippets: %w", err)
+	}
+	sort.Slice(res.domains, func(i, j int) bool { return res.domains[i].Name < res.domains[j].Name })
+	sort.Slice(res.backends, func(i, j int) bool { return res.backends[i].Name < res.backends[j].Name })
+	return &res, nil
+}
+
+// create adds the resources to the service version.
+//
+// NOTE: Conditions and health checks are created first as the other
+// resources can reference them.
+func (r *cloneResources) create(client api.Interface, serviceID string, version int) error {
+	for _, v := range r.conditions {
+		if _, err := client.CreateCondition(&fastly.CreateConditionInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Name:           fastly.String(v.Name),
+			Priority:       fastly.Int(v.Priority),
+			Statement:      fastly.String(v.Statement),
+			Type:           fastly.String(v.Type),
+		}); err != nil {
+			return fmt.Errorf("error cloning condition '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.healthChecks {
+		headers := v.Headers
+		if _, err := client.CreateHealthCheck(&fastly.CreateHealthCheckInput{
+			ServiceID:        serviceID,
+			ServiceVersion:   version,
+			CheckInterval:    fastly.Int(v.CheckInterval),
+			Comment:          fastly.String(v.Comment),
+			ExpectedResponse: fastly.Int(v.ExpectedResponse),
+			HTTPVersion:      fastly.String(v.HTTPVersion),
+			Headers:          &headers,
+			Host:             fastly.String(v.Host),
+			Initial:          fastly.Int(v.Initial),
+			Method:           fastly.String(v.Method),
+			Name:             fastly.String(v.Name),
+			Path:             fastly.String(v.Path),
+			Threshold:        fastly.Int(v.Threshold),
+			Timeout:          fastly.Int(v.Timeout),
+			Window:           fastly.Int(v.Window),
+		}); err != nil {
+			return fmt.Errorf("error cloning health check '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.backends {
+		if _, err := client.CreateBackend(&fastly.CreateBackendInput{
+			ServiceID:           serviceID,
+			ServiceVersion:      version,
+			Address:             fastly.String(v.Address),
+			AutoLoadbalance:     fastly.CBool(v.AutoLoadbalance),
+			BetweenBytesTimeout: fastly.Int(v.BetweenBytesTimeout),
+			Comment:             fastly.String(v.Comment),
+			ConnectTimeout:      fastly.Int(v.ConnectTimeout),
+			ErrorThreshold:      fastly.Int(v.ErrorThreshold),
+			FirstByteTimeout:    fastly.Int(v.FirstByteTimeout),
+			HealthCheck:         fastly.String(v.HealthCheck),
+			KeepAliveTime:       fastly.Int(v.KeepAliveTime),
+			MaxConn:             fastly.Int(v.MaxConn),
+			MaxTLSVersion:       fastly.String(v.MaxTLSVersion),
+			MinTLSVersion:       fastly.String(v.MinTLSVersion),
+			Name:                fastly.String(v.Name),
+			OverrideHost:        fastly.String(v.OverrideHost),
+			Port:                fastly.Int(v.Port),
+			RequestCondition:    fastly.String(v.RequestCondition),
+			SSLCACert:           fastly.String(v.SSLCACert),
+			SSLCertHostname:     fastly.String(v.SSLCertHostname),
+			SSLCheckCert:        fastly.CBool(v.SSLCheckCert),
+			SSLCiphers:          fastly.String(v.SSLCiphers),
+			SSLClientCert:       fastly.String(v.SSLClientCert),
+			SSLClientKey:        fastly.String(v.SSLClientKey),
+			SSLSNIHostname:      fastly.String(v.SSLSNIHostname),
+			Shield:              fastly.String(v.Shield),
+			UseSSL:              fastly.CBool(v.UseSSL),
+			Weight:              fastly.Int(v.Weight),
+		}); err != nil {
+			return fmt.Errorf("error cloning backend '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.domains {
+		if _, err := client.CreateDomain(&fastly.CreateDomainInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Comment:        fastly.String(v.Comment),
+			Name:           fastly.String(v.Name),
+		}); err != nil {
+			return fmt.Errorf("error cloning domain '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.headers {
+		action, typ := v.Action, v.Type
+		if _, err := client.CreateHeader(&fastly.CreateHeaderInput{
+			ServiceID:         serviceID,
+			ServiceVersion:    version,
+			Action:            &action,
+			CacheCondition:    fastly.String(v.CacheCondition),
+			Destination:       fastly.String(v.Destination),
+			IgnoreIfSet:       fastly.CBool(v.IgnoreIfSet),
+			Name:              fastly.String(v.Name),
+			Priority:          fastly.Int(v.Priority),
+			Regex:             fastly.String(v.Regex),
+			RequestCondition:  fastly.String(v.RequestCondition),
+			ResponseCondition: fastly.String(v.ResponseCondition),
+			Source:            fastly.String(v.Source),
+			Substitution:      fastly.String(v.Substitution),
+			Type:              &typ,
+		}); err != nil {
+			return fmt.Errorf("error cloning header '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.vcls {
+		if _, err := client.CreateVCL(&fastly.CreateVCLInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Content:        fastly.String(v.Content),
+			Main:           fastly.Bool(v.Main),
+			Name:           fastly.String(v.Name),
+		}); err != nil {
+			return fmt.Errorf("error cloning VCL '%s': %w", v.Name, err)
+		}
+	}
+	for _, v := range r.snippets {
+		typ := v.Type
+		if _, err := client.CreateSnippet(&fastly.CreateSnippetInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+			Content:        fastly.String(v.Content),
+			Dynamic:        fastly.Int(v.Dynamic),
+			Name:           fastly.String(v.Name),
+			Priority:       fastly.Int(v.Priority),
+			Type:           &typ,
+		}); err != nil {
+			return fmt.Errorf("error cloning snippet '%s': %w", v.Name, err)
+		}
+	}
+	if r.settings != nil {
+		if _, err := client.UpdateSettings(&fastly.UpdateSettingsInput{
+			ServiceID:       serviceID,
+			ServiceVersion:  version,
+			DefaultHost:     fastly.String(r.settings.DefaultHost),
+			DefaultTTL:      r.settings.DefaultTTL,
+			StaleIfError:    fastly.Bool(r.settings.StaleIfError),
+			StaleIfErrorTTL: fastly.Uint(r.settings.StaleIfErrorTTL),
+		}); err != nil {
+			return fmt.Errorf("error cloning settings: %w", err)
+		}
+	}
+	return nil
+}
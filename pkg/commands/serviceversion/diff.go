@@ -0,0 +1,134 @@
+package serviceversion
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/diff"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// DiffCommand calls the Fastly API to compare two service versions.
+type DiffCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	manifest    manifest.Data
+	serviceName cmd.OptionalServiceNameID
+	from        cmd.OptionalServiceVersion
+	to          cmd.OptionalServiceVersion
+}
+
+// NewDiffCommand returns a usable command registered under the parent.
+func NewDiffCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *DiffCommand {
+	var c DiffCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("diff", "Compare the configuration of two Fastly service versions")
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "from",
+		Description: "The version to compare from: 'latest', 'active', or the number of a specific version",
+		Dst:         &c.from.Value,
+		Required:    true,
+	})
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        "to",
+		Description: "The version to compare to: 'latest', 'active', or the number of a specific version",
+		Dst:         &c.to.Value,
+		Required:    true,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *DiffCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	from, err := c.from.Parse(serviceID, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"From":       c.from.Value,
+		})
+		return err
+	}
+	to, err := c.to.Parse(serviceID, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"To":         c.to.Value,
+		})
+		return err
+	}
+
+	a, err := diff.Fetch(c.Globals.APIClient, serviceID, from.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": from.Number,
+		})
+		return err
+	}
+	b, err := diff.Fetch(c.Globals.APIClient, serviceID, to.Number)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": to.Number,
+		})
+		return err
+	}
+
+	changes := diff.Compare(a, b)
+
+	if ok, err := c.WriteJSON(out, diffOutput{
+		ServiceID: serviceID,
+		From:      from.Number,
+		To:        to.Number,
+		Changes:   changes,
+	}); ok {
+		return err
+	}
+
+	if len(changes) == 0 {
+		text.Info(out, "No differences found between service %s version %d and version %d", serviceID, from.Number, to.Number)
+		return nil
+	}
+
+	diff.Print(out, fmt.Sprintf("version %d", from.Number), fmt.Sprintf("version %d", to.Number), changes)
+	return nil
+}
+
+// diffOutput is the structure rendered when the --json flag is set.
+type diffOutput struct {
+	ServiceID string        `json:"service_id"`
+	From      int           `json:"from"`
+	To        int           `json:"to"`
+	Changes   []diff.Change `json:"changes"`
+}
@@ -0,0 +1,159 @@
+package serviceversion
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/time"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// activateEventType is the event log type recorded for version activations.
+const activateEventType = "version.activate"
+
+// HistoryCommand calls the Fastly API to list service version activations.
+type HistoryCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	manifest    manifest.Data
+	limit       int
+	serviceName cmd.OptionalServiceNameID
+}
+
+// NewHistoryCommand returns a usable command registered under the parent.
+func NewHistoryCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *HistoryCommand {
+	var c HistoryCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("history", "Show who activated which Fastly service versions, and with which comment")
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagInt(cmd.LimitFlag(&c.limit))
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Activation is a single entry in the activation history of a service.
+type Activation struct {
+	ActivatedAt string `json:"activated_at"`
+	Version     int    `json:"version"`
+	User        string `json:"user"`
+	Comment     string `json:"comment"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *HistoryCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	events, err := c.Globals.APIClient.GetAPIEvents(&fastly.GetAPIEventsFilterInput{
+		EventType:  activateEventType,
+		MaxResults: c.limit,
+		ServiceID:  serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return fmt.Errorf("error listing activation events: %w", err)
+	}
+
+	versions, err := c.Globals.APIClient.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+	comments := make(map[int]string)
+	for _, v := range versions {
+		comments[v.Number] = v.Comment
+	}
+
+	users := make(map[string]string)
+	history := make([]Activation, 0, len(events.Events))
+	for _, e := range events.Events {
+		a := Activation{
+			Version: eventVersion(e),
+			User:    c.userLogin(users, e.UserID),
+		}
+		if e.CreatedAt != nil {
+			a.ActivatedAt = e.CreatedAt.UTC().Format(time.Format)
+		}
+		a.Comment = comments[a.Version]
+		history = append(history, a)
+	}
+
+	if ok, err := c.WriteJSON(out, history); ok {
+		return err
+	}
+
+	tw := text.NewTable(out)
+	tw.AddHeader("ACTIVATED AT (UTC)", "VERSION", "USER", "COMMENT")
+	for _, a := range history {
+		tw.AddLine(a.ActivatedAt, a.Version, a.User, a.Comment)
+	}
+	tw.Print()
+	return nil
+}
+
+// userLogin resolves a user ID to the user's login, caching the result.
+//
+// NOTE: If the user can't be retrieved (e.g. they've since been deleted, or the
+// token lacks permission) we fall back to displaying the user ID.
+func (c *HistoryCommand) userLogin(cache map[string]string, id string) string {
+	if id == "" {
+		return ""
+	}
+	if login, ok := cache[id]; ok {
+		return login
+	}
+	login := id
+	if u, err := c.Globals.APIClient.GetUser(&fastly.GetUserInput{ID: id}); err == nil && u.Login != "" {
+		login = u.Login
+	}
+	cache[id] = login
+	return login
+}
+
+// eventVersion extracts the activated service version from the event metadata.
+func eventVersion(e *fastly.Event) int {
+	switch v := e.Metadata["version"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+	return 0
+}
@@ -2,10 +2,14 @@ package serviceversion_test
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/go-fastly/v7/fastly"
@@ -57,6 +61,191 @@ func TestVersionClone(t *testing.T) {
 	}
 }
 
+func TestVersionCloneFromService(t *testing.T) {
+	mapping := filepath.Join(t.TempDir(), "mapping.json")
+	err := os.WriteFile(mapping, []byte(`{"domains": {"www.example.com": "eu.example.com"}, "backends": {"origin": "eu.origin.example.com"}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// created records the resources created in the target service.
+	var created []string
+	api := unclonedAPI(mock.API{
+		ListVersionsFn:   testutil.ListVersions,
+		GetSettingsFn:    getSettingsOK,
+		ListConditionsFn: listConditionsOK,
+		ListHealthChecksFn: func(i *fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+			return []*fastly.HealthCheck{}, nil
+		},
+		ListDomainsFn:  listDomainsOK,
+		ListBackendsFn: listBackendsOK,
+		ListHeadersFn:  listHeadersOK,
+		ListVCLsFn:     listVCLsOK,
+		ListSnippetsFn: listSnippetsOK,
+		CreateVersionFn: func(i *fastly.CreateVersionInput) (*fastly.Version, error) {
+			created = nil
+			return &fastly.Version{ServiceID: i.ServiceID, Number: 4, Comment: *i.Comment}, nil
+		},
+		CreateConditionFn: func(i *fastly.CreateConditionInput) (*fastly.Condition, error) {
+			created = append(created, fmt.Sprintf("condition %s@%s/%d", *i.Name, i.ServiceID, i.ServiceVersion))
+			return &fastly.Condition{}, nil
+		},
+		CreateDomainFn: func(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
+			created = append(created, fmt.Sprintf("domain %s@%s/%d", *i.Name, i.ServiceID, i.ServiceVersion))
+			return &fastly.Domain{}, nil
+		},
+		CreateBackendFn: func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+			created = append(created, fmt.Sprintf("backend %s=%s@%s/%d", *i.Name, *i.Address, i.ServiceID, i.ServiceVersion))
+			return &fastly.Backend{}, nil
+		},
+		CreateVCLFn: func(i *fastly.CreateVCLInput) (*fastly.VCL, error) {
+			created = append(created, fmt.Sprintf("vcl %s@%s/%d", *i.Name, i.ServiceID, i.ServiceVersion))
+			return &fastly.VCL{}, nil
+		},
+		UpdateSettingsFn: func(i *fastly.UpdateSettingsInput) (*fastly.Settings, error) {
+			created = append(created, fmt.Sprintf("settings ttl=%d@%s/%d", i.DefaultTTL, i.ServiceID, i.ServiceVersion))
+			return &fastly.Settings{}, nil
+		},
+	})
+	partial := api
+	partial.ListDictionariesFn = func(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+		return []*fastly.Dictionary{{Name: "redirects"}, {Name: "flags"}}, nil
+	}
+	partial.ListSplunksFn = func(i *fastly.ListSplunksInput) ([]*fastly.Splunk, error) {
+		return []*fastly.Splunk{{Name: "logs"}}, nil
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		args        []string
+		api         mock.API
+		stdin       string
+		wantError   string
+		wantOutput  string
+		wantCreated []string
+	}{
+		{
+			args:      args("service-version clone --service-id 123 --version 1 --mapping " + mapping),
+			wantError: "--mapping requires --from-service",
+		},
+		{
+			args:      args("service-version clone --service-id 123 --version 1 --from-service 123"),
+			wantError: "--from-service is the same as the target service (123)",
+		},
+		{
+			args:      args("service-version clone --service-id 123 --version 1 --from-service 456 --non-interactive"),
+			api:       api,
+			wantError: "no mapping for domain 'www.example.com'",
+		},
+		{
+			args:       args("service-version clone --service-id 123 --version 1 --from-service 456 --mapping " + mapping),
+			api:        api,
+			wantOutput: "Cloned service 456 version 1 to service 123 version 4",
+			wantCreated: []string{
+				"condition is-api@123/4",
+				"backend origin=eu.origin.example.com@123/4",
+				"domain eu.example.com@123/4",
+				"vcl main@123/4",
+				"settings ttl=3600@123/4",
+			},
+		},
+		{
+			args:       args("service-version clone --service-id 123 --version 1 --from-service 456"),
+			api:        api,
+			stdin:      "\n\n",
+			wantOutput: "Skipping domain 'www.example.com'",
+			wantCreated: []string{
+				"condition is-api@123/4",
+				"backend origin=1.example.com@123/4",
+				"vcl main@123/4",
+				"settings ttl=3600@123/4",
+			},
+		},
+		{
+			args: args("service-version clone --service-id 123 --version 1 --from-service 456 --mapping " + mapping),
+			api: func() mock.API {
+				api := api
+				api.CreateBackendFn = func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+					return nil, testutil.Err
+				}
+				return api
+			}(),
+			wantError: "error cloning backend 'origin': " + testutil.Err.Error(),
+		},
+		{
+			args:      args("service-version clone --service-id 123 --version 1 --from-service 456 --mapping " + mapping),
+			api:       partial,
+			wantError: "service 456 version 1 has resources that can't be cloned:\n\n\tdictionaries (redirects, flags)\n\tSplunk logging endpoints (logs)",
+		},
+		{
+			args:       args("service-version clone --service-id 123 --version 1 --from-service 456 --allow-partial --mapping " + mapping),
+			api:        partial,
+			wantOutput: "Partially cloned service 456 version 1 to service 123 version 4",
+			wantCreated: []string{
+				"condition is-api@123/4",
+				"backend origin=eu.origin.example.com@123/4",
+				"domain eu.example.com@123/4",
+				"vcl main@123/4",
+				"settings ttl=3600@123/4",
+			},
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			created = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantCreated != nil {
+				testutil.AssertEqual(t, testcase.wantCreated, created)
+			}
+		})
+	}
+}
+
+// unclonedAPI sets the functions listing the resources that aren't cloned,
+// which are all empty.
+func unclonedAPI(api mock.API) mock.API {
+	api.ListCacheSettingsFn = func(*fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error) { return nil, nil }
+	api.ListGzipsFn = func(*fastly.ListGzipsInput) ([]*fastly.Gzip, error) { return nil, nil }
+	api.ListRequestSettingsFn = func(*fastly.ListRequestSettingsInput) ([]*fastly.RequestSetting, error) { return nil, nil }
+	api.ListResponseObjectsFn = func(*fastly.ListResponseObjectsInput) ([]*fastly.ResponseObject, error) { return nil, nil }
+	api.ListDictionariesFn = func(*fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) { return nil, nil }
+	api.ListACLsFn = func(*fastly.ListACLsInput) ([]*fastly.ACL, error) { return nil, nil }
+	api.ListBlobStoragesFn = func(*fastly.ListBlobStoragesInput) ([]*fastly.BlobStorage, error) { return nil, nil }
+	api.ListBigQueriesFn = func(*fastly.ListBigQueriesInput) ([]*fastly.BigQuery, error) { return nil, nil }
+	api.ListCloudfilesFn = func(*fastly.ListCloudfilesInput) ([]*fastly.Cloudfiles, error) { return nil, nil }
+	api.ListDatadogFn = func(*fastly.ListDatadogInput) ([]*fastly.Datadog, error) { return nil, nil }
+	api.ListDigitalOceansFn = func(*fastly.ListDigitalOceansInput) ([]*fastly.DigitalOcean, error) { return nil, nil }
+	api.ListElasticsearchFn = func(*fastly.ListElasticsearchInput) ([]*fastly.Elasticsearch, error) { return nil, nil }
+	api.ListFTPsFn = func(*fastly.ListFTPsInput) ([]*fastly.FTP, error) { return nil, nil }
+	api.ListPubsubsFn = func(*fastly.ListPubsubsInput) ([]*fastly.Pubsub, error) { return nil, nil }
+	api.ListGCSsFn = func(*fastly.ListGCSsInput) ([]*fastly.GCS, error) { return nil, nil }
+	api.ListHerokusFn = func(*fastly.ListHerokusInput) ([]*fastly.Heroku, error) { return nil, nil }
+	api.ListHoneycombsFn = func(*fastly.ListHoneycombsInput) ([]*fastly.Honeycomb, error) { return nil, nil }
+	api.ListHTTPSFn = func(*fastly.ListHTTPSInput) ([]*fastly.HTTPS, error) { return nil, nil }
+	api.ListKafkasFn = func(*fastly.ListKafkasInput) ([]*fastly.Kafka, error) { return nil, nil }
+	api.ListKinesisFn = func(*fastly.ListKinesisInput) ([]*fastly.Kinesis, error) { return nil, nil }
+	api.ListLogentriesFn = func(*fastly.ListLogentriesInput) ([]*fastly.Logentries, error) { return nil, nil }
+	api.ListLogglyFn = func(*fastly.ListLogglyInput) ([]*fastly.Loggly, error) { return nil, nil }
+	api.ListLogshuttlesFn = func(*fastly.ListLogshuttlesInput) ([]*fastly.Logshuttle, error) { return nil, nil }
+	api.ListNewRelicFn = func(*fastly.ListNewRelicInput) ([]*fastly.NewRelic, error) { return nil, nil }
+	api.ListOpenstacksFn = func(*fastly.ListOpenstackInput) ([]*fastly.Openstack, error) { return nil, nil }
+	api.ListPapertrailsFn = func(*fastly.ListPapertrailsInput) ([]*fastly.Papertrail, error) { return nil, nil }
+	api.ListS3sFn = func(*fastly.ListS3sInput) ([]*fastly.S3, error) { return nil, nil }
+	api.ListScalyrsFn = func(*fastly.ListScalyrsInput) ([]*fastly.Scalyr, error) { return nil, nil }
+	api.ListSFTPsFn = func(*fastly.ListSFTPsInput) ([]*fastly.SFTP, error) { return nil, nil }
+	api.ListSplunksFn = func(*fastly.ListSplunksInput) ([]*fastly.Splunk, error) { return nil, nil }
+	api.ListSumologicsFn = func(*fastly.ListSumologicsInput) ([]*fastly.Sumologic, error) { return nil, nil }
+	api.ListSyslogsFn = func(*fastly.ListSyslogsInput) ([]*fastly.Syslog, error) { return nil, nil }
+	return api
+}
+
 func TestVersionList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -160,14 +349,43 @@ func TestVersionUpdate(t *testing.T) {
 func TestVersionActivate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
-		args       []string
-		api        mock.API
-		wantError  string
-		wantOutput string
+		args           []string
+		api            mock.API
+		requireComment bool
+		staged         config.Staged
+		wantError      string
+		wantOutput     string
+		wantStaged     config.Staged
 	}{
 		{
 			args:      args("service-version activate --service-id 123"),
-			wantError: "error parsing arguments: required flag --version not provided",
+			wantError: "error parsing arguments: exactly one of --version or --latest-draft must be provided",
+		},
+		{
+			args:      args("service-version activate --service-id 123 --version 3 --latest-draft"),
+			wantError: "error parsing arguments: exactly one of --version or --latest-draft must be provided",
+		},
+		{
+			args:      args("service-version activate --service-id 123 --latest-draft"),
+			wantError: "no staged version found for service 123",
+		},
+		{
+			args: args("service-version activate --service-id 123 --latest-draft"),
+			api: mock.API{
+				GetVersionFn: getVersionOK,
+			},
+			staged:    config.Staged{"123": 1},
+			wantError: "staged version 1 is no longer a draft",
+		},
+		{
+			args: args("service-version activate --service-id 123 --latest-draft"),
+			api: mock.API{
+				GetVersionFn:      getVersionOK,
+				ActivateVersionFn: activateVersionOK,
+			},
+			staged:     config.Staged{"123": 3, "456": 5},
+			wantOutput: "Activated service 123 version 3",
+			wantStaged: config.Staged{"456": 5},
 		},
 		{
 			args: args("service-version activate --service-id 123 --version 1 --autoclone"),
@@ -195,6 +413,43 @@ func TestVersionActivate(t *testing.T) {
 			},
 			wantOutput: "Activated service 123 version 3",
 		},
+		{
+			args: args("service-version activate --service-id 123 --version 3 --comment foo"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+					if *i.Comment != "foo" {
+						return nil, fmt.Errorf("unexpected comment: %s", *i.Comment)
+					}
+					return updateVersionOK(i)
+				},
+				ActivateVersionFn: activateVersionOK,
+			},
+			wantOutput: "Activated service 123 version 3",
+		},
+		{
+			args: args("service-version activate --service-id 123 --version 3 --comment foo"),
+			api: mock.API{
+				ListVersionsFn:  testutil.ListVersions,
+				UpdateVersionFn: updateVersionError,
+			},
+			wantError: "error setting comment for service version 3: " + testutil.Err.Error(),
+		},
+		{
+			args:           args("service-version activate --service-id 123 --version 3"),
+			requireComment: true,
+			wantError:      "a comment is required to activate a service version",
+		},
+		{
+			args: args("service-version activate --service-id 123 --version 3 --comment foo"),
+			api: mock.API{
+				ListVersionsFn:    testutil.ListVersions,
+				UpdateVersionFn:   updateVersionOK,
+				ActivateVersionFn: activateVersionOK,
+			},
+			requireComment: true,
+			wantOutput:     "Activated service 123 version 3",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -202,9 +457,71 @@ func TestVersionActivate(t *testing.T) {
 			var stdout bytes.Buffer
 			opts := testutil.NewRunOpts(testcase.args, &stdout)
 			opts.APIClient = mock.APIClient(testcase.api)
+			opts.ConfigFile.Fastly.RequireActivationComment = testcase.requireComment
+			opts.ConfigFile.Staged = testcase.staged
 			err := app.Run(opts)
 			testutil.AssertErrorContains(t, err, testcase.wantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantStaged != nil {
+				testutil.AssertEqual(t, testcase.wantStaged, opts.ConfigFile.Staged)
+			}
+		})
+	}
+}
+
+func TestVersionStage(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+		wantStaged config.Staged
+	}{
+		{
+			args:      args("service-version stage"),
+			wantError: "error reading service: no service ID found",
+		},
+		{
+			args: args("service-version stage --service-id 123"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+			},
+			wantOutput: "Staged service 123 version 4 (cloned from version 1)",
+			wantStaged: config.Staged{"123": 4},
+		},
+		{
+			args: args("service-version stage --service-id 123 --version 2"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionResult(4),
+			},
+			wantOutput: "Staged service 123 version 4 (cloned from version 2)",
+			wantStaged: config.Staged{"123": 4},
+		},
+		{
+			args: args("service-version stage --service-id 123"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CloneVersionFn: testutil.CloneVersionError,
+			},
+			wantError: testutil.Err.Error(),
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			opts.ConfigFile.Staged = make(config.Staged)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			if testcase.wantStaged != nil {
+				testutil.AssertEqual(t, testcase.wantStaged, opts.ConfigFile.Staged)
+			}
 		})
 	}
 }
@@ -301,6 +618,136 @@ func TestVersionLock(t *testing.T) {
 	}
 }
 
+func TestVersionDiff(t *testing.T) {
+	args := testutil.Args
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetSettingsFn:  getSettingsOK,
+		ListDomainsFn:  listDomainsOK,
+		ListBackendsFn: listBackendsOK,
+		ListHeadersFn:  listHeadersOK,
+		ListVCLsFn:     listVCLsOK,
+		ListSnippetsFn: listSnippetsOK,
+	}
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput []string
+	}{
+		{
+			args:      args("service-version diff --service-id 123 --to 2"),
+			wantError: "error parsing arguments: required flag --from not provided",
+		},
+		{
+			args:       args("service-version diff --service-id 123 --from 1 --to 1"),
+			api:        api,
+			wantOutput: []string{"No differences found between service 123 version 1 and version 1"},
+		},
+		{
+			args: args("service-version diff --service-id 123 --from 1 --to 3"),
+			api:  api,
+			wantOutput: []string{
+				"--- version 1",
+				"+++ version 3",
+				"@@ settings/general (modified) @@\n-  default_ttl: 3600\n+  default_ttl: 3602",
+				"@@ domains/www.example.com (removed) @@",
+				"@@ domains/www3.example.com (added) @@",
+				"@@ backends/origin (modified) @@\n-  address: 1.example.com\n+  address: 3.example.com",
+				"@@ vcls/main (modified) @@\n  content:\n     sub vcl_recv {\n-      set req.http.Version = \"1\";\n+      set req.http.Version = \"3\";\n     }",
+			},
+		},
+		{
+			args: args("service-version diff --service-id 123 --from 1 --to 3 --json"),
+			api:  api,
+			wantOutput: []string{
+				`"from": 1`,
+				`"to": 3`,
+				`"section": "backends"`,
+				`"type": "modified"`,
+				`"field": "address"`,
+			},
+		},
+		{
+			args: args("service-version diff --service-id 123 --from 1 --to 3"),
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				GetSettingsFn: func(i *fastly.GetSettingsInput) (*fastly.Settings, error) {
+					return nil, testutil.Err
+				},
+			},
+			wantError: "error fetching settings: " + testutil.Err.Error(),
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, want := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), want)
+			}
+		})
+	}
+}
+
+func TestVersionHistory(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args: args("service-version history --service-id 123"),
+			api: mock.API{
+				GetAPIEventsFn: func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+					return fastly.GetAPIEventsResponse{}, testutil.Err
+				},
+			},
+			wantError: "error listing activation events: " + testutil.Err.Error(),
+		},
+		{
+			args: args("service-version history --service-id 123"),
+			api: mock.API{
+				GetAPIEventsFn: getAPIEventsOK,
+				ListVersionsFn: func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+					return []*fastly.Version{
+						{Number: 1, Comment: "initial release"},
+						{Number: 2, Comment: "add backend"},
+					}, nil
+				},
+				GetUserFn: func(i *fastly.GetUserInput) (*fastly.User, error) {
+					if i.ID == "abc" {
+						return &fastly.User{Login: "foo@example.com"}, nil
+					}
+					return nil, testutil.Err
+				},
+			},
+			wantOutput: strings.TrimSpace(`
+ACTIVATED AT (UTC)  VERSION  USER             COMMENT
+2021-06-15 23:00    2        foo@example.com  add backend
+2021-06-14 23:00    1        def              initial release
+`) + "\n",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+		})
+	}
+}
+
 var listVersionsShortOutput = strings.TrimSpace(`
 NUMBER  ACTIVE  LAST EDITED (UTC)
 1       true    2000-01-01 01:00
@@ -344,6 +791,16 @@ Versions: 3
 		Last edited (UTC): 2000-01-03 01:00
 `) + "\n\n"
 
+func getVersionOK(i *fastly.GetVersionInput) (*fastly.Version, error) {
+	versions, _ := testutil.ListVersions(&fastly.ListVersionsInput{ServiceID: i.ServiceID})
+	for _, v := range versions {
+		if v.Number == i.ServiceVersion {
+			return v, nil
+		}
+	}
+	return nil, testutil.Err
+}
+
 func updateVersionOK(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
 	return &fastly.Version{
 		Number:    i.ServiceVersion,
@@ -404,3 +861,90 @@ func lockVersionOK(i *fastly.LockVersionInput) (*fastly.Version, error) {
 func lockVersionError(i *fastly.LockVersionInput) (*fastly.Version, error) {
 	return nil, testutil.Err
 }
+
+func getSettingsOK(i *fastly.GetSettingsInput) (*fastly.Settings, error) {
+	return &fastly.Settings{
+		DefaultTTL:     uint(3600 + i.ServiceVersion - 1),
+		ServiceID:      i.ServiceID,
+		ServiceVersion: i.ServiceVersion,
+	}, nil
+}
+
+func listDomainsOK(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+	name := "www.example.com"
+	if i.ServiceVersion > 1 {
+		name = fmt.Sprintf("www%d.example.com", i.ServiceVersion)
+	}
+	return []*fastly.Domain{
+		{
+			Name:           name,
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+		},
+	}, nil
+}
+
+func listBackendsOK(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+	return []*fastly.Backend{
+		{
+			Address:        fmt.Sprintf("%d.example.com", i.ServiceVersion),
+			Name:           "origin",
+			Port:           443,
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+		},
+	}, nil
+}
+
+func listConditionsOK(i *fastly.ListConditionsInput) ([]*fastly.Condition, error) {
+	return []*fastly.Condition{
+		{
+			Name:           "is-api",
+			Statement:      `req.url ~ "^/api/"`,
+			Type:           "REQUEST",
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+		},
+	}, nil
+}
+
+func listHeadersOK(i *fastly.ListHeadersInput) ([]*fastly.Header, error) {
+	return []*fastly.Header{}, nil
+}
+
+func listVCLsOK(i *fastly.ListVCLsInput) ([]*fastly.VCL, error) {
+	return []*fastly.VCL{
+		{
+			Content:        fmt.Sprintf("sub vcl_recv {\n  set req.http.Version = \"%d\";\n}\n", i.ServiceVersion),
+			Main:           true,
+			Name:           "main",
+			ServiceID:      i.ServiceID,
+			ServiceVersion: i.ServiceVersion,
+		},
+	}, nil
+}
+
+func listSnippetsOK(i *fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
+	return []*fastly.Snippet{}, nil
+}
+
+func getAPIEventsOK(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+	return fastly.GetAPIEventsResponse{
+		Events: []*fastly.Event{
+			{
+				CreatedAt: testutil.MustParseTimeRFC3339("2021-06-15T23:00:00Z"),
+				EventType: i.EventType,
+				Metadata:  map[string]any{"version": float64(2)},
+				ServiceID: i.ServiceID,
+				UserID:    "abc",
+			},
+			{
+				CreatedAt: testutil.MustParseTimeRFC3339("2021-06-14T23:00:00Z"),
+				EventType: i.EventType,
+				Metadata:  map[string]any{"version": float64(1)},
+				ServiceID: i.ServiceID,
+				UserID:    "def",
+			},
+		},
+	}, nil
+}
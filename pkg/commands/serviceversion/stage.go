@@ -0,0 +1,119 @@
+package serviceversion
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// StageCommand calls the Fastly API to clone a service version into a draft
+// that is recorded locally as staged for later activation.
+type StageCommand struct {
+	cmd.Base
+	manifest       manifest.Data
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+}
+
+// NewStageCommand returns a usable command registered under the parent.
+func NewStageCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *StageCommand {
+	var c StageCommand
+	c.Globals = g
+	c.manifest = m
+	c.CmdClause = parent.Command("stage", "Clone a Fastly service version into a draft to be activated later via `service-version activate --latest-draft`")
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: "The version to clone: 'latest', 'active', or the number of a specific version (default: active)",
+		Dst:         &c.serviceVersion.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *StageCommand) Exec(_ io.Reader, out io.Writer) error {
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	ver, err := c.Globals.APIClient.CloneVersion(&fastly.CloneVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return err
+	}
+
+	if c.Globals.Config.Staged == nil {
+		c.Globals.Config.Staged = make(config.Staged)
+	}
+	c.Globals.Config.Staged[serviceID] = ver.Number
+	if err := c.Globals.Config.Write(c.Globals.Path); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error saving staged version: %w", err)
+	}
+
+	text.Success(out, "Staged service %s version %d (cloned from version %d)", serviceID, ver.Number, serviceVersion.Number)
+	return nil
+}
+
+// stagedVersion returns the draft version staged for the given service.
+func stagedVersion(g *global.Data, serviceID string) (*fastly.Version, error) {
+	number, ok := g.Config.Staged[serviceID]
+	if !ok {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("no staged version found for service %s", serviceID),
+			Remediation: "Stage a draft version first using `fastly service-version stage`, or specify a version using --version.",
+		}
+	}
+
+	v, err := g.APIClient.GetVersion(&fastly.GetVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: number,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving staged version %d: %w", number, err)
+	}
+	if v.Active || v.Locked {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("staged version %d is no longer a draft (it has been activated or locked)", number),
+			Remediation: "Stage a new draft version using `fastly service-version stage`.",
+		}
+	}
+	return v, nil
+}
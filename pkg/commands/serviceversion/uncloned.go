@@ -0,0 +1,181 @@
+package serviceversion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// unclonedResources are the resources of a type that cloneResources doesn't
+// copy into another service.
+type unclonedResources struct {
+	Kind  string
+	Names []string
+}
+
+// String returns the type of resource followed by the resource names.
+func (u unclonedResources) String() string {
+	return fmt.Sprintf("%s (%s)", u.Kind, strings.Join(u.Names, ", "))
+}
+
+// fetchUncloned lists the resources of the service version that aren't
+// cloned into another service, such as dictionaries and logging endpoints,
+// so that a partial clone isn't mistaken for a complete one.
+func fetchUncloned(client api.Interface, serviceID string, version int) ([]unclonedResources, error) {
+	listers := []struct {
+		kind string
+		list func() ([]string, error)
+	}{
+		{"cache settings", func() ([]string, error) {
+			v, err := client.ListCacheSettings(&fastly.ListCacheSettingsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.CacheSetting) string { return r.Name }), err
+		}},
+		{"gzip configurations", func() ([]string, error) {
+			v, err := client.ListGzips(&fastly.ListGzipsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Gzip) string { return r.Name }), err
+		}},
+		{"request settings", func() ([]string, error) {
+			v, err := client.ListRequestSettings(&fastly.ListRequestSettingsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.RequestSetting) string { return r.Name }), err
+		}},
+		{"response objects", func() ([]string, error) {
+			v, err := client.ListResponseObjects(&fastly.ListResponseObjectsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.ResponseObject) string { return r.Name }), err
+		}},
+		{"dictionaries", func() ([]string, error) {
+			v, err := client.ListDictionaries(&fastly.ListDictionariesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Dictionary) string { return r.Name }), err
+		}},
+		{"ACLs", func() ([]string, error) {
+			v, err := client.ListACLs(&fastly.ListACLsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.ACL) string { return r.Name }), err
+		}},
+		{"Azure Blob Storage logging endpoints", func() ([]string, error) {
+			v, err := client.ListBlobStorages(&fastly.ListBlobStoragesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.BlobStorage) string { return r.Name }), err
+		}},
+		{"BigQuery logging endpoints", func() ([]string, error) {
+			v, err := client.ListBigQueries(&fastly.ListBigQueriesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.BigQuery) string { return r.Name }), err
+		}},
+		{"Cloud Files logging endpoints", func() ([]string, error) {
+			v, err := client.ListCloudfiles(&fastly.ListCloudfilesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Cloudfiles) string { return r.Name }), err
+		}},
+		{"Datadog logging endpoints", func() ([]string, error) {
+			v, err := client.ListDatadog(&fastly.ListDatadogInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Datadog) string { return r.Name }), err
+		}},
+		{"DigitalOcean Spaces logging endpoints", func() ([]string, error) {
+			v, err := client.ListDigitalOceans(&fastly.ListDigitalOceansInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.DigitalOcean) string { return r.Name }), err
+		}},
+		{"Elasticsearch logging endpoints", func() ([]string, error) {
+			v, err := client.ListElasticsearch(&fastly.ListElasticsearchInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Elasticsearch) string { return r.Name }), err
+		}},
+		{"FTP logging endpoints", func() ([]string, error) {
+			v, err := client.ListFTPs(&fastly.ListFTPsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.FTP) string { return r.Name }), err
+		}},
+		{"Google Cloud Pub/Sub logging endpoints", func() ([]string, error) {
+			v, err := client.ListPubsubs(&fastly.ListPubsubsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Pubsub) string { return r.Name }), err
+		}},
+		{"Google Cloud Storage logging endpoints", func() ([]string, error) {
+			v, err := client.ListGCSs(&fastly.ListGCSsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.GCS) string { return r.Name }), err
+		}},
+		{"Heroku logging endpoints", func() ([]string, error) {
+			v, err := client.ListHerokus(&fastly.ListHerokusInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Heroku) string { return r.Name }), err
+		}},
+		{"Honeycomb logging endpoints", func() ([]string, error) {
+			v, err := client.ListHoneycombs(&fastly.ListHoneycombsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Honeycomb) string { return r.Name }), err
+		}},
+		{"HTTPS logging endpoints", func() ([]string, error) {
+			v, err := client.ListHTTPS(&fastly.ListHTTPSInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.HTTPS) string { return r.Name }), err
+		}},
+		{"Kafka logging endpoints", func() ([]string, error) {
+			v, err := client.ListKafkas(&fastly.ListKafkasInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Kafka) string { return r.Name }), err
+		}},
+		{"Kinesis logging endpoints", func() ([]string, error) {
+			v, err := client.ListKinesis(&fastly.ListKinesisInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Kinesis) string { return r.Name }), err
+		}},
+		{"Logentries logging endpoints", func() ([]string, error) {
+			v, err := client.ListLogentries(&fastly.ListLogentriesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Logentries) string { return r.Name }), err
+		}},
+		{"Loggly logging endpoints", func() ([]string, error) {
+			v, err := client.ListLoggly(&fastly.ListLogglyInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Loggly) string { return r.Name }), err
+		}},
+		{"Log Shuttle logging endpoints", func() ([]string, error) {
+			v, err := client.ListLogshuttles(&fastly.ListLogshuttlesInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Logshuttle) string { return r.Name }), err
+		}},
+		{"New Relic logging endpoints", func() ([]string, error) {
+			v, err := client.ListNewRelic(&fastly.ListNewRelicInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.NewRelic) string { return r.Name }), err
+		}},
+		{"OpenStack logging endpoints", func() ([]string, error) {
+			v, err := client.ListOpenstack(&fastly.ListOpenstackInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Openstack) string { return r.Name }), err
+		}},
+		{"Papertrail logging endpoints", func() ([]string, error) {
+			v, err := client.ListPapertrails(&fastly.ListPapertrailsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Papertrail) string { return r.Name }), err
+		}},
+		{"S3 logging endpoints", func() ([]string, error) {
+			v, err := client.ListS3s(&fastly.ListS3sInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.S3) string { return r.Name }), err
+		}},
+		{"Scalyr logging endpoints", func() ([]string, error) {
+			v, err := client.ListScalyrs(&fastly.ListScalyrsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Scalyr) string { return r.Name }), err
+		}},
+		{"SFTP logging endpoints", func() ([]string, error) {
+			v, err := client.ListSFTPs(&fastly.ListSFTPsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.SFTP) string { return r.Name }), err
+		}},
+		{"Splunk logging endpoints", func() ([]string, error) {
+			v, err := client.ListSplunks(&fastly.ListSplunksInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Splunk) string { return r.Name }), err
+		}},
+		{"Sumo Logic logging endpoints", func() ([]string, error) {
+			v, err := client.ListSumologics(&fastly.ListSumologicsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Sumologic) string { return r.Name }), err
+		}},
+		{"Syslog logging endpoints", func() ([]string, error) {
+			v, err := client.ListSyslogs(&fastly.ListSyslogsInput{ServiceID: serviceID, ServiceVersion: version})
+			return resourceNames(v, func(r *fastly.Syslog) string { return r.Name }), err
+		}},
+	}
+
+	var uncloned []unclonedResources
+	for _, l := range listers {
+		names, err := l.list()
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %s: %w", l.kind, err)
+		}
+		if len(names) > 0 {
+			uncloned = append(uncloned, unclonedResources{Kind: l.kind, Names: names})
+		}
+	}
+	return uncloned, nil
+}
+
+// resourceNames returns the names of the resources.
+func resourceNames[T any](resources []*T, name func(*T) string) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, name(r))
+	}
+	return names
+}
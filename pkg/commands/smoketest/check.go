@@ -0,0 +1,120 @@
+package smoketest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+)
+
+// Result is the outcome of a check against a domain.
+type Result struct {
+	Check     string        `json:"check"`
+	Domain    string        `json:"domain"`
+	URL       string        `json:"url"`
+	Status    int           `json:"status,omitempty"`
+	Latency   time.Duration `json:"-"`
+	LatencyMS int64         `json:"latency_ms"`
+	Weight    float64       `json:"weight"`
+	Passed    bool          `json:"passed"`
+	Failures  []string      `json:"failures,omitempty"`
+}
+
+// run makes the check's request against the domain and asserts its response.
+func (c Check) run(client api.HTTPClient, scheme, domain string, timeout time.Duration) Result {
+	r := Result{
+		Check:  c.Name,
+		Domain: domain,
+		URL:    fmt.Sprintf("%s://%s%s", scheme, domain, c.Path),
+		Weight: c.weight(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, c.Method, r.URL, nil)
+	if err != nil {
+		return r.fail("error creating request: %s", err)
+	}
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return r.fail("error making request: %s", err)
+	}
+	defer resp.Body.Close() // #nosec G307
+	body, err := io.ReadAll(resp.Body)
+	r.Latency = time.Since(start)
+	r.LatencyMS = r.Latency.Milliseconds()
+	if err != nil {
+		return r.fail("error reading response body: %s", err)
+	}
+
+	r.Status = resp.StatusCode
+	if resp.StatusCode != c.Expect.Status {
+		r.Failures = append(r.Failures, fmt.Sprintf("status is %d, want %d", resp.StatusCode, c.Expect.Status))
+	}
+
+	headers := make([]string, 0, len(c.headers))
+	for h := range c.headers {
+		headers = append(headers, h)
+	}
+	sort.Strings(headers)
+	for _, h := range headers {
+		values, ok := resp.Header[http.CanonicalHeaderKey(h)]
+		if !ok {
+			r.Failures = append(r.Failures, fmt.Sprintf("header %s is missing", h))
+			continue
+		}
+		if !matchesAny(c.headers[h].MatchString, values) {
+			r.Failures = append(r.Failures, fmt.Sprintf("header %s doesn't match '%s'", h, c.headers[h]))
+		}
+	}
+
+	if c.body != nil && !c.body.Match(body) {
+		r.Failures = append(r.Failures, fmt.Sprintf("body doesn't match '%s'", c.body))
+	}
+	if c.Expect.MaxLatency > 0 && r.Latency > c.Expect.MaxLatency {
+		r.Failures = append(r.Failures, fmt.Sprintf("latency is %s, want at most %s", r.Latency.Round(time.Millisecond), c.Expect.MaxLatency))
+	}
+
+	r.Passed = len(r.Failures) == 0
+	return r
+}
+
+// fail records a failure that prevents the response from being asserted.
+func (r Result) fail(format string, args ...any) Result {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+	return r
+}
+
+// matchesAny reports whether any of the values match.
+func matchesAny(match func(string) bool, values []string) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// score returns the percentage of the results' total weight that passed.
+func score(results []Result) float64 {
+	var passed, total float64
+	for _, r := range results {
+		total += r.Weight
+		if r.Passed {
+			passed += r.Weight
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return passed / total * 100
+}
@@ -0,0 +1,3 @@
+// Package smoketest contains a command to run a declarative set of HTTP
+// assertions against the domains of a Fastly service.
+package smoketest
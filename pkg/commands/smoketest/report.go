@@ -0,0 +1,113 @@
+package smoketest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Report is the outcome of a smoke test.
+type Report struct {
+	ServiceID      string   `json:"service_id"`
+	ServiceVersion int      `json:"service_version"`
+	Score          float64  `json:"score"`
+	PassThreshold  float64  `json:"pass_threshold"`
+	Passed         bool     `json:"passed"`
+	Results        []Result `json:"results"`
+}
+
+// writeJSON writes the report as JSON to path.
+func (r Report) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeReport(path, data)
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the results of a domain.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is the result of a check.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure describes why a check failed.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the report as JUnit XML to path, with a test suite for
+// each domain.
+func (r Report) writeJUnit(path string) error {
+	var root junitTestSuites
+	suites := make(map[string]int)
+	times := make(map[string]time.Duration)
+	for _, res := range r.Results {
+		i, ok := suites[res.Domain]
+		if !ok {
+			i = len(root.Suites)
+			suites[res.Domain] = i
+			root.Suites = append(root.Suites, junitTestSuite{Name: res.Domain})
+		}
+		s := &root.Suites[i]
+
+		tc := junitTestCase{
+			Name:      res.Check,
+			ClassName: res.Domain,
+			Time:      seconds(res.Latency),
+		}
+		if !res.Passed {
+			tc.Failure = &junitFailure{
+				Message: res.Failures[0],
+				Text:    fmt.Sprintf("%s failed:\n%s", res.URL, strings.Join(res.Failures, "\n")),
+			}
+			s.Failures++
+		}
+		s.Tests++
+		s.Cases = append(s.Cases, tc)
+		times[res.Domain] += res.Latency
+	}
+	for i := range root.Suites {
+		root.Suites[i].Time = seconds(times[root.Suites[i].Name])
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeReport(path, append([]byte(xml.Header), data...))
+}
+
+// seconds formats a duration as seconds, as JUnit expects.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeReport writes the report data to path.
+func writeReport(path string, data []byte) error {
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil { // #nosec G306
+		return fmt.Errorf("error writing report '%s': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package smoketest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *RootCommand {
+	var c RootCommand
+	c.CmdClause = parent.Command("smoke-test", "Run a declarative set of HTTP assertions against the domains of a service")
+	c.Globals = g
+	c.manifest = m
+
+	// required
+	c.CmdClause.Flag("spec", "Path to a YAML file of the checks to run against each domain").Required().StringVar(&c.spec)
+
+	// optional
+	c.CmdClause.Flag("report-json", "Path to write a JSON report of the results to").StringVar(&c.reportJSON)
+	c.CmdClause.Flag("report-junit", "Path to write a JUnit XML report of the results to").StringVar(&c.reportJUnit)
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagVersionName,
+		Description: "The version whose domains are tested ('active' by default, or 'latest' if no version is active)",
+		Dst:         &c.serviceVersion.Value,
+	})
+
+	return &c
+}
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	cmd.Base
+
+	manifest       manifest.Data
+	reportJSON     string
+	reportJUnit    string
+	serviceName    cmd.OptionalServiceNameID
+	serviceVersion cmd.OptionalServiceVersion
+	spec           string
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	spec, err := ReadSpec(c.spec)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, serviceVersion, err := cmd.ServiceDetails(cmd.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           c.manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": errors.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersion.Number,
+		})
+		return fmt.Errorf("error listing domains: %w", err)
+	}
+	names, err := selectDomains(domains, spec.Domains)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	report := Report{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersion.Number,
+		PassThreshold:  spec.threshold(),
+	}
+	for _, domain := range names {
+		text.Break(out)
+		text.Output(out, "%s %s", text.Bold("Testing"), domain)
+		for _, check := range spec.Checks {
+			if len(check.Domains) > 0 && !contains(check.Domains, domain) {
+				continue
+			}
+			r := check.run(c.Globals.HTTPClient, spec.Scheme, domain, spec.Timeout)
+			report.Results = append(report.Results, r)
+			if r.Passed {
+				text.Output(out, "%s %s (%dms)", text.BoldGreen("✓"), check.Name, r.LatencyMS)
+				continue
+			}
+			text.Output(out, "%s %s: %s", text.BoldRed("✗"), check.Name, strings.Join(r.Failures, ", "))
+		}
+	}
+	if len(report.Results) == 0 {
+		err := errors.RemediationError{
+			Inner:       fmt.Errorf("no checks apply to the domains: %s", strings.Join(names, ", ")),
+			Remediation: "Ensure the domains of the checks in the spec are domains of the service.",
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	report.Score = score(report.Results)
+	report.Passed = report.Score >= report.PassThreshold
+
+	if c.reportJSON != "" {
+		if err := report.writeJSON(c.reportJSON); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+	if c.reportJUnit != "" {
+		if err := report.writeJUnit(c.reportJUnit); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+
+	var passed int
+	for _, r := range report.Results {
+		if r.Passed {
+			passed++
+		}
+	}
+	text.Break(out)
+	if !report.Passed {
+		return fmt.Errorf("smoke test failed: %d of %d checks passed, a weighted score of %.1f%% (the threshold is %g%%)", passed, len(report.Results), report.Score, report.PassThreshold)
+	}
+	text.Success(out, "Smoke test passed: %d of %d checks passed, a weighted score of %.1f%%", passed, len(report.Results), report.Score)
+	return nil
+}
+
+// selectDomains returns the names of the service's domains to test, which are
+// restricted to those in the spec (if any). Wildcard domains are skipped as
+// they can't be requested.
+func selectDomains(domains []*fastly.Domain, only []string) ([]string, error) {
+	var names []string
+	for _, d := range domains {
+		if strings.Contains(d.Name, "*") {
+			continue
+		}
+		if len(only) > 0 && !contains(only, d.Name) {
+			continue
+		}
+		names = append(names, d.Name)
+	}
+	sort.Strings(names)
+
+	for _, o := range only {
+		if !contains(names, o) {
+			return nil, errors.RemediationError{
+				Inner:       fmt.Errorf("'%s' isn't a domain of the service", o),
+				Remediation: "Update the domains of the spec to the service's domains (see `fastly domain list`).",
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("the service has no domains to test"),
+			Remediation: "Add a domain to the service (see `fastly domain create`), or select another version via --version.",
+		}
+	}
+	return names, nil
+}
+
+// contains reports whether the values contain s.
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+package smoketest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/smoketest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestSmokeTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<h1>Welcome</h1>")
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/auth":
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	domain := strings.TrimPrefix(srv.URL, "http://")
+
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{
+				{Name: domain, ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion},
+				{Name: "*.example.com", ServiceID: i.ServiceID, ServiceVersion: i.ServiceVersion},
+			}, nil
+		},
+	}
+
+	checks := `
+checks:
+  - name: homepage
+    path: /
+    weight: 3
+    expect:
+      headers:
+        content-type: ^text/html
+      body: Welcome
+  - name: missing
+    path: /missing
+    expect:
+      status: 404
+  - name: auth
+    path: /auth
+    headers:
+      Authorization: Bearer 123
+`
+
+	scenarios := []struct {
+		name       string
+		api        mock.API
+		args       string
+		spec       string
+		wantError  string
+		wantOutput []string
+	}{
+		{
+			name:      "validate missing --spec flag",
+			args:      "smoke-test --service-id 123 --token 123",
+			wantError: "error parsing arguments: required flag --spec not provided",
+		},
+		{
+			name:      "validate invalid spec",
+			args:      "smoke-test --service-id 123 --token 123",
+			spec:      "checks:\n  - name: homepage\n    path: /\n    expect:\n      status: ok\n",
+			wantError: "error parsing smoke test spec",
+		},
+		{
+			name:      "validate unknown spec field",
+			args:      "smoke-test --service-id 123 --token 123",
+			spec:      "checks:\n  - name: homepage\n    url: /\n",
+			wantError: "field url not found",
+		},
+		{
+			name:      "validate invalid body pattern",
+			args:      "smoke-test --service-id 123 --token 123",
+			spec:      "checks:\n  - name: homepage\n    path: /\n    expect:\n      body: '('\n",
+			wantError: "check 'homepage' has an invalid body pattern",
+		},
+		{
+			name:      "validate spec domain isn't a domain of the service",
+			api:       api,
+			args:      "smoke-test --service-id 123 --token 123",
+			spec:      "scheme: http\ndomains: [www.example.org]\n" + checks,
+			wantError: "'www.example.org' isn't a domain of the service",
+		},
+		{
+			name: "validate ListDomains API error",
+			api: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+					return nil, testutil.Err
+				},
+			},
+			args:      "smoke-test --service-id 123 --token 123",
+			spec:      "scheme: http\n" + checks,
+			wantError: "error listing domains: test error",
+		},
+		{
+			name: "success",
+			api:  api,
+			args: "smoke-test --service-id 123 --token 123",
+			spec: "scheme: http\n" + checks,
+			wantOutput: []string{
+				"Testing " + domain,
+				"✓ homepage",
+				"✓ missing",
+				"✓ auth",
+				"Smoke test passed: 3 of 3 checks passed, a weighted score of 100.0%",
+			},
+		},
+		{
+			name: "failure below the threshold",
+			api:  api,
+			args: "smoke-test --service-id 123 --token 123",
+			spec: "scheme: http\npass_threshold: 90\n" + checks + `
+  - name: slow
+    path: /slow
+    expect:
+      body: Welcome
+      max_latency: 1ms
+`,
+			wantError: "smoke test failed: 3 of 4 checks passed, a weighted score of 83.3% (the threshold is 90%)",
+			wantOutput: []string{
+				"✗ slow: body doesn't match 'Welcome', latency is",
+			},
+		},
+		{
+			name: "failure within the threshold",
+			api:  api,
+			args: "smoke-test --service-id 123 --token 123",
+			spec: "scheme: http\npass_threshold: 80\n" + checks + `
+  - name: unauthorized
+    path: /auth
+    expect:
+      headers:
+        x-cache: HIT
+`,
+			wantOutput: []string{
+				"✗ unauthorized: status is 401, want 200, header x-cache is missing",
+				"Smoke test passed: 3 of 4 checks passed, a weighted score of 83.3%",
+			},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testcase.args
+			if testcase.spec != "" {
+				path := filepath.Join(t.TempDir(), "smoke.yaml")
+				if err := os.WriteFile(path, []byte(testcase.spec), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				args += " --spec " + path
+			}
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestSmokeTestReports(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	domain := strings.TrimPrefix(srv.URL, "http://")
+
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "smoke.yaml")
+	data := "scheme: http\nchecks:\n  - name: homepage\n    path: /\n  - name: about\n    path: /about\n    weight: 0.5\n"
+	if err := os.WriteFile(spec, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jsonReport := filepath.Join(dir, "report.json")
+	junitReport := filepath.Join(dir, "report.xml")
+
+	var stdout bytes.Buffer
+	args := fmt.Sprintf("smoke-test --service-id 123 --version 1 --token 123 --spec %s --report-json %s --report-junit %s", spec, jsonReport, junitReport)
+	opts := testutil.NewRunOpts(testutil.Args(args), &stdout)
+	opts.APIClient = mock.APIClient(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{{Name: domain}}, nil
+		},
+	})
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "smoke test failed: 1 of 2 checks passed, a weighted score of 66.7%")
+
+	var report smoketest.Report
+	if err := json.Unmarshal([]byte(readFile(t, jsonReport)), &report); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "123", report.ServiceID)
+	testutil.AssertEqual(t, 1, report.ServiceVersion)
+	testutil.AssertBool(t, false, report.Passed)
+	testutil.AssertEqual(t, 2, len(report.Results))
+	testutil.AssertEqual(t, []string{"status is 404, want 200"}, report.Results[1].Failures)
+
+	var junit struct {
+		Suites []struct {
+			Name     string `xml:"name,attr"`
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			Cases    []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal([]byte(readFile(t, junitReport)), &junit); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, 1, len(junit.Suites))
+	testutil.AssertString(t, domain, junit.Suites[0].Name)
+	testutil.AssertEqual(t, 2, junit.Suites[0].Tests)
+	testutil.AssertEqual(t, 1, junit.Suites[0].Failures)
+	if junit.Suites[0].Cases[0].Failure != nil {
+		t.Error("want the homepage check to pass")
+	}
+	testutil.AssertString(t, "status is 404, want 200", junit.Suites[0].Cases[1].Failure.Message)
+}
+
+// readFile returns the content of the file at path.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
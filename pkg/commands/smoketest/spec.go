@@ -0,0 +1,187 @@
+package smoketest
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultTimeout is the time each request is given when the spec doesn't set
+// a timeout.
+const DefaultTimeout = 10 * time.Second
+
+// specRemediation explains the format of a smoke test spec.
+const specRemediation = `A smoke test spec is a YAML file such as:
+
+  scheme: https          # optional, 'https' (default) or 'http'
+  timeout: 10s           # optional, per request
+  pass_threshold: 100    # optional, the percentage of the checks' weight that must pass
+  domains: [example.com] # optional, defaults to all the service's domains
+  checks:
+    - name: homepage
+      path: /
+      weight: 2          # optional, defaults to 1
+      expect:
+        status: 200      # optional, defaults to 200
+        headers:
+          content-type: ^text/html
+        body: Welcome
+        max_latency: 500ms`
+
+// Spec is a declarative set of HTTP assertions.
+type Spec struct {
+	// Checks are the requests made against each domain.
+	Checks []Check `yaml:"checks"`
+	// Domains restricts the checks to these domains of the service.
+	Domains []string `yaml:"domains"`
+	// PassThreshold is the percentage of the checks' total weight that must
+	// pass for the smoke test to pass (defaults to 100).
+	PassThreshold *float64 `yaml:"pass_threshold"`
+	// Scheme is the URL scheme used for each request (defaults to https).
+	Scheme string `yaml:"scheme"`
+	// Timeout is the maximum time for each request (defaults to DefaultTimeout).
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Check is a single request and the assertions made about its response.
+type Check struct {
+	// Domains restricts the check to these domains of the service.
+	Domains []string `yaml:"domains"`
+	// Expect are the assertions made about the response.
+	Expect Expect `yaml:"expect"`
+	// Headers are the request headers.
+	Headers map[string]string `yaml:"headers"`
+	// Method is the request method (defaults to GET).
+	Method string `yaml:"method"`
+	// Name identifies the check in the output and reports.
+	Name string `yaml:"name"`
+	// Path is the request path, including any query string.
+	Path string `yaml:"path"`
+	// Weight is the check's share of the score (defaults to 1).
+	Weight *float64 `yaml:"weight"`
+
+	body    *regexp.Regexp
+	headers map[string]*regexp.Regexp
+}
+
+// Expect are the assertions made about a response.
+type Expect struct {
+	// Body is a regular expression the response body must match.
+	Body string `yaml:"body"`
+	// Headers maps each header to a regular expression its value must match.
+	Headers map[string]string `yaml:"headers"`
+	// MaxLatency is the maximum time to receive the whole response.
+	MaxLatency time.Duration `yaml:"max_latency"`
+	// Status is the expected status code (defaults to 200).
+	Status int `yaml:"status"`
+}
+
+// weight returns the check's weight.
+func (c Check) weight() float64 {
+	if c.Weight == nil {
+		return 1
+	}
+	return *c.Weight
+}
+
+// threshold returns the percentage of the total weight that must pass.
+func (s Spec) threshold() float64 {
+	if s.PassThreshold == nil {
+		return 100
+	}
+	return *s.PassThreshold
+}
+
+// ReadSpec reads and validates the smoke test spec at path.
+func ReadSpec(path string) (*Spec, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we trust the user's choice of spec.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading smoke test spec: %w", err)
+	}
+
+	var s Spec
+	if err := yaml.UnmarshalStrict(data, &s); err != nil {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("error parsing smoke test spec '%s': %w", path, err),
+			Remediation: specRemediation,
+		}
+	}
+	if err := s.validate(); err != nil {
+		return nil, errors.RemediationError{
+			Inner:       fmt.Errorf("invalid smoke test spec '%s': %w", path, err),
+			Remediation: specRemediation,
+		}
+	}
+	return &s, nil
+}
+
+// validate checks the spec, setting its defaults and compiling its regular
+// expressions.
+func (s *Spec) validate() error {
+	if len(s.Checks) == 0 {
+		return fmt.Errorf("no checks")
+	}
+	switch s.Scheme {
+	case "":
+		s.Scheme = "https"
+	case "http", "https":
+	default:
+		return fmt.Errorf("unsupported scheme '%s'", s.Scheme)
+	}
+	if s.Timeout == 0 {
+		s.Timeout = DefaultTimeout
+	}
+	if t := s.threshold(); t < 0 || t > 100 {
+		return fmt.Errorf("pass_threshold %g isn't between 0 and 100", t)
+	}
+
+	names := make(map[string]bool)
+	for i := range s.Checks {
+		c := &s.Checks[i]
+		if c.Name == "" {
+			return fmt.Errorf("check %d has no name", i+1)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate check '%s'", c.Name)
+		}
+		names[c.Name] = true
+
+		if !strings.HasPrefix(c.Path, "/") {
+			return fmt.Errorf("check '%s' has a path that doesn't start with '/'", c.Name)
+		}
+		if c.Method == "" {
+			c.Method = http.MethodGet
+		}
+		c.Method = strings.ToUpper(c.Method)
+		if c.weight() < 0 {
+			return fmt.Errorf("check '%s' has a negative weight", c.Name)
+		}
+		if c.Expect.Status == 0 {
+			c.Expect.Status = http.StatusOK
+		}
+
+		var err error
+		if c.Expect.Body != "" {
+			if c.body, err = regexp.Compile(c.Expect.Body); err != nil {
+				return fmt.Errorf("check '%s' has an invalid body pattern: %w", c.Name, err)
+			}
+		}
+		c.headers = make(map[string]*regexp.Regexp, len(c.Expect.Headers))
+		for h, pattern := range c.Expect.Headers {
+			if c.headers[h], err = regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("check '%s' has an invalid pattern for header '%s': %w", c.Name, h, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// aggregateLag is how many seconds of stats are waited for from the other
+// services before a second is merged without the services that haven't
+// reported it yet.
+const aggregateLag = 5
+
+// Aggregator merges the realtime stats of several services, writing each
+// second of the stats of every service along with their sum.
+type Aggregator struct {
+	Client     api.RealtimeStatsInterface
+	ServiceIDs []string
+	// JSON writes each second as a line of JSON, rather than as a table.
+	JSON bool
+	// Out receives the warnings.
+	Out io.Writer
+}
+
+// AggregatedBlock is a second of the merged stats.
+type AggregatedBlock struct {
+	Recorded   float64                      `json:"recorded"`
+	Aggregated statsResponseData            `json:"aggregated"`
+	Services   map[string]statsResponseData `json:"services"`
+}
+
+// serviceBlock is a second of the stats of a service.
+type serviceBlock struct {
+	serviceID string
+	block     realtimeResponseData
+}
+
+// Run polls the realtime stats of each service until ctx is done, writing the
+// merged stats of each second, in order, to w.
+//
+// NOTE: A failure to fetch the stats of a service is reported but doesn't
+// stop the aggregator, as the realtime stats API can be briefly unavailable.
+func (a Aggregator) Run(ctx context.Context, w io.Writer) error {
+	blocks := make(chan serviceBlock)
+	var wg sync.WaitGroup
+	for _, serviceID := range a.ServiceIDs {
+		wg.Add(1)
+		go func(serviceID string) {
+			defer wg.Done()
+			a.poll(ctx, serviceID, blocks)
+		}(serviceID)
+	}
+	go func() {
+		wg.Wait()
+		close(blocks)
+	}()
+
+	var (
+		pending = make(map[float64]*AggregatedBlock)
+		newest  float64
+		written float64
+	)
+	// write writes the pending seconds that are complete, or that the other
+	// services are too far behind to wait for, or all of them if flush is set.
+	write := func(flush bool) error {
+		recorded := make([]float64, 0, len(pending))
+		for r := range pending {
+			recorded = append(recorded, r)
+		}
+		sort.Float64s(recorded)
+		for _, r := range recorded {
+			b := pending[r]
+			if !flush && len(b.Services) < len(a.ServiceIDs) && newest-r < aggregateLag {
+				break
+			}
+			if err := a.write(w, b); err != nil {
+				return err
+			}
+			delete(pending, r)
+			written = r
+		}
+		return nil
+	}
+
+	for sb := range blocks {
+		r := sb.block.Recorded
+		if r <= written {
+			// The second has already been written without this service.
+			continue
+		}
+		b, ok := pending[r]
+		if !ok {
+			b = &AggregatedBlock{
+				Recorded:   r,
+				Aggregated: make(statsResponseData),
+				Services:   make(map[string]statsResponseData, len(a.ServiceIDs)),
+			}
+			pending[r] = b
+		}
+		b.Services[sb.serviceID] = sb.block.Aggregated
+		for k, v := range sb.block.Aggregated {
+			if n, ok := v.(float64); ok {
+				sum, _ := b.Aggregated[k].(float64)
+				b.Aggregated[k] = sum + n
+			}
+		}
+		if r > newest {
+			newest = r
+		}
+
+		if err := write(false); err != nil {
+			// Drain the remaining blocks so the pollers can stop.
+			go func() {
+				for range blocks {
+				}
+			}()
+			return err
+		}
+	}
+	return write(true)
+}
+
+// poll sends each second of the stats of a service until ctx is done.
+func (a Aggregator) poll(ctx context.Context, serviceID string, blocks chan<- serviceBlock) {
+	var timestamp uint64
+	for {
+		var envelope realtimeResponse
+		err := a.Client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: serviceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err != nil {
+			text.Warning(a.Out, "Failed to fetch the realtime stats of service %s: %s", serviceID, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		timestamp = envelope.Timestamp
+
+		for _, block := range envelope.Data {
+			blocks <- serviceBlock{serviceID: serviceID, block: block}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// write writes a second of the merged stats.
+func (a Aggregator) write(w io.Writer, b *AggregatedBlock) error {
+	if a.JSON {
+		if err := json.NewEncoder(w).Encode(b); err != nil {
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s (%d of %d services)\n", time.Unix(int64(b.Recorded), 0).UTC().Format(time.RFC3339), len(b.Services), len(a.ServiceIDs))
+	t := text.NewTable(w)
+	t.AddHeader("SERVICE", "REQ/S", "HIT RATIO", "ERRORS/S", "5XX/S", "BANDWIDTH")
+	rows := []dashboardRow{newDashboardRow("TOTAL", b.Aggregated)}
+	for _, serviceID := range a.ServiceIDs {
+		if data, ok := b.Services[serviceID]; ok {
+			rows = append(rows, newDashboardRow(serviceID, data))
+		}
+	}
+	for _, r := range rows {
+		t.AddLine(r.name, fmt.Sprintf("%.0f", r.requests), ratio(r.hits, r.hits+r.miss), fmt.Sprintf("%.0f", r.errors), fmt.Sprintf("%.0f", r.status5xx), bandwidth(r.bandwidth))
+	}
+	t.Print()
+	text.Break(w)
+	return nil
+}
@@ -0,0 +1,132 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestRealtimeServicesFlags(t *testing.T) {
+	for _, testcase := range []struct {
+		args      string
+		api       mock.API
+		wantError string
+	}{
+		{
+			args:      "stats realtime --services 123,456 --all-services",
+			wantError: "--all-services can't be used with --services",
+		},
+		{
+			args:      "stats realtime --services 123,456 --dashboard",
+			wantError: "--dashboard can't be used with --services or --all-services",
+		},
+		{
+			args: "stats realtime --all-services",
+			api: mock.API{
+				NewListServicesPaginatorFn: func(i *fastly.ListServicesInput) fastly.PaginatorServices {
+					return &servicesPaginator{}
+				},
+			},
+			wantError: "no services found",
+		},
+	} {
+		t.Run(testcase.args, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+// servicesPaginator returns a single page of services.
+type servicesPaginator struct {
+	services []*fastly.Service
+	done     bool
+}
+
+func (p *servicesPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p *servicesPaginator) Remaining() int {
+	return 0
+}
+
+func (p *servicesPaginator) GetNext() ([]*fastly.Service, error) {
+	p.done = true
+	return p.services, nil
+}
+
+func TestAggregator(t *testing.T) {
+	blocks := map[string][]string{
+		"abc": {
+			`{"recorded": 1672531200, "aggregated": {"requests": 10, "hits": 8, "miss": 2}}`,
+			`{"recorded": 1672531201, "aggregated": {"requests": 5, "hits": 5, "bandwidth": 2048}}`,
+		},
+		"def": {
+			`{"recorded": 1672531200, "aggregated": {"requests": 2, "miss": 2, "status_5xx": 1}}`,
+		},
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		json       bool
+		wantOutput string
+	}{
+		{
+			// The second recorded only by abc is written once the aggregator
+			// stops, as def might otherwise have reported it later.
+			name: "json",
+			json: true,
+			wantOutput: `{"recorded":1672531200,"aggregated":{"hits":8,"miss":4,"requests":12,"status_5xx":1},"services":{"abc":{"hits":8,"miss":2,"requests":10},"def":{"miss":2,"requests":2,"status_5xx":1}}}
+{"recorded":1672531201,"aggregated":{"bandwidth":2048,"hits":5,"requests":5},"services":{"abc":{"bandwidth":2048,"hits":5,"requests":5}}}
+`,
+		},
+		{
+			name: "text",
+			wantOutput: `2023-01-01T00:00:00Z (2 of 2 services)
+SERVICE  REQ/S  HIT RATIO  ERRORS/S  5XX/S  BANDWIDTH
+TOTAL    12     66.7%      0         1      0.0 B/s
+abc      10     80.0%      0         0      0.0 B/s
+def      2      0.0%       0         1      0.0 B/s
+
+2023-01-01T00:00:01Z (1 of 2 services)
+SERVICE  REQ/S  HIT RATIO  ERRORS/S  5XX/S  BANDWIDTH
+TOTAL    5      100.0%     0         0      2.0 KB/s
+abc      5      100.0%     0         0      2.0 KB/s
+
+`,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var out bytes.Buffer
+			a := stats.Aggregator{
+				Client: &servicesClient{
+					blocks:    blocks,
+					calls:     make(map[string]int),
+					remaining: 3,
+					cancel:    cancel,
+				},
+				ServiceIDs: []string{"abc", "def"},
+				JSON:       testcase.json,
+				Out:        &out,
+			}
+			var stdout bytes.Buffer
+			err := a.Run(ctx, &stdout)
+			testutil.AssertNoError(t, err)
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+			testutil.AssertString(t, "", out.String())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// The ANSI escape sequences used to redraw the dashboard in place.
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+)
+
+// Dashboard renders the realtime stats of a service as a table of its busiest
+// datacenters, redrawn in place after each second of stats like top(1).
+type Dashboard struct {
+	Client    api.RealtimeStatsInterface
+	ServiceID string
+	// Rows limits the number of datacenters displayed, busiest first. Zero
+	// displays every datacenter.
+	Rows int
+}
+
+// dashboardRow is the stats of a datacenter, or of the service, for a second.
+type dashboardRow struct {
+	name      string
+	requests  float64
+	hits      float64
+	miss      float64
+	errors    float64
+	status5xx float64
+	bandwidth float64
+}
+
+// newDashboardRow reads the metrics of a block of stats.
+func newDashboardRow(name string, data statsResponseData) dashboardRow {
+	metric := func(k string) float64 {
+		v, _ := data[k].(float64)
+		return v
+	}
+	return dashboardRow{
+		name:      name,
+		requests:  metric("requests"),
+		hits:      metric("hits"),
+		miss:      metric("miss"),
+		errors:    metric("errors"),
+		status5xx: metric("status_5xx"),
+		bandwidth: metric("bandwidth"),
+	}
+}
+
+// Run polls the realtime stats until ctx is done, redrawing the dashboard on
+// w after each block of stats.
+//
+// NOTE: A failure to fetch the stats is displayed in place of the last stats,
+// rather than stopping the dashboard, as the realtime stats API can be
+// briefly unavailable.
+func (d Dashboard) Run(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, hideCursor); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.WriteString(w, showCursor)
+	}()
+
+	var (
+		timestamp uint64
+		started   = time.Now()
+		total     dashboardRow
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		var envelope realtimeResponse
+		err := d.Client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: d.ServiceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err != nil {
+			var buf bytes.Buffer
+			buf.WriteString(clearScreen)
+			d.header(&buf, started, total)
+			text.Warning(&buf, "Failed to fetch the realtime stats: %s", err)
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		timestamp = envelope.Timestamp
+
+		for _, block := range envelope.Data {
+			service := newDashboardRow("TOTAL", block.Aggregated)
+			total.requests += service.requests
+			total.errors += service.errors
+
+			var buf bytes.Buffer
+			buf.WriteString(clearScreen)
+			d.render(&buf, started, total, block, service)
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// header renders the service and the totals since the dashboard started.
+func (d Dashboard) header(w io.Writer, started time.Time, total dashboardRow) {
+	fmt.Fprintf(w, "%s %s (press ^C to quit)\n", text.Bold("Service"), d.ServiceID)
+	fmt.Fprintf(w, "Since %s: %.0f requests, %.0f errors (%s)\n\n", started.Format("15:04:05"), total.requests, total.errors, ratio(total.errors, total.requests))
+}
+
+// render renders the header followed by the stats of the service and each of
+// its datacenters, busiest first.
+func (d Dashboard) render(w io.Writer, started time.Time, total dashboardRow, block realtimeResponseData, service dashboardRow) {
+	d.header(w, started, total)
+
+	rows := make([]dashboardRow, 0, len(block.Datacenter))
+	for pop, data := range block.Datacenter {
+		rows = append(rows, newDashboardRow(pop, data))
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].requests != rows[j].requests {
+			return rows[i].requests > rows[j].requests
+		}
+		return rows[i].name < rows[j].name
+	})
+	hidden := 0
+	if d.Rows > 0 && len(rows) > d.Rows {
+		rows, hidden = rows[:d.Rows], len(rows)-d.Rows
+	}
+
+	t := text.NewTable(w)
+	t.AddHeader("DATACENTER", "REQ/S", "HIT RATIO", "ERRORS/S", "5XX/S", "BANDWIDTH")
+	for _, r := range append([]dashboardRow{service}, rows...) {
+		t.AddLine(r.name, fmt.Sprintf("%.0f", r.requests), ratio(r.hits, r.hits+r.miss), fmt.Sprintf("%.0f", r.errors), fmt.Sprintf("%.0f", r.status5xx), bandwidth(r.bandwidth))
+	}
+	t.Print()
+	if hidden > 0 {
+		fmt.Fprintf(w, "... and %d more datacenters\n", hidden)
+	}
+	fmt.Fprintf(w, "\nRecorded at %s\n", time.Unix(int64(block.Recorded), 0).UTC().Format(time.RFC3339))
+}
+
+// ratio formats n/d as a percentage, or a dash when d is zero.
+func ratio(n, d float64) string {
+	if d == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", n/d*100)
+}
+
+// bandwidth formats bytes per second with a binary unit.
+func bandwidth(b float64) string {
+	units := []string{"B/s", "KB/s", "MB/s", "GB/s"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
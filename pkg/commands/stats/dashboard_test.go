@@ -0,0 +1,90 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestRealtimeDashboardFlags(t *testing.T) {
+	for _, testcase := range []struct {
+		args      string
+		wantError string
+	}{
+		{
+			args:      "stats realtime --service-id 123 --dashboard --format json",
+			wantError: "--dashboard can't be used with --format",
+		},
+		{
+			args:      "stats realtime --service-id 123 --dashboard",
+			wantError: "--dashboard requires a terminal",
+		},
+	} {
+		t.Run(testcase.args, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+// datacenterClient returns a single block of stats, cancelling the context
+// once it's been returned.
+type datacenterClient struct {
+	block  string
+	cancel context.CancelFunc
+}
+
+func (c *datacenterClient) GetRealtimeStatsJSON(_ *fastly.GetRealtimeStatsInput, dst any) error {
+	c.cancel()
+	return json.Unmarshal([]byte(`{"timestamp": 1, "data": [`+c.block+`]}`), dst)
+}
+
+func TestDashboard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	block := `{
+		"recorded": 1672531200,
+		"aggregated": {"requests": 30, "hits": 18, "miss": 2, "errors": 3, "status_5xx": 3, "bandwidth": 3145728},
+		"datacenter": {
+			"LHR": {"requests": 20, "hits": 15, "miss": 0, "errors": 1, "status_5xx": 1, "bandwidth": 2097152},
+			"JFK": {"requests": 8, "hits": 3, "miss": 2, "errors": 2, "status_5xx": 2, "bandwidth": 1048576},
+			"SYD": {"requests": 2, "bandwidth": 512}
+		}
+	}`
+	var out bytes.Buffer
+	d := stats.Dashboard{
+		Client:    &datacenterClient{block: block, cancel: cancel},
+		ServiceID: "123",
+		Rows:      2,
+	}
+	err := d.Run(ctx, &out)
+	testutil.AssertNoError(t, err)
+
+	got := out.String()
+	for _, s := range []string{
+		"Service 123 (press ^C to quit)",
+		"30 requests, 3 errors (10.0%)",
+		"DATACENTER  REQ/S  HIT RATIO  ERRORS/S  5XX/S  BANDWIDTH",
+		"TOTAL       30     90.0%      3         3      3.0 MB/s",
+		"LHR         20     100.0%     1         1      2.0 MB/s",
+		"JFK         8      60.0%      2         2      1.0 MB/s",
+		"... and 1 more datacenters",
+		"Recorded at 2023-01-01T00:00:00Z",
+	} {
+		testutil.AssertStringContains(t, got, s)
+	}
+	testutil.AssertStringDoesntContain(t, got, "SYD")
+	if !strings.HasSuffix(got, "\x1b[?25h") {
+		t.Errorf("want the cursor to be restored, got %q", got)
+	}
+}
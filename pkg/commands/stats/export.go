@@ -0,0 +1,360 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+	"github.com/fastly/kingpin"
+)
+
+// ExportCommand serves the stats of services for Prometheus to scrape.
+type ExportCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	historicalInterval time.Duration
+	listen             string
+	metrics            []string
+	serviceName        cmd.OptionalServiceNameID
+	services           []string
+}
+
+// NewExportCommand is the "stats export" subcommand.
+func NewExportCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *ExportCommand {
+	var c ExportCommand
+	c.Globals = g
+	c.manifest = m
+
+	c.CmdClause = parent.Command("export", "Serve the realtime and historical stats of Fastly services in the Prometheus exposition format")
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	c.CmdClause.Flag("historical-interval", "How often the historical stats, recorded per minute, are fetched when scraped").Default("1m").DurationVar(&c.historicalInterval)
+	c.CmdClause.Flag("listen", "Address (host:port) to serve the metrics on, at /metrics").Default(":9100").StringVar(&c.listen)
+	c.CmdClause.Flag("metric", fmt.Sprintf("A stats metric to export, set once per metric (default: %s)", strings.Join(DefaultPushMetrics, ", "))).StringsVar(&c.metrics)
+	c.CmdClause.Flag("services", "A comma-separated list of alphanumeric strings identifying the services to export (default: the --service-id or --service-name service)").StringsVar(&c.services, kingpin.Separator(","))
+
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *ExportCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.historicalInterval < time.Minute {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --historical-interval: %s", c.historicalInterval),
+			Remediation: "Provide an interval of at least 1m, as historical stats are recorded every minute.",
+		}
+	}
+
+	services := c.services
+	if len(services) == 0 {
+		serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return err
+		}
+		if c.Globals.Verbose() {
+			cmd.DisplayServiceID(serviceID, flag, source, out)
+		}
+		services = []string{serviceID}
+	}
+
+	metrics := c.metrics
+	if len(metrics) == 0 {
+		metrics = DefaultPushMetrics
+	}
+
+	ln, err := net.Listen("tcp", c.listen)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error listening on %s: %w", c.listen, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	e := &Exporter{
+		Client:             c.Globals.APIClient,
+		RTSClient:          c.Globals.RTSClient,
+		ServiceIDs:         services,
+		Metrics:            metrics,
+		HistoricalInterval: c.historicalInterval,
+		Out:                out,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+			cancel()
+		}
+	}()
+
+	text.Info(out, "Serving %d metrics of %s at http://%s/metrics (press ^C to stop)", len(metrics), strings.Join(services, ", "), ln.Addr())
+	text.Break(out)
+
+	err = e.Run(ctx)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	_ = srv.Shutdown(shutdownCtx)
+	select {
+	case serr := <-serveErr:
+		err = fmt.Errorf("error serving metrics: %w", serr)
+	default:
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": services,
+			"Listen":   c.listen,
+		})
+		return err
+	}
+	return nil
+}
+
+// Exporter serves the stats of services in the Prometheus exposition format.
+//
+// The realtime stats are exported as counters of each metric summed since the
+// exporter started, and the historical stats as gauges of each metric for the
+// most recently recorded minute.
+type Exporter struct {
+	Client     api.Interface
+	RTSClient  api.RealtimeStatsInterface
+	ServiceIDs []string
+	Metrics    []string
+	// HistoricalInterval is how long the historical stats are cached for, so
+	// that frequent scrapes don't exhaust the API rate limit.
+	HistoricalInterval time.Duration
+	// Out receives the warnings.
+	Out io.Writer
+
+	mu         sync.Mutex
+	realtime   map[string]map[string]float64
+	historical map[string]historicalSample
+}
+
+// historicalSample is the most recent historical stats of a service.
+type historicalSample struct {
+	fetched time.Time
+	data    statsResponseData
+}
+
+// Run polls the realtime stats of each service until ctx is done.
+//
+// NOTE: A failure to fetch the stats is reported but doesn't stop the
+// exporter, as the realtime stats API can be briefly unavailable.
+func (e *Exporter) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, serviceID := range e.ServiceIDs {
+		wg.Add(1)
+		go func(serviceID string) {
+			defer wg.Done()
+			e.poll(ctx, serviceID)
+		}(serviceID)
+	}
+	wg.Wait()
+	return nil
+}
+
+// poll sums the realtime stats of a service until ctx is done.
+func (e *Exporter) poll(ctx context.Context, serviceID string) {
+	var timestamp uint64
+	for {
+		var envelope realtimeResponse
+		err := e.RTSClient.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: serviceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err != nil {
+			e.warn("Failed to fetch the realtime stats of service %s: %s", serviceID, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		timestamp = envelope.Timestamp
+
+		e.mu.Lock()
+		if e.realtime == nil {
+			e.realtime = make(map[string]map[string]float64)
+		}
+		sums, ok := e.realtime[serviceID]
+		if !ok {
+			sums = make(map[string]float64, len(e.Metrics))
+			e.realtime[serviceID] = sums
+		}
+		for _, block := range envelope.Data {
+			for _, m := range e.Metrics {
+				if v, ok := block.Aggregated[m].(float64); ok {
+					sums[m] += v
+				}
+			}
+		}
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// ServeHTTP implements the http.Handler interface, writing the metrics of
+// each service.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	historical := make(map[string]statsResponseData, len(e.ServiceIDs))
+	for _, serviceID := range e.ServiceIDs {
+		if data, err := e.historicalStats(serviceID); err != nil {
+			e.warn("Failed to fetch the historical stats of service %s: %s", serviceID, err)
+		} else {
+			historical[serviceID] = data
+		}
+	}
+
+	var b strings.Builder
+	e.mu.Lock()
+	for _, m := range e.Metrics {
+		name := "fastly_realtime_" + metricName(m) + "_total"
+		fmt.Fprintf(&b, "# HELP %s The %s realtime metric summed since the exporter started.\n", name, m)
+		fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		for _, serviceID := range e.sortedServiceIDs() {
+			fmt.Fprintf(&b, "%s{service_id=\"%s\"} %s\n", name, labelValue(serviceID), formatValue(e.realtime[serviceID][m]))
+		}
+	}
+	e.mu.Unlock()
+
+	for _, m := range e.Metrics {
+		name := "fastly_historical_" + metricName(m)
+		fmt.Fprintf(&b, "# HELP %s The %s historical metric of the most recently recorded minute.\n", name, m)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		for _, serviceID := range e.sortedServiceIDs() {
+			data, ok := historical[serviceID]
+			if !ok {
+				continue
+			}
+			if v, ok := data[m].(float64); ok {
+				fmt.Fprintf(&b, "%s{service_id=\"%s\"} %s\n", name, labelValue(serviceID), formatValue(v))
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = io.WriteString(w, b.String())
+}
+
+// historicalStats returns the most recent minute of the historical stats of
+// a service, fetching them if they're older than the HistoricalInterval.
+func (e *Exporter) historicalStats(serviceID string) (statsResponseData, error) {
+	e.mu.Lock()
+	sample, ok := e.historical[serviceID]
+	e.mu.Unlock()
+	if ok && time.Since(sample.fetched) < e.HistoricalInterval {
+		return sample.data, nil
+	}
+
+	var envelope statsResponse
+	err := e.Client.GetStatsJSON(&fastly.GetStatsInput{
+		Service: serviceID,
+		By:      "minute",
+		From:    "10 minutes ago",
+	}, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Status != statusSuccess {
+		return nil, fmt.Errorf("non-success response: %s", envelope.Msg)
+	}
+
+	sample = historicalSample{fetched: time.Now()}
+	if n := len(envelope.Data); n > 0 {
+		sample.data = envelope.Data[n-1]
+	}
+	e.mu.Lock()
+	if e.historical == nil {
+		e.historical = make(map[string]historicalSample)
+	}
+	e.historical[serviceID] = sample
+	e.mu.Unlock()
+	return sample.data, nil
+}
+
+// sortedServiceIDs returns the service IDs in order, so the metrics are
+// written in a stable order.
+func (e *Exporter) sortedServiceIDs() []string {
+	ids := append([]string(nil), e.ServiceIDs...)
+	sort.Strings(ids)
+	return ids
+}
+
+// warn reports a warning, serialising the warnings of the pollers.
+func (e *Exporter) warn(format string, args ...any) {
+	if e.Out == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	text.Warning(e.Out, format, args...)
+}
+
+// metricName replaces the characters of a stats metric that aren't valid in
+// a Prometheus metric name.
+func metricName(m string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, m)
+}
+
+// labelValueReplacer escapes a Prometheus label value.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
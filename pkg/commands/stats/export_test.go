@@ -0,0 +1,122 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestExportFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	opts := testutil.NewRunOpts(testutil.Args("stats export --service-id 123 --historical-interval 30s"), &stdout)
+	err := app.Run(opts)
+	testutil.AssertErrorContains(t, err, "invalid --historical-interval: 30s")
+}
+
+// servicesClient returns the blocks of stats of each service in turn,
+// cancelling the context once they've all been returned.
+//
+// NOTE: It's safe for concurrent use, as a client is shared by the pollers of
+// every service.
+type servicesClient struct {
+	mu        sync.Mutex
+	blocks    map[string][]string
+	calls     map[string]int
+	remaining int
+	cancel    context.CancelFunc
+}
+
+func (c *servicesClient) GetRealtimeStatsJSON(i *fastly.GetRealtimeStatsInput, dst any) error {
+	// Simulate the API's one second blocks, scaled down.
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data := "[]"
+	if n := c.calls[i.ServiceID]; n < len(c.blocks[i.ServiceID]) {
+		data = "[" + c.blocks[i.ServiceID][n] + "]"
+		c.calls[i.ServiceID]++
+		c.remaining--
+		if c.remaining == 0 {
+			c.cancel()
+		}
+	}
+	return json.Unmarshal([]byte(fmt.Sprintf(`{"timestamp": 1, "data": %s}`, data)), dst)
+}
+
+func TestExporter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var historicalCalls int
+	e := &stats.Exporter{
+		Client: mock.API{
+			GetStatsJSONFn: func(i *fastly.GetStatsInput, dst any) error {
+				historicalCalls++
+				data := `{"status": "success", "data": [{"requests": 100}, {"requests": 120, "hits": 90}]}`
+				if i.Service == "def" {
+					data = `{"status": "error", "msg": "Service not found"}`
+				}
+				return json.Unmarshal([]byte(data), dst)
+			},
+		},
+		RTSClient: &servicesClient{
+			blocks: map[string][]string{
+				"abc": {`{"recorded": 1, "aggregated": {"requests": 10, "hits": 8}}`, `{"recorded": 2, "aggregated": {"requests": 5, "hits": 5}}`},
+				"def": {`{"recorded": 1, "aggregated": {"requests": 2, "miss": 2}}`},
+			},
+			calls:     make(map[string]int),
+			remaining: 3,
+			cancel:    cancel,
+		},
+		ServiceIDs:         []string{"def", "abc"},
+		Metrics:            []string{"requests", "hits", "status-5xx"},
+		HistoricalInterval: time.Hour,
+		Out:                &bytes.Buffer{},
+	}
+	err := e.Run(ctx)
+	testutil.AssertNoError(t, err)
+
+	want := `# HELP fastly_realtime_requests_total The requests realtime metric summed since the exporter started.
+# TYPE fastly_realtime_requests_total counter
+fastly_realtime_requests_total{service_id="abc"} 15
+fastly_realtime_requests_total{service_id="def"} 2
+# HELP fastly_realtime_hits_total The hits realtime metric summed since the exporter started.
+# TYPE fastly_realtime_hits_total counter
+fastly_realtime_hits_total{service_id="abc"} 13
+fastly_realtime_hits_total{service_id="def"} 0
+# HELP fastly_realtime_status_5xx_total The status-5xx realtime metric summed since the exporter started.
+# TYPE fastly_realtime_status_5xx_total counter
+fastly_realtime_status_5xx_total{service_id="abc"} 0
+fastly_realtime_status_5xx_total{service_id="def"} 0
+# HELP fastly_historical_requests The requests historical metric of the most recently recorded minute.
+# TYPE fastly_historical_requests gauge
+fastly_historical_requests{service_id="abc"} 120
+# HELP fastly_historical_hits The hits historical metric of the most recently recorded minute.
+# TYPE fastly_historical_hits gauge
+fastly_historical_hits{service_id="abc"} 90
+# HELP fastly_historical_status_5xx The status-5xx historical metric of the most recently recorded minute.
+# TYPE fastly_historical_status_5xx gauge
+`
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		testutil.AssertString(t, want, rec.Body.String())
+	}
+	testutil.AssertStringContains(t, e.Out.(*bytes.Buffer).String(), "Failed to fetch the historical stats of service def: non-success response: Service not found")
+
+	// The historical stats of abc are cached, while those of def are fetched
+	// again as they failed.
+	testutil.AssertEqual(t, 3, historicalCalls)
+}
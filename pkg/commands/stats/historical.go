@@ -1,24 +1,35 @@
 package stats
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/go-fastly/v7/fastly"
+	"github.com/fastly/kingpin"
 )
 
 const statusSuccess = "success"
 
+// DefaultCSVFields are the columns of --format csv when --fields isn't set.
+var DefaultCSVFields = append([]string{"start_time"}, DefaultPushMetrics...)
+
 // HistoricalCommand exposes the Historical Stats API.
 type HistoricalCommand struct {
 	cmd.Base
 	manifest manifest.Data
 
 	Input       fastly.GetStatsInput
+	fields      []string
 	formatFlag  string
 	serviceName cmd.OptionalServiceNameID
 }
@@ -45,16 +56,24 @@ func NewHistoricalCommand(parent cmd.Registerer, g *global.Data, m manifest.Data
 
 	c.CmdClause.Flag("from", "From time, accepted formats at https://fastly.dev/reference/api/metrics-stats/historical-stats").StringVar(&c.Input.From)
 	c.CmdClause.Flag("to", "To time").StringVar(&c.Input.To)
-	c.CmdClause.Flag("by", "Aggregation period (minute/hour/day)").EnumVar(&c.Input.By, "minute", "hour", "day")
+	c.CmdClause.Flag("by", "Aggregation period, i.e. the time bucket of each row (minute/hour/day)").EnumVar(&c.Input.By, "minute", "hour", "day")
 	c.CmdClause.Flag("region", "Filter by region ('stats regions' to list)").StringVar(&c.Input.Region)
 
-	c.CmdClause.Flag("format", "Output format (json)").EnumVar(&c.formatFlag, "json")
+	c.CmdClause.Flag("fields", fmt.Sprintf("A comma-separated list of the stats fields to output with --format csv or json (csv default: %s)", strings.Join(DefaultCSVFields, ","))).StringsVar(&c.fields, kingpin.Separator(","))
+	c.CmdClause.Flag("format", "Output format (csv, json)").EnumVar(&c.formatFlag, "csv", "json")
 
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
+	if len(c.fields) > 0 && c.formatFlag == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--fields requires --format"),
+			Remediation: "Use --format csv or --format json to output the selected fields.",
+		}
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -78,9 +97,25 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fmt.Errorf("non-success response: %s", envelope.Msg)
 	}
 
+	if err := checkFields(c.fields, envelope.Data); err != nil {
+		return err
+	}
+
 	switch c.formatFlag {
+	case "csv":
+		fields := c.fields
+		if len(fields) == 0 {
+			fields = DefaultCSVFields
+		}
+		if err := writeBlocksCSV(out, fields, envelope.Data); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return err
+		}
+
 	case "json":
-		err := writeBlocksJSON(out, serviceID, envelope.Data)
+		err := writeBlocksJSON(out, c.fields, envelope.Data)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
@@ -118,8 +153,15 @@ func writeBlocks(out io.Writer, service string, blocks []statsResponseData) erro
 	return nil
 }
 
-func writeBlocksJSON(out io.Writer, _ string, blocks []statsResponseData) error {
+func writeBlocksJSON(out io.Writer, fields []string, blocks []statsResponseData) error {
 	for _, block := range blocks {
+		if len(fields) > 0 {
+			selected := make(statsResponseData, len(fields))
+			for _, f := range fields {
+				selected[f] = block[f]
+			}
+			block = selected
+		}
 		if err := json.NewEncoder(out).Encode(block); err != nil {
 			return err
 		}
@@ -127,3 +169,81 @@ func writeBlocksJSON(out io.Writer, _ string, blocks []statsResponseData) error
 
 	return nil
 }
+
+// writeBlocksCSV writes a header row of the fields followed by a row per
+// block, with the start_time of the block formatted as an RFC 3339 time.
+func writeBlocksCSV(out io.Writer, fields []string, blocks []statsResponseData) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(fields); err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			v, err := csvValue(f, block[f])
+			if err != nil {
+				return err
+			}
+			record[i] = v
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvValue formats a field of a block as a CSV cell, encoding values other
+// than numbers and strings, such as histograms, as JSON.
+func csvValue(field string, v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case float64:
+		if field == "start_time" {
+			return time.Unix(int64(v), 0).UTC().Format(time.RFC3339), nil
+		}
+		return formatValue(v), nil
+	case string:
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// checkFields returns an error if a field isn't in any of the blocks, which is
+// most likely a typo.
+func checkFields(fields []string, blocks []statsResponseData) error {
+	if len(fields) == 0 || len(blocks) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, block := range blocks {
+		for k := range block {
+			known[k] = true
+		}
+	}
+	var unknown []string
+	for _, f := range fields {
+		if !known[f] {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	available := make([]string, 0, len(known))
+	for k := range known {
+		available = append(available, k)
+	}
+	sort.Strings(available)
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("unknown --fields: %s", strings.Join(unknown, ", ")),
+		Remediation: fmt.Sprintf("The available fields are: %s", strings.Join(available, ", ")),
+	}
+}
@@ -35,6 +35,30 @@ func TestHistorical(t *testing.T) {
 			api:        mock.API{GetStatsJSONFn: getStatsJSONOK},
 			wantOutput: historicalJSONOK,
 		},
+		{
+			args:       args("stats historical --service-id=123 --by=hour --format=csv"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONHourly},
+			wantOutput: historicalCSVOK,
+		},
+		{
+			args:       args("stats historical --service-id=123 --format=csv --fields=start_time,hits,miss_histogram"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONHourly},
+			wantOutput: "start_time,hits,miss_histogram\n2023-01-01T00:00:00Z,8,\"{\"\"10\"\":2}\"\n2023-01-01T01:00:00Z,5,\n",
+		},
+		{
+			args:       args("stats historical --service-id=123 --format=json --fields=requests"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONHourly},
+			wantOutput: "{\"requests\":10}\n{\"requests\":5}\n",
+		},
+		{
+			args:      args("stats historical --service-id=123 --format=csv --fields=requests,hitz"),
+			api:       mock.API{GetStatsJSONFn: getStatsJSONHourly},
+			wantError: "unknown --fields: hitz",
+		},
+		{
+			args:      args("stats historical --service-id=123 --fields=requests"),
+			wantError: "--fields requires --format",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -98,6 +122,25 @@ func getStatsJSONOK(i *fastly.GetStatsInput, o any) error {
 	return json.Unmarshal(msg, o)
 }
 
+var historicalCSVOK = `start_time,requests,hits,miss,pass,errors,bandwidth,status_2xx,status_3xx,status_4xx,status_5xx
+2023-01-01T00:00:00Z,10,8,2,0,1,1536,9,0,0,1
+2023-01-01T01:00:00Z,5,5,,,,,,,,
+`
+
+func getStatsJSONHourly(i *fastly.GetStatsInput, o any) error {
+	msg := []byte(`
+{
+  "status": "success",
+  "meta": {"by": "hour", "region": "all"},
+  "data": [
+    {"start_time": 1672531200, "requests": 10, "hits": 8, "miss": 2, "pass": 0, "errors": 1, "bandwidth": 1536, "status_2xx": 9, "status_3xx": 0, "status_4xx": 0, "status_5xx": 1, "miss_histogram": {"10": 2}},
+    {"start_time": 1672534800, "requests": 5, "hits": 5}
+  ]
+}`)
+
+	return json.Unmarshal(msg, o)
+}
+
 func getStatsJSONError(i *fastly.GetStatsInput, o any) error {
 	return errTest
 }
@@ -0,0 +1,248 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// DefaultPushMetrics are the realtime metrics pushed when --metric isn't set.
+var DefaultPushMetrics = []string{
+	"requests", "hits", "miss", "pass", "errors", "bandwidth",
+	"status_2xx", "status_3xx", "status_4xx", "status_5xx",
+}
+
+// PushCommand forwards realtime stats to a StatsD or Graphite agent.
+type PushCommand struct {
+	cmd.Base
+	manifest manifest.Data
+
+	graphite    string
+	interval    time.Duration
+	metrics     []string
+	prefix      string
+	serviceName cmd.OptionalServiceNameID
+	statsd      string
+}
+
+// NewPushCommand is the "stats push" subcommand.
+func NewPushCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *PushCommand {
+	var c PushCommand
+	c.Globals = g
+	c.manifest = m
+
+	c.CmdClause = parent.Command("push", "Continuously forward realtime stats for a Fastly service to a StatsD or Graphite agent")
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Name:        cmd.FlagServiceIDName,
+		Description: cmd.FlagServiceIDDesc,
+		Dst:         &c.manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(cmd.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        cmd.FlagServiceName,
+		Description: cmd.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	c.CmdClause.Flag("graphite", "Address (host:port) of a Graphite agent accepting the plaintext protocol over TCP").PlaceHolder("ADDR").StringVar(&c.graphite)
+	c.CmdClause.Flag("interval", "How often the metrics, summed since the last push, are pushed").Default("30s").DurationVar(&c.interval)
+	c.CmdClause.Flag("metric", fmt.Sprintf("A realtime metric to push, set once per metric (default: %s)", strings.Join(DefaultPushMetrics, ", "))).StringsVar(&c.metrics)
+	c.CmdClause.Flag("prefix", "Prefix of the metric names, which are <prefix>.<service ID>.<metric>").Default("fastly").StringVar(&c.prefix)
+	c.CmdClause.Flag("statsd", "Address (host:port) of a StatsD agent accepting metrics over UDP").PlaceHolder("ADDR").StringVar(&c.statsd)
+
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *PushCommand) Exec(_ io.Reader, out io.Writer) error {
+	if (c.statsd == "") == (c.graphite == "") {
+		return fsterr.RemediationError{
+			Inner:       errors.New("exactly one of --statsd or --graphite is required"),
+			Remediation: "Provide the address of the StatsD or Graphite agent to push the metrics to, e.g. --statsd localhost:8125",
+		}
+	}
+	if c.interval < time.Second {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --interval: %s", c.interval),
+			Remediation: "Provide an interval of at least 1s, as realtime stats are recorded every second.",
+		}
+	}
+
+	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		cmd.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	metrics := c.metrics
+	if len(metrics) == 0 {
+		metrics = DefaultPushMetrics
+	}
+
+	network, addr, format := "udp", c.statsd, StatsDFormat
+	if c.graphite != "" {
+		network, addr, format = "tcp", c.graphite, GraphiteFormat
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("error connecting to %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	text.Info(out, "Pushing %d metrics for service %s to %s every %s (press ^C to stop)", len(metrics), serviceID, addr, c.interval)
+	text.Break(out)
+
+	p := Pusher{
+		Client:    c.Globals.RTSClient,
+		ServiceID: serviceID,
+		Metrics:   metrics,
+		Prefix:    c.prefix,
+		Format:    format,
+		Interval:  c.interval,
+		Out:       out,
+		Verbose:   c.Globals.Verbose(),
+	}
+	if err := p.Run(ctx, conn); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+			"Address":    addr,
+		})
+		return err
+	}
+	return nil
+}
+
+// Format formats a metric as a line of an agent's protocol.
+type Format func(name string, value float64, at time.Time) string
+
+// StatsDFormat formats the metric as a StatsD counter.
+func StatsDFormat(name string, value float64, _ time.Time) string {
+	return fmt.Sprintf("%s:%s|c", name, formatValue(value))
+}
+
+// GraphiteFormat formats the metric using the Graphite plaintext protocol.
+func GraphiteFormat(name string, value float64, at time.Time) string {
+	return fmt.Sprintf("%s %s %d", name, formatValue(value), at.Unix())
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Pusher sums the realtime stats of a service and pushes them to an agent
+// every interval.
+type Pusher struct {
+	Client    api.RealtimeStatsInterface
+	ServiceID string
+	Metrics   []string
+	Prefix    string
+	Format    Format
+	Interval  time.Duration
+	// Out receives the warnings, and each push when Verbose is set.
+	Out     io.Writer
+	Verbose bool
+}
+
+// Run polls the realtime stats until ctx is done, writing the metrics to w
+// after each interval and, for the partial interval, before returning.
+//
+// NOTE: A failure to fetch the stats is reported but doesn't stop the
+// pusher, as the realtime stats API can be briefly unavailable.
+func (p Pusher) Run(ctx context.Context, w io.Writer) error {
+	var (
+		timestamp uint64
+		sums      = make(map[string]float64, len(p.Metrics))
+		last      = time.Now()
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return p.push(w, sums, time.Now())
+		default:
+		}
+
+		var envelope realtimeResponse
+		err := p.Client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: p.ServiceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		if err != nil {
+			text.Warning(p.Out, "Failed to fetch the realtime stats: %s", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		timestamp = envelope.Timestamp
+
+		for _, block := range envelope.Data {
+			for _, m := range p.Metrics {
+				if v, ok := block.Aggregated[m].(float64); ok {
+					sums[m] += v
+				}
+			}
+		}
+
+		if now := time.Now(); now.Sub(last) >= p.Interval {
+			if err := p.push(w, sums, now); err != nil {
+				return err
+			}
+			last = now
+		}
+	}
+}
+
+// push writes the sums to w, resetting them.
+//
+// Every metric is written, including those that were zero (or absent from the
+// stats), so the agent has a value for each interval.
+//
+// NOTE: Each line is written separately, so that each is a UDP datagram of
+// its own for a StatsD agent.
+func (p Pusher) push(w io.Writer, sums map[string]float64, at time.Time) error {
+	for _, m := range p.Metrics {
+		line := p.Format(fmt.Sprintf("%s.%s.%s", p.Prefix, p.ServiceID, m), sums[m], at)
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return fmt.Errorf("error pushing metrics: %w", err)
+		}
+		delete(sums, m)
+	}
+	if p.Verbose {
+		text.Output(p.Out, "%s Pushed %d metrics", at.UTC().Format(time.RFC3339), len(p.Metrics))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+func TestPushFlags(t *testing.T) {
+	for _, testcase := range []struct {
+		args      string
+		wantError string
+	}{
+		{
+			args:      "stats push --service-id 123",
+			wantError: "exactly one of --statsd or --graphite is required",
+		},
+		{
+			args:      "stats push --service-id 123 --statsd localhost:8125 --graphite localhost:2003",
+			wantError: "exactly one of --statsd or --graphite is required",
+		},
+		{
+			args:      "stats push --service-id 123 --statsd localhost:8125 --interval 500ms",
+			wantError: "invalid --interval: 500ms",
+		},
+	} {
+		t.Run(testcase.args, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+// realtimeClient returns a block of stats per call, cancelling the context
+// once they've all been returned.
+type realtimeClient struct {
+	blocks []string
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (c *realtimeClient) GetRealtimeStatsJSON(i *fastly.GetRealtimeStatsInput, dst any) error {
+	c.calls++
+	if c.calls == len(c.blocks) {
+		c.cancel()
+	}
+	// Simulate the API's one second blocks, scaled down.
+	time.Sleep(10 * time.Millisecond)
+	data := fmt.Sprintf(`{"timestamp": %d, "data": [{"recorded": 1, "aggregated": %s}]}`, c.calls, c.blocks[c.calls-1])
+	return json.Unmarshal([]byte(data), dst)
+}
+
+func TestPusher(t *testing.T) {
+	blocks := []string{
+		`{"requests": 10, "hits": 8, "status_5xx": 1}`,
+		`{"requests": 5, "hits": 5}`,
+	}
+	at := time.Unix(1672531200, 0)
+
+	for _, testcase := range []struct {
+		name       string
+		format     stats.Format
+		interval   time.Duration
+		wantOutput string
+	}{
+		{
+			// The interval isn't reached, so the stats are pushed once when the
+			// pusher stops.
+			name:       "statsd",
+			format:     stats.StatsDFormat,
+			interval:   time.Hour,
+			wantOutput: "fastly.123.requests:15|c\nfastly.123.status_5xx:1|c\nfastly.123.miss:0|c\n",
+		},
+		{
+			// The stats are pushed after each block, as the interval is reached.
+			name: "graphite",
+			format: func(name string, value float64, _ time.Time) string {
+				return stats.GraphiteFormat(name, value, at)
+			},
+			interval: time.Millisecond,
+			wantOutput: "fastly.123.requests 10 1672531200\nfastly.123.status_5xx 1 1672531200\nfastly.123.miss 0 1672531200\n" +
+				"fastly.123.requests 5 1672531200\nfastly.123.status_5xx 0 1672531200\nfastly.123.miss 0 1672531200\n" +
+				"fastly.123.requests 0 1672531200\nfastly.123.status_5xx 0 1672531200\nfastly.123.miss 0 1672531200\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var pushed, out bytes.Buffer
+			p := stats.Pusher{
+				Client:    &realtimeClient{blocks: blocks, cancel: cancel},
+				ServiceID: "123",
+				Metrics:   []string{"requests", "status_5xx", "miss"},
+				Prefix:    "fastly",
+				Format:    testcase.format,
+				Interval:  testcase.interval,
+				Out:       &out,
+			}
+			err := p.Run(ctx, &pushed)
+			testutil.AssertNoError(t, err)
+			testutil.AssertString(t, testcase.wantOutput, pushed.String())
+		})
+	}
+}
@@ -1,16 +1,24 @@
 package stats
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/go-fastly/v7/fastly"
+	"github.com/fastly/kingpin"
+	"golang.org/x/term"
 )
 
 // RealtimeCommand exposes the Realtime Metrics API.
@@ -18,8 +26,11 @@ type RealtimeCommand struct {
 	cmd.Base
 	manifest manifest.Data
 
+	allServices bool
+	dashboard   bool
 	formatFlag  string
 	serviceName cmd.OptionalServiceNameID
+	services    []string
 }
 
 // NewRealtimeCommand is the "stats realtime" subcommand.
@@ -42,13 +53,48 @@ func NewRealtimeCommand(parent cmd.Registerer, g *global.Data, data manifest.Dat
 		Dst:         &c.serviceName.Value,
 	})
 
+	c.CmdClause.Flag("all-services", "Merge the realtime stats of every service of the account, with a breakdown per service").BoolVar(&c.allServices)
+	c.CmdClause.Flag("dashboard", "Display a live-updating dashboard of the requests, hit ratio, errors and bandwidth of each datacenter").BoolVar(&c.dashboard)
 	c.CmdClause.Flag("format", "Output format (json)").EnumVar(&c.formatFlag, "json")
+	c.CmdClause.Flag("services", "A comma-separated list of alphanumeric strings identifying services whose realtime stats are merged, with a breakdown per service").StringsVar(&c.services, kingpin.Separator(","))
 
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.allServices && len(c.services) > 0 {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--all-services can't be used with --services"),
+			Remediation: "Use --services to merge the stats of some services, or --all-services to merge the stats of every service.",
+		}
+	}
+	multi := c.allServices || len(c.services) > 0
+	if c.dashboard && multi {
+		return fsterr.RemediationError{
+			Inner:       errors.New("--dashboard can't be used with --services or --all-services"),
+			Remediation: "Use --dashboard with a single service, e.g. --service-id, to view its datacenters.",
+		}
+	}
+	if c.dashboard {
+		if c.formatFlag != "" {
+			return fsterr.RemediationError{
+				Inner:       errors.New("--dashboard can't be used with --format"),
+				Remediation: "Use --format json to process the stats from a script, or --dashboard to view them.",
+			}
+		}
+		if !text.IsTTY(out) {
+			return fsterr.RemediationError{
+				Inner:       errors.New("--dashboard requires a terminal"),
+				Remediation: "Use --format json to process the stats from a script.",
+			}
+		}
+	}
+
+	if multi {
+		return c.runAggregator(out)
+	}
+
 	serviceID, source, flag, err := cmd.ServiceID(c.serviceName, c.manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -57,8 +103,16 @@ func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
 		cmd.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	switch c.formatFlag {
-	case "json":
+	switch {
+	case c.dashboard:
+		if err := c.runDashboard(serviceID, out); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return err
+		}
+
+	case c.formatFlag == "json":
 		if err := loopJSON(c.Globals.RTSClient, serviceID, out); err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
@@ -78,6 +132,87 @@ func (c *RealtimeCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// dashboardChrome is the number of lines of the dashboard other than the
+// datacenter rows.
+const dashboardChrome = 9
+
+// runDashboard displays the dashboard until interrupted, with as many
+// datacenters as fit in the terminal.
+func (c *RealtimeCommand) runDashboard(serviceID string, out io.Writer) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	d := Dashboard{
+		Client:    c.Globals.RTSClient,
+		ServiceID: serviceID,
+	}
+	if _, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && height > dashboardChrome {
+		d.Rows = height - dashboardChrome
+	}
+	return d.Run(ctx, out)
+}
+
+// runAggregator merges the stats of the --services, or of every service with
+// --all-services, until interrupted.
+func (c *RealtimeCommand) runAggregator(out io.Writer) error {
+	services := c.services
+	if c.allServices {
+		paginator := c.Globals.APIClient.NewListServicesPaginator(&fastly.ListServicesInput{})
+		for paginator.HasNext() {
+			data, err := paginator.GetNext()
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Remaining Pages": paginator.Remaining(),
+				})
+				return err
+			}
+			for _, s := range data {
+				services = append(services, s.ID)
+			}
+		}
+		if len(services) == 0 {
+			return errors.New("no services found")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	a := Aggregator{
+		Client:     c.Globals.RTSClient,
+		ServiceIDs: services,
+		JSON:       c.formatFlag == "json",
+		Out:        out,
+	}
+	if err := a.Run(ctx, out); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Services": services,
+		})
+		return err
+	}
+	return nil
+}
+
 func loopJSON(client api.RealtimeStatsInterface, service string, out io.Writer) error {
 	var timestamp uint64
 	for {
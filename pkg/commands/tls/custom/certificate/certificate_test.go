@@ -2,8 +2,18 @@ package certificate_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/mock"
@@ -25,7 +35,7 @@ func TestCreate(t *testing.T) {
 		{
 			Name:      "validate missing --cert-blob flag",
 			Args:      args("tls-custom certificate create"),
-			WantError: "required flag --cert-blob not provided",
+			WantError: "provide exactly one of --cert-blob or --cert-path",
 		},
 		{
 			Name: validateAPIError,
@@ -64,6 +74,180 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	rootKey, root := newCert(t, "Test Root", true, nil, nil)
+	intermediateKey, intermediate := newCert(t, "Test Intermediate", true, root, rootKey)
+	leafKey, leaf := newCert(t, "www.example.com", false, intermediate, intermediateKey)
+	_, other := newCert(t, "Other Root", true, nil, nil)
+
+	fullchain := filepath.Join(dir, "fullchain.pem")
+	writeFile(t, fullchain, pemCerts(intermediate, root, leaf))
+	unrelated := filepath.Join(dir, "unrelated.pem")
+	writeFile(t, unrelated, pemCerts(leaf, intermediate, other))
+	key := filepath.Join(dir, "key.pem")
+	der, err := x509.MarshalPKCS8PrivateKey(leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	otherKey := filepath.Join(dir, "other-key.pem")
+	der, err = x509.MarshalPKCS8PrivateKey(rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, otherKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	var calls []string
+	api := mock.API{
+		CreatePrivateKeyFn: func(i *fastly.CreatePrivateKeyInput) (*fastly.PrivateKey, error) {
+			calls = append(calls, "key:"+i.Name)
+			return &fastly.PrivateKey{Name: i.Name}, nil
+		},
+		CreateCustomTLSCertificateFn: func(i *fastly.CreateCustomTLSCertificateInput) (*fastly.CustomTLSCertificate, error) {
+			calls = append(calls, "cert")
+			if i.CertBlob != string(pemCerts(leaf, intermediate)) {
+				return nil, fmt.Errorf("unexpected certificate blob:\n%s", i.CertBlob)
+			}
+			return &fastly.CustomTLSCertificate{ID: mockResponseID}, nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		args       string
+		api        mock.API
+		wantError  string
+		wantOutput []string
+		wantCalls  []string
+	}{
+		{
+			name: "certificate and key",
+			args: "tls-custom certificate create --cert-path " + fullchain + " --key-path " + key,
+			api:  api,
+			wantOutput: []string{
+				"Uploading the certificate for 'www.example.com' with 1 intermediate certificates",
+				"Skipping the root certificate 'Test Root'",
+				"Created TLS Private Key 'www.example.com'",
+				"Created TLS Certificate '123'",
+			},
+			wantCalls: []string{"key:www.example.com", "cert"},
+		},
+		{
+			name:      "key name",
+			args:      "tls-custom certificate create --cert-path " + fullchain + " --key-path " + key + " --name example --key-name example-key",
+			api:       api,
+			wantCalls: []string{"key:example-key", "cert"},
+		},
+		{
+			name:       "certificate only",
+			args:       "tls-custom certificate create --cert-path " + fullchain,
+			api:        api,
+			wantOutput: []string{"Created TLS Certificate '123'"},
+			wantCalls:  []string{"cert"},
+		},
+		{
+			name:      "key doesn't match",
+			args:      "tls-custom certificate create --cert-path " + unrelated + " --key-path " + otherKey,
+			api:       api,
+			wantError: "none of the certificates in '" + unrelated + "' is for the private key",
+		},
+		{
+			name:      "certificate outside the chain",
+			args:      "tls-custom certificate create --cert-path " + unrelated + " --key-path " + key,
+			api:       api,
+			wantError: "the certificate for 'CN=Other Root' isn't in the chain of the certificate for 'CN=www.example.com'",
+		},
+		{
+			name:      "--cert-blob and --cert-path",
+			args:      "tls-custom certificate create --cert-blob example --cert-path " + fullchain,
+			wantError: "provide exactly one of --cert-blob or --cert-path",
+		},
+		{
+			name:      "--key-path without --cert-path",
+			args:      "tls-custom certificate create --cert-blob example --key-path " + key,
+			wantError: "--key-path requires --cert-path",
+		},
+		{
+			name: "key upload fails",
+			args: "tls-custom certificate create --cert-path " + fullchain + " --key-path " + key,
+			api: mock.API{
+				CreatePrivateKeyFn: func(_ *fastly.CreatePrivateKeyInput) (*fastly.PrivateKey, error) {
+					calls = append(calls, "key")
+					return nil, testutil.Err
+				},
+			},
+			wantError: "error creating private key: " + testutil.Err.Error(),
+			wantCalls: []string{"key"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			calls = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(testcase.api)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantCalls, calls)
+		})
+	}
+}
+
+// newCert creates a certificate for a new key, signed by the parent (or
+// self-signed when parent is nil).
+func newCert(t *testing.T, cn string, ca bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  ca,
+	}
+	if ca {
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		tmpl.DNSNames = []string{cn}
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+func pemCerts(certs ...*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, c := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -0,0 +1,162 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// chain is a leaf certificate followed by the intermediates that issued it.
+type chain struct {
+	Leaf          *x509.Certificate
+	Intermediates []*x509.Certificate
+	// Roots is the self-signed certificates of the file, which aren't uploaded
+	// as clients already trust them.
+	Roots []*x509.Certificate
+}
+
+// PEM encodes the leaf and intermediates, in that order.
+func (c chain) PEM() string {
+	var buf bytes.Buffer
+	for _, cert := range append([]*x509.Certificate{c.Leaf}, c.Intermediates...) {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.String()
+}
+
+// readChain reads the certificates of the PEM file at path (e.g. a
+// fullchain.pem), in any order, and splits the leaf from its intermediates.
+//
+// The leaf is the certificate for the public key pub when it's set, and
+// otherwise the certificate that didn't issue any other of the file.
+func readChain(path string, pub crypto.PublicKey) (chain, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we require a user to provide the path of the file.
+	// #nosec
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return chain{}, fmt.Errorf("error reading certificate: %w", err)
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return chain{}, certError(path, fmt.Errorf("unexpected PEM block type %s", block.Type))
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return chain{}, certError(path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return chain{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid certificate '%s': no PEM data found", path),
+			Remediation: "Provide a PEM-formatted file, which begins with a line such as '-----BEGIN CERTIFICATE-----'.",
+		}
+	}
+
+	leaf, err := findLeaf(path, certs, pub)
+	if err != nil {
+		return chain{}, err
+	}
+
+	c := chain{Leaf: leaf}
+	used := map[*x509.Certificate]bool{leaf: true}
+	for issued := leaf; ; {
+		issuer := findIssuer(issued, certs, used)
+		if issuer == nil {
+			break
+		}
+		used[issuer] = true
+		if isSelfSigned(issuer) {
+			c.Roots = append(c.Roots, issuer)
+			break
+		}
+		c.Intermediates = append(c.Intermediates, issuer)
+		issued = issuer
+	}
+	for _, cert := range certs {
+		if !used[cert] {
+			return chain{}, certError(path, fmt.Errorf("the certificate for '%s' isn't in the chain of the certificate for '%s'", cert.Subject, leaf.Subject))
+		}
+	}
+	return c, nil
+}
+
+// findLeaf returns the leaf certificate of certs.
+func findLeaf(path string, certs []*x509.Certificate, pub crypto.PublicKey) (*x509.Certificate, error) {
+	if pub != nil {
+		spki, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range certs {
+			if bytes.Equal(spki, cert.RawSubjectPublicKeyInfo) {
+				return cert, nil
+			}
+		}
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("none of the certificates in '%s' is for the private key", path),
+			Remediation: "Check the certificate was issued for a certificate signing request (CSR) made with this key.",
+		}
+	}
+
+	if len(certs) == 1 {
+		return certs[0], nil
+	}
+	var leaves []*x509.Certificate
+	for _, cert := range certs {
+		issuer := false
+		for _, other := range certs {
+			if other != cert && bytes.Equal(other.RawIssuer, cert.RawSubject) && other.CheckSignatureFrom(cert) == nil {
+				issuer = true
+				break
+			}
+		}
+		if !issuer && !isSelfSigned(cert) {
+			leaves = append(leaves, cert)
+		}
+	}
+	if len(leaves) != 1 {
+		return nil, certError(path, fmt.Errorf("expected a single leaf certificate, found %d", len(leaves)))
+	}
+	return leaves[0], nil
+}
+
+// findIssuer returns the certificate of certs (not already used) that issued
+// the certificate.
+func findIssuer(issued *x509.Certificate, certs []*x509.Certificate, used map[*x509.Certificate]bool) *x509.Certificate {
+	for _, cert := range certs {
+		if !used[cert] && bytes.Equal(issued.RawIssuer, cert.RawSubject) && issued.CheckSignatureFrom(cert) == nil {
+			return cert
+		}
+	}
+	return nil
+}
+
+// isSelfSigned reports whether the certificate is a self-signed (root)
+// certificate.
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// certError describes an invalid certificate.
+func certError(path string, err error) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid certificate '%s': %w", path, err),
+		Remediation: "Provide the X.509 certificate and its intermediates in PEM format (e.g. the fullchain.pem of your certificate authority).",
+	}
+}
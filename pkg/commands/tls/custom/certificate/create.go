@@ -1,9 +1,16 @@
 package certificate
 
 import (
+	"crypto"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
 	"github.com/fastly/cli/pkg/cmd"
+	"github.com/fastly/cli/pkg/commands/tls/custom/privatekey"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -17,11 +24,12 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 	c.Globals = g
 	c.manifest = m
 
-	// required
-	c.CmdClause.Flag("cert-blob", "The PEM-formatted certificate blob").Required().StringVar(&c.certBlob)
-
 	// optional
+	c.CmdClause.Flag("cert-blob", "The PEM-formatted certificate blob (alternative to --cert-path)").StringVar(&c.certBlob)
+	c.CmdClause.Flag("cert-path", "Path to a PEM file of the certificate and its intermediates in any order (e.g. fullchain.pem), which are uploaded leaf first").StringVar(&c.certPath)
 	c.CmdClause.Flag("id", "Alphanumeric string identifying a TLS certificate").StringVar(&c.id)
+	c.CmdClause.Flag("key-name", "A customizable name for the private key uploaded from --key-path. Defaults to the name of the certificate").StringVar(&c.keyName)
+	c.CmdClause.Flag("key-path", "Path to the PEM-formatted private key of the certificate, which is uploaded before the certificate (requires --cert-path)").StringVar(&c.keyPath)
 	c.CmdClause.Flag("name", "A customizable name for your certificate. Defaults to the certificate's Common Name or first Subject Alternative Names (SAN) entry").StringVar(&c.name)
 
 	return &c
@@ -32,13 +40,35 @@ type CreateCommand struct {
 	cmd.Base
 
 	certBlob string
+	certPath string
 	id       string
+	keyName  string
+	keyPath  string
 	manifest manifest.Data
 	name     string
 }
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if (c.certBlob == "") == (c.certPath == "") {
+		return fsterr.RemediationError{
+			Inner:       errors.New("error parsing arguments: provide exactly one of --cert-blob or --cert-path"),
+			Remediation: "Pass the path of the certificate file with --cert-path, or its contents with --cert-blob.",
+		}
+	}
+	if c.keyPath != "" && c.certPath == "" {
+		return fsterr.RemediationError{
+			Inner:       errors.New("error parsing arguments: --key-path requires --cert-path"),
+			Remediation: "Pass the path of the certificate file with --cert-path.",
+		}
+	}
+
+	if c.certPath != "" {
+		if err := c.readFiles(out); err != nil {
+			return err
+		}
+	}
+
 	input := c.constructInput()
 
 	r, err := c.Globals.APIClient.CreateCustomTLSCertificate(input)
@@ -54,6 +84,71 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// readFiles reads the certificate chain of --cert-path (and the private key of
+// --key-path), uploading the key so the certificate can be created for it.
+//
+// NOTE: A certificate can only be created once its private key is uploaded,
+// so the key is checked against the certificate before either is uploaded.
+func (c *CreateCommand) readFiles(out io.Writer) error {
+	var (
+		key []byte
+		pub crypto.PublicKey
+	)
+	if c.keyPath != "" {
+		signer, err := privatekey.ReadPrivateKey(c.keyPath)
+		if err != nil {
+			return err
+		}
+		pub = signer.Public()
+
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we require a user to provide the path of the file.
+		// #nosec
+		key, err = os.ReadFile(filepath.Clean(c.keyPath))
+		if err != nil {
+			return fmt.Errorf("error reading key: %w", err)
+		}
+	}
+
+	ch, err := readChain(c.certPath, pub)
+	if err != nil {
+		return err
+	}
+	c.certBlob = ch.PEM()
+
+	text.Info(out, "Uploading the certificate for '%s' with %d intermediate certificates", ch.Leaf.Subject.CommonName, len(ch.Intermediates))
+	if len(ch.Roots) > 0 {
+		text.Info(out, "Skipping the root certificate '%s', which clients already trust", ch.Roots[0].Subject.CommonName)
+	}
+
+	if key == nil {
+		return nil
+	}
+	name := c.keyName
+	if name == "" {
+		name = c.name
+	}
+	if name == "" {
+		name = ch.Leaf.Subject.CommonName
+	}
+	k, err := c.Globals.APIClient.CreatePrivateKey(&fastly.CreatePrivateKeyInput{
+		Key:  string(key),
+		Name: name,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Private Key Name": name,
+		})
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error creating private key: %w", err),
+			Remediation: "If the key has already been uploaded, omit --key-path to create only the certificate.",
+		}
+	}
+	text.Success(out, "Created TLS Private Key '%s'", k.Name)
+	return nil
+}
+
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *CreateCommand) constructInput() *fastly.CreateCustomTLSCertificateInput {
 	var input fastly.CreateCustomTLSCertificateInput
@@ -0,0 +1,298 @@
+package privatekey
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// expiryWarning is how soon before a certificate expires that check warns
+// about it.
+const expiryWarning = 30 * 24 * time.Hour
+
+// NewCheckCommand returns a usable command registered under the parent.
+func NewCheckCommand(parent cmd.Registerer, g *global.Data) *CheckCommand {
+	var c CheckCommand
+	c.CmdClause = parent.Command("check", "Check a private key file matches a certificate before uploading them")
+	c.Globals = g
+
+	// required
+	c.CmdClause.Flag("key", "Path to the PEM-formatted private key file").Required().StringVar(&c.key)
+
+	// optional
+	c.CmdClause.Flag("cert", "Path to the PEM-formatted certificate file, whose first certificate must be signed for the key").StringVar(&c.cert)
+	c.CmdClause.Flag("cert-id", "Alphanumeric string identifying an uploaded TLS certificate, to report its expiry and domains").StringVar(&c.certID)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+
+	return &c
+}
+
+// CheckCommand compares a local private key with a certificate.
+type CheckCommand struct {
+	cmd.Base
+	cmd.JSONOutput
+
+	cert   string
+	certID string
+	key    string
+}
+
+// checkResult is the outcome of a check.
+type checkResult struct {
+	CertificateID string    `json:"certificate_id,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	KeyType       string    `json:"key_type"`
+	Match         *bool     `json:"match"`
+	NotAfter      time.Time `json:"not_after"`
+	SANs          []string  `json:"sans"`
+	Subject       string    `json:"subject,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *CheckCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if (c.cert == "") == (c.certID == "") {
+		return fsterr.RemediationError{
+			Inner:       errors.New("error parsing arguments: provide exactly one of --cert or --cert-id"),
+			Remediation: "Pass the certificate file with --cert to verify it matches the key, or the ID of an uploaded certificate with --cert-id.",
+		}
+	}
+
+	key, err := ReadPrivateKey(c.key)
+	if err != nil {
+		return err
+	}
+	keyType, err := describeKey(key.Public())
+	if err != nil {
+		return keyError(c.key, err)
+	}
+
+	var r *checkResult
+	if c.cert != "" {
+		r, err = checkCertificateFile(c.cert, key.Public())
+	} else {
+		r, err = c.checkCertificateID()
+	}
+	if err != nil {
+		return err
+	}
+	r.KeyType = keyType
+
+	if ok, err := c.WriteJSON(out, r); ok {
+		if err == nil && r.Match != nil && !*r.Match {
+			return errMismatch
+		}
+		return err
+	}
+
+	c.print(out, r)
+
+	switch {
+	case r.Match == nil:
+		text.Warning(out, "Fastly doesn't return the contents of uploaded certificates, so the key can't be matched against certificate %s. Pass the certificate file with --cert to verify the pair.", r.CertificateID)
+	case !*r.Match:
+		return errMismatch
+	default:
+		text.Success(out, "The private key matches the certificate")
+	}
+	return nil
+}
+
+// errMismatch is returned when the key doesn't match the certificate.
+var errMismatch = fsterr.RemediationError{
+	Inner:       errors.New("the private key doesn't match the certificate"),
+	Remediation: "Check the certificate was issued for a certificate signing request (CSR) made with this key, and that the leaf certificate comes first in the certificate file.",
+}
+
+// checkCertificateID describes an uploaded certificate.
+func (c *CheckCommand) checkCertificateID() (*checkResult, error) {
+	cert, err := c.Globals.APIClient.GetCustomTLSCertificate(&fastly.GetCustomTLSCertificateInput{
+		ID: c.certID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"TLS Certificate ID": c.certID,
+		})
+		return nil, err
+	}
+
+	r := &checkResult{
+		CertificateID: cert.ID,
+		Issuer:        cert.Issuer,
+		SANs:          make([]string, 0, len(cert.Domains)),
+		Subject:       cert.IssuedTo,
+	}
+	if cert.NotAfter != nil {
+		r.NotAfter = *cert.NotAfter
+	}
+	for _, d := range cert.Domains {
+		r.SANs = append(r.SANs, d.ID)
+	}
+	return r, nil
+}
+
+// print displays the key and certificate, warning of an expired or expiring
+// certificate.
+func (c *CheckCommand) print(out io.Writer, r *checkResult) {
+	lines := text.Lines{
+		"Private key": r.KeyType,
+	}
+	if r.CertificateID != "" {
+		lines["Certificate ID"] = r.CertificateID
+	}
+	if r.Subject != "" {
+		lines["Subject"] = r.Subject
+	}
+	if r.Issuer != "" {
+		lines["Issuer"] = r.Issuer
+	}
+	if !r.NotAfter.IsZero() {
+		lines["Not after"] = r.NotAfter.UTC().Format(time.RFC3339)
+	}
+	lines["SANs"] = strings.Join(r.SANs, ", ")
+	text.PrintLines(out, lines)
+
+	if r.NotAfter.IsZero() {
+		return
+	}
+	switch remaining := time.Until(r.NotAfter); {
+	case remaining <= 0:
+		text.Warning(out, "The certificate expired %s ago.", days(-remaining))
+	case remaining < expiryWarning:
+		text.Warning(out, "The certificate expires in %s.", days(remaining))
+	}
+}
+
+// days formats a duration in whole days.
+func days(d time.Duration) string {
+	n := int(d.Hours() / 24)
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// checkCertificateFile compares the public key of the first certificate in
+// the file at path with pub.
+func checkCertificateFile(path string, pub crypto.PublicKey) (*checkResult, error) {
+	block, err := readPEM(path, "certificate")
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, certError(path, fmt.Errorf("unexpected PEM block type %s", block.Type))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, certError(path, err)
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	match := bytes.Equal(spki, cert.RawSubjectPublicKeyInfo)
+
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return &checkResult{
+		Issuer:   cert.Issuer.String(),
+		Match:    &match,
+		NotAfter: cert.NotAfter,
+		SANs:     sans,
+		Subject:  cert.Subject.String(),
+	}, nil
+}
+
+// ReadPrivateKey reads a PKCS #1, PKCS #8 or SEC 1 private key from the file
+// at path.
+func ReadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path, "key")
+	if err != nil {
+		return nil, err
+	}
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if s, ok := k.(crypto.Signer); ok {
+			return s, nil
+		}
+		return nil, keyError(path, fmt.Errorf("unsupported key type %T", k))
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	return nil, keyError(path, fmt.Errorf("unable to parse PEM block type %s as a private key", block.Type))
+}
+
+// describeKey returns the algorithm and size of a public key.
+func describeKey(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen()), nil
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", k.Curve.Params().Name), nil
+	case ed25519.PublicKey:
+		return "Ed25519", nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+// readPEM reads the first PEM block of the file at path.
+func readPEM(path, kind string) (*pem.Block, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as we require a user to provide the path of the file.
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", kind, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid %s '%s': no PEM data found", kind, path),
+			Remediation: "Provide a PEM-formatted file, which begins with a line such as '-----BEGIN CERTIFICATE-----'.",
+		}
+	}
+	return block, nil
+}
+
+// keyError describes an invalid private key.
+func keyError(path string, err error) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid key '%s': %w", path, err),
+		Remediation: "Provide an RSA, ECDSA or Ed25519 private key in PKCS #1, PKCS #8 or SEC 1 PEM format.",
+	}
+}
+
+// certError describes an invalid certificate.
+func certError(path string, err error) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid certificate '%s': %w", path, err),
+		Remediation: "Provide an X.509 certificate in PEM format, with the certificate for the key first.",
+	}
+}
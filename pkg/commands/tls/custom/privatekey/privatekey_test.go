@@ -2,7 +2,17 @@ package privatekey_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/mock"
@@ -19,6 +29,142 @@ const (
 	validateMissingIDFlag = "validate missing --id flag"
 )
 
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Date(2099, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := writeKey(t, filepath.Join(dir, "key.pem"))
+	writeKey(t, filepath.Join(dir, "other.pem"))
+	writeCert(t, filepath.Join(dir, "cert.pem"), key, notAfter)
+	writeCert(t, filepath.Join(dir, "expired.pem"), key, time.Now().Add(-73*time.Hour))
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name:      "validate missing --key flag",
+			Args:      args("tls-custom private-key check --cert " + filepath.Join(dir, "cert.pem")),
+			WantError: "required flag --key not provided",
+		},
+		{
+			Name:      "validate missing --cert and --cert-id flags",
+			Args:      args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem")),
+			WantError: "provide exactly one of --cert or --cert-id",
+		},
+		{
+			Name:      "validate invalid key",
+			Args:      args("tls-custom private-key check --key " + filepath.Join(dir, "missing.pem") + " --cert " + filepath.Join(dir, "cert.pem")),
+			WantError: "error reading key",
+		},
+		{
+			Name: "validate matching key and certificate",
+			Args: args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem") + " --cert " + filepath.Join(dir, "cert.pem")),
+			WantOutputs: []string{
+				"Private key: ECDSA P-256",
+				"Not after: 2099-01-02T03:04:05Z",
+				"SANs: example.com, www.example.com",
+				"The private key matches the certificate",
+			},
+		},
+		{
+			Name:      "validate mismatched key and certificate",
+			Args:      args("tls-custom private-key check --key " + filepath.Join(dir, "other.pem") + " --cert " + filepath.Join(dir, "cert.pem")),
+			WantError: "the private key doesn't match the certificate",
+		},
+		{
+			Name:       "validate expired certificate",
+			Args:       args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem") + " --cert " + filepath.Join(dir, "expired.pem")),
+			WantOutput: "The certificate expired 3 days ago.",
+		},
+		{
+			Name:       "validate --json",
+			Args:       args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem") + " --cert " + filepath.Join(dir, "cert.pem") + " --json"),
+			WantOutput: `"match": true`,
+		},
+		{
+			Name: "validate --cert-id",
+			API: mock.API{
+				GetCustomTLSCertificateFn: func(i *fastly.GetCustomTLSCertificateInput) (*fastly.CustomTLSCertificate, error) {
+					return &fastly.CustomTLSCertificate{
+						ID:       i.ID,
+						IssuedTo: "example.com",
+						NotAfter: &notAfter,
+						Domains:  []*fastly.TLSDomain{{ID: "example.com"}},
+					}, nil
+				},
+			},
+			Args: args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem") + " --cert-id " + mockResponseID),
+			WantOutputs: []string{
+				"Certificate ID: 123",
+				"SANs: example.com",
+				"Fastly doesn't return the contents of uploaded certificates",
+			},
+		},
+		{
+			Name: "validate --cert-id API error",
+			API: mock.API{
+				GetCustomTLSCertificateFn: func(i *fastly.GetCustomTLSCertificateInput) (*fastly.CustomTLSCertificate, error) {
+					return nil, testutil.Err
+				},
+			},
+			Args:      args("tls-custom private-key check --key " + filepath.Join(dir, "key.pem") + " --cert-id " + mockResponseID),
+			WantError: testutil.Err.Error(),
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.APIClient = mock.APIClient(testcase.API)
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// writeKey writes a new PKCS #8 ECDSA private key to path.
+func writeKey(t *testing.T, path string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, path, "PRIVATE KEY", der)
+	return key
+}
+
+// writeCert writes a self-signed certificate for key to path.
+func writeCert(t *testing.T, path string, key *ecdsa.PrivateKey, notAfter time.Time) {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, path, "CERTIFICATE", der)
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestCreate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -1,9 +1,16 @@
 package subscription
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fastly/cli/pkg/cmd"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
@@ -14,20 +21,36 @@ const emptyString = ""
 
 var certAuth = []string{"lets-encrypt", "globalsign"}
 
+// PollInterval is the time between checks of a subscription's state when
+// waiting for its certificate to be issued.
+var PollInterval = 15 * time.Second
+
+// The states of a subscription and its authorizations.
+const (
+	stateFailed = "failed"
+	stateIssued = "issued"
+)
+
 // NewCreateCommand returns a usable command registered under the parent.
 func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *CreateCommand {
 	var c CreateCommand
-	c.CmdClause = parent.Command("create", "Create a new TLS subscription").Alias("add")
+	c.CmdClause = parent.Command("create", "Create a new TLS subscription, showing the DNS records that validate its domains (prompts for the domains when --domain is omitted)").Alias("add")
 	c.Globals = g
 	c.manifest = m
 
-	// required
-	c.CmdClause.Flag("domain", "Domain(s) to add to the TLS certificates generated for the subscription (set flag once per domain)").Required().StringsVar(&c.domains)
-
 	// optional
 	c.CmdClause.Flag("cert-auth", "The entity that issues and certifies the TLS certificates for your subscription. Valid values are lets-encrypt or globalsign").HintOptions(certAuth...).EnumVar(&c.certAuth, certAuth...)
 	c.CmdClause.Flag("common-name", "The domain name associated with the subscription. Default to the first domain specified by --domain").StringVar(&c.commonName)
 	c.CmdClause.Flag("config", "Alphanumeric string identifying a TLS configuration").StringVar(&c.config)
+	c.CmdClause.Flag("domain", "Domain(s) to add to the TLS certificates generated for the subscription (set flag once per domain)").StringsVar(&c.domains)
+	c.RegisterFlagBool(cmd.BoolFlagOpts{
+		Name:        cmd.FlagJSONName,
+		Description: cmd.FlagJSONDesc,
+		Dst:         &c.json,
+		Short:       'j',
+	})
+	c.CmdClause.Flag("wait", "Wait until the domains are validated and the certificate is issued").BoolVar(&c.wait)
+	c.CmdClause.Flag("wait-timeout", "How long to wait for the certificate to be issued").Default("30m").DurationVar(&c.waitTimeout)
 
 	return &c
 }
@@ -36,15 +59,57 @@ func NewCreateCommand(parent cmd.Registerer, g *global.Data, m manifest.Data) *C
 type CreateCommand struct {
 	cmd.Base
 
-	certAuth   string
-	commonName string
-	config     string
-	domains    []string
-	manifest   manifest.Data
+	certAuth    string
+	commonName  string
+	config      string
+	domains     []string
+	json        bool
+	manifest    manifest.Data
+	wait        bool
+	waitTimeout time.Duration
+}
+
+// CreateResult is the subscription created, with the DNS challenges that
+// validate its domains.
+type CreateResult struct {
+	ID                   string      `json:"id"`
+	CertificateAuthority string      `json:"certificate_authority"`
+	CommonName           string      `json:"common_name"`
+	Domains              []string    `json:"domains"`
+	State                string      `json:"state"`
+	Challenges           []Challenge `json:"challenges"`
+	Warnings             []string    `json:"warnings,omitempty"`
+}
+
+// Challenge is a DNS record that validates the ownership of a domain.
+type Challenge struct {
+	AuthorizationID    string   `json:"authorization_id"`
+	AuthorizationState string   `json:"authorization_state"`
+	Type               string   `json:"type"`
+	RecordName         string   `json:"record_name"`
+	RecordType         string   `json:"record_type"`
+	Values             []string `json:"values"`
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.json {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	// NOTE: The wizard is only run when --domain is omitted and prompts can be
+	// answered, so scripts get the same error as when the flag was required.
+	var scanner *bufio.Scanner
+	if len(c.domains) == 0 {
+		if c.json || !c.canPrompt(in) {
+			return fmt.Errorf("error parsing arguments: required flag --domain not provided")
+		}
+		scanner = bufio.NewScanner(in)
+		if err := c.prompt(scanner, out); err != nil {
+			return err
+		}
+	}
+
 	input := c.constructInput()
 
 	r, err := c.Globals.APIClient.CreateTLSSubscription(input)
@@ -58,10 +123,241 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created TLS Subscription '%s' (Authority: %s, Common Name: %s)", r.ID, r.CertificateAuthority, r.CommonName.ID)
+	if !c.json {
+		text.Success(out, "Created TLS Subscription '%s' (Authority: %s, Common Name: %s)", r.ID, r.CertificateAuthority, r.CommonName.ID)
+	}
+
+	// The authorizations (and so the DNS challenges) are only returned when
+	// they're included, which creating a subscription doesn't support.
+	sub, err := c.get(r.ID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"TLS Subscription ID": r.ID,
+		})
+		if c.json {
+			return err
+		}
+		text.Warning(out, "Failed to fetch the DNS challenges of the subscription: %s", err)
+		text.Info(out, "To see the DNS records to create run:\n\n\t$ fastly tls-subscription describe --id %s --include tls_authorizations", r.ID)
+		return nil
+	}
+
+	if !c.json {
+		printChallenges(out, sub)
+		if !c.wait && scanner != nil {
+			text.Break(out)
+			answer, err := ask(scanner, out, "Wait for the domains to be validated? [y/N] ")
+			if err != nil {
+				return err
+			}
+			c.wait = answer == "y" || answer == "yes"
+		}
+	}
+
+	if c.wait {
+		sub, err = c.waitForIssued(out, sub)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.json {
+		data, err := json.Marshal(newCreateResult(sub))
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error: unable to write data to stdout: %w", err)
+		}
+	}
 	return nil
 }
 
+// canPrompt reports whether the user can be prompted for the subscription.
+func (c *CreateCommand) canPrompt(in io.Reader) bool {
+	if _, ok := in.(text.NoPromptReader); ok || in == nil {
+		return false
+	}
+	return !c.Globals.Flags.NonInteractive && !c.Globals.Flags.AutoYes && !c.Globals.Flags.AcceptDefaults
+}
+
+// prompt walks the user through the domains and certificate authority of the
+// subscription, asking only for those not set by flags.
+func (c *CreateCommand) prompt(scanner *bufio.Scanner, out io.Writer) error {
+	text.Break(out)
+	for len(c.domains) == 0 {
+		answer, err := ask(scanner, out, "Domains to secure (separated by commas or spaces): ")
+		if err != nil {
+			return err
+		}
+		c.domains = strings.FieldsFunc(answer, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+	}
+
+	if c.commonName == emptyString && len(c.domains) > 1 {
+		answer, err := ask(scanner, out, fmt.Sprintf("Common name [%s]: ", c.domains[0]))
+		if err != nil {
+			return err
+		}
+		c.commonName = answer
+	}
+
+	for c.certAuth == emptyString {
+		text.Break(out)
+		for i, ca := range certAuth {
+			fmt.Fprintf(out, "%d. %s\n", i+1, ca)
+		}
+		answer, err := ask(scanner, out, fmt.Sprintf("Certificate authority [%s]: ", certAuth[0]))
+		if err != nil {
+			return err
+		}
+		switch n, convErr := strconv.Atoi(answer); {
+		case answer == "":
+			c.certAuth = certAuth[0]
+		case convErr == nil && n >= 1 && n <= len(certAuth):
+			c.certAuth = certAuth[n-1]
+		default:
+			for _, ca := range certAuth {
+				if strings.EqualFold(answer, ca) {
+					c.certAuth = ca
+				}
+			}
+			if c.certAuth == emptyString {
+				text.Warning(out, "Select a number between 1 and %d", len(certAuth))
+			}
+		}
+	}
+	return nil
+}
+
+// ask prompts the user and returns the answer.
+//
+// NOTE: A single scanner reads every answer, as one scanner per prompt could
+// buffer (and so lose) the answers to later prompts.
+func ask(scanner *bufio.Scanner, out io.Writer, prompt string) (string, error) {
+	fmt.Fprint(out, text.Bold(prompt))
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("error reading input: %w", err)
+		}
+		return "", fmt.Errorf("error reading input: %w", io.EOF)
+	}
+	return strings.ToLower(strings.TrimSpace(scanner.Text())), nil
+}
+
+// get fetches the subscription with its authorizations.
+func (c *CreateCommand) get(id string) (*fastly.TLSSubscription, error) {
+	return c.Globals.APIClient.GetTLSSubscription(&fastly.GetTLSSubscriptionInput{
+		ID:      id,
+		Include: fastly.String("tls_authorizations"),
+	})
+}
+
+// waitForIssued polls the subscription until its certificate is issued, an
+// authorization fails, or --wait-timeout passes.
+func (c *CreateCommand) waitForIssued(out io.Writer, sub *fastly.TLSSubscription) (*fastly.TLSSubscription, error) {
+	deadline := time.Now().Add(c.waitTimeout)
+	state := ""
+	for {
+		if sub.State != state && sub.State != stateIssued {
+			state = sub.State
+			if !c.json {
+				text.Info(out, "Subscription '%s' is %s", sub.ID, state)
+			}
+		}
+		if sub.State == stateIssued {
+			if !c.json {
+				text.Success(out, "The certificate of TLS Subscription '%s' has been issued", sub.ID)
+			}
+			return sub, nil
+		}
+		for _, a := range sub.Authorizations {
+			if a.State == stateFailed {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("the validation of authorization '%s' failed", a.ID),
+					Remediation: fmt.Sprintf("Check the DNS records, and then run `fastly tls-subscription describe --id %s --include tls_authorizations` to see its state.", sub.ID),
+				}
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("timed out after %s waiting for the certificate of TLS Subscription '%s' to be issued (state: %s)", c.waitTimeout, sub.ID, sub.State),
+				Remediation: fmt.Sprintf("DNS changes can take a while to propagate. Run `fastly tls-subscription describe --id %s` later to check its state.", sub.ID),
+			}
+		}
+
+		time.Sleep(PollInterval)
+		next, err := c.get(sub.ID)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"TLS Subscription ID": sub.ID,
+			})
+			return nil, err
+		}
+		sub = next
+	}
+}
+
+// printChallenges displays the DNS records that validate the domains of the
+// subscription.
+func printChallenges(out io.Writer, sub *fastly.TLSSubscription) {
+	r := newCreateResult(sub)
+	if len(r.Challenges) == 0 {
+		text.Info(out, "There are no DNS challenges for TLS Subscription '%s' (state: %s)", r.ID, r.State)
+		return
+	}
+
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("AUTHORIZATION", "STATE", "CHALLENGE", "RECORD NAME", "RECORD TYPE", "VALUES")
+	for _, ch := range r.Challenges {
+		t.AddLine(ch.AuthorizationID, ch.AuthorizationState, ch.Type, ch.RecordName, ch.RecordType, strings.Join(ch.Values, ", "))
+	}
+	t.Print()
+	for _, w := range r.Warnings {
+		text.Warning(out, "%s", w)
+	}
+	text.Info(out, "Create one of the DNS records of each authorization to validate its domain. Run the command with --wait (or `fastly tls-subscription describe --id %s`) to follow the validation.", r.ID)
+}
+
+// newCreateResult flattens the subscription and its authorizations.
+func newCreateResult(sub *fastly.TLSSubscription) CreateResult {
+	r := CreateResult{
+		ID:                   sub.ID,
+		CertificateAuthority: sub.CertificateAuthority,
+		State:                sub.State,
+		Challenges:           []Challenge{},
+	}
+	if sub.CommonName != nil {
+		r.CommonName = sub.CommonName.ID
+	}
+	for _, d := range sub.Domains {
+		r.Domains = append(r.Domains, d.ID)
+	}
+	for _, a := range sub.Authorizations {
+		if a == nil {
+			continue
+		}
+		for _, ch := range a.Challenges {
+			r.Challenges = append(r.Challenges, Challenge{
+				AuthorizationID:    a.ID,
+				AuthorizationState: a.State,
+				Type:               ch.Type,
+				RecordName:         ch.RecordName,
+				RecordType:         ch.RecordType,
+				Values:             ch.Values,
+			})
+		}
+		for _, w := range a.Warnings {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: %s", w.Type, w.Instructions))
+		}
+	}
+	return r
+}
+
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *CreateCommand) constructInput() *fastly.CreateTLSSubscriptionInput {
 	var input fastly.CreateTLSSubscriptionInput
@@ -3,9 +3,12 @@ package subscription_test
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/tls/subscription"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/go-fastly/v7/fastly"
@@ -49,11 +52,81 @@ func TestCreate(t *testing.T) {
 						},
 					}, nil
 				},
+				GetTLSSubscriptionFn: getSubscriptionStates("pending"),
 			},
 			Args:       args("tls-subscription create --domain example.com"),
 			WantOutput: fmt.Sprintf("Created TLS Subscription '%s' (Authority: %s, Common Name: example.com)", mockResponseID, certificateAuthority),
 		},
+		{
+			Name: "validate DNS challenges are displayed",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn:    getSubscriptionStates("pending"),
+			},
+			Args: args("tls-subscription create --domain example.com"),
+			WantOutputs: []string{
+				"AUTHORIZATION  STATE    CHALLENGE           RECORD NAME                  RECORD TYPE  VALUES",
+				"auth1          pending  managed_dns         _acme-challenge.example.com  CNAME        abc.fastly-validations.com",
+				"auth1          pending  managed_http_cname  example.com                  CNAME        j.sni.global.fastly.net",
+			},
+		},
+		{
+			Name: "validate DNS challenges can't be fetched",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn: func(_ *fastly.GetTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+					return nil, testutil.Err
+				},
+			},
+			Args: args("tls-subscription create --domain example.com"),
+			WantOutputs: []string{
+				"Failed to fetch the DNS challenges of the subscription: test error",
+				"fastly tls-subscription describe --id 123",
+			},
+		},
+		{
+			Name: "validate --json",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn:    getSubscriptionStates("pending"),
+			},
+			Args:       args("tls-subscription create --domain example.com --json"),
+			WantOutput: `{"id":"123","certificate_authority":"lets-encrypt","common_name":"example.com","domains":["example.com"],"state":"pending","challenges":[{"authorization_id":"auth1","authorization_state":"pending","type":"managed_dns","record_name":"_acme-challenge.example.com","record_type":"CNAME","values":["abc.fastly-validations.com"]}`,
+		},
+		{
+			Name: "validate --wait until issued",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn:    getSubscriptionStates("pending", "processing", "issued"),
+			},
+			Args: args("tls-subscription create --domain example.com --wait"),
+			WantOutputs: []string{
+				"Subscription '123' is pending",
+				"Subscription '123' is processing",
+				"The certificate of TLS Subscription '123' has been issued",
+			},
+		},
+		{
+			Name: "validate --wait when validation fails",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn:    getSubscriptionStates("pending", "failed"),
+			},
+			Args:      args("tls-subscription create --domain example.com --wait"),
+			WantError: "the validation of authorization 'auth1' failed",
+		},
+		{
+			Name: "validate --wait times out",
+			API: mock.API{
+				CreateTLSSubscriptionFn: createSubscriptionOK,
+				GetTLSSubscriptionFn:    getSubscriptionStates("pending"),
+			},
+			Args:      args("tls-subscription create --domain example.com --wait --wait-timeout 0s"),
+			WantError: "timed out after 0s waiting for the certificate of TLS Subscription '123' to be issued (state: pending)",
+		},
 	}
+	defer func(d time.Duration) { subscription.PollInterval = d }(subscription.PollInterval)
+	subscription.PollInterval = 0
 
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -64,10 +137,149 @@ func TestCreate(t *testing.T) {
 			err := app.Run(opts)
 			testutil.AssertErrorContains(t, err, testcase.WantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestCreateWizard(t *testing.T) {
+	var got *fastly.CreateTLSSubscriptionInput
+	api := mock.API{
+		CreateTLSSubscriptionFn: func(i *fastly.CreateTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+			got = i
+			return createSubscriptionOK(i)
+		},
+		GetTLSSubscriptionFn: getSubscriptionStates("pending", "issued"),
+	}
+	defer func(d time.Duration) { subscription.PollInterval = d }(subscription.PollInterval)
+	subscription.PollInterval = 0
+
+	for _, testcase := range []struct {
+		name           string
+		args           string
+		stdin          string
+		wantError      string
+		wantOutput     []string
+		wantDomains    []string
+		wantCommonName string
+		wantCertAuth   string
+	}{
+		{
+			name:         "single domain",
+			args:         "tls-subscription create",
+			stdin:        "example.com\n\nn\n",
+			wantOutput:   []string{"Domains to secure", "Certificate authority [lets-encrypt]", "Wait for the domains to be validated?", "_acme-challenge.example.com"},
+			wantDomains:  []string{"example.com"},
+			wantCertAuth: "lets-encrypt",
+		},
+		{
+			name:           "multiple domains",
+			args:           "tls-subscription create",
+			stdin:          "\nexample.com, www.example.com\nwww.example.com\nfoo\n2\ny\n",
+			wantOutput:     []string{"Common name [example.com]", "Select a number between 1 and 2", "has been issued"},
+			wantDomains:    []string{"example.com", "www.example.com"},
+			wantCommonName: "www.example.com",
+			wantCertAuth:   "globalsign",
+		},
+		{
+			name:         "flags aren't prompted for",
+			args:         "tls-subscription create --cert-auth globalsign --wait",
+			stdin:        "example.com\n",
+			wantOutput:   []string{"has been issued"},
+			wantDomains:  []string{"example.com"},
+			wantCertAuth: "globalsign",
+		},
+		{
+			name:      "non-interactive",
+			args:      "tls-subscription create --non-interactive",
+			stdin:     "example.com\n",
+			wantError: "required flag --domain not provided",
+		},
+		{
+			name:      "input ends",
+			args:      "tls-subscription create",
+			stdin:     "example.com\n",
+			wantError: "error reading input: EOF",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			got = nil
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testutil.Args(testcase.args), &stdout)
+			opts.APIClient = mock.APIClient(api)
+			opts.Stdin = strings.NewReader(testcase.stdin)
+			err := app.Run(opts)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantDomains == nil {
+				return
+			}
+			var domains []string
+			for _, d := range got.Domains {
+				domains = append(domains, d.ID)
+			}
+			testutil.AssertEqual(t, testcase.wantDomains, domains)
+			var commonName string
+			if got.CommonName != nil {
+				commonName = got.CommonName.ID
+			}
+			testutil.AssertString(t, testcase.wantCommonName, commonName)
+			testutil.AssertString(t, testcase.wantCertAuth, got.CertificateAuthority)
 		})
 	}
 }
 
+func createSubscriptionOK(i *fastly.CreateTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+	return &fastly.TLSSubscription{
+		ID:                   mockResponseID,
+		CertificateAuthority: certificateAuthority,
+		CommonName:           &fastly.TLSDomain{ID: "example.com"},
+	}, nil
+}
+
+// getSubscriptionStates returns a subscription in each of the states in turn,
+// staying in the last.
+func getSubscriptionStates(states ...string) func(*fastly.GetTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+	var n int
+	return func(i *fastly.GetTLSSubscriptionInput) (*fastly.TLSSubscription, error) {
+		if i.Include == nil || *i.Include != "tls_authorizations" {
+			return nil, testutil.Err
+		}
+		state := states[len(states)-1]
+		if n < len(states) {
+			state = states[n]
+		}
+		n++
+		authState := state
+		if state == "issued" {
+			authState = "valid"
+		}
+		return &fastly.TLSSubscription{
+			ID:                   i.ID,
+			CertificateAuthority: certificateAuthority,
+			CommonName:           &fastly.TLSDomain{ID: "example.com"},
+			Domains:              []*fastly.TLSDomain{{ID: "example.com"}},
+			State:                state,
+			Authorizations: []*fastly.TLSAuthorizations{
+				{
+					ID:    "auth1",
+					State: authState,
+					Challenges: []fastly.TLSChallenge{
+						{Type: "managed_dns", RecordName: "_acme-challenge.example.com", RecordType: "CNAME", Values: []string{"abc.fastly-validations.com"}},
+						{Type: "managed_http_cname", RecordName: "example.com", RecordType: "CNAME", Values: []string{"j.sni.global.fastly.net"}},
+					},
+				},
+			},
+		}, nil
+	}
+}
+
 func TestDelete(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
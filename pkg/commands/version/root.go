@@ -7,8 +7,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/blang/semver"
 	"github.com/fastly/cli/pkg/cmd"
 	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/update"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/github"
 	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/useragent"
@@ -23,26 +26,132 @@ func init() {
 	fastly.UserAgent = fmt.Sprintf("%s, %s", useragent.Name, fastly.UserAgent)
 }
 
+// releaseNotesURL is the URL of the release notes for a given CLI version.
+const releaseNotesURL = "https://github.com/fastly/cli/releases/tag/v%s"
+
 // RootCommand is the parent command for all subcommands in this package.
 // It should be installed under the primary root command.
 type RootCommand struct {
 	cmd.Base
-	av github.AssetVersioner
+	cmd.JSONOutput
+
+	av             github.AssetVersioner
+	check          bool
+	cliAV          github.AssetVersioner
+	exitIfOutdated bool
 }
 
 // NewRootCommand returns a new command registered in the parent.
-func NewRootCommand(parent cmd.Registerer, av github.AssetVersioner) *RootCommand {
+func NewRootCommand(parent cmd.Registerer, cliAV, av github.AssetVersioner) *RootCommand {
 	var c RootCommand
 	c.av = av
+	c.cliAV = cliAV
 	c.CmdClause = parent.Command("version", "Display version information for the Fastly CLI")
+	c.CmdClause.Flag("check", "Check whether a newer version of the Fastly CLI is available").BoolVar(&c.check)
+	c.CmdClause.Flag("exit-nonzero-if-outdated", "Exit with a non-zero status if a newer version of the Fastly CLI is available (implies --check)").BoolVar(&c.exitIfOutdated)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	return &c
 }
 
+// Info is the version information rendered when the --json flag is set.
+type Info struct {
+	Version   string      `json:"version"`
+	Commit    string      `json:"commit"`
+	GoVersion string      `json:"go_version"`
+	Viceroy   string      `json:"viceroy_version,omitempty"`
+	Update    *UpdateInfo `json:"update,omitempty"`
+}
+
+// UpdateInfo describes whether a newer version of the CLI is available.
+type UpdateInfo struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest"`
+	Outdated        bool   `json:"outdated"`
+	ReleaseNotesURL string `json:"release_notes_url"`
+}
+
+// ChecksForUpdate indicates if the command will itself check for a newer
+// version of the CLI (so the usual background check can be skipped).
+func (c *RootCommand) ChecksForUpdate() bool {
+	return c.check || c.exitIfOutdated
+}
+
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
-	fmt.Fprintf(out, "Fastly CLI version %s (%s)\n", revision.AppVersion, revision.GitCommit)
-	fmt.Fprintf(out, "Built with %s\n", revision.GoVersion)
+	info := Info{
+		Version:   revision.AppVersion,
+		Commit:    revision.GitCommit,
+		GoVersion: revision.GoVersion,
+		Viceroy:   c.viceroyVersion(),
+	}
+
+	if c.ChecksForUpdate() {
+		u, err := c.checkUpdate()
+		if err != nil {
+			return err
+		}
+		info.Update = u
+	}
+
+	if ok, err := c.WriteJSON(out, info); ok {
+		if err != nil {
+			return err
+		}
+		return c.outdatedError(info.Update)
+	}
+
+	fmt.Fprintf(out, "Fastly CLI version %s (%s)\n", info.Version, info.Commit)
+	fmt.Fprintf(out, "Built with %s\n", info.GoVersion)
+	if info.Viceroy != "" {
+		fmt.Fprintf(out, "Viceroy version: %s\n", info.Viceroy)
+	}
+
+	if u := info.Update; u != nil {
+		fmt.Fprintf(out, "\nLatest version: %s\n", u.Latest)
+		if u.Outdated {
+			fmt.Fprintf(out, "A new version of the Fastly CLI is available.\n")
+			fmt.Fprintf(out, "Release notes: %s\n", u.ReleaseNotesURL)
+			fmt.Fprintf(out, "Run `fastly update` to get the latest version.\n")
+		} else {
+			fmt.Fprintf(out, "The Fastly CLI is up to date.\n")
+		}
+	}
+
+	return c.outdatedError(info.Update)
+}
+
+// checkUpdate compares the current CLI version against the latest release.
+func (c *RootCommand) checkUpdate() (*UpdateInfo, error) {
+	if c.cliAV == nil {
+		return nil, fmt.Errorf("error checking for the latest version: no versioner configured")
+	}
+	current, latest, outdated := update.Check(revision.AppVersion, c.cliAV)
+	if latest.Equals(semver.Version{}) {
+		return nil, fmt.Errorf("error checking for the latest version: unable to determine the latest Fastly CLI release")
+	}
+	return &UpdateInfo{
+		Current:         current.String(),
+		Latest:          latest.String(),
+		Outdated:        outdated,
+		ReleaseNotesURL: fmt.Sprintf(releaseNotesURL, latest),
+	}, nil
+}
 
+// outdatedError returns an error if --exit-nonzero-if-outdated is set and a
+// newer version of the CLI is available.
+func (c *RootCommand) outdatedError(u *UpdateInfo) error {
+	if !c.exitIfOutdated || u == nil || !u.Outdated {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("the Fastly CLI is outdated (current: %s, latest: %s)", u.Current, u.Latest),
+		Remediation: "Run `fastly update` to get the latest version.",
+	}
+}
+
+// viceroyVersion returns the version output of the installed Viceroy binary
+// (if there is one).
+func (c *RootCommand) viceroyVersion() string {
 	viceroy := filepath.Join(compute.InstallDir, c.av.BinaryName())
 	// gosec flagged this:
 	// G204 (CWE-78): Subprocess launched with variable
@@ -53,10 +162,9 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	// nosemgrep
 	command := exec.Command(viceroy, "--version")
 	if stdoutStderr, err := command.CombinedOutput(); err == nil {
-		fmt.Fprintf(out, "Viceroy version: %s", stdoutStderr)
+		return strings.TrimSpace(string(stdoutStderr))
 	}
-
-	return nil
+	return ""
 }
 
 // IsPreRelease determines if the given app version is a pre-release.
@@ -12,6 +12,8 @@ import (
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/testutil"
 )
 
@@ -85,3 +87,112 @@ func TestVersion(t *testing.T) {
 		"",
 	}, "\n"), stdout.String())
 }
+
+func TestVersionCheck(t *testing.T) {
+	// Override the InstallDir so no viceroy binary is found.
+	orgInstallDir := compute.InstallDir
+	compute.InstallDir = t.TempDir()
+	defer func() {
+		compute.InstallDir = orgInstallDir
+	}()
+
+	orgAppVersion := revision.AppVersion
+	defer func() {
+		revision.AppVersion = orgAppVersion
+	}()
+
+	scenarios := []struct {
+		testutil.TestScenario
+		appVersion    string
+		latestVersion string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate latest version cannot be determined",
+				Args:      testutil.Args("version --check"),
+				WantError: "error checking for the latest version",
+			},
+			appVersion: "v1.2.3",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success up to date",
+				Args: testutil.Args("version --check"),
+				WantOutputs: []string{
+					"Fastly CLI version v1.2.3",
+					"Latest version: 1.2.3",
+					"The Fastly CLI is up to date.",
+				},
+			},
+			appVersion:    "v1.2.3",
+			latestVersion: "1.2.3",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success outdated",
+				Args: testutil.Args("version --check"),
+				WantOutputs: []string{
+					"Latest version: 1.3.0",
+					"A new version of the Fastly CLI is available.",
+					"Release notes: https://github.com/fastly/cli/releases/tag/v1.3.0",
+				},
+			},
+			appVersion:    "v1.2.3",
+			latestVersion: "1.3.0",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "success outdated with --json",
+				Args: testutil.Args("version --check --json"),
+				WantOutputs: []string{
+					`"version": "v1.2.3"`,
+					`"current": "1.2.3"`,
+					`"latest": "1.3.0"`,
+					`"outdated": true`,
+					`"release_notes_url": "https://github.com/fastly/cli/releases/tag/v1.3.0"`,
+				},
+			},
+			appVersion:    "v1.2.3",
+			latestVersion: "1.3.0",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "success up to date with --exit-nonzero-if-outdated",
+				Args:       testutil.Args("version --exit-nonzero-if-outdated"),
+				WantOutput: "The Fastly CLI is up to date.",
+			},
+			appVersion:    "v1.3.0",
+			latestVersion: "1.3.0",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate outdated with --exit-nonzero-if-outdated",
+				Args:       testutil.Args("version --exit-nonzero-if-outdated --json"),
+				WantError:  "the Fastly CLI is outdated (current: 1.2.3, latest: 1.3.0)",
+				WantOutput: `"outdated": true`,
+			},
+			appVersion:    "v1.2.3",
+			latestVersion: "1.3.0",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			revision.AppVersion = testcase.appVersion
+
+			var stdout bytes.Buffer
+			opts := testutil.NewRunOpts(testcase.Args, &stdout)
+			opts.Versioners = app.Versioners{
+				CLI:     mock.AssetVersioner{AssetVersion: testcase.latestVersion},
+				Viceroy: mock.AssetVersioner{BinaryFilename: "viceroy"},
+			}
+			err := app.Run(opts)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
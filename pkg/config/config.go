@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -59,6 +60,29 @@ type LegacyUser struct {
 // Fastly represents fastly specific configuration.
 type Fastly struct {
 	APIEndpoint string `toml:"api_endpoint"`
+	// RequireActivationComment refuses service version activation unless a
+	// comment describing the change is provided.
+	RequireActivationComment bool `toml:"require_activation_comment"`
+}
+
+// ComputeInit represents the organisation defaults applied to every project
+// created by `compute init`.
+type ComputeInit struct {
+	// CodeOwners are the owners written to a CODEOWNERS file (e.g. @org/team).
+	CodeOwners []string `toml:"code_owners"`
+	// License is the path of a file copied into the project as LICENSE.
+	License string `toml:"license"`
+	// RequiredFields are the fastly.toml fields a project must set (e.g. authors).
+	RequiredFields []string `toml:"required_fields"`
+	// StarterKitIndexes are the URLs of JSON documents, with the same structure
+	// as StarterKits, listing additional starter kits.
+	StarterKitIndexes []string `toml:"starter_kit_indexes"`
+	// StarterKits are additional starter kits (e.g. hosted on an internal Git
+	// host) listed alongside the official ones.
+	StarterKits StarterKitLanguages `toml:"starter_kits"`
+	// Template is a directory, or git repository URL, whose files are copied
+	// into the project.
+	Template string `toml:"template"`
 }
 
 // CLI represents CLI specific configuration.
@@ -112,23 +136,46 @@ type Profile struct {
 	Default bool   `toml:"default" json:"default"`
 	Email   string `toml:"email" json:"email"`
 	Token   string `toml:"token" json:"token"`
+	// SoftPurge makes `fastly purge` default to soft purging (see --soft).
+	SoftPurge bool `toml:"soft_purge,omitempty" json:"soft_purge,omitempty"`
 }
 
+// Staged maps a service ID to the draft service version that was staged for
+// later activation (see `fastly service-version stage`).
+type Staged map[string]int
+
 // StarterKitLanguages represents language specific starter kits.
 type StarterKitLanguages struct {
-	AssemblyScript []StarterKit `toml:"assemblyscript"`
-	Go             []StarterKit `toml:"go"`
-	JavaScript     []StarterKit `toml:"javascript"`
-	Rust           []StarterKit `toml:"rust"`
+	AssemblyScript []StarterKit `toml:"assemblyscript" json:"assemblyscript"`
+	Go             []StarterKit `toml:"go" json:"go"`
+	JavaScript     []StarterKit `toml:"javascript" json:"javascript"`
+	Rust           []StarterKit `toml:"rust" json:"rust"`
 }
 
 // StarterKit represents starter kit specific configuration.
 type StarterKit struct {
-	Name        string `toml:"name"`
-	Description string `toml:"description"`
-	Path        string `toml:"path"`
-	Tag         string `toml:"tag"`
-	Branch      string `toml:"branch"`
+	Name        string `toml:"name" json:"name"`
+	Description string `toml:"description" json:"description"`
+	Path        string `toml:"path" json:"path"`
+	Tag         string `toml:"tag" json:"tag"`
+	Branch      string `toml:"branch" json:"branch"`
+	// Tags are keywords describing the starter kit, used by `compute starters
+	// list --search`.
+	Tags []string `toml:"tags" json:"tags,omitempty"`
+}
+
+// Append returns the starter kits of both s and other, those of s first.
+func (s StarterKitLanguages) Append(other StarterKitLanguages) StarterKitLanguages {
+	join := func(a, b []StarterKit) []StarterKit {
+		kits := make([]StarterKit, 0, len(a)+len(b))
+		return append(append(kits, a...), b...)
+	}
+	return StarterKitLanguages{
+		AssemblyScript: join(s.AssemblyScript, other.AssemblyScript),
+		Go:             join(s.Go, other.Go),
+		JavaScript:     join(s.JavaScript, other.JavaScript),
+		Rust:           join(s.Rust, other.Rust),
+	}
 }
 
 // createConfigDir creates the application configuration directory if it
@@ -141,10 +188,12 @@ func createConfigDir(path string) error {
 // File represents our application toml configuration.
 type File struct {
 	CLI           CLI                 `toml:"cli"`
+	ComputeInit   ComputeInit         `toml:"compute-init"`
 	ConfigVersion int                 `toml:"config_version"`
 	Fastly        Fastly              `toml:"fastly"`
 	Language      Language            `toml:"language"`
 	Profiles      Profiles            `toml:"profile"`
+	Staged        Staged              `toml:"staged"`
 	StarterKits   StarterKitLanguages `toml:"starter-kits"`
 	Viceroy       Viceroy             `toml:"viceroy"`
 
@@ -388,12 +437,24 @@ func (f *File) Write(path string) error {
 type Environment struct {
 	Token    string
 	Endpoint string
+	CI       text.CI
+	// CIVar is the env var that identified a CI environment (see env.CI).
+	CIVar    string
+	ReadOnly bool
 }
 
 // Read populates the fields from the provided environment.
 func (e *Environment) Read(state map[string]string) {
 	e.Token = state[env.Token]
 	e.Endpoint = state[env.Endpoint]
+	e.ReadOnly = state[env.ReadOnly] == "1" || strings.EqualFold(state[env.ReadOnly], "true")
+	e.CI = text.DetectCI(state)
+	for _, v := range env.CI {
+		if s, ok := state[v]; ok && s != "" && !strings.EqualFold(s, "false") {
+			e.CIVar = v
+			break
+		}
+	}
 }
 
 // invalidStaticConfigErr generates an error to alert the user to an issue with
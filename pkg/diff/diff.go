@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// Sections is the ordered list of configuration sections that are compared.
+var Sections = []string{"settings", "domains", "backends", "headers", "vcls", "snippets"}
+
+// Change types.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// Resource is a flattened view of a single configuration object, mapping each
+// of its fields to a string representation of the field's value.
+type Resource map[string]string
+
+// Config is a flattened view of a service version's configuration, keyed by
+// section and then by resource name.
+type Config map[string]map[string]Resource
+
+// Change describes a difference between two configurations.
+type Change struct {
+	Section string        `json:"section"`
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	Fields  []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange describes a difference in a single resource field.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ignoredFields are fields that differ between versions without reflecting a
+// change in configuration.
+var ignoredFields = map[string]bool{
+	"created_at": true,
+	"deleted_at": true,
+	"id":         true,
+	"service_id": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+// Fetch retrieves the configuration of the given service version.
+func Fetch(client api.Interface, serviceID string, version int) (Config, error) {
+	cfg := make(Config)
+	for _, s := range Sections {
+		cfg[s] = make(map[string]Resource)
+	}
+
+	settings, err := client.GetSettings(&fastly.GetSettingsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching settings: %w", err)
+	}
+	cfg["settings"]["general"] = flatten(settings)
+
+	domains, err := client.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching domains: %w", err)
+	}
+	for _, d := range domains {
+		cfg["domains"][d.Name] = flatten(d)
+	}
+
+	backends, err := client.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching backends: %w", err)
+	}
+	for _, b := range backends {
+		cfg["backends"][b.Name] = flatten(b)
+	}
+
+	headers, err := client.ListHeaders(&fastly.ListHeadersInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching headers: %w", err)
+	}
+	for _, h := range headers {
+		cfg["headers"][h.Name] = flatten(h)
+	}
+
+	vcls, err := client.ListVCLs(&fastly.ListVCLsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching VCLs: %w", err)
+	}
+	for _, v := range vcls {
+		cfg["vcls"][v.Name] = flatten(v)
+	}
+
+	snippets, err := client.ListSnippets(&fastly.ListSnippetsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching snippets: %w", err)
+	}
+	for _, s := range snippets {
+		cfg["snippets"][s.Name] = flatten(s)
+	}
+
+	return cfg, nil
+}
+
+// flatten converts a go-fastly resource struct into a Resource using the
+// struct's mapstructure tags as field names.
+func flatten(v any) Resource {
+	r := make(Resource)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return r
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			name = strings.ToLower(rt.Field(i).Name)
+		}
+		name = strings.TrimPrefix(name, "general.")
+		if ignoredFields[name] {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				r[name] = ""
+				continue
+			}
+			fv = fv.Elem()
+		}
+		r[name] = fmt.Sprintf("%v", fv.Interface())
+	}
+	return r
+}
+
+// Compare returns the changes required to turn configuration a into b.
+//
+// NOTE: Changes are ordered by section (see Sections) and then by name.
+func Compare(a, b Config) []Change {
+	var changes []Change
+	for _, s := range Sections {
+		names := make(map[string]bool)
+		for n := range a[s] {
+			names[n] = true
+		}
+		for n := range b[s] {
+			names[n] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for n := range names {
+			sorted = append(sorted, n)
+		}
+		sort.Strings(sorted)
+
+		for _, n := range sorted {
+			from, inA := a[s][n]
+			to, inB := b[s][n]
+			switch {
+			case !inA:
+				changes = append(changes, Change{Section: s, Name: n, Type: Added, Fields: fieldChanges(nil, to)})
+			case !inB:
+				changes = append(changes, Change{Section: s, Name: n, Type: Removed, Fields: fieldChanges(from, nil)})
+			default:
+				if fc := fieldChanges(from, to); len(fc) > 0 {
+					changes = append(changes, Change{Section: s, Name: n, Type: Modified, Fields: fc})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// fieldChanges returns the sorted list of fields whose values differ.
+func fieldChanges(a, b Resource) []FieldChange {
+	fields := make(map[string]bool)
+	for f := range a {
+		fields[f] = true
+	}
+	for f := range b {
+		fields[f] = true
+	}
+	sorted := make([]string, 0, len(fields))
+	for f := range fields {
+		sorted = append(sorted, f)
+	}
+	sort.Strings(sorted)
+
+	var fc []FieldChange
+	for _, f := range sorted {
+		if a[f] != b[f] {
+			fc = append(fc, FieldChange{Field: f, From: a[f], To: b[f]})
+		}
+	}
+	return fc
+}
+
+// ServiceIDPlaceholder replaces a service ID in normalized configuration.
+const ServiceIDPlaceholder = "<service_id>"
+
+// Normalize replaces any reference to the given service ID within the values
+// of cfg with ServiceIDPlaceholder, so configuration from different services
+// can be compared without every ID reference being reported as a change.
+func Normalize(cfg Config, serviceID string) Config {
+	if serviceID == "" {
+		return cfg
+	}
+	for _, resources := range cfg {
+		for _, r := range resources {
+			for k, v := range r {
+				r[k] = strings.ReplaceAll(v, serviceID, ServiceIDPlaceholder)
+			}
+		}
+	}
+	return cfg
+}
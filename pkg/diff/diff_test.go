@@ -0,0 +1,152 @@
+package diff_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/fastly/cli/pkg/diff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCompare(t *testing.T) {
+	a := diff.Config{
+		"backends": {
+			"origin":  {"address": "a.example.com", "port": "443"},
+			"removed": {"address": "r.example.com"},
+		},
+	}
+	b := diff.Config{
+		"backends": {
+			"origin": {"address": "b.example.com", "port": "443"},
+		},
+		"domains": {
+			"www.example.com": {"name": "www.example.com"},
+		},
+	}
+
+	want := []diff.Change{
+		{
+			Section: "domains",
+			Name:    "www.example.com",
+			Type:    diff.Added,
+			Fields:  []diff.FieldChange{{Field: "name", To: "www.example.com"}},
+		},
+		{
+			Section: "backends",
+			Name:    "origin",
+			Type:    diff.Modified,
+			Fields:  []diff.FieldChange{{Field: "address", From: "a.example.com", To: "b.example.com"}},
+		},
+		{
+			Section: "backends",
+			Name:    "removed",
+			Type:    diff.Removed,
+			Fields:  []diff.FieldChange{{Field: "address", From: "r.example.com"}},
+		},
+	}
+	if got := diff.Compare(a, b); !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := diff.Normalize(diff.Config{"vcls": {"main": {"content": "# service abc"}}}, "abc")
+	b := diff.Normalize(diff.Config{"vcls": {"main": {"content": "# service xyz"}}}, "xyz")
+	if changes := diff.Compare(a, b); len(changes) != 0 {
+		t.Fatalf("want no changes, got: %+v", changes)
+	}
+}
+
+func TestLines(t *testing.T) {
+	got := diff.Lines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	want := []diff.Line{
+		{Op: diff.OpEqual, Text: "a"},
+		{Op: diff.OpRemove, Text: "b"},
+		{Op: diff.OpAdd, Text: "x"},
+		{Op: diff.OpEqual, Text: "c"},
+		{Op: diff.OpAdd, Text: "d"},
+	}
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+// TestLinesMinimal validates the diff of random inputs reproduces both inputs
+// and has the fewest changes, i.e. keeps a longest common subsequence.
+func TestLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, r.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		var from, to []string
+		var equal int
+		for _, l := range diff.Lines(a, b) {
+			if l.Op != diff.OpAdd {
+				from = append(from, l.Text)
+			}
+			if l.Op != diff.OpRemove {
+				to = append(to, l.Text)
+			}
+			if l.Op == diff.OpEqual {
+				equal++
+			}
+		}
+		if !cmp.Equal(a, from, cmpopts.EquateEmpty()) || !cmp.Equal(b, to, cmpopts.EquateEmpty()) {
+			t.Fatalf("the diff of %q and %q doesn't reproduce them", a, b)
+		}
+		if want := lcs(a, b); equal != want {
+			t.Fatalf("the diff of %q and %q keeps %d lines, want %d", a, b, equal, want)
+		}
+	}
+}
+
+// TestLinesLarge validates large inputs with few changes are compared quickly
+// and without a len(a)*len(b) table.
+func TestLinesLarge(t *testing.T) {
+	a := make([]string, 100000)
+	for i := range a {
+		a[i] = fmt.Sprintf("line %d", i)
+	}
+	b := append([]string{}, a...)
+	b[500] = "changed"
+	b = append(b[:70000], b[70001:]...)
+
+	var changes int
+	for _, l := range diff.Lines(a, b) {
+		if l.Op != diff.OpEqual {
+			changes++
+		}
+	}
+	if changes != 3 {
+		t.Fatalf("want 3 changed lines, have %d", changes)
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] >= cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
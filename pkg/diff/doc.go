@@ -0,0 +1,3 @@
+// Package diff contains abstractions for comparing the configuration of
+// Fastly service versions.
+package diff
@@ -0,0 +1,201 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	green = color.New(color.FgGreen).SprintFunc()
+	red   = color.New(color.FgRed).SprintFunc()
+	cyan  = color.New(color.FgCyan).SprintFunc()
+)
+
+// Print writes the changes to out as a colorized unified diff.
+func Print(out io.Writer, fromLabel, toLabel string, changes []Change) {
+	fmt.Fprintln(out, red("--- "+fromLabel))
+	fmt.Fprintln(out, green("+++ "+toLabel))
+
+	for _, c := range changes {
+		fmt.Fprintf(out, "\n%s\n", cyan(fmt.Sprintf("@@ %s/%s (%s) @@", c.Section, c.Name, c.Type)))
+		for _, f := range c.Fields {
+			if strings.Contains(f.From, "\n") || strings.Contains(f.To, "\n") {
+				fmt.Fprintf(out, "  %s:\n", f.Field)
+				for _, l := range Lines(splitLines(f.From), splitLines(f.To)) {
+					printLine(out, l.Op, "    "+l.Text)
+				}
+				continue
+			}
+			if c.Type != Added {
+				printLine(out, OpRemove, fmt.Sprintf("  %s: %s", f.Field, f.From))
+			}
+			if c.Type != Removed {
+				printLine(out, OpAdd, fmt.Sprintf("  %s: %s", f.Field, f.To))
+			}
+		}
+	}
+}
+
+func printLine(out io.Writer, op byte, s string) {
+	switch op {
+	case OpAdd:
+		fmt.Fprintln(out, green("+"+s))
+	case OpRemove:
+		fmt.Fprintln(out, red("-"+s))
+	default:
+		fmt.Fprintln(out, " "+s)
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// Line operations.
+const (
+	OpEqual  byte = ' '
+	OpAdd    byte = '+'
+	OpRemove byte = '-'
+)
+
+// Line is a single line of a line-based diff.
+type Line struct {
+	Op   byte
+	Text string
+}
+
+// Lines computes a minimal line-based diff of a and b using Myers' algorithm,
+// in its linear space variant, so large files (e.g. VCL) can be compared.
+//
+// Within each run of changed lines the removed lines are listed before the
+// added lines.
+func Lines(a, b []string) []Line {
+	edits := myers(a, b, nil)
+
+	lines := make([]Line, 0, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].Op == OpEqual {
+			lines = append(lines, edits[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != OpEqual {
+			j++
+		}
+		for _, op := range []byte{OpRemove, OpAdd} {
+			for _, l := range edits[i:j] {
+				if l.Op == op {
+					lines = append(lines, l)
+				}
+			}
+		}
+		i = j
+	}
+	return lines
+}
+
+// myers appends the diff of a and b to lines. It divides the problem at the
+// middle snake of an optimal edit path, which needs O(len(a)+len(b)) space.
+func myers(a, b []string, lines []Line) []Line {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		lines = append(lines, Line{OpEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+	var suffix int
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, s := range b {
+			lines = append(lines, Line{OpAdd, s})
+		}
+	case len(b) == 0:
+		for _, s := range a {
+			lines = append(lines, Line{OpRemove, s})
+		}
+	default:
+		// NOTE: With the common prefix and suffix removed the edit distance is at
+		// least 2, so both halves are smaller problems.
+		x, y, u, v := middleSnake(a, b)
+		lines = myers(a[:x], b[:y], lines)
+		for _, s := range a[x:u] {
+			lines = append(lines, Line{OpEqual, s})
+		}
+		lines = myers(a[u:], b[v:], lines)
+	}
+
+	for _, s := range common {
+		lines = append(lines, Line{OpEqual, s})
+	}
+	return lines
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of
+// an optimal edit path from a to b, found by searching forwards from the start
+// and backwards from the end until the paths overlap.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward[offset+k] is the furthest x reached on diagonal k = x - y from
+	// the start, and backward[offset+k] is the furthest distance reached on
+	// diagonal k from the end.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+			forward[offset+k] = u
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && u+backward[offset+c] >= n {
+				return x, y, u, v
+			}
+		}
+
+		for c := -d; c <= d; c += 2 {
+			var bx int
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				bx = backward[offset+c+1]
+			} else {
+				bx = backward[offset+c-1] + 1
+			}
+			by := bx - c
+			ex, ey := bx, by
+			for ex < n && ey < m && a[n-1-ex] == b[m-1-ey] {
+				ex++
+				ey++
+			}
+			backward[offset+c] = ex
+			if k := delta - c; !odd && k >= -d && k <= d && ex+forward[offset+k] >= n {
+				return n - ex, m - ey, n - bx, m - by
+			}
+		}
+	}
+
+	// The paths always overlap by d = limit.
+	panic("diff: no middle snake")
+}
@@ -16,4 +16,21 @@ const (
 
 	// CustomerID is the env var we look in for a Customer ID.
 	CustomerID = "FASTLY_CUSTOMER_ID"
+
+	// ReadOnly is the env var we look in to enable read-only mode.
+	ReadOnly = "FASTLY_READ_ONLY"
 )
+
+// CI lists the env vars set by common CI systems. If any of them is set (and
+// not "false"), the CLI won't wait for user input to interactive prompts.
+var CI = []string{
+	"CI",
+	"BUILDKITE",
+	"CIRCLECI",
+	"CODEBUILD_BUILD_ID",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"JENKINS_URL",
+	"TEAMCITY_VERSION",
+	"TF_BUILD",
+}
@@ -15,6 +15,12 @@ var ErrSignalKilled = fmt.Errorf("a SIGTERM was received")
 // file modification noticed while running `compute serve --watch`.
 var ErrViceroyRestart = fmt.Errorf("a RESTART was initiated")
 
+// ErrViceroyReload means the viceroy binary needs to be restarted, without
+// rebuilding the project, due to a modification of a file backing a local
+// store (e.g. [local_server.config_stores]) noticed while running `compute
+// serve`.
+var ErrViceroyReload = fmt.Errorf("a RELOAD was initiated")
+
 // ErrIncompatibleServeFlags means no --skip-build can't be used with --watch
 // because it defeats the purpose of --watch which is designed to restart
 // Viceroy whenever changes are detected (those changes would not be seen if we
@@ -113,3 +119,11 @@ var ErrInvalidVerboseJSONCombo = RemediationError{
 	Inner:       fmt.Errorf("invalid flag combination, --verbose and --json"),
 	Remediation: "Use either --verbose or --json, not both.",
 }
+
+// ErrActivationCommentRequired means the user attempted to activate a service
+// version without a comment while the `require_activation_comment` option is
+// enabled in the CLI configuration.
+var ErrActivationCommentRequired = RemediationError{
+	Inner:       fmt.Errorf("a comment is required to activate a service version"),
+	Remediation: "Describe the change being activated using the --comment flag (or disable `require_activation_comment` in the [fastly] section of the CLI config file).",
+}
@@ -62,6 +62,18 @@ var AuthRemediation = fmt.Sprintf(strings.Join([]string{
 	"Verify that the token is still valid via `fastly whoami`.",
 }, " "), env.Token)
 
+// PromptUnavailableRemediation suggests how to avoid interactive prompts.
+var PromptUnavailableRemediation = strings.Join([]string{
+	"The CLI won't wait for input in a CI environment.",
+	"Provide the value via the relevant command flag, or set --accept-defaults, --auto-yes or --non-interactive to let the CLI answer prompts for you.",
+}, " ")
+
+// ReadOnlyRemediation explains how to disable read-only mode.
+var ReadOnlyRemediation = fmt.Sprintf(strings.Join([]string{
+	"Read-only mode is enabled, so commands that modify resources can't be run.",
+	"Remove the --read-only flag (and unset the environment variable %s) to run this command.",
+}, " "), env.ReadOnly)
+
 // NetworkRemediation suggests, somewhat unhelpfully, to try again later.
 var NetworkRemediation = strings.Join([]string{
 	"This error may be caused by transient network issues.",
@@ -166,3 +178,12 @@ var InvalidStaticConfigRemediation = strings.Join([]string{
 	"If this does not resolve the issue, then please file an issue:",
 	"https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md",
 }, " ")
+
+// QueryRemediation explains the --query flag only filters JSON output, and
+// lists the supported subset of JMESPath.
+var QueryRemediation = strings.Join([]string{
+	"The --query flag filters a command's JSON output using a subset of JMESPath (https://jmespath.org/):",
+	"fields (foo.bar, \"quoted-field\"), indexes ([0], [-1]), projections ([*], [], *),",
+	"filters ([?Active], [?Name=='foo'], [?Number > `2`]) and pipes ([?Active] | [0]).",
+	"Use it with a command that supports the --json flag, e.g. `fastly service-version list --json --query '[?Active].Number'`.",
+}, " ")
@@ -17,12 +17,16 @@ import (
 // divider is used as separator lines around shell output.
 const divider = "--------------------------------------------------------------------------------"
 
+// outputGroup is the name of the foldable CI log section for command output.
+const outputGroup = "command_output"
+
 // Streaming models a generic command execution that consumers can use to
 // execute commands and stream their output to an io.Writer. For example
 // compute commands can use this to standardize the flow control for each
 // compiler toolchain.
 type Streaming struct {
 	Args           []string
+	CI             text.CI
 	Command        string
 	Env            []string
 	ForceOutput    bool
@@ -99,14 +103,13 @@ func (s *Streaming) Exec() error {
 		output = s.Output
 	}
 
-	text.Info(output, "Command output:")
-	text.Output(output, divider)
+	s.startOutput(output)
 
 	cmd.Stdout = output
 	cmd.Stderr = output
 
 	if err := cmd.Start(); err != nil {
-		text.Output(output, divider)
+		s.endOutput(output)
 		return err
 	}
 
@@ -116,7 +119,7 @@ func (s *Streaming) Exec() error {
 	s.Process = cmd.Process
 
 	if err := cmd.Wait(); err != nil {
-		text.Output(output, divider)
+		s.endOutput(output)
 
 		// If we're in verbose mode, the build output is shown.
 		// So in that case we don't want to have a spinner as it'll interweave output.
@@ -141,10 +144,30 @@ func (s *Streaming) Exec() error {
 		return fmt.Errorf("error during execution process (see 'command output' above): %w", err)
 	}
 
-	text.Output(output, divider)
+	s.endOutput(output)
 	return nil
 }
 
+// startOutput writes the header that precedes the command output. In CI mode
+// the output is opened as a foldable log section.
+func (s *Streaming) startOutput(w io.Writer) {
+	if s.CI != "" {
+		text.CIGroupStart(w, s.CI, outputGroup, "Command output")
+		return
+	}
+	text.Info(w, "Command output:")
+	text.Output(w, divider)
+}
+
+// endOutput writes the footer that follows the command output.
+func (s *Streaming) endOutput(w io.Writer) {
+	if s.CI != "" {
+		text.CIGroupEnd(w, s.CI, outputGroup)
+		return
+	}
+	text.Output(w, divider)
+}
+
 // Signal enables spawned subprocess to accept given signal.
 func (s *Streaming) Signal(sig os.Signal) error {
 	if s.Process != nil {
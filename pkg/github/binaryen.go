@@ -0,0 +1,237 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fastly/cli/pkg/api"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
+	"github.com/mholt/archiver"
+)
+
+const (
+	// BinaryenVersion is the pinned binaryen release that provides wasm-opt.
+	BinaryenVersion = "116"
+
+	// BinaryenReleaseURL takes a version (twice) and a platform (e.g.
+	// x86_64-linux). A checksum of each asset is published at the same URL with
+	// a .sha256 suffix.
+	BinaryenReleaseURL = "https://github.com/WebAssembly/binaryen/releases/download/version_%s/binaryen-version_%s-%s.tar.gz"
+)
+
+// binaryenPlatforms are the platforms of the binaryen release assets, keyed by
+// GOOS/GOARCH.
+var binaryenPlatforms = map[string]string{
+	"darwin/amd64":  "x86_64-macos",
+	"darwin/arm64":  "arm64-macos",
+	"linux/amd64":   "x86_64-linux",
+	"linux/arm64":   "aarch64-linux",
+	"windows/amd64": "x86_64-windows",
+}
+
+// NewBinaryen returns a versioner for the wasm-opt binary of the pinned
+// binaryen release.
+func NewBinaryen(opts BinaryenOpts) *Binaryen {
+	binary := "wasm-opt"
+	if fstruntime.Windows {
+		binary = binary + ".exe"
+	}
+	releaseURL := opts.ReleaseURL
+	if releaseURL == "" {
+		releaseURL = BinaryenReleaseURL
+	}
+	version := opts.Version
+	if version == "" {
+		version = BinaryenVersion
+	}
+
+	return &Binaryen{
+		binary:     binary,
+		httpClient: opts.HTTPClient,
+		releaseURL: releaseURL,
+		version:    version,
+	}
+}
+
+// BinaryenOpts represents options to be passed to NewBinaryen.
+type BinaryenOpts struct {
+	// HTTPClient is able to make HTTP requests.
+	HTTPClient api.HTTPClient
+	// ReleaseURL overrides BinaryenReleaseURL.
+	ReleaseURL string
+	// Version overrides BinaryenVersion.
+	Version string
+}
+
+// Binaryen is a versioner that uses binaryen releases.
+//
+// Unlike Asset, the version is pinned rather than looked up, so a CLI release
+// always installs the same wasm-opt, and each download is verified against
+// the checksum published with the release.
+type Binaryen struct {
+	// binary is the name of the executable binary.
+	binary string
+	// httpClient is able to make HTTP requests.
+	httpClient api.HTTPClient
+	// releaseURL is the format of the release asset URLs.
+	releaseURL string
+	// version is the pinned release version.
+	version string
+}
+
+// BinaryName returns the configured binary output name.
+func (b Binaryen) BinaryName() string {
+	return b.binary
+}
+
+// Download retrieves the pinned release.
+func (b *Binaryen) Download() (bin string, err error) {
+	return b.DownloadVersion(b.version)
+}
+
+// DownloadVersion retrieves a release, verifies its checksum and extracts it
+// to a temporary directory.
+//
+// The returned path is the wasm-opt binary within the extracted release (i.e.
+// <tmp>/binaryen-version_<n>/bin/wasm-opt), as wasm-opt links the binaryen
+// library (in lib) on some platforms, so the whole release must be installed.
+// The caller is responsible for removing the temporary directory.
+func (b *Binaryen) DownloadVersion(version string) (bin string, err error) {
+	endpoint, err := b.versionURL(version)
+	if err != nil {
+		return "", err
+	}
+
+	want, err := b.checksum(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "fastly-download")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp release directory: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(tmpDir)
+		}
+	}()
+
+	res, err := b.get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to request binaryen release asset: %w", err)
+	}
+	defer res.Body.Close() // #nosec G307
+
+	archive, err := createArchive(filepath.Base(endpoint), tmpDir, res.Body)
+	if err != nil {
+		return "", err
+	}
+	have, err := fileChecksum(archive)
+	if err != nil {
+		return "", err
+	}
+	if have != want {
+		return "", fmt.Errorf("the checksum of %s doesn't match the published checksum (sha256 %s, want %s)", filepath.Base(endpoint), have, want)
+	}
+
+	if err := archiver.Unarchive(archive, tmpDir); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", filepath.Base(endpoint), err)
+	}
+	if err := os.Remove(archive); err != nil {
+		return "", err
+	}
+
+	bin = filepath.Join(tmpDir, "binaryen-version_"+version, "bin", b.binary)
+	if _, err := os.Stat(bin); err != nil {
+		return "", fmt.Errorf("%s not found in %s", b.binary, filepath.Base(endpoint))
+	}
+	return bin, nil
+}
+
+// URL returns the asset URL of the pinned release.
+func (b *Binaryen) URL() (url string, err error) {
+	return b.versionURL(b.version)
+}
+
+// Version returns the pinned release version.
+func (b *Binaryen) Version() (version string, err error) {
+	return b.version, nil
+}
+
+// versionURL returns the asset URL of a release for the current platform.
+func (b *Binaryen) versionURL(version string) (string, error) {
+	platform, ok := binaryenPlatforms[runtime.GOOS+"/"+runtime.GOARCH]
+	if !ok {
+		return "", fmt.Errorf("binaryen doesn't publish a release for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	return fmt.Sprintf(b.releaseURL, version, version, platform), nil
+}
+
+// checksum returns the sha256 checksum published for the release asset.
+//
+// The checksum file has the format: <checksum>  <filename>
+func (b *Binaryen) checksum(endpoint string) (string, error) {
+	res, err := b.get(endpoint + ".sha256")
+	if err != nil {
+		return "", fmt.Errorf("failed to request binaryen release checksum: %w", err)
+	}
+	defer res.Body.Close() // #nosec G307
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read binaryen release checksum: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the binaryen release checksum is empty")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// get makes a GET request, returning an error for a non-200 response.
+func (b *Binaryen) get(endpoint string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a HTTP request: %w", err)
+	}
+
+	if b.httpClient == nil {
+		b.httpClient = http.DefaultClient
+	}
+	res, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("%s", res.Status)
+	}
+	return res, nil
+}
+
+// fileChecksum returns the hex encoded sha256 checksum of the file at path.
+func fileChecksum(path string) (string, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the path is the downloaded archive.
+	// #nosec
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // #nosec G307
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
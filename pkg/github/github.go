@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/fastly/cli/pkg/api"
 	fstruntime "github.com/fastly/cli/pkg/runtime"
@@ -17,6 +18,10 @@ import (
 const (
 	// metadataURL takes a GitHub repo (e.g. cli or viceroy), an OS (e.g. darwin or linux), and an arch (e.g. amd64 or arm64).
 	metadataURL = "https://developer.fastly.com/api/internal/releases/meta/%s/%s/%s"
+
+	// releaseURL takes a GitHub org, a repo, a version (twice), the repo (for the
+	// asset name), an OS, an arch and an archive extension.
+	releaseURL = "https://github.com/%s/%s/releases/download/v%s/%s_v%s_%s-%s%s"
 )
 
 // New returns a usable asset.
@@ -76,7 +81,22 @@ func (g *Asset) Download() (bin string, err error) {
 	if err != nil {
 		return "", err
 	}
+	return g.download(endpoint)
+}
+
+// DownloadVersion retrieves the binary archive format of a specific release
+// from GitHub.
+func (g *Asset) DownloadVersion(version string) (bin string, err error) {
+	ext := ".tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = ".zip"
+	}
+	version = strings.TrimPrefix(version, "v")
+	return g.download(fmt.Sprintf(releaseURL, g.org, g.repo, version, g.repo, version, runtime.GOOS, runtime.GOARCH, ext))
+}
 
+// download retrieves the binary archive from endpoint and extracts the binary.
+func (g *Asset) download(endpoint string) (bin string, err error) {
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create a HTTP request: %w", err)
@@ -194,6 +214,8 @@ type AssetVersioner interface {
 	BinaryName() string
 	// Download implements the Versioner interface.
 	Download() (bin string, err error)
+	// DownloadVersion downloads a specific release rather than the latest.
+	DownloadVersion(version string) (bin string, err error)
 	// URL returns the asset URL if set, otherwise calls the API metadata endpoint.
 	URL() (url string, err error)
 	// Version returns the asset Version if set, otherwise calls the API metadata endpoint.
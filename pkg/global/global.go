@@ -1,6 +1,7 @@
 package global
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/api"
@@ -8,6 +9,7 @@ import (
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // DefaultEndpoint is the default Fastly API endpoint.
@@ -34,6 +36,10 @@ type Data struct {
 	Output   io.Writer
 	Path     string
 
+	// Progress emits progress events when --progress-json or --profile-cli is
+	// set (otherwise it's nil, which discards events).
+	Progress *text.Progress
+
 	// Custom interfaces
 	ErrLog     fsterr.LogInterface
 	APIClient  api.Interface
@@ -83,6 +89,53 @@ func (d *Data) Token() (string, lookup.Source) {
 	return "", lookup.SourceUndefined
 }
 
+// Profile yields the profile in use, resolved in the same order as Token (the
+// --profile flag, the manifest `profile` field, then the default profile).
+//
+// NOTE: nil is returned if there is no such profile.
+func (d *Data) Profile() (string, *config.Profile) {
+	for _, name := range []string{d.Flags.Profile, d.Manifest.File.Profile} {
+		if name == "" {
+			continue
+		}
+		if p, ok := d.Config.Profiles[name]; ok {
+			return name, p
+		}
+	}
+	for k, v := range d.Config.Profiles {
+		if v.Default {
+			return k, v
+		}
+	}
+	return "", nil
+}
+
+// CI yields the CI system whose log syntax should be emitted (if any). The
+// --output-ci=auto flag detects the system from the environment.
+func (d *Data) CI() text.CI {
+	if d.Flags.OutputCI == "auto" {
+		return d.Env.CI
+	}
+	return text.CI(d.Flags.OutputCI)
+}
+
+// Promptable indicates if the user can respond to interactive prompts. When
+// running in a CI environment, prompts are disabled unless one of the
+// --accept-defaults, --auto-yes or --non-interactive flags is set (as they
+// determine how prompts are handled), and the reason is returned.
+func (d *Data) Promptable() (bool, string) {
+	if d.Env.CIVar == "" || d.Flags.AcceptDefaults || d.Flags.AutoYes || d.Flags.NonInteractive {
+		return true, ""
+	}
+	return false, fmt.Sprintf("CI environment detected via $%s", d.Env.CIVar)
+}
+
+// ReadOnly indicates if mutating API calls should be prevented, which can be
+// enabled via flag or env var.
+func (d *Data) ReadOnly() bool {
+	return d.Flags.ReadOnly || d.Env.ReadOnly
+}
+
 // Verbose yields the verbose flag, which can only be set via flags.
 func (d *Data) Verbose() bool {
 	return d.Flags.Verbose
@@ -111,10 +164,18 @@ func (d *Data) Endpoint() (string, lookup.Source) {
 type Flags struct {
 	AcceptDefaults bool
 	AutoYes        bool
+	Curl           bool
 	Endpoint       string
+	Env            string
 	NonInteractive bool
+	OutputCI       string
 	Profile        string
+	ProfileCLI     bool
+	ProgressJSON   bool
+	Query          string
 	Quiet          bool
+	ReadOnly       bool
+	RecordAPI      string
 	Token          string
 	Verbose        bool
 }
@@ -0,0 +1,4 @@
+// Package kvsync contains abstractions for synchronising a local file of
+// key/value pairs with a remote store (e.g. a dictionary or config store),
+// detecting values changed remotely since the last sync.
+package kvsync
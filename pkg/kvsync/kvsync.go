@@ -0,0 +1,476 @@
+package kvsync
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// StateSuffix is appended to the path of the local file to give the path of
+// its hash manifest, which records the hash of each value as of the last sync
+// with each remote store.
+const StateSuffix = ".sync.json"
+
+// The values of the --prefer flag.
+const (
+	PreferLocal  = "local"
+	PreferRemote = "remote"
+)
+
+// Preferences are the values of the --prefer flag.
+var Preferences = []string{PreferLocal, PreferRemote}
+
+// Action is a modification of the remote store.
+type Action string
+
+// The modifications of the remote store.
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is a modification of a key of the remote store.
+type Change struct {
+	Action Action `json:"action"`
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Conflict is a key whose remote value changed since the last sync, as well
+// as differing from its local value. A nil value means the key is absent.
+type Conflict struct {
+	Key    string
+	Local  *string
+	Remote *string
+}
+
+// Resolution is how a conflict is resolved.
+type Resolution int
+
+// The resolutions of a conflict.
+const (
+	// KeepLocal overwrites the remote value with the local value.
+	KeepLocal Resolution = iota
+	// KeepRemote updates the local file with the remote value.
+	KeepRemote
+	// Skip leaves both values unchanged, so the conflict is reported again by
+	// the next sync.
+	Skip
+)
+
+// State is the state of a local file as of its last sync with a remote store.
+type State struct {
+	// Hashes are the hashes of the values of each key as of the last sync.
+	Hashes map[string]string `json:"hashes"`
+}
+
+// stateFile is the hash manifest of a local file, which holds the State of
+// each remote store the file is synced with, keyed by Target.
+type stateFile struct {
+	Targets map[string]State `json:"targets"`
+}
+
+// Target identifies a remote store in the hash manifest by its IDs, e.g. a
+// service ID and dictionary ID, so a file can be synced with several stores.
+func Target(ids ...string) string {
+	return strings.Join(ids, "/")
+}
+
+// Hash returns the hash of a value recorded in the State.
+func Hash(value string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))
+}
+
+// ReadState reads the state of the local file as of its last sync with the
+// target store.
+//
+// NOTE: A missing manifest or target isn't an error, as the file hasn't been
+// synced with the store yet.
+func ReadState(file, target string) (State, error) {
+	f, err := readStateFile(file)
+	if err != nil {
+		return State{}, err
+	}
+	s := f.Targets[target]
+	if s.Hashes == nil {
+		s.Hashes = make(map[string]string)
+	}
+	return s, nil
+}
+
+// Write records the state of the local file as of its sync with the target
+// store in the file's hash manifest.
+func (s State) Write(file, target string) error {
+	f, err := readStateFile(file)
+	if err != nil {
+		return err
+	}
+	f.Targets[target] = s
+	return writeJSON(file+StateSuffix, f)
+}
+
+// readStateFile reads the hash manifest of the local file.
+func readStateFile(file string) (stateFile, error) {
+	f := stateFile{Targets: make(map[string]State)}
+	data, err := os.ReadFile(file + StateSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return f, fmt.Errorf("error reading %s: %w", file+StateSuffix, err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("error parsing %s: %w", file+StateSuffix, err)
+	}
+	if f.Targets == nil {
+		f.Targets = make(map[string]State)
+	}
+	return f, nil
+}
+
+// ReadLocal reads the local file, a JSON object of string values.
+func ReadLocal(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+	var local map[string]string
+	if err := json.Unmarshal(data, &local); err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing %s: %w", file, err),
+			Remediation: `The file must be a JSON object of string values, e.g. {"key": "value"}.`,
+		}
+	}
+	if local == nil {
+		local = make(map[string]string)
+	}
+	return local, nil
+}
+
+// Diff returns the changes that make the remote store match the local file,
+// and the keys that can't be changed without overwriting a value changed
+// remotely since the last sync.
+//
+// NOTE: Remote keys that aren't in the local file, and weren't synced before,
+// are left unchanged as they're managed by other means.
+func Diff(local, remote map[string]string, state State) (changes []Change, conflicts []Conflict) {
+	for _, key := range keys(local, remote) {
+		l, lok := local[key]
+		r, rok := remote[key]
+		h, hok := state.Hashes[key]
+
+		switch {
+		case lok && rok && l == r:
+			continue
+		case lok && !rok && !hok:
+			changes = append(changes, Change{Action: Create, Key: key, Value: l})
+			continue
+		case lok && rok && hok && Hash(r) == h:
+			changes = append(changes, Change{Action: Update, Key: key, Value: l})
+			continue
+		case !lok && rok && !hok:
+			continue
+		case !lok && rok && Hash(r) == h:
+			changes = append(changes, Change{Action: Delete, Key: key})
+			continue
+		case !lok && !rok:
+			continue
+		}
+
+		c := Conflict{Key: key}
+		if lok {
+			c.Local = &l
+		}
+		if rok {
+			c.Remote = &r
+		}
+		conflicts = append(conflicts, c)
+	}
+	return changes, conflicts
+}
+
+// Replace returns the changes that make the remote store exactly match the
+// local file, deleting every remote key that isn't in the file.
+func Replace(local, remote map[string]string) []Change {
+	var changes []Change
+	for _, key := range keys(local, remote) {
+		l, lok := local[key]
+		r, rok := remote[key]
+		switch {
+		case lok && !rok:
+			changes = append(changes, Change{Action: Create, Key: key, Value: l})
+		case lok && l != r:
+			changes = append(changes, Change{Action: Update, Key: key, Value: l})
+		case !lok:
+			changes = append(changes, Change{Action: Delete, Key: key})
+		}
+	}
+	return changes
+}
+
+// Options control the behaviour of Sync.
+type Options struct {
+	// File is the path of the local file.
+	File string
+	// Target identifies the remote store (see Target).
+	Target string
+	// Remote holds the values of the remote store.
+	Remote map[string]string
+	// Apply makes the changes to the remote store.
+	Apply func([]Change) error
+	// Prefer resolves every conflict without prompting (see Preferences).
+	Prefer string
+	// Delete removes the remote keys that aren't in the local file and weren't
+	// synced before, which are otherwise left unchanged.
+	Delete bool
+	// NonInteractive skips conflicts rather than prompting, unless Prefer is
+	// set.
+	NonInteractive bool
+	In             io.Reader
+	Out            io.Writer
+}
+
+// Result summarises a sync.
+type Result struct {
+	Created    int      `json:"created"`
+	Updated    int      `json:"updated"`
+	Deleted    int      `json:"deleted"`
+	KeptRemote []string `json:"kept_remote,omitempty"`
+	Skipped    []string `json:"skipped,omitempty"`
+}
+
+// Sync makes the remote store match the local file, resolving each conflict
+// via Prefer or else by prompting, and records the state of the synced keys
+// for the target store in the file's hash manifest.
+func Sync(o Options) (Result, error) {
+	var result Result
+
+	local, err := ReadLocal(o.File)
+	if err != nil {
+		return result, err
+	}
+	state, err := ReadState(o.File, o.Target)
+	if err != nil {
+		return result, err
+	}
+
+	changes, conflicts := Diff(local, o.Remote, state)
+	if o.Delete {
+		changes = append(changes, unmanaged(local, o.Remote, state)...)
+	}
+
+	// NOTE: Prompts read a line at a time, so that each prompt receives its
+	// own line of input.
+	if _, ok := o.In.(text.NoPromptReader); ok {
+		o.NonInteractive = true
+	}
+	in := lineReader{o.In}
+
+	var localChanged bool
+	for _, c := range conflicts {
+		resolution, err := resolve(c, o.Prefer, o.NonInteractive, in, o.Out)
+		if err != nil {
+			return result, err
+		}
+		switch resolution {
+		case KeepLocal:
+			switch {
+			case c.Local == nil:
+				changes = append(changes, Change{Action: Delete, Key: c.Key})
+			case c.Remote == nil:
+				changes = append(changes, Change{Action: Create, Key: c.Key, Value: *c.Local})
+			default:
+				changes = append(changes, Change{Action: Update, Key: c.Key, Value: *c.Local})
+			}
+		case KeepRemote:
+			if c.Remote == nil {
+				delete(local, c.Key)
+			} else {
+				local[c.Key] = *c.Remote
+			}
+			localChanged = true
+			result.KeptRemote = append(result.KeptRemote, c.Key)
+		case Skip:
+			result.Skipped = append(result.Skipped, c.Key)
+		}
+	}
+
+	if len(changes) > 0 {
+		if err := o.Apply(changes); err != nil {
+			return result, err
+		}
+	}
+	remote := make(map[string]string, len(o.Remote))
+	for k, v := range o.Remote {
+		remote[k] = v
+	}
+	for _, c := range changes {
+		switch c.Action {
+		case Create:
+			result.Created++
+			remote[c.Key] = c.Value
+		case Update:
+			result.Updated++
+			remote[c.Key] = c.Value
+		case Delete:
+			result.Deleted++
+			delete(remote, c.Key)
+		}
+	}
+
+	if localChanged {
+		if err := writeJSON(o.File, local); err != nil {
+			return result, err
+		}
+	}
+
+	// NOTE: The state of skipped keys is left unchanged, so that they remain
+	// conflicts until resolved.
+	skipped := make(map[string]bool, len(result.Skipped))
+	for _, k := range result.Skipped {
+		skipped[k] = true
+	}
+	next := State{Hashes: make(map[string]string, len(local))}
+	for k, v := range local {
+		if skipped[k] {
+			continue
+		}
+		if r, ok := remote[k]; ok && r == v {
+			next.Hashes[k] = Hash(v)
+		}
+	}
+	for _, k := range result.Skipped {
+		if h, ok := state.Hashes[k]; ok {
+			next.Hashes[k] = h
+		}
+	}
+	if err := next.Write(o.File, o.Target); err != nil {
+		return result, err
+	}
+
+	if len(result.Skipped) > 0 && o.Prefer == "" && o.NonInteractive {
+		return result, fsterr.RemediationError{
+			Inner:       fmt.Errorf("%d keys changed remotely since the last sync and weren't synced: %s", len(result.Skipped), strings.Join(result.Skipped, ", ")),
+			Remediation: "Re-run the command interactively to resolve each conflict, or use --prefer local|remote.",
+		}
+	}
+	return result, nil
+}
+
+// unmanaged returns the deletion of each remote key that isn't in the local
+// file and wasn't synced before, which Diff leaves unchanged.
+func unmanaged(local, remote map[string]string, state State) []Change {
+	var changes []Change
+	for _, key := range keys(remote, nil) {
+		_, lok := local[key]
+		_, hok := state.Hashes[key]
+		if !lok && !hok {
+			changes = append(changes, Change{Action: Delete, Key: key})
+		}
+	}
+	return changes
+}
+
+// resolve returns the resolution of a conflict, prompting for it unless
+// prefer is set or prompts are disabled.
+func resolve(c Conflict, prefer string, nonInteractive bool, in io.Reader, out io.Writer) (Resolution, error) {
+	switch prefer {
+	case PreferLocal:
+		return KeepLocal, nil
+	case PreferRemote:
+		return KeepRemote, nil
+	}
+	if nonInteractive {
+		text.Warning(out, "Skipping '%s' as it changed remotely since the last sync.", c.Key)
+		return Skip, nil
+	}
+
+	text.Break(out)
+	text.Output(out, "%s '%s' changed remotely since the last sync.", text.BoldYellow("Conflict:"), c.Key)
+	text.Output(out, "  local:  %s", describe(c.Local))
+	text.Output(out, "  remote: %s", describe(c.Remote))
+	answer, err := text.Input(out, "Keep [l]ocal, keep [r]emote or [s]kip? ", in, func(s string) error {
+		switch strings.ToLower(s) {
+		case "l", "local", "r", "remote", "s", "skip":
+			return nil
+		}
+		return fmt.Errorf("enter l, r or s")
+	})
+	if err != nil {
+		return Skip, err
+	}
+	switch strings.ToLower(answer) {
+	case "l", "local":
+		return KeepLocal, nil
+	case "r", "remote":
+		return KeepRemote, nil
+	}
+	return Skip, nil
+}
+
+// describe renders a value of a conflict.
+func describe(v *string) string {
+	if v == nil {
+		return "(deleted)"
+	}
+	return fmt.Sprintf("%q", *v)
+}
+
+// keys returns the sorted keys of both maps.
+func keys(a, b map[string]string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var ks []string
+	for _, m := range []map[string]string{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				ks = append(ks, k)
+			}
+		}
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// writeJSON writes v to path as indented JSON.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// lineReader reads at most a line at a time from the wrapped reader, so that
+// the input of a later prompt isn't consumed by the buffer of an earlier one.
+type lineReader struct {
+	io.Reader
+}
+
+// Read implements io.Reader.
+func (r lineReader) Read(p []byte) (int, error) {
+	var n int
+	for n < len(p) {
+		m, err := r.Reader.Read(p[n : n+1])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		if m == 1 && p[n-1] == '\n' {
+			break
+		}
+	}
+	return n, nil
+}
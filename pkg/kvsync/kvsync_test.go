@@ -0,0 +1,218 @@
+package kvsync_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/kvsync"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	state := kvsync.State{Hashes: map[string]string{
+		"synced":         kvsync.Hash("v1"),
+		"hotfixed":       kvsync.Hash("v1"),
+		"deleted-local":  kvsync.Hash("v1"),
+		"deleted-remote": kvsync.Hash("v1"),
+		"hotfix-deleted": kvsync.Hash("v1"),
+	}}
+	local := map[string]string{
+		"synced":         "v2",
+		"hotfixed":       "v2",
+		"deleted-remote": "v1",
+		"new":            "v1",
+		"unsynced":       "local",
+		"same":           "v1",
+	}
+	remote := map[string]string{
+		"synced":         "v1",
+		"hotfixed":       "hotfix",
+		"deleted-local":  "v1",
+		"hotfix-deleted": "hotfix",
+		"unsynced":       "remote",
+		"same":           "v1",
+		"unmanaged":      "v1",
+	}
+
+	changes, conflicts := kvsync.Diff(local, remote, state)
+	testutil.AssertEqual(t, []kvsync.Change{
+		{Action: kvsync.Delete, Key: "deleted-local"},
+		{Action: kvsync.Create, Key: "new", Value: "v1"},
+		{Action: kvsync.Update, Key: "synced", Value: "v2"},
+	}, changes)
+
+	var keys []string
+	for _, c := range conflicts {
+		keys = append(keys, c.Key)
+	}
+	testutil.AssertEqual(t, []string{"deleted-remote", "hotfix-deleted", "hotfixed", "unsynced"}, keys)
+}
+
+func TestReplace(t *testing.T) {
+	local := map[string]string{"same": "v1", "changed": "v2", "new": "v1"}
+	remote := map[string]string{"same": "v1", "changed": "v1", "unmanaged": "v1"}
+
+	testutil.AssertEqual(t, []kvsync.Change{
+		{Action: kvsync.Update, Key: "changed", Value: "v2"},
+		{Action: kvsync.Create, Key: "new", Value: "v1"},
+		{Action: kvsync.Delete, Key: "unmanaged"},
+	}, kvsync.Replace(local, remote))
+}
+
+func TestSync(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "items.json")
+	write := func(s string) {
+		if err := os.WriteFile(file, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remote := map[string]string{"a": "1", "b": "2"}
+	apply := func(changes []kvsync.Change) error {
+		for _, c := range changes {
+			if c.Action == kvsync.Delete {
+				delete(remote, c.Key)
+				continue
+			}
+			remote[c.Key] = c.Value
+		}
+		return nil
+	}
+	sync := func(in, prefer string, nonInteractive bool) (kvsync.Result, string, error) {
+		var out bytes.Buffer
+		r, err := kvsync.Sync(kvsync.Options{
+			File:           file,
+			Target:         kvsync.Target("123", "456"),
+			Remote:         remote,
+			Apply:          apply,
+			Prefer:         prefer,
+			NonInteractive: nonInteractive,
+			In:             strings.NewReader(in),
+			Out:            &out,
+		})
+		return r, out.String(), err
+	}
+
+	// The first sync has no known state, so differing values are conflicts.
+	write(`{"a": "1", "b": "local", "c": "3"}`)
+	r, _, err := sync("", kvsync.PreferLocal, false)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, kvsync.Result{Created: 1, Updated: 1}, r)
+	testutil.AssertEqual(t, map[string]string{"a": "1", "b": "local", "c": "3"}, remote)
+
+	// Values changed locally are synced without prompting.
+	write(`{"a": "10", "b": "local", "c": "3"}`)
+	r, _, err = sync("", "", false)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, kvsync.Result{Updated: 1}, r)
+
+	// Hotfixes are prompted for, each prompt reading its own line of input.
+	remote["b"] = "hotfix-b"
+	remote["c"] = "hotfix-c"
+	write(`{"a": "10", "b": "local-b", "c": "local-c"}`)
+	r, out, err := sync("x\nr\nl\n", "", false)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "'b' changed remotely since the last sync")
+	testutil.AssertStringContains(t, out, `remote: "hotfix-b"`)
+	testutil.AssertStringContains(t, out, "enter l, r or s")
+	testutil.AssertEqual(t, []string{"b"}, r.KeptRemote)
+	testutil.AssertEqual(t, 1, r.Updated)
+	testutil.AssertEqual(t, "local-c", remote["c"])
+
+	local, err := kvsync.ReadLocal(file)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, map[string]string{"a": "10", "b": "hotfix-b", "c": "local-c"}, local)
+
+	// Conflicts are skipped when prompts are disabled, and reported again.
+	remote["a"] = "hotfix-a"
+	write(`{"a": "11", "b": "hotfix-b", "c": "local-c"}`)
+	for i := 0; i < 2; i++ {
+		r, _, err = sync("", "", true)
+		testutil.AssertErrorContains(t, err, "1 keys changed remotely since the last sync and weren't synced: a")
+		testutil.AssertEqual(t, []string{"a"}, r.Skipped)
+		testutil.AssertEqual(t, "hotfix-a", remote["a"])
+	}
+}
+
+func TestSyncDelete(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(file, []byte(`{"a": "1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var applied []kvsync.Change
+	r, err := kvsync.Sync(kvsync.Options{
+		File:   file,
+		Remote: map[string]string{"a": "1", "unmanaged": "2"},
+		Apply: func(changes []kvsync.Change) error {
+			applied = changes
+			return nil
+		},
+		Delete: true,
+		In:     strings.NewReader(""),
+		Out:    &bytes.Buffer{},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, kvsync.Result{Deleted: 1}, r)
+	testutil.AssertEqual(t, []kvsync.Change{{Action: kvsync.Delete, Key: "unmanaged"}}, applied)
+}
+
+func TestSyncTargets(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(file, []byte(`{"a": "1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stores := map[string]map[string]string{
+		kvsync.Target("service-a", "dict"): {},
+		kvsync.Target("service-b", "dict"): {},
+	}
+	sync := func(target string) (kvsync.Result, error) {
+		remote := stores[target]
+		return kvsync.Sync(kvsync.Options{
+			File:   file,
+			Target: target,
+			Remote: remote,
+			Apply: func(changes []kvsync.Change) error {
+				for _, c := range changes {
+					remote[c.Key] = c.Value
+				}
+				return nil
+			},
+			NonInteractive: true,
+			In:             strings.NewReader(""),
+			Out:            &bytes.Buffer{},
+		})
+	}
+
+	for target := range stores {
+		r, err := sync(target)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, kvsync.Result{Created: 1}, r)
+	}
+
+	// A hotfix to one store is a conflict for that store only, as the state
+	// of each store is kept separately.
+	stores[kvsync.Target("service-a", "dict")]["a"] = "hotfix"
+	if err := os.WriteFile(file, []byte(`{"a": "2"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := sync(kvsync.Target("service-a", "dict"))
+	testutil.AssertErrorContains(t, err, "changed remotely since the last sync")
+	r, err := sync(kvsync.Target("service-b", "dict"))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, kvsync.Result{Updated: 1}, r)
+
+	for target := range stores {
+		state, err := kvsync.ReadState(file, target)
+		testutil.AssertNoError(t, err)
+		want := kvsync.Hash("1")
+		if target == kvsync.Target("service-b", "dict") {
+			want = kvsync.Hash("2")
+		}
+		testutil.AssertEqual(t, want, state.Hashes["a"])
+	}
+}
@@ -0,0 +1,189 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	toml "github.com/pelletier/go-toml"
+)
+
+// Environment represents an [env.<name>] section of the manifest, whose values
+// override the top-level values when the environment is selected via the
+// global --env flag. This allows a single manifest to describe, for example,
+// the staging and production services of a package.
+//
+// The [setup] and [local_server] entries are overridden by name, so an
+// environment only needs to define the entries that differ.
+type Environment struct {
+	ServiceID   string      `toml:"service_id,omitempty"`
+	Setup       Setup       `toml:"setup,omitempty"`
+	LocalServer LocalServer `toml:"local_server,omitempty"`
+}
+
+// envState tracks the environment applied to the File, so that Write()
+// persists changes made while it's selected to the [env.<name>] section
+// rather than the top-level values.
+type envState struct {
+	name string
+	// base holds the top-level values, before the environment was applied.
+	base Environment
+	// merged holds the values once the environment was applied, so that
+	// changes made afterwards can be detected.
+	merged Environment
+}
+
+// Environment returns the name of the environment applied via ApplyEnv().
+func (f *File) Environment() string {
+	if f.env == nil {
+		return ""
+	}
+	return f.env.name
+}
+
+// HasEnv indicates if the manifest defines an [env.<name>] section.
+func (f *File) HasEnv(name string) bool {
+	_, ok := f.Env[name]
+	return ok
+}
+
+// ApplyEnv overrides the top-level service_id, [setup] and [local_server]
+// values with those of the [env.<name>] section.
+func (f *File) ApplyEnv(name string) error {
+	if !f.HasEnv(name) {
+		names := make([]string, 0, len(f.Env))
+		for n := range f.Env {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		remediation := fmt.Sprintf("Add an [env.%s] section to the %s manifest.", name, Filename)
+		if len(names) > 0 {
+			remediation = fmt.Sprintf("The environments defined in the %s manifest are: %s", Filename, strings.Join(names, ", "))
+		}
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the environment '%s' isn't defined in the %s manifest", name, Filename),
+			Remediation: remediation,
+		}
+	}
+	f.env = &envState{name: name}
+	return f.applyEnv()
+}
+
+// applyEnv merges the selected environment into the top-level values.
+func (f *File) applyEnv() error {
+	base, err := copyEnvironment(Environment{
+		ServiceID:   f.ServiceID,
+		Setup:       f.Setup,
+		LocalServer: f.LocalServer,
+	})
+	if err != nil {
+		return err
+	}
+	merged, err := copyEnvironment(base)
+	if err != nil {
+		return err
+	}
+	override, err := copyEnvironment(*f.Env[f.env.name])
+	if err != nil {
+		return err
+	}
+	merged.merge(override)
+
+	// NOTE: Both copies are made the same way, so that they're only unequal
+	// once the File is changed.
+	if f.env.merged, err = copyEnvironment(merged); err != nil {
+		return err
+	}
+	if merged, err = copyEnvironment(merged); err != nil {
+		return err
+	}
+	f.env.base = base
+	f.ServiceID = merged.ServiceID
+	f.Setup = merged.Setup
+	f.LocalServer = merged.LocalServer
+	return nil
+}
+
+// unapplyEnv returns a copy of the File with the top-level values restored,
+// and any changes made since the environment was applied moved to its
+// [env.<name>] section.
+func (f *File) unapplyEnv() *File {
+	out := *f
+	out.Env = make(map[string]*Environment, len(f.Env))
+	for n, e := range f.Env {
+		out.Env[n] = e
+	}
+
+	e := *f.Env[f.env.name]
+	if f.ServiceID != f.env.merged.ServiceID {
+		e.ServiceID = f.ServiceID
+	}
+	if !reflect.DeepEqual(f.Setup, f.env.merged.Setup) {
+		e.Setup = f.Setup
+	}
+	if !reflect.DeepEqual(f.LocalServer, f.env.merged.LocalServer) {
+		e.LocalServer = f.LocalServer
+	}
+	out.Env[f.env.name] = &e
+
+	out.ServiceID = f.env.base.ServiceID
+	out.Setup = f.env.base.Setup
+	out.LocalServer = f.env.base.LocalServer
+	return &out
+}
+
+// merge overrides the values of e with those set in o.
+func (e *Environment) merge(o Environment) {
+	if o.ServiceID != "" {
+		e.ServiceID = o.ServiceID
+	}
+
+	e.Setup.Backends = mergeMap(e.Setup.Backends, o.Setup.Backends)
+	e.Setup.Dictionaries = mergeMap(e.Setup.Dictionaries, o.Setup.Dictionaries)
+	e.Setup.Loggers = mergeMap(e.Setup.Loggers, o.Setup.Loggers)
+	e.Setup.ObjectStores = mergeMap(e.Setup.ObjectStores, o.Setup.ObjectStores)
+	e.Setup.SecretStores = mergeMap(e.Setup.SecretStores, o.Setup.SecretStores)
+
+	e.LocalServer.Backends = mergeMap(e.LocalServer.Backends, o.LocalServer.Backends)
+	e.LocalServer.ConfigStores = mergeMap(e.LocalServer.ConfigStores, o.LocalServer.ConfigStores)
+	e.LocalServer.Dictionaries = mergeMap(e.LocalServer.Dictionaries, o.LocalServer.Dictionaries)
+	e.LocalServer.KVStores = mergeMap(e.LocalServer.KVStores, o.LocalServer.KVStores)
+	e.LocalServer.ObjectStores = mergeMap(e.LocalServer.ObjectStores, o.LocalServer.ObjectStores)
+	e.LocalServer.SecretStores = mergeMap(e.LocalServer.SecretStores, o.LocalServer.SecretStores)
+	if o.LocalServer.Geolocation != nil {
+		e.LocalServer.Geolocation = o.LocalServer.Geolocation
+	}
+	if o.LocalServer.ViceroyVersion != "" {
+		e.LocalServer.ViceroyVersion = o.LocalServer.ViceroyVersion
+	}
+}
+
+// mergeMap returns the entries of base, replaced or extended by those of
+// override.
+func mergeMap[V any](base, override map[string]V) map[string]V {
+	if len(override) == 0 {
+		return base
+	}
+	if base == nil {
+		base = make(map[string]V, len(override))
+	}
+	for k, v := range override {
+		base[k] = v
+	}
+	return base
+}
+
+// copyEnvironment returns a deep copy of e.
+func copyEnvironment(e Environment) (Environment, error) {
+	var c Environment
+	data, err := toml.Marshal(e)
+	if err != nil {
+		return c, fmt.Errorf("error copying manifest environment: %w", err)
+	}
+	if err := toml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("error copying manifest environment: %w", err)
+	}
+	return c, nil
+}
@@ -186,18 +186,22 @@ func (v *Version) UnmarshalText(txt []byte) error {
 // File represents all of the configuration parameters in the fastly.toml
 // manifest file schema.
 type File struct {
-	Authors         []string    `toml:"authors"`
-	Description     string      `toml:"description"`
-	Language        string      `toml:"language"`
-	Profile         string      `toml:"profile,omitempty"`
-	LocalServer     LocalServer `toml:"local_server,omitempty"`
-	ManifestVersion Version     `toml:"manifest_version"`
-	Name            string      `toml:"name"`
-	Scripts         Scripts     `toml:"scripts,omitempty"`
-	ServiceID       string      `toml:"service_id"`
-	Setup           Setup       `toml:"setup,omitempty"`
+	Authors         []string                `toml:"authors"`
+	BuildInfo       *BuildInfo              `toml:"build_info,omitempty"`
+	Description     string                  `toml:"description"`
+	Env             map[string]*Environment `toml:"env,omitempty"`
+	Language        string                  `toml:"language"`
+	Profile         string                  `toml:"profile,omitempty"`
+	LocalServer     LocalServer             `toml:"local_server,omitempty"`
+	ManifestVersion Version                 `toml:"manifest_version"`
+	Name            string                  `toml:"name"`
+	Scripts         Scripts                 `toml:"scripts,omitempty"`
+	ServiceGroups   map[string][]string     `toml:"service_groups,omitempty"`
+	ServiceID       string                  `toml:"service_id"`
+	Setup           Setup                   `toml:"setup,omitempty"`
 
 	quiet     bool
+	env       *envState
 	errLog    fsterr.LogInterface
 	exists    bool
 	output    io.Writer
@@ -210,9 +214,31 @@ func (f *File) SetQuiet(v bool) {
 }
 
 // Scripts represents build configuration.
+//
+// The pre_build, pre_deploy and post_deploy hooks are run by `compute build`
+// and `compute deploy` (and the commands which build or deploy), failing the
+// command if the script fails.
 type Scripts struct {
-	Build     string `toml:"build,omitempty"`
-	PostBuild string `toml:"post_build,omitempty"`
+	Build      string `toml:"build,omitempty"`
+	PostBuild  string `toml:"post_build,omitempty"`
+	PostDeploy string `toml:"post_deploy,omitempty"`
+	PreBuild   string `toml:"pre_build,omitempty"`
+	PreDeploy  string `toml:"pre_deploy,omitempty"`
+	Test       string `toml:"test,omitempty"`
+}
+
+// BuildInfo describes how a package was built. It's recorded in the
+// fastly.toml manifest of the package (not the project) by `compute build`.
+type BuildInfo struct {
+	// BuildTime is the SOURCE_DATE_EPOCH of the build (RFC 3339), or the
+	// default package archive time if unset, so builds remain reproducible.
+	BuildTime  string `toml:"build_time" json:"build_time"`
+	CLIVersion string `toml:"cli_version" json:"cli_version"`
+	// GitCommit is the commit of the project's Git repository (if any), with
+	// a "-dirty" suffix if it had uncommitted changes.
+	GitCommit string `toml:"git_commit,omitempty" json:"git_commit,omitempty"`
+	// Toolchain are the versions of the language toolchain's tools.
+	Toolchain map[string]string `toml:"toolchain,omitempty" json:"toolchain,omitempty"`
 }
 
 // Setup represents a set of service configuration that works with the code in
@@ -222,6 +248,7 @@ type Setup struct {
 	Dictionaries map[string]*SetupDictionary  `toml:"dictionaries,omitempty"`
 	Loggers      map[string]*SetupLogger      `toml:"log_endpoints,omitempty"`
 	ObjectStores map[string]*SetupObjectStore `toml:"object_stores,omitempty"`
+	SecretStores map[string]*SetupSecretStore `toml:"secret_stores,omitempty"`
 }
 
 // Defined indicates if there is any [setup] configuration in the manifest.
@@ -280,12 +307,29 @@ type SetupObjectStoreItems struct {
 	Description string `toml:"description,omitempty"`
 }
 
+// SetupSecretStore represents a '[setup.secret_stores.<T>]' instance.
+type SetupSecretStore struct {
+	Entries     map[string]SetupSecretStoreEntry `toml:"entries,omitempty"`
+	Description string                           `toml:"description,omitempty"`
+}
+
+// SetupSecretStoreEntry represents a '[setup.secret_stores.<T>.entries.<K>]'
+// instance.
+type SetupSecretStoreEntry struct {
+	Description string `toml:"description,omitempty"`
+}
+
 // LocalServer represents a list of mocked Viceroy resources.
 type LocalServer struct {
 	Backends     map[string]LocalBackend       `toml:"backends"`
+	ConfigStores map[string]LocalConfigStore   `toml:"config_stores,omitempty"`
 	Dictionaries map[string]LocalDictionary    `toml:"dictionaries,omitempty"`
+	Geolocation  *LocalGeolocation             `toml:"geolocation,omitempty"`
+	KVStores     map[string][]LocalKVStore     `toml:"kv_stores,omitempty"`
 	ObjectStores map[string][]LocalObjectStore `toml:"object_stores,omitempty"`
 	SecretStores map[string][]LocalSecretStore `toml:"secret_stores,omitempty"`
+	// ViceroyVersion pins the Viceroy release used by `compute serve`.
+	ViceroyVersion string `toml:"viceroy_version,omitempty"`
 }
 
 // LocalBackend represents a backend to be mocked by the local testing server.
@@ -294,6 +338,12 @@ type LocalBackend struct {
 	OverrideHost string `toml:"override_host,omitempty"`
 	CertHost     string `toml:"cert_host,omitempty"`
 	UseSNI       bool   `toml:"use_sni,omitempty"`
+	// InsecureSkipVerify disables verification of an HTTPS backend's
+	// certificate (e.g. a self-signed certificate of a local dev server).
+	InsecureSkipVerify bool `toml:"insecure_skip_verify,omitempty"`
+	// CACertificateFile is a PEM file of the CA(s) used to verify an HTTPS
+	// backend's certificate, relative to the manifest file.
+	CACertificateFile string `toml:"ca_certificate_file,omitempty"`
 }
 
 // LocalDictionary represents a dictionary to be mocked by the local testing server.
@@ -303,6 +353,36 @@ type LocalDictionary struct {
 	Contents map[string]string `toml:"contents,omitempty"`
 }
 
+// LocalGeolocation represents the geolocation data returned by the local
+// testing server for client IP addresses.
+type LocalGeolocation struct {
+	// Addresses maps an IP address to its geolocation fields (e.g.
+	// country_code), used with the "inline-toml" format.
+	Addresses map[string]map[string]any `toml:"addresses,omitempty"`
+	// File is a JSON file of addresses, used with the "json" format.
+	File   string `toml:"file,omitempty"`
+	Format string `toml:"format,omitempty"`
+	// UseDefaultLoopback returns Viceroy's built-in data for loopback
+	// addresses without an entry.
+	UseDefaultLoopback *bool `toml:"use_default_loopback,omitempty"`
+}
+
+// LocalConfigStore represents a config_store to be mocked by the local testing
+// server. The format is either "json" (File) or "inline-toml" (Contents), and
+// defaults to whichever of the two is set.
+type LocalConfigStore struct {
+	File     string            `toml:"file,omitempty"`
+	Format   string            `toml:"format,omitempty"`
+	Contents map[string]string `toml:"contents,omitempty"`
+}
+
+// LocalKVStore represents a kv_store to be mocked by the local testing server.
+type LocalKVStore struct {
+	Key  string `toml:"key"`
+	File string `toml:"file,omitempty"`
+	Data string `toml:"data,omitempty"`
+}
+
 // LocalObjectStore represents an object_store to be mocked by the local testing server.
 type LocalObjectStore struct {
 	Key  string `toml:"key"`
@@ -315,6 +395,8 @@ type LocalSecretStore struct {
 	Key  string `toml:"key"`
 	File string `toml:"file,omitempty"`
 	Data string `toml:"data,omitempty"`
+	// Env is an environment variable the secret is read from by `compute serve`.
+	Env string `toml:"env,omitempty"`
 }
 
 // Exists yields whether the manifest exists.
@@ -456,6 +538,12 @@ func (f *File) Load(data []byte) error {
 	if err != nil {
 		return fmt.Errorf("error unmarshaling fastly.toml: %w", err)
 	}
+	// The data holds the top-level values, so the environment is re-applied.
+	if f.env != nil {
+		if err := f.applyEnv(); err != nil {
+			return err
+		}
+	}
 	return f.Write(Filename)
 }
 
@@ -539,6 +627,10 @@ func (f *File) Read(path string) (err error) {
 }
 
 // Write persists the manifest content to disk.
+//
+// NOTE: If an environment was applied (see ApplyEnv), the top-level values are
+// written unchanged and any changes made to them are written to the
+// environment's section instead.
 func (f *File) Write(path string) error {
 	// gosec flagged this:
 	// G304 (CWE-22): Potential file inclusion via variable
@@ -556,7 +648,11 @@ func (f *File) Write(path string) error {
 		return err
 	}
 
-	if err := toml.NewEncoder(fp).Encode(f); err != nil {
+	v := f
+	if f.env != nil {
+		v = f.unapplyEnv()
+	}
+	if err := toml.NewEncoder(fp).Encode(v); err != nil {
 		return err
 	}
 
@@ -301,3 +301,98 @@ func TestManifestPersistsLocalServerSection(t *testing.T) {
 		t.Fatalf("testing section between original and updated fastly.toml do not match (-want +got):\n%s", diff)
 	}
 }
+
+func TestManifestEnv(t *testing.T) {
+	data := `manifest_version = 2
+name = "envs"
+service_id = "default-id"
+
+[setup.backends.origin]
+address = "dev.example.com"
+
+[setup.backends.static]
+address = "static.example.com"
+
+[local_server.backends.origin]
+url = "http://127.0.0.1:8080"
+
+[env.staging]
+service_id = "staging-id"
+
+[env.staging.setup.backends.origin]
+address = "staging.example.com"
+
+[env.staging.local_server.backends.origin]
+url = "http://127.0.0.1:9090"
+
+[env.production.setup.backends.origin]
+address = "www.example.com"
+`
+	fpath := filepath.Join(t.TempDir(), manifest.Filename)
+	if err := os.WriteFile(fpath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("unknown environment", func(t *testing.T) {
+		var m manifest.File
+		if err := m.Read(fpath); err != nil {
+			t.Fatal(err)
+		}
+		err := m.ApplyEnv("qa")
+		testutil.AssertErrorContains(t, err, "the environment 'qa' isn't defined in the fastly.toml manifest")
+		testutil.AssertRemediationErrorContains(t, err, "production, staging")
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		var m manifest.File
+		if err := m.Read(fpath); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.ApplyEnv("staging"); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, "staging", m.Environment())
+		testutil.AssertEqual(t, "staging-id", m.ServiceID)
+		testutil.AssertEqual(t, "staging.example.com", m.Setup.Backends["origin"].Address)
+		testutil.AssertEqual(t, "static.example.com", m.Setup.Backends["static"].Address)
+		testutil.AssertEqual(t, "http://127.0.0.1:9090", m.LocalServer.Backends["origin"].URL)
+	})
+
+	t.Run("service_id inherited", func(t *testing.T) {
+		var m manifest.File
+		if err := m.Read(fpath); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.ApplyEnv("production"); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, "default-id", m.ServiceID)
+		testutil.AssertEqual(t, "www.example.com", m.Setup.Backends["origin"].Address)
+		testutil.AssertEqual(t, "http://127.0.0.1:8080", m.LocalServer.Backends["origin"].URL)
+	})
+
+	t.Run("write persists changes to the environment", func(t *testing.T) {
+		var m manifest.File
+		if err := m.Read(fpath); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.ApplyEnv("production"); err != nil {
+			t.Fatal(err)
+		}
+		m.ServiceID = "production-id"
+		if err := m.Write(fpath); err != nil {
+			t.Fatal(err)
+		}
+
+		var written manifest.File
+		if err := written.Read(fpath); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, "default-id", written.ServiceID)
+		testutil.AssertEqual(t, "dev.example.com", written.Setup.Backends["origin"].Address)
+		testutil.AssertEqual(t, "production-id", written.Env["production"].ServiceID)
+		testutil.AssertEqual(t, "www.example.com", written.Env["production"].Setup.Backends["origin"].Address)
+		testutil.AssertEqual(t, "staging-id", written.Env["staging"].ServiceID)
+		testutil.AssertEqual(t, 0, len(written.Env["production"].LocalServer.Backends))
+	})
+}
@@ -21,6 +21,7 @@ type API struct {
 	DeleteServiceFn     func(*fastly.DeleteServiceInput) error
 	SearchServiceFn     func(*fastly.SearchServiceInput) (*fastly.Service, error)
 
+	CreateVersionFn     func(*fastly.CreateVersionInput) (*fastly.Version, error)
 	CloneVersionFn      func(*fastly.CloneVersionInput) (*fastly.Version, error)
 	ListVersionsFn      func(*fastly.ListVersionsInput) ([]*fastly.Version, error)
 	GetVersionFn        func(*fastly.GetVersionInput) (*fastly.Version, error)
@@ -237,6 +238,22 @@ type API struct {
 	UpdateDynamicSnippetFn func(i *fastly.UpdateDynamicSnippetInput) (*fastly.DynamicSnippet, error)
 	DeleteSnippetFn        func(i *fastly.DeleteSnippetInput) error
 
+	CreateHeaderFn func(i *fastly.CreateHeaderInput) (*fastly.Header, error)
+	ListHeadersFn  func(i *fastly.ListHeadersInput) ([]*fastly.Header, error)
+
+	CreateConditionFn func(i *fastly.CreateConditionInput) (*fastly.Condition, error)
+	ListConditionsFn  func(i *fastly.ListConditionsInput) ([]*fastly.Condition, error)
+
+	ListCacheSettingsFn   func(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error)
+	ListGzipsFn           func(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error)
+	ListRequestSettingsFn func(i *fastly.ListRequestSettingsInput) ([]*fastly.RequestSetting, error)
+	ListResponseObjectsFn func(i *fastly.ListResponseObjectsInput) ([]*fastly.ResponseObject, error)
+
+	GetSettingsFn    func(i *fastly.GetSettingsInput) (*fastly.Settings, error)
+	UpdateSettingsFn func(i *fastly.UpdateSettingsInput) (*fastly.Settings, error)
+
+	GetAPIEventsFn func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error)
+
 	PurgeFn     func(i *fastly.PurgeInput) (*fastly.Purge, error)
 	PurgeKeyFn  func(i *fastly.PurgeKeyInput) (*fastly.Purge, error)
 	PurgeKeysFn func(i *fastly.PurgeKeysInput) (map[string]string, error)
@@ -407,6 +424,11 @@ func (m API) DeleteService(i *fastly.DeleteServiceInput) error {
 	return m.DeleteServiceFn(i)
 }
 
+// CreateVersion implements Interface.
+func (m API) CreateVersion(i *fastly.CreateVersionInput) (*fastly.Version, error) {
+	return m.CreateVersionFn(i)
+}
+
 // CloneVersion implements Interface.
 func (m API) CloneVersion(i *fastly.CloneVersionInput) (*fastly.Version, error) {
 	return m.CloneVersionFn(i)
@@ -1302,6 +1324,61 @@ func (m API) DeleteSnippet(i *fastly.DeleteSnippetInput) error {
 	return m.DeleteSnippetFn(i)
 }
 
+// CreateHeader implements Interface.
+func (m API) CreateHeader(i *fastly.CreateHeaderInput) (*fastly.Header, error) {
+	return m.CreateHeaderFn(i)
+}
+
+// ListHeaders implements Interface.
+func (m API) ListHeaders(i *fastly.ListHeadersInput) ([]*fastly.Header, error) {
+	return m.ListHeadersFn(i)
+}
+
+// CreateCondition implements Interface.
+func (m API) CreateCondition(i *fastly.CreateConditionInput) (*fastly.Condition, error) {
+	return m.CreateConditionFn(i)
+}
+
+// ListConditions implements Interface.
+func (m API) ListConditions(i *fastly.ListConditionsInput) ([]*fastly.Condition, error) {
+	return m.ListConditionsFn(i)
+}
+
+// ListCacheSettings implements Interface.
+func (m API) ListCacheSettings(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error) {
+	return m.ListCacheSettingsFn(i)
+}
+
+// ListGzips implements Interface.
+func (m API) ListGzips(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error) {
+	return m.ListGzipsFn(i)
+}
+
+// ListRequestSettings implements Interface.
+func (m API) ListRequestSettings(i *fastly.ListRequestSettingsInput) ([]*fastly.RequestSetting, error) {
+	return m.ListRequestSettingsFn(i)
+}
+
+// ListResponseObjects implements Interface.
+func (m API) ListResponseObjects(i *fastly.ListResponseObjectsInput) ([]*fastly.ResponseObject, error) {
+	return m.ListResponseObjectsFn(i)
+}
+
+// GetSettings implements Interface.
+func (m API) GetSettings(i *fastly.GetSettingsInput) (*fastly.Settings, error) {
+	return m.GetSettingsFn(i)
+}
+
+// UpdateSettings implements Interface.
+func (m API) UpdateSettings(i *fastly.UpdateSettingsInput) (*fastly.Settings, error) {
+	return m.UpdateSettingsFn(i)
+}
+
+// GetAPIEvents implements Interface.
+func (m API) GetAPIEvents(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+	return m.GetAPIEventsFn(i)
+}
+
 // Purge implements Interface.
 func (m API) Purge(i *fastly.PurgeInput) (*fastly.Purge, error) {
 	return m.PurgeFn(i)
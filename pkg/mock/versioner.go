@@ -23,6 +23,11 @@ func (av AssetVersioner) Download() (string, error) {
 	return "", fmt.Errorf("not implemented")
 }
 
+// DownloadVersion implements github.Versioner interface.
+func (av AssetVersioner) DownloadVersion(_ string) (string, error) {
+	return av.Download()
+}
+
 // URL implements github.Versioner interface.
 func (av AssetVersioner) URL() (string, error) {
 	return "", nil
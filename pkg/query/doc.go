@@ -0,0 +1,4 @@
+// Package query implements a subset of JMESPath for filtering the JSON output
+// of commands (see the global --query flag). The subset is listed on Query, and
+// other JMESPath syntax is rejected with an error naming it.
+package query
@@ -0,0 +1,590 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query is a compiled JMESPath-like expression.
+//
+// The supported subset of JMESPath is:
+//
+//   - fields: foo.bar, "quoted-field"
+//   - indexes: [0], [-1]
+//   - projections: [*], [] (flatten), * (object values)
+//   - filters: [?Active], [?Name=='foo'], [?Number > `2`]
+//   - pipes: [?Active] | [0]
+//
+// Other JMESPath syntax (e.g. slices, multi-selects, functions, and the &&,
+// || and ! operators) is rejected by Compile with an error naming it, rather
+// than being parsed as something else.
+type Query struct {
+	expr  string
+	pipes [][]op
+}
+
+// opKind identifies a step of an expression.
+type opKind int
+
+const (
+	opField opKind = iota
+	opIndex
+	opProjectList
+	opFlatten
+	opProjectObject
+	opFilter
+)
+
+// op is a single step of an expression.
+type op struct {
+	kind  opKind
+	field string
+	index int
+	cond  *condition
+}
+
+// condition is the predicate of a filter.
+type condition struct {
+	lhs      []op
+	operator string
+	rhs      any
+}
+
+// Compile parses the expression.
+func Compile(expr string) (*Query, error) {
+	q := &Query{expr: expr}
+	for _, part := range splitPipes(expr) {
+		p := &parser{s: strings.TrimSpace(part)}
+		if p.s == "" {
+			return nil, fmt.Errorf("invalid query '%s': empty expression", expr)
+		}
+		ops, err := p.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid query '%s': %w", expr, err)
+		}
+		q.pipes = append(q.pipes, ops)
+	}
+	return q, nil
+}
+
+// String returns the expression.
+func (q *Query) String() string {
+	return q.expr
+}
+
+// Search evaluates the query against the decoded JSON value.
+func (q *Query) Search(v any) any {
+	for _, ops := range q.pipes {
+		v = eval(ops, v)
+	}
+	return v
+}
+
+// Apply evaluates the query against the JSON document read from r, writing the
+// result to w. Strings are written without quotes (for use in scripts), and
+// null results are omitted.
+func (q *Query) Apply(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("the output isn't JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("the output isn't a single JSON document")
+	}
+
+	switch result := q.Search(v).(type) {
+	case nil:
+		return nil
+	case string:
+		_, err := fmt.Fprintln(w, result)
+		return err
+	default:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+}
+
+// eval applies the ops to v. Projections apply the remaining ops to each
+// element, omitting null results.
+func eval(ops []op, v any) any {
+	if len(ops) == 0 || v == nil {
+		return v
+	}
+	o, rest := ops[0], ops[1:]
+
+	switch o.kind {
+	case opField:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		return eval(rest, m[o.field])
+	case opIndex:
+		a, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		i := o.index
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil
+		}
+		return eval(rest, a[i])
+	case opProjectObject:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]any, 0, len(keys))
+		for _, k := range keys {
+			values = append(values, m[k])
+		}
+		return project(rest, values)
+	}
+
+	a, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	switch o.kind {
+	case opFlatten:
+		var flat []any
+		for _, e := range a {
+			if sub, ok := e.([]any); ok {
+				flat = append(flat, sub...)
+				continue
+			}
+			flat = append(flat, e)
+		}
+		return project(rest, flat)
+	case opFilter:
+		var matched []any
+		for _, e := range a {
+			if o.cond.match(e) {
+				matched = append(matched, e)
+			}
+		}
+		return project(rest, matched)
+	default:
+		return project(rest, a)
+	}
+}
+
+// project applies the ops to each element, omitting null results. As in
+// JMESPath, a flatten ends the projection and applies to its results.
+func project(ops []op, a []any) any {
+	for i, o := range ops {
+		if o.kind == opFlatten {
+			return eval(ops[i:], project(ops[:i], a))
+		}
+	}
+	results := []any{}
+	for _, e := range a {
+		if r := eval(ops, e); r != nil {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// match reports whether the condition holds for v.
+func (c *condition) match(v any) bool {
+	lhs := eval(c.lhs, v)
+	switch c.operator {
+	case "":
+		return truthy(lhs)
+	case "==":
+		return equal(lhs, c.rhs)
+	case "!=":
+		return !equal(lhs, c.rhs)
+	}
+
+	l, lok := number(lhs)
+	r, rok := number(c.rhs)
+	if !lok || !rok {
+		return false
+	}
+	switch c.operator {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+// truthy reports whether v is truthy, as defined by JMESPath.
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	case map[string]any:
+		return len(t) > 0
+	}
+	return true
+}
+
+// equal reports whether the JSON values are equal, comparing numbers by value.
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// number returns v as a float64 if it's a JSON number.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// splitPipes splits the expression on the pipes outside of quotes.
+//
+// NOTE: An || isn't split on, so the parser can report it as unsupported.
+func splitPipes(expr string) []string {
+	var (
+		parts []string
+		quote byte
+		start int
+	)
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.HasPrefix(expr[i:], "||"):
+			i++
+		case c == '|':
+			parts = append(parts, expr[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+// parser parses a pipe-free expression.
+type parser struct {
+	s   string
+	pos int
+}
+
+// parse parses the whole expression.
+func (p *parser) parse() ([]op, error) {
+	ops, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.unexpected()
+	}
+	return ops, nil
+}
+
+// path parses a sequence of fields, brackets and wildcards, stopping at the
+// first character that can't continue the path.
+func (p *parser) path() ([]op, error) {
+	var ops []op
+	first := true
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			break
+		}
+		c := p.s[p.pos]
+		switch {
+		case c == '[':
+			o, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, o)
+		case c == '.' && !first:
+			p.pos++
+			p.skipSpace()
+			if p.pos >= len(p.s) {
+				return nil, fmt.Errorf("expected a field at position %d", p.pos+1)
+			}
+			if p.s[p.pos] == '[' {
+				return nil, p.unsupported("multi-select lists")
+			}
+			o, err := p.field()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, o)
+		case first && (c == '*' || c == '"' || isIdentStart(c)):
+			o, err := p.field()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, o)
+		default:
+			if first {
+				return nil, p.unexpected()
+			}
+			return ops, nil
+		}
+		first = false
+	}
+	return ops, nil
+}
+
+// field parses an identifier, quoted identifier or object wildcard.
+func (p *parser) field() (op, error) {
+	c := p.s[p.pos]
+	switch {
+	case c == '*':
+		p.pos++
+		return op{kind: opProjectObject}, nil
+	case c == '"':
+		end := p.closing('"')
+		if end < 0 {
+			return op{}, fmt.Errorf("unterminated quoted field at position %d", p.pos+1)
+		}
+		var name string
+		if err := json.Unmarshal([]byte(p.s[p.pos:end+1]), &name); err != nil {
+			return op{}, fmt.Errorf("invalid quoted field at position %d: %w", p.pos+1, err)
+		}
+		p.pos = end + 1
+		return op{kind: opField, field: name}, nil
+	case isIdentStart(c):
+		start := p.pos
+		for p.pos < len(p.s) && isIdent(p.s[p.pos]) {
+			p.pos++
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '(' {
+			p.pos = start
+			return op{}, p.unsupported("functions")
+		}
+		return op{kind: opField, field: p.s[start:p.pos]}, nil
+	}
+	return op{}, p.unexpected()
+}
+
+// bracket parses an index, list projection, flatten or filter.
+func (p *parser) bracket() (op, error) {
+	start := p.pos
+	p.pos++ // [
+	p.skipSpace()
+
+	var o op
+	switch {
+	case strings.HasPrefix(p.s[p.pos:], "]"):
+		o = op{kind: opFlatten}
+	case strings.HasPrefix(p.s[p.pos:], "*"):
+		p.pos++
+		o = op{kind: opProjectList}
+	case strings.HasPrefix(p.s[p.pos:], "?"):
+		p.pos++
+		cond, err := p.condition()
+		if err != nil {
+			return op{}, err
+		}
+		o = op{kind: opFilter, cond: cond}
+	default:
+		end := p.pos
+		for end < len(p.s) && (p.s[end] == '-' || (p.s[end] >= '0' && p.s[end] <= '9')) {
+			end++
+		}
+		if end < len(p.s) && p.s[end] == ':' {
+			return op{}, p.unsupported("slices")
+		}
+		if end == p.pos && p.pos < len(p.s) && p.s[p.pos] != ']' {
+			return op{}, p.unsupported("multi-select lists")
+		}
+		i, err := strconv.Atoi(p.s[p.pos:end])
+		if err != nil {
+			return op{}, fmt.Errorf("invalid index at position %d", p.pos+1)
+		}
+		p.pos = end
+		o = op{kind: opIndex, index: i}
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] != ']' {
+		if name := unsupportedSyntax(p.s[p.pos:]); name != "" {
+			return op{}, p.unsupported(name)
+		}
+	}
+	if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+		return op{}, fmt.Errorf("unterminated '[' at position %d", start+1)
+	}
+	p.pos++
+	return o, nil
+}
+
+// condition parses the predicate of a filter.
+func (p *parser) condition() (*condition, error) {
+	p.skipSpace()
+	lhs, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	c := &condition{lhs: lhs}
+
+	p.skipSpace()
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.s[p.pos:], operator) {
+			c.operator = operator
+			p.pos += len(operator)
+			break
+		}
+	}
+	if c.operator == "" {
+		return c, nil
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, errors.New("expected a literal after the comparison")
+	}
+	switch quote := p.s[p.pos]; quote {
+	case '\'':
+		end := p.closing(quote)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string at position %d", p.pos+1)
+		}
+		c.rhs = p.s[p.pos+1 : end]
+		p.pos = end + 1
+	case '`':
+		end := p.closing(quote)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated literal at position %d", p.pos+1)
+		}
+		dec := json.NewDecoder(strings.NewReader(p.s[p.pos+1 : end]))
+		dec.UseNumber()
+		if err := dec.Decode(&c.rhs); err != nil {
+			return nil, fmt.Errorf("invalid literal at position %d: %w", p.pos+1, err)
+		}
+		p.pos = end + 1
+	case '"', '@':
+		return nil, p.unsupported("comparisons with fields")
+	default:
+		if isIdentStart(quote) {
+			return nil, p.unsupported("comparisons with fields")
+		}
+		return nil, fmt.Errorf("expected a 'string' or `literal` at position %d", p.pos+1)
+	}
+	return c, nil
+}
+
+// syntax is the JMESPath syntax outside the supported subset, keyed by the
+// token it starts with.
+//
+// NOTE: The tokens are ordered so that && is matched before &.
+var syntax = []struct {
+	token string
+	name  string
+}{
+	{"||", "or expressions (||)"},
+	{"&&", "and expressions (&&)"},
+	{"&", "expression references (&)"},
+	{"!", "not expressions (!)"},
+	{"@", "the current node (@)"},
+	{"{", "multi-select hashes"},
+	{"(", "parenthesized expressions"},
+	{"`", "literals outside of filters"},
+	{"'", "raw strings outside of filters"},
+}
+
+// unsupportedSyntax returns the name of the unsupported JMESPath syntax s
+// starts with, if any.
+func unsupportedSyntax(s string) string {
+	for _, u := range syntax {
+		if strings.HasPrefix(s, u.token) {
+			return u.name
+		}
+	}
+	return ""
+}
+
+// unsupported returns the error for the unsupported JMESPath syntax at p.pos.
+func (p *parser) unsupported(name string) error {
+	return fmt.Errorf("unsupported JMESPath syntax at position %d: %s", p.pos+1, name)
+}
+
+// unexpected returns the error for the text at p.pos, which can't continue the
+// expression.
+func (p *parser) unexpected() error {
+	if name := unsupportedSyntax(p.s[p.pos:]); name != "" {
+		return p.unsupported(name)
+	}
+	return fmt.Errorf("unexpected '%s' at position %d", p.s[p.pos:], p.pos+1)
+}
+
+// closing returns the position of the quote closing the one at p.pos.
+func (p *parser) closing(quote byte) int {
+	for i := p.pos + 1; i < len(p.s); i++ {
+		switch p.s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// skipSpace advances past any whitespace.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// isIdentStart reports whether c can start an unquoted field.
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdent reports whether c can continue an unquoted field.
+func isIdent(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,143 @@
+package query_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/query"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const versions = `[
+  {"Number": 1, "Active": false, "Comment": "", "Tags": ["a", "b"], "Service": {"ID": "123", "Name": "foo"}},
+  {"Number": 2, "Active": true, "Comment": "live", "Tags": ["c"], "Service": {"ID": "123", "Name": "foo"}},
+  {"Number": 3, "Active": false, "Comment": "draft", "Tags": [], "Service": {"ID": "123", "Name": "foo"}}
+]`
+
+func TestApply(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		expr       string
+		input      string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			name:       "field",
+			expr:       "[0].Service.Name",
+			wantOutput: "foo\n",
+		},
+		{
+			name:       "quoted field",
+			expr:       `[0]."Service".ID`,
+			wantOutput: "123\n",
+		},
+		{
+			name:       "negative index",
+			expr:       "[-1].Number",
+			wantOutput: "3\n",
+		},
+		{
+			name:       "index out of range",
+			expr:       "[5].Number",
+			wantOutput: "",
+		},
+		{
+			name:       "list projection",
+			expr:       "[*].Number",
+			wantOutput: "[\n  1,\n  2,\n  3\n]\n",
+		},
+		{
+			name:       "flatten",
+			expr:       "[].Tags[]",
+			wantOutput: "[\n  \"a\",\n  \"b\",\n  \"c\"\n]\n",
+		},
+		{
+			name:       "object projection",
+			expr:       "[0].Service.*",
+			wantOutput: "[\n  \"123\",\n  \"foo\"\n]\n",
+		},
+		{
+			name:       "truthy filter",
+			expr:       "[?Comment].Number",
+			wantOutput: "[\n  2,\n  3\n]\n",
+		},
+		{
+			name:       "string comparison",
+			expr:       "[?Comment == 'live'].Number",
+			wantOutput: "[\n  2\n]\n",
+		},
+		{
+			name:       "number comparison",
+			expr:       "[?Number >= `2`].Comment",
+			wantOutput: "[\n  \"live\",\n  \"draft\"\n]\n",
+		},
+		{
+			name:       "pipe",
+			expr:       "[?Active == `false`] | [-1].Comment",
+			wantOutput: "draft\n",
+		},
+		{
+			name:      "validate input isn't JSON",
+			expr:      "Number",
+			input:     "NUMBER  ACTIVE\n1       true\n",
+			wantError: "the output isn't JSON",
+		},
+		{
+			name:      "validate input has several documents",
+			expr:      "Number",
+			input:     `{"Number": 1}{"Number": 2}`,
+			wantError: "the output isn't a single JSON document",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			q, err := query.Compile(testcase.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input := testcase.input
+			if input == "" {
+				input = versions
+			}
+			var out bytes.Buffer
+			err = q.Apply(strings.NewReader(input), &out)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.wantOutput, out.String())
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	for _, testcase := range []struct {
+		expr      string
+		wantError string
+	}{
+		{expr: "", wantError: "empty expression"},
+		{expr: "Number |", wantError: "empty expression"},
+		{expr: "[0", wantError: "unterminated '['"},
+		{expr: "[-]", wantError: "invalid index"},
+		{expr: "[?Number > 2]", wantError: "expected a 'string' or `literal`"},
+		{expr: "[?Number > `two`]", wantError: "invalid literal"},
+		{expr: "[?Name == 'foo]", wantError: "unterminated string"},
+		{expr: "[0:2]", wantError: "unsupported JMESPath syntax at position 2: slices"},
+		{expr: "[::-1]", wantError: "unsupported JMESPath syntax at position 2: slices"},
+		{expr: "[Number, Comment]", wantError: "unsupported JMESPath syntax at position 2: multi-select lists"},
+		{expr: "[*].[Number, Comment]", wantError: "unsupported JMESPath syntax at position 5: multi-select lists"},
+		{expr: "[*].{N: Number}", wantError: "unsupported JMESPath syntax at position 5: multi-select hashes"},
+		{expr: "length(@)", wantError: "unsupported JMESPath syntax at position 1: functions"},
+		{expr: "[?Active && Comment]", wantError: "unsupported JMESPath syntax at position 10: and expressions (&&)"},
+		{expr: "[?Active || Comment]", wantError: "unsupported JMESPath syntax at position 10: or expressions (||)"},
+		{expr: "Number || Comment", wantError: "unsupported JMESPath syntax at position 8: or expressions (||)"},
+		{expr: "[?!Active]", wantError: "unsupported JMESPath syntax at position 3: not expressions (!)"},
+		{expr: "[?@ == `1`]", wantError: "unsupported JMESPath syntax at position 3: the current node (@)"},
+		{expr: "[?Number == Other]", wantError: "unsupported JMESPath syntax at position 13: comparisons with fields"},
+		{expr: "sort_by(@, &Number)", wantError: "unsupported JMESPath syntax at position 1: functions"},
+	} {
+		t.Run(testcase.expr, func(t *testing.T) {
+			_, err := query.Compile(testcase.expr)
+			testutil.AssertErrorContains(t, err, "invalid query '"+testcase.expr+"'")
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
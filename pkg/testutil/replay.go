@@ -0,0 +1,35 @@
+package testutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/go-fastly/v7/fastly"
+)
+
+// ReplayAPIClient returns an API client factory whose client serves responses
+// from a session recorded with the global --record-api flag. This allows a
+// failing sequence of API interactions shared by a user to be turned into a
+// regression test.
+//
+// The test fails if the session file can't be read.
+func ReplayAPIClient(t *testing.T, path string) app.APIClientFactory {
+	t.Helper()
+
+	s, err := api.ReadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := api.NewReplayer(s)
+
+	return func(token, endpoint string) (api.Interface, error) {
+		client, err := fastly.NewClientForEndpoint(token, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		client.HTTPClient = &http.Client{Transport: replayer}
+		return client, nil
+	}
+}
@@ -0,0 +1,83 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CI is a continuous integration system whose log syntax can be emitted by the
+// CLI (see the global --output-ci flag).
+type CI string
+
+const (
+	// CIGitHub is GitHub Actions.
+	CIGitHub CI = "github"
+	// CIGitLab is GitLab CI/CD.
+	CIGitLab CI = "gitlab"
+)
+
+// DetectCI returns the CI system identified by the given environment
+// variables, or an empty CI if the CLI isn't running in a supported system.
+func DetectCI(state map[string]string) CI {
+	switch {
+	case state["GITHUB_ACTIONS"] == "true":
+		return CIGitHub
+	case state["GITLAB_CI"] == "true":
+		return CIGitLab
+	}
+	return ""
+}
+
+// CIGroupStart writes the marker that opens a foldable section of log output.
+// The name identifies the section and must be closed by CIGroupEnd.
+func CIGroupStart(w io.Writer, ci CI, name, title string) {
+	switch ci {
+	case CIGitHub:
+		fmt.Fprintf(w, "::group::%s\n", title)
+	case CIGitLab:
+		fmt.Fprintf(w, "\x1b[0Ksection_start:%d:%s[collapsed=true]\r\x1b[0K%s\n", time.Now().Unix(), name, title)
+	}
+}
+
+// CIGroupEnd writes the marker that closes a foldable section of log output.
+func CIGroupEnd(w io.Writer, ci CI, name string) {
+	switch ci {
+	case CIGitHub:
+		fmt.Fprintln(w, "::endgroup::")
+	case CIGitLab:
+		fmt.Fprintf(w, "\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), name)
+	}
+}
+
+// CIError writes an error annotation for the given file (which may be empty).
+//
+// NOTE: GitLab has no annotation syntax, so the error is written as a
+// highlighted log line instead.
+func CIError(w io.Writer, ci CI, file, msg string) {
+	switch ci {
+	case CIGitHub:
+		var props string
+		if file != "" {
+			props = " file=" + escapeGitHubProperty(file)
+		}
+		fmt.Fprintf(w, "::error%s::%s\n", props, escapeGitHubData(msg))
+	case CIGitLab:
+		if file != "" {
+			msg = file + ": " + msg
+		}
+		fmt.Fprintf(w, "%s %s\n", BoldRed("ERROR:"), msg)
+	}
+}
+
+// escapeGitHubData escapes the message of a GitHub Actions workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a GitHub Actions workflow
+// command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,74 @@
+package text_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestDetectCI(t *testing.T) {
+	testutil.AssertEqual(t, text.CIGitHub, text.DetectCI(map[string]string{"GITHUB_ACTIONS": "true"}))
+	testutil.AssertEqual(t, text.CIGitLab, text.DetectCI(map[string]string{"GITLAB_CI": "true"}))
+	testutil.AssertEqual(t, text.CI(""), text.DetectCI(map[string]string{"CI": "true"}))
+}
+
+func TestCIError(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		ci         text.CI
+		file       string
+		msg        string
+		wantOutput string
+	}{
+		{
+			name:       "github",
+			ci:         text.CIGitHub,
+			msg:        "build failed",
+			wantOutput: "::error::build failed\n",
+		},
+		{
+			name:       "github with file and escaping",
+			ci:         text.CIGitHub,
+			file:       "a:b,c.toml",
+			msg:        "100% broken\nsee above",
+			wantOutput: "::error file=a%3Ab%2Cc.toml::100%25 broken%0Asee above\n",
+		},
+		{
+			name:       "gitlab",
+			ci:         text.CIGitLab,
+			file:       "fastly.toml",
+			msg:        "build failed",
+			wantOutput: "ERROR: fastly.toml: build failed\n",
+		},
+		{
+			name: "disabled",
+			msg:  "build failed",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			text.CIError(&buf, testcase.ci, testcase.file, testcase.msg)
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
+
+func TestCIGroup(t *testing.T) {
+	var buf bytes.Buffer
+	text.CIGroupStart(&buf, text.CIGitHub, "build", "Build output")
+	text.CIGroupEnd(&buf, text.CIGitHub, "build")
+	testutil.AssertString(t, "::group::Build output\n::endgroup::\n", buf.String())
+
+	buf.Reset()
+	text.CIGroupStart(&buf, text.CIGitLab, "build", "Build output")
+	text.CIGroupEnd(&buf, text.CIGitLab, "build")
+	out := buf.String()
+	for _, want := range []string{"section_start:", ":build[collapsed=true]\r\x1b[0KBuild output\n", "section_end:", ":build\r\x1b[0K\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %q in output %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package text
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The status of a progress event.
+const (
+	ProgressStarted   = "started"
+	ProgressSucceeded = "succeeded"
+	ProgressFailed    = "failed"
+)
+
+// ProgressEvent is a single line of the --progress-json NDJSON stream.
+//
+// Events without a step mark the start and end of a phase (e.g. build), while
+// those with a step mark the start and end of a step within the phase.
+type ProgressEvent struct {
+	Time       time.Time `json:"time"`
+	Phase      string    `json:"phase"`
+	Step       string    `json:"step,omitempty"`
+	Status     string    `json:"status"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Progress writes progress events as NDJSON (see the global --progress-json
+// flag), and passes them to any observers (see the global --profile-cli flag).
+//
+// NOTE: The methods of a nil Progress do nothing, so callers needn't check
+// whether either flag was set.
+type Progress struct {
+	mu        sync.Mutex
+	enc       *json.Encoder
+	observers []func(ProgressEvent)
+}
+
+// NewProgress returns a Progress that writes events to w. If w is nil the
+// events are only passed to the observers.
+func NewProgress(w io.Writer) *Progress {
+	p := &Progress{}
+	if w != nil {
+		p.enc = json.NewEncoder(w)
+	}
+	return p
+}
+
+// Observe calls fn with each event emitted from now on.
+func (p *Progress) Observe(fn func(ProgressEvent)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.observers = append(p.observers, fn)
+}
+
+// Emit writes the event, setting its time if it's unset.
+func (p *Progress) Emit(e ProgressEvent) {
+	if p == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enc != nil {
+		_ = p.enc.Encode(e)
+	}
+	for _, fn := range p.observers {
+		fn(e)
+	}
+}
+
+// Phase emits the start of a phase, and returns a function that emits its end
+// given the phase's error (if any).
+func (p *Progress) Phase(phase string) func(err error) {
+	if p == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	p.Emit(ProgressEvent{Phase: phase, Status: ProgressStarted})
+	return func(err error) {
+		e := ProgressEvent{Phase: phase, Status: ProgressSucceeded, DurationMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			e.Status = ProgressFailed
+			e.Error = err.Error()
+		}
+		p.Emit(e)
+	}
+}
+
+// Spinner returns a spinner that, as well as displaying s, emits the steps of
+// the phase. A step starts with the spinner's first message and ends when the
+// spinner is stopped.
+func (p *Progress) Spinner(s Spinner, phase string) Spinner {
+	if p == nil {
+		return s
+	}
+	return &progressSpinner{Spinner: s, progress: p, phase: phase}
+}
+
+// progressSpinner emits a progress event for each spinner step.
+type progressSpinner struct {
+	Spinner
+	progress *Progress
+	phase    string
+
+	step  string
+	start time.Time
+}
+
+// Message implements the Spinner interface.
+//
+// NOTE: A step's message may be updated (e.g. with a countdown), so only the
+// first message of a step begins it.
+func (s *progressSpinner) Message(message string) {
+	if s.step == "" {
+		s.step = strings.TrimSuffix(strings.TrimSpace(message), "...")
+		s.start = time.Now()
+		s.progress.Emit(ProgressEvent{Phase: s.phase, Step: s.step, Status: ProgressStarted})
+	}
+	s.Spinner.Message(message)
+}
+
+// Stop implements the Spinner interface.
+func (s *progressSpinner) Stop() error {
+	s.end(ProgressSucceeded)
+	return s.Spinner.Stop()
+}
+
+// StopFail implements the Spinner interface.
+func (s *progressSpinner) StopFail() error {
+	s.end(ProgressFailed)
+	return s.Spinner.StopFail()
+}
+
+// end emits the end of the current step (if any).
+func (s *progressSpinner) end(status string) {
+	if s.step == "" {
+		return
+	}
+	s.progress.Emit(ProgressEvent{Phase: s.phase, Step: s.step, Status: status, DurationMS: time.Since(s.start).Milliseconds()})
+	s.step = ""
+}
@@ -0,0 +1,81 @@
+package text_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestProgress(t *testing.T) {
+	var events, out bytes.Buffer
+	p := text.NewProgress(&events)
+
+	done := p.Phase("build")
+	s, err := text.NewSpinner(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = p.Spinner(s, "build")
+
+	_ = s.Start()
+	s.Message("Verifying fastly.toml...")
+	s.StopMessage("Verifying fastly.toml")
+	_ = s.Stop()
+
+	_ = s.Start()
+	s.Message("Running [scripts.build]...")
+	s.Message("Running [scripts.build] (still)...")
+	s.StopFailMessage("Running [scripts.build]")
+	_ = s.StopFail()
+	done(errors.New("exit status 1"))
+
+	var got []text.ProgressEvent
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		var e text.ProgressEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %s", line, err)
+		}
+		if e.Time.IsZero() {
+			t.Fatalf("want event time, got: %s", line)
+		}
+		// The time and duration vary so aren't compared.
+		got = append(got, text.ProgressEvent{Phase: e.Phase, Step: e.Step, Status: e.Status, Error: e.Error})
+	}
+	testutil.AssertEqual(t, []text.ProgressEvent{
+		{Phase: "build", Status: text.ProgressStarted},
+		{Phase: "build", Step: "Verifying fastly.toml", Status: text.ProgressStarted},
+		{Phase: "build", Step: "Verifying fastly.toml", Status: text.ProgressSucceeded},
+		{Phase: "build", Step: "Running [scripts.build]", Status: text.ProgressStarted},
+		{Phase: "build", Step: "Running [scripts.build]", Status: text.ProgressFailed},
+		{Phase: "build", Status: text.ProgressFailed, Error: "exit status 1"},
+	}, got)
+}
+
+func TestProgressNil(t *testing.T) {
+	var p *text.Progress
+	var out bytes.Buffer
+	s, err := text.NewSpinner(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Spinner(s, "build") != s {
+		t.Fatal("want a nil Progress to return the spinner unchanged")
+	}
+	p.Phase("build")(nil)
+	p.Emit(text.ProgressEvent{Phase: "build"})
+}
+
+func TestProgressObserve(t *testing.T) {
+	p := text.NewProgress(nil)
+	var got []string
+	p.Observe(func(e text.ProgressEvent) {
+		got = append(got, e.Phase+" "+e.Status)
+	})
+	p.Phase("deploy")(nil)
+	testutil.AssertEqual(t, []string{"deploy started", "deploy succeeded"}, got)
+}
@@ -2,6 +2,7 @@ package text
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -79,6 +80,9 @@ func Output(w io.Writer, format string, args ...any) {
 //
 // Input is intended to be used to take interactive input from the user.
 func Input(w io.Writer, prefix string, r io.Reader, validators ...func(string) error) (string, error) {
+	if err := promptable(prefix, r); err != nil {
+		return "", err
+	}
 	s := bufio.NewScanner(r)
 
 outer:
@@ -100,8 +104,33 @@ outer:
 	}
 }
 
+// ErrPromptUnavailable indicates user input was required but prompts have
+// been disabled (see NoPromptReader).
+var ErrPromptUnavailable = errors.New("unable to prompt for input")
+
+// NoPromptReader wraps a reader from which data can still be read (e.g. a
+// value piped via stdin) but from which prompts can't be answered, such as
+// when running in a CI environment where nobody can respond.
+type NoPromptReader struct {
+	io.Reader
+
+	// Reason describes why prompts are disabled.
+	Reason string
+}
+
+// promptable returns an error if r doesn't allow prompting for input.
+func promptable(prefix string, r io.Reader) error {
+	if npr, ok := r.(NoPromptReader); ok {
+		return fmt.Errorf("%w (%s): %s", ErrPromptUnavailable, npr.Reason, strings.TrimSpace(prefix))
+	}
+	return nil
+}
+
 // IsStdin returns true if r is standard input.
 func IsStdin(r io.Reader) bool {
+	if npr, ok := r.(NoPromptReader); ok {
+		r = npr.Reader
+	}
 	if f, ok := r.(*os.File); ok {
 		return f.Fd() == uintptr(syscall.Stdin)
 	}
@@ -128,6 +157,9 @@ func IsTTY(fd any) bool {
 // InputSecure is like Input but doesn't echo input back to the terminal,
 // if and only if r is os.Stdin.
 func InputSecure(w io.Writer, prefix string, r io.Reader, validators ...func(string) error) (string, error) {
+	if err := promptable(prefix, r); err != nil {
+		return "", err
+	}
 	if !IsStdin(r) {
 		return Input(w, prefix, r, validators...)
 	}
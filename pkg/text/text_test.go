@@ -152,3 +152,23 @@ func TestPrefixes(t *testing.T) {
 		})
 	}
 }
+
+func TestInputNoPrompt(t *testing.T) {
+	var buf bytes.Buffer
+	in := text.NoPromptReader{Reader: strings.NewReader("a\n"), Reason: "CI environment detected"}
+
+	_, err := text.Input(&buf, "Name: ", in)
+	testutil.AssertErrorContains(t, err, "unable to prompt for input (CI environment detected): Name:")
+	if !errors.Is(err, text.ErrPromptUnavailable) {
+		t.Fatalf("want ErrPromptUnavailable, got: %v", err)
+	}
+	testutil.AssertString(t, "", buf.String())
+
+	_, err = text.AskYesNo(&buf, "Continue? [y/N] ", in)
+	testutil.AssertErrorContains(t, err, "unable to prompt for input")
+
+	// Data can still be read from the underlying reader.
+	data, err := io.ReadAll(in)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "a\n", string(data))
+}